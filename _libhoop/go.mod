@@ -2,4 +2,9 @@ module libhoop
 
 go 1.22.4
 
-require github.com/creack/pty v1.1.21
+replace github.com/hoophq/hoop/common => ../common
+
+require (
+	github.com/creack/pty v1.1.21
+	github.com/hoophq/hoop/common v0.0.0-00010101000000-000000000000
+)
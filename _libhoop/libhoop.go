@@ -4,15 +4,21 @@ import (
 	"context"
 	"fmt"
 	"io"
+
+	"libhoop/proxy/postgres"
 )
 
-type core struct{}
+type core struct {
+	ctx     context.Context
+	clientW io.Writer
+	opts    map[string]string
+}
 type noopProxy struct {
 	connectionType string
 }
 
 func NewDBCore(ctx context.Context, clientW io.Writer, opts map[string]string) *core {
-	return &core{}
+	return &core{ctx: ctx, clientW: clientW, opts: opts}
 }
 
 func (p *noopProxy) Run(onErr func(int, string)) {
@@ -23,10 +29,18 @@ func (p *noopProxy) Write(data []byte) (int, error) { return len(data), nil }
 func (p *noopProxy) Done() <-chan struct{}          { return nil }
 func (p *noopProxy) Close() error                   { return nil }
 
-func (c *core) MySQL() (Proxy, error)    { return &noopProxy{connectionType: "mysql"}, nil }
-func (c *core) MSSQL() (Proxy, error)    { return &noopProxy{connectionType: "mssql"}, nil }
-func (c *core) MongoDB() (Proxy, error)  { return &noopProxy{connectionType: "mongodb"}, nil }
-func (c *core) Postgres() (Proxy, error) { return &noopProxy{connectionType: "postgres"}, nil }
+func (c *core) MySQL() (Proxy, error)   { return &noopProxy{connectionType: "mysql"}, nil }
+func (c *core) MSSQL() (Proxy, error)   { return &noopProxy{connectionType: "mssql"}, nil }
+func (c *core) MongoDB() (Proxy, error) { return &noopProxy{connectionType: "mongodb"}, nil }
+func (c *core) Postgres() (Proxy, error) {
+	return postgres.New(c.ctx, c.clientW, postgres.Options{
+		Hostname: c.opts["hostname"],
+		Port:     c.opts["port"],
+		Username: c.opts["username"],
+		Password: c.opts["password"],
+		SSLMode:  c.opts["sslmode"],
+	}), nil
+}
 
 func NewAdHocExec(rawEnvVarList map[string]any, args []string, payload []byte, stdout, stderr io.WriteCloser, opts map[string]string) (Proxy, error) {
 	return &noopProxy{connectionType: "terminal-exec"}, nil
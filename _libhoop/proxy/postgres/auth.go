@@ -0,0 +1,208 @@
+package postgres
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const scramSHA256Mechanism = "SCRAM-SHA-256"
+
+// authenticate performs the authentication with the server using the credentials
+// of the agent. It returns when the server sends the authentication ok message.
+func authenticate(rw io.ReadWriter, user, password string) error {
+	var scram *scramClient
+	for {
+		msg, err := readMessage(rw, maxBackendMessageSize)
+		if err != nil {
+			return fmt.Errorf("failed reading authentication message, reason=%v", err)
+		}
+		switch msg.typ {
+		case serverErrorMessage:
+			return &serverError{msg: msg}
+		case serverNegotiate:
+			// the server doesn't support a minor version or protocol options, it's safe to ignore
+			continue
+		case serverAuth:
+		default:
+			return fmt.Errorf("unexpected message %q during authentication", msg.typ)
+		}
+		if len(msg.body) < 4 {
+			return fmt.Errorf("invalid authentication message length (%v)", len(msg.body))
+		}
+		authType, data := binary.BigEndian.Uint32(msg.body[0:4]), msg.body[4:]
+		switch authType {
+		case uint32(authOK):
+			return nil
+		case uint32(authCleartextPassword):
+			err = writeMessage(rw, newPasswordMessage(append([]byte(password), 0)))
+		case uint32(authMD5Password):
+			if len(data) != 4 {
+				return fmt.Errorf("invalid md5 salt length (%v)", len(data))
+			}
+			err = writeMessage(rw, newPasswordMessage(append([]byte(md5Password(user, password, data)), 0)))
+		case uint32(authSASL):
+			mechanisms := strings.Split(strings.TrimRight(string(data), "\x00"), "\x00")
+			if !slices.Contains(mechanisms, scramSHA256Mechanism) {
+				return fmt.Errorf("unsupported sasl authentication mechanisms %v", mechanisms)
+			}
+			if scram, err = newScramClient(password); err != nil {
+				return err
+			}
+			err = writeMessage(rw, newSASLInitialResponseMessage(scramSHA256Mechanism, scram.clientFirstMessage()))
+		case uint32(authSASLContinue):
+			if scram == nil {
+				return fmt.Errorf("received sasl continue message before initial response")
+			}
+			var clientFinal []byte
+			if clientFinal, err = scram.clientFinalMessage(data); err != nil {
+				return err
+			}
+			err = writeMessage(rw, newPasswordMessage(clientFinal))
+		case uint32(authSASLFinal):
+			if scram == nil {
+				return fmt.Errorf("received sasl final message before initial response")
+			}
+			err = scram.verifyServerFinal(data)
+		default:
+			return fmt.Errorf("authentication method (%v) requested by the server is not supported", authType)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func writeMessage(w io.Writer, msg *message) error {
+	_, err := w.Write(msg.encode())
+	return err
+}
+
+// serverError is an error response sent by the server
+type serverError struct {
+	msg *message
+}
+
+func (e *serverError) Error() string { return parseErrorMessage(e.msg.body) }
+
+// md5Password returns the md5 hashed password: md5(md5(password + user) + salt)
+func md5Password(user, password string, salt []byte) string {
+	h := md5.Sum([]byte(password + user))
+	h = md5.Sum(append([]byte(hex.EncodeToString(h[:])), salt...))
+	return "md5" + hex.EncodeToString(h[:])
+}
+
+func newSASLInitialResponseMessage(mechanism string, data []byte) *message {
+	body := append([]byte(mechanism), 0)
+	size := make([]byte, 4)
+	binary.BigEndian.PutUint32(size, uint32(len(data)))
+	body = append(body, size...)
+	return newPasswordMessage(append(body, data...))
+}
+
+// scramClient implements the client side of SCRAM-SHA-256 without channel binding.
+// The username is empty, the server uses the user of the startup message.
+//
+// https://datatracker.ietf.org/doc/html/rfc5802
+type scramClient struct {
+	password        string
+	clientNonce     string
+	clientFirstBare string
+	authMessage     string
+	saltedPassword  []byte
+}
+
+func newScramClient(password string) (*scramClient, error) {
+	nonce := make([]byte, 18)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed generating scram nonce, reason=%v", err)
+	}
+	c := &scramClient{password: password, clientNonce: base64.RawStdEncoding.EncodeToString(nonce)}
+	c.clientFirstBare = "n=,r=" + c.clientNonce
+	return c, nil
+}
+
+func (c *scramClient) clientFirstMessage() []byte { return []byte("n,," + c.clientFirstBare) }
+
+func (c *scramClient) clientFinalMessage(serverFirst []byte) ([]byte, error) {
+	attrs := parseScramAttributes(string(serverFirst))
+	nonce, encSalt, iterations := attrs["r"], attrs["s"], attrs["i"]
+	if !strings.HasPrefix(nonce, c.clientNonce) || len(nonce) == len(c.clientNonce) {
+		return nil, fmt.Errorf("invalid scram nonce sent by the server")
+	}
+	salt, err := base64.StdEncoding.DecodeString(encSalt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scram salt sent by the server, reason=%v", err)
+	}
+	iter, err := strconv.Atoi(iterations)
+	if err != nil || iter < 1 {
+		return nil, fmt.Errorf("invalid scram iteration count (%q) sent by the server", iterations)
+	}
+	// biws is the base64 of the gs2 header: n,,
+	clientFinalWithoutProof := "c=biws,r=" + nonce
+	c.authMessage = c.clientFirstBare + "," + string(serverFirst) + "," + clientFinalWithoutProof
+	c.saltedPassword = pbkdf2SHA256([]byte(c.password), salt, iter)
+	clientKey := hmacSHA256(c.saltedPassword, []byte("Client Key"))
+	storedKey := sha256.Sum256(clientKey)
+	clientSignature := hmacSHA256(storedKey[:], []byte(c.authMessage))
+	proof := make([]byte, len(clientKey))
+	for i := range clientKey {
+		proof[i] = clientKey[i] ^ clientSignature[i]
+	}
+	return []byte(clientFinalWithoutProof + ",p=" + base64.StdEncoding.EncodeToString(proof)), nil
+}
+
+func (c *scramClient) verifyServerFinal(serverFinal []byte) error {
+	attrs := parseScramAttributes(string(serverFinal))
+	if errMsg, ok := attrs["e"]; ok {
+		return fmt.Errorf("scram authentication failed, reason=%v", errMsg)
+	}
+	serverSignature, err := base64.StdEncoding.DecodeString(attrs["v"])
+	if err != nil {
+		return fmt.Errorf("invalid scram server signature, reason=%v", err)
+	}
+	serverKey := hmacSHA256(c.saltedPassword, []byte("Server Key"))
+	if !hmac.Equal(serverSignature, hmacSHA256(serverKey, []byte(c.authMessage))) {
+		return fmt.Errorf("scram server signature mismatch")
+	}
+	return nil
+}
+
+func parseScramAttributes(data string) map[string]string {
+	attrs := map[string]string{}
+	for _, attr := range strings.Split(data, ",") {
+		if key, val, found := strings.Cut(attr, "="); found {
+			attrs[key] = val
+		}
+	}
+	return attrs
+}
+
+func hmacSHA256(key, data []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// pbkdf2SHA256 is the Hi function of SCRAM, the derived key has the size of the hash
+func pbkdf2SHA256(password, salt []byte, iterations int) []byte {
+	u := hmacSHA256(password, append(append([]byte{}, salt...), 0, 0, 0, 1))
+	result := bytes.Clone(u)
+	for i := 1; i < iterations; i++ {
+		u = hmacSHA256(password, u)
+		for j := range result {
+			result[j] ^= u[j]
+		}
+	}
+	return result
+}
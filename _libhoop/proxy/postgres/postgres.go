@@ -0,0 +1,366 @@
+// Package postgres implements a postgres wire protocol proxy. The client connects
+// without credentials, the proxy authenticates with the server using the
+// credentials of the agent and forwards the messages of both ends afterwards.
+package postgres
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pgtypes "github.com/hoophq/hoop/common/pgtypes"
+)
+
+const (
+	dialTimeout   = time.Second * 15
+	maxWriteBatch = 64 * 1024
+)
+
+// Options are the attributes to connect in the postgres server
+type Options struct {
+	Hostname string
+	Port     string
+	Username string
+	Password string
+	// disable, prefer, require or verify-full. Defaults to prefer
+	SSLMode string
+}
+
+type proxy struct {
+	ctx     context.Context
+	opts    Options
+	clientW io.Writer
+	clientR *chanReader
+
+	mu         sync.Mutex
+	serverConn net.Conn
+	terminated atomic.Bool
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+// New returns a proxy that writes the messages of the server to clientW.
+// The messages of the client are written to the proxy with Write.
+func New(ctx context.Context, clientW io.Writer, opts Options) *proxy {
+	if opts.SSLMode == "" {
+		opts.SSLMode = "prefer"
+	}
+	return &proxy{
+		ctx:     ctx,
+		opts:    opts,
+		clientW: clientW,
+		clientR: newChanReader(),
+		done:    make(chan struct{}),
+	}
+}
+
+// Run starts proxying the connection in background. The onErr callback is
+// called when the connection with the server fails or it's unexpectedly closed.
+func (p *proxy) Run(onErr func(exitCode int, errMsg string)) {
+	go func() {
+		defer p.Close()
+		if err := p.serve(); err != nil && !p.isClosed() {
+			onErr(1, err.Error())
+		}
+	}()
+	go func() {
+		select {
+		case <-p.ctx.Done():
+			_ = p.Close()
+		case <-p.done:
+		}
+	}()
+}
+
+// Write forwards the data sent by the client
+func (p *proxy) Write(data []byte) (int, error) { return p.clientR.write(data) }
+func (p *proxy) Done() <-chan struct{}          { return p.done }
+
+func (p *proxy) Close() error {
+	p.closeOnce.Do(func() {
+		close(p.done)
+		p.clientR.close()
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.serverConn != nil {
+			_ = p.serverConn.Close()
+		}
+	})
+	return nil
+}
+
+func (p *proxy) isClosed() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *proxy) serve() error {
+	startup, err := p.readStartup()
+	if err != nil {
+		return err
+	}
+	if startup.isCancelRequest() {
+		return p.cancelRequest(startup)
+	}
+	if startup.code != protocolVersion3 {
+		_ = p.writeClient(newErrorMessage("0A000", "unsupported frontend protocol").encode())
+		return fmt.Errorf("unsupported postgres protocol version %v", startup.code)
+	}
+	serverConn, err := p.connect()
+	if err != nil {
+		_ = p.writeClient(newErrorMessage("08006", err.Error()).encode())
+		return err
+	}
+	if err := p.setServerConn(serverConn); err != nil {
+		return err
+	}
+	serverR := bufio.NewReaderSize(serverConn, 32*1024)
+	serverRW := struct {
+		io.Reader
+		io.Writer
+	}{serverR, serverConn}
+
+	// the client credentials are replaced by the ones of the agent
+	params := allowedStartupParams(startup.params)
+	params["user"] = p.opts.Username
+	if params["database"] == "" {
+		params["database"] = p.opts.Username
+	}
+	if _, err := serverConn.Write(encodeStartupMessage(protocolVersion3, encodeStartupParams(params))); err != nil {
+		return fmt.Errorf("failed sending startup message to server, reason=%v", err)
+	}
+	if err := authenticate(serverRW, p.opts.Username, p.opts.Password); err != nil {
+		if srvErr, ok := err.(*serverError); ok {
+			_ = p.writeClient(srvErr.msg.encode())
+		} else {
+			_ = p.writeClient(newErrorMessage("28000", err.Error()).encode())
+		}
+		return fmt.Errorf("failed authenticating with postgres server, reason=%v", err)
+	}
+	if err := p.writeClient(newAuthOKMessage().encode()); err != nil {
+		return err
+	}
+
+	go func() {
+		if err := p.copyClientToServer(serverConn); err != nil {
+			_ = p.Close()
+		}
+	}()
+	err = p.copyServerToClient(serverR)
+	// the server closes the connection when the client terminates it
+	if p.terminated.Load() {
+		return nil
+	}
+	return err
+}
+
+// allowedStartupParams returns the startup parameters of the client that are forwarded to the server.
+// Parameters that change how the server handles the connection, like replication and options
+// (command-line arguments of the backend process), are dropped.
+func allowedStartupParams(clientParams map[string]string) map[string]string {
+	params := map[string]string{}
+	for key, val := range clientParams {
+		switch strings.ToLower(key) {
+		case "database", "application_name", "client_encoding", "datestyle", "timezone",
+			"intervalstyle", "extra_float_digits", "search_path":
+			params[key] = val
+		}
+	}
+	return params
+}
+
+// readStartup reads the startup message of the client, it denies ssl and
+// gss encryption requests because the client connection is already secured by hoop
+func (p *proxy) readStartup() (*startupMessage, error) {
+	for {
+		startup, err := readStartupMessage(p.clientR)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading startup message, reason=%v", err)
+		}
+		switch startup.code {
+		case sslRequestCode, gssEncRequestCode:
+			if err := p.writeClient([]byte{'N'}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		return startup, nil
+	}
+}
+
+// cancelRequest forwards the cancel request to the server using a new connection.
+// The backend key data is not rewritten, the client has the pid and secret of the server.
+//
+// https://www.postgresql.org/docs/current/protocol-flow.html#PROTOCOL-FLOW-CANCELING-REQUESTS
+func (p *proxy) cancelRequest(startup *startupMessage) error {
+	serverConn, err := p.connect()
+	if err != nil {
+		return err
+	}
+	defer serverConn.Close()
+	_, err = serverConn.Write(encodeStartupMessage(pgtypes.ClientCancelRequestMessage, startup.frame))
+	return err
+}
+
+// connect dials the server negotiating ssl based on the ssl mode
+func (p *proxy) connect() (net.Conn, error) {
+	addr := net.JoinHostPort(p.opts.Hostname, p.opts.Port)
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(p.ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed connecting to postgres server %v, reason=%v", addr, err)
+	}
+	if p.opts.SSLMode != "disable" {
+		if conn, err = p.negotiateSSL(conn); err != nil {
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// setServerConn keeps the connection to be closed when the proxy is closed
+func (p *proxy) setServerConn(conn net.Conn) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.isClosed() {
+		_ = conn.Close()
+		return fmt.Errorf("proxy closed before connecting to the postgres server")
+	}
+	p.serverConn = conn
+	return nil
+}
+
+func (p *proxy) negotiateSSL(conn net.Conn) (net.Conn, error) {
+	if _, err := conn.Write(encodeStartupMessage(sslRequestCode, nil)); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed sending ssl request, reason=%v", err)
+	}
+	resp := make([]byte, 1)
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed reading ssl response, reason=%v", err)
+	}
+	switch {
+	case resp[0] == 'N' && p.opts.SSLMode == "prefer":
+		return conn, nil
+	case resp[0] == 'N':
+		_ = conn.Close()
+		return nil, fmt.Errorf("postgres server does not support ssl, sslmode=%v", p.opts.SSLMode)
+	case resp[0] != 'S':
+		_ = conn.Close()
+		return nil, fmt.Errorf("unexpected ssl response %q from postgres server", resp[0])
+	}
+	tlsConfig := &tls.Config{ServerName: p.opts.Hostname}
+	if p.opts.SSLMode != "verify-full" {
+		// prefer and require modes don't verify the certificate of the server
+		tlsConfig.InsecureSkipVerify = true
+	}
+	tlsConn := tls.Client(conn, tlsConfig)
+	ctx, cancelFn := context.WithTimeout(p.ctx, dialTimeout)
+	defer cancelFn()
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed performing tls handshake with postgres server, reason=%v", err)
+	}
+	return tlsConn, nil
+}
+
+func (p *proxy) copyClientToServer(serverConn net.Conn) error {
+	for {
+		msg, err := readMessage(p.clientR, maxFrontendMessageSize)
+		if err != nil {
+			return err
+		}
+		if msg.typ == pgtypes.ClientTerminate.Byte() {
+			p.terminated.Store(true)
+		}
+		if _, err := serverConn.Write(msg.encode()); err != nil {
+			return err
+		}
+	}
+}
+
+// copyServerToClient writes the messages of the server in batches, the backend key data
+// message is always the first message of a batch to be recognized by the client.
+func (p *proxy) copyServerToClient(serverR *bufio.Reader) error {
+	var batch []byte
+	for {
+		msg, err := readMessage(serverR, maxBackendMessageSize)
+		if err != nil {
+			if err == io.EOF {
+				err = fmt.Errorf("postgres server closed the connection")
+			}
+			return err
+		}
+		if msg.typ == pgtypes.ServerBackendKeyData.Byte() && len(batch) > 0 {
+			if err := p.writeClient(batch); err != nil {
+				return err
+			}
+			batch = nil
+		}
+		batch = append(batch, msg.encode()...)
+		if serverR.Buffered() == 0 || len(batch) >= maxWriteBatch {
+			if err := p.writeClient(batch); err != nil {
+				return err
+			}
+			batch = nil
+		}
+	}
+}
+
+func (p *proxy) writeClient(data []byte) error {
+	if _, err := p.clientW.Write(data); err != nil {
+		return fmt.Errorf("failed writing to client, reason=%v", err)
+	}
+	return nil
+}
+
+// chanReader is a reader fed by the writes of the client,
+// it doesn't block the writer while the proxy connects to the server
+type chanReader struct {
+	ch     chan []byte
+	buf    []byte
+	done   chan struct{}
+	closed sync.Once
+}
+
+func newChanReader() *chanReader {
+	return &chanReader{ch: make(chan []byte, 1024), done: make(chan struct{})}
+}
+
+func (r *chanReader) write(data []byte) (int, error) {
+	buf := make([]byte, len(data))
+	copy(buf, data)
+	select {
+	case <-r.done:
+		return 0, io.ErrClosedPipe
+	case r.ch <- buf:
+		return len(data), nil
+	}
+}
+
+func (r *chanReader) Read(p []byte) (int, error) {
+	if len(r.buf) == 0 {
+		select {
+		case <-r.done:
+			return 0, io.EOF
+		case r.buf = <-r.ch:
+		}
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (r *chanReader) close() { r.closed.Do(func() { close(r.done) }) }
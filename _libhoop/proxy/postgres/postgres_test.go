@@ -0,0 +1,475 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	pgtypes "github.com/hoophq/hoop/common/pgtypes"
+)
+
+const (
+	fakeUser     = "hoop"
+	fakePassword = "secret"
+	fakePid      = 42
+	fakeKey      = 1234
+
+	largeRowQuery = "SELECT large_row"
+)
+
+// fakeServer is an in-process postgres server implementing
+// the authentication methods and a minimal query flow
+type fakeServer struct {
+	t         *testing.T
+	lis       net.Listener
+	auth      string
+	tlsConfig *tls.Config
+
+	mu             sync.Mutex
+	startupParams  map[string]string
+	cancelRequests [][]byte
+}
+
+func newFakeServer(t *testing.T, auth string, withTLS bool) *fakeServer {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed listening: %v", err)
+	}
+	srv := &fakeServer{t: t, lis: lis, auth: auth}
+	if withTLS {
+		srv.tlsConfig = &tls.Config{Certificates: []tls.Certificate{newSelfSignedCert(t)}}
+	}
+	t.Cleanup(func() { _ = lis.Close() })
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go srv.handle(conn)
+		}
+	}()
+	return srv
+}
+
+func (s *fakeServer) options(sslMode string) Options {
+	host, port, _ := net.SplitHostPort(s.lis.Addr().String())
+	return Options{Hostname: host, Port: port, Username: fakeUser, Password: fakePassword, SSLMode: sslMode}
+}
+
+func (s *fakeServer) handle(conn net.Conn) {
+	defer conn.Close()
+	var startup *startupMessage
+	for {
+		var err error
+		if startup, err = readStartupMessage(conn); err != nil {
+			return
+		}
+		if startup.code != sslRequestCode {
+			break
+		}
+		if s.tlsConfig == nil {
+			_, _ = conn.Write([]byte{'N'})
+			continue
+		}
+		_, _ = conn.Write([]byte{'S'})
+		tlsConn := tls.Server(conn, s.tlsConfig)
+		if err := tlsConn.Handshake(); err != nil {
+			return
+		}
+		conn = tlsConn
+	}
+	if startup.isCancelRequest() {
+		s.mu.Lock()
+		s.cancelRequests = append(s.cancelRequests, startup.frame)
+		s.mu.Unlock()
+		return
+	}
+	s.mu.Lock()
+	s.startupParams = startup.params
+	s.mu.Unlock()
+	if err := s.authenticate(conn, startup.params["user"]); err != nil {
+		_, _ = conn.Write(newErrorMessage("28P01", err.Error()).encode())
+		return
+	}
+	keyData := make([]byte, 8)
+	binary.BigEndian.PutUint32(keyData[0:4], fakePid)
+	binary.BigEndian.PutUint32(keyData[4:8], fakeKey)
+	for _, msg := range []*message{
+		newAuthOKMessage(),
+		{typ: 'S', body: []byte("server_version\x0016.0\x00")},
+		{typ: 'K', body: keyData},
+		{typ: 'Z', body: []byte{'I'}},
+	} {
+		_, _ = conn.Write(msg.encode())
+	}
+	for {
+		msg, err := readMessage(conn, maxFrontendMessageSize)
+		if err != nil {
+			return
+		}
+		var resp []*message
+		switch msg.typ {
+		case 'Q':
+			query := strings.TrimRight(string(msg.body), "\x00")
+			resp = []*message{{typ: 'C', body: []byte(query + "\x00")}, {typ: 'Z', body: []byte{'I'}}}
+			if query == largeRowQuery {
+				// a data row with a single column larger than the buffer of client messages
+				value := bytes.Repeat([]byte("a"), maxFrontendMessageSize+1)
+				row := binary.BigEndian.AppendUint16(nil, 1)
+				row = binary.BigEndian.AppendUint32(row, uint32(len(value)))
+				resp = append([]*message{{typ: 'D', body: append(row, value...)}}, resp...)
+			}
+		case 'P':
+			resp = []*message{{typ: '1'}}
+		case 'B':
+			resp = []*message{{typ: '2'}}
+		case 'E':
+			resp = []*message{{typ: 'C', body: []byte("SELECT 1\x00")}}
+		case 'S':
+			resp = []*message{{typ: 'Z', body: []byte{'I'}}}
+		case 'X':
+			return
+		}
+		for _, m := range resp {
+			_, _ = conn.Write(m.encode())
+		}
+	}
+}
+
+func (s *fakeServer) authenticate(conn net.Conn, user string) error {
+	authFailed := fmt.Errorf("password authentication failed for user %q", user)
+	switch s.auth {
+	case "trust":
+		return nil
+	case "cleartext":
+		_, _ = conn.Write((&message{typ: 'R', body: []byte{0, 0, 0, authCleartextPassword}}).encode())
+		msg, err := readMessage(conn, maxFrontendMessageSize)
+		if err != nil || string(msg.body) != fakePassword+"\x00" {
+			return authFailed
+		}
+		return nil
+	case "md5":
+		salt := []byte{1, 2, 3, 4}
+		_, _ = conn.Write((&message{typ: 'R', body: append([]byte{0, 0, 0, authMD5Password}, salt...)}).encode())
+		msg, err := readMessage(conn, maxFrontendMessageSize)
+		if err != nil || string(msg.body) != md5Password(fakeUser, fakePassword, salt)+"\x00" {
+			return authFailed
+		}
+		return nil
+	case "scram":
+		return s.scramAuth(conn, authFailed)
+	}
+	return fmt.Errorf("unknown auth %v", s.auth)
+}
+
+func (s *fakeServer) scramAuth(conn net.Conn, authFailed error) error {
+	_, _ = conn.Write((&message{typ: 'R', body: []byte("\x00\x00\x00\x0aSCRAM-SHA-256\x00\x00")}).encode())
+	msg, err := readMessage(conn, maxFrontendMessageSize)
+	if err != nil {
+		return err
+	}
+	mechanism, data, _ := bytes.Cut(msg.body, []byte{0})
+	if string(mechanism) != scramSHA256Mechanism || len(data) < 4 {
+		return fmt.Errorf("invalid sasl initial response")
+	}
+	clientFirstBare := strings.TrimPrefix(string(data[4:]), "n,,")
+	salt, iterations := []byte("fake-salt"), 4096
+	serverFirst := fmt.Sprintf("r=%sserver-nonce,s=%s,i=%v", parseScramAttributes(clientFirstBare)["r"],
+		base64.StdEncoding.EncodeToString(salt), iterations)
+	_, _ = conn.Write((&message{typ: 'R', body: append([]byte{0, 0, 0, authSASLContinue}, serverFirst...)}).encode())
+	if msg, err = readMessage(conn, maxFrontendMessageSize); err != nil {
+		return err
+	}
+	clientFinalWithoutProof, encProof, _ := strings.Cut(string(msg.body), ",p=")
+	authMessage := clientFirstBare + "," + serverFirst + "," + clientFinalWithoutProof
+	saltedPassword := pbkdf2SHA256([]byte(fakePassword), salt, iterations)
+	storedKey := sha256.Sum256(hmacSHA256(saltedPassword, []byte("Client Key")))
+	clientSignature := hmacSHA256(storedKey[:], []byte(authMessage))
+	proof, _ := base64.StdEncoding.DecodeString(encProof)
+	if len(proof) != len(clientSignature) {
+		return authFailed
+	}
+	for i := range proof {
+		proof[i] ^= clientSignature[i]
+	}
+	if sha256.Sum256(proof) != storedKey {
+		return authFailed
+	}
+	serverSignature := hmacSHA256(hmacSHA256(saltedPassword, []byte("Server Key")), []byte(authMessage))
+	serverFinal := "v=" + base64.StdEncoding.EncodeToString(serverSignature)
+	_, _ = conn.Write((&message{typ: 'R', body: append([]byte{0, 0, 0, authSASLFinal}, serverFinal...)}).encode())
+	return nil
+}
+
+func newSelfSignedCert(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// fakeClient reads what the proxy writes to the client
+type fakeClient struct {
+	r *io.PipeReader
+	w *io.PipeWriter
+
+	mu     sync.Mutex
+	writes [][]byte
+}
+
+func newFakeClient() *fakeClient {
+	r, w := io.Pipe()
+	return &fakeClient{r: r, w: w}
+}
+
+func (c *fakeClient) Write(data []byte) (int, error) {
+	c.mu.Lock()
+	c.writes = append(c.writes, bytes.Clone(data))
+	c.mu.Unlock()
+	return c.w.Write(data)
+}
+
+// readUntil reads messages until it finds the message type
+func (c *fakeClient) readUntil(t *testing.T, typ byte) []*message {
+	var messages []*message
+	for {
+		msg, err := readMessage(c.r, maxBackendMessageSize)
+		if err != nil {
+			t.Fatalf("failed reading message from proxy: %v", err)
+		}
+		messages = append(messages, msg)
+		if msg.typ == typ || msg.typ == serverErrorMessage {
+			return messages
+		}
+	}
+}
+
+func runProxy(t *testing.T, opts Options) (*proxy, *fakeClient, chan string) {
+	client := newFakeClient()
+	p := New(context.Background(), client, opts)
+	errCh := make(chan string, 1)
+	p.Run(func(_ int, errMsg string) { errCh <- errMsg })
+	t.Cleanup(func() { _ = p.Close(); _ = client.r.Close() })
+	return p, client, errCh
+}
+
+func newStartup(params map[string]string) []byte {
+	return encodeStartupMessage(protocolVersion3, encodeStartupParams(params))
+}
+
+func messageTypes(messages []*message) string {
+	var types []byte
+	for _, m := range messages {
+		types = append(types, m.typ)
+	}
+	return string(types)
+}
+
+func TestProxyAuthentication(t *testing.T) {
+	for _, tt := range []struct {
+		msg     string
+		auth    string
+		withTLS bool
+		sslMode string
+		pass    string
+		wantErr string
+	}{
+		{msg: "it should connect with trust authentication", auth: "trust", sslMode: "disable"},
+		{msg: "it should authenticate with cleartext password", auth: "cleartext", sslMode: "disable"},
+		{msg: "it should authenticate with md5 password and fallback to plain text", auth: "md5", sslMode: "prefer"},
+		{msg: "it should authenticate with scram-sha-256 over tls", auth: "scram", withTLS: true, sslMode: "require"},
+		{msg: "it should authenticate with scram-sha-256 over tls when preferred", auth: "scram", withTLS: true},
+		{msg: "it should return the error of the server with a wrong password", auth: "scram", sslMode: "disable",
+			pass: "wrong-pass", wantErr: `FATAL: password authentication failed for user "hoop" (SQLSTATE 28P01)`},
+		{msg: "it should return error when the server does not support tls", auth: "trust", sslMode: "require",
+			wantErr: "postgres server does not support ssl, sslmode=require"},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			srv := newFakeServer(t, tt.auth, tt.withTLS)
+			opts := srv.options(tt.sslMode)
+			if tt.pass != "" {
+				opts.Password = tt.pass
+			}
+			p, client, errCh := runProxy(t, opts)
+			_, _ = p.Write(newStartup(map[string]string{"user": "noop", "database": "mydb", "application_name": "psql",
+				"DateStyle": "ISO", "replication": "database", "options": "-c session_replication_role=replica"}))
+
+			messages := client.readUntil(t, 'Z')
+			if tt.wantErr != "" {
+				if got := messages[len(messages)-1]; got.typ != serverErrorMessage || !strings.Contains(parseErrorMessage(got.body), tt.wantErr) {
+					t.Fatalf("expected error response %q, got=%q", tt.wantErr, parseErrorMessage(got.body))
+				}
+				select {
+				case errMsg := <-errCh:
+					if !strings.Contains(errMsg, tt.wantErr) {
+						t.Errorf("expected error callback containing %q, got=%q", tt.wantErr, errMsg)
+					}
+				case <-time.After(time.Second * 5):
+					t.Fatal("timeout waiting for the error callback")
+				}
+				return
+			}
+			if got := messageTypes(messages); got != "RSKZ" {
+				t.Fatalf("expected authentication ok, parameter status, backend key data and ready for query, got=%q", got)
+			}
+			srv.mu.Lock()
+			params := srv.startupParams
+			srv.mu.Unlock()
+			// the parameters out of the allow list are not forwarded
+			want := map[string]string{"user": fakeUser, "database": "mydb", "application_name": "psql", "DateStyle": "ISO"}
+			if fmt.Sprint(params) != fmt.Sprint(want) {
+				t.Errorf("expected startup params %v, got=%v", want, params)
+			}
+		})
+	}
+}
+
+func TestProxyQueryFlow(t *testing.T) {
+	srv := newFakeServer(t, "md5", false)
+	p, client, errCh := runProxy(t, srv.options("disable"))
+
+	// the client connection is secured by hoop, ssl requests must be denied
+	_, _ = p.Write(encodeStartupMessage(sslRequestCode, nil))
+	sslResp := make([]byte, 1)
+	if _, err := io.ReadFull(client.r, sslResp); err != nil || sslResp[0] != 'N' {
+		t.Fatalf("expected ssl request to be denied, got=%q, err=%v", sslResp, err)
+	}
+	_, _ = p.Write(newStartup(map[string]string{"user": "noop"}))
+	client.readUntil(t, 'Z')
+
+	client.mu.Lock()
+	var keyDataFirst bool
+	for _, data := range client.writes {
+		keyDataFirst = keyDataFirst || data[0] == 'K'
+	}
+	client.mu.Unlock()
+	if !keyDataFirst {
+		t.Errorf("expected backend key data to be the first message of a write")
+	}
+
+	// simple query, the frames could be split by the transport
+	query := (&message{typ: 'Q', body: []byte("SELECT 1\x00")}).encode()
+	_, _ = p.Write(query[:3])
+	_, _ = p.Write(query[3:])
+	if got := messageTypes(client.readUntil(t, 'Z')); got != "CZ" {
+		t.Errorf("expected command complete and ready for query, got=%q", got)
+	}
+
+	// the messages of the server are not limited by the buffer of client messages
+	_, _ = p.Write((&message{typ: 'Q', body: []byte(largeRowQuery + "\x00")}).encode())
+	messages := client.readUntil(t, 'Z')
+	if got := messageTypes(messages); got != "DCZ" || len(messages[0].body) != maxFrontendMessageSize+7 {
+		t.Errorf("expected data row, command complete and ready for query, got=%q", got)
+	}
+
+	// extended query
+	var extended []byte
+	for _, msg := range []*message{
+		{typ: 'P', body: []byte("\x00SELECT $1\x00\x00\x00")},
+		{typ: 'B', body: []byte("\x00\x00\x00\x00\x00\x01\x00\x00\x00\x011\x00\x00")},
+		{typ: 'E', body: []byte("\x00\x00\x00\x00\x00")},
+		{typ: 'S'},
+	} {
+		extended = append(extended, msg.encode()...)
+	}
+	_, _ = p.Write(extended)
+	if got := messageTypes(client.readUntil(t, 'Z')); got != "12CZ" {
+		t.Errorf("expected parse, bind, command complete and ready for query, got=%q", got)
+	}
+
+	// terminate
+	_, _ = p.Write((&message{typ: 'X'}).encode())
+	select {
+	case <-p.Done():
+	case <-time.After(time.Second * 5):
+		t.Fatal("timeout waiting the proxy to finish")
+	}
+	select {
+	case errMsg := <-errCh:
+		t.Errorf("expected no error when the client terminates the connection, got=%v", errMsg)
+	default:
+	}
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if srv.startupParams["database"] != fakeUser {
+		t.Errorf("expected database to default to the user, got=%v", srv.startupParams["database"])
+	}
+}
+
+func TestProxyCancelRequest(t *testing.T) {
+	srv := newFakeServer(t, "trust", false)
+	p, _, errCh := runProxy(t, srv.options("disable"))
+
+	frame := make([]byte, 8)
+	binary.BigEndian.PutUint32(frame[0:4], fakePid)
+	binary.BigEndian.PutUint32(frame[4:8], fakeKey)
+	_, _ = p.Write(encodeStartupMessage(pgtypes.ClientCancelRequestMessage, frame))
+	select {
+	case <-p.Done():
+	case errMsg := <-errCh:
+		t.Fatalf("unexpected error: %v", errMsg)
+	case <-time.After(time.Second * 5):
+		t.Fatal("timeout waiting the cancel request")
+	}
+	// the server reads the cancel request asynchronously
+	for i := 0; i < 50; i++ {
+		srv.mu.Lock()
+		got := srv.cancelRequests
+		srv.mu.Unlock()
+		if len(got) > 0 {
+			if !bytes.Equal(got[0], frame) {
+				t.Errorf("expected cancel request with pid and key %v, got=%v", frame, got[0])
+			}
+			return
+		}
+		time.Sleep(time.Millisecond * 100)
+	}
+	t.Fatal("cancel request not received by the server")
+}
+
+func TestScramClient(t *testing.T) {
+	// https://datatracker.ietf.org/doc/html/rfc7677#section-3
+	c := &scramClient{password: "pencil", clientNonce: "rOprNGfwEbeRWgbNEkqO", clientFirstBare: "n=user,r=rOprNGfwEbeRWgbNEkqO"}
+	clientFinal, err := c.clientFinalMessage([]byte("r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "c=biws,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,p=dHzbZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ="
+	if string(clientFinal) != want {
+		t.Errorf("expected client final message %q, got=%q", want, clientFinal)
+	}
+	if err := c.verifyServerFinal([]byte("v=6rriTRBi23WpRR/wtup+mMhUZUn/dB5nLTJRsjl95G4=")); err != nil {
+		t.Errorf("expected valid server signature, got=%v", err)
+	}
+	if err := c.verifyServerFinal([]byte("v=AAAA")); err == nil {
+		t.Errorf("expected error with an invalid server signature")
+	}
+}
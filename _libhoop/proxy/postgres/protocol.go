@@ -0,0 +1,165 @@
+package postgres
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	pgtypes "github.com/hoophq/hoop/common/pgtypes"
+)
+
+// https://www.postgresql.org/docs/current/protocol-message-formats.html
+const (
+	protocolVersion3   uint32 = 196608
+	sslRequestCode     uint32 = 80877103
+	gssEncRequestCode  uint32 = 80877104
+	maxStartupSize     int    = 10000
+	serverAuth         byte   = 'R'
+	serverErrorMessage byte   = 'E'
+	serverNegotiate    byte   = 'v'
+
+	authOK                byte = 0
+	authCleartextPassword byte = 3
+	authMD5Password       byte = 5
+	authSASL              byte = 10
+	authSASLContinue      byte = 11
+	authSASLFinal         byte = 12
+)
+
+const (
+	// maxFrontendMessageSize limits the messages of clients buffered by the proxy
+	maxFrontendMessageSize int = pgtypes.DefaultBufferSize
+	// maxBackendMessageSize is the limit of the protocol, the messages of the
+	// server could carry large values (bytea, jsonb, text) in data rows
+	maxBackendMessageSize int = math.MaxInt32 - 4
+)
+
+type message struct {
+	typ  byte
+	body []byte
+}
+
+func (m *message) encode() []byte {
+	dst := make([]byte, 5, len(m.body)+5)
+	dst[0] = m.typ
+	binary.BigEndian.PutUint32(dst[1:5], uint32(len(m.body)+4))
+	return append(dst, m.body...)
+}
+
+// readMessage reads a typed message of the protocol (type + length + body)
+// with a body of at most maxSize bytes
+func readMessage(r io.Reader, maxSize int) (*message, error) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	size := int(binary.BigEndian.Uint32(header[1:5])) - 4
+	if size < 0 || size > maxSize {
+		return nil, fmt.Errorf("invalid message length (%v) for message type %q", size, header[0])
+	}
+	msg := &message{typ: header[0], body: make([]byte, size)}
+	if _, err := io.ReadFull(r, msg.body); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// startupMessage is the first message sent by the client, it's not typed.
+// It could be a startup, ssl, gss encryption or cancel request.
+type startupMessage struct {
+	code   uint32
+	params map[string]string
+	// the raw frame after the request code
+	frame []byte
+}
+
+func readStartupMessage(r io.Reader) (*startupMessage, error) {
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	size := int(binary.BigEndian.Uint32(header[0:4])) - 8
+	if size < 0 || size > maxStartupSize {
+		return nil, fmt.Errorf("invalid startup message length (%v)", size)
+	}
+	msg := &startupMessage{code: binary.BigEndian.Uint32(header[4:8]), frame: make([]byte, size)}
+	if _, err := io.ReadFull(r, msg.frame); err != nil {
+		return nil, err
+	}
+	if msg.code != protocolVersion3 {
+		return msg, nil
+	}
+	// a list of parameter name and value pairs terminated by a zero byte
+	msg.params = map[string]string{}
+	parts := bytes.Split(bytes.TrimRight(msg.frame, "\x00"), []byte{0})
+	for i := 0; i+1 < len(parts); i += 2 {
+		msg.params[string(parts[i])] = string(parts[i+1])
+	}
+	return msg, nil
+}
+
+func (m *startupMessage) isCancelRequest() bool {
+	return m.code == pgtypes.ClientCancelRequestMessage && len(m.frame) == 8
+}
+
+func encodeStartupMessage(code uint32, frame []byte) []byte {
+	dst := make([]byte, 8, len(frame)+8)
+	binary.BigEndian.PutUint32(dst[0:4], uint32(len(frame)+8))
+	binary.BigEndian.PutUint32(dst[4:8], code)
+	return append(dst, frame...)
+}
+
+func encodeStartupParams(params map[string]string) []byte {
+	var frame []byte
+	for key, val := range params {
+		frame = append(frame, key...)
+		frame = append(frame, 0)
+		frame = append(frame, val...)
+		frame = append(frame, 0)
+	}
+	return append(frame, 0)
+}
+
+func newAuthOKMessage() *message {
+	return &message{typ: serverAuth, body: []byte{0, 0, 0, authOK}}
+}
+
+// newErrorMessage returns a fatal error response to be sent to the client
+func newErrorMessage(code, errMsg string) *message {
+	var body []byte
+	for _, field := range []struct {
+		typ byte
+		val string
+	}{{'S', "FATAL"}, {'V', "FATAL"}, {'C', code}, {'M', errMsg}} {
+		body = append(body, field.typ)
+		body = append(body, field.val...)
+		body = append(body, 0)
+	}
+	return &message{typ: serverErrorMessage, body: append(body, 0)}
+}
+
+// parseErrorMessage returns a readable representation of an error response message
+func parseErrorMessage(body []byte) string {
+	fields := map[byte]string{}
+	for _, field := range bytes.Split(body, []byte{0}) {
+		if len(field) > 1 {
+			fields[field[0]] = string(field[1:])
+		}
+	}
+	var sb strings.Builder
+	if severity := fields['S']; severity != "" {
+		sb.WriteString(severity + ": ")
+	}
+	sb.WriteString(fields['M'])
+	if code := fields['C']; code != "" {
+		sb.WriteString(fmt.Sprintf(" (SQLSTATE %s)", code))
+	}
+	return sb.String()
+}
+
+func newPasswordMessage(data []byte) *message {
+	return &message{typ: pgtypes.ClientPassword.Byte(), body: data}
+}
@@ -133,6 +133,10 @@ func (a *Agent) Run() error {
 		case pbagent.MongoDBConnectionWrite:
 			a.processMongoDBProtocol(pkt)
 
+		// Redis Protocol
+		case pbagent.RedisConnectionWrite:
+			a.processRedisProtocol(pkt)
+
 		// raw tcp
 		case pbagent.TCPConnectionWrite:
 			a.processTCPWriteServer(pkt)
@@ -292,6 +296,23 @@ func (a *Agent) sessionCleanup(sessionID string) {
 			a.connStore.Del(key)
 		}
 	}
+	a.connStore.Del(fmt.Sprintf(secretsStoreKey, sessionID))
+	// revoke the dynamic credentials issued for the session
+	if err := secretsmanager.Release(sessionID); err != nil {
+		log.Warnf("session=%s - failed releasing secrets, reason=%v", sessionID, err)
+	}
+}
+
+// invalidateSecretsOnAuthFailure removes the secrets of the session from the cache
+// when the remote server denies the credentials, they could have been rotated in the provider
+func (a *Agent) invalidateSecretsOnAuthFailure(sessionID, errMsg string) {
+	if !isAuthenticationError(errMsg) {
+		return
+	}
+	if envVars, ok := a.connStore.Get(fmt.Sprintf(secretsStoreKey, sessionID)).(map[string]any); ok {
+		log.Infof("session=%v - authentication failure, invalidating cached secrets", sessionID)
+		secretsmanager.Invalidate(envVars)
+	}
 }
 
 func (a *Agent) sendClientSessionClose(sessionID string, errMsg string) {
@@ -310,6 +331,7 @@ func (a *Agent) sendClientSessionCloseWithExitCode(sessionID string, errMsg, exi
 	var errPayload []byte
 	if errMsg != "" {
 		errPayload = []byte(errMsg)
+		a.invalidateSecretsOnAuthFailure(sessionID, errMsg)
 	}
 	_ = a.client.Send(&pb.Packet{
 		Type:    pbclient.SessionClose,
@@ -392,7 +414,8 @@ func (a *Agent) checkTCPLiveness(pkt *pb.Packet, envVars map[string]any) error {
 		connType == pb.ConnectionTypeTCP ||
 		connType == pb.ConnectionTypeMySQL ||
 		connType == pb.ConnectionTypeMSSQL ||
-		connType == pb.ConnectionTypeMongoDB {
+		connType == pb.ConnectionTypeMongoDB ||
+		connType == pb.ConnectionTypeRedis {
 		connEnvVars, err := parseConnectionEnvVars(envVars, connType)
 		if err != nil {
 			return err
@@ -424,7 +447,9 @@ func (a *Agent) decodeConnectionParams(sessionID []byte, pkt *pb.Packet) *pb.Age
 		})
 		return nil
 	}
-	envVars, err := secretsmanager.Decode(connParams.EnvVars)
+	// keep the references of the secrets to invalidate them in case of authentication failures
+	a.connStore.Set(fmt.Sprintf(secretsStoreKey, string(sessionID)), connParams.EnvVars)
+	envVars, err := secretsmanager.Decode(string(sessionID), connParams.EnvVars)
 	if err != nil {
 		errMsg := fmt.Sprintf("failed decoding environment variables %v", err)
 		log.With("sid", string(sessionID)).Warn(errMsg)
@@ -487,6 +512,24 @@ func (a *Agent) getMSPresidioAnonymizerURL() string {
 	return string(v)
 }
 
+// isAuthenticationError reports if the error message is an authentication
+// failure of the database servers supported by the agent
+func isAuthenticationError(errMsg string) bool {
+	errMsg = strings.ToLower(errMsg)
+	for _, pattern := range []string{
+		"authentication failed",  // postgres, mongodb
+		"access denied for user", // mysql
+		"login failed for user",  // mssql
+		"wrongpass",              // redis
+		"invalid password",       // redis < 6
+	} {
+		if strings.Contains(errMsg, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
 func b64Enc(src []byte) string { return base64.StdEncoding.EncodeToString(src) }
 
 func isPortActive(e *connEnv) error {
@@ -591,6 +634,17 @@ func parseConnectionEnvVars(envVars map[string]any, connType pb.ConnectionType)
 		if env.host == "" || env.pass == "" || env.user == "" {
 			return nil, errors.New("missing required secrets for mongodb connection [HOST, USER, PASS]")
 		}
+	case pb.ConnectionTypeRedis:
+		if env.port == "" {
+			env.port = "6379"
+		}
+		if env.scheme != "" && env.scheme != "redis" && env.scheme != "rediss" {
+			return nil, fmt.Errorf("wrong option (%q) for SCHEME, accept only: %v", env.scheme,
+				[]string{"redis", "rediss"})
+		}
+		if env.host == "" {
+			return nil, errors.New("missing required secrets for redis connection [HOST]")
+		}
 	case pb.ConnectionTypeSSH:
 		if env.port == "" {
 			env.port = "22"
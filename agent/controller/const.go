@@ -3,6 +3,7 @@ package controller
 const (
 	execStoreKey               string = "exec:%s"
 	cmdStoreKey                string = "cmd:%s"
+	secretsStoreKey            string = "secrets:%s"
 	gcpJSONCredentialsKey      string = "gcp_credentials"
 	dlpProviderKey             string = "dlp_provider"
 	msPresidioAnalyzerURLKey   string = "mspresidio_analyzer_url"
@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/hoophq/hoop/common/log"
+	pb "github.com/hoophq/hoop/common/proto"
+	pbclient "github.com/hoophq/hoop/common/proto/client"
+	"github.com/hoophq/hoop/common/redistypes"
+)
+
+func (a *Agent) processRedisProtocol(pkt *pb.Packet) {
+	sid := string(pkt.Spec[pb.SpecGatewaySessionID])
+	connParams := a.connectionParams(sid)
+	if connParams == nil {
+		log.With("sid", sid).Errorf("connection params not found")
+		a.sendClientSessionClose(sid, "connection params not found, contact the administrator")
+		return
+	}
+	clientConnectionID := string(pkt.Spec[pb.SpecClientConnectionID])
+	if clientConnectionID == "" {
+		log.With("sid", sid).Errorf("connection id not found in packet specification")
+		a.sendClientSessionClose(sid, "connection id not found, contact the administrator")
+		return
+	}
+	clientConnectionIDKey := fmt.Sprintf("%s:%s", sid, clientConnectionID)
+	if serverWriter, ok := a.connStore.Get(clientConnectionIDKey).(io.WriteCloser); ok {
+		if _, err := serverWriter.Write(pkt.Payload); err != nil {
+			log.With("sid", sid).Errorf("failed sending packet, err=%v", err)
+			a.sendClientSessionClose(sid, "fail to write packet")
+			_ = serverWriter.Close()
+		}
+		return
+	}
+
+	connenv, err := parseConnectionEnvVars(connParams.EnvVars, pb.ConnectionTypeRedis)
+	if err != nil {
+		log.With("sid", sid).Errorf("redis credentials not found in memory, err=%v", err)
+		a.sendClientSessionClose(sid, "credentials are empty, contact the administrator")
+		return
+	}
+	log.With("sid", sid, "conn", clientConnectionID, "tls", connenv.scheme == "rediss").
+		Infof("starting redis connection at %v", connenv.Address())
+	serverConn, err := newRedisConn(connenv)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed connecting with redis server, reason=%v", err)
+		log.With("sid", sid).Error(errMsg)
+		a.sendClientSessionClose(sid, errMsg)
+		return
+	}
+	a.connStore.Set(clientConnectionIDKey, serverConn)
+	streamClient := pb.NewStreamWriter(a.client, pbclient.RedisConnectionWrite, pkt.Spec)
+	go func() {
+		defer a.connStore.Del(clientConnectionIDKey)
+		if _, err := serverConn.Write(pkt.Payload); err != nil {
+			log.With("sid", sid).Errorf("failed writing first packet, err=%v", err)
+			_ = serverConn.Close()
+			a.sendClientTCPConnectionClose(sid, clientConnectionID)
+			return
+		}
+		if _, err := io.Copy(streamClient, serverConn); err != nil {
+			log.With("sid", sid).Infof("done copying redis connection, reason=%v", err)
+		}
+		a.sendClientTCPConnectionClose(sid, clientConnectionID)
+	}()
+}
+
+// newRedisConn connects to the redis server authenticating with the credentials of the
+// connection. The client connects without credentials, the AUTH and SELECT commands are
+// issued by the agent before forwarding the commands of the client.
+func newRedisConn(env *connEnv) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: time.Second * 10}
+	var conn net.Conn
+	var err error
+	if env.scheme == "rediss" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", env.Address(), &tls.Config{
+			ServerName:         env.host,
+			InsecureSkipVerify: env.insecure,
+		})
+	} else {
+		conn, err = dialer.Dial("tcp", env.Address())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed dialing server: %v", err)
+	}
+	var commands [][]string
+	switch {
+	case env.pass != "" && env.user != "":
+		commands = append(commands, []string{"AUTH", env.user, env.pass})
+	case env.pass != "":
+		commands = append(commands, []string{"AUTH", env.pass})
+	}
+	if env.dbname != "" && env.dbname != "0" {
+		commands = append(commands, []string{"SELECT", env.dbname})
+	}
+	_ = conn.SetDeadline(time.Now().Add(time.Second * 10))
+	r := bufio.NewReader(conn)
+	for _, args := range commands {
+		if _, err := conn.Write(redistypes.EncodeCommand(args...)); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("failed sending %v command: %v", args[0], err)
+		}
+		reply, err := redistypes.ReadValue(r)
+		if err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("failed reading %v reply: %v", args[0], err)
+		}
+		if errMsg, isErr := redistypes.ParseError(reply); isErr {
+			_ = conn.Close()
+			return nil, fmt.Errorf("%v command failed: %v", args[0], errMsg)
+		}
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return conn, nil
+}
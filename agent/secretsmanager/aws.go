@@ -2,7 +2,6 @@ package secretsmanager
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 
@@ -11,12 +10,14 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 	"github.com/aws/smithy-go/logging"
 	"github.com/hoophq/hoop/common/log"
-	"github.com/hoophq/hoop/common/memory"
 )
 
+func init() {
+	Register(string(secretProviderAWSSecretsManagerType), func() (SecretsGetter, error) { return newAwsProvider() })
+}
+
 type awsProvider struct {
 	client *secretsmanager.Client
-	cache  memory.Store
 }
 
 func newAwsProvider() (*awsProvider, error) {
@@ -31,17 +32,12 @@ func newAwsProvider() (*awsProvider, error) {
 		// TODO: add zap as logger
 		o.Logger = logging.NewStandardLogger(os.Stdout)
 	})
-	return &awsProvider{svc, memory.New()}, nil
+	return &awsProvider{svc}, nil
 }
 
 func (p *awsProvider) GetKey(secretID, secretKey string) (string, error) {
-	if obj := p.cache.Get(secretID); obj != nil {
-		if keyVal, ok := obj.(map[string]any); ok {
-			if v, ok := keyVal[secretKey]; ok {
-				return fmt.Sprintf("%v", v), nil
-			}
-			return "", fmt.Errorf("secret key not found. secret=%v, key=%v", secretID, secretKey)
-		}
+	if v, ok := getCachedKey(secretProviderAWSSecretsManagerType, secretID, secretKey); ok {
+		return v, nil
 	}
 
 	input := &secretsmanager.GetSecretValueInput{
@@ -51,13 +47,13 @@ func (p *awsProvider) GetKey(secretID, secretKey string) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("(%v) %v", secretID, err)
 	}
-	var keyValSecret map[string]any
-	if err := json.Unmarshal([]byte(*result.SecretString), &keyValSecret); err != nil {
+	keyValSecret, err := decodeJSONSecret([]byte(*result.SecretString))
+	if err != nil {
 		return "", fmt.Errorf("failed deserializing secret key/val, err=%v", err)
 	}
 	if v, ok := keyValSecret[secretKey]; ok {
-		p.cache.Set(secretID, keyValSecret)
-		return fmt.Sprintf("%v", v), nil
+		secretsCache.set(secretProviderAWSSecretsManagerType, secretID, keyValSecret, nil)
+		return v, nil
 	}
 	return "", fmt.Errorf("secret id %s found, but key %s was not", secretID, secretKey)
 }
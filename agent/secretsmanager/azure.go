@@ -0,0 +1,153 @@
+package secretsmanager
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hoophq/hoop/common/httpclient"
+	"github.com/hoophq/hoop/common/log"
+)
+
+const (
+	azureKeyVaultAPIVersion     string = "7.4"
+	azureKeyVaultResource       string = "https://vault.azure.net"
+	defaultAzureAuthorityHost   string = "https://login.microsoftonline.com"
+	defaultAzureIMDSEndpoint    string = "http://169.254.169.254"
+	azureIMDSTokenAPIVersion    string = "2018-02-01"
+	azureClientCredentialsScope string = azureKeyVaultResource + "/.default"
+)
+
+func init() {
+	Register(string(secretProviderAzureKeyVaultType), func() (SecretsGetter, error) { return newAzureKeyVaultProvider(nil) })
+}
+
+// azureKeyVaultProvider fetches secrets stored as json objects in Azure Key Vault.
+// It authenticates with a service principal when the AZURE_TENANT_ID, AZURE_CLIENT_ID
+// and AZURE_CLIENT_SECRET envs are set, otherwise it uses the managed identity of the host.
+//
+// _azurekv:<secret-name>:<secret-key> - the latest version of the secret in the AZURE_KEYVAULT_URL vault
+//
+// _azurekv:<secret-name>/<version>:<secret-key> - a specific version of the secret
+type azureKeyVaultProvider struct {
+	vaultURL      string
+	tenantID      string
+	clientID      string
+	clientSecret  string
+	authorityHost string
+	imdsEndpoint  string
+	tokenCache    accessTokenCache
+	httpClient    httpclient.HttpClient
+}
+
+type azureSecretBundle struct {
+	ID    string `json:"id"`
+	Value string `json:"value"`
+}
+
+func newAzureKeyVaultProvider(httpClient httpclient.HttpClient) (*azureKeyVaultProvider, error) {
+	vaultURL := strings.TrimSuffix(os.Getenv("AZURE_KEYVAULT_URL"), "/")
+	if vaultURL == "" {
+		return nil, fmt.Errorf("AZURE_KEYVAULT_URL env not set")
+	}
+	if httpClient == nil {
+		httpClient = httpclient.NewHttpClient("")
+	}
+	p := &azureKeyVaultProvider{
+		vaultURL:      vaultURL,
+		tenantID:      os.Getenv("AZURE_TENANT_ID"),
+		clientID:      os.Getenv("AZURE_CLIENT_ID"),
+		clientSecret:  os.Getenv("AZURE_CLIENT_SECRET"),
+		authorityHost: strings.TrimSuffix(os.Getenv("AZURE_AUTHORITY_HOST"), "/"),
+		imdsEndpoint:  strings.TrimSuffix(os.Getenv("AZURE_IMDS_ENDPOINT"), "/"),
+		httpClient:    httpClient,
+	}
+	if p.clientSecret != "" && (p.tenantID == "" || p.clientID == "") {
+		return nil, fmt.Errorf("AZURE_CLIENT_SECRET env is set but AZURE_TENANT_ID and/or AZURE_CLIENT_ID env are empty")
+	}
+	if p.authorityHost == "" {
+		p.authorityHost = defaultAzureAuthorityHost
+	}
+	if p.imdsEndpoint == "" {
+		p.imdsEndpoint = defaultAzureIMDSEndpoint
+	}
+	return p, nil
+}
+
+func (p *azureKeyVaultProvider) GetKey(secretID, secretKey string) (string, error) {
+	if v, ok := getCachedKey(secretProviderAzureKeyVaultType, secretID, secretKey); ok {
+		return v, nil
+	}
+	accessToken, err := p.getAccessToken()
+	if err != nil {
+		return "", fmt.Errorf("(%v) failed obtaining azure access token, reason=%v", secretID, err)
+	}
+	ctx, cancelFn := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancelFn()
+	apiURL := fmt.Sprintf("%s/secrets/%s?api-version=%s", p.vaultURL, strings.Trim(secretID, "/"), azureKeyVaultAPIVersion)
+	log.With("secretid", secretID).Debugf("fetching azure key vault secret at %v", apiURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed creating http request, err=%v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	var bundle azureSecretBundle
+	if err := doJSONRequest(p.httpClient, req, &bundle); err != nil {
+		return "", fmt.Errorf("(%v) %v", secretID, err)
+	}
+	secretData, err := decodeJSONSecret([]byte(bundle.Value))
+	if err != nil {
+		return "", fmt.Errorf("(%v) failed decoding secret value to json, reason=%v", secretID, err)
+	}
+	if v, ok := secretData[secretKey]; ok {
+		secretsCache.set(secretProviderAzureKeyVaultType, secretID, secretData, nil)
+		return v, nil
+	}
+	return "", fmt.Errorf("secret id %s found, but key %s was not", secretID, secretKey)
+}
+
+func (p *azureKeyVaultProvider) getAccessToken() (string, error) {
+	return p.tokenCache.get(func() (string, time.Duration, error) {
+		ctx, cancelFn := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancelFn()
+		var req *http.Request
+		var err error
+		if p.clientSecret != "" {
+			// https://learn.microsoft.com/en-us/entra/identity-platform/v2-oauth2-client-creds-grant-flow
+			form := url.Values{
+				"grant_type":    {"client_credentials"},
+				"client_id":     {p.clientID},
+				"client_secret": {p.clientSecret},
+				"scope":         {azureClientCredentialsScope},
+			}
+			tokenURL := fmt.Sprintf("%s/%s/oauth2/v2.0/token", p.authorityHost, p.tenantID)
+			req, err = http.NewRequestWithContext(ctx, "POST", tokenURL, strings.NewReader(form.Encode()))
+			if err != nil {
+				return "", 0, fmt.Errorf("failed creating http request, err=%v", err)
+			}
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		} else {
+			// https://learn.microsoft.com/en-us/entra/identity/managed-identities-azure-resources/how-to-use-vm-token
+			query := url.Values{"api-version": {azureIMDSTokenAPIVersion}, "resource": {azureKeyVaultResource}}
+			if p.clientID != "" {
+				// user assigned managed identity
+				query.Set("client_id", p.clientID)
+			}
+			tokenURL := fmt.Sprintf("%s/metadata/identity/oauth2/token?%s", p.imdsEndpoint, query.Encode())
+			req, err = http.NewRequestWithContext(ctx, "GET", tokenURL, nil)
+			if err != nil {
+				return "", 0, fmt.Errorf("failed creating http request, err=%v", err)
+			}
+			req.Header.Set("Metadata", "true")
+		}
+		var resp oauthTokenResponse
+		if err := doJSONRequest(p.httpClient, req, &resp); err != nil {
+			return "", 0, err
+		}
+		return resp.AccessToken, resp.expiresIn(), nil
+	})
+}
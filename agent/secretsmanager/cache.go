@@ -0,0 +1,159 @@
+package secretsmanager
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hoophq/hoop/common/log"
+)
+
+// defaultCacheTTL is how long a secret is kept in the memory of the agent,
+// it could be changed with the HOOP_SECRETS_CACHE_TTL env. A zero value disables the cache.
+const defaultCacheTTL = time.Minute * 5
+
+var secretsCache = &secretCache{items: map[string]*cachedSecret{}}
+
+// secretLease is the lease of a secret issued by the provider (e.g.: vault).
+// A renewable lease is renewed when a third of its duration remains
+type secretLease struct {
+	id        string
+	duration  time.Duration
+	renewable bool
+	expiresAt time.Time
+	renew     func(leaseID string) (time.Duration, error)
+}
+
+type cachedSecret struct {
+	data      map[string]string
+	expiresAt time.Time
+	lease     *secretLease
+}
+
+// secretCache keeps the secrets obtained from providers among sessions,
+// it avoids fetching the same secret from the provider on each session open
+type secretCache struct {
+	mu    sync.Mutex
+	items map[string]*cachedSecret
+}
+
+func cacheKey(provider secretProviderType, secretID string) string {
+	return fmt.Sprintf("%s:%s", provider, secretID)
+}
+
+func cacheTTL() time.Duration {
+	v := os.Getenv("HOOP_SECRETS_CACHE_TTL")
+	if v == "" {
+		return defaultCacheTTL
+	}
+	ttl, err := time.ParseDuration(v)
+	if err != nil || ttl < 0 {
+		log.Warnf("invalid HOOP_SECRETS_CACHE_TTL env %q, using default of %v", v, defaultCacheTTL)
+		return defaultCacheTTL
+	}
+	return ttl
+}
+
+// get returns the data of a secret if it's not expired. A renewable lease
+// about to expire is renewed, in case of failure the secret is removed from the cache.
+func (c *secretCache) get(provider secretProviderType, secretID string) (map[string]string, bool) {
+	key := cacheKey(provider, secretID)
+	c.mu.Lock()
+	item, ok := c.items[key]
+	var lease *secretLease
+	if ok {
+		lease = item.lease
+	}
+	c.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	now := time.Now().UTC()
+	if !now.Before(item.expiresAt) {
+		c.del(provider, secretID)
+		return nil, false
+	}
+	if lease == nil || lease.expiresAt.Sub(now) > lease.duration/3 {
+		return item.data, true
+	}
+	if !lease.renewable || lease.renew == nil || !now.Before(lease.expiresAt) {
+		c.del(provider, secretID)
+		return nil, false
+	}
+	duration, err := lease.renew(lease.id)
+	if err != nil {
+		log.With("secretid", secretID).Warnf("failed renewing lease %v, reason=%v", lease.id, err)
+		c.del(provider, secretID)
+		return nil, false
+	}
+	log.With("secretid", secretID).Infof("lease %v renewed with success, duration=%v", lease.id, duration)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item.lease = &secretLease{
+		id:        lease.id,
+		duration:  duration,
+		renewable: lease.renewable,
+		expiresAt: now.Add(duration),
+		renew:     lease.renew,
+	}
+	return item.data, true
+}
+
+// set stores the secret for the duration of the cache ttl. When the lease is
+// not renewable the secret is kept at most for the duration of the lease.
+func (c *secretCache) set(provider secretProviderType, secretID string, data map[string]string, lease *secretLease) {
+	ttl := cacheTTL()
+	if ttl == 0 {
+		return
+	}
+	now := time.Now().UTC()
+	item := &cachedSecret{data: data, expiresAt: now.Add(ttl)}
+	if lease != nil && lease.duration > 0 {
+		lease.expiresAt = now.Add(lease.duration)
+		if !lease.renewable && lease.expiresAt.Before(item.expiresAt) {
+			item.expiresAt = lease.expiresAt
+		}
+		item.lease = lease
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[cacheKey(provider, secretID)] = item
+}
+
+func (c *secretCache) del(provider secretProviderType, secretID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.items, cacheKey(provider, secretID))
+}
+
+func (c *secretCache) flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = map[string]*cachedSecret{}
+}
+
+// getCachedKey returns the value of a key from a cached secret. A key not found in
+// the cached secret is considered a miss, the secret could have been updated in the provider.
+func getCachedKey(provider secretProviderType, secretID, secretKey string) (string, bool) {
+	keyVal, ok := secretsCache.get(provider, secretID)
+	if !ok {
+		return "", false
+	}
+	v, ok := keyVal[secretKey]
+	return v, ok
+}
+
+// Invalidate removes the secrets referenced by the environment variables from the cache,
+// the next session will obtain them from the provider. It must be used when a session
+// fails authenticating with credentials obtained from a secrets provider.
+func Invalidate(envVars map[string]any) {
+	for envKey, encEnvVal := range envVars {
+		attr, err := decodeVal(encEnvVal)
+		if err != nil || attr == nil || getProviderFactory(attr.provider) == nil {
+			continue
+		}
+		log.With("secretid", attr.secretID).Infof("invalidating cached secret of %v", envKey)
+		secretsCache.del(attr.provider, attr.secretID)
+	}
+}
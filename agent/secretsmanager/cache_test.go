@@ -0,0 +1,97 @@
+package secretsmanager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretCache(t *testing.T) {
+	data := map[string]string{"PASS": "secret"}
+	for _, tt := range []struct {
+		msg       string
+		ttl       string
+		lease     *secretLease
+		elapsed   time.Duration
+		wantFound bool
+		wantLease time.Duration
+	}{
+		{msg: "it should return the cached secret", wantFound: true},
+		{msg: "it should expire the secret after the ttl", ttl: "1m", elapsed: time.Minute},
+		{msg: "it should not cache when the ttl is zero", ttl: "0"},
+		{msg: "it should expire the secret when the lease expires before the ttl", ttl: "10m",
+			lease: &secretLease{id: "kv/1", duration: time.Minute}, elapsed: time.Minute},
+		{msg: "it should renew the lease when a third of its duration remains", ttl: "10m",
+			lease: &secretLease{id: "db/1", duration: time.Minute * 3, renewable: true,
+				renew: func(string) (time.Duration, error) { return time.Hour, nil }},
+			elapsed: time.Minute * 2, wantFound: true, wantLease: time.Hour},
+		{msg: "it should not renew the lease when it has enough time left", ttl: "10m",
+			lease: &secretLease{id: "db/1", duration: time.Minute * 3, renewable: true,
+				renew: func(string) (time.Duration, error) { return 0, fmt.Errorf("unexpected renew") }},
+			elapsed: time.Minute, wantFound: true, wantLease: time.Minute * 3},
+		{msg: "it should remove the secret when it fails renewing the lease", ttl: "10m",
+			lease: &secretLease{id: "db/1", duration: time.Minute * 3, renewable: true,
+				renew: func(string) (time.Duration, error) { return 0, fmt.Errorf("lease not found") }},
+			elapsed: time.Minute * 2},
+		{msg: "it should remove the secret when the renewable lease has expired", ttl: "10m",
+			lease: &secretLease{id: "db/1", duration: time.Minute * 3, renewable: true,
+				renew: func(string) (time.Duration, error) { return time.Hour, nil }},
+			elapsed: time.Minute * 3},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			secretsCache.flush()
+			t.Setenv("HOOP_SECRETS_CACHE_TTL", tt.ttl)
+			secretsCache.set(secretProviderVaultKv2Type, "dbsecret", data, tt.lease)
+			// move the expiration of the secret back in time
+			if item, ok := secretsCache.items[cacheKey(secretProviderVaultKv2Type, "dbsecret")]; ok {
+				item.expiresAt = item.expiresAt.Add(-tt.elapsed)
+				if item.lease != nil {
+					item.lease.expiresAt = item.lease.expiresAt.Add(-tt.elapsed)
+				}
+			}
+
+			got, found := secretsCache.get(secretProviderVaultKv2Type, "dbsecret")
+			assert.Equal(t, tt.wantFound, found)
+			if !tt.wantFound {
+				assert.Nil(t, got)
+				assert.Empty(t, secretsCache.items)
+				return
+			}
+			assert.Equal(t, data, got)
+			if tt.wantLease > 0 {
+				item := secretsCache.items[cacheKey(secretProviderVaultKv2Type, "dbsecret")]
+				assert.Equal(t, tt.wantLease, item.lease.duration)
+			}
+		})
+	}
+}
+
+func TestInvalidate(t *testing.T) {
+	secretsCache.flush()
+	baseDir := t.TempDir()
+	t.Setenv("HOOP_SECRETS_DIR", baseDir)
+	secretFile := filepath.Join(baseDir, "pg.json")
+	assert.Nil(t, os.WriteFile(secretFile, []byte(`{"PASS": "old-secret"}`), 0600))
+
+	envVars := map[string]any{
+		"envvar:PASS": encodeVal("_file:pg.json:PASS"),
+		"envvar:HOST": encodeVal("127.0.0.1"),
+	}
+	decodeFn := func() string {
+		got, err := Decode("", envVars)
+		assert.Nil(t, err)
+		return fmt.Sprintf("%v", got["envvar:PASS"])
+	}
+	assert.Equal(t, encodeVal("old-secret"), decodeFn())
+
+	// rotate the secret
+	assert.Nil(t, os.WriteFile(secretFile, []byte(`{"PASS": "new-secret"}`), 0600))
+	assert.Equal(t, encodeVal("old-secret"), decodeFn(), "it should return the cached secret")
+
+	Invalidate(envVars)
+	assert.Equal(t, encodeVal("new-secret"), decodeFn(), "it should obtain the secret from the provider")
+}
@@ -6,6 +6,10 @@ import (
 	"os"
 )
 
+func init() {
+	Register(string(secretProviderEnvJSONType), func() (SecretsGetter, error) { return &envJsonProvider{}, nil })
+}
+
 type envJsonProvider struct{}
 
 func (p *envJsonProvider) GetKey(secretID, secretKey string) (string, error) {
@@ -0,0 +1,85 @@
+package secretsmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/hoophq/hoop/common/log"
+)
+
+const defaultExecTimeout = time.Second * 30
+
+func init() {
+	Register(string(secretProviderExecType), func() (SecretsGetter, error) { return newExecProvider() })
+}
+
+// execProvider runs a helper command configured in the agent runtime and reads
+// the secrets from its standard output. The secret id is passed as the last argument
+// of the command and it must print a json object containing the secret keys.
+//
+// _exec:<secret-id>:<secret-key> - runs $HOOP_SECRETS_EXEC_COMMAND <secret-id>
+type execProvider struct {
+	command string
+	args    []string
+	timeout time.Duration
+}
+
+func newExecProvider() (*execProvider, error) {
+	var cmdList []string
+	if cmdJson := os.Getenv("HOOP_SECRETS_EXEC_COMMAND"); cmdJson != "" {
+		if err := json.Unmarshal([]byte(cmdJson), &cmdList); err != nil {
+			// not a json array, use it as the path of the command
+			cmdList = []string{cmdJson}
+		}
+	}
+	if len(cmdList) == 0 || cmdList[0] == "" {
+		return nil, fmt.Errorf("HOOP_SECRETS_EXEC_COMMAND env not set")
+	}
+	timeout := defaultExecTimeout
+	if v := os.Getenv("HOOP_SECRETS_EXEC_TIMEOUT"); v != "" {
+		var err error
+		if timeout, err = time.ParseDuration(v); err != nil {
+			return nil, fmt.Errorf("failed parsing HOOP_SECRETS_EXEC_TIMEOUT env, reason=%v", err)
+		}
+	}
+	return &execProvider{command: cmdList[0], args: cmdList[1:], timeout: timeout}, nil
+}
+
+func (p *execProvider) GetKey(secretID, secretKey string) (string, error) {
+	if v, ok := getCachedKey(secretProviderExecType, secretID, secretKey); ok {
+		return v, nil
+	}
+	ctx, cancelFn := context.WithTimeout(context.Background(), p.timeout)
+	defer cancelFn()
+	args := append(append([]string{}, p.args...), secretID)
+	cmd := exec.CommandContext(ctx, p.command, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	log.With("secretid", secretID).Debugf("executing secrets helper command %v", p.command)
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("(%v) failed executing secrets helper command, reason=%v, stderr=%v",
+			secretID, err, truncateOutput(stderr.String()))
+	}
+	secretData, err := decodeJSONSecret(stdout.Bytes())
+	if err != nil {
+		return "", fmt.Errorf("(%v) failed decoding output of secrets helper command to json, err=%v", secretID, err)
+	}
+	if v, ok := secretData[secretKey]; ok {
+		secretsCache.set(secretProviderExecType, secretID, secretData, nil)
+		return v, nil
+	}
+	return "", fmt.Errorf("secret id %s found, but key %s was not", secretID, secretKey)
+}
+
+func truncateOutput(s string) string {
+	if len(s) > 500 {
+		return s[:500] + " [truncated]"
+	}
+	return s
+}
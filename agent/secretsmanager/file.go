@@ -0,0 +1,86 @@
+package secretsmanager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const defaultSecretsDir string = "/etc/hoop/secrets"
+
+func init() {
+	Register(string(secretProviderFileType), func() (SecretsGetter, error) { return newFileProvider() })
+}
+
+// fileProvider reads secrets from a mounted secrets directory, e.g.: kubernetes secret volumes.
+// The secret id could be a directory containing a file for each key or a json file.
+//
+// _file:<secret-dir>:<secret-key> - reads the file <base-dir>/<secret-dir>/<secret-key>
+//
+// _file:<secret-file.json>:<secret-key> - reads the key of the json file <base-dir>/<secret-file.json>
+type fileProvider struct {
+	baseDir string
+}
+
+func newFileProvider() (*fileProvider, error) {
+	baseDir := os.Getenv("HOOP_SECRETS_DIR")
+	if baseDir == "" {
+		baseDir = defaultSecretsDir
+	}
+	baseDir, err := filepath.Abs(baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve secrets directory, reason=%v", err)
+	}
+	return &fileProvider{baseDir: baseDir}, nil
+}
+
+func (p *fileProvider) GetKey(secretID, secretKey string) (string, error) {
+	if v, ok := getCachedKey(secretProviderFileType, secretID, secretKey); ok {
+		return v, nil
+	}
+	secretPath, err := p.resolvePath(secretID)
+	if err != nil {
+		return "", err
+	}
+	fileInfo, err := os.Stat(secretPath)
+	if err != nil {
+		return "", fmt.Errorf("(%v) %v", secretID, err)
+	}
+	if fileInfo.IsDir() {
+		keyPath, err := p.resolvePath(filepath.Join(secretID, secretKey))
+		if err != nil {
+			return "", err
+		}
+		data, err := os.ReadFile(keyPath)
+		if err != nil {
+			return "", fmt.Errorf("(%v) %v", secretID, err)
+		}
+		// files created by editors and shell redirections ends with a line break
+		return strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r"), nil
+	}
+
+	data, err := os.ReadFile(secretPath)
+	if err != nil {
+		return "", fmt.Errorf("(%v) %v", secretID, err)
+	}
+	secretData, err := decodeJSONSecret(data)
+	if err != nil {
+		return "", fmt.Errorf("failed decoding secret file %q to json, err=%v", secretID, err)
+	}
+	if v, ok := secretData[secretKey]; ok {
+		secretsCache.set(secretProviderFileType, secretID, secretData, nil)
+		return v, nil
+	}
+	return "", fmt.Errorf("secret id %s found, but key %s was not", secretID, secretKey)
+}
+
+// resolvePath returns the absolute path of the secret, denying paths outside the base directory
+func (p *fileProvider) resolvePath(secretID string) (string, error) {
+	secretPath := filepath.Join(p.baseDir, filepath.Clean("/"+secretID))
+	relPath, err := filepath.Rel(p.baseDir, secretPath)
+	if err != nil || relPath == "." || strings.HasPrefix(relPath, "..") {
+		return "", fmt.Errorf("secret %q is not a valid path in the secrets directory", secretID)
+	}
+	return secretPath, nil
+}
@@ -0,0 +1,142 @@
+package secretsmanager
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hoophq/hoop/common/httpclient"
+	"github.com/hoophq/hoop/common/log"
+)
+
+const (
+	defaultGCPSecretManagerEndpoint string = "https://secretmanager.googleapis.com"
+	defaultGCPMetadataHost          string = "metadata.google.internal"
+)
+
+func init() {
+	Register(string(secretProviderGCPSecretManagerType), func() (SecretsGetter, error) { return newGCPSecretManagerProvider(nil) })
+}
+
+// gcpSecretManagerProvider fetches secrets stored as json objects in GCP Secret Manager.
+// It authenticates with the access token of the GOOGLE_OAUTH_ACCESS_TOKEN env or
+// with the service account of the compute metadata server (e.g.: GKE workload identity).
+//
+// _gcpsm:<secret-name>:<secret-key> - the latest version of the secret in the GOOGLE_CLOUD_PROJECT project
+//
+// _gcpsm:projects/<project>/secrets/<secret-name>/versions/<version>:<secret-key> - the full resource name
+type gcpSecretManagerProvider struct {
+	projectID    string
+	endpoint     string
+	metadataHost string
+	staticToken  string
+	tokenCache   accessTokenCache
+	httpClient   httpclient.HttpClient
+}
+
+type gcpAccessSecretVersionResponse struct {
+	Name    string `json:"name"`
+	Payload struct {
+		Data string `json:"data"`
+	} `json:"payload"`
+}
+
+func newGCPSecretManagerProvider(httpClient httpclient.HttpClient) (*gcpSecretManagerProvider, error) {
+	if httpClient == nil {
+		httpClient = httpclient.NewHttpClient("")
+	}
+	p := &gcpSecretManagerProvider{
+		projectID:    os.Getenv("GOOGLE_CLOUD_PROJECT"),
+		endpoint:     strings.TrimSuffix(os.Getenv("GCP_SECRET_MANAGER_ENDPOINT"), "/"),
+		metadataHost: os.Getenv("GCE_METADATA_HOST"),
+		staticToken:  os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"),
+		httpClient:   httpClient,
+	}
+	if p.endpoint == "" {
+		p.endpoint = defaultGCPSecretManagerEndpoint
+	}
+	if p.metadataHost == "" {
+		p.metadataHost = defaultGCPMetadataHost
+	}
+	return p, nil
+}
+
+func (p *gcpSecretManagerProvider) GetKey(secretID, secretKey string) (string, error) {
+	if v, ok := getCachedKey(secretProviderGCPSecretManagerType, secretID, secretKey); ok {
+		return v, nil
+	}
+	resourceName, err := p.resourceName(secretID)
+	if err != nil {
+		return "", err
+	}
+	accessToken, err := p.getAccessToken()
+	if err != nil {
+		return "", fmt.Errorf("(%v) failed obtaining gcp access token, reason=%v", secretID, err)
+	}
+	ctx, cancelFn := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancelFn()
+	apiURL := fmt.Sprintf("%s/v1/%s:access", p.endpoint, resourceName)
+	log.With("secretid", secretID).Debugf("fetching gcp secret at %v", apiURL)
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed creating http request, err=%v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	var resp gcpAccessSecretVersionResponse
+	if err := doJSONRequest(p.httpClient, req, &resp); err != nil {
+		return "", fmt.Errorf("(%v) %v", secretID, err)
+	}
+	payload, err := base64.StdEncoding.DecodeString(resp.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("(%v) failed decoding secret payload, reason=%v", secretID, err)
+	}
+	secretData, err := decodeJSONSecret(payload)
+	if err != nil {
+		return "", fmt.Errorf("(%v) failed decoding secret payload to json, reason=%v", secretID, err)
+	}
+	if v, ok := secretData[secretKey]; ok {
+		secretsCache.set(secretProviderGCPSecretManagerType, secretID, secretData, nil)
+		return v, nil
+	}
+	return "", fmt.Errorf("secret id %s found, but key %s was not", secretID, secretKey)
+}
+
+// resourceName returns the resource name of the secret version
+func (p *gcpSecretManagerProvider) resourceName(secretID string) (string, error) {
+	name := strings.Trim(secretID, "/")
+	if !strings.HasPrefix(name, "projects/") {
+		if p.projectID == "" {
+			return "", fmt.Errorf("(%v) GOOGLE_CLOUD_PROJECT env not set, use the full resource name of the secret", secretID)
+		}
+		name = fmt.Sprintf("projects/%s/secrets/%s", p.projectID, name)
+	}
+	if !strings.Contains(name, "/versions/") {
+		name += "/versions/latest"
+	}
+	return name, nil
+}
+
+func (p *gcpSecretManagerProvider) getAccessToken() (string, error) {
+	if p.staticToken != "" {
+		return p.staticToken, nil
+	}
+	return p.tokenCache.get(func() (string, time.Duration, error) {
+		ctx, cancelFn := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancelFn()
+		tokenURL := fmt.Sprintf("http://%s/computeMetadata/v1/instance/service-accounts/default/token", p.metadataHost)
+		req, err := http.NewRequestWithContext(ctx, "GET", tokenURL, nil)
+		if err != nil {
+			return "", 0, fmt.Errorf("failed creating http request, err=%v", err)
+		}
+		req.Header.Set("Metadata-Flavor", "Google")
+		var resp oauthTokenResponse
+		if err := doJSONRequest(p.httpClient, req, &resp); err != nil {
+			return "", 0, err
+		}
+		return resp.AccessToken, resp.expiresIn(), nil
+	})
+}
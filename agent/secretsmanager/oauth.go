@@ -0,0 +1,64 @@
+package secretsmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hoophq/hoop/common/httpclient"
+)
+
+// accessTokenCache keeps an oauth access token until it's about to expire
+type accessTokenCache struct {
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// get returns the cached token or obtains a new one with the fetch function
+func (c *accessTokenCache) get(fetch func() (string, time.Duration, error)) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// renew it ahead of time to avoid using an expired token in flight
+	if c.token != "" && time.Now().Add(time.Minute).Before(c.expiresAt) {
+		return c.token, nil
+	}
+	token, expiresIn, err := fetch()
+	if err != nil {
+		return "", err
+	}
+	c.token, c.expiresAt = token, time.Now().Add(expiresIn)
+	return token, nil
+}
+
+type oauthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	// some providers returns it as a string
+	ExpiresIn json.Number `json:"expires_in"`
+}
+
+func (r *oauthTokenResponse) expiresIn() time.Duration {
+	sec, _ := strconv.ParseInt(r.ExpiresIn.String(), 10, 64)
+	return time.Duration(sec) * time.Second
+}
+
+// doJSONRequest performs the http request decoding a successful response into v
+func doJSONRequest(client httpclient.HttpClient, req *http.Request, v any) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed performing request, status=%v, body=%v", resp.StatusCode, string(respBody))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed decoding response, status=%v, reason=%v", resp.StatusCode, err)
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package secretsmanager
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newGCPTestServer(t *testing.T, secrets map[string]string) (*httptest.Server, *int) {
+	var tokenRequests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/computeMetadata/v1/instance/service-accounts/default/token" {
+			if r.Header.Get("Metadata-Flavor") != "Google" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			tokenRequests++
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "gcp-token", "expires_in": 3599})
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer gcp-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error": {"code": 401, "status": "UNAUTHENTICATED"}}`))
+			return
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/"), ":access")
+		secret, ok := secrets[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": {"code": 404, "status": "NOT_FOUND"}}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"name":    name,
+			"payload": map[string]any{"data": base64.StdEncoding.EncodeToString([]byte(secret))},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &tokenRequests
+}
+
+func TestGCPSecretManagerProviderGetKey(t *testing.T) {
+	srv, tokenRequests := newGCPTestServer(t, map[string]string{
+		"projects/myproject/secrets/pgprod/versions/latest": `{"PASS": "secret"}`,
+		"projects/other/secrets/mysql/versions/2":           `{"USER": "root"}`,
+	})
+	t.Setenv("GCP_SECRET_MANAGER_ENDPOINT", srv.URL)
+	t.Setenv("GCE_METADATA_HOST", strings.TrimPrefix(srv.URL, "http://"))
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "myproject")
+	t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "")
+
+	for _, tt := range []struct {
+		msg       string
+		secretID  string
+		secretKey string
+		want      string
+		err       string
+	}{
+		{msg: "it should fetch the latest version of the secret", secretID: "pgprod", secretKey: "PASS", want: "secret"},
+		{msg: "it should fetch the secret by its resource name", secretID: "projects/other/secrets/mysql/versions/2",
+			secretKey: "USER", want: "root"},
+		{msg: "it should return error when the key does not exist", secretID: "pgprod", secretKey: "USER",
+			err: "secret id pgprod found, but key USER was not"},
+		{msg: "it should return error when the secret does not exist", secretID: "unknown", secretKey: "PASS",
+			err: `(unknown) failed performing request, status=404, body={"error": {"code": 404, "status": "NOT_FOUND"}}`},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			secretsCache.flush()
+			prov, err := newGCPSecretManagerProvider(nil)
+			assert.Nil(t, err)
+			got, err := prov.GetKey(tt.secretID, tt.secretKey)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("it should reuse the access token of the metadata server", func(t *testing.T) {
+		secretsCache.flush()
+		*tokenRequests = 0
+		prov, err := newGCPSecretManagerProvider(nil)
+		assert.Nil(t, err)
+		for _, secretID := range []string{"pgprod", "projects/other/secrets/mysql/versions/2"} {
+			_, _ = prov.GetKey(secretID, "PASS")
+		}
+		assert.Equal(t, 1, *tokenRequests)
+	})
+
+	t.Run("it should use the access token from env", func(t *testing.T) {
+		secretsCache.flush()
+		t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "invalid-token")
+		prov, err := newGCPSecretManagerProvider(nil)
+		assert.Nil(t, err)
+		_, err = prov.GetKey("pgprod", "PASS")
+		assert.EqualError(t, err, `(pgprod) failed performing request, status=401, body={"error": {"code": 401, "status": "UNAUTHENTICATED"}}`)
+	})
+}
+
+func newAzureTestServer(t *testing.T, secrets map[string]string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/mytenant/oauth2/v2.0/token":
+			_ = r.ParseForm()
+			if r.Form.Get("client_secret") != "client-secret" || r.Form.Get("scope") != "https://vault.azure.net/.default" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "azure-token", "expires_in": 3599})
+			return
+		case r.URL.Path == "/metadata/identity/oauth2/token":
+			if r.Header.Get("Metadata") != "true" || r.URL.Query().Get("resource") != "https://vault.azure.net" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			// the managed identity endpoint returns the expiration as string
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "azure-token", "expires_in": "3599"})
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer azure-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		secret, ok := secrets[strings.TrimPrefix(r.URL.Path, "/secrets/")]
+		if !ok || r.URL.Query().Get("api-version") == "" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": {"code": "SecretNotFound"}}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": r.URL.Path, "value": secret})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestAzureKeyVaultProviderGetKey(t *testing.T) {
+	srv := newAzureTestServer(t, map[string]string{
+		"pgprod":      `{"PASS": "secret"}`,
+		"pgprod/v123": `{"PASS": "old-secret"}`,
+		"plain":       `not-json`,
+	})
+	t.Setenv("AZURE_KEYVAULT_URL", srv.URL)
+	t.Setenv("AZURE_AUTHORITY_HOST", srv.URL)
+	t.Setenv("AZURE_IMDS_ENDPOINT", srv.URL)
+
+	for _, tt := range []struct {
+		msg          string
+		clientSecret string
+		secretID     string
+		secretKey    string
+		want         string
+		err          string
+	}{
+		{msg: "it should fetch the secret with a managed identity", secretID: "pgprod", secretKey: "PASS", want: "secret"},
+		{msg: "it should fetch the secret with a service principal", clientSecret: "client-secret",
+			secretID: "pgprod", secretKey: "PASS", want: "secret"},
+		{msg: "it should fetch a version of the secret", secretID: "pgprod/v123", secretKey: "PASS", want: "old-secret"},
+		{msg: "it should return error with invalid credentials", clientSecret: "invalid", secretID: "pgprod", secretKey: "PASS",
+			err: "(pgprod) failed obtaining azure access token, reason=failed performing request, status=401, body="},
+		{msg: "it should return error when the secret is not a json object", secretID: "plain", secretKey: "PASS",
+			err: "(plain) failed decoding secret value to json, reason=invalid character 'o' in literal null (expecting 'u')"},
+		{msg: "it should return error when the secret does not exist", secretID: "unknown", secretKey: "PASS",
+			err: `(unknown) failed performing request, status=404, body={"error": {"code": "SecretNotFound"}}`},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			secretsCache.flush()
+			t.Setenv("AZURE_TENANT_ID", "mytenant")
+			t.Setenv("AZURE_CLIENT_ID", "myclient")
+			t.Setenv("AZURE_CLIENT_SECRET", tt.clientSecret)
+			prov, err := newAzureKeyVaultProvider(nil)
+			assert.Nil(t, err)
+			got, err := prov.GetKey(tt.secretID, tt.secretKey)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("it should return error when the vault url is not set", func(t *testing.T) {
+		t.Setenv("AZURE_KEYVAULT_URL", "")
+		_, err := newAzureKeyVaultProvider(nil)
+		assert.EqualError(t, err, "AZURE_KEYVAULT_URL env not set")
+	})
+}
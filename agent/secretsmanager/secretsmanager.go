@@ -2,14 +2,28 @@ package secretsmanager
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 )
 
-type secretsGetter interface {
+// SecretsGetter obtains the value of a key from a secret stored in an external provider
+type SecretsGetter interface {
 	GetKey(secretID, secretKey string) (string, error)
 }
 
+// SessionSecretsGetter obtains secrets issued for a single session, e.g.: dynamic
+// database credentials. They are not cached and are revoked when the session ends.
+type SessionSecretsGetter interface {
+	GetSessionKey(sessionID, secretID, secretKey string) (string, error)
+	RevokeSession(sessionID string) error
+}
+
+// ProviderFactory initializes a secrets provider, it's called once
+// for each provider used and the instance is kept for the lifetime of the agent
+type ProviderFactory func() (SecretsGetter, error)
+
 type secretProviderType string
 
 const (
@@ -21,19 +35,77 @@ const (
 	secretProviderVaultKv1Type secretProviderType = "_vaultkv1"
 	// fetches secrets from vault k/v store version 2
 	secretProviderVaultKv2Type secretProviderType = "_vaultkv2"
+	// issues dynamic credentials from vault database secrets engine
+	secretProviderVaultDBType secretProviderType = "_vaultdb"
+	// fetches secrets from files of a mounted secrets directory
+	secretProviderFileType secretProviderType = "_file"
+	// fetches secrets from the json output of a helper command
+	secretProviderExecType secretProviderType = "_exec"
+	// fetches secrets from gcp secret manager
+	secretProviderGCPSecretManagerType secretProviderType = "_gcpsm"
+	// fetches secrets from azure key vault
+	secretProviderAzureKeyVaultType secretProviderType = "_azurekv"
 )
 
+var (
+	registryMutex sync.RWMutex
+	registry      = map[secretProviderType]ProviderFactory{}
+
+	providersMutex sync.Mutex
+	providers      = map[secretProviderType]SecretsGetter{}
+)
+
+// Register makes a secrets provider available by its name, e.g.: _aws.
+// Values in the form of <name>:<secret-id>:<secret-key> are decoded by this provider.
+// It panics if a provider with the same name is already registered.
+func Register(name string, factory ProviderFactory) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	if !strings.HasPrefix(name, "_") {
+		panic(fmt.Sprintf("secretsmanager: provider name %q must start with an underscore", name))
+	}
+	if _, ok := registry[secretProviderType(name)]; ok {
+		panic(fmt.Sprintf("secretsmanager: provider %q already registered", name))
+	}
+	registry[secretProviderType(name)] = factory
+}
+
+func getProviderFactory(provider secretProviderType) ProviderFactory {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	return registry[provider]
+}
+
+// getProvider returns the instance of a registered provider, initializing it in the first use.
+// It returns a nil provider if it's not registered.
+func getProvider(providerType secretProviderType) (SecretsGetter, error) {
+	factory := getProviderFactory(providerType)
+	if factory == nil {
+		return nil, nil
+	}
+	providersMutex.Lock()
+	defer providersMutex.Unlock()
+	if provider, ok := providers[providerType]; ok {
+		return provider, nil
+	}
+	provider, err := factory()
+	if err != nil {
+		return nil, fmt.Errorf("failed initializing %v provider, err=%v",
+			strings.TrimPrefix(string(providerType), "_"), err)
+	}
+	providers[providerType] = provider
+	return provider, nil
+}
+
 // Decode environment variables based on the provider of a certain env.
 // When a value contains a _<provider>:<secret-id>:<secret-key> it will load
-// the value from an external source. If the provider isn't implemented then
+// the value from an external source. If the provider isn't registered then
 // it will be a noop.
-func Decode(envVars map[string]any) (map[string]any, error) {
-	providerSingleton := map[secretProviderType]secretsGetter{
-		secretProviderAWSSecretsManagerType: nil,
-		secretProviderEnvJSONType:           nil,
-		secretProviderVaultKv1Type:          nil,
-		secretProviderVaultKv2Type:          nil,
-	}
+//
+// The secrets are cached among sessions, see the HOOP_SECRETS_CACHE_TTL env.
+// Providers issuing secrets per session (SessionSecretsGetter) are bound to the sessionID,
+// they must be released with Release when the session ends.
+func Decode(sessionID string, envVars map[string]any) (map[string]any, error) {
 	decodedEnvVars := map[string]any{}
 	var errors []string
 	for envKey, encEnvVal := range envVars {
@@ -47,41 +119,22 @@ func Decode(envVars map[string]any) (map[string]any, error) {
 			decodedEnvVars[envKey] = encEnvVal
 			continue
 		}
-		var provider secretsGetter
-		switch attr.provider {
-		case secretProviderAWSSecretsManagerType:
-			provider = providerSingleton[secretProviderAWSSecretsManagerType]
-			if provider == nil {
-				awsProv, err := newAwsProvider()
-				if err != nil {
-					return nil, fmt.Errorf("failed initializing aws provider, err=%v", err)
-				}
-				providerSingleton[secretProviderAWSSecretsManagerType] = awsProv
-				provider = awsProv
-			}
-		case secretProviderEnvJSONType:
-			provider = providerSingleton[secretProviderEnvJSONType]
-			if provider == nil {
-				envJsonProv := &envJsonProvider{}
-				providerSingleton[secretProviderEnvJSONType] = envJsonProv
-				provider = envJsonProv
-			}
-		case secretProviderVaultKv1Type, secretProviderVaultKv2Type:
-			provider = providerSingleton[attr.provider]
-			if provider == nil {
-				vaultProvider, err := newVaultKeyValProvider(attr.provider, nil)
-				if err != nil {
-					return nil, fmt.Errorf("failed initializing vault provider, err=%v", err)
-				}
-				providerSingleton[attr.provider] = vaultProvider
-				provider = vaultProvider
-			}
-		default:
+		provider, err := getProvider(attr.provider)
+		if err != nil {
+			_ = Release(sessionID)
+			return nil, err
+		}
+		if provider == nil {
 			// it's not an secrets manager env definition
 			decodedEnvVars[envKey] = encEnvVal
 			continue
 		}
-		val, err := provider.GetKey(attr.secretID, attr.secretKey)
+		var val string
+		if sessionProvider, ok := provider.(SessionSecretsGetter); ok {
+			val, err = sessionProvider.GetSessionKey(sessionID, attr.secretID, attr.secretKey)
+		} else {
+			val, err = provider.GetKey(attr.secretID, attr.secretKey)
+		}
 		if err != nil {
 			errors = append(errors, fmt.Sprintf("%s %v", envKey, err))
 			continue
@@ -89,11 +142,34 @@ func Decode(envVars map[string]any) (map[string]any, error) {
 		decodedEnvVars[envKey] = base64.StdEncoding.EncodeToString([]byte(val))
 	}
 	if len(errors) > 0 {
+		_ = Release(sessionID)
 		return nil, fmt.Errorf("%q", errors)
 	}
 	return decodedEnvVars, nil
 }
 
+// Release revokes the secrets issued for the session by the initialized providers
+func Release(sessionID string) error {
+	providersMutex.Lock()
+	var sessionProviders []SessionSecretsGetter
+	for _, provider := range providers {
+		if p, ok := provider.(SessionSecretsGetter); ok {
+			sessionProviders = append(sessionProviders, p)
+		}
+	}
+	providersMutex.Unlock()
+	var errors []string
+	for _, p := range sessionProviders {
+		if err := p.RevokeSession(sessionID); err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+	if len(errors) > 0 {
+		return fmt.Errorf("%q", errors)
+	}
+	return nil
+}
+
 type envValAttribute struct {
 	provider  secretProviderType
 	secretID  string
@@ -113,3 +189,17 @@ func decodeVal(encEnvVal any) (*envValAttribute, error) {
 	secretProvider, secretID, secretKey := secretProviderType(parts[0]), parts[1], parts[2]
 	return &envValAttribute{secretProvider, secretID, secretKey}, nil
 }
+
+// decodeJSONSecret decodes a secret stored as a json object
+// converting its values to string
+func decodeJSONSecret(data []byte) (map[string]string, error) {
+	var keyVal map[string]any
+	if err := json.Unmarshal(data, &keyVal); err != nil {
+		return nil, err
+	}
+	secretData := map[string]string{}
+	for key, val := range keyVal {
+		secretData[key] = fmt.Sprintf("%v", val)
+	}
+	return secretData, nil
+}
@@ -0,0 +1,138 @@
+package secretsmanager
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeProvider map[string]string
+
+func (p fakeProvider) GetKey(secretID, secretKey string) (string, error) {
+	if v, ok := p[secretID+":"+secretKey]; ok {
+		return v, nil
+	}
+	return "", fmt.Errorf("secret key %q not found", secretKey)
+}
+
+func encodeVal(v string) string { return base64.StdEncoding.EncodeToString([]byte(v)) }
+
+func TestDecodeWithRegisteredProvider(t *testing.T) {
+	var initCount int
+	Register("_fake", func() (SecretsGetter, error) {
+		initCount++
+		return fakeProvider{"db:PASS": "secret", "db:USER": "admin"}, nil
+	})
+	Register("_fakeerr", func() (SecretsGetter, error) { return nil, fmt.Errorf("missing config") })
+
+	got, err := Decode("", map[string]any{
+		"envvar:PASS":  encodeVal("_fake:db:PASS"),
+		"envvar:USER":  encodeVal("_fake:db:USER"),
+		"envvar:PLAIN": encodeVal("plain-value"),
+		"envvar:OTHER": encodeVal("_unknown:db:PASS"),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]any{
+		"envvar:PASS":  encodeVal("secret"),
+		"envvar:USER":  encodeVal("admin"),
+		"envvar:PLAIN": encodeVal("plain-value"),
+		"envvar:OTHER": encodeVal("_unknown:db:PASS"),
+	}, got)
+
+	_, err = Decode("", map[string]any{"envvar:PASS": encodeVal("_fake:db:NOKEY")})
+	assert.EqualError(t, err, `["envvar:PASS secret key \"NOKEY\" not found"]`)
+	assert.Equal(t, 1, initCount, "it should initialize the provider once among calls")
+
+	_, err = Decode("", map[string]any{"envvar:PASS": encodeVal("_fakeerr:db:PASS")})
+	assert.EqualError(t, err, "failed initializing fakeerr provider, err=missing config")
+
+	assert.Panics(t, func() { Register("_fake", nil) }, "it should not allow registering a provider twice")
+	assert.Panics(t, func() { Register("fake", nil) }, "it should not allow names without the underscore prefix")
+}
+
+func TestFileProviderGetKey(t *testing.T) {
+	baseDir := t.TempDir()
+	assert.Nil(t, os.MkdirAll(filepath.Join(baseDir, "pgprod"), 0700))
+	assert.Nil(t, os.WriteFile(filepath.Join(baseDir, "pgprod", "PASS"), []byte("secret\n"), 0600))
+	assert.Nil(t, os.WriteFile(filepath.Join(baseDir, "mysql.json"), []byte(`{"USER": "root", "PORT": 3306}`), 0600))
+	t.Setenv("HOOP_SECRETS_DIR", baseDir)
+
+	for _, tt := range []struct {
+		msg       string
+		secretID  string
+		secretKey string
+		want      string
+		err       string
+	}{
+		{msg: "it should read the key file of a directory", secretID: "pgprod", secretKey: "PASS", want: "secret"},
+		{msg: "it should read the key of a json file", secretID: "mysql.json", secretKey: "PORT", want: "3306"},
+		{msg: "it should return error when the key does not exist", secretID: "mysql.json", secretKey: "PASS",
+			err: "secret id mysql.json found, but key PASS was not"},
+		{msg: "it should not read files outside of the secrets directory", secretID: "pgprod", secretKey: "../../../etc/passwd",
+			err: `(pgprod) open ` + filepath.Join(baseDir, "etc/passwd") + `: no such file or directory`},
+		{msg: "it should not allow the secrets directory as secret", secretID: "../", secretKey: "PASS",
+			err: `secret "../" is not a valid path in the secrets directory`},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			secretsCache.flush()
+			prov, err := newFileProvider()
+			assert.Nil(t, err)
+			got, err := prov.GetKey(tt.secretID, tt.secretKey)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExecProviderGetKey(t *testing.T) {
+	helperPath := filepath.Join(t.TempDir(), "helper.sh")
+	helperScript := `#!/bin/sh
+case "$2" in
+  pgprod) echo '{"PASS": "secret", "ENV": "'$1'"}' ;;
+  invalid) echo 'not-json' ;;
+  *) echo "secret $2 not found" >&2; exit 1 ;;
+esac
+`
+	assert.Nil(t, os.WriteFile(helperPath, []byte(helperScript), 0700))
+	t.Setenv("HOOP_SECRETS_EXEC_COMMAND", fmt.Sprintf(`[%q, "production"]`, helperPath))
+
+	for _, tt := range []struct {
+		msg       string
+		secretID  string
+		secretKey string
+		want      string
+		err       string
+	}{
+		{msg: "it should read the key from the json output", secretID: "pgprod", secretKey: "PASS", want: "secret"},
+		{msg: "it should pass the configured arguments", secretID: "pgprod", secretKey: "ENV", want: "production"},
+		{msg: "it should return error with invalid output", secretID: "invalid", secretKey: "PASS",
+			err: "(invalid) failed decoding output of secrets helper command to json, err=invalid character 'o' in literal null (expecting 'u')"},
+		{msg: "it should return the stderr when the command fails", secretID: "unknown", secretKey: "PASS",
+			err: "(unknown) failed executing secrets helper command, reason=exit status 1, stderr=secret unknown not found\n"},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			secretsCache.flush()
+			prov, err := newExecProvider()
+			assert.Nil(t, err)
+			got, err := prov.GetKey(tt.secretID, tt.secretKey)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Setenv("HOOP_SECRETS_EXEC_COMMAND", "")
+	_, err := newExecProvider()
+	assert.EqualError(t, err, "HOOP_SECRETS_EXEC_COMMAND env not set")
+}
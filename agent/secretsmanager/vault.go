@@ -14,14 +14,18 @@ import (
 	"github.com/hoophq/hoop/common/envloader"
 	"github.com/hoophq/hoop/common/httpclient"
 	"github.com/hoophq/hoop/common/log"
-	"github.com/hoophq/hoop/common/memory"
 )
 
 const defaultKV2Path string = "secret/data/"
 
+func init() {
+	for _, kvType := range []secretProviderType{secretProviderVaultKv1Type, secretProviderVaultKv2Type} {
+		Register(string(kvType), func() (SecretsGetter, error) { return newVaultKeyValProvider(kvType, nil) })
+	}
+}
+
 type vaultProvider struct {
 	config     *vaultConfig
-	cache      memory.Store
 	kvType     secretProviderType
 	httpClient httpclient.HttpClient
 }
@@ -65,6 +69,7 @@ type KeyValV2 struct {
 
 type KVGetter interface {
 	GetData() map[string]string
+	GetMeta() KeyValMeta
 }
 
 func NewVaultProvider() (*vaultProvider, error) {
@@ -79,7 +84,7 @@ func newVaultKeyValProvider(kvType secretProviderType, httpClient httpclient.Htt
 	if httpClient == nil {
 		httpClient = httpclient.NewHttpClient(config.tlsCA)
 	}
-	return &vaultProvider{config: config, cache: memory.New(), kvType: kvType, httpClient: httpClient}, nil
+	return &vaultProvider{config: config, kvType: kvType, httpClient: httpClient}, nil
 }
 
 func loadAppRoleCredentials() (string, string, error) {
@@ -159,13 +164,8 @@ func (p *vaultProvider) SetValue(secretID string, secretsPayload map[string]stri
 }
 
 func (p *vaultProvider) GetKey(secretID, secretKey string) (string, error) {
-	if obj := p.cache.Get(secretID); obj != nil {
-		if keyVal, ok := obj.(map[string]string); ok {
-			if v, ok := keyVal[secretKey]; ok {
-				return fmt.Sprintf("%v", v), nil
-			}
-			return "", fmt.Errorf("secret key not found. secret=%v, key=%v", secretID, secretKey)
-		}
+	if v, ok := getCachedKey(p.kvType, secretID, secretKey); ok {
+		return v, nil
 	}
 	kv, err := p.keyValGetRequest(secretID)
 	if err != nil {
@@ -174,13 +174,67 @@ func (p *vaultProvider) GetKey(secretID, secretKey string) (string, error) {
 	log.Infof("vault decoded response: %s", kv)
 	if data := kv.GetData(); data != nil {
 		if v, ok := data[secretKey]; ok {
-			p.cache.Set(secretID, data)
+			secretsCache.set(p.kvType, secretID, data, p.newLease(kv.GetMeta()))
 			return v, nil
 		}
 	}
 	return "", fmt.Errorf("secret id %s found, but key %s was not", secretID, secretKey)
 }
 
+// newLease returns the lease of a secret, renewable leases are renewed
+// by the agent while the secret is kept in the cache
+func (p *vaultProvider) newLease(meta KeyValMeta) *secretLease {
+	if meta.LeaseDuration <= 0 {
+		return nil
+	}
+	return &secretLease{
+		id:        meta.LeaseID,
+		duration:  time.Duration(meta.LeaseDuration) * time.Second,
+		renewable: meta.Renewable && meta.LeaseID != "",
+		renew:     p.renewLease,
+	}
+}
+
+// renewLease renews a lease returning its new duration
+//
+// https://developer.hashicorp.com/vault/api-docs/system/leases#renew-lease
+func (p *vaultProvider) renewLease(leaseID string) (time.Duration, error) {
+	apiURL := strings.TrimSuffix(p.config.serverAddr, "/") + "/v1/sys/leases/renew"
+	ctx, cancelFn := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancelFn()
+	payload, err := json.Marshal(map[string]string{"lease_id": leaseID})
+	if err != nil {
+		return 0, fmt.Errorf("unable to encode /sys/leases/renew payload, reason=%v", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "PUT", apiURL, bytes.NewBuffer(payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed creating http request, err=%v", err)
+	}
+	vaultToken, err := p.GetVaultToken()
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", vaultToken)
+	req.Header.Set("X-Vault-Request", "true")
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if err := decodeVaultHttpErrorResponseBody(resp); err != nil {
+		return 0, err
+	}
+	var meta KeyValMeta
+	if err := json.NewDecoder(resp.Body).Decode(&meta); err != nil {
+		return 0, fmt.Errorf("failed decoding renew lease response, status=%v, reason=%v", resp.StatusCode, err)
+	}
+	if meta.LeaseDuration <= 0 {
+		return 0, fmt.Errorf("lease %v is not renewable", leaseID)
+	}
+	return time.Duration(meta.LeaseDuration) * time.Second, nil
+}
+
 func (p *vaultProvider) GetVaultToken() (string, error) {
 	if p.config.appRoleID != "" {
 		ctx, cancelFn := context.WithTimeout(context.Background(), time.Second*5)
@@ -269,6 +323,8 @@ func (p *vaultProvider) keyValGetRequest(secretID string) (KVGetter, error) {
 	return nil, fmt.Errorf("unknown secret provider %v", p.kvType)
 }
 
+func (m KeyValMeta) GetMeta() KeyValMeta { return m }
+
 func (k *KeyValV1) GetData() map[string]string { return k.Data }
 func (k *KeyValV1) String() string {
 	return fmt.Sprintf("request_id=%v, lease_id=%v, lease_duration=%v, mount_type=%v, keys=%v",
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/hoophq/hoop/common/log"
 	"github.com/stretchr/testify/assert"
@@ -91,6 +92,7 @@ func TestVaultProviderGetKey(t *testing.T) {
 		},
 	} {
 		t.Run(tt.msg, func(t *testing.T) {
+			secretsCache.flush()
 			prov, err := newVaultKeyValProvider(tt.kvType, tt.fakeHttpClient)
 			if prov == nil {
 				t.Fatalf("did not expected to obtain error obtaining vault provider, reason=%v", err)
@@ -104,7 +106,7 @@ func TestVaultProviderGetKey(t *testing.T) {
 				assert.Nil(t, err)
 				assert.Equal(t, expectedVal, gotVal, "must match with keys from server")
 			}
-			if !prov.cache.Has(tt.secretID) && tt.err == nil {
+			if _, ok := secretsCache.get(tt.kvType, tt.secretID); !ok && tt.err == nil {
 				t.Errorf("secret key %q not found. Expect to cache secret id from server in memory", tt.secretID)
 			}
 		})
@@ -149,3 +151,96 @@ func TestUrlPathForProvider(t *testing.T) {
 		})
 	}
 }
+
+func TestVaultProviderRenewLease(t *testing.T) {
+	t.Setenv("VAULT_TOKEN", "noop")
+	t.Setenv("VAULT_ADDR", "http://127.0.0.1:8200")
+	t.Setenv("HOOP_SECRETS_CACHE_TTL", "10m")
+	secretsCache.flush()
+	var fetchCount, renewCount int
+	var renewLeaseID string
+	fakeClient := clientFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Method == "PUT" && req.URL.Path == "/v1/sys/leases/renew" {
+			renewCount++
+			var payload map[string]string
+			_ = json.NewDecoder(req.Body).Decode(&payload)
+			renewLeaseID = payload["lease_id"]
+			return createTestServer(KeyValMeta{LeaseID: renewLeaseID, LeaseDuration: 3600, Renewable: true}, nil).Do(req)
+		}
+		fetchCount++
+		return createTestServer(KeyValV1{
+			KeyValMeta: KeyValMeta{LeaseID: "kv/mysecret/abc", LeaseDuration: 60, Renewable: true},
+			Data:       map[string]string{"PASS": "dbsecret"},
+		}, nil).Do(req)
+	})
+	prov, err := newVaultKeyValProvider(secretProviderVaultKv1Type, fakeClient)
+	assert.Nil(t, err)
+
+	for i := 0; i < 2; i++ {
+		got, err := prov.GetKey("kv/mysecret", "PASS")
+		assert.Nil(t, err)
+		assert.Equal(t, "dbsecret", got)
+	}
+	assert.Equal(t, 1, fetchCount, "it should fetch the secret once")
+	assert.Equal(t, 0, renewCount)
+
+	// expire two thirds of the lease
+	item := secretsCache.items[cacheKey(secretProviderVaultKv1Type, "kv/mysecret")]
+	item.lease.expiresAt = item.lease.expiresAt.Add(-time.Second * 40)
+	got, err := prov.GetKey("kv/mysecret", "PASS")
+	assert.Nil(t, err)
+	assert.Equal(t, "dbsecret", got)
+	assert.Equal(t, 1, fetchCount)
+	assert.Equal(t, 1, renewCount, "it should renew the lease")
+	assert.Equal(t, "kv/mysecret/abc", renewLeaseID)
+	assert.Equal(t, time.Hour, item.lease.duration)
+}
+
+func TestVaultDatabaseProviderSessionCredentials(t *testing.T) {
+	t.Setenv("VAULT_TOKEN", "noop")
+	t.Setenv("VAULT_ADDR", "http://127.0.0.1:8200")
+	var issuedPaths, revokedLeases []string
+	fakeClient := clientFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Method == "PUT" && req.URL.Path == "/v1/sys/leases/revoke" {
+			var payload map[string]string
+			_ = json.NewDecoder(req.Body).Decode(&payload)
+			revokedLeases = append(revokedLeases, payload["lease_id"])
+			return &http.Response{StatusCode: http.StatusNoContent, Body: io.NopCloser(bytes.NewBuffer(nil))}, nil
+		}
+		issuedPaths = append(issuedPaths, req.URL.Path)
+		n := len(issuedPaths)
+		return createTestServer(KeyValV1{
+			KeyValMeta: KeyValMeta{LeaseID: fmt.Sprintf("database/creds/readonly/%v", n), LeaseDuration: 3600},
+			Data:       map[string]string{"username": fmt.Sprintf("v-hoop-%v", n), "password": "dbsecret"},
+		}, nil).Do(req)
+	})
+	prov, err := newVaultDatabaseProvider(fakeClient)
+	assert.Nil(t, err)
+
+	getKeysFn := func(sessionID, secretID string) []string {
+		var values []string
+		for _, secretKey := range []string{"username", "password"} {
+			v, err := prov.GetSessionKey(sessionID, secretID, secretKey)
+			assert.Nil(t, err)
+			values = append(values, v)
+		}
+		return values
+	}
+	assert.Equal(t, []string{"v-hoop-1", "dbsecret"}, getKeysFn("session-1", "readonly"))
+	assert.Equal(t, []string{"v-hoop-2", "dbsecret"}, getKeysFn("session-2", "dbs/creds/readonly"))
+	assert.Equal(t, []string{"/v1/database/creds/readonly", "/v1/dbs/creds/readonly"}, issuedPaths,
+		"it should issue a credential per session")
+
+	_, err = prov.GetSessionKey("session-1", "readonly", "USER")
+	assert.EqualError(t, err, "secret id readonly found, but key USER was not")
+	_, err = prov.GetKey("readonly", "username")
+	assert.EqualError(t, err, "(readonly) vault database credentials are issued per session")
+
+	assert.Nil(t, prov.RevokeSession("session-1"))
+	assert.Equal(t, []string{"database/creds/readonly/1"}, revokedLeases)
+	assert.Nil(t, prov.RevokeSession("session-1"), "it should be a noop for released sessions")
+	assert.Equal(t, []string{"database/creds/readonly/1"}, revokedLeases)
+
+	assert.Equal(t, []string{"v-hoop-3", "dbsecret"}, getKeysFn("session-1", "readonly"),
+		"it should issue a new credential after revoking the session")
+}
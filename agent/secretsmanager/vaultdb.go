@@ -0,0 +1,203 @@
+package secretsmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hoophq/hoop/common/httpclient"
+	"github.com/hoophq/hoop/common/log"
+)
+
+const defaultDatabaseMountPath string = "database"
+
+func init() {
+	Register(string(secretProviderVaultDBType), func() (SecretsGetter, error) { return newVaultDatabaseProvider(nil) })
+}
+
+// vaultDatabaseProvider issues dynamic credentials from the vault database secrets engine.
+// Each session obtains its own credentials, they are renewed while the session is open
+// and revoked when it ends.
+//
+// _vaultdb:<role>:<username|password> - issue credentials at database/creds/<role>
+//
+// _vaultdb:<mount>/creds/<role>:<username|password> - issue credentials using a custom mount point
+//
+// https://developer.hashicorp.com/vault/api-docs/secret/databases#generate-credentials
+type vaultDatabaseProvider struct {
+	*vaultProvider
+
+	mu sync.Mutex
+	// session id -> secret id -> credential
+	sessions map[string]map[string]*vaultDBCredential
+}
+
+type vaultDBCredential struct {
+	leaseID  string
+	data     map[string]string
+	cancelFn context.CancelFunc
+}
+
+func newVaultDatabaseProvider(httpClient httpclient.HttpClient) (*vaultDatabaseProvider, error) {
+	p, err := newVaultKeyValProvider(secretProviderVaultDBType, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	return &vaultDatabaseProvider{vaultProvider: p, sessions: map[string]map[string]*vaultDBCredential{}}, nil
+}
+
+func (p *vaultDatabaseProvider) GetKey(secretID, _ string) (string, error) {
+	return "", fmt.Errorf("(%v) vault database credentials are issued per session", secretID)
+}
+
+// GetSessionKey issues the credential in the first use, the next keys
+// of the same secret are obtained from the same credential in the session
+func (p *vaultDatabaseProvider) GetSessionKey(sessionID, secretID, secretKey string) (string, error) {
+	if sessionID == "" {
+		return "", fmt.Errorf("(%v) vault database credentials are issued per session", secretID)
+	}
+	p.mu.Lock()
+	cred := p.sessions[sessionID][secretID]
+	p.mu.Unlock()
+	if cred == nil {
+		var err error
+		cred, err = p.issueCredential(sessionID, secretID)
+		if err != nil {
+			return "", fmt.Errorf("(%v) %v", secretID, err)
+		}
+		p.mu.Lock()
+		if _, ok := p.sessions[sessionID]; !ok {
+			p.sessions[sessionID] = map[string]*vaultDBCredential{}
+		}
+		p.sessions[sessionID][secretID] = cred
+		p.mu.Unlock()
+	}
+	if v, ok := cred.data[secretKey]; ok {
+		return v, nil
+	}
+	return "", fmt.Errorf("secret id %s found, but key %s was not", secretID, secretKey)
+}
+
+// RevokeSession revokes the leases of all credentials issued for the session
+func (p *vaultDatabaseProvider) RevokeSession(sessionID string) error {
+	p.mu.Lock()
+	creds := p.sessions[sessionID]
+	delete(p.sessions, sessionID)
+	p.mu.Unlock()
+	var errors []string
+	for secretID, cred := range creds {
+		cred.cancelFn()
+		if err := p.revokeLease(cred.leaseID); err != nil {
+			errors = append(errors, fmt.Sprintf("(%v) failed revoking lease %v, reason=%v", secretID, cred.leaseID, err))
+			continue
+		}
+		log.With("sid", sessionID, "secretid", secretID).Infof("lease %v revoked with success", cred.leaseID)
+	}
+	if len(errors) > 0 {
+		return fmt.Errorf("%s", strings.Join(errors, "; "))
+	}
+	return nil
+}
+
+func (p *vaultDatabaseProvider) issueCredential(sessionID, secretID string) (*vaultDBCredential, error) {
+	apiURL := databaseCredsURL(p.config.serverAddr, secretID)
+	log.With("sid", sessionID, "secretid", secretID).Infof("issuing database credentials at %v", apiURL)
+	ctx, cancelFn := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancelFn()
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating http request, err=%v", err)
+	}
+	vaultToken, err := p.GetVaultToken()
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", vaultToken)
+	req.Header.Set("X-Vault-Request", "true")
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if err := decodeVaultHttpErrorResponseBody(resp); err != nil {
+		return nil, err
+	}
+	obj := KeyValV1{Data: map[string]string{}}
+	if err := json.NewDecoder(resp.Body).Decode(&obj); err != nil {
+		return nil, fmt.Errorf("failed decoding response, status=%v, length=%v, reason=%v",
+			resp.StatusCode, resp.ContentLength, err)
+	}
+	log.With("sid", sessionID).Infof("database credentials issued with success: %s", obj.String())
+	keepAliveCtx, keepAliveCancelFn := context.WithCancel(context.Background())
+	cred := &vaultDBCredential{leaseID: obj.LeaseID, data: obj.Data, cancelFn: keepAliveCancelFn}
+	if obj.Renewable && obj.LeaseID != "" && obj.LeaseDuration > 0 {
+		go p.keepAlive(keepAliveCtx, sessionID, obj.LeaseID, time.Duration(obj.LeaseDuration)*time.Second)
+	}
+	return cred, nil
+}
+
+// keepAlive renews the lease when a third of its duration remains until the session ends
+// or vault denies the renewal, e.g.: the max ttl of the role has been reached
+func (p *vaultDatabaseProvider) keepAlive(ctx context.Context, sessionID, leaseID string, duration time.Duration) {
+	for {
+		timer := time.NewTimer(duration * 2 / 3)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		var err error
+		duration, err = p.renewLease(leaseID)
+		if err != nil {
+			log.With("sid", sessionID).Warnf("failed renewing lease %v, reason=%v", leaseID, err)
+			return
+		}
+		log.With("sid", sessionID).Infof("lease %v renewed with success, duration=%v", leaseID, duration)
+	}
+}
+
+// https://developer.hashicorp.com/vault/api-docs/system/leases#revoke-lease
+func (p *vaultDatabaseProvider) revokeLease(leaseID string) error {
+	if leaseID == "" {
+		return nil
+	}
+	apiURL := strings.TrimSuffix(p.config.serverAddr, "/") + "/v1/sys/leases/revoke"
+	ctx, cancelFn := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancelFn()
+	payload, err := json.Marshal(map[string]string{"lease_id": leaseID})
+	if err != nil {
+		return fmt.Errorf("unable to encode /sys/leases/revoke payload, reason=%v", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "PUT", apiURL, bytes.NewBuffer(payload))
+	if err != nil {
+		return fmt.Errorf("failed creating http request, err=%v", err)
+	}
+	vaultToken, err := p.GetVaultToken()
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", vaultToken)
+	req.Header.Set("X-Vault-Request", "true")
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return decodeVaultHttpErrorResponseBody(resp)
+}
+
+func databaseCredsURL(serverAddr, secretID string) string {
+	apiURL := strings.TrimSuffix(serverAddr, "/") + "/v1/"
+	secretID = strings.Trim(secretID, "/")
+	if strings.Contains(secretID, "/") {
+		return apiURL + secretID
+	}
+	return apiURL + defaultDatabaseMountPath + "/creds/" + secretID
+}
@@ -21,6 +21,9 @@ func init() {
 	MainCmd.AddCommand(serverInfoCmd)
 	MainCmd.AddCommand(openWebhooksDashboardCmd)
 	MainCmd.AddCommand(licenseCmd)
+	MainCmd.AddCommand(guardRailsCmd)
+	MainCmd.AddCommand(policiesCmd)
+	MainCmd.AddCommand(verifySessionCmd)
 
 	serverInfoCmd.Flags().StringVarP(&outputFlag, "output", "o", "", "Output format. One off: (json)")
 }
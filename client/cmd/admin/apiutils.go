@@ -124,6 +124,22 @@ func parseResourceOrDie(args []string, method, outputFlag string) *apiResource {
 		if method == "POST" {
 			apir.suffixEndpoint = "/api/serviceaccounts"
 		}
+	case "token", "tokens":
+		apir.resourceGet = false
+		apir.resourceCreate = true
+		apir.resourceDelete = true
+		apir.suffixEndpoint = path.Join("/api/tokens", apir.name)
+		if method == "POST" {
+			apir.suffixEndpoint = "/api/tokens"
+		}
+	case "role", "roles":
+		apir.resourceCreate = true
+		apir.resourceUpdate = true
+		apir.resourceDelete = true
+		apir.suffixEndpoint = path.Join("/api/roles", apir.name)
+		if method == "POST" {
+			apir.suffixEndpoint = "/api/roles"
+		}
 	case "review", "reviews":
 		apir.suffixEndpoint = path.Join("/api/reviews", apir.name)
 	case "plugin", "plugins":
@@ -134,12 +150,12 @@ func parseResourceOrDie(args []string, method, outputFlag string) *apiResource {
 			apir.suffixEndpoint = "/api/plugins"
 		}
 	case "policies", "policy":
-		apir.resourceList = true
+		apir.resourceCreate = true
+		apir.resourceUpdate = true
 		apir.resourceDelete = true
-		apir.resourceGet = false
-		apir.suffixEndpoint = "/api/policies"
-		if method == "DELETE" {
-			apir.suffixEndpoint = path.Join("/api/policies", apir.name)
+		apir.suffixEndpoint = path.Join("/api/policies", apir.name)
+		if method == "POST" {
+			apir.suffixEndpoint = "/api/policies"
 		}
 	case "datamasking", "accesscontrol":
 		apir.resourceCreate = true
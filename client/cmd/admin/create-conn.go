@@ -35,7 +35,7 @@ var (
 
 func init() {
 	createConnectionCmd.Flags().StringVarP(&connAgentFlag, "agent", "a", "", "Name of the agent")
-	createConnectionCmd.Flags().StringVarP(&connTypeFlag, "type", "t", "custom", "Type of the connection. One off: (custom, application/[httpproxy|ssh|tcp], database/[mssql|mongodb|mysql|postgres])")
+	createConnectionCmd.Flags().StringVarP(&connTypeFlag, "type", "t", "custom", "Type of the connection. One off: (custom, application/[httpproxy|ssh|tcp], database/[mssql|mongodb|mysql|postgres|redis])")
 	createConnectionCmd.Flags().StringSliceVarP(&connPuginFlag, "plugin", "p", nil, "Plugins that will be enabled for this connection in the form of: <plugin>:<config01>;<config02>,...")
 	createConnectionCmd.Flags().StringSliceVar(&reviewersFlag, "reviewers", nil, "The approval groups for this connection")
 	createConnectionCmd.Flags().StringSliceVar(&connRedactTypesFlag, "redact-types", nil, "The redact types for this connection")
@@ -107,6 +107,10 @@ var createConnectionCmd = &cobra.Command{
 				if err := validateTcpEnvs(envVar); err != nil {
 					styles.PrintErrorAndExit(err.Error())
 				}
+			case pb.ConnectionTypeRedis:
+				if envVar["envvar:HOST"] == "" {
+					styles.PrintErrorAndExit("missing required HOST env for %v", pb.ConnectionTypeRedis)
+				}
 			case pb.ConnectionTypePostgres, pb.ConnectionTypeMySQL, pb.ConnectionTypeMSSQL:
 				if err := validateNativeDbEnvs(envVar); err != nil {
 					styles.PrintErrorAndExit(err.Error())
@@ -0,0 +1,135 @@
+package admin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hoophq/hoop/client/cmd/styles"
+	"github.com/hoophq/hoop/common/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	policyEffectFlag          string
+	policyDescriptionFlag     string
+	policyGroupsFlag          []string
+	policyConnectionTagsFlag  []string
+	policyConnectionTypesFlag []string
+	policyVerbsFlag           []string
+	policyOriginsFlag         []string
+	policySourceIPsFlag       []string
+	policyDaysFlag            []string
+	policyStartTimeFlag       string
+	policyEndTimeFlag         string
+	policyTimezoneFlag        string
+	policyOverwriteFlag       bool
+)
+
+func init() {
+	createPolicyCmd.Flags().StringVar(&policyEffectFlag, "effect", "allow", "The effect of the policy (allow or deny)")
+	createPolicyCmd.Flags().StringVar(&policyDescriptionFlag, "description", "", "The description of the policy")
+	createPolicyCmd.Flags().StringSliceVar(&policyGroupsFlag, "groups", []string{}, "The groups of users the policy applies to, it applies to all users when it's empty")
+	createPolicyCmd.Flags().StringSliceVar(&policyConnectionTagsFlag, "connection-tags", []string{}, "The tags the connection must have, e.g.: env=staging,team=banking")
+	createPolicyCmd.Flags().StringSliceVar(&policyConnectionTypesFlag, "connection-types", []string{}, "The types or subtypes of the connection, e.g.: postgres,mysql")
+	createPolicyCmd.Flags().StringSliceVar(&policyVerbsFlag, "verbs", []string{}, "The verbs of the request (exec, connect)")
+	createPolicyCmd.Flags().StringSliceVar(&policyOriginsFlag, "origins", []string{}, "The client origins of the request (client, client-api, client-api-runbooks, client-proxymanager)")
+	createPolicyCmd.Flags().StringSliceVar(&policySourceIPsFlag, "source-ips", []string{}, "The ip addresses or cidrs of the request, e.g.: 10.0.0.0/8,192.168.0.10")
+	createPolicyCmd.Flags().StringSliceVar(&policyDaysFlag, "days", []string{}, "The days of the week of the time window, e.g.: mon,tue,wed,thu,fri")
+	createPolicyCmd.Flags().StringVar(&policyStartTimeFlag, "start-time", "", "The start of the time window in the format HH:MM, e.g.: 09:00")
+	createPolicyCmd.Flags().StringVar(&policyEndTimeFlag, "end-time", "", "The end of the time window in the format HH:MM, e.g.: 18:00")
+	createPolicyCmd.Flags().StringVar(&policyTimezoneFlag, "timezone", "", "The IANA timezone of the time window, it defaults to UTC")
+	createPolicyCmd.Flags().BoolVar(&policyOverwriteFlag, "overwrite", false, "It will create or update it if a policy already exists")
+}
+
+var createPolicyExamplesDesc = `
+hoop admin create policy contractors-staging --groups contractors --connection-tags env=staging --verbs exec --days mon,tue,wed,thu,fri --start-time 09:00 --end-time 18:00
+hoop admin create policy deny-prod-connect --effect deny --connection-tags env=prod --verbs connect
+hoop admin create policy vpn-only --source-ips 10.0.0.0/8 --overwrite`
+
+var createPolicyCmd = &cobra.Command{
+	Use:     "policy NAME",
+	Aliases: []string{"policies"},
+	Short:   "Create an access policy that allows or denies access to connections.",
+	Example: createPolicyExamplesDesc,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			cmd.Usage()
+			styles.PrintErrorAndExit("missing resource name")
+		}
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		resourceName := args[0]
+		connectionTags := map[string]string{}
+		for _, keyValTag := range policyConnectionTagsFlag {
+			key, val, found := strings.Cut(keyValTag, "=")
+			if !found {
+				styles.PrintErrorAndExit("invalid connection tag %q, it must be in the key=value format", keyValTag)
+			}
+			connectionTags[key] = val
+		}
+		var timeWindow map[string]any
+		if len(policyDaysFlag) > 0 || policyStartTimeFlag != "" || policyEndTimeFlag != "" || policyTimezoneFlag != "" {
+			timeWindow = map[string]any{
+				"days":       policyDaysFlag,
+				"start_time": policyStartTimeFlag,
+				"end_time":   policyEndTimeFlag,
+				"timezone":   policyTimezoneFlag,
+			}
+		}
+
+		actionName := "created"
+		method := "POST"
+		resourceArgs := []string{"policies"}
+		if policyOverwriteFlag {
+			policyID, err := getPolicyIDByName(resourceName)
+			if err != nil {
+				styles.PrintErrorAndExit(err.Error())
+			}
+			if policyID != "" {
+				log.Debugf("policy %v exists, updating", resourceName)
+				actionName = "updated"
+				method = "PUT"
+				resourceArgs = append(resourceArgs, policyID)
+			}
+		}
+		apir := parseResourceOrDie(resourceArgs, method, outputFlag)
+		resp, err := httpBodyRequest(apir, method, map[string]any{
+			"name":             resourceName,
+			"description":      policyDescriptionFlag,
+			"effect":           policyEffectFlag,
+			"groups":           policyGroupsFlag,
+			"connection_tags":  connectionTags,
+			"connection_types": policyConnectionTypesFlag,
+			"verbs":            policyVerbsFlag,
+			"client_origins":   policyOriginsFlag,
+			"source_ips":       policySourceIPsFlag,
+			"time_window":      timeWindow,
+		})
+		if err != nil {
+			styles.PrintErrorAndExit(err.Error())
+		}
+		if apir.decodeTo == "raw" {
+			jsonData, _ := resp.([]byte)
+			fmt.Println(string(jsonData))
+			return
+		}
+		obj, _ := resp.(map[string]any)
+		fmt.Printf("policy %v %v, id=%v\n", resourceName, actionName, obj["id"])
+	},
+}
+
+// getPolicyIDByName returns the id of a policy, it returns an empty value if it's not found
+func getPolicyIDByName(name string) (string, error) {
+	apir := parseResourceOrDie([]string{"policies"}, "GET", "")
+	resp, _, err := httpRequest(apir)
+	if err != nil {
+		return "", err
+	}
+	items, _ := resp.([]map[string]any)
+	for _, m := range items {
+		if m["name"] == name {
+			return fmt.Sprintf("%v", m["id"]), nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,92 @@
+package admin
+
+import (
+	"fmt"
+
+	"github.com/hoophq/hoop/client/cmd/styles"
+	"github.com/hoophq/hoop/common/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rolePermissionsFlag []string
+	roleGroupsFlag      []string
+	roleDescriptionFlag string
+	roleOverwriteFlag   bool
+)
+
+func init() {
+	createRoleCmd.Flags().StringSliceVar(&rolePermissionsFlag, "permissions", []string{}, "The permissions granted by the role, e.g.: connections:read,connections:write")
+	createRoleCmd.Flags().StringSliceVar(&roleGroupsFlag, "groups", []string{}, "The groups of users granted with the role, e.g.: dba-team,sre")
+	createRoleCmd.Flags().StringVar(&roleDescriptionFlag, "description", "", "The description of the role")
+	createRoleCmd.Flags().BoolVar(&roleOverwriteFlag, "overwrite", false, "It will create or update it if a role already exists")
+	_ = createRoleCmd.MarkFlagRequired("permissions")
+}
+
+var createRoleExamplesDesc = `
+hoop admin create role connection-manager --permissions connections:read,connections:write --groups dba-team
+hoop admin create role reviewer --permissions reviews:read,reviews:write,sessions:read --groups reviewers --overwrite`
+
+var createRoleCmd = &cobra.Command{
+	Use:     "role NAME",
+	Aliases: []string{"roles"},
+	Short:   "Create a custom role that grants permissions to the users of its groups.",
+	Example: createRoleExamplesDesc,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			cmd.Usage()
+			styles.PrintErrorAndExit("missing resource name")
+		}
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		resourceName := args[0]
+		actionName := "created"
+		method := "POST"
+		resourceArgs := []string{"roles"}
+		if roleOverwriteFlag {
+			roleID, err := getRoleIDByName(resourceName)
+			if err != nil {
+				styles.PrintErrorAndExit(err.Error())
+			}
+			if roleID != "" {
+				log.Debugf("role %v exists, updating", resourceName)
+				actionName = "updated"
+				method = "PUT"
+				resourceArgs = append(resourceArgs, roleID)
+			}
+		}
+		apir := parseResourceOrDie(resourceArgs, method, outputFlag)
+		resp, err := httpBodyRequest(apir, method, map[string]any{
+			"name":        resourceName,
+			"description": roleDescriptionFlag,
+			"permissions": rolePermissionsFlag,
+			"groups":      roleGroupsFlag,
+		})
+		if err != nil {
+			styles.PrintErrorAndExit(err.Error())
+		}
+		if apir.decodeTo == "raw" {
+			jsonData, _ := resp.([]byte)
+			fmt.Println(string(jsonData))
+			return
+		}
+		obj, _ := resp.(map[string]any)
+		fmt.Printf("role %v %v, id=%v\n", resourceName, actionName, obj["id"])
+	},
+}
+
+// getRoleIDByName returns the id of a role, it returns an empty value if it's not found
+func getRoleIDByName(name string) (string, error) {
+	apir := parseResourceOrDie([]string{"roles"}, "GET", "")
+	resp, _, err := httpRequest(apir)
+	if err != nil {
+		return "", err
+	}
+	items, _ := resp.([]map[string]any)
+	for _, m := range items {
+		if m["name"] == name {
+			return fmt.Sprintf("%v", m["id"]), nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,91 @@
+package admin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hoophq/hoop/client/cmd/styles"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tokenScopesFlag      []string
+	tokenConnectionsFlag []string
+	tokenExpiresInFlag   string
+	tokenSubjectFlag     string
+)
+
+func init() {
+	createTokenCmd.Flags().StringSliceVar(&tokenScopesFlag, "scopes", []string{}, "The scopes of the token, e.g.: sessions:read,connections:read,exec,connect")
+	createTokenCmd.Flags().StringSliceVar(&tokenConnectionsFlag, "connections", []string{}, "Restrict the token to a list of connections, e.g.: pgdemo,mysqldemo")
+	createTokenCmd.Flags().StringVar(&tokenExpiresInFlag, "expires-in", "", "The time the token is valid, e.g.: 30d, 12h. It never expires when it's empty")
+	createTokenCmd.Flags().StringVar(&tokenSubjectFlag, "subject", "", "The subject of the user or service account the token acts on behalf of (admin only)")
+	_ = createTokenCmd.MarkFlagRequired("scopes")
+}
+
+var createTokenExamplesDesc = `
+hoop admin create token sessions-report --scopes sessions:read --expires-in 30d
+hoop admin create token ci-migrations --scopes exec --connections pgdemo --subject ci-bot@serviceaccount`
+
+var createTokenCmd = &cobra.Command{
+	Use:     "token NAME",
+	Aliases: []string{"tokens"},
+	Short:   "Create an api token that acts on behalf of a user or service account.",
+	Example: createTokenExamplesDesc,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			cmd.Usage()
+			styles.PrintErrorAndExit("missing resource name")
+		}
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		resourceName := args[0]
+		req := map[string]any{
+			"name":        resourceName,
+			"scopes":      tokenScopesFlag,
+			"connections": tokenConnectionsFlag,
+			"subject":     tokenSubjectFlag,
+		}
+		if tokenExpiresInFlag != "" {
+			expiresIn, err := parseExpiresIn(tokenExpiresInFlag)
+			if err != nil {
+				styles.PrintErrorAndExit(err.Error())
+			}
+			req["expires_at"] = time.Now().UTC().Add(expiresIn).Format(time.RFC3339)
+		}
+		apir := parseResourceOrDie([]string{"tokens"}, "POST", outputFlag)
+		resp, err := httpBodyRequest(apir, "POST", req)
+		if err != nil {
+			styles.PrintErrorAndExit(err.Error())
+		}
+		if apir.decodeTo == "raw" {
+			jsonData, _ := resp.([]byte)
+			fmt.Println(string(jsonData))
+			return
+		}
+		obj, _ := resp.(map[string]any)
+		fmt.Printf("token %v created, id=%v\n", resourceName, obj["id"])
+		fmt.Println("store it in a safe place, it will not be displayed again:")
+		fmt.Println()
+		fmt.Println(obj["token"])
+	},
+}
+
+// parseExpiresIn parses a duration accepting days as unit, e.g.: 30d
+func parseExpiresIn(val string) (time.Duration, error) {
+	var d time.Duration
+	var err error
+	if days, found := strings.CutSuffix(val, "d"); found {
+		var n int
+		n, err = strconv.Atoi(days)
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err = time.ParseDuration(val)
+	}
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid --expires-in value %q, accepted formats are: 30d, 12h, 90m", val)
+	}
+	return d, nil
+}
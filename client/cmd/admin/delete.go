@@ -11,6 +11,9 @@ var deleteLongDesc = `Delete resource by its name. Available ones:
 
 * agent
 * connection
+* policies (remove it by id)
+* roles (remove it by id)
+* tokens (revoke it by id)
 * users
 `
 
@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/hoophq/hoop/client/cmd/styles"
 	clientconfig "github.com/hoophq/hoop/client/config"
@@ -30,10 +31,13 @@ var getLongDesc = `Display one or many resources. Available ones:
 * connections (tabview)
 * orgkeys (tabview)
 * plugins (tabview)
+* policies (tabview)
 * reviews
+* roles (tabview)
 * runbooks
 * serviceaccounts (tabview)
 * sessions
+* tokens (tabview)
 * users (tabview)
 `
 
@@ -210,6 +214,60 @@ var getCmd = &cobra.Command{
 					fmt.Fprintln(w)
 				}
 			}
+		case "token", "tokens":
+			contents, _ := obj.([]map[string]any)
+			fmt.Fprintln(w, "ID\tNAME\tOWNER\tPREFIX\tSCOPES\tCONNECTIONS\tSTATUS\tEXPIRES AT\tLAST USED AT\t")
+			for _, m := range contents {
+				scopes, _ := m["scopes"].([]any)
+				connections, _ := m["connections"].([]any)
+				connectionList := joinItems(connections)
+				if connectionList == "" {
+					connectionList = "-"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t",
+					m["id"], m["name"], m["email"], m["token_prefix"], joinItems(scopes), connectionList,
+					m["status"], timeOrDash(m["expires_at"]), timeOrDash(m["last_used_at"]))
+				fmt.Fprintln(w)
+			}
+		case "role", "roles":
+			fmt.Fprintln(w, "ID\tNAME\tPERMISSIONS\tGROUPS\tUPDATED AT\t")
+			switch contents := obj.(type) {
+			case map[string]any:
+				m := contents
+				permissions, _ := m["permissions"].([]any)
+				groups, _ := m["groups"].([]any)
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t",
+					m["id"], m["name"], joinItems(permissions), joinItems(groups), timeOrDash(m["updated_at"]))
+				fmt.Fprintln(w)
+			case []map[string]any:
+				for _, m := range contents {
+					permissions, _ := m["permissions"].([]any)
+					groups, _ := m["groups"].([]any)
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t",
+						m["id"], m["name"], joinItems(permissions), joinItems(groups), timeOrDash(m["updated_at"]))
+					fmt.Fprintln(w)
+				}
+			}
+		case "policy", "policies":
+			fmt.Fprintln(w, "ID\tNAME\tEFFECT\tGROUPS\tCONDITIONS\tUPDATED AT\t")
+			printPolicy := func(m map[string]any) {
+				groups, _ := m["groups"].([]any)
+				groupList := joinItems(groups)
+				if groupList == "" {
+					groupList = "-"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t",
+					m["id"], m["name"], m["effect"], groupList, policyConditions(m), timeOrDash(m["updated_at"]))
+				fmt.Fprintln(w)
+			}
+			switch contents := obj.(type) {
+			case map[string]any:
+				printPolicy(contents)
+			case []map[string]any:
+				for _, m := range contents {
+					printPolicy(m)
+				}
+			}
 		case "runbooks":
 			switch contents := obj.(type) {
 			case map[string]any:
@@ -387,6 +445,62 @@ func joinItems(items []any) string {
 	return strings.Join(list, ", ")
 }
 
+// timeOrDash formats a RFC3339 time value, it returns a dash when the value is empty
+func timeOrDash(v any) string {
+	val, _ := v.(string)
+	if val == "" {
+		return "-"
+	}
+	t, err := time.Parse(time.RFC3339Nano, val)
+	if err != nil {
+		return val
+	}
+	return t.Format(time.RFC3339)
+}
+
+// policyConditions summarizes the conditions of an access policy, e.g.:
+// tags=env=staging verbs=exec window=mon,tue 09:00-18:00 (UTC)
+func policyConditions(m map[string]any) string {
+	var conditions []string
+	if tags, _ := m["connection_tags"].(map[string]any); len(tags) > 0 {
+		conditions = append(conditions, "tags="+joinMap(tags))
+	}
+	for _, attr := range []struct{ key, name string }{
+		{"connection_types", "types"},
+		{"verbs", "verbs"},
+		{"client_origins", "origins"},
+		{"source_ips", "ips"},
+	} {
+		if items, _ := m[attr.key].([]any); len(items) > 0 {
+			conditions = append(conditions, attr.name+"="+strings.ReplaceAll(joinItems(items), ", ", ","))
+		}
+	}
+	if window, _ := m["time_window"].(map[string]any); window != nil {
+		days, _ := window["days"].([]any)
+		dayList := strings.ReplaceAll(joinItems(days), ", ", ",")
+		if dayList == "" {
+			dayList = "all"
+		}
+		startTime, _ := window["start_time"].(string)
+		endTime, _ := window["end_time"].(string)
+		timezone, _ := window["timezone"].(string)
+		if startTime == "" {
+			startTime = "00:00"
+		}
+		if endTime == "" {
+			endTime = "24:00"
+		}
+		if timezone == "" {
+			timezone = "UTC"
+		}
+		conditions = append(conditions, fmt.Sprintf("window=%s %s-%s (%s)", dayList, startTime, endTime, timezone))
+	}
+	if len(conditions) == 0 {
+		return "-"
+	}
+	return strings.Join(conditions, " ")
+}
+
 func joinMap(v any) (res string) {
 	m, ok := v.(map[string]any)
 	if !ok {
@@ -0,0 +1,196 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/hoophq/hoop/client/cmd/styles"
+	clientconfig "github.com/hoophq/hoop/client/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	guardRailsRulesFileFlag  string
+	guardRailsRuleIDFlag     string
+	guardRailsInputFlag      string
+	guardRailsInputFileFlag  string
+	guardRailsOutputFlag     string
+	guardRailsOutputFileFlag string
+	guardRailsConnectionFlag string
+	guardRailsSessionsFlag   int
+)
+
+func init() {
+	guardRailsTestCmd.Flags().StringVarP(&guardRailsRulesFileFlag, "file", "f", "", "The file containing the rules in json format, the same format of the guardrails resource")
+	guardRailsTestCmd.Flags().StringVar(&guardRailsRuleIDFlag, "id", "", "The id of an existing guardrails resource to test")
+	guardRailsTestCmd.Flags().StringVar(&guardRailsInputFlag, "sample-input", "", "The sample input to validate against the input rules")
+	guardRailsTestCmd.Flags().StringVar(&guardRailsInputFileFlag, "sample-input-file", "", "The file containing the sample input")
+	guardRailsTestCmd.Flags().StringVar(&guardRailsOutputFlag, "sample-output", "", "The sample output to validate against the output rules")
+	guardRailsTestCmd.Flags().StringVar(&guardRailsOutputFileFlag, "sample-output-file", "", "The file containing the sample output")
+	guardRailsTestCmd.Flags().StringVar(&guardRailsConnectionFlag, "connection", "", "Replay the rules against the latest sessions of this connection")
+	guardRailsTestCmd.Flags().IntVar(&guardRailsSessionsFlag, "sessions", 20, "The number of latest sessions to replay, max 100")
+	guardRailsTestCmd.Flags().StringVarP(&outputFlag, "output", "o", "", "Output format. One off: (json)")
+
+	guardRailsCmd.AddCommand(guardRailsTestCmd)
+}
+
+var guardRailsCmd = &cobra.Command{
+	Use:     "guardrails",
+	Aliases: []string{"guardrail"},
+	Short:   "Manage guard rail rules",
+}
+
+var guardRailsTestExamplesDesc = `
+hoop admin guardrails test -f rules.json --sample-input 'DROP TABLE customers'
+hoop admin guardrails test --id 15B5A2FD-0706-4A47-B1CF-B93CCFC5B3D7 --sample-output-file result.txt
+hoop admin guardrails test -f rules.json --connection pgdemo --sessions 50`
+
+var guardRailsTestCmd = &cobra.Command{
+	Use:     "test",
+	Short:   "Test guard rail rules against sample data or the latest sessions of a connection",
+	Example: guardRailsTestExamplesDesc,
+	Run: func(cmd *cobra.Command, args []string) {
+		conf := clientconfig.GetClientConfigOrDie()
+		rules := loadGuardRailRulesOrDie(conf)
+		sampleInput := readFlagOrFileOrDie(guardRailsInputFlag, guardRailsInputFileFlag)
+		sampleOutput := readFlagOrFileOrDie(guardRailsOutputFlag, guardRailsOutputFileFlag)
+		if sampleInput == "" && sampleOutput == "" && guardRailsConnectionFlag == "" {
+			styles.PrintErrorAndExit("missing sample data, use --sample-input, --sample-output or --connection flags")
+		}
+		req := map[string]any{
+			"input":         rules["input"],
+			"output":        rules["output"],
+			"sample_input":  sampleInput,
+			"sample_output": sampleOutput,
+		}
+		if guardRailsConnectionFlag != "" {
+			req["connection_name"] = guardRailsConnectionFlag
+			req["sessions_limit"] = guardRailsSessionsFlag
+		}
+		decodeTo := "object"
+		if outputFlag == "json" {
+			decodeTo = "raw"
+		}
+		apir := &apiResource{suffixEndpoint: "/api/guardrails/test", conf: conf, decodeTo: decodeTo}
+		obj, err := httpBodyRequest(apir, "POST", req)
+		if err != nil {
+			styles.PrintErrorAndExit(err.Error())
+		}
+		if outputFlag == "json" {
+			jsonData, _ := obj.([]byte)
+			fmt.Println(string(jsonData))
+			return
+		}
+		resp, _ := obj.(map[string]any)
+		printGuardRailsTestResult(resp, map[string]string{"input": sampleInput, "output": sampleOutput})
+	},
+}
+
+// loadGuardRailRulesOrDie loads the rules from a file or from an existing guardrails resource
+func loadGuardRailRulesOrDie(conf *clientconfig.Config) map[string]any {
+	switch {
+	case guardRailsRulesFileFlag != "" && guardRailsRuleIDFlag != "":
+		styles.PrintErrorAndExit("--file and --id flags are mutually exclusive")
+	case guardRailsRulesFileFlag != "":
+		data, err := os.ReadFile(guardRailsRulesFileFlag)
+		if err != nil {
+			styles.PrintErrorAndExit("failed reading rules file: %v", err)
+		}
+		var rules map[string]any
+		if err := json.Unmarshal(data, &rules); err != nil {
+			styles.PrintErrorAndExit("failed decoding rules file: %v", err)
+		}
+		return rules
+	case guardRailsRuleIDFlag != "":
+		obj, _, err := httpRequest(&apiResource{
+			suffixEndpoint: path.Join("/api/guardrails", guardRailsRuleIDFlag),
+			conf:           conf,
+			decodeTo:       "object"})
+		if err != nil {
+			styles.PrintErrorAndExit(err.Error())
+		}
+		rules, _ := obj.(map[string]any)
+		return rules
+	}
+	styles.PrintErrorAndExit("missing the rules, use --file or --id flags")
+	return nil
+}
+
+func readFlagOrFileOrDie(val, filePath string) string {
+	if filePath == "" {
+		return val
+	}
+	if val != "" {
+		styles.PrintErrorAndExit("the sample data and its file flags are mutually exclusive")
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		styles.PrintErrorAndExit("failed reading file %v: %v", filePath, err)
+	}
+	return string(data)
+}
+
+func printGuardRailsTestResult(resp map[string]any, samples map[string]string) {
+	matches, _ := resp["matches"].([]any)
+	if len(matches) == 0 {
+		fmt.Println("No rules matched the sample data")
+	}
+	w := tabwriter.NewWriter(os.Stdout, 6, 4, 3, ' ', tabwriter.TabIndent)
+	if len(matches) > 0 {
+		fmt.Fprintln(w, "DIRECTION\tRULE\tTYPE\tACTION\tOFFSETS\tMATCH\tEXPLANATION\t")
+	}
+	for _, obj := range matches {
+		m, _ := obj.(map[string]any)
+		direction := fmt.Sprintf("%v", m["direction"])
+		offsets, _ := m["offsets"].([]any)
+		var offsetList, matchList []string
+		for _, offsetObj := range offsets {
+			offset, _ := offsetObj.(map[string]any)
+			start, _ := offset["start"].(float64)
+			end, _ := offset["end"].(float64)
+			offsetList = append(offsetList, fmt.Sprintf("%v-%v", start, end))
+			sample := samples[direction]
+			if int(start) >= 0 && int(end) <= len(sample) && start <= end {
+				matchList = append(matchList, fmt.Sprintf("%q", truncateString(sample[int(start):int(end)], 30)))
+			}
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\t\n",
+			direction, m["rule_index"], m["rule_type"], m["action"],
+			strings.Join(offsetList, ","), strings.Join(matchList, ","), m["explanation"])
+	}
+	w.Flush()
+
+	replay, _ := resp["replay"].(map[string]any)
+	if replay == nil {
+		return
+	}
+	fmt.Printf("\nReplay of connection %v\n", replay["connection_name"])
+	fmt.Printf("  Sessions Evaluated: %v\n", replay["sessions_evaluated"])
+	fmt.Printf("  Blocked:            %v\n", replay["blocked"])
+	fmt.Printf("  Warned:             %v\n", replay["warned"])
+	fmt.Printf("  Reviewed:           %v\n", replay["reviewed"])
+	fmt.Printf("  Masked:             %v\n", replay["masked"])
+	blocked, _ := replay["blocked_sessions"].([]any)
+	if len(blocked) == 0 {
+		return
+	}
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 6, 4, 3, ' ', tabwriter.TabIndent)
+	defer w.Flush()
+	fmt.Fprintln(w, "SESSION\tUSER\tCREATED AT\tREASON\t")
+	for _, obj := range blocked {
+		s, _ := obj.(map[string]any)
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t\n", s["id"], s["user_email"], s["created_at"], s["reason"])
+	}
+}
+
+func truncateString(s string, size int) string {
+	if len(s) <= size {
+		return s
+	}
+	return s[:size] + "..."
+}
@@ -0,0 +1,122 @@
+package admin
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"text/tabwriter"
+
+	"github.com/hoophq/hoop/client/cmd/styles"
+	clientconfig "github.com/hoophq/hoop/client/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	policyEvalUserFlag     string
+	policyEvalVerbFlag     string
+	policyEvalOriginFlag   string
+	policyEvalSourceIPFlag string
+	policyEvalTimeFlag     string
+)
+
+func init() {
+	policiesEvaluateCmd.Flags().StringVar(&policyEvalUserFlag, "user", "", "The email or the subject of the user to evaluate, it defaults to the current user")
+	policiesEvaluateCmd.Flags().StringVar(&policyEvalVerbFlag, "verb", "", "The verb of the request (exec, connect), an empty value evaluates reading the connection")
+	policiesEvaluateCmd.Flags().StringVar(&policyEvalOriginFlag, "origin", "", "The client origin of the request (client, client-api, client-api-runbooks, client-proxymanager)")
+	policiesEvaluateCmd.Flags().StringVar(&policyEvalSourceIPFlag, "source-ip", "", "The source ip of the request, it defaults to the ip of this client")
+	policiesEvaluateCmd.Flags().StringVar(&policyEvalTimeFlag, "time", "", "The time of the request in RFC3339 format, it defaults to the current time")
+	policiesEvaluateCmd.Flags().StringVarP(&outputFlag, "output", "o", "", "Output format. One off: (json)")
+
+	policiesCmd.AddCommand(policiesEvaluateCmd)
+}
+
+var policiesCmd = &cobra.Command{
+	Use:     "policies",
+	Aliases: []string{"policy"},
+	Short:   "Manage access policies",
+}
+
+var policiesEvaluateExamplesDesc = `
+hoop admin policies evaluate pgdemo --user john.wick@bad.org --verb exec --origin client
+hoop admin policies evaluate pgdemo --verb connect --source-ip 10.0.0.1 --time 2024-07-27T10:30:00Z`
+
+var policiesEvaluateCmd = &cobra.Command{
+	Use:     "evaluate CONNECTION",
+	Short:   "Explain if a user is allowed to access a connection",
+	Example: policiesEvaluateExamplesDesc,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			cmd.Usage()
+			styles.PrintErrorAndExit("missing connection name")
+		}
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		conf := clientconfig.GetClientConfigOrDie()
+		query := url.Values{"connection": []string{args[0]}}
+		for key, val := range map[string]string{
+			"user":      policyEvalUserFlag,
+			"verb":      policyEvalVerbFlag,
+			"origin":    policyEvalOriginFlag,
+			"source_ip": policyEvalSourceIPFlag,
+			"time":      policyEvalTimeFlag,
+		} {
+			if val != "" {
+				query.Set(key, val)
+			}
+		}
+		decodeTo := "object"
+		if outputFlag == "json" {
+			decodeTo = "raw"
+		}
+		obj, _, err := httpRequest(&apiResource{
+			suffixEndpoint:  "/api/policies/evaluate",
+			queryAttributes: query,
+			conf:            conf,
+			decodeTo:        decodeTo})
+		if err != nil {
+			styles.PrintErrorAndExit(err.Error())
+		}
+		if outputFlag == "json" {
+			jsonData, _ := obj.([]byte)
+			fmt.Println(string(jsonData))
+			return
+		}
+		resp, _ := obj.(map[string]any)
+		printPoliciesEvaluation(resp)
+	},
+}
+
+func printPoliciesEvaluation(resp map[string]any) {
+	verb, _ := resp["verb"].(string)
+	if verb == "" {
+		verb = "read"
+	}
+	decision := "DENIED"
+	if resp["allowed"] == true {
+		decision = "ALLOWED"
+	}
+	fmt.Printf("Decision:       %v\n", decision)
+	fmt.Printf("Reason:         %v\n", resp["reason"])
+	fmt.Printf("User:           %v\n", resp["user"])
+	fmt.Printf("Connection:     %v\n", resp["connection"])
+	fmt.Printf("Verb:           %v\n", verb)
+	fmt.Printf("Client Origin:  %v\n", toStr(resp["client_origin"]))
+	fmt.Printf("Source IP:      %v\n", toStr(resp["source_ip"]))
+	fmt.Printf("Time:           %v\n", timeOrDash(resp["time"]))
+	fmt.Printf("Access Control: %v\n", resp["access_control_allowed"])
+	fmt.Printf("Policies:       %v\n", resp["policies_allowed"])
+
+	policies, _ := resp["policies"].([]any)
+	if len(policies) == 0 {
+		return
+	}
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 6, 4, 3, ' ', tabwriter.TabIndent)
+	defer w.Flush()
+	fmt.Fprintln(w, "POLICY\tEFFECT\tAPPLICABLE\tMATCHED\tREASON\t")
+	for _, obj := range policies {
+		p, _ := obj.(map[string]any)
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t\n",
+			p["name"], p["effect"], p["applicable"], p["matched"], toStr(p["reason"]))
+	}
+}
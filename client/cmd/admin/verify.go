@@ -0,0 +1,84 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/hoophq/hoop/client/cmd/styles"
+	clientconfig "github.com/hoophq/hoop/client/config"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	verifySessionCmd.Flags().StringVarP(&outputFlag, "output", "o", "", "Output format. One off: (json)")
+}
+
+var verifySessionOutput = `Session:            %v
+Status:             %v
+Reason:             %v
+Events:             %v (sealed: %v)
+Digest:             %v
+Sealed Digest:      %v
+Key ID:             %v
+Signature Verified: %v
+Sealed At:          %v
+Anchored At:        %v
+`
+
+var verifySessionCmd = &cobra.Command{
+	Use:   "verify-session SESSION_ID",
+	Short: "Verify if the event stream of a session was truncated or modified",
+	Long: `Verify the event stream of a session against the digest sealed by the gateway when the session ended.
+It exits with status 2 when the session was tampered.`,
+	Example: "hoop admin verify-session 5364ec99-653b-41ba-8165-67236e894990",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			cmd.Usage()
+			styles.PrintErrorAndExit("missing the session id argument")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		conf := clientconfig.GetClientConfigOrDie()
+		obj, _, err := httpRequest(&apiResource{
+			suffixEndpoint: path.Join("/api/sessions", args[0], "verify"),
+			conf:           conf,
+			decodeTo:       "raw"})
+		if err != nil {
+			styles.PrintErrorAndExit(err.Error())
+		}
+		data, _ := obj.([]byte)
+		var resp map[string]any
+		if err := json.Unmarshal(data, &resp); err != nil {
+			styles.PrintErrorAndExit("failed decoding response: %v", err)
+		}
+		if outputFlag == "json" {
+			fmt.Println(string(data))
+		} else {
+			displayFn := func(val any) any {
+				if val == nil || val == "" {
+					return "-"
+				}
+				return val
+			}
+			fmt.Printf(verifySessionOutput,
+				resp["session_id"],
+				resp["status"],
+				resp["reason"],
+				resp["events"],
+				resp["sealed_events"],
+				displayFn(resp["digest"]),
+				displayFn(resp["sealed_digest"]),
+				displayFn(resp["key_id"]),
+				resp["signature_verified"],
+				displayFn(resp["sealed_at"]),
+				displayFn(resp["anchored_at"]),
+			)
+		}
+		if resp["status"] == "tampered" {
+			os.Exit(2)
+		}
+	},
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/hoophq/hoop/client/cmd/styles"
 	clientconfig "github.com/hoophq/hoop/client/config"
@@ -25,7 +26,7 @@ func init() {
 	viewCmd.Flags().BoolVar(&viewRawFlag, "raw", false, "Display sensitive credentials information")
 
 	_ = createCmd.MarkFlagRequired("api-url")
-	MainCmd.AddCommand(createCmd, viewCmd, clearCmd)
+	MainCmd.AddCommand(createCmd, viewCmd, clearCmd, useContextCmd, getContextsCmd)
 }
 
 var MainCmd = &cobra.Command{
@@ -72,6 +73,9 @@ var viewCmd = &cobra.Command{
 	SilenceUsage: false,
 	Run: func(cmd *cobra.Command, args []string) {
 		c := clientconfig.GetClientConfigOrDie()
+		if c.Profile != "" {
+			fmt.Printf("context=%s\n", c.Profile)
+		}
 		fmt.Printf("api_url=%s\n", c.ApiURL)
 		fmt.Printf("grpc_url=%s\n", c.GrpcURL)
 		if viewRawFlag {
@@ -87,12 +91,61 @@ var viewCmd = &cobra.Command{
 
 var clearCmd = &cobra.Command{
 	Use:          "clear",
-	Short:        "Delete the client hoop configuration file if exists",
+	Short:        "Delete the client hoop configuration file if exists, use --profile to delete only a context",
 	SilenceUsage: false,
 	Run: func(cmd *cobra.Command, args []string) {
+		if f := cmd.Flag("profile"); f != nil && f.Changed {
+			if err := clientconfig.RemoveContext(f.Value.String()); err != nil {
+				styles.PrintErrorAndExit("failed removing context, err=%v", err)
+			}
+			fmt.Printf("context %q removed\n", f.Value.String())
+			return
+		}
 		if err := clientconfig.Remove(); err != nil {
 			styles.PrintErrorAndExit("failed removing configuration file, err=%v", err)
 		}
 		fmt.Println("configuration file removed")
 	},
 }
+
+var useContextCmd = &cobra.Command{
+	Use:          "use-context NAME",
+	Short:        "Set the current context of the configuration file",
+	Example:      "hoop config use-context staging",
+	SilenceUsage: false,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			styles.PrintErrorAndExit("missing the context name argument")
+		}
+		if err := clientconfig.UseContext(args[0]); err != nil {
+			styles.PrintErrorAndExit(err.Error())
+		}
+		fmt.Printf("switched to context %q\n", args[0])
+	},
+}
+
+var getContextsCmd = &cobra.Command{
+	Use:          "get-contexts",
+	Short:        "List the contexts of the configuration file",
+	SilenceUsage: false,
+	Run: func(cmd *cobra.Command, args []string) {
+		contexts, current, err := clientconfig.ListContexts()
+		if err != nil {
+			styles.PrintErrorAndExit(err.Error())
+		}
+		w := tabwriter.NewWriter(os.Stdout, 6, 4, 3, ' ', 0)
+		defer w.Flush()
+		fmt.Fprintln(w, "CURRENT\tNAME\tAPI URL\tGRPC URL\tLOGGED IN\t")
+		for _, ctx := range contexts {
+			currentMark := ""
+			if ctx.Name == current {
+				currentMark = "*"
+			}
+			loggedIn := "no"
+			if ctx.Token != "" {
+				loggedIn = "yes"
+			}
+			fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t\n", currentMark, ctx.Name, ctx.ApiURL, ctx.GrpcURL, loggedIn)
+		}
+	},
+}
@@ -33,9 +33,11 @@ import (
 )
 
 type ConnectFlags struct {
-	proxyAddr string
-	proxyPort string
-	duration  string
+	proxyAddr  string
+	proxyPort  string
+	duration   string
+	extend     string
+	breakGlass string
 }
 
 var connectFlags = ConnectFlags{}
@@ -56,10 +58,23 @@ var (
 			if dur.Seconds() < 60 {
 				return fmt.Errorf("the minimum duration is 60 seconds (60s)")
 			}
+			if connectFlags.extend != "" {
+				dur, err := time.ParseDuration(connectFlags.extend)
+				if err != nil {
+					return fmt.Errorf("invalid extend duration, valid units are 's', 'm', 'h'. E.g.: 60s|3m|1h")
+				}
+				if dur.Seconds() < 60 {
+					return fmt.Errorf("the minimum extend duration is 60 seconds (60s)")
+				}
+			}
 			return nil
 		},
 		SilenceUsage: false,
 		Run: func(cmd *cobra.Command, args []string) {
+			if connectFlags.extend != "" {
+				runExtend(args[0], connectFlags.extend)
+				return
+			}
 			clientEnvVars, err := parseClientEnvVars()
 			if err != nil {
 				fmt.Println(err)
@@ -75,6 +90,8 @@ func init() {
 	connectCmd.Flags().StringVarP(&connectFlags.proxyPort, "port", "p", "", "The port to listen the proxy")
 	connectCmd.Flags().StringSliceVarP(&inputEnvVars, "env", "e", nil, "Input environment variables to send")
 	connectCmd.Flags().StringVarP(&connectFlags.duration, "duration", "d", "30m", "The amount of time that the session will last. Valid time units are 's', 'm', 'h'")
+	connectCmd.Flags().StringVar(&connectFlags.breakGlass, "break-glass", "", "Open the session without waiting for the review in emergencies, it requires the reason of the access. The reviewers are notified and must acknowledge it afterwards")
+	connectCmd.Flags().StringVar(&connectFlags.extend, "extend", "", "Request additional time for the active access of the connection, the connected sessions are kept. Valid time units are 's', 'm', 'h'")
 	rootCmd.AddCommand(connectCmd)
 }
 
@@ -102,6 +119,9 @@ func runConnect(args []string, clientEnvVars map[string]string) {
 	sendOpenSessionPktFn := func() {
 		spec := newClientArgsSpec(c.clientArgs, clientEnvVars)
 		spec[pb.SpecJitTimeout] = []byte(connectFlags.duration)
+		if connectFlags.breakGlass != "" {
+			spec[pb.SpecBreakGlassReason] = []byte(connectFlags.breakGlass)
+		}
 		if err := c.client.Send(&pb.Packet{
 			Type: pbagent.SessionOpen,
 			Spec: spec,
@@ -199,6 +219,20 @@ func runConnect(args []string, clientEnvVars map[string]string) {
 				fmt.Printf(" mongodb://noop:noop@%s:%s/?directConnection=true\n", srv.Host().Host, srv.Host().Port)
 				fmt.Println("------------------------------------------------------------")
 				fmt.Println("ready to accept connections!")
+			case pb.ConnectionTypeRedis:
+				srv := proxy.NewRedisServer(c.proxyPort, c.client)
+				if err := srv.Serve(string(sessionID)); err != nil {
+					c.processGracefulExit(err)
+				}
+				c.loader.Stop()
+				c.client.StartKeepAlive()
+				c.connStore.Set(string(sessionID), srv)
+				c.printHeader(string(sessionID))
+				fmt.Println()
+				fmt.Println("---------------------redis-credentials----------------------")
+				fmt.Printf("             redis://%s:%s\n", srv.Host().Host, srv.Host().Port)
+				fmt.Println("------------------------------------------------------------")
+				fmt.Println("ready to accept connections!")
 			case pb.ConnectionTypeTCP:
 				tcp := proxy.NewTCPServer(c.proxyPort, c.client, pbagent.TCPConnectionWrite)
 				if err := tcp.Serve(string(sessionID)); err != nil {
@@ -342,6 +376,19 @@ func runConnect(args []string, clientEnvVars map[string]string) {
 				errMsg := fmt.Errorf("failed writing to client, err=%v", err)
 				c.processGracefulExit(errMsg)
 			}
+		case pbclient.RedisConnectionWrite:
+			sessionID := pkt.Spec[pb.SpecGatewaySessionID]
+			srvObj := c.connStore.Get(string(sessionID))
+			srv, ok := srvObj.(*proxy.RedisServer)
+			if !ok {
+				return
+			}
+			connectionID := string(pkt.Spec[pb.SpecClientConnectionID])
+			_, err := srv.PacketWriteClient(connectionID, pkt)
+			if err != nil {
+				errMsg := fmt.Errorf("failed writing to client, err=%v", err)
+				c.processGracefulExit(errMsg)
+			}
 		case pbclient.HttpProxyConnectionWrite:
 			sessionID := pkt.Spec[pb.SpecGatewaySessionID]
 			connectionID := string(pkt.Spec[pb.SpecClientConnectionID])
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/hoophq/hoop/client/cmd/styles"
+	clientconfig "github.com/hoophq/hoop/client/config"
+	"github.com/hoophq/hoop/common/httpclient"
+)
+
+// runExtend requests additional time for the active jit review of the connection,
+// the review is approved by the same groups of the jit review.
+func runExtend(connectionName, extendDuration string) {
+	config := clientconfig.GetClientConfigOrDie()
+	duration, _ := time.ParseDuration(extendDuration)
+	reviewID, err := extendHTTPRequest(config, connectionName, duration)
+	if err != nil {
+		printErrorAndExit(err.Error())
+	}
+	fmt.Printf("requested %v of additional access to %v, waiting to be approved at %v\n",
+		duration.String(), connectionName, styles.Keyword(fmt.Sprintf(" %s/reviews/%s ", config.ApiURL, reviewID)))
+}
+
+func extendHTTPRequest(c *clientconfig.Config, connectionName string, duration time.Duration) (string, error) {
+	body, err := json.Marshal(map[string]any{"access_duration_sec": int(duration.Seconds())})
+	if err != nil {
+		return "", fmt.Errorf("failed marshaling body request, err=%v", err)
+	}
+	apiURL := fmt.Sprintf("%s/api/connections/%s/jit/extension", c.ApiURL, url.PathEscape(connectionName))
+	req, err := http.NewRequest("POST", apiURL, bytes.NewBuffer(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.Token))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpclient.NewHttpClient(c.TlsCA()).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed performing extend request, err=%v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		data, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("failed requesting extension, status-code=%v, payload=%v", resp.StatusCode, string(data))
+	}
+	var review struct {
+		ID string `json:"id"`
+	}
+	return review.ID, json.NewDecoder(resp.Body).Decode(&review)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/hoophq/hoop/client/cmd/admin"
 	"github.com/hoophq/hoop/client/cmd/config"
+	clientconfig "github.com/hoophq/hoop/client/config"
 	"github.com/hoophq/hoop/common/grpc"
 	"github.com/hoophq/hoop/common/log"
 	"github.com/spf13/cobra"
@@ -21,7 +22,7 @@ var rootCmd = &cobra.Command{
 	CompletionOptions: cobra.CompletionOptions{DisableDefaultCmd: true},
 	Long: `Connect to private infra-structure without the need of a VPN.
 https://hoop.dev/docs`,
-	PersistentPreRun: func(_ *cobra.Command, _ []string) {
+	PersistentPreRun: func(cmd *cobra.Command, _ []string) {
 		// run with the env GODEBUG=http2debug=2 to log http2 frames.
 		if debugGrpcFlag {
 			log.SetGrpcLogger()
@@ -29,6 +30,9 @@ https://hoop.dev/docs`,
 		if debugFlag {
 			log.SetDefaultLoggerLevel(log.LevelDebug)
 		}
+		if f := cmd.Flag("profile"); f != nil {
+			clientconfig.SetProfile(f.Value.String())
+		}
 	},
 }
 
@@ -47,4 +51,10 @@ func init() {
 
 	rootCmd.AddCommand(config.MainCmd)
 	rootCmd.AddCommand(admin.MainCmd)
+
+	// commands that load the client configuration
+	for _, c := range []*cobra.Command{connectCmd, execCmd, searchCmd, loginCmd, sessionCmd, uiCmd, config.MainCmd, admin.MainCmd} {
+		c.PersistentFlags().String("profile", "",
+			"The context of the configuration file to use, it overrides the env HOOP_PROFILE and the current context")
+	}
 }
@@ -46,6 +46,7 @@ func init() {
 	runCmd.Flags().StringVar(&runFlags.ConnectionString, "mysql", dbConnectionURI, "The database connection uri, e.g.: mysql://...")
 	runCmd.Flags().StringVar(&runFlags.ConnectionString, "mssql", dbConnectionURI, "The database connection uri, e.g.: sqlserver://...")
 	runCmd.Flags().StringVar(&runFlags.ConnectionString, "mongodb", dbConnectionURI, "The database connection uri, e.g.: mongodb://...")
+	runCmd.Flags().StringVar(&runFlags.ConnectionString, "redis", dbConnectionURI, "The database connection uri, e.g.: redis://...")
 	runCmd.Flags().StringSliceVar(&runFlags.Reviewers, "review", nil, "The approval groups for this connection, interactions are reviewed when enabled")
 	runCmd.Flags().StringSliceVar(&runFlags.RedactTypes, "data-masking", nil, "The data masking types for this connection, content is redacted when enabled")
 
@@ -145,6 +146,18 @@ func setDatabaseType(req *proto.PreConnectRequest) (err error) {
 		if port == "" {
 			port = "27017"
 		}
+	case u.Scheme == "redis":
+		req.Subtype = proto.ConnectionTypeRedis.String()
+		req.Command = []string{"redis-cli", "-h", "$HOST", "-p", "$PORT", "--user", "$USER", "--pass", "$PASS", "--no-auth-warning", "-n", "$DB"}
+		if port == "" {
+			port = "6379"
+		}
+		if dbname == "" {
+			req.Envs["envvar:DB"] = encb64("0")
+		}
+		if u.User.Username() == "" {
+			u.User = url.UserPassword("default", passwd)
+		}
 	case strings.HasSuffix(u.Scheme, "sqlserver"):
 		req.Subtype = proto.ConnectionTypeMSSQL.String()
 		req.Command = []string{"sqlcmd", "--exit-on-error", "--trim-spaces", "-r", "-S$HOST:$PORT", "-U$USER", "-d$DB", "-i/dev/stdin"}
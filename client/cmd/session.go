@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	clientconfig "github.com/hoophq/hoop/client/config"
+	"github.com/hoophq/hoop/common/httpclient"
+	"github.com/hoophq/hoop/gateway/session/asciicast"
+	"github.com/spf13/cobra"
+)
+
+var replaySpeedFlag float64
+
+var sessionCmd = &cobra.Command{
+	Use:   "session",
+	Short: "Interact with recorded sessions",
+}
+
+var sessionReplayCmd = &cobra.Command{
+	Use:   "replay SESSION_ID",
+	Short: "Replay a recorded terminal session in the local terminal",
+	Long: `Replay a recorded session with the original timing of the events.
+It's intended for sessions opened with hoop connect of terminal and ssh connections.`,
+	Example: "hoop session replay 5701046A-7B7A-4A78-ABB0-A24C95E6FE54 --speed 2",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("missing session id")
+		}
+		if replaySpeedFlag <= 0 {
+			return fmt.Errorf("the speed must be greater than zero")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		runSessionReplay(args[0], replaySpeedFlag)
+	},
+}
+
+func init() {
+	sessionReplayCmd.Flags().Float64VarP(&replaySpeedFlag, "speed", "s", 1, "The playback speed, e.g.: 2 replays the session twice as fast")
+	sessionCmd.AddCommand(sessionReplayCmd)
+	rootCmd.AddCommand(sessionCmd)
+}
+
+func runSessionReplay(sessionID string, speed float64) {
+	config := clientconfig.GetClientConfigOrDie()
+	body, err := playbackHTTPRequest(config, sessionID)
+	if err != nil {
+		printErrorAndExit(err.Error())
+	}
+	defer body.Close()
+	if err := replay(body, os.Stdout, speed, time.Sleep); err != nil {
+		printErrorAndExit(err.Error())
+	}
+	fmt.Printf("\r\n")
+}
+
+func playbackHTTPRequest(c *clientconfig.Config, sessionID string) (io.ReadCloser, error) {
+	apiURL := fmt.Sprintf("%s/api/sessions/%s/playback", c.ApiURL, url.PathEscape(sessionID))
+	req, err := http.NewRequest("GET", apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.Token))
+	resp, err := httpclient.NewHttpClient(c.TlsCA()).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed performing playback request, err=%v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed obtaining session playback, status-code=%v, payload=%v", resp.StatusCode, string(data))
+	}
+	return resp.Body, nil
+}
+
+// replay writes the output events of an asciicast stream to w
+// waiting the relative time between events divided by the speed
+func replay(r io.Reader, w io.Writer, speed float64, sleepFn func(time.Duration)) error {
+	reader := bufio.NewReader(r)
+	line, err := reader.ReadBytes('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed reading playback header, reason=%v", err)
+	}
+	var header asciicast.Header
+	if err := json.Unmarshal(line, &header); err != nil {
+		return fmt.Errorf("failed decoding playback header, reason=%v", err)
+	}
+	if header.Version != asciicast.Version {
+		return fmt.Errorf("unsupported playback version %v", header.Version)
+	}
+	var lastEventTime float64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var ev asciicast.Event
+			if err := json.Unmarshal(line, &ev); err != nil {
+				return fmt.Errorf("failed decoding playback event, reason=%v", err)
+			}
+			if ev.Time > lastEventTime {
+				sleepFn(time.Duration((ev.Time - lastEventTime) / speed * float64(time.Second)))
+				lastEventTime = ev.Time
+			}
+			// the input is echoed by the remote terminal
+			if ev.Code == "o" {
+				if _, err := io.WriteString(w, ev.Data); err != nil {
+					return err
+				}
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed reading playback event, reason=%v", err)
+		}
+	}
+}
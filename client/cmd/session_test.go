@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReplay(t *testing.T) {
+	for _, tt := range []struct {
+		msg        string
+		playback   string
+		speed      float64
+		wantOutput string
+		wantSleeps []time.Duration
+		err        error
+	}{
+		{
+			msg: "it must write the output with the original timing",
+			playback: `{"version":2,"width":120,"height":40}
+[0.5,"r","120x40"]
+[1,"i","l"]
+[1.5,"o","ls\r\n"]
+[3.5,"o","file.txt\r\n"]
+`,
+			speed:      1,
+			wantOutput: "ls\r\nfile.txt\r\n",
+			wantSleeps: []time.Duration{time.Millisecond * 500, time.Millisecond * 500, time.Millisecond * 500, time.Second * 2},
+		},
+		{
+			msg: "it must accelerate the timing with the speed",
+			playback: `{"version":2,"width":80,"height":24}
+[2,"o","$ "]
+[4,"o","exit"]`,
+			speed:      2,
+			wantOutput: "$ exit",
+			wantSleeps: []time.Duration{time.Second, time.Second},
+		},
+		{
+			msg:      "it must fail with an unsupported version",
+			playback: `{"version":1,"width":80,"height":24}`,
+			speed:    1,
+			err:      fmt.Errorf("unsupported playback version 1"),
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			var output bytes.Buffer
+			var sleeps []time.Duration
+			err := replay(strings.NewReader(tt.playback), &output, tt.speed, func(d time.Duration) { sleeps = append(sleeps, d) })
+			if !cmp.Equal(fmt.Sprintf("%v", tt.err), fmt.Sprintf("%v", err)) {
+				t.Errorf("expect error to match, got=%v, want=%v", err, tt.err)
+			}
+			if diff := cmp.Diff(tt.wantOutput, output.String()); diff != "" {
+				t.Errorf("output not equal: %v", diff)
+			}
+			if diff := cmp.Diff(tt.wantSleeps, sleeps); diff != "" {
+				t.Errorf("sleeps not equal: %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// fuzzyScore reports if all characters of the pattern appear in order in the text (case insensitive).
+// The score favors consecutive characters and characters at the start of words.
+func fuzzyScore(pattern, text string) (int, bool) {
+	p := []rune(strings.ToLower(pattern))
+	if len(p) == 0 {
+		return 0, true
+	}
+	t := []rune(strings.ToLower(text))
+	best, found := 0, false
+	// the greedy match of each occurrence of the first character, e.g.: "st" in "mysql-staging"
+	for start := range t {
+		if t[start] != p[0] {
+			continue
+		}
+		if score, ok := fuzzyScoreFrom(p, t, start); ok && (!found || score > best) {
+			best, found = score, true
+		}
+	}
+	return best, found
+}
+
+func fuzzyScoreFrom(p, t []rune, start int) (int, bool) {
+	score, pi, prev := 0, 0, -2
+	for i := start; i < len(t) && pi < len(p); i++ {
+		if t[i] != p[pi] {
+			continue
+		}
+		score++
+		if prev == i-1 {
+			score += 5
+		}
+		if i == 0 || !unicode.IsLetter(t[i-1]) && !unicode.IsDigit(t[i-1]) {
+			score += 3
+		}
+		prev = i
+		pi++
+	}
+	return score, pi == len(p)
+}
+
+// filterConnections returns the connections matching the query by name or type,
+// ordered by the best matches. An empty query returns all connections.
+func filterConnections(query string, items []Connection) []Connection {
+	type match struct {
+		conn  Connection
+		score int
+	}
+	var matches []match
+	for _, conn := range items {
+		score, ok := fuzzyScore(query, conn.Name)
+		if typeScore, typeOk := fuzzyScore(query, conn.DisplayType()); typeOk && (!ok || typeScore > score) {
+			score, ok = typeScore, true
+		}
+		if ok {
+			matches = append(matches, match{conn, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	result := make([]Connection, 0, len(matches))
+	for _, m := range matches {
+		result = append(result, m.conn)
+	}
+	return result
+}
@@ -0,0 +1,86 @@
+package tui
+
+import "unicode/utf8"
+
+type KeyCode int
+
+const (
+	KeyRune KeyCode = iota
+	KeyEnter
+	KeyBackspace
+	KeyUp
+	KeyDown
+	KeyTab
+	KeyShiftTab
+	KeyEsc
+	KeyCtrlC
+	KeyCtrlE
+	KeyCtrlN
+	KeyCtrlP
+	KeyCtrlR
+	KeyCtrlU
+	KeyUnknown
+)
+
+type Key struct {
+	Code KeyCode
+	Rune rune
+}
+
+var controlKeys = map[byte]KeyCode{
+	'\r': KeyEnter,
+	'\n': KeyEnter,
+	'\t': KeyTab,
+	127:  KeyBackspace,
+	8:    KeyBackspace,
+	3:    KeyCtrlC,
+	5:    KeyCtrlE,
+	14:   KeyCtrlN,
+	16:   KeyCtrlP,
+	18:   KeyCtrlR,
+	21:   KeyCtrlU,
+}
+
+// parseKeys decodes the keys of a chunk read from a terminal in raw mode.
+// A single read contains a whole escape sequence, a lone escape byte is the esc key.
+func parseKeys(b []byte) []Key {
+	var keys []Key
+	for len(b) > 0 {
+		if b[0] == 0x1b {
+			if len(b) == 1 {
+				return append(keys, Key{Code: KeyEsc})
+			}
+			// CSI (ESC [) and SS3 (ESC O) sequences
+			if (b[1] == '[' || b[1] == 'O') && len(b) > 2 {
+				switch b[2] {
+				case 'A':
+					keys = append(keys, Key{Code: KeyUp})
+				case 'B':
+					keys = append(keys, Key{Code: KeyDown})
+				case 'Z':
+					keys = append(keys, Key{Code: KeyShiftTab})
+				default:
+					keys = append(keys, Key{Code: KeyUnknown})
+				}
+				b = b[3:]
+				continue
+			}
+			keys = append(keys, Key{Code: KeyEsc})
+			b = b[1:]
+			continue
+		}
+		if code, ok := controlKeys[b[0]]; ok {
+			keys = append(keys, Key{Code: code})
+			b = b[1:]
+			continue
+		}
+		r, size := utf8.DecodeRune(b)
+		code := KeyRune
+		if r == utf8.RuneError || r < 0x20 {
+			code = KeyUnknown
+		}
+		keys = append(keys, Key{Code: code, Rune: r})
+		b = b[size:]
+	}
+	return keys
+}
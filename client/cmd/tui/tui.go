@@ -0,0 +1,486 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/term"
+)
+
+const (
+	enterAltScreen = "\x1b[?1049h"
+	exitAltScreen  = "\x1b[?1049l"
+	hideCursor     = "\x1b[?25l"
+	showCursor     = "\x1b[?25h"
+	clearScreen    = "\x1b[H\x1b[2J"
+
+	defaultAccessDuration = "30m"
+)
+
+var (
+	titleStyle     = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("204"))
+	activeTabStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("0")).Background(lipgloss.Color("204"))
+	tabStyle       = lipgloss.NewStyle().Faint(true)
+	headerStyle    = lipgloss.NewStyle().Faint(true).Bold(true)
+	selectedStyle  = lipgloss.NewStyle().Bold(true).Background(lipgloss.Color("235")).Foreground(lipgloss.Color("204"))
+	warnStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("#DBAB79"))
+	faintStyle     = lipgloss.NewStyle().Faint(true)
+)
+
+type Connection struct {
+	Name           string
+	Type           string
+	SubType        string
+	AgentStatus    string
+	ReviewRequired bool
+	ConnectEnabled bool
+	ExecEnabled    bool
+}
+
+// DisplayType returns the subtype of the connection, falling back to its type
+func (c Connection) DisplayType() string {
+	if c.SubType != "" {
+		return c.SubType
+	}
+	return c.Type
+}
+
+type Review struct {
+	ID         string
+	Type       string
+	Status     string
+	Connection string
+	Input      string
+	CreatedAt  time.Time
+}
+
+type Session struct {
+	ID         string
+	Connection string
+	Verb       string
+	Status     string
+	ExitCode   *int
+	StartDate  time.Time
+}
+
+// Data is the content displayed by the interface
+type Data struct {
+	ApiURL      string
+	UserEmail   string
+	Connections []Connection
+	Reviews     []Review
+	Sessions    []Session
+	// the errors of loading the reviews and sessions, they're displayed in their tabs
+	ReviewsError  string
+	SessionsError string
+}
+
+type ActionType string
+
+const (
+	ActionConnect       ActionType = "connect"
+	ActionExec          ActionType = "exec"
+	ActionRequestAccess ActionType = "request-access"
+)
+
+// Action is the operation selected by the user in a connection
+type Action struct {
+	Type       ActionType
+	Connection string
+	// Duration is the access duration requested by the user (request-access only)
+	Duration string
+}
+
+type tab int
+
+const (
+	tabConnections tab = iota
+	tabReviews
+	tabSessions
+)
+
+var tabNames = []string{"Connections", "Reviews", "Sessions"}
+
+type App struct {
+	data    *Data
+	tab     tab
+	query   string
+	cursors [3]int
+	// durationPrompt is the input of the access duration, nil when it's not prompting
+	durationPrompt *string
+	message        string
+	width          int
+	height         int
+}
+
+func New(data *Data) *App {
+	sort.SliceStable(data.Connections, func(i, j int) bool { return data.Connections[i].Name < data.Connections[j].Name })
+	return &App{data: data, width: 80, height: 24}
+}
+
+// Run opens the interface in the terminal, it returns the action selected
+// by the user or an empty value when the user quits
+func Run(data *Data) (*Action, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return nil, errors.New("the interactive mode requires a terminal")
+	}
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, fmt.Errorf("failed configuring terminal, reason=%v", err)
+	}
+	defer term.Restore(fd, oldState)
+	fmt.Fprint(os.Stdout, enterAltScreen+hideCursor)
+	defer fmt.Fprint(os.Stdout, showCursor+exitAltScreen)
+
+	app := New(data)
+	buf := make([]byte, 256)
+	for {
+		if width, height, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+			app.width, app.height = width, height
+		}
+		fmt.Fprint(os.Stdout, clearScreen+app.Render())
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading input, reason=%v", err)
+		}
+		for _, key := range parseKeys(buf[:n]) {
+			if action, quit := app.HandleKey(key); quit {
+				return action, nil
+			}
+		}
+	}
+}
+
+// HandleKey updates the state of the interface, it returns true when the interface must be closed.
+// The returned action is empty when the user quits without selecting one.
+func (a *App) HandleKey(k Key) (*Action, bool) {
+	a.message = ""
+	if k.Code == KeyCtrlC {
+		return nil, true
+	}
+	if a.durationPrompt != nil {
+		return a.handlePromptKey(k)
+	}
+	switch k.Code {
+	case KeyTab:
+		a.tab = (a.tab + 1) % tab(len(tabNames))
+	case KeyShiftTab:
+		a.tab = (a.tab + tab(len(tabNames)) - 1) % tab(len(tabNames))
+	case KeyUp, KeyCtrlP:
+		a.moveCursor(-1)
+	case KeyDown, KeyCtrlN:
+		a.moveCursor(1)
+	case KeyEsc:
+		if a.tab == tabConnections && a.query != "" {
+			a.setQuery("")
+			return nil, false
+		}
+		return nil, true
+	}
+	if a.tab != tabConnections {
+		return nil, false
+	}
+
+	switch k.Code {
+	case KeyRune:
+		a.setQuery(a.query + string(k.Rune))
+	case KeyBackspace:
+		if q := []rune(a.query); len(q) > 0 {
+			a.setQuery(string(q[:len(q)-1]))
+		}
+	case KeyCtrlU:
+		a.setQuery("")
+	case KeyEnter, KeyCtrlE, KeyCtrlR:
+		conn, ok := a.selectedConnection()
+		if !ok {
+			a.message = "no connection selected"
+			return nil, false
+		}
+		return a.connectionAction(k.Code, conn)
+	}
+	return nil, false
+}
+
+func (a *App) connectionAction(code KeyCode, conn Connection) (*Action, bool) {
+	switch code {
+	case KeyEnter:
+		if !conn.ConnectEnabled {
+			a.message = fmt.Sprintf("connect is disabled for %v", conn.Name)
+			return nil, false
+		}
+		return &Action{Type: ActionConnect, Connection: conn.Name}, true
+	case KeyCtrlE:
+		if !conn.ExecEnabled {
+			a.message = fmt.Sprintf("exec is disabled for %v", conn.Name)
+			return nil, false
+		}
+		return &Action{Type: ActionExec, Connection: conn.Name}, true
+	case KeyCtrlR:
+		if !conn.ReviewRequired {
+			a.message = fmt.Sprintf("%v does not require review, press enter to connect", conn.Name)
+			return nil, false
+		}
+		if !conn.ConnectEnabled {
+			a.message = fmt.Sprintf("connect is disabled for %v", conn.Name)
+			return nil, false
+		}
+		duration := defaultAccessDuration
+		a.durationPrompt = &duration
+	}
+	return nil, false
+}
+
+func (a *App) handlePromptKey(k Key) (*Action, bool) {
+	switch k.Code {
+	case KeyEsc:
+		a.durationPrompt = nil
+	case KeyRune:
+		*a.durationPrompt += string(k.Rune)
+	case KeyBackspace:
+		if d := []rune(*a.durationPrompt); len(d) > 0 {
+			*a.durationPrompt = string(d[:len(d)-1])
+		}
+	case KeyCtrlU:
+		*a.durationPrompt = ""
+	case KeyEnter:
+		dur, err := time.ParseDuration(*a.durationPrompt)
+		if err != nil {
+			a.message = "invalid duration, valid units are 's', 'm', 'h'. E.g.: 60s|3m|1h"
+			return nil, false
+		}
+		if dur < time.Minute {
+			a.message = "the minimum duration is 60 seconds (60s)"
+			return nil, false
+		}
+		conn, ok := a.selectedConnection()
+		if !ok {
+			a.durationPrompt = nil
+			return nil, false
+		}
+		return &Action{Type: ActionRequestAccess, Connection: conn.Name, Duration: *a.durationPrompt}, true
+	}
+	return nil, false
+}
+
+func (a *App) setQuery(query string) {
+	a.query = query
+	a.cursors[tabConnections] = 0
+}
+
+func (a *App) moveCursor(delta int) {
+	size := a.itemsLen()
+	cursor := a.cursors[a.tab] + delta
+	if cursor >= size {
+		cursor = size - 1
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
+	a.cursors[a.tab] = cursor
+}
+
+func (a *App) itemsLen() int {
+	switch a.tab {
+	case tabReviews:
+		return len(a.data.Reviews)
+	case tabSessions:
+		return len(a.data.Sessions)
+	}
+	return len(filterConnections(a.query, a.data.Connections))
+}
+
+func (a *App) selectedConnection() (Connection, bool) {
+	items := filterConnections(a.query, a.data.Connections)
+	cursor := a.cursors[tabConnections]
+	if cursor >= len(items) {
+		return Connection{}, false
+	}
+	return items[cursor], true
+}
+
+// Render returns the content of the screen, the lines are terminated
+// with carriage returns because the terminal is in raw mode
+func (a *App) Render() string {
+	var lines []string
+	var tabs []string
+	for i, name := range tabNames {
+		label := fmt.Sprintf(" %s (%d) ", name, a.tabItems(tab(i)))
+		if tab(i) == a.tab {
+			tabs = append(tabs, activeTabStyle.Render(label))
+			continue
+		}
+		tabs = append(tabs, tabStyle.Render(label))
+	}
+	lines = append(lines, titleStyle.Render("hoop")+" "+faintStyle.Render(a.data.UserEmail)+"  "+strings.Join(tabs, " "))
+
+	var header string
+	var rows []string
+	switch a.tab {
+	case tabConnections:
+		lines = append(lines, "> "+a.query+"_")
+		header, rows = a.connectionRows()
+	case tabReviews:
+		lines = append(lines, warnStyle.Render(a.data.ReviewsError))
+		header, rows = a.reviewRows()
+	case tabSessions:
+		lines = append(lines, warnStyle.Render(a.data.SessionsError))
+		header, rows = a.sessionRows()
+	}
+	lines = append(lines, headerStyle.Render(truncate(header, a.width)))
+
+	// keep the selected row visible, the header takes 3 lines and the footer 2 lines
+	listHeight := max(a.height-5, 1)
+	cursor := a.cursors[a.tab]
+	start := max(cursor-listHeight+1, 0)
+	for i := start; i < len(rows) && i < start+listHeight; i++ {
+		row := pad(rows[i], a.width)
+		if i == cursor {
+			lines = append(lines, selectedStyle.Render(row))
+			continue
+		}
+		lines = append(lines, a.styleRow(i, row))
+	}
+	for i := len(lines); i < a.height-2; i++ {
+		lines = append(lines, "")
+	}
+	lines = append(lines, "", a.footer())
+	return strings.Join(lines, "\r\n")
+}
+
+func (a *App) tabItems(t tab) int {
+	switch t {
+	case tabReviews:
+		return len(a.data.Reviews)
+	case tabSessions:
+		return len(a.data.Sessions)
+	}
+	return len(a.data.Connections)
+}
+
+func (a *App) styleRow(i int, row string) string {
+	if a.tab != tabConnections {
+		return row
+	}
+	items := filterConnections(a.query, a.data.Connections)
+	if i < len(items) && items[i].AgentStatus != "online" {
+		return faintStyle.Render(row)
+	}
+	return row
+}
+
+func (a *App) footer() string {
+	if a.durationPrompt != nil {
+		return fmt.Sprintf("access duration: %s_   %s", *a.durationPrompt,
+			faintStyle.Render("enter request access · esc cancel"))
+	}
+	if a.message != "" {
+		return warnStyle.Render(a.message)
+	}
+	switch a.tab {
+	case tabReviews:
+		if a.cursors[tabReviews] < len(a.data.Reviews) {
+			review := a.data.Reviews[a.cursors[tabReviews]]
+			return faintStyle.Render(fmt.Sprintf("%s/reviews/%s · tab switch · esc quit", a.data.ApiURL, review.ID))
+		}
+	case tabSessions:
+		if a.cursors[tabSessions] < len(a.data.Sessions) {
+			session := a.data.Sessions[a.cursors[tabSessions]]
+			return faintStyle.Render(fmt.Sprintf("hoop session replay %s · tab switch · esc quit", session.ID))
+		}
+	case tabConnections:
+		return faintStyle.Render("enter connect · ctrl+e exec · ctrl+r request access · tab switch · esc quit")
+	}
+	return faintStyle.Render("tab switch · esc quit")
+}
+
+func (a *App) connectionRows() (string, []string) {
+	items := filterConnections(a.query, a.data.Connections)
+	nameSize := 4
+	for _, conn := range items {
+		nameSize = max(nameSize, len([]rune(conn.Name)))
+	}
+	nameSize = min(nameSize, 40)
+	format := fmt.Sprintf("%%-%ds   %%-16s   %%-8s   %%-8s", nameSize)
+	header := fmt.Sprintf(format, "NAME", "TYPE", "AGENT", "REVIEW")
+	var rows []string
+	for _, conn := range items {
+		review := "-"
+		if conn.ReviewRequired {
+			review = "required"
+		}
+		agentStatus := conn.AgentStatus
+		if agentStatus == "" {
+			agentStatus = "-"
+		}
+		rows = append(rows, fmt.Sprintf(format, truncate(conn.Name, nameSize), truncate(conn.DisplayType(), 16), agentStatus, review))
+	}
+	if len(items) == 0 {
+		rows = append(rows, "no connections found")
+	}
+	return header, rows
+}
+
+func (a *App) reviewRows() (string, []string) {
+	format := "%-36s   %-20s   %-11s   %-8s   %-20s   %s"
+	header := fmt.Sprintf(format, "ID", "CONNECTION", "TYPE", "STATUS", "CREATED AT", "INPUT")
+	var rows []string
+	for _, r := range a.data.Reviews {
+		input := strings.Join(strings.Fields(r.Input), " ")
+		if input == "" {
+			input = "-"
+		}
+		rows = append(rows, fmt.Sprintf(format, r.ID, truncate(r.Connection, 20), r.Type, r.Status,
+			formatTime(r.CreatedAt), truncate(input, 40)))
+	}
+	if len(rows) == 0 && a.data.ReviewsError == "" {
+		rows = append(rows, "no pending reviews")
+	}
+	return header, rows
+}
+
+func (a *App) sessionRows() (string, []string) {
+	format := "%-36s   %-20s   %-7s   %-7s   %-4s   %s"
+	header := fmt.Sprintf(format, "ID", "CONNECTION", "VERB", "STATUS", "EXIT", "STARTED AT")
+	var rows []string
+	for _, s := range a.data.Sessions {
+		exitCode := "-"
+		if s.ExitCode != nil {
+			exitCode = fmt.Sprintf("%d", *s.ExitCode)
+		}
+		rows = append(rows, fmt.Sprintf(format, s.ID, truncate(s.Connection, 20), s.Verb, s.Status,
+			exitCode, formatTime(s.StartDate)))
+	}
+	if len(rows) == 0 && a.data.SessionsError == "" {
+		rows = append(rows, "no sessions found")
+	}
+	return header, rows
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+func truncate(s string, size int) string {
+	r := []rune(s)
+	if len(r) <= size {
+		return s
+	}
+	if size <= 1 {
+		return string(r[:max(size, 0)])
+	}
+	return string(r[:size-1]) + "…"
+}
+
+func pad(s string, size int) string {
+	s = truncate(s, size)
+	return s + strings.Repeat(" ", max(size-len([]rune(s)), 0))
+}
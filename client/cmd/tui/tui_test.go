@@ -0,0 +1,151 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func testData() *Data {
+	return &Data{Connections: []Connection{
+		{Name: "pg-prod", Type: "database", SubType: "postgres", AgentStatus: "online", ReviewRequired: true, ConnectEnabled: true, ExecEnabled: true},
+		{Name: "mysql-staging", Type: "database", SubType: "mysql", AgentStatus: "online", ConnectEnabled: true, ExecEnabled: true},
+		{Name: "bash", Type: "custom", AgentStatus: "offline", ConnectEnabled: true},
+	}}
+}
+
+func typeKeys(s string) []Key {
+	var keys []Key
+	for _, r := range s {
+		keys = append(keys, Key{Code: KeyRune, Rune: r})
+	}
+	return keys
+}
+
+func TestParseKeys(t *testing.T) {
+	for _, tt := range []struct {
+		msg   string
+		input string
+		want  []Key
+	}{
+		{msg: "it must parse runes", input: "pgé", want: []Key{{KeyRune, 'p'}, {KeyRune, 'g'}, {KeyRune, 'é'}}},
+		{msg: "it must parse the arrows", input: "\x1b[A\x1b[B\x1bOA", want: []Key{{Code: KeyUp}, {Code: KeyDown}, {Code: KeyUp}}},
+		{msg: "it must parse a lone escape", input: "\x1b", want: []Key{{Code: KeyEsc}}},
+		{msg: "it must parse shift tab", input: "\x1b[Z", want: []Key{{Code: KeyShiftTab}}},
+		{msg: "it must parse the control keys", input: "\r\t\x7f\x03\x05\x12", want: []Key{
+			{Code: KeyEnter}, {Code: KeyTab}, {Code: KeyBackspace}, {Code: KeyCtrlC}, {Code: KeyCtrlE}, {Code: KeyCtrlR}}},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, parseKeys([]byte(tt.input))); diff != "" {
+				t.Errorf("keys mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFilterConnections(t *testing.T) {
+	for _, tt := range []struct {
+		msg   string
+		query string
+		want  []string
+	}{
+		{msg: "it must return all connections with an empty query", query: "", want: []string{"pg-prod", "mysql-staging", "bash"}},
+		{msg: "it must match characters in order", query: "msg", want: []string{"mysql-staging"}},
+		{msg: "it must match case insensitive", query: "PGP", want: []string{"pg-prod"}},
+		{msg: "it must match by the type of the connection", query: "postgres", want: []string{"pg-prod"}},
+		{msg: "it must rank consecutive matches first", query: "st", want: []string{"mysql-staging", "pg-prod", "bash"}},
+		{msg: "it must return nothing when characters are not in order", query: "dp", want: []string{}},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			got := []string{}
+			for _, conn := range filterConnections(tt.query, testData().Connections) {
+				got = append(got, conn.Name)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("connections mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHandleKey(t *testing.T) {
+	for _, tt := range []struct {
+		msg         string
+		keys        []Key
+		wantAction  *Action
+		wantQuit    bool
+		wantMessage string
+	}{
+		{
+			msg:        "it must connect to the first match of the search",
+			keys:       append(typeKeys("mysql"), Key{Code: KeyEnter}),
+			wantAction: &Action{Type: ActionConnect, Connection: "mysql-staging"},
+			wantQuit:   true,
+		},
+		{
+			msg:        "it must exec in the selected connection",
+			keys:       []Key{{Code: KeyDown}, {Code: KeyDown}, {Code: KeyUp}, {Code: KeyCtrlE}},
+			wantAction: &Action{Type: ActionExec, Connection: "mysql-staging"},
+			wantQuit:   true,
+		},
+		{
+			msg:         "it must not exec when it's disabled",
+			keys:        append(typeKeys("bash"), Key{Code: KeyCtrlE}),
+			wantMessage: "exec is disabled for bash",
+		},
+		{
+			msg:        "it must request access with the duration of the prompt",
+			keys:       append(typeKeys("pg"), Key{Code: KeyCtrlR}, Key{Code: KeyBackspace}, Key{Code: KeyBackspace}, Key{Code: KeyBackspace}, Key{Code: KeyRune, Rune: '2'}, Key{Code: KeyRune, Rune: 'h'}, Key{Code: KeyEnter}),
+			wantAction: &Action{Type: ActionRequestAccess, Connection: "pg-prod", Duration: "2h"},
+			wantQuit:   true,
+		},
+		{
+			msg:         "it must validate the duration of the prompt",
+			keys:        append(typeKeys("pg"), Key{Code: KeyCtrlR}, Key{Code: KeyCtrlU}, Key{Code: KeyRune, Rune: '3'}, Key{Code: KeyRune, Rune: '0'}, Key{Code: KeyRune, Rune: 's'}, Key{Code: KeyEnter}),
+			wantMessage: "the minimum duration is 60 seconds (60s)",
+		},
+		{
+			msg:         "it must not request access to connections without review",
+			keys:        append(typeKeys("mysql"), Key{Code: KeyCtrlR}),
+			wantMessage: "mysql-staging does not require review, press enter to connect",
+		},
+		{
+			msg:         "it must not select a connection when nothing matches",
+			keys:        append(typeKeys("zzz"), Key{Code: KeyEnter}),
+			wantMessage: "no connection selected",
+		},
+		{
+			msg:  "it must clear the search with esc",
+			keys: append(typeKeys("zzz"), Key{Code: KeyEsc}),
+		},
+		{
+			msg:      "it must quit with esc when the search is empty",
+			keys:     []Key{{Code: KeyEsc}},
+			wantQuit: true,
+		},
+		{
+			msg:  "it must not select connections in other tabs",
+			keys: []Key{{Code: KeyTab}, {Code: KeyEnter}, {Code: KeyRune, Rune: 'p'}},
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			app := New(testData())
+			var action *Action
+			var quit bool
+			for _, k := range tt.keys {
+				if action, quit = app.HandleKey(k); quit {
+					break
+				}
+			}
+			if diff := cmp.Diff(tt.wantAction, action); diff != "" {
+				t.Errorf("action mismatch (-want +got):\n%s", diff)
+			}
+			if quit != tt.wantQuit {
+				t.Errorf("expected quit=%v, got=%v", tt.wantQuit, quit)
+			}
+			if app.message != tt.wantMessage {
+				t.Errorf("expected message=%q, got=%q", tt.wantMessage, app.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/briandowns/spinner"
+	"github.com/hoophq/hoop/client/cmd/tui"
+	clientconfig "github.com/hoophq/hoop/client/config"
+	"github.com/hoophq/hoop/common/httpclient"
+	"github.com/hoophq/hoop/common/log"
+	"github.com/spf13/cobra"
+)
+
+const uiSessionsLimit = 20
+
+var uiCmd = &cobra.Command{
+	Use:     "ui",
+	Aliases: []string{"tui"},
+	Short:   "Browse and open connections in an interactive terminal interface",
+	Long: `Browse the connections you have access to with fuzzy search, and connect,
+execute a script written in $EDITOR or request just-in-time access to them.
+It also displays your pending reviews and your recent sessions.
+
+Keys:
+  type to search, up/down to select, tab to switch between connections, reviews and sessions
+  enter    connect to the selected connection
+  ctrl+e   write a script in $EDITOR and execute it in the selected connection
+  ctrl+r   request just-in-time access to the selected connection
+  esc      clear the search or quit`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runUI()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(uiCmd)
+}
+
+func runUI() {
+	config := clientconfig.GetClientConfigOrDie()
+	loader := spinner.New(spinner.CharSets[11], 70*time.Millisecond, spinner.WithWriter(os.Stderr))
+	loader.Color("green")
+	loader.Suffix = " loading connections ..."
+	loader.Start()
+	data, err := fetchUIData(config)
+	loader.Stop()
+	if err != nil {
+		printErrorAndExit(err.Error())
+	}
+	action, err := tui.Run(data)
+	if err != nil {
+		printErrorAndExit(err.Error())
+	}
+	if action == nil {
+		return
+	}
+	clientEnvVars, err := parseClientEnvVars()
+	if err != nil {
+		printErrorAndExit(err.Error())
+	}
+	args := []string{action.Connection}
+	switch action.Type {
+	case tui.ActionConnect:
+		runConnect(args, clientEnvVars)
+	case tui.ActionRequestAccess:
+		connectFlags.duration = action.Duration
+		runConnect(args, clientEnvVars)
+	case tui.ActionExec:
+		input, err := readInputFromEditor(action.Connection)
+		if err != nil {
+			printErrorAndExit(err.Error())
+		}
+		if strings.TrimSpace(input) == "" {
+			printErrorAndExit("the script is empty, nothing to execute")
+		}
+		inputStdin = input
+		runExec(args, clientEnvVars)
+	}
+}
+
+// readInputFromEditor opens a temporary file in the editor of the env EDITOR (defaults to vi)
+// and returns its content when the editor exits
+func readInputFromEditor(connectionName string) (string, error) {
+	editor := strings.Fields(os.Getenv("EDITOR"))
+	if len(editor) == 0 {
+		editor = []string{"vi"}
+	}
+	f, err := os.CreateTemp("", fmt.Sprintf("hoop-exec-%s-*.txt", url.PathEscape(connectionName)))
+	if err != nil {
+		return "", fmt.Errorf("failed creating temporary file, reason=%v", err)
+	}
+	_ = f.Close()
+	defer os.Remove(f.Name())
+
+	cmd := exec.Command(editor[0], append(editor[1:], f.Name())...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed running editor %q, reason=%v", strings.Join(editor, " "), err)
+	}
+	input, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed reading script file, reason=%v", err)
+	}
+	return string(input), nil
+}
+
+// fetchUIData loads the connections, the pending reviews and the recent sessions of the user.
+// The failures of loading the reviews and the sessions are displayed in the interface.
+func fetchUIData(c *clientconfig.Config) (*tui.Data, error) {
+	var userInfo struct {
+		ID    string `json:"id"`
+		Email string `json:"email"`
+	}
+	if err := uiHTTPRequest(c, "/api/userinfo", nil, &userInfo); err != nil {
+		return nil, err
+	}
+	var connections []struct {
+		Name              string   `json:"name"`
+		Type              string   `json:"type"`
+		SubType           string   `json:"subtype"`
+		Status            string   `json:"status"`
+		Reviewers         []string `json:"reviewers"`
+		AccessModeConnect string   `json:"access_mode_connect"`
+		AccessModeExec    string   `json:"access_mode_exec"`
+	}
+	if err := uiHTTPRequest(c, "/api/connections", nil, &connections); err != nil {
+		return nil, err
+	}
+	data := &tui.Data{ApiURL: c.ApiURL, UserEmail: userInfo.Email}
+	for _, conn := range connections {
+		data.Connections = append(data.Connections, tui.Connection{
+			Name:           conn.Name,
+			Type:           conn.Type,
+			SubType:        conn.SubType,
+			AgentStatus:    conn.Status,
+			ReviewRequired: len(conn.Reviewers) > 0,
+			ConnectEnabled: conn.AccessModeConnect != "disabled",
+			ExecEnabled:    conn.AccessModeExec != "disabled",
+		})
+	}
+
+	var reviews []struct {
+		ID         string    `json:"id"`
+		Type       string    `json:"type"`
+		Status     string    `json:"status"`
+		Input      string    `json:"input"`
+		CreatedAt  time.Time `json:"created_at"`
+		Connection struct {
+			Name string `json:"name"`
+		} `json:"review_connection"`
+	}
+	if err := uiHTTPRequest(c, "/api/reviews", nil, &reviews); err != nil {
+		log.Debugf("failed loading reviews: %v", err)
+		data.ReviewsError = "failed loading reviews"
+	}
+	for _, r := range reviews {
+		if r.Status != "PENDING" {
+			continue
+		}
+		data.Reviews = append(data.Reviews, tui.Review{
+			ID:         r.ID,
+			Type:       r.Type,
+			Status:     r.Status,
+			Connection: r.Connection.Name,
+			Input:      r.Input,
+			CreatedAt:  r.CreatedAt,
+		})
+	}
+
+	var sessions struct {
+		Items []struct {
+			ID         string    `json:"id"`
+			Connection string    `json:"connection"`
+			Verb       string    `json:"verb"`
+			Status     string    `json:"status"`
+			ExitCode   *int      `json:"exit_code"`
+			StartDate  time.Time `json:"start_date"`
+		} `json:"data"`
+	}
+	query := url.Values{"user": []string{userInfo.ID}, "limit": []string{fmt.Sprintf("%d", uiSessionsLimit)}}
+	if err := uiHTTPRequest(c, "/api/sessions", query, &sessions); err != nil {
+		log.Debugf("failed loading sessions: %v", err)
+		data.SessionsError = "failed loading sessions"
+	}
+	for _, s := range sessions.Items {
+		data.Sessions = append(data.Sessions, tui.Session{
+			ID:         s.ID,
+			Connection: s.Connection,
+			Verb:       s.Verb,
+			Status:     s.Status,
+			ExitCode:   s.ExitCode,
+			StartDate:  s.StartDate,
+		})
+	}
+	return data, nil
+}
+
+func uiHTTPRequest(c *clientconfig.Config, path string, query url.Values, into any) error {
+	apiURL := fmt.Sprintf("%s%s", c.ApiURL, path)
+	if len(query) > 0 {
+		apiURL += "?" + query.Encode()
+	}
+	req, err := http.NewRequest("GET", apiURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", c.Token))
+	if c.IsApiKey() {
+		req.Header.Set("Api-Key", c.Token)
+	}
+	resp, err := httpclient.NewHttpClient(c.TlsCA()).Do(req)
+	if err != nil {
+		return fmt.Errorf("failed performing request at %v, err=%v", path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed fetching %v, status-code=%v, payload=%v", path, resp.StatusCode, string(data))
+	}
+	return json.NewDecoder(resp.Body).Decode(into)
+}
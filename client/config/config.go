@@ -10,7 +10,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/BurntSushi/toml"
 	"github.com/google/uuid"
 	"github.com/hoophq/hoop/client/cmd/styles"
 	"github.com/hoophq/hoop/common/clientconfig"
@@ -30,17 +29,25 @@ type Config struct {
 	TlsCAB64Enc  string `toml:"tls_ca"`
 	Mode         string `toml:"-"`
 	InsecureGRPC bool   `toml:"-"`
-	filepath     string `toml:"-"`
+	// Profile is the name of the context loaded from the configuration file
+	Profile  string `toml:"-"`
+	filepath string `toml:"-"`
 }
 
-// NewConfigFile creates a new configuration in the filesystem
+// NewConfigFile creates a new configuration in the filesystem,
+// it's saved in the selected context of the configuration file
 func NewConfigFile(apiURL, grpcURL, token, tlsCA string) (string, error) {
 	filepath, err := clientconfig.NewPath(clientconfig.ClientFile)
 	if err != nil {
 		return "", err
 	}
+	fc, err := readFileConfig(filepath)
+	if err != nil {
+		return "", err
+	}
 	config := &Config{
 		filepath:    filepath,
+		Profile:     fc.selectedContext(),
 		Token:       token,
 		ApiURL:      apiURL,
 		GrpcURL:     grpcURL,
@@ -61,7 +68,7 @@ func Remove() error {
 
 // Load builds an client config file in the following order.
 // load the configuration based on environment variables  (HOOP_GRPCURL, HOOP_APIURL & HOOP_TOKEN)
-// load based in the configuration file $HOME/.hoop/client.toml.
+// load based in the selected context of the configuration file $HOME/.hoop/client.toml.
 // load a configuration file if localhost grpc port has connectivity.
 func Load() (*Config, error) {
 	// TODO: if env is set, use it
@@ -87,19 +94,31 @@ func Load() (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
-	var conf Config
-	if _, err := toml.DecodeFile(filepath, &conf); err != nil {
-		return nil, fmt.Errorf("failed decoding configuration file=%v, err=%v", filepath, err)
+	fc, err := readFileConfig(filepath)
+	if err != nil {
+		return nil, err
 	}
-
-	if !conf.isEmpty() {
+	contextName := fc.selectedContext()
+	if ctx := fc.getContext(contextName); ctx != nil {
+		conf := &Config{
+			Token:        ctx.Token,
+			ApiURL:       ctx.ApiURL,
+			GrpcURL:      ctx.GrpcURL,
+			TlsCAB64Enc:  ctx.TlsCAB64Enc,
+			Mode:         clientconfig.ModeConfigFile,
+			InsecureGRPC: hasInsecureScheme(ctx.GrpcURL),
+			Profile:      contextName,
+			filepath:     filepath,
+		}
 		if conf.TlsCAB64Enc == "" {
 			conf.TlsCAB64Enc = base64.StdEncoding.EncodeToString([]byte(tlsCA))
 		}
-		conf.Mode = clientconfig.ModeConfigFile
-		conf.filepath = filepath
-		conf.InsecureGRPC = hasInsecureScheme(conf.GrpcURL)
-		return &conf, nil
+		return conf, nil
+	}
+
+	// the context is created when the configuration is saved
+	if contextName != DefaultContext {
+		return &Config{filepath: filepath, Profile: contextName}, ErrEmpty
 	}
 
 	// fallback connecting to localhost without tls / authentication
@@ -116,7 +135,7 @@ func Load() (*Config, error) {
 			InsecureGRPC: true,
 		}, nil
 	}
-	return &Config{filepath: filepath}, ErrEmpty
+	return &Config{filepath: filepath, Profile: DefaultContext}, ErrEmpty
 }
 
 // GrpcClientConfig returns a configuration to connect to the gRPC server
@@ -131,7 +150,6 @@ func (c *Config) GrpcClientConfig() (grpc.ClientConfig, error) {
 	}, err
 }
 
-func (c *Config) isEmpty() bool  { return c.GrpcURL == "" && c.ApiURL == "" }
 func (c *Config) IsValid() bool  { return c.ApiURL != "" }
 func (c *Config) HasToken() bool { return c.Mode == clientconfig.ModeLocal || c.Token != "" }
 func (c *Config) IsApiKey() bool {
@@ -154,12 +172,20 @@ func (c *Config) Save() (bool, error) {
 		return false, nil
 	}
 	debugTokenClaims(c.Token)
-	confBuffer := bytes.NewBuffer([]byte{})
-	if err := toml.NewEncoder(confBuffer).Encode(c); err != nil {
-		return false, fmt.Errorf("failed saving config to %s, encode-err=%v", c.filepath, err)
-	}
-	if err := os.WriteFile(c.filepath, confBuffer.Bytes(), 0600); err != nil {
-		return false, fmt.Errorf("failed saving config to %s, err=%v", c.filepath, err)
+	// the other contexts of the file are preserved
+	fc, err := readFileConfig(c.filepath)
+	if err != nil {
+		return false, err
+	}
+	fc.setContext(&Context{
+		Name:        c.Profile,
+		Token:       c.Token,
+		ApiURL:      c.ApiURL,
+		GrpcURL:     c.GrpcURL,
+		TlsCAB64Enc: c.TlsCAB64Enc,
+	})
+	if err := fc.write(c.filepath); err != nil {
+		return false, err
 	}
 	return true, nil
 }
@@ -184,12 +210,17 @@ func debugTokenClaims(jwtToken string) {
 func GetClientConfigOrDie() *Config {
 	config, err := Load()
 	switch err {
-	case ErrEmpty, nil:
+	case ErrEmpty:
+		if config.Profile != DefaultContext {
+			styles.PrintErrorAndExit("context %q not found, run 'hoop login --profile %v' to create it",
+				config.Profile, config.Profile)
+		}
+	case nil:
 	default:
 		styles.PrintErrorAndExit(err.Error())
 	}
-	log.Debugf("loaded clientconfig, mode=%v, grpc-tls=%v, api_url=%v, grpc_url=%v, tokenlength=%v, tlsca=%v",
-		config.Mode, !config.InsecureGRPC, config.ApiURL, config.GrpcURL, len(config.Token), config.TlsCAB64Enc != "")
+	log.Debugf("loaded clientconfig, mode=%v, profile=%v, grpc-tls=%v, api_url=%v, grpc_url=%v, tokenlength=%v, tlsca=%v",
+		config.Mode, config.Profile, !config.InsecureGRPC, config.ApiURL, config.GrpcURL, len(config.Token), config.TlsCAB64Enc != "")
 	return config
 }
 
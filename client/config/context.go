@@ -0,0 +1,175 @@
+package clientconfig
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/BurntSushi/toml"
+	"github.com/hoophq/hoop/common/clientconfig"
+)
+
+// DefaultContext is the context stored in the top level attributes of the configuration file
+const DefaultContext = "default"
+
+// profileName is the context selected with the --profile flag
+var profileName string
+
+// SetProfile selects the context of the configuration file to load,
+// it overrides the env HOOP_PROFILE and the current context of the file.
+func SetProfile(name string) { profileName = name }
+
+// Context is a named gateway configuration
+type Context struct {
+	Name        string `toml:"-"`
+	Token       string `toml:"token"`
+	ApiURL      string `toml:"api_url"`
+	GrpcURL     string `toml:"grpc_url"`
+	TlsCAB64Enc string `toml:"tls_ca"`
+}
+
+func (c *Context) isEmpty() bool { return c.GrpcURL == "" && c.ApiURL == "" }
+
+// fileConfig is the format of the configuration file. The top level attributes
+// are the default context, it keeps the file compatible with previous versions.
+type fileConfig struct {
+	Token          string              `toml:"token"`
+	ApiURL         string              `toml:"api_url"`
+	GrpcURL        string              `toml:"grpc_url"`
+	TlsCAB64Enc    string              `toml:"tls_ca"`
+	CurrentContext string              `toml:"current_context,omitempty"`
+	Contexts       map[string]*Context `toml:"contexts,omitempty"`
+}
+
+func readFileConfig(filepath string) (*fileConfig, error) {
+	var fc fileConfig
+	if _, err := toml.DecodeFile(filepath, &fc); err != nil {
+		return nil, fmt.Errorf("failed decoding configuration file=%v, err=%v", filepath, err)
+	}
+	return &fc, nil
+}
+
+func (fc *fileConfig) write(filepath string) error {
+	confBuffer := bytes.NewBuffer([]byte{})
+	if err := toml.NewEncoder(confBuffer).Encode(fc); err != nil {
+		return fmt.Errorf("failed saving config to %s, encode-err=%v", filepath, err)
+	}
+	if err := os.WriteFile(filepath, confBuffer.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed saving config to %s, err=%v", filepath, err)
+	}
+	return nil
+}
+
+// selectedContext returns the name of the context to use in the following order:
+// the --profile flag, the env HOOP_PROFILE and the current context of the file
+func (fc *fileConfig) selectedContext() string {
+	for _, name := range []string{profileName, os.Getenv("HOOP_PROFILE"), fc.CurrentContext} {
+		if name != "" {
+			return name
+		}
+	}
+	return DefaultContext
+}
+
+// getContext returns the context by its name, it's nil when it does not exist
+func (fc *fileConfig) getContext(name string) *Context {
+	if name == DefaultContext {
+		ctx := &Context{Name: DefaultContext, Token: fc.Token, ApiURL: fc.ApiURL, GrpcURL: fc.GrpcURL, TlsCAB64Enc: fc.TlsCAB64Enc}
+		if ctx.isEmpty() {
+			return nil
+		}
+		return ctx
+	}
+	ctx, ok := fc.Contexts[name]
+	if !ok || ctx == nil {
+		return nil
+	}
+	ctx.Name = name
+	return ctx
+}
+
+func (fc *fileConfig) setContext(ctx *Context) {
+	if ctx.Name == "" || ctx.Name == DefaultContext {
+		fc.Token, fc.ApiURL, fc.GrpcURL, fc.TlsCAB64Enc = ctx.Token, ctx.ApiURL, ctx.GrpcURL, ctx.TlsCAB64Enc
+		return
+	}
+	if fc.Contexts == nil {
+		fc.Contexts = map[string]*Context{}
+	}
+	fc.Contexts[ctx.Name] = ctx
+}
+
+func (fc *fileConfig) listContexts() []Context {
+	var items []Context
+	if ctx := fc.getContext(DefaultContext); ctx != nil {
+		items = append(items, *ctx)
+	}
+	var names []string
+	for name := range fc.Contexts {
+		if name != DefaultContext {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if ctx := fc.getContext(name); ctx != nil {
+			items = append(items, *ctx)
+		}
+	}
+	return items
+}
+
+// ListContexts returns the contexts of the configuration file and the name of the selected one
+func ListContexts() ([]Context, string, error) {
+	filepath, err := clientconfig.NewPath(clientconfig.ClientFile)
+	if err != nil {
+		return nil, "", err
+	}
+	fc, err := readFileConfig(filepath)
+	if err != nil {
+		return nil, "", err
+	}
+	return fc.listContexts(), fc.selectedContext(), nil
+}
+
+// UseContext sets the current context of the configuration file
+func UseContext(name string) error {
+	filepath, err := clientconfig.NewPath(clientconfig.ClientFile)
+	if err != nil {
+		return err
+	}
+	fc, err := readFileConfig(filepath)
+	if err != nil {
+		return err
+	}
+	if fc.getContext(name) == nil {
+		return fmt.Errorf("context %q not found", name)
+	}
+	fc.CurrentContext = name
+	return fc.write(filepath)
+}
+
+// RemoveContext removes a context from the configuration file, the
+// current context is reset to the default one when it's removed
+func RemoveContext(name string) error {
+	filepath, err := clientconfig.NewPath(clientconfig.ClientFile)
+	if err != nil {
+		return err
+	}
+	fc, err := readFileConfig(filepath)
+	if err != nil {
+		return err
+	}
+	if fc.getContext(name) == nil {
+		return fmt.Errorf("context %q not found", name)
+	}
+	if name == DefaultContext {
+		fc.setContext(&Context{Name: DefaultContext})
+	}
+	delete(fc.Contexts, name)
+	if fc.CurrentContext == name {
+		fc.CurrentContext = ""
+	}
+	return fc.write(filepath)
+}
@@ -0,0 +1,61 @@
+package clientconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSelectedContext(t *testing.T) {
+	for _, tt := range []struct {
+		msg     string
+		profile string
+		env     string
+		current string
+		want    string
+	}{
+		{msg: "it must use the default context", want: DefaultContext},
+		{msg: "it must use the current context of the file", current: "staging", want: "staging"},
+		{msg: "it must use the env over the current context", env: "prod", current: "staging", want: "prod"},
+		{msg: "it must use the profile flag over the env", profile: "dev", env: "prod", current: "staging", want: "dev"},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			SetProfile(tt.profile)
+			defer SetProfile("")
+			t.Setenv("HOOP_PROFILE", tt.env)
+			fc := &fileConfig{CurrentContext: tt.current}
+			if got := fc.selectedContext(); got != tt.want {
+				t.Errorf("expected context %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSaveContexts(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.toml")
+	// a configuration file of previous versions is the default context
+	legacyConfig := "token = \"tk-prod\"\napi_url = \"https://prod.hoop.dev\"\ngrpc_url = \"prod.hoop.dev:8443\"\ntls_ca = \"\"\n"
+	if err := os.WriteFile(configFile, []byte(legacyConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	staging := &Config{Profile: "staging", Token: "tk-stg", ApiURL: "https://stg.hoop.dev", GrpcURL: "stg.hoop.dev:8443", filepath: configFile}
+	if _, err := staging.Save(); err != nil {
+		t.Fatalf("failed saving config: %v", err)
+	}
+	fc, err := readFileConfig(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Context{
+		{Name: DefaultContext, Token: "tk-prod", ApiURL: "https://prod.hoop.dev", GrpcURL: "prod.hoop.dev:8443"},
+		{Name: "staging", Token: "tk-stg", ApiURL: "https://stg.hoop.dev", GrpcURL: "stg.hoop.dev:8443"},
+	}
+	if diff := cmp.Diff(want, fc.listContexts()); diff != "" {
+		t.Errorf("contexts mismatch (-want +got):\n%s", diff)
+	}
+	if got := fc.getContext("unknown"); got != nil {
+		t.Errorf("expected unknown context to be nil, got %v", got)
+	}
+}
@@ -16,6 +16,7 @@ const (
 	defaultMSSQLPort     = "1444"
 	defaultMySQLPort     = "3307"
 	defaultPostgresPort  = "5433"
+	defaultRedisPort     = "6380"
 	defaultTCPPort       = "8999"
 	defaultSSHPort       = "2222"
 	defaultHttpProxyPort = "8081"
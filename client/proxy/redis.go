@@ -0,0 +1,136 @@
+package proxy
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+
+	"github.com/hoophq/hoop/common/log"
+	"github.com/hoophq/hoop/common/memory"
+	pb "github.com/hoophq/hoop/common/proto"
+	pbagent "github.com/hoophq/hoop/common/proto/agent"
+	"github.com/hoophq/hoop/common/redistypes"
+)
+
+type RedisServer struct {
+	listenAddr      string
+	client          pb.ClientTransport
+	connectionStore memory.Store
+	listener        net.Listener
+}
+
+func NewRedisServer(proxyPort string, client pb.ClientTransport) *RedisServer {
+	listenAddr := defaultListenAddr(defaultRedisPort)
+	if proxyPort != "" {
+		listenAddr = defaultListenAddr(proxyPort)
+	}
+	return &RedisServer{
+		listenAddr:      listenAddr,
+		client:          client,
+		connectionStore: memory.New(),
+	}
+}
+
+func (p *RedisServer) Serve(sessionID string) error {
+	listenAddr := p.listenAddr
+	lis, err := net.Listen("tcp4", listenAddr)
+	if err != nil {
+		return fmt.Errorf("failed listening to address %v, err=%v", listenAddr, err)
+	}
+	p.listener = lis
+	go func() {
+		connectionID := 0
+		for {
+			connectionID++
+			conn, err := lis.Accept()
+			if err != nil {
+				log.Infof("failed obtain listening connection, err=%v", err)
+				lis.Close()
+				break
+			}
+			go p.serveConn(sessionID, strconv.Itoa(connectionID), conn)
+		}
+	}()
+	return nil
+}
+
+func (s *RedisServer) serveConn(sessionID, connectionID string, conn net.Conn) {
+	defer func() {
+		log.Infof("session=%v | conn=%s | client=%s - closing tcp connection",
+			sessionID, connectionID, conn.RemoteAddr())
+		s.connectionStore.Del(connectionID)
+		if err := conn.Close(); err != nil {
+			log.Warnf("failed closing client connection, err=%v", err)
+		}
+		_ = s.client.Send(&pb.Packet{
+			Type: pbagent.TCPConnectionClose,
+			Spec: map[string][]byte{
+				pb.SpecClientConnectionID: []byte(connectionID),
+				pb.SpecGatewaySessionID:   []byte(sessionID),
+			}})
+	}()
+	s.connectionStore.Set(connectionID, conn)
+	log.Infof("session=%v | conn=%s | client=%s - connected", sessionID, connectionID, conn.RemoteAddr())
+	stream := pb.NewStreamWriter(s.client, pbagent.RedisConnectionWrite, map[string][]byte{
+		string(pb.SpecClientConnectionID): []byte(connectionID),
+		string(pb.SpecGatewaySessionID):   []byte(sessionID),
+	})
+	if err := copyRedisBuffer(stream, conn); err != nil && err != io.EOF {
+		log.Warnf("failed copying buffer, err=%v", err)
+	}
+}
+
+func (s *RedisServer) PacketWriteClient(connectionID string, pkt *pb.Packet) (int, error) {
+	conn, err := s.getConnection(connectionID)
+	if err != nil {
+		log.Warnf("receive packet (length=%v) after connection (%v) is closed", len(pkt.Payload), connectionID)
+		return 0, nil
+	}
+	return conn.Write(pkt.Payload)
+}
+
+func (s *RedisServer) CloseTCPConnection(connectionID string) {
+	if conn, err := s.getConnection(connectionID); err == nil {
+		_ = conn.Close()
+	}
+}
+
+func (s *RedisServer) Close() error { return s.listener.Close() }
+
+func (s *RedisServer) getConnection(connectionID string) (io.WriteCloser, error) {
+	connectionObj := s.connectionStore.Get(connectionID)
+	conn, ok := connectionObj.(io.WriteCloser)
+	if !ok {
+		return nil, fmt.Errorf("local connection %q not found", connectionID)
+	}
+	return conn, nil
+}
+
+func (s *RedisServer) Host() Host { return getListenAddr(s.listenAddr) }
+
+// copyRedisBuffer writes only complete commands to dst, it allows the gateway to
+// decode each packet. Pipelined commands already buffered are sent in the same packet.
+func copyRedisBuffer(dst io.Writer, src io.Reader) error {
+	r := bufio.NewReaderSize(src, defaultBufferSize)
+	for {
+		pkt, err := redistypes.ReadCommand(r)
+		if err != nil {
+			return err
+		}
+		for r.Buffered() > 0 && len(pkt) < maxPacketSize {
+			cmd, err := redistypes.ReadCommand(r)
+			if err != nil {
+				return err
+			}
+			pkt = append(pkt, cmd...)
+		}
+		if len(pkt) > maxPacketSize {
+			return fmt.Errorf("max packet size reached (max:%v, pkt:%v)", maxPacketSize, len(pkt))
+		}
+		if _, err := dst.Write(pkt); err != nil {
+			return err
+		}
+	}
+}
@@ -18,6 +18,7 @@ const (
 	MySQLConnectionWrite     = "AgentMySQLConnectionWrite"
 	MSSQLConnectionWrite     = "AgentMSSQLConnectionWrite"
 	MongoDBConnectionWrite   = "AgentMongoDBConnectionWrite"
+	RedisConnectionWrite     = "AgentRedisConnectionWrite"
 	SSHConnectionWrite       = "AgentSSHConnectionWrite"
 	HttpProxyConnectionWrite = "AgentHttpProxyConnectionWrite"
 )
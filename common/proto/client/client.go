@@ -17,6 +17,7 @@ const (
 	MySQLConnectionWrite     = "ClientMySQLConnectionWrite"
 	MSSQLConnectionWrite     = "ClientMSSQLConnectionWrite"
 	MongoDBConnectionWrite   = "ClientMongoDBConnectionWrite"
+	RedisConnectionWrite     = "ClientRedisConnectionWrite"
 	SSHConnectionWrite       = "ClientSSHConnectionWrite"
 	WriteStdout              = "ClientWriteStdout"
 	WriteStderr              = "ClientWriteStderr"
@@ -32,6 +32,7 @@ const (
 	SpecGatewayJitID                 string = "jit.id"
 	SpecJitStatus                    string = "jit.status"
 	SpecJitTimeout                   string = "jit.timeout"
+	SpecBreakGlassReason             string = "breakglass.reason"
 
 	DefaultKeepAlive time.Duration = 10 * time.Second
 
@@ -41,6 +42,7 @@ const (
 	ConnectionTypeMySQL       ConnectionType = "mysql"
 	ConnectionTypeMSSQL       ConnectionType = "mssql"
 	ConnectionTypeMongoDB     ConnectionType = "mongodb"
+	ConnectionTypeRedis       ConnectionType = "redis"
 	ConnectionTypeOracleDB    ConnectionType = "oracledb"
 	ConnectionTypeTCP         ConnectionType = "tcp"
 	ConnectionTypeHttpProxy   ConnectionType = "httpproxy"
@@ -179,6 +179,8 @@ func ToConnectionType(connectionType, subtype string) ConnectionType {
 			return ConnectionType(ConnectionTypeMongoDB)
 		case "mssql":
 			return ConnectionType(ConnectionTypeMSSQL)
+		case "redis":
+			return ConnectionType(ConnectionTypeRedis)
 		case "oracledb":
 			return ConnectionType(ConnectionTypeOracleDB)
 		}
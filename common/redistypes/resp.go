@@ -0,0 +1,334 @@
+// Package redistypes decodes the redis serialization protocol (RESP).
+// The client commands are arrays of bulk strings or inline commands,
+// the server replies could be any RESP2 or RESP3 type.
+//
+// https://redis.io/docs/latest/develop/reference/protocol-spec/
+package redistypes
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MaxBulkLength is the maximum size of a bulk string accepted by the redis server
+	MaxBulkLength = 512 * 1024 * 1024
+	// maxInlineLength is the maximum size of an inline command
+	maxInlineLength = 64 * 1024
+	// maxNestingDepth is the maximum depth of nested aggregate types
+	maxNestingDepth = 32
+)
+
+// RESP data types
+const (
+	TypeSimpleString   byte = '+'
+	TypeSimpleError    byte = '-'
+	TypeInteger        byte = ':'
+	TypeBulkString     byte = '$'
+	TypeArray          byte = '*'
+	TypeNull           byte = '_'
+	TypeBoolean        byte = '#'
+	TypeDouble         byte = ','
+	TypeBigNumber      byte = '('
+	TypeBulkError      byte = '!'
+	TypeVerbatimString byte = '='
+	TypeMap            byte = '%'
+	TypeAttribute      byte = '|'
+	TypeSet            byte = '~'
+	TypePush           byte = '>'
+)
+
+// Command is a command sent by a redis client, the first argument is the name of the command
+type Command []string
+
+// Name returns the upper case name of the command
+func (c Command) Name() string {
+	if len(c) == 0 {
+		return ""
+	}
+	return strings.ToUpper(c[0])
+}
+
+// String returns the command as it would be typed in redis-cli,
+// arguments with spaces or non printable characters are quoted.
+func (c Command) String() string {
+	args := make([]string, len(c))
+	for i, arg := range c {
+		args[i] = quoteArg(arg)
+	}
+	if len(args) > 0 {
+		args[0] = strings.ToUpper(args[0])
+	}
+	return strings.Join(args, " ")
+}
+
+func quoteArg(arg string) string {
+	if arg == "" {
+		return `""`
+	}
+	for _, r := range arg {
+		if r <= ' ' || r == '"' || r == '\'' || r == '\\' || r > '~' {
+			return strconv.Quote(arg)
+		}
+	}
+	return arg
+}
+
+// EncodeCommand encodes the arguments as an array of bulk strings
+func EncodeCommand(args ...string) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&buf, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	return buf.Bytes()
+}
+
+// EncodeError encodes a simple error reply, the message must not contain new lines
+func EncodeError(msg string) []byte {
+	msg = strings.NewReplacer("\r", " ", "\n", " ").Replace(msg)
+	return []byte("-" + msg + "\r\n")
+}
+
+// ReadCommand reads a complete command of the client returning its raw bytes.
+// It reads arrays of bulk strings and inline commands.
+func ReadCommand(r *bufio.Reader) ([]byte, error) {
+	typ, err := r.Peek(1)
+	if err != nil {
+		return nil, err
+	}
+	if typ[0] != TypeArray {
+		line, err := readLine(r, maxInlineLength)
+		if err != nil {
+			return nil, err
+		}
+		return append(line, '\r', '\n'), nil
+	}
+	return ReadValue(r)
+}
+
+// ReadValue reads a complete RESP value returning its raw bytes.
+// Aggregate types are read with all their elements.
+func ReadValue(r *bufio.Reader) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := readValue(r, &buf, 0); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func readValue(r *bufio.Reader, dst *bytes.Buffer, depth int) error {
+	if depth > maxNestingDepth {
+		return fmt.Errorf("max nesting depth reached (%v)", maxNestingDepth)
+	}
+	line, err := readLine(r, maxInlineLength)
+	if err != nil {
+		return err
+	}
+	if len(line) == 0 {
+		return fmt.Errorf("empty resp value")
+	}
+	dst.Write(line)
+	dst.WriteString("\r\n")
+	switch line[0] {
+	case TypeSimpleString, TypeSimpleError, TypeInteger, TypeNull,
+		TypeBoolean, TypeDouble, TypeBigNumber:
+		return nil
+	case TypeBulkString, TypeBulkError, TypeVerbatimString:
+		size, err := parseLength(line)
+		if err != nil || size < 0 {
+			return err
+		}
+		_, err = io.CopyN(dst, r, int64(size)+2)
+		return err
+	case TypeArray, TypeSet, TypePush, TypeMap, TypeAttribute:
+		size, err := parseLength(line)
+		if err != nil || size < 0 {
+			return err
+		}
+		if line[0] == TypeMap || line[0] == TypeAttribute {
+			size *= 2
+		}
+		for i := 0; i < size; i++ {
+			if err := readValue(r, dst, depth+1); err != nil {
+				return err
+			}
+		}
+		// an attribute is followed by the value it describes
+		if line[0] == TypeAttribute {
+			return readValue(r, dst, depth+1)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown resp type %q", line[0])
+}
+
+// readLine reads a line terminated by \r\n or \n without the terminator
+func readLine(r *bufio.Reader, maxSize int) ([]byte, error) {
+	var line []byte
+	for {
+		chunk, isPrefix, err := r.ReadLine()
+		if err != nil {
+			return nil, err
+		}
+		line = append(line, chunk...)
+		if len(line) > maxSize {
+			return nil, fmt.Errorf("max line size reached (%v)", maxSize)
+		}
+		if !isPrefix {
+			return line, nil
+		}
+	}
+}
+
+func parseLength(line []byte) (int, error) {
+	size, err := strconv.Atoi(string(line[1:]))
+	if err != nil {
+		return 0, fmt.Errorf("invalid length %q for resp type %q", line[1:], line[0])
+	}
+	if size > MaxBulkLength {
+		return 0, fmt.Errorf("invalid length (%v) for resp type %q", size, line[0])
+	}
+	return size, nil
+}
+
+// DecodeCommands decodes all commands of the data. It accepts arrays of bulk strings
+// and inline commands, the inline format allows decoding commands typed by users.
+func DecodeCommands(data []byte) ([]Command, error) {
+	var commands []Command
+	r := bufio.NewReader(bytes.NewReader(data))
+	for {
+		typ, err := r.Peek(1)
+		if err == io.EOF {
+			return commands, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		var cmd Command
+		if typ[0] == TypeArray {
+			cmd, err = decodeArrayCommand(r)
+		} else {
+			var line []byte
+			if line, err = readLine(r, maxInlineLength); err == nil {
+				cmd, err = splitInlineArgs(string(line))
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(cmd) > 0 {
+			commands = append(commands, cmd)
+		}
+	}
+}
+
+func decodeArrayCommand(r *bufio.Reader) (Command, error) {
+	line, err := readLine(r, maxInlineLength)
+	if err != nil {
+		return nil, err
+	}
+	size, err := parseLength(line)
+	if err != nil {
+		return nil, err
+	}
+	cmd := Command{}
+	for i := 0; i < size; i++ {
+		line, err := readLine(r, maxInlineLength)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading command argument, reason=%v", err)
+		}
+		if len(line) == 0 || line[0] != TypeBulkString {
+			return nil, fmt.Errorf("command argument must be a bulk string, got=%q", line)
+		}
+		argSize, err := parseLength(line)
+		if err != nil {
+			return nil, err
+		}
+		if argSize < 0 {
+			return nil, fmt.Errorf("command argument must not be null")
+		}
+		arg := make([]byte, argSize+2)
+		if _, err := io.ReadFull(r, arg); err != nil {
+			return nil, fmt.Errorf("failed reading command argument, reason=%v", err)
+		}
+		cmd = append(cmd, string(arg[:argSize]))
+	}
+	return cmd, nil
+}
+
+// splitInlineArgs splits an inline command in arguments, it accepts
+// double quoted arguments with escape sequences and single quoted arguments
+func splitInlineArgs(line string) (Command, error) {
+	var args Command
+	for i := 0; i < len(line); {
+		switch c := line[i]; {
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '"':
+			end := i + 1
+			for ; end < len(line) && line[end] != '"'; end++ {
+				if line[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(line) {
+				return nil, fmt.Errorf("unbalanced quotes in command")
+			}
+			arg, err := strconv.Unquote(line[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid quoted argument %v, reason=%v", line[i:end+1], err)
+			}
+			args = append(args, arg)
+			i = end + 1
+		case c == '\'':
+			end := strings.IndexByte(line[i+1:], '\'')
+			if end == -1 {
+				return nil, fmt.Errorf("unbalanced quotes in command")
+			}
+			args = append(args, line[i+1:i+1+end])
+			i += end + 2
+		default:
+			end := strings.IndexAny(line[i:], " \t\r")
+			if end == -1 {
+				end = len(line) - i
+			}
+			args = append(args, line[i:i+end])
+			i += end
+		}
+	}
+	return args, nil
+}
+
+// ParseError returns the message of an error reply, it returns false if the reply is not an error
+func ParseError(reply []byte) (string, bool) {
+	if len(reply) == 0 || (reply[0] != TypeSimpleError && reply[0] != TypeBulkError) {
+		return "", false
+	}
+	msg := reply[1:]
+	if reply[0] == TypeBulkError {
+		// skip the length line
+		if idx := bytes.Index(msg, []byte("\r\n")); idx != -1 {
+			msg = msg[idx+2:]
+		}
+	}
+	return string(bytes.TrimRight(msg, "\r\n")), true
+}
+
+// DecodeCommandsText decodes the commands of the data returning one command per line
+func DecodeCommandsText(data []byte) ([]byte, error) {
+	commands, err := DecodeCommands(data)
+	if err != nil {
+		return nil, err
+	}
+	var text []byte
+	for _, cmd := range commands {
+		text = append(text, cmd.String()...)
+		text = append(text, '\n')
+	}
+	return text, nil
+}
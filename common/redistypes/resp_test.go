@@ -0,0 +1,125 @@
+package redistypes
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeCommands(t *testing.T) {
+	for _, tt := range []struct {
+		msg     string
+		data    string
+		want    []Command
+		wantErr string
+	}{
+		{
+			msg:  "it must decode an array of bulk strings",
+			data: "*2\r\n$3\r\nGET\r\n$3\r\nfoo\r\n",
+			want: []Command{{"GET", "foo"}},
+		},
+		{
+			msg:  "it must decode pipelined commands",
+			data: "*1\r\n$8\r\nFLUSHALL\r\n*2\r\n$3\r\ndel\r\n$5\r\nk\r\nv1\r\n",
+			want: []Command{{"FLUSHALL"}, {"del", "k\r\nv1"}},
+		},
+		{
+			msg:  "it must decode inline commands",
+			data: "SET key \"hello world\"\r\nGET 'my key'\n",
+			want: []Command{{"SET", "key", "hello world"}, {"GET", "my key"}},
+		},
+		{
+			msg:  "it must skip empty lines",
+			data: "\r\n\nPING\n",
+			want: []Command{{"PING"}},
+		},
+		{
+			msg:     "it must error with unbalanced quotes",
+			data:    "SET key \"value",
+			wantErr: "unbalanced quotes in command",
+		},
+		{
+			msg:     "it must error when the argument is not a bulk string",
+			data:    "*1\r\n:1\r\n",
+			wantErr: "command argument must be a bulk string, got=\":1\"",
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			got, err := DecodeCommands([]byte(tt.data))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDecodeCommandsText(t *testing.T) {
+	got, err := DecodeCommandsText([]byte("*2\r\n$3\r\nget\r\n$3\r\nfoo\r\n*1\r\n$8\r\nFLUSHALL\r\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "GET foo\nFLUSHALL\n", string(got))
+}
+
+func TestCommandString(t *testing.T) {
+	assert.Equal(t, "GET foo", Command{"get", "foo"}.String())
+	assert.Equal(t, `SET key "hello world"`, Command{"set", "key", "hello world"}.String())
+	assert.Equal(t, `SET key ""`, Command{"SET", "key", ""}.String())
+	assert.Equal(t, "CONFIG", Command{"config", "set"}.Name())
+}
+
+func TestReadValue(t *testing.T) {
+	for _, tt := range []struct {
+		msg  string
+		data string
+	}{
+		{msg: "simple string", data: "+OK\r\n"},
+		{msg: "error", data: "-WRONGPASS invalid username-password pair\r\n"},
+		{msg: "integer", data: ":1000\r\n"},
+		{msg: "bulk string", data: "$5\r\nhe\r\nl\r\n"},
+		{msg: "null bulk string", data: "$-1\r\n"},
+		{msg: "nested array", data: "*2\r\n*1\r\n+a\r\n$1\r\nb\r\n"},
+		{msg: "resp3 map", data: "%1\r\n+key\r\n#t\r\n"},
+		{msg: "resp3 attribute", data: "|1\r\n+ttl\r\n:3600\r\n+value\r\n"},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			// the trailing value must not be consumed
+			r := bufio.NewReader(bytes.NewBufferString(tt.data + "+NEXT\r\n"))
+			got, err := ReadValue(r)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.data, string(got))
+			next, err := ReadValue(r)
+			assert.NoError(t, err)
+			assert.Equal(t, "+NEXT\r\n", string(next))
+		})
+	}
+}
+
+func TestReadCommand(t *testing.T) {
+	r := bufio.NewReader(bytes.NewBufferString("*2\r\n$3\r\nGET\r\n$3\r\nfoo\r\nPING\n"))
+	got, err := ReadCommand(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "*2\r\n$3\r\nGET\r\n$3\r\nfoo\r\n", string(got))
+	got, err = ReadCommand(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "PING\r\n", string(got))
+	_, err = ReadCommand(r)
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestParseError(t *testing.T) {
+	msg, ok := ParseError([]byte("-WRONGPASS invalid username-password pair\r\n"))
+	assert.True(t, ok)
+	assert.Equal(t, "WRONGPASS invalid username-password pair", msg)
+	msg, ok = ParseError([]byte("!21\r\nSYNTAX invalid syntax\r\n"))
+	assert.True(t, ok)
+	assert.Equal(t, "SYNTAX invalid syntax", msg)
+	_, ok = ParseError([]byte("+OK\r\n"))
+	assert.False(t, ok)
+	assert.Equal(t, "-ERR a b\r\n", string(EncodeError("ERR a\nb")))
+	assert.Equal(t, "*2\r\n$4\r\nAUTH\r\n$3\r\npwd\r\n", string(EncodeCommand("AUTH", "pwd")))
+}
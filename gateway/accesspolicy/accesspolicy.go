@@ -0,0 +1,296 @@
+package accesspolicy
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	// embed the timezone database, the gateway image may not contain it
+	_ "time/tzdata"
+
+	pb "github.com/hoophq/hoop/common/proto"
+	"github.com/hoophq/hoop/gateway/models"
+)
+
+const (
+	// EffectAllow restricts the access of the users of the policy to the requests matching its conditions
+	EffectAllow = "allow"
+	// EffectDeny denies the requests matching the conditions of the policy
+	EffectDeny = "deny"
+)
+
+var (
+	Effects       = []string{EffectAllow, EffectDeny}
+	Verbs         = []string{pb.ClientVerbExec, pb.ClientVerbConnect}
+	ClientOrigins = []string{
+		pb.ConnectionOriginClient,
+		pb.ConnectionOriginClientAPI,
+		pb.ConnectionOriginClientAPIRunbooks,
+		pb.ConnectionOriginClientProxyManager,
+	}
+	// WeekDays are indexed by time.Weekday
+	WeekDays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+)
+
+// Request contains the attributes of an attempt to access a connection
+type Request struct {
+	UserGroups        []string
+	ConnectionName    string
+	ConnectionType    string
+	ConnectionSubType string
+	ConnectionTags    map[string]string
+	// Verb is the action performed in the connection (exec or connect),
+	// an empty value represents reading the connection
+	Verb string
+	// Origin is the client that issued the request
+	Origin   string
+	SourceIP string
+	// Time of the request, it defaults to the current time
+	Time time.Time
+	// IssuedByGateway indicates the request was issued by the gateway on behalf
+	// of an api request, the policies were already evaluated by the api
+	IssuedByGateway bool
+}
+
+// Result is the outcome of evaluating a single policy
+type Result struct {
+	Policy *models.AccessPolicy
+	// Applicable indicates if the policy applies to the groups of the user
+	Applicable bool
+	// Matched indicates if all conditions of the policy matched the request
+	Matched bool
+	// Reason describes why the policy didn't match the request
+	Reason string
+}
+
+// Decision is the outcome of evaluating all policies of an organization
+type Decision struct {
+	Allowed bool
+	Reason  string
+	Results []Result
+}
+
+// Evaluate decides if a request is allowed by the policies. The deny policies matching the request
+// take precedence. When allow policies apply to the user, at least one of them must match the request.
+// Users that are not subject to any allow policy keep the access granted by their groups.
+func Evaluate(policies []models.AccessPolicy, req Request) *Decision {
+	if req.Time.IsZero() {
+		req.Time = time.Now().UTC()
+	}
+	d := &Decision{Results: []Result{}}
+	var deniedBy, allowedBy string
+	var allowPolicies []string
+	for i := range policies {
+		p := &policies[i]
+		res := Result{Policy: p, Applicable: isApplicable(p, req.UserGroups)}
+		if !res.Applicable {
+			res.Reason = "the user does not belong to the groups of the policy"
+			d.Results = append(d.Results, res)
+			continue
+		}
+		res.Reason = matchConditions(p, req)
+		res.Matched = res.Reason == ""
+		d.Results = append(d.Results, res)
+		switch p.Effect {
+		case EffectDeny:
+			if res.Matched && deniedBy == "" {
+				deniedBy = p.Name
+			}
+		case EffectAllow:
+			allowPolicies = append(allowPolicies, p.Name)
+			if res.Matched && allowedBy == "" {
+				allowedBy = p.Name
+			}
+		}
+	}
+	switch {
+	case deniedBy != "":
+		d.Reason = fmt.Sprintf("denied by policy %q", deniedBy)
+	case len(allowPolicies) == 0:
+		d.Allowed = true
+		d.Reason = "no allow policies apply to the user"
+	case allowedBy != "":
+		d.Allowed = true
+		d.Reason = fmt.Sprintf("allowed by policy %q", allowedBy)
+	default:
+		d.Reason = fmt.Sprintf("the request does not match any of the allow policies: %v", strings.Join(allowPolicies, ", "))
+	}
+	return d
+}
+
+// ValidatePolicy validates the attributes of an access policy
+func ValidatePolicy(p *models.AccessPolicy) error {
+	if !slices.Contains(Effects, p.Effect) {
+		return fmt.Errorf("invalid effect %q, accepted values are: %v", p.Effect, strings.Join(Effects, ", "))
+	}
+	for _, verb := range p.Verbs {
+		if !slices.Contains(Verbs, verb) {
+			return fmt.Errorf("invalid verb %q, accepted values are: %v", verb, strings.Join(Verbs, ", "))
+		}
+	}
+	for _, origin := range p.ClientOrigins {
+		if !slices.Contains(ClientOrigins, origin) {
+			return fmt.Errorf("invalid client origin %q, accepted values are: %v", origin, strings.Join(ClientOrigins, ", "))
+		}
+	}
+	for _, sourceIP := range p.SourceIPs {
+		if _, err := parseSourceIP(sourceIP); err != nil {
+			return fmt.Errorf("invalid source ip %q, it must be an ip address or a cidr, e.g.: 10.0.0.1, 10.0.0.0/8", sourceIP)
+		}
+	}
+	if w := p.TimeWindow; w != nil {
+		for _, day := range w.Days {
+			if !slices.Contains(WeekDays, day) {
+				return fmt.Errorf("invalid day %q, accepted values are: %v", day, strings.Join(WeekDays, ", "))
+			}
+		}
+		for _, clock := range []string{w.StartTime, w.EndTime} {
+			if _, err := parseClock(clock, 0); err != nil {
+				return err
+			}
+		}
+		if _, err := time.LoadLocation(w.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone %q", w.Timezone)
+		}
+	}
+	return nil
+}
+
+func isApplicable(p *models.AccessPolicy, userGroups []string) bool {
+	if len(p.Groups) == 0 {
+		return true
+	}
+	for _, group := range userGroups {
+		if slices.Contains(p.Groups, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchConditions returns the reason the request doesn't match the conditions
+// of the policy, an empty value means all conditions matched
+func matchConditions(p *models.AccessPolicy, req Request) string {
+	var tagKeys []string
+	for key := range p.ConnectionTags {
+		tagKeys = append(tagKeys, key)
+	}
+	sort.Strings(tagKeys)
+	for _, key := range tagKeys {
+		if val, ok := req.ConnectionTags[key]; !ok || val != p.ConnectionTags[key] {
+			return fmt.Sprintf("the connection does not have the tag %v=%v", key, p.ConnectionTags[key])
+		}
+	}
+	if len(p.ConnectionTypes) > 0 &&
+		!slices.Contains(p.ConnectionTypes, req.ConnectionType) &&
+		!slices.Contains(p.ConnectionTypes, req.ConnectionSubType) {
+		return fmt.Sprintf("the connection type %q is not one of: %v", req.ConnectionType, strings.Join(p.ConnectionTypes, ", "))
+	}
+
+	// the verb and the origin are attributes of executions. Reading a connection
+	// is allowed by allow policies and it's not denied by the deny policies restricted to them.
+	isRead := req.Verb == ""
+	if len(p.Verbs) > 0 {
+		switch {
+		case isRead && p.Effect == EffectDeny:
+			return "the policy applies only to the verbs: " + strings.Join(p.Verbs, ", ")
+		case !isRead && !slices.Contains(p.Verbs, req.Verb):
+			return fmt.Sprintf("the verb %q is not one of: %v", req.Verb, strings.Join(p.Verbs, ", "))
+		}
+	}
+	if len(p.ClientOrigins) > 0 {
+		switch {
+		case isRead && p.Effect == EffectDeny:
+			return "the policy applies only to the client origins: " + strings.Join(p.ClientOrigins, ", ")
+		case !isRead && !slices.Contains(p.ClientOrigins, req.Origin):
+			return fmt.Sprintf("the client origin %q is not one of: %v", req.Origin, strings.Join(p.ClientOrigins, ", "))
+		}
+	}
+	if p.TimeWindow != nil {
+		if reason := matchTimeWindow(p.TimeWindow, req.Time); reason != "" {
+			return reason
+		}
+	}
+	if len(p.SourceIPs) > 0 && !matchSourceIP(p.SourceIPs, req.SourceIP) {
+		return fmt.Sprintf("the source ip %q is not one of: %v", req.SourceIP, strings.Join(p.SourceIPs, ", "))
+	}
+	return ""
+}
+
+func matchTimeWindow(w *models.AccessPolicyTimeWindow, t time.Time) string {
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		return fmt.Sprintf("invalid timezone %q", w.Timezone)
+	}
+	t = t.In(loc)
+	day := WeekDays[t.Weekday()]
+	if len(w.Days) > 0 && !slices.Contains(w.Days, day) {
+		return fmt.Sprintf("the day %q is not one of: %v", day, strings.Join(w.Days, ", "))
+	}
+	start, _ := parseClock(w.StartTime, 0)
+	end, _ := parseClock(w.EndTime, 24*60)
+	now := t.Hour()*60 + t.Minute()
+	inWindow := now >= start && now < end
+	// windows ending before they start cross midnight, e.g.: 22:00-06:00
+	if end < start {
+		inWindow = now >= start || now < end
+	}
+	if !inWindow {
+		return fmt.Sprintf("the time %v is not between %v and %v (%v)",
+			t.Format("15:04"), formatClock(start), formatClock(end), loc)
+	}
+	return ""
+}
+
+func matchSourceIP(sourceIPs []string, sourceIP string) bool {
+	addr, err := netip.ParseAddr(sourceIP)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, val := range sourceIPs {
+		prefix, err := parseSourceIP(val)
+		if err == nil && prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSourceIP parses an ip address or a cidr as a prefix
+func parseSourceIP(val string) (netip.Prefix, error) {
+	if strings.Contains(val, "/") {
+		prefix, err := netip.ParsePrefix(val)
+		return prefix.Masked(), err
+	}
+	addr, err := netip.ParseAddr(val)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// parseClock parses a time of the day in the format HH:MM as minutes,
+// it returns the default value when it's empty
+func parseClock(val string, defaultVal int) (int, error) {
+	if val == "" {
+		return defaultVal, nil
+	}
+	hh, mm, found := strings.Cut(val, ":")
+	hour, herr := strconv.Atoi(hh)
+	minute, merr := strconv.Atoi(mm)
+	if !found || len(hh) != 2 || len(mm) != 2 || herr != nil || merr != nil ||
+		hour < 0 || minute < 0 || minute > 59 || hour*60+minute > 24*60 {
+		return 0, fmt.Errorf("invalid time of the day %q, it must be in the format HH:MM, e.g.: 09:00, 18:30", val)
+	}
+	return hour*60 + minute, nil
+}
+
+func formatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
@@ -0,0 +1,243 @@
+package accesspolicy
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/hoophq/hoop/common/proto"
+	"github.com/hoophq/hoop/gateway/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	// Tuesday
+	businessHours = time.Date(2024, 7, 23, 10, 30, 0, 0, time.UTC)
+	// Tuesday
+	afterHours = time.Date(2024, 7, 23, 20, 0, 0, 0, time.UTC)
+	// Saturday
+	weekend = time.Date(2024, 7, 27, 10, 30, 0, 0, time.UTC)
+)
+
+func contractorsPolicy() models.AccessPolicy {
+	return models.AccessPolicy{
+		Name:           "contractors-staging",
+		Effect:         EffectAllow,
+		Groups:         []string{"contractors"},
+		ConnectionTags: map[string]string{"env": "staging"},
+		Verbs:          []string{pb.ClientVerbExec},
+		TimeWindow: &models.AccessPolicyTimeWindow{
+			Days:      []string{"mon", "tue", "wed", "thu", "fri"},
+			StartTime: "09:00",
+			EndTime:   "18:00",
+		},
+	}
+}
+
+func denyProdConnectPolicy() models.AccessPolicy {
+	return models.AccessPolicy{
+		Name:           "deny-prod-connect",
+		Effect:         EffectDeny,
+		ConnectionTags: map[string]string{"env": "prod"},
+		Verbs:          []string{pb.ClientVerbConnect},
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	for _, tt := range []struct {
+		msg        string
+		policies   []models.AccessPolicy
+		req        Request
+		wantAllow  bool
+		wantReason string
+	}{
+		{
+			msg:        "it must allow when there are no policies",
+			req:        Request{UserGroups: []string{"sre"}, Verb: pb.ClientVerbConnect, Time: businessHours},
+			wantAllow:  true,
+			wantReason: "no allow policies apply to the user",
+		},
+		{
+			msg:      "it must allow contractors to exec on staging during business hours",
+			policies: []models.AccessPolicy{contractorsPolicy()},
+			req: Request{UserGroups: []string{"contractors"}, ConnectionTags: map[string]string{"env": "staging"},
+				Verb: pb.ClientVerbExec, Time: businessHours},
+			wantAllow:  true,
+			wantReason: `allowed by policy "contractors-staging"`,
+		},
+		{
+			msg:      "it must allow contractors to read staging connections",
+			policies: []models.AccessPolicy{contractorsPolicy()},
+			req: Request{UserGroups: []string{"contractors"}, ConnectionTags: map[string]string{"env": "staging"},
+				Time: businessHours},
+			wantAllow:  true,
+			wantReason: `allowed by policy "contractors-staging"`,
+		},
+		{
+			msg:      "it must deny contractors to exec on prod",
+			policies: []models.AccessPolicy{contractorsPolicy()},
+			req: Request{UserGroups: []string{"contractors"}, ConnectionTags: map[string]string{"env": "prod"},
+				Verb: pb.ClientVerbExec, Time: businessHours},
+			wantReason: "the request does not match any of the allow policies: contractors-staging",
+		},
+		{
+			msg:      "it must deny contractors to connect on staging",
+			policies: []models.AccessPolicy{contractorsPolicy()},
+			req: Request{UserGroups: []string{"contractors"}, ConnectionTags: map[string]string{"env": "staging"},
+				Verb: pb.ClientVerbConnect, Time: businessHours},
+			wantReason: "the request does not match any of the allow policies: contractors-staging",
+		},
+		{
+			msg:      "it must deny contractors to exec on staging after hours",
+			policies: []models.AccessPolicy{contractorsPolicy()},
+			req: Request{UserGroups: []string{"contractors"}, ConnectionTags: map[string]string{"env": "staging"},
+				Verb: pb.ClientVerbExec, Time: afterHours},
+			wantReason: "the request does not match any of the allow policies: contractors-staging",
+		},
+		{
+			msg:      "it must deny contractors to exec on staging during the weekend",
+			policies: []models.AccessPolicy{contractorsPolicy()},
+			req: Request{UserGroups: []string{"contractors"}, ConnectionTags: map[string]string{"env": "staging"},
+				Verb: pb.ClientVerbExec, Time: weekend},
+			wantReason: "the request does not match any of the allow policies: contractors-staging",
+		},
+		{
+			msg:      "it must allow users that are not subject to the allow policies",
+			policies: []models.AccessPolicy{contractorsPolicy()},
+			req: Request{UserGroups: []string{"sre"}, ConnectionTags: map[string]string{"env": "prod"},
+				Verb: pb.ClientVerbConnect, Time: weekend},
+			wantAllow:  true,
+			wantReason: "no allow policies apply to the user",
+		},
+		{
+			msg:      "it must deny when a deny policy matches the request",
+			policies: []models.AccessPolicy{contractorsPolicy(), denyProdConnectPolicy()},
+			req: Request{UserGroups: []string{"sre"}, ConnectionTags: map[string]string{"env": "prod"},
+				Verb: pb.ClientVerbConnect, Time: businessHours},
+			wantReason: `denied by policy "deny-prod-connect"`,
+		},
+		{
+			msg:      "it must not deny reading connections when the deny policy is restricted to verbs",
+			policies: []models.AccessPolicy{denyProdConnectPolicy()},
+			req: Request{UserGroups: []string{"sre"}, ConnectionTags: map[string]string{"env": "prod"},
+				Time: businessHours},
+			wantAllow:  true,
+			wantReason: "no allow policies apply to the user",
+		},
+		{
+			msg: "it must allow when the source ip is in the cidr",
+			policies: []models.AccessPolicy{{Name: "vpn", Effect: EffectAllow,
+				SourceIPs: []string{"10.0.0.0/8", "192.168.0.10"}}},
+			req:        Request{Verb: pb.ClientVerbExec, SourceIP: "10.12.0.1", Time: businessHours},
+			wantAllow:  true,
+			wantReason: `allowed by policy "vpn"`,
+		},
+		{
+			msg: "it must deny when the source ip is not in the cidr",
+			policies: []models.AccessPolicy{{Name: "vpn", Effect: EffectAllow,
+				SourceIPs: []string{"10.0.0.0/8", "192.168.0.10"}}},
+			req:        Request{Verb: pb.ClientVerbExec, SourceIP: "192.168.0.11", Time: businessHours},
+			wantReason: "the request does not match any of the allow policies: vpn",
+		},
+		{
+			msg: "it must match the client origin and the connection type",
+			policies: []models.AccessPolicy{{Name: "api-postgres", Effect: EffectAllow,
+				ClientOrigins: []string{pb.ConnectionOriginClientAPI}, ConnectionTypes: []string{"postgres"}}},
+			req: Request{Verb: pb.ClientVerbExec, Origin: pb.ConnectionOriginClientAPI,
+				ConnectionType: "database", ConnectionSubType: "postgres", Time: businessHours},
+			wantAllow:  true,
+			wantReason: `allowed by policy "api-postgres"`,
+		},
+		{
+			msg: "it must match time windows crossing midnight",
+			policies: []models.AccessPolicy{{Name: "maintenance", Effect: EffectAllow,
+				TimeWindow: &models.AccessPolicyTimeWindow{StartTime: "19:00", EndTime: "06:00"}}},
+			req:        Request{Verb: pb.ClientVerbExec, Time: afterHours},
+			wantAllow:  true,
+			wantReason: `allowed by policy "maintenance"`,
+		},
+		{
+			msg: "it must match time windows in the timezone of the policy",
+			policies: []models.AccessPolicy{{Name: "sao-paulo", Effect: EffectAllow,
+				TimeWindow: &models.AccessPolicyTimeWindow{StartTime: "09:00", EndTime: "18:00", Timezone: "America/Sao_Paulo"}}},
+			req:        Request{Verb: pb.ClientVerbExec, Time: afterHours},
+			wantAllow:  true,
+			wantReason: `allowed by policy "sao-paulo"`,
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			d := Evaluate(tt.policies, tt.req)
+			assert.Equal(t, tt.wantAllow, d.Allowed)
+			assert.Equal(t, tt.wantReason, d.Reason)
+			assert.Len(t, d.Results, len(tt.policies))
+		})
+	}
+}
+
+func TestEvaluateResultReason(t *testing.T) {
+	d := Evaluate([]models.AccessPolicy{contractorsPolicy(), denyProdConnectPolicy()}, Request{
+		UserGroups:     []string{"contractors"},
+		ConnectionTags: map[string]string{"env": "staging"},
+		Verb:           pb.ClientVerbExec,
+		Time:           afterHours,
+	})
+	assert.False(t, d.Allowed)
+	assert.True(t, d.Results[0].Applicable)
+	assert.False(t, d.Results[0].Matched)
+	assert.Equal(t, "the time 20:00 is not between 09:00 and 18:00 (UTC)", d.Results[0].Reason)
+	assert.True(t, d.Results[1].Applicable)
+	assert.Equal(t, "the connection does not have the tag env=prod", d.Results[1].Reason)
+}
+
+func TestValidatePolicy(t *testing.T) {
+	for _, tt := range []struct {
+		msg     string
+		policy  models.AccessPolicy
+		wantErr string
+	}{
+		{msg: "it must accept a valid policy", policy: contractorsPolicy()},
+		{
+			msg:     "it must return error with an invalid effect",
+			policy:  models.AccessPolicy{Effect: "permit"},
+			wantErr: `invalid effect "permit", accepted values are: allow, deny`,
+		},
+		{
+			msg:     "it must return error with an invalid verb",
+			policy:  models.AccessPolicy{Effect: EffectDeny, Verbs: []string{pb.ClientVerbPlainExec}},
+			wantErr: `invalid verb "plain-exec", accepted values are: exec, connect`,
+		},
+		{
+			msg:     "it must return error with an invalid client origin",
+			policy:  models.AccessPolicy{Effect: EffectDeny, ClientOrigins: []string{pb.ConnectionOriginAgent}},
+			wantErr: `invalid client origin "agent", accepted values are: client, client-api, client-api-runbooks, client-proxymanager`,
+		},
+		{
+			msg:     "it must return error with an invalid source ip",
+			policy:  models.AccessPolicy{Effect: EffectDeny, SourceIPs: []string{"10.0.0.0/33"}},
+			wantErr: `invalid source ip "10.0.0.0/33", it must be an ip address or a cidr, e.g.: 10.0.0.1, 10.0.0.0/8`,
+		},
+		{
+			msg:     "it must return error with an invalid day",
+			policy:  models.AccessPolicy{Effect: EffectDeny, TimeWindow: &models.AccessPolicyTimeWindow{Days: []string{"monday"}}},
+			wantErr: `invalid day "monday", accepted values are: sun, mon, tue, wed, thu, fri, sat`,
+		},
+		{
+			msg:     "it must return error with an invalid time of the day",
+			policy:  models.AccessPolicy{Effect: EffectDeny, TimeWindow: &models.AccessPolicyTimeWindow{StartTime: "9:00"}},
+			wantErr: `invalid time of the day "9:00", it must be in the format HH:MM, e.g.: 09:00, 18:30`,
+		},
+		{
+			msg:     "it must return error with an invalid timezone",
+			policy:  models.AccessPolicy{Effect: EffectDeny, TimeWindow: &models.AccessPolicyTimeWindow{Timezone: "Mars/Olympus"}},
+			wantErr: `invalid timezone "Mars/Olympus"`,
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			err := ValidatePolicy(&tt.policy)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -21,8 +21,10 @@ const (
 	EventUpdateServiceAccount         = "hoop-update-serviceaccount"
 
 	// review
-	EventUpdateReview = "hoop-update-review"
-	EventFetchReviews = "hoop-fetch-reviews"
+	EventUpdateReview           = "hoop-update-review"
+	EventFetchReviews           = "hoop-fetch-reviews"
+	EventRequestReviewExtension = "hoop-request-review-extension"
+	EventCreateScheduledReview  = "hoop-create-scheduled-review"
 
 	// agent
 	EventCreateAgent         = "hoop-create-agent"
@@ -45,6 +47,25 @@ const (
 	EventUpdateGuardRailRules = "hoop-update-guardrail-rules"
 	EventDeleteGuardRailRules = "hoop-delete-guardrail-rules"
 
+	// Retention Policies
+	EventCreateRetentionPolicy = "hoop-create-retention-policy"
+	EventUpdateRetentionPolicy = "hoop-update-retention-policy"
+	EventDeleteRetentionPolicy = "hoop-delete-retention-policy"
+
+	// API Tokens
+	EventCreateAPIToken = "hoop-create-api-token"
+	EventRevokeAPIToken = "hoop-revoke-api-token"
+
+	// Roles
+	EventCreateRole = "hoop-create-role"
+	EventUpdateRole = "hoop-update-role"
+	EventDeleteRole = "hoop-delete-role"
+
+	// Access Policies
+	EventCreateAccessPolicy = "hoop-create-access-policy"
+	EventUpdateAccessPolicy = "hoop-update-access-policy"
+	EventDeleteAccessPolicy = "hoop-delete-access-policy"
+
 	// AWS
 	EventAWSVerifyPermissions = "hoop-aws-verify-permissions"
 
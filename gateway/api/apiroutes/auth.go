@@ -12,9 +12,11 @@ import (
 	"github.com/google/uuid"
 	"github.com/hoophq/hoop/common/apiutils"
 	"github.com/hoophq/hoop/common/log"
+	"github.com/hoophq/hoop/gateway/models"
 	"github.com/hoophq/hoop/gateway/pgrest"
 	pgorgs "github.com/hoophq/hoop/gateway/pgrest/orgs"
 	pguserauth "github.com/hoophq/hoop/gateway/pgrest/userauth"
+	"github.com/hoophq/hoop/gateway/security/apitoken"
 	"github.com/hoophq/hoop/gateway/security/idp"
 	"github.com/hoophq/hoop/gateway/storagev2"
 	"github.com/hoophq/hoop/gateway/storagev2/types"
@@ -51,6 +53,13 @@ func (r *Router) AuthMiddleware(c *gin.Context) {
 		return
 	}
 
+	// api token authentication, the token acts on behalf
+	// of its owner and it's restricted by its scopes
+	if token, _ := parseToken(c); apitoken.IsToken(token) {
+		r.authenticateAPIToken(c, token)
+		return
+	}
+
 	// jwt key authentication
 	subject, err := r.validateAccessToken(c)
 	if err != nil {
@@ -105,19 +114,63 @@ func (r *Router) AuthMiddleware(c *gin.Context) {
 	}
 
 	// validate routes based on permissions from the user groups of a registered user
-	roles := rolesFromContext(c)
-	if !isGroupAllowed(ctx.UserGroups, roles...) {
-		log.Debugf("not allowed to access route, user=%v, path=%v, roles=%v",
-			ctx.UserEmail, c.Request.URL.Path, roles)
-		c.AbortWithStatus(http.StatusForbidden)
+	if !r.authorizeUser(c, ctx) {
 		return
 	}
 
-	log.Debugf("user authenticated, roles=%v, org=%s, subject=%s, isadmin=%v, isauditor=%v",
-		roles, ctx.OrgName, subject, ctx.IsAdmin(), ctx.IsAuditor())
+	log.Debugf("user authenticated, roles=%v, permissions=%v, org=%s, subject=%s, isadmin=%v, isauditor=%v",
+		rolesFromContext(c), ctx.Permissions, ctx.OrgName, subject, ctx.IsAdmin(), ctx.IsAuditor())
+	r.setUserContext(ctx, c)
+}
+
+// authenticateAPIToken validates if the token is active and if its scopes and
+// the groups of its owner are allowed to access the route
+func (r *Router) authenticateAPIToken(c *gin.Context, token string) {
+	ctx, scopes, err := apitoken.Authenticate(token)
+	if err != nil {
+		log.Infof("failed authenticating api token, prefix=%v, reason=%v, url-path=%v",
+			apitoken.DisplayPrefix(token), err, c.Request.URL.Path)
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "unauthorized"})
+		return
+	}
+	routePath := strings.TrimPrefix(c.FullPath(), r.BasePath())
+	if !apitoken.IsRouteAllowed(scopes, c.Request.Method, routePath) {
+		log.Debugf("api token not allowed to access route, user=%v, token=%v, path=%v, scopes=%v",
+			ctx.UserEmail, ctx.TokenID, routePath, scopes)
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "the scopes of the api token do not allow accessing this route"})
+		return
+	}
+	if !r.authorizeUser(c, ctx) {
+		return
+	}
 	r.setUserContext(ctx, c)
 }
 
+// authorizeUser validates if the groups of the user or the permissions granted by the
+// custom roles of its groups are allowed to access the route. It aborts the request when
+// the access is denied.
+func (r *Router) authorizeUser(c *gin.Context, ctx *pguserauth.Context) bool {
+	// admin users are allowed to access any route
+	if !ctx.IsAdmin() {
+		permissions, err := models.ListPermissionsByGroups(ctx.OrgID, ctx.UserGroups)
+		if err != nil {
+			log.Errorf("failed fetching permissions of user, subject=%v, err=%v", ctx.UserSubject, err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"message": "failed fetching user permissions"})
+			return false
+		}
+		ctx.Permissions = permissions
+	}
+	roles := rolesFromContext(c)
+	permission := permissionFromContext(c)
+	if !isGroupAllowed(ctx.UserGroups, roles...) && !isPermissionAllowed(ctx.Permissions, permission) {
+		log.Debugf("not allowed to access route, user=%v, path=%v, roles=%v, permission=%v",
+			ctx.UserEmail, c.Request.URL.Path, roles, permission)
+		c.AbortWithStatus(http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
 // setUserContext and call next middleware
 func (r *Router) setUserContext(ctx *pguserauth.Context, c *gin.Context) {
 	auditApiChanges(c, ctx)
@@ -127,6 +180,8 @@ func (r *Router) setUserContext(ctx *pguserauth.Context, c *gin.Context) {
 			WithSlackID(ctx.UserSlackID).
 			WithOrgName(ctx.OrgName).
 			WithOrgLicenseData(ctx.OrgLicenseData).
+			WithAPIToken(ctx.TokenID, ctx.TokenConnections).
+			WithPermissions(ctx.Permissions).
 			WithApiURL(r.provider.ApiURL).
 			WithGrpcURL(r.grpcURL),
 	)
@@ -1,14 +1,21 @@
 package apiroutes
 
 import (
+	"errors"
+	"fmt"
 	"slices"
 
 	"github.com/gin-gonic/gin"
 	"github.com/hoophq/hoop/gateway/api/openapi"
+	"github.com/hoophq/hoop/gateway/models"
+	"github.com/hoophq/hoop/gateway/storagev2"
 	"github.com/hoophq/hoop/gateway/storagev2/types"
 )
 
-const roleContextKey string = "hoop-roles"
+const (
+	roleContextKey       string = "hoop-roles"
+	permissionContextKey string = "hoop-permission"
+)
 
 func rolesFromContext(c *gin.Context) []openapi.RoleType {
 	obj, ok := c.Get(roleContextKey)
@@ -19,6 +26,21 @@ func rolesFromContext(c *gin.Context) []openapi.RoleType {
 	return roles
 }
 
+func permissionFromContext(c *gin.Context) openapi.PermissionType {
+	obj, ok := c.Get(permissionContextKey)
+	if !ok {
+		return ""
+	}
+	permission, _ := obj.(openapi.PermissionType)
+	return permission
+}
+
+// isPermissionAllowed validates if the permissions granted by the custom roles of a user
+// allows accessing a route. Routes without a permission could not be granted by custom roles.
+func isPermissionAllowed(userPermissions []string, routePermission openapi.PermissionType) bool {
+	return routePermission != "" && slices.Contains(userPermissions, string(routePermission))
+}
+
 // isGroupAllowed validates if the groups of a user is allowed to access a route
 func isGroupAllowed(userGroups []string, roleNames ...openapi.RoleType) (valid bool) {
 	if slices.Contains(userGroups, types.GroupAdmin) {
@@ -44,6 +66,53 @@ func isGroupAllowed(userGroups []string, roleNames ...openapi.RoleType) (valid b
 	return len(roleNames) == 0 || slices.Contains(roleNames, openapi.RoleStandardType)
 }
 
+// ErrForbiddenGroupChange is returned when a user is not allowed to change the groups of another user
+var ErrForbiddenGroupChange = errors.New("forbidden group change")
+
+// ValidateGroupChange validates if a user is allowed to change the groups of another user.
+// Custom roles may grant managing users, but only admin users could manage admin users
+// or grant the admin group, and the groups added by other users must not grant permissions
+// they don't hold, otherwise it would allow escalating privileges.
+func ValidateGroupChange(ctx *storagev2.Context, currentGroups, newGroups []string) error {
+	return validateGroupChange(ctx.UserGroups, ctx.Permissions, currentGroups, newGroups, func(groups []string) ([]string, error) {
+		return models.ListPermissionsByGroups(ctx.OrgID, groups)
+	})
+}
+
+func validateGroupChange(userGroups, userPermissions, currentGroups, newGroups []string, listPermissions func(groups []string) ([]string, error)) error {
+	if slices.Contains(userGroups, types.GroupAdmin) {
+		return nil
+	}
+	if slices.Contains(currentGroups, types.GroupAdmin) {
+		return fmt.Errorf("%w, only admin users can manage admin users", ErrForbiddenGroupChange)
+	}
+	var addedGroups []string
+	for _, group := range newGroups {
+		if !slices.Contains(currentGroups, group) {
+			addedGroups = append(addedGroups, group)
+		}
+	}
+	if len(addedGroups) == 0 {
+		return nil
+	}
+	if slices.Contains(addedGroups, types.GroupAdmin) {
+		return fmt.Errorf("%w, only admin users can grant the %s group", ErrForbiddenGroupChange, types.GroupAdmin)
+	}
+	if slices.Contains(addedGroups, types.GroupAuditor) && !slices.Contains(userGroups, types.GroupAuditor) {
+		return fmt.Errorf("%w, only admin and auditor users can grant the %s group", ErrForbiddenGroupChange, types.GroupAuditor)
+	}
+	grantedPermissions, err := listPermissions(addedGroups)
+	if err != nil {
+		return fmt.Errorf("failed fetching the permissions of groups: %v", err)
+	}
+	for _, permission := range grantedPermissions {
+		if !slices.Contains(userPermissions, permission) {
+			return fmt.Errorf("%w, the groups grant the permission %q which the user doesn't hold", ErrForbiddenGroupChange, permission)
+		}
+	}
+	return nil
+}
+
 // AdminOnlyAccessRole allows only admin users to access this role
 func AdminOnlyAccessRole(c *gin.Context) {
 	c.Set(roleContextKey, []openapi.RoleType{openapi.RoleAdminType})
@@ -58,3 +127,18 @@ func ReadOnlyAccessRole(c *gin.Context) {
 	})
 	c.Next()
 }
+
+// AuditorAccessRole allows only admin and auditor roles to access it
+func AuditorAccessRole(c *gin.Context) {
+	c.Set(roleContextKey, []openapi.RoleType{openapi.RoleAuditorType})
+	c.Next()
+}
+
+// PermissionAccess grants access to a route to the users of custom roles with the permission,
+// it extends the access granted by the route roles.
+func PermissionAccess(permission openapi.PermissionType) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(permissionContextKey, permission)
+		c.Next()
+	}
+}
@@ -53,3 +53,152 @@ func TestIsGroupAllowed(t *testing.T) {
 	}
 
 }
+
+func TestIsPermissionAllowed(t *testing.T) {
+	for _, tt := range []struct {
+		msg             string
+		permissions     []string
+		routePermission openapi.PermissionType
+		want            bool
+	}{
+		{
+			msg:             "it should allow when the user has the permission of the route",
+			permissions:     []string{"connections:read", "connections:write"},
+			routePermission: openapi.PermissionConnectionsWrite,
+			want:            true,
+		},
+		{
+			msg:             "it should deny when the user does not have the permission of the route",
+			permissions:     []string{"connections:read"},
+			routePermission: openapi.PermissionConnectionsWrite,
+			want:            false,
+		},
+		{
+			msg:             "it should deny when the route does not have a permission",
+			permissions:     []string{"connections:read"},
+			routePermission: "",
+			want:            false,
+		},
+		{
+			msg:             "it should deny when the user does not have permissions",
+			permissions:     nil,
+			routePermission: openapi.PermissionUsersRead,
+			want:            false,
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			assert.Equal(t, tt.want, isPermissionAllowed(tt.permissions, tt.routePermission))
+		})
+	}
+}
+
+func TestValidateGroupChange(t *testing.T) {
+	rolePermissions := map[string][]string{
+		"user-managers":   {"users:write"},
+		"policy-managers": {"policies:write", "users:write"},
+	}
+	listPermissions := func(groups []string) ([]string, error) {
+		var permissions []string
+		for _, group := range groups {
+			permissions = append(permissions, rolePermissions[group]...)
+		}
+		return permissions, nil
+	}
+	for _, tt := range []struct {
+		msg             string
+		userGroups      []string
+		userPermissions []string
+		currentGroups   []string
+		newGroups       []string
+		wantErr         string
+	}{
+		{
+			msg:        "it should allow admin users to grant the admin group",
+			userGroups: []string{types.GroupAdmin},
+			newGroups:  []string{types.GroupAdmin},
+		},
+		{
+			msg:           "it should allow admin users to remove the admin group",
+			userGroups:    []string{types.GroupAdmin},
+			currentGroups: []string{types.GroupAdmin},
+			newGroups:     []string{"sre"},
+		},
+		{
+			msg:        "it should allow admin users to grant groups of custom roles",
+			userGroups: []string{types.GroupAdmin},
+			newGroups:  []string{"policy-managers"},
+		},
+		{
+			msg:             "it should allow non admin users to manage the groups of non admin users",
+			userGroups:      []string{"user-managers"},
+			userPermissions: []string{"users:write"},
+			currentGroups:   []string{"sre", "policy-managers"},
+			newGroups:       []string{"sre", "dba", "user-managers"},
+		},
+		{
+			msg:             "it should allow non admin users to keep groups they could not grant",
+			userGroups:      []string{"user-managers"},
+			userPermissions: []string{"users:write"},
+			currentGroups:   []string{"policy-managers"},
+			newGroups:       []string{"policy-managers", "sre"},
+		},
+		{
+			msg:        "it should deny non admin users to grant the admin group",
+			userGroups: []string{"user-managers"},
+			newGroups:  []string{"sre", types.GroupAdmin},
+			wantErr:    "forbidden group change, only admin users can grant the admin group",
+		},
+		{
+			msg:           "it should deny non admin users to grant the admin group to themselves",
+			userGroups:    []string{"user-managers"},
+			currentGroups: []string{"user-managers"},
+			newGroups:     []string{"user-managers", types.GroupAdmin},
+			wantErr:       "forbidden group change, only admin users can grant the admin group",
+		},
+		{
+			msg:           "it should deny non admin users to remove the admin group",
+			userGroups:    []string{"user-managers"},
+			currentGroups: []string{types.GroupAdmin},
+			newGroups:     []string{},
+			wantErr:       "forbidden group change, only admin users can manage admin users",
+		},
+		{
+			msg:           "it should deny non admin users to manage admin users",
+			userGroups:    []string{"user-managers"},
+			currentGroups: []string{types.GroupAdmin},
+			newGroups:     []string{types.GroupAdmin},
+			wantErr:       "forbidden group change, only admin users can manage admin users",
+		},
+		{
+			msg:        "it should deny non auditor users to grant the auditor group",
+			userGroups: []string{"user-managers"},
+			newGroups:  []string{types.GroupAuditor},
+			wantErr:    "forbidden group change, only admin and auditor users can grant the auditor group",
+		},
+		{
+			msg:        "it should allow auditor users to grant the auditor group",
+			userGroups: []string{types.GroupAuditor, "user-managers"},
+			newGroups:  []string{types.GroupAuditor},
+		},
+		{
+			msg:             "it should deny non admin users to grant permissions they don't hold",
+			userGroups:      []string{"user-managers"},
+			userPermissions: []string{"users:write"},
+			currentGroups:   []string{"user-managers"},
+			newGroups:       []string{"user-managers", "policy-managers"},
+			wantErr:         `forbidden group change, the groups grant the permission "policies:write" which the user doesn't hold`,
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			// the users:write permission grants access to the route, but it must not allow escalating privileges
+			assert.True(t, isPermissionAllowed([]string{"users:write"}, openapi.PermissionUsersWrite))
+			err := validateGroupChange(tt.userGroups, tt.userPermissions, tt.currentGroups, tt.newGroups, listPermissions)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.ErrorIs(t, err, ErrForbiddenGroupChange)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/hoophq/hoop/common/apiutils"
 	"github.com/hoophq/hoop/common/log"
 	pb "github.com/hoophq/hoop/common/proto"
+	"github.com/hoophq/hoop/gateway/accesspolicy"
 	"github.com/hoophq/hoop/gateway/api/openapi"
 	"github.com/hoophq/hoop/gateway/clientexec"
 	"github.com/hoophq/hoop/gateway/models"
@@ -93,6 +94,7 @@ func Post(c *gin.Context) {
 		GuardRailRules:      req.GuardRailRules,
 		JiraIssueTemplateID: sql.NullString{String: req.JiraIssueTemplateID, Valid: true},
 		ConnectionTags:      req.ConnectionTags,
+		ReviewPolicy:        toReviewPolicy(req.ReviewPolicy),
 	})
 	if err != nil {
 		log.Errorf("failed creating connection, err=%v", err)
@@ -191,6 +193,7 @@ func Put(c *gin.Context) {
 		GuardRailRules:      req.GuardRailRules,
 		JiraIssueTemplateID: sql.NullString{String: req.JiraIssueTemplateID, Valid: true},
 		ConnectionTags:      req.ConnectionTags,
+		ReviewPolicy:        toReviewPolicy(req.ReviewPolicy),
 	})
 	if err != nil {
 		switch err.(type) {
@@ -284,7 +287,7 @@ func List(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
-	allowedFn, err := accessControlAllowed(ctx)
+	allowedFn, err := accessControlAllowed(ctx, accesspolicy.Request{SourceIP: c.ClientIP()})
 	if err != nil {
 		log.Errorf("failed validating connection access control, err=%v", err)
 		sentry.CaptureException(err)
@@ -293,7 +296,7 @@ func List(c *gin.Context) {
 	}
 	responseConnList := []openapi.Connection{}
 	for _, conn := range connList {
-		if allowedFn(conn.Name) {
+		if allowedFn(&conn) {
 			var managedBy *string
 			if conn.ManagedBy.Valid {
 				managedBy = &conn.ManagedBy.String
@@ -326,6 +329,7 @@ func List(c *gin.Context) {
 				AccessSchema:        conn.AccessSchema,
 				GuardRailRules:      conn.GuardRailRules,
 				JiraIssueTemplateID: conn.JiraIssueTemplateID.String,
+				ReviewPolicy:        toOpenApiReviewPolicy(conn.ReviewPolicy),
 			})
 		}
 
@@ -352,14 +356,14 @@ func Get(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
-	allowedFn, err := accessControlAllowed(ctx)
+	allowedFn, err := accessControlAllowed(ctx, accesspolicy.Request{SourceIP: c.ClientIP()})
 	if err != nil {
 		log.Errorf("failed validating connection access control, err=%v", err)
 		sentry.CaptureException(err)
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
-	if conn == nil || !allowedFn(conn.Name) {
+	if conn == nil || !allowedFn(conn) {
 		c.JSON(http.StatusNotFound, gin.H{"message": "not found"})
 		return
 	}
@@ -394,20 +398,25 @@ func Get(c *gin.Context) {
 		AccessSchema:        conn.AccessSchema,
 		GuardRailRules:      conn.GuardRailRules,
 		JiraIssueTemplateID: conn.JiraIssueTemplateID.String,
+		ReviewPolicy:        toOpenApiReviewPolicy(conn.ReviewPolicy),
 	})
 }
 
-// FetchByName fetches a connection based in access control rules
-func FetchByName(ctx pgrest.Context, connectionName string) (*models.Connection, error) {
+// FetchByName fetches a connection based in access control rules,
+// the request contains the attributes used to evaluate the access policies
+func FetchByName(ctx pgrest.Context, connectionName string, req accesspolicy.Request) (*models.Connection, error) {
 	conn, err := models.GetConnectionByNameOrID(ctx.GetOrgID(), connectionName)
 	if err != nil {
 		return nil, err
 	}
-	allowedFn, err := accessControlAllowed(ctx)
+	if conn == nil {
+		return nil, nil
+	}
+	allowedFn, err := accessControlAllowed(ctx, req)
 	if err != nil {
 		return nil, err
 	}
-	if conn == nil || !allowedFn(conn.Name) {
+	if !allowedFn(conn) {
 		return nil, nil
 	}
 	return conn, nil
@@ -427,7 +436,7 @@ func ListDatabases(c *gin.Context) {
 	ctx := storagev2.ParseContext(c)
 	connNameOrID := c.Param("nameOrID")
 
-	conn, err := FetchByName(ctx, connNameOrID)
+	conn, err := FetchByName(ctx, connNameOrID, accesspolicy.Request{SourceIP: c.ClientIP()})
 	if err != nil {
 		log.Errorf("failed fetching connection, err=%v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
@@ -568,7 +577,7 @@ func GetDatabaseSchemas(c *gin.Context) {
 		}
 	}
 
-	conn, err := FetchByName(ctx, connNameOrID)
+	conn, err := FetchByName(ctx, connNameOrID, accesspolicy.Request{SourceIP: c.ClientIP()})
 	if err != nil {
 		log.Errorf("failed fetching connection, err=%v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
@@ -12,12 +12,17 @@ import (
 	"unicode"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hoophq/hoop/common/log"
 	pb "github.com/hoophq/hoop/common/proto"
+	"github.com/hoophq/hoop/gateway/accesspolicy"
 	"github.com/hoophq/hoop/gateway/api/openapi"
 	apivalidation "github.com/hoophq/hoop/gateway/api/validation"
 	"github.com/hoophq/hoop/gateway/models"
 	"github.com/hoophq/hoop/gateway/pgrest"
 	pgplugins "github.com/hoophq/hoop/gateway/pgrest/plugins"
+	"github.com/hoophq/hoop/gateway/review"
+	"github.com/hoophq/hoop/gateway/security/apitoken"
+	"github.com/hoophq/hoop/gateway/storagev2/types"
 	plugintypes "github.com/hoophq/hoop/gateway/transport/plugins/types"
 )
 
@@ -27,7 +32,66 @@ var (
 	connectionTagsValRe, _ = regexp.Compile(`^[a-zA-Z0-9-_\+=@\/:\s]+$`)
 )
 
-func accessControlAllowed(ctx pgrest.Context) (func(connName string) bool, error) {
+// accessControlAllowed returns a function that validates if the user could access a connection.
+// The access policies of the organization are evaluated with the attributes of the request.
+func accessControlAllowed(ctx pgrest.Context, req accesspolicy.Request) (func(conn *models.Connection) bool, error) {
+	allowedFn, err := PluginAccessControlAllowed(ctx)
+	if err != nil {
+		return nil, err
+	}
+	policyAllowedFn, err := accessPolicyAllowed(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	// api tokens could be restricted to a list of connections
+	var tokenConnections []string
+	if tokenCtx, ok := ctx.(pgrest.TokenContext); ok {
+		tokenConnections = tokenCtx.GetTokenConnections()
+	}
+	return func(conn *models.Connection) bool {
+		return apitoken.IsConnectionAllowed(tokenConnections, conn.Name) &&
+			allowedFn(conn.Name) &&
+			policyAllowedFn(conn)
+	}, nil
+}
+
+// accessPolicyAllowed evaluates the access policies of the organization. The requests issued
+// by the gateway were evaluated by the api with the attributes of the client, thus they're always allowed.
+func accessPolicyAllowed(ctx pgrest.Context, req accesspolicy.Request) (func(conn *models.Connection) bool, error) {
+	if req.IssuedByGateway {
+		return func(_ *models.Connection) bool { return true }, nil
+	}
+	policies, err := models.ListAccessPolicies(ctx.GetOrgID())
+	if err != nil {
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return func(_ *models.Connection) bool { return true }, nil
+	}
+	return func(conn *models.Connection) bool {
+		decision := accesspolicy.Evaluate(policies, NewAccessPolicyRequest(ctx, conn, req))
+		if !decision.Allowed {
+			log.With("org", ctx.GetOrgID(), "connection", conn.Name).
+				Debugf("access policy denied the request, verb=%v, origin=%v, source-ip=%v, reason=%v",
+					req.Verb, req.Origin, req.SourceIP, decision.Reason)
+		}
+		return decision.Allowed
+	}, nil
+}
+
+// NewAccessPolicyRequest fills the attributes of the user and the connection in a request
+func NewAccessPolicyRequest(ctx pgrest.Context, conn *models.Connection, req accesspolicy.Request) accesspolicy.Request {
+	req.UserGroups = ctx.GetUserGroups()
+	req.ConnectionName = conn.Name
+	req.ConnectionType = conn.Type
+	req.ConnectionSubType = conn.SubType.String
+	req.ConnectionTags = conn.ConnectionTags
+	return req
+}
+
+// PluginAccessControlAllowed validates the access to connections
+// based on the groups of the users configured in the access control plugin
+func PluginAccessControlAllowed(ctx pgrest.Context) (func(connName string) bool, error) {
 	p, err := pgplugins.New().FetchOne(ctx, plugintypes.PluginAccessControlName)
 	if err != nil {
 		return nil, err
@@ -85,6 +149,11 @@ func GetConnectionDefaults(connType, connSubType string, useMongoConnStr bool) (
 	case pb.ConnectionTypeOracleDB:
 		envs["envvar:LD_LIBRARY_PATH"] = base64.StdEncoding.EncodeToString([]byte(`/opt/oracle/instantclient_19_24`))
 		cmd = []string{"sqlplus", "-s", "$USER/$PASS@$HOST:$PORT/$SID"}
+	case pb.ConnectionTypeRedis:
+		envs["envvar:PORT"] = base64.StdEncoding.EncodeToString([]byte(`6379`))
+		envs["envvar:USER"] = base64.StdEncoding.EncodeToString([]byte(`default`))
+		envs["envvar:DB"] = base64.StdEncoding.EncodeToString([]byte(`0`))
+		cmd = []string{"redis-cli", "-h", "$HOST", "-p", "$PORT", "--user", "$USER", "--pass", "$PASS", "--no-auth-warning", "-n", "$DB"}
 	case pb.ConnectionTypeMongoDB:
 		envs["envvar:OPTIONS"] = base64.StdEncoding.EncodeToString([]byte(`tls=true`))
 		envs["envvar:PORT"] = base64.StdEncoding.EncodeToString([]byte(`27017`))
@@ -128,6 +197,10 @@ func validateConnectionRequest(req openapi.Connection) error {
 		return fmt.Errorf(strings.Join(errors, "; "))
 	}
 
+	if err := review.ValidatePolicy(toReviewPolicy(req.ReviewPolicy), req.Reviewers); err != nil {
+		return err
+	}
+
 	if len(req.ConnectionTags) > 10 {
 		return fmt.Errorf("max tag association reached (10)")
 	}
@@ -509,3 +582,39 @@ func cleanMongoOutput(output string) string {
 
 	return output[startJSON:]
 }
+
+func toReviewPolicy(p *openapi.ReviewPolicy) *types.ReviewPolicy {
+	if p == nil {
+		return nil
+	}
+	return &types.ReviewPolicy{
+		MinApprovalsPerGroup:  p.MinApprovalsPerGroup,
+		MinApprovers:          p.MinApprovers,
+		Mode:                  p.Mode,
+		RequiredGroup:         p.RequiredGroup,
+		ExpirationSec:         p.ExpirationSec,
+		EscalationSec:         p.EscalationSec,
+		EscalationGroup:       p.EscalationGroup,
+		BreakGlass:            p.BreakGlass,
+		BreakGlassDurationSec: p.BreakGlassDurationSec,
+		BreakGlassMaxPerDay:   p.BreakGlassMaxPerDay,
+	}
+}
+
+func toOpenApiReviewPolicy(p *types.ReviewPolicy) *openapi.ReviewPolicy {
+	if p == nil {
+		return nil
+	}
+	return &openapi.ReviewPolicy{
+		MinApprovalsPerGroup:  p.MinApprovalsPerGroup,
+		MinApprovers:          p.MinApprovers,
+		Mode:                  p.Mode,
+		RequiredGroup:         p.RequiredGroup,
+		ExpirationSec:         p.ExpirationSec,
+		EscalationSec:         p.EscalationSec,
+		EscalationGroup:       p.EscalationGroup,
+		BreakGlass:            p.BreakGlass,
+		BreakGlassDurationSec: p.BreakGlassDurationSec,
+		BreakGlassMaxPerDay:   p.BreakGlassMaxPerDay,
+	}
+}
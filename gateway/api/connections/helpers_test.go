@@ -11,11 +11,13 @@ import (
 	"testing"
 
 	pb "github.com/hoophq/hoop/common/proto"
+	"github.com/hoophq/hoop/gateway/accesspolicy"
 	"github.com/hoophq/hoop/gateway/api/openapi"
 	"github.com/hoophq/hoop/gateway/models"
 	"github.com/hoophq/hoop/gateway/pgrest"
 	"github.com/hoophq/hoop/gateway/storagev2"
 	"github.com/hoophq/hoop/gateway/storagev2/types"
+	plugintypes "github.com/hoophq/hoop/gateway/transport/plugins/types"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -52,7 +54,7 @@ func createTestServer(plConn []*pgrest.PluginConnection) clientFunc {
 	})
 }
 
-func TestAccessControlAllowed(t *testing.T) {
+func TestPluginAccessControlAllowed(t *testing.T) {
 	u, _ := url.Parse("http://localhost:3000")
 	pgrest.WithBaseURL(u)
 	for _, tt := range []struct {
@@ -108,7 +110,7 @@ func TestAccessControlAllowed(t *testing.T) {
 		t.Run(tt.msg, func(t *testing.T) {
 			pgrest.WithHttpClient(tt.fakeClient)
 			ctx := storagev2.NewOrganizationContext("").WithUserInfo("", "", "", "", tt.groups)
-			allowed, err := accessControlAllowed(ctx)
+			allowed, err := PluginAccessControlAllowed(ctx)
 			if err != nil {
 				t.Fatalf("did not expect error, got %v", err)
 			}
@@ -120,6 +122,43 @@ func TestAccessControlAllowed(t *testing.T) {
 	}
 }
 
+func TestAccessControlAllowedTokenConnections(t *testing.T) {
+	u, _ := url.Parse("http://localhost:3000")
+	pgrest.WithBaseURL(u)
+	pgrest.WithHttpClient(createTestServer([]*pgrest.PluginConnection{}))
+	// the requests issued by the gateway skip the evaluation of access policies
+	req := accesspolicy.Request{Verb: pb.ClientVerbConnect, Origin: pb.ConnectionOriginClientProxyManager, IssuedByGateway: true}
+	for _, tt := range []struct {
+		msg   string
+		ctx   pgrest.Context
+		allow map[string]bool
+	}{
+		{
+			msg:   "it should restrict the connections of api tokens in the context of the api",
+			ctx:   storagev2.NewOrganizationContext("").WithUserInfo("", "", "", "", []string{types.GroupAdmin}).WithAPIToken("token-id", []string{"pg-a"}),
+			allow: map[string]bool{"pg-a": true, "pg-b": false},
+		},
+		{
+			msg:   "it should restrict the connections of api tokens in the context of the gateway",
+			ctx:   plugintypes.Context{UserGroups: []string{types.GroupAdmin}, TokenConnections: []string{"pg-a"}},
+			allow: map[string]bool{"pg-a": true, "pg-b": false},
+		},
+		{
+			msg:   "it should allow all connections when the context is not restricted",
+			ctx:   plugintypes.Context{UserGroups: []string{types.GroupAdmin}},
+			allow: map[string]bool{"pg-a": true, "pg-b": true},
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			allowedFn, err := accessControlAllowed(tt.ctx, req)
+			assert.NoError(t, err)
+			for name, want := range tt.allow {
+				assert.Equal(t, want, allowedFn(&models.Connection{Name: name}), name)
+			}
+		})
+	}
+}
+
 func TestConnectionFilterOptions(t *testing.T) {
 	for _, tt := range []struct {
 		msg     string
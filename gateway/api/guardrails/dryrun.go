@@ -0,0 +1,235 @@
+package apiguardrails
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoophq/hoop/common/log"
+	"github.com/hoophq/hoop/gateway/api/openapi"
+	"github.com/hoophq/hoop/gateway/guardrails"
+	"github.com/hoophq/hoop/gateway/models"
+	"github.com/hoophq/hoop/gateway/security/apitoken"
+	"github.com/hoophq/hoop/gateway/storagev2"
+)
+
+const maxReplaySessions = 100
+
+// TestGuardRailRules
+//
+//	@Summary		Test Guard Rail Rules
+//	@Description	Validate a set of rules against sample input and output without persisting it.
+//	@Description	It returns every rule that matched, the byte offsets of the match and why it matched.
+//	@Description	When a connection is provided, the rules are replayed against its latest sessions
+//	@Description	reporting how many sessions would have been blocked, it requires the permission to read the sessions of all users.
+//	@Tags			Guard Rails
+//	@Accept			json
+//	@Produce		json
+//	@Param			request		body		openapi.GuardRailTestRequest	true	"The request body resource"
+//	@Success		200			{object}	openapi.GuardRailTestResponse
+//	@Failure		400,403,404,422,500	{object}	openapi.HTTPError
+//	@Router			/guardrails/test [post]
+func Test(c *gin.Context) {
+	ctx := storagev2.ParseContext(c)
+	var req openapi.GuardRailTestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	for direction, rules := range map[string]map[string]any{"input": req.Input, "output": req.Output} {
+		if err := guardrails.ValidateRules(direction, rules); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+			return
+		}
+	}
+	// the replay reads the content of the sessions of any user of the connection
+	if req.ConnectionName != "" && !canReplaySessions(ctx) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"message": fmt.Sprintf("replaying sessions requires the %v permission", openapi.PermissionSessionsRead)})
+		return
+	}
+	if req.ConnectionName != "" && (req.SessionsLimit < 1 || req.SessionsLimit > maxReplaySessions) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"message": fmt.Sprintf("sessions_limit must be between 1 and %v", maxReplaySessions)})
+		return
+	}
+
+	resp := openapi.GuardRailTestResponse{Matches: []openapi.GuardRailTestMatch{}}
+	for _, sample := range []struct {
+		direction string
+		rules     map[string]any
+		data      string
+	}{
+		{"input", req.Input, req.SampleInput},
+		{"output", req.Output, req.SampleOutput},
+	} {
+		items, err := guardrails.Explain(sample.direction, sample.rules, []byte(sample.data))
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+			return
+		}
+		for _, item := range items {
+			offsets := []openapi.GuardRailMatchOffset{}
+			for _, o := range item.Offsets {
+				offsets = append(offsets, openapi.GuardRailMatchOffset{Start: o.Start, End: o.End})
+			}
+			resp.Matches = append(resp.Matches, openapi.GuardRailTestMatch{
+				Direction:   item.StreamDirection,
+				RuleIndex:   item.RuleIndex,
+				RuleType:    item.RuleType,
+				Action:      item.Action,
+				Offsets:     offsets,
+				Explanation: item.Reason,
+			})
+		}
+	}
+
+	if req.ConnectionName != "" {
+		conn, err := models.GetConnectionByNameOrID(ctx.GetOrgID(), req.ConnectionName)
+		if err != nil {
+			log.Errorf("failed fetching connection, reason=%v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "failed fetching connection"})
+			return
+		}
+		if conn == nil || !apitoken.IsConnectionAllowed(ctx.TokenConnections, conn.Name) {
+			c.JSON(http.StatusNotFound, gin.H{"message": "connection not found"})
+			return
+		}
+		resp.Replay, err = replaySessions(ctx.GetOrgID(), conn.Name, req)
+		if err != nil {
+			log.Errorf("failed replaying guard rail rules, reason=%v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// canReplaySessions returns true if the user is allowed to read the sessions of other users
+func canReplaySessions(ctx *storagev2.Context) bool {
+	return ctx.IsAuditorOrAdminUser() || ctx.HasPermission(string(openapi.PermissionSessionsRead))
+}
+
+// replaySessions evaluates the rules against the input and output of the latest sessions of a connection
+func replaySessions(orgID, connectionName string, req openapi.GuardRailTestRequest) (*openapi.GuardRailReplayResult, error) {
+	inputRules, err := encodeRules(req.Input)
+	if err != nil {
+		return nil, err
+	}
+	outputRules, err := encodeRules(req.Output)
+	if err != nil {
+		return nil, err
+	}
+	opt := models.NewSessionOption()
+	opt.ConnectionName = connectionName
+	opt.Limit = req.SessionsLimit
+	sessionList, err := models.ListSessions(orgID, opt)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing sessions: %v", err)
+	}
+	result := &openapi.GuardRailReplayResult{
+		ConnectionName:  connectionName,
+		BlockedSessions: []openapi.GuardRailReplaySession{},
+	}
+	for _, item := range sessionList.Items {
+		session, err := models.GetSessionByID(orgID, item.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed fetching session %v: %v", item.ID, err)
+		}
+		input, output, err := sessionStreams(session)
+		if err != nil {
+			log.Warnf("sid=%v - skipping session replay, reason=%v", session.ID, err)
+			continue
+		}
+		result.SessionsEvaluated++
+		var warned, reviewed, masked bool
+		var blockErr error
+		for _, stream := range []struct {
+			direction string
+			rules     []byte
+			data      []byte
+		}{
+			{"input", inputRules, input},
+			{"output", outputRules, output},
+		} {
+			res, err := guardrails.Evaluate(stream.direction, stream.rules, stream.data)
+			if _, ok := err.(*guardrails.ErrRuleMatch); ok {
+				blockErr = err
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			warned = warned || len(res.Warnings) > 0
+			reviewed = reviewed || res.Review != nil
+			masked = masked || len(res.Masks) > 0
+		}
+		if blockErr != nil {
+			result.Blocked++
+			result.BlockedSessions = append(result.BlockedSessions, openapi.GuardRailReplaySession{
+				ID:        session.ID,
+				UserEmail: session.UserEmail,
+				Reason:    blockErr.Error(),
+				CreatedAt: session.CreatedAt,
+			})
+			continue
+		}
+		if warned {
+			result.Warned++
+		}
+		if reviewed {
+			result.Reviewed++
+		}
+		if masked {
+			result.Masked++
+		}
+	}
+	return result, nil
+}
+
+// encodeRules encodes the rules in the same format it's loaded from the connection
+func encodeRules(rules map[string]any) ([]byte, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal([]map[string]any{rules})
+	if err != nil {
+		return nil, fmt.Errorf("failed encoding rules: %v", err)
+	}
+	return data, nil
+}
+
+// sessionStreams returns the input and output of a session from the audit event stream.
+// The input of one-off executions is stored apart of the event stream.
+func sessionStreams(s *models.Session) (input, output []byte, err error) {
+	input = []byte(s.BlobInput)
+	if len(s.BlobStream) == 0 {
+		return
+	}
+	var eventStream [][]any
+	if err := json.Unmarshal(s.BlobStream, &eventStream); err != nil {
+		return nil, nil, fmt.Errorf("failed decoding blob stream: %v", err)
+	}
+	for _, event := range eventStream {
+		if len(event) < 3 {
+			continue
+		}
+		eventType, _ := event[1].(string)
+		eventData, _ := event[2].(string)
+		data, err := base64.StdEncoding.DecodeString(eventData)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed decoding event data: %v", err)
+		}
+		switch eventType {
+		case "i":
+			if len(s.BlobInput) == 0 {
+				input = append(input, data...)
+			}
+		case "o", "e":
+			output = append(output, data...)
+		}
+	}
+	return
+}
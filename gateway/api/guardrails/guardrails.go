@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/hoophq/hoop/common/log"
 	"github.com/hoophq/hoop/gateway/api/openapi"
+	"github.com/hoophq/hoop/gateway/guardrails"
 	"github.com/hoophq/hoop/gateway/models"
 	"github.com/hoophq/hoop/gateway/storagev2"
 )
@@ -21,7 +22,7 @@ import (
 //	@Produce		json
 //	@Param			request		body		openapi.GuardRailRuleRequest	true	"The request body resource"
 //	@Success		201			{object}	openapi.GuardRailRuleResponse
-//	@Failure		400,409,500	{object}	openapi.HTTPError
+//	@Failure		400,409,422,500	{object}	openapi.HTTPError
 //	@Router			/guardrails [post]
 func Post(c *gin.Context) {
 	ctx := storagev2.ParseContext(c)
@@ -69,7 +70,7 @@ func Post(c *gin.Context) {
 //	@Produce		json
 //	@Param			request	body		openapi.GuardRailRuleRequest	true	"The request body resource"
 //	@Success		200		{object}	openapi.GuardRailRuleResponse
-//	@Failure		400,500	{object}	openapi.HTTPError
+//	@Failure		400,422,500	{object}	openapi.HTTPError
 //	@Router			/guardrails/{id} [put]
 func Put(c *gin.Context) {
 	ctx := storagev2.ParseContext(c)
@@ -92,6 +93,7 @@ func Put(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
 		return
 	case nil:
+		guardrails.InvalidateCache()
 		c.JSON(http.StatusOK, &openapi.GuardRailRuleResponse{
 			ID:          rule.ID,
 			Name:        rule.Name,
@@ -190,6 +192,7 @@ func Delete(c *gin.Context) {
 	case models.ErrNotFound:
 		c.JSON(http.StatusNotFound, gin.H{"message": "resource not found"})
 	case nil:
+		guardrails.InvalidateCache()
 		c.Writer.WriteHeader(http.StatusNoContent)
 	default:
 		log.Errorf("failed removing guard rail rules, reason=%v", err)
@@ -204,5 +207,11 @@ func parseRequestPayload(c *gin.Context) *openapi.GuardRailRuleRequest {
 		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return nil
 	}
+	for direction, rules := range map[string]map[string]any{"input": req.Input, "output": req.Output} {
+		if err := guardrails.ValidateRules(direction, rules); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+			return nil
+		}
+	}
 	return &req
 }
@@ -526,6 +526,71 @@ const docTemplate = `{
                 }
             }
         },
+        "/connections/{nameOrID}/jit/extension": {
+            "post": {
+                "description": "Request additional access time for the active jit review of the user in a connection.\nThe extension is reviewed by the same groups of the review, when approved the revoke time of the review is pushed out without dropping the connected sessions.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Request Review Extension",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name or UUID of the connection",
+                        "name": "nameOrID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The request body resource",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/openapi.ReviewExtensionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.Review"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
         "/connections/{nameOrID}/schemas": {
             "get": {
                 "description": "Get detailed schema information including tables, views, columns and indexes",
@@ -834,6 +899,76 @@ const docTemplate = `{
                             "$ref": "#/definitions/openapi.HTTPError"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/guardrails/test": {
+            "post": {
+                "description": "Validate a set of rules against sample input and output without persisting it.\nIt returns every rule that matched, the byte offsets of the match and why it matched.\nWhen a connection is provided, the rules are replayed against its latest sessions\nreporting how many sessions would have been blocked, it requires the permission to read the sessions of all users.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Guard Rails"
+                ],
+                "summary": "Test Guard Rail Rules",
+                "parameters": [
+                    {
+                        "description": "The request body resource",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/openapi.GuardRailTestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.GuardRailTestResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -928,6 +1063,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/openapi.HTTPError"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2236,9 +2377,36 @@ const docTemplate = `{
                 }
             }
         },
-        "/proxymanager/connect": {
+        "/policies": {
+            "get": {
+                "description": "List the access policies of the organization",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Access Policies"
+                ],
+                "summary": "List Access Policies",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/openapi.AccessPolicy"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "Send a connect request to the client. A successful response indicates the client has stablished a connection.\nIf the connection resource has the review enabled, it returns a successful response containing the link of the review in the ` + "`" + `Localtion` + "`" + ` header.",
+                "description": "Create a policy that allows or denies access to connections based on the groups of the user,\nthe tags and the type of the connection, the verb, the client origin, the time and the source ip of the request.\nPolicies restrict the access granted by the groups of the users, they're evaluated in the api and when clients connect to the gateway.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2246,9 +2414,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Proxy Manager"
+                    "Access Policies"
                 ],
-                "summary": "ProxyManager Connect",
+                "summary": "Create Access Policy",
                 "parameters": [
                     {
                         "description": "The request body resource",
@@ -2256,21 +2424,15 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/openapi.ProxyManagerRequest"
+                            "$ref": "#/definitions/openapi.AccessPolicyRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/openapi.ProxyManagerResponse"
-                        },
-                        "headers": {
-                            "Location": {
-                                "type": "string",
-                                "description": "It will contain the url of the review in case the connection resource has the review enabled"
-                            }
+                            "$ref": "#/definitions/openapi.AccessPolicy"
                         }
                     },
                     "400": {
@@ -2279,8 +2441,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/openapi.HTTPError"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/openapi.HTTPError"
                         }
@@ -2300,21 +2462,70 @@ const docTemplate = `{
                 }
             }
         },
-        "/proxymanager/disconnect": {
-            "post": {
-                "description": "Send a disconnect request. The transport layer will disconnect the connected client asynchronously",
+        "/policies/evaluate": {
+            "get": {
+                "description": "Explain if a user is allowed to access a connection, the outcome of each policy is returned.\nThe attributes of the request that are not provided default to the attributes of the current request.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Proxy Manager"
+                    "Access Policies"
+                ],
+                "summary": "Evaluate Access Policies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The name of the connection",
+                        "name": "connection",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The email or the subject of the user, it defaults to the authenticated user",
+                        "name": "user",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "enum": [
+                            "exec",
+                            "connect"
+                        ],
+                        "description": "The verb of the request, an empty value evaluates reading the connection",
+                        "name": "verb",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "enum": [
+                            "client",
+                            "client-api",
+                            "client-api-runbooks",
+                            "client-proxymanager"
+                        ],
+                        "description": "The client origin of the request",
+                        "name": "origin",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The source ip of the request, it defaults to the ip of the client",
+                        "name": "source_ip",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The time of the request in RFC3339 format, it defaults to the current time",
+                        "name": "time",
+                        "in": "query"
+                    }
                 ],
-                "summary": "ProxyManager Disconnect",
                 "responses": {
-                    "202": {
-                        "description": "Accepted",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/openapi.ProxyManagerResponse"
+                            "$ref": "#/definitions/openapi.AccessPolicyEvaluation"
                         }
                     },
                     "404": {
@@ -2338,36 +2549,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/proxymanager/status": {
+        "/policies/{id}": {
             "get": {
-                "description": "Get the current status of the client",
+                "description": "Get an Access Policy",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Proxy Manager"
+                    "Access Policies"
                 ],
-                "summary": "ProxyManager Status",
+                "summary": "Get Access Policy",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "string",
-                        "description": "Filter by type",
-                        "name": "type",
-                        "in": "query"
+                        "description": "The unique identifier of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/openapi.ProxyManagerResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/openapi.HTTPError"
+                            "$ref": "#/definitions/openapi.AccessPolicy"
                         }
                     },
                     "404": {
@@ -2383,113 +2588,42 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/publicserverinfo": {
-            "get": {
-                "description": "Get public server information",
+            },
+            "put": {
+                "description": "Update an Access Policy",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Server Management"
+                    "Access Policies"
                 ],
-                "summary": "Get Public Server Info",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/openapi.PublicServerInfo"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/openapi.HTTPError"
-                        }
-                    }
-                }
-            }
-        },
-        "/reports/sessions": {
-            "get": {
-                "description": "The report payload groups sessions by info types and by a custom field (` + "`" + `group_by` + "`" + `) provided by the client.\nThe items returns data containing the sum of redact fields performed by a given info type aggregated by the ` + "`" + `group_by` + "`" + ` attribute.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Reports"
-                ],
-                "summary": "Session Reports",
+                "summary": "Update Access Policy",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "pgdemo",
-                        "description": "Filter by the connection name",
-                        "name": "connection_name",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "format": "date",
-                        "example": "2024-07-30",
-                        "description": "End Date, default to current date + 1 day",
-                        "name": "end_date",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "connection",
-                            "connection_type",
-                            "id",
-                            "user_email"
-                        ],
-                        "type": "string",
-                        "default": "connection",
-                        "example": "connection_type",
-                        "description": "Group by this field",
-                        "name": "group_by",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "FF970D17-23C6-4254-ABE6-103A9DDF30EE",
-                        "description": "Filter the report by the ID of the session",
+                        "description": "The unique identifier of the resource",
                         "name": "id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "format": "date",
-                        "example": "2024-07-29",
-                        "description": "Start Date, default to current date",
-                        "name": "start_date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "johnwick@bad.org",
-                        "description": "Filter the report by e-mail",
-                        "name": "user_email",
-                        "in": "query"
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "enum": [
-                            "exec",
-                            "connect"
-                        ],
-                        "type": "string",
-                        "example": "exec",
-                        "description": "Filter the report by the type of the verb",
-                        "name": "verb",
-                        "in": "query"
+                        "description": "The request body resource",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/openapi.AccessPolicyRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/openapi.SessionReport"
+                            "$ref": "#/definitions/openapi.AccessPolicy"
                         }
                     },
                     "400": {
@@ -2498,33 +2632,22 @@ const docTemplate = `{
                             "$ref": "#/definitions/openapi.HTTPError"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/openapi.HTTPError"
                         }
-                    }
-                }
-            }
-        },
-        "/reviews": {
-            "get": {
-                "description": "List review resources",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Reviews"
-                ],
-                "summary": "List Reviews",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/openapi.Review"
-                            }
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
                         }
                     },
                     "500": {
@@ -2534,33 +2657,28 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/reviews/{id}": {
-            "get": {
-                "description": "Get review resource by id",
+            },
+            "delete": {
+                "description": "Delete an Access Policy",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Reviews"
+                    "Access Policies"
                 ],
-                "summary": "Get Review",
+                "summary": "Delete Access Policy",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Resource identifier of the review",
+                        "description": "The unique identifier of the resource",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/openapi.Review"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "404": {
                         "description": "Not Found",
@@ -2575,9 +2693,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update the status of a review resource",
+            }
+        },
+        "/proxymanager/connect": {
+            "post": {
+                "description": "Send a connect request to the client. A successful response indicates the client has stablished a connection.\nIf the connection resource has the review enabled, it returns a successful response containing the link of the review in the ` + "`" + `Localtion` + "`" + ` header.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2585,24 +2705,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Reviews"
+                    "Proxy Manager"
                 ],
-                "summary": "Update Review Status",
+                "summary": "ProxyManager Connect",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Resource identifier of the review",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "description": "The request body resource",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/openapi.ReviewRequest"
+                            "$ref": "#/definitions/openapi.ProxyManagerRequest"
                         }
                     }
                 ],
@@ -2610,7 +2723,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/openapi.Review"
+                            "$ref": "#/definitions/openapi.ProxyManagerResponse"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "It will contain the url of the review in case the connection resource has the review enabled"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
                         }
                     },
                     "404": {
@@ -2619,6 +2744,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/openapi.HTTPError"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2628,21 +2759,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/serverinfo": {
-            "get": {
-                "description": "Get server information",
+        "/proxymanager/disconnect": {
+            "post": {
+                "description": "Send a disconnect request. The transport layer will disconnect the connected client asynchronously",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Server Management"
+                    "Proxy Manager"
                 ],
-                "summary": "Get Server Info",
+                "summary": "ProxyManager Disconnect",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/openapi.ServerInfo"
+                            "$ref": "#/definitions/openapi.ProxyManagerResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
                         }
                     },
                     "500": {
@@ -2654,24 +2797,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/serviceaccounts": {
+        "/proxymanager/status": {
             "get": {
-                "description": "List all service accounts",
+                "description": "Get the current status of the client",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User Management"
+                    "Proxy Manager"
+                ],
+                "summary": "ProxyManager Status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "string",
+                        "description": "Filter by type",
+                        "name": "type",
+                        "in": "query"
+                    }
                 ],
-                "summary": "List Service Accounts",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/openapi.ServiceAccount"
-                            }
+                            "$ref": "#/definitions/openapi.ProxyManagerResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
                         }
                     },
                     "500": {
@@ -2681,35 +2842,113 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a service account that is associated with a identity provider client",
-                "consumes": [
+            }
+        },
+        "/publicserverinfo": {
+            "get": {
+                "description": "Get public server information",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "Server Management"
+                ],
+                "summary": "Get Public Server Info",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.PublicServerInfo"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/sessions": {
+            "get": {
+                "description": "The report payload groups sessions by info types and by a custom field (` + "`" + `group_by` + "`" + `) provided by the client.\nThe items returns data containing the sum of redact fields performed by a given info type aggregated by the ` + "`" + `group_by` + "`" + ` attribute.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User Management"
+                    "Reports"
                 ],
-                "summary": "Create Service Account",
+                "summary": "Session Reports",
                 "parameters": [
                     {
-                        "description": "The request body resource",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/openapi.ServiceAccount"
-                        }
+                        "type": "string",
+                        "example": "pgdemo",
+                        "description": "Filter by the connection name",
+                        "name": "connection_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "example": "2024-07-30",
+                        "description": "End Date, default to current date + 1 day",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "connection",
+                            "connection_type",
+                            "id",
+                            "user_email"
+                        ],
+                        "type": "string",
+                        "default": "connection",
+                        "example": "connection_type",
+                        "description": "Group by this field",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "FF970D17-23C6-4254-ABE6-103A9DDF30EE",
+                        "description": "Filter the report by the ID of the session",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date",
+                        "example": "2024-07-29",
+                        "description": "Start Date, default to current date",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "johnwick@bad.org",
+                        "description": "Filter the report by e-mail",
+                        "name": "user_email",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exec",
+                            "connect"
+                        ],
+                        "type": "string",
+                        "example": "exec",
+                        "description": "Filter the report by the type of the verb",
+                        "name": "verb",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/openapi.ServiceAccount"
+                            "$ref": "#/definitions/openapi.SessionReport"
                         }
                     },
                     "400": {
@@ -2718,16 +2957,33 @@ const docTemplate = `{
                             "$ref": "#/definitions/openapi.HTTPError"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/openapi.HTTPError"
                         }
-                    },
-                    "422": {
-                        "description": "Unprocessable Entity",
+                    }
+                }
+            }
+        },
+        "/retention-policies": {
+            "get": {
+                "description": "List the retention policies of the organization",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Retention Policies"
+                ],
+                "summary": "List Retention Policies",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/openapi.HTTPError"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/openapi.RetentionPolicy"
+                            }
                         }
                     },
                     "500": {
@@ -2737,11 +2993,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/serviceaccounts/{subject}": {
-            "put": {
-                "description": "Update a service account that is associated with a identity provider client",
+            },
+            "post": {
+                "description": "Create a policy to purge the content of sessions after a number of days.\nThe policies are enforced periodically by the gateway.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2749,32 +3003,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User Management"
+                    "Retention Policies"
                 ],
-                "summary": "Update Service Account",
+                "summary": "Create Retention Policy",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "The subject identifier of the service account",
-                        "name": "subject",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "description": "The request body resource",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/openapi.ServiceAccount"
+                            "$ref": "#/definitions/openapi.RetentionPolicyRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/openapi.ServiceAccount"
+                            "$ref": "#/definitions/openapi.RetentionPolicy"
                         }
                     },
                     "400": {
@@ -2783,8 +3030,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/openapi.HTTPError"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/openapi.HTTPError"
                         }
@@ -2804,67 +3051,36 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions": {
+        "/retention-policies/{id}": {
             "get": {
-                "description": "List session resources",
+                "description": "Get a Retention Policy",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Sessions"
+                    "Retention Policies"
                 ],
-                "summary": "List Sessions",
+                "summary": "Get Retention Policy",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Filter by user's subject id",
-                        "name": "user",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by connection's name",
-                        "name": "connection",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by connection's type",
-                        "name": "type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "format": "RFC3339",
-                        "description": "Filter starting on this date",
-                        "name": "start_date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "format": "RFC3339",
-                        "description": "Filter ending on this date",
-                        "name": "end_date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Limit the amount of records to return (max: 100)",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Offset to paginate through resources",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "The unique identifier of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/openapi.SessionList"
+                            "$ref": "#/definitions/openapi.RetentionPolicy"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
                         }
                     },
                     "500": {
@@ -2875,8 +3091,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
-                "description": "This endpoint performs ad-hoc executions. It will wait 50 seconds for a sucessful response (200), otherwise return an Accepted status code (202) meaning the execution will be held asynchronously. The outcome could be obtained later on by fetching the resource using the attribute ` + "`" + `id` + "`" + `.\n\nThe payload of this request is used with the Connection resource to construct the command to be executed in the remote agent.\n- The ` + "`" + `script` + "`" + ` attribute is passed as stdin to the Connection resource ` + "`" + `command` + "`" + ` attribute.\n- The attribute ` + "`" + `client_args` + "`" + ` is appended to the suffix of the ` + "`" + `command` + "`" + `.\n\nFor example, the following connection:\n\n` + "`" + `` + "`" + `` + "`" + `json\n{\n  \"name\": \"bash-connection\",\n  \"command\": [\"/bin/bash\"],\n  \"type\": \"custom\"\n}\n` + "`" + `` + "`" + `` + "`" + `\n\nWith the following payload:\n\n` + "`" + `` + "`" + `` + "`" + `json\n{\n  \"script\": \"echo 'hello world'\",\n  \"client_args\": [\"-x\"],\n  \"connection\": \"bash-connection\"\n}\n` + "`" + `` + "`" + `` + "`" + `\n\nWill perform an ad-hoc shell execution as:\n\n` + "`" + `` + "`" + `` + "`" + `sh\n/bin/bash -x \u003c\u003cEOF\necho 'hello world'\nEOF\n` + "`" + `` + "`" + `` + "`" + `\n",
+            "put": {
+                "description": "Update a Retention Policy",
                 "consumes": [
                     "application/json"
                 ],
@@ -2884,35 +3100,48 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Sessions"
+                    "Retention Policies"
                 ],
-                "summary": "Exec",
+                "summary": "Update Retention Policy",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The unique identifier of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "description": "The request body resource",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/openapi.ExecRequest"
+                            "$ref": "#/definitions/openapi.RetentionPolicyRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "The execution has finished",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/openapi.ExecResponse"
+                            "$ref": "#/definitions/openapi.RetentionPolicy"
                         }
                     },
-                    "202": {
-                        "description": "The execution is still in progress",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/openapi.ExecResponse"
+                            "$ref": "#/definitions/openapi.HTTPError"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/openapi.HTTPError"
                         }
@@ -2930,99 +3159,28 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/sessions/{session_id}": {
-            "get": {
-                "description": "Get a session by id. This endpoint returns a conditional response\n\n- When the query string ` + "`" + `extension` + "`" + ` is present it will return a payload containing a link to download the session\n\n` + "`" + `` + "`" + `` + "`" + `json\n{\n  \"download_url\": \"http://127.0.0.1:8009/api/sessions/\u003cid\u003e/download?token=\u003ctoken\u003e\u0026extension=csv\u0026newline=1\u0026event-time=0\u0026events=o,e\",\n  \"expire_at\": \"2024-07-25T15:56:35.317601Z\",\n}\n` + "`" + `` + "`" + `` + "`" + `\n\n- Fetching the endpoint without any query string returns the payload documented for this endpoint\n- The attribute ` + "`" + `event_stream` + "`" + ` will be rendered differently if the request contains the query string ` + "`" + `event_stream=utf8` + "`" + `\n\n` + "`" + `` + "`" + `` + "`" + `json\n{\n  (...)\n  \"event_stream\": [\"hello world\"]\n  (...)\n}\n` + "`" + `` + "`" + `` + "`" + `\n\nThe attribute ` + "`" + `metrics` + "`" + ` contains the following structure:\n\n` + "`" + `` + "`" + `` + "`" + `json\n{\n  \"data_masking\": {\n    \"err_count\": 0,\n    \"info_types\": {\n      \"EMAIL_ADDRESS\": 1\n    },\n    \"total_redact_count\": 1,\n    \"transformed_bytes\": 31\n  },\n  \"event_size\": 356\n}\n` + "`" + `` + "`" + `` + "`" + `\n",
+            },
+            "delete": {
+                "description": "Delete a Retention Policy, the sessions already purged are not affected",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Sessions"
+                    "Retention Policies"
                 ],
-                "summary": "Get Session",
+                "summary": "Delete Retention Policy",
                 "parameters": [
                     {
-                        "enum": [
-                            "0",
-                            "1"
-                        ],
                         "type": "string",
-                        "default": "0",
-                        "example": "1",
-                        "description": "Construct the file content adding the event time as prefix when parsing each event",
-                        "name": "event-time",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "utf8",
-                            "base64"
-                        ],
-                        "type": "string",
-                        "description": "This option will parse the session output (o) and error (e) events as an utf-8 content in the session payload",
-                        "name": "event_stream",
-                        "in": "query"
-                    },
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "csv",
-                        "example": [
-                            "i",
-                            "o",
-                            "e"
-                        ],
-                        "description": "Choose the type of events to include\n* ` + "`" + `i` + "`" + ` - Input (stdin)\n* ` + "`" + `o` + "`" + ` - Output (stdout)\n* ` + "`" + `e` + "`" + ` - Error (stderr)",
-                        "name": "events",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "event_stream"
-                        ],
-                        "type": "string",
-                        "example": "event_stream",
-                        "description": "Expand the given attributes",
-                        "name": "expand",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "csv",
-                        "description": "The file extension to donwload the session as a file content.\n* ` + "`" + `csv` + "`" + ` - it will parse the content to format in csv format\n* ` + "`" + `json` + "`" + ` - it will parse the content as a json stream.\n* ` + "`" + `\u003cany-format\u003e` + "`" + ` - No special parsing is applied",
-                        "name": "extension",
-                        "in": "query"
-                    },
-                    {
-                        "enum": [
-                            "0",
-                            "1"
-                        ],
-                        "type": "string",
-                        "default": "0",
-                        "example": "1",
-                        "description": "Construct the file content adding a break line when parsing each event",
-                        "name": "new_line",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "The id of the resource",
-                        "name": "session_id",
+                        "description": "The unique identifier of the resource",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/openapi.Session"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "404": {
                         "description": "Not Found",
@@ -3039,52 +3197,71 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{session_id}/download": {
+        "/retention-purges": {
             "get": {
-                "description": "Download session by id",
+                "description": "List the sessions purged by retention policies, the most recent first",
                 "produces": [
-                    "application/octet-stream",
                     "application/json"
                 ],
                 "tags": [
-                    "Sessions"
+                    "Retention Policies"
                 ],
-                "summary": "Download Session",
+                "summary": "List Retention Purges",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "The id of the resource",
-                        "name": "session_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter by connection's name",
+                        "name": "connection",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit the amount of records to return (max: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset to paginate through resources",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
-                        },
-                        "headers": {
-                            "Accept-Length": {
-                                "type": "integer",
-                                "description": "size in bytes of the content"
-                            },
-                            "Content-Disposition": {
-                                "type": "string",
-                                "description": "application/octet-stream"
-                            },
-                            "Content-Type": {
-                                "type": "string",
-                                "description": "application/octet-stream"
-                            }
+                            "$ref": "#/definitions/openapi.RetentionPurgeList"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/openapi.HTTPError"
                         }
+                    }
+                }
+            }
+        },
+        "/reviews": {
+            "get": {
+                "description": "List review resources",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "List Reviews",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/openapi.Review"
+                            }
+                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -3093,11 +3270,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/sessions/{session_id}/exec": {
+            },
             "post": {
-                "description": "Run an execution in a reviewed session",
+                "description": "Request access to a connection for a future time window. The groups of the connection review the request in advance,\nwhen approved the sessions of the user are admitted only inside the window without a review per session.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3105,29 +3280,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Core"
+                    "Reviews"
                 ],
-                "summary": "Reviewed Exec",
+                "summary": "Create Scheduled Review",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "The id of the resource",
-                        "name": "session_id",
-                        "in": "path",
-                        "required": true
+                        "description": "The request body resource",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/openapi.ScheduledReviewRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "The execution has finished",
-                        "schema": {
-                            "$ref": "#/definitions/openapi.ExecResponse"
-                        }
-                    },
-                    "202": {
-                        "description": "The execution is still in progress",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/openapi.ExecResponse"
+                            "$ref": "#/definitions/openapi.Review"
                         }
                     },
                     "400": {
@@ -3142,12 +3313,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/openapi.HTTPError"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
-                        "schema": {
-                            "$ref": "#/definitions/openapi.HTTPError"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -3157,29 +3322,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{session_id}/kill": {
-            "post": {
+        "/reviews/{id}": {
+            "get": {
+                "description": "Get review resource by id",
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Sessions"
+                    "Reviews"
                 ],
-                "summary": "Kill Session",
+                "summary": "Get Review",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "The id of the resource",
-                        "name": "session_id",
+                        "description": "Resource identifier of the review",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/openapi.HTTPError"
+                            "$ref": "#/definitions/openapi.Review"
                         }
                     },
                     "404": {
@@ -3195,10 +3361,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/sessions/{session_id}/metadata": {
-            "patch": {
+            },
+            "put": {
+                "description": "Update the status of a review resource",
                 "consumes": [
                     "application/json"
                 ],
@@ -3206,28 +3371,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Sessions"
+                    "Reviews"
                 ],
-                "summary": "Update Session Metadata",
+                "summary": "Update Review Status",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Resource identifier of the review",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "description": "The request body resource",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/openapi.SessionUpdateMetadataRequest"
+                            "$ref": "#/definitions/openapi.ReviewRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/openapi.HTTPError"
+                            "$ref": "#/definitions/openapi.Review"
                         }
                     },
                     "404": {
@@ -3245,9 +3414,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/sessions/{session_id}/review": {
+        "/reviews/{id}/extension": {
             "put": {
-                "description": "Update the status of a review resource by the session id",
+                "description": "Approve or reject the pending extension of a jit review",
                 "consumes": [
                     "application/json"
                 ],
@@ -3257,12 +3426,12 @@ const docTemplate = `{
                 "tags": [
                     "Reviews"
                 ],
-                "summary": "Update Review Status By Sid",
+                "summary": "Update Review Extension Status",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Resource identifier of the session",
-                        "name": "session_id",
+                        "description": "Resource identifier of the review",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
@@ -3283,6 +3452,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/openapi.Review"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -3298,35 +3479,62 @@ const docTemplate = `{
                 }
             }
         },
-        "/signup": {
-            "post": {
-                "description": "Signup anonymous authenticated user. This endpoint is only used for multi tenant setups.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/roles": {
+            "get": {
+                "description": "List the custom roles of the organization",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "Roles"
                 ],
-                "summary": "Signup",
-                "parameters": [
-                    {
-                        "description": "The request body resource",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                "summary": "List Roles",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/openapi.SignupRequest"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/openapi.Role"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a custom role that grants permissions to the users of its groups.\nThe permissions are granted on top of the access provided by the default groups.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Roles"
+                ],
+                "summary": "Create Role",
+                "parameters": [
+                    {
+                        "description": "The request body resource",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/openapi.RoleRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/openapi.SignupRequest"
+                            "$ref": "#/definitions/openapi.Role"
                         }
                     },
                     "400": {
@@ -3356,51 +3564,37 @@ const docTemplate = `{
                 }
             }
         },
-        "/userinfo": {
+        "/roles/{id}": {
             "get": {
-                "description": "Get own user's information",
+                "description": "Get a Role",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User Management"
+                    "Roles"
+                ],
+                "summary": "Get Role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The unique identifier of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get UserInfo",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/openapi.UserInfo"
+                            "$ref": "#/definitions/openapi.Role"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/openapi.HTTPError"
                         }
-                    }
-                }
-            }
-        },
-        "/users": {
-            "get": {
-                "description": "List all users",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "User Management"
-                ],
-                "summary": "List Users",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/openapi.User"
-                            }
-                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -3410,8 +3604,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
-                "description": "Inviting a user will pre configure user definitions like display name, profile picture, groups or his slack id",
+            "put": {
+                "description": "Update a Role, the changes are applied on the next request of the users",
                 "consumes": [
                     "application/json"
                 ],
@@ -3419,25 +3613,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User Management"
+                    "Roles"
                 ],
-                "summary": "Invite User",
+                "summary": "Update Role",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The unique identifier of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "description": "The request body resource",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/openapi.User"
+                            "$ref": "#/definitions/openapi.RoleRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/openapi.User"
+                            "$ref": "#/definitions/openapi.Role"
                         }
                     },
                     "400": {
@@ -3446,6 +3647,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/openapi.HTTPError"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
                     "409": {
                         "description": "Conflict",
                         "schema": {
@@ -3465,26 +3672,33 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/users/groups": {
-            "get": {
-                "description": "List all groups from all users",
+            },
+            "delete": {
+                "description": "Delete a Role, the users of its groups lose the permissions granted by it",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User Management"
+                    "Roles"
+                ],
+                "summary": "Delete Role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The unique identifier of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "List User Groups",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/openapi.HTTPError"
                         }
                     },
                     "500": {
@@ -3496,44 +3710,50 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/self/slack": {
-            "patch": {
-                "description": "Patch own user's slack id",
+        "/serverinfo": {
+            "get": {
+                "description": "Get server information",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User Management"
-                ],
-                "summary": "Patch User Slack ID",
-                "parameters": [
-                    {
-                        "description": "The request body resource",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/openapi.UserPatchSlackID"
-                        }
-                    }
+                    "Server Management"
                 ],
+                "summary": "Get Server Info",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/openapi.User"
+                            "$ref": "#/definitions/openapi.ServerInfo"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/openapi.HTTPError"
                         }
-                    },
-                    "422": {
-                        "description": "Unprocessable Entity",
+                    }
+                }
+            }
+        },
+        "/serviceaccounts": {
+            "get": {
+                "description": "List all service accounts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Management"
+                ],
+                "summary": "List Service Accounts",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/openapi.HTTPError"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/openapi.ServiceAccount"
+                            }
                         }
                     },
                     "500": {
@@ -3543,36 +3763,51 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/users/{emailOrID}": {
-            "get": {
-                "description": "Get user by email or subject id",
+            },
+            "post": {
+                "description": "Create a service account that is associated with a identity provider client",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "User Management"
                 ],
-                "summary": "Get User",
+                "summary": "Create Service Account",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "The subject identifier or email of the user",
-                        "name": "emailOrID",
-                        "in": "path",
-                        "required": true
+                        "description": "The request body resource",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/openapi.ServiceAccount"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/openapi.User"
+                            "$ref": "#/definitions/openapi.ServiceAccount"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
                             "$ref": "#/definitions/openapi.HTTPError"
                         }
@@ -3586,9 +3821,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}": {
+        "/serviceaccounts/{subject}": {
             "put": {
-                "description": "Updates an existing user",
+                "description": "Update a service account that is associated with a identity provider client",
                 "consumes": [
                     "application/json"
                 ],
@@ -3598,12 +3833,12 @@ const docTemplate = `{
                 "tags": [
                     "User Management"
                 ],
-                "summary": "Update User",
+                "summary": "Update Service Account",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "The subject identifier of the user",
-                        "name": "id",
+                        "description": "The subject identifier of the service account",
+                        "name": "subject",
                         "in": "path",
                         "required": true
                     },
@@ -3613,7 +3848,7 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/openapi.User"
+                            "$ref": "#/definitions/openapi.ServiceAccount"
                         }
                     }
                 ],
@@ -3621,7 +3856,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/openapi.User"
+                            "$ref": "#/definitions/openapi.ServiceAccount"
                         }
                     },
                     "400": {
@@ -3630,6 +3865,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/openapi.HTTPError"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
                     "422": {
                         "description": "Unprocessable Entity",
                         "schema": {
@@ -3643,31 +3884,117 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete a user.",
-                "produces": [
+            }
+        },
+        "/sessions": {
+            "get": {
+                "description": "List session resources",
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User Management"
+                    "Sessions"
                 ],
-                "summary": "Delete User",
+                "summary": "List Sessions",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "The subject identifier of the user",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter by user's subject id",
+                        "name": "user",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by connection's name",
+                        "name": "connection",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by connection's type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "RFC3339",
+                        "description": "Filter starting on this date",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "RFC3339",
+                        "description": "Filter ending on this date",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit the amount of records to return (max: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset to paginate through resources",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.SessionList"
+                        }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "This endpoint performs ad-hoc executions. It will wait 50 seconds for a sucessful response (200), otherwise return an Accepted status code (202) meaning the execution will be held asynchronously. The outcome could be obtained later on by fetching the resource using the attribute ` + "`" + `id` + "`" + `.\n\nThe payload of this request is used with the Connection resource to construct the command to be executed in the remote agent.\n- The ` + "`" + `script` + "`" + ` attribute is passed as stdin to the Connection resource ` + "`" + `command` + "`" + ` attribute.\n- The attribute ` + "`" + `client_args` + "`" + ` is appended to the suffix of the ` + "`" + `command` + "`" + `.\n\nFor example, the following connection:\n\n` + "`" + `` + "`" + `` + "`" + `json\n{\n  \"name\": \"bash-connection\",\n  \"command\": [\"/bin/bash\"],\n  \"type\": \"custom\"\n}\n` + "`" + `` + "`" + `` + "`" + `\n\nWith the following payload:\n\n` + "`" + `` + "`" + `` + "`" + `json\n{\n  \"script\": \"echo 'hello world'\",\n  \"client_args\": [\"-x\"],\n  \"connection\": \"bash-connection\"\n}\n` + "`" + `` + "`" + `` + "`" + `\n\nWill perform an ad-hoc shell execution as:\n\n` + "`" + `` + "`" + `` + "`" + `sh\n/bin/bash -x \u003c\u003cEOF\necho 'hello world'\nEOF\n` + "`" + `` + "`" + `` + "`" + `\n",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sessions"
+                ],
+                "summary": "Exec",
+                "parameters": [
+                    {
+                        "description": "The request body resource",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/openapi.ExecRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The execution has finished",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.ExecResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "The execution is still in progress",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.ExecResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/openapi.HTTPError"
                         }
@@ -3687,146 +4014,1663 @@ const docTemplate = `{
                 }
             }
         },
-        "/webhooks-dashboard": {
+        "/sessions/{session_id}": {
             "get": {
-                "description": "Get webhooks dashboard url",
+                "description": "Get a session by id. This endpoint returns a conditional response\n\n- When the query string ` + "`" + `extension` + "`" + ` is present it will return a payload containing a link to download the session\n\n` + "`" + `` + "`" + `` + "`" + `json\n{\n  \"download_url\": \"http://127.0.0.1:8009/api/sessions/\u003cid\u003e/download?token=\u003ctoken\u003e\u0026extension=csv\u0026newline=1\u0026event-time=0\u0026events=o,e\",\n  \"expire_at\": \"2024-07-25T15:56:35.317601Z\",\n}\n` + "`" + `` + "`" + `` + "`" + `\n\n- Fetching the endpoint without any query string returns the payload documented for this endpoint\n- The attribute ` + "`" + `event_stream` + "`" + ` will be rendered differently if the request contains the query string ` + "`" + `event_stream=utf8` + "`" + `\n\n` + "`" + `` + "`" + `` + "`" + `json\n{\n  (...)\n  \"event_stream\": [\"hello world\"]\n  (...)\n}\n` + "`" + `` + "`" + `` + "`" + `\n\nThe attribute ` + "`" + `metrics` + "`" + ` contains the following structure:\n\n` + "`" + `` + "`" + `` + "`" + `json\n{\n  \"data_masking\": {\n    \"err_count\": 0,\n    \"info_types\": {\n      \"EMAIL_ADDRESS\": 1\n    },\n    \"total_redact_count\": 1,\n    \"transformed_bytes\": 31\n  },\n  \"event_size\": 356\n}\n` + "`" + `` + "`" + `` + "`" + `\n",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Features"
+                    "Sessions"
+                ],
+                "summary": "Get Session",
+                "parameters": [
+                    {
+                        "enum": [
+                            "0",
+                            "1"
+                        ],
+                        "type": "string",
+                        "default": "0",
+                        "example": "1",
+                        "description": "Construct the file content adding the event time as prefix when parsing each event",
+                        "name": "event-time",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "utf8",
+                            "base64"
+                        ],
+                        "type": "string",
+                        "description": "This option will parse the session output (o) and error (e) events as an utf-8 content in the session payload",
+                        "name": "event_stream",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "example": [
+                            "i",
+                            "o",
+                            "e"
+                        ],
+                        "description": "Choose the type of events to include\n* ` + "`" + `i` + "`" + ` - Input (stdin)\n* ` + "`" + `o` + "`" + ` - Output (stdout)\n* ` + "`" + `e` + "`" + ` - Error (stderr)\n* ` + "`" + `r` + "`" + ` - Resize of the terminal, the content is the size in the format ` + "`" + `rows,cols,x,y` + "`" + `",
+                        "name": "events",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "event_stream",
+                            "statements"
+                        ],
+                        "type": "string",
+                        "example": "event_stream",
+                        "description": "Expand the given attributes\n* ` + "`" + `event_stream` + "`" + ` - the content of the session\n* ` + "`" + `statements` + "`" + ` - the statements executed by database connections",
+                        "name": "expand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "csv",
+                        "description": "The file extension to donwload the session as a file content.\n* ` + "`" + `csv` + "`" + ` - it will parse the content to format in csv format\n* ` + "`" + `json` + "`" + ` - it will parse the content as a json stream.\n* ` + "`" + `\u003cany-format\u003e` + "`" + ` - No special parsing is applied",
+                        "name": "extension",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "0",
+                            "1"
+                        ],
+                        "type": "string",
+                        "default": "0",
+                        "example": "1",
+                        "description": "Construct the file content adding a break line when parsing each event",
+                        "name": "new_line",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "The id of the resource",
+                        "name": "session_id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get Webhooks Dashboard",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/openapi.WebhooksDashboardResponse"
+                            "$ref": "#/definitions/openapi.Session"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/openapi.HTTPError"
-                        }
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{session_id}/download": {
+            "get": {
+                "description": "Download session by id",
+                "produces": [
+                    "application/octet-stream",
+                    "application/json"
+                ],
+                "tags": [
+                    "Sessions"
+                ],
+                "summary": "Download Session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The id of the resource",
+                        "name": "session_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        },
+                        "headers": {
+                            "Accept-Length": {
+                                "type": "integer",
+                                "description": "size in bytes of the content"
+                            },
+                            "Content-Disposition": {
+                                "type": "string",
+                                "description": "application/octet-stream"
+                            },
+                            "Content-Type": {
+                                "type": "string",
+                                "description": "application/octet-stream"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{session_id}/exec": {
+            "post": {
+                "description": "Run an execution in a reviewed session",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Core"
+                ],
+                "summary": "Reviewed Exec",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The id of the resource",
+                        "name": "session_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The execution has finished",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.ExecResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "The execution is still in progress",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.ExecResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{session_id}/kill": {
+            "post": {
+                "tags": [
+                    "Sessions"
+                ],
+                "summary": "Kill Session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The id of the resource",
+                        "name": "session_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{session_id}/metadata": {
+            "patch": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sessions"
+                ],
+                "summary": "Update Session Metadata",
+                "parameters": [
+                    {
+                        "description": "The request body resource",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/openapi.SessionUpdateMetadataRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{session_id}/playback": {
+            "get": {
+                "description": "Stream the events of a session with their relative time in the asciinema v2 format (asciicast).\nThe first line is the header with the initial size of the terminal, the next lines are the events in the format ` + "`" + `[\u003ctime\u003e, \u003ccode\u003e, \u003cdata\u003e]` + "`" + `\n* ` + "`" + `o` + "`" + ` - the output of the session\n* ` + "`" + `i` + "`" + ` - the input of the session\n* ` + "`" + `r` + "`" + ` - a resize of the client terminal in the format ` + "`" + `\u003ccols\u003ex\u003crows\u003e` + "`" + `",
+                "produces": [
+                    "application/x-asciicast",
+                    "application/json"
+                ],
+                "tags": [
+                    "Sessions"
+                ],
+                "summary": "Session Playback",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The id of the resource",
+                        "name": "session_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{session_id}/review": {
+            "put": {
+                "description": "Update the status of a review resource by the session id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Update Review Status By Sid",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Resource identifier of the session",
+                        "name": "session_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The request body resource",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/openapi.ReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.Review"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions/{session_id}/verify": {
+            "get": {
+                "description": "Verify the event stream of a session against the digest sealed by the gateway when the session ended.\nIt detects when the event stream was truncated or modified and when the seal doesn't match its signature or the anchored digest.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sessions"
+                ],
+                "summary": "Verify Session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The id of the resource",
+                        "name": "session_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.SessionVerification"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/signup": {
+            "post": {
+                "description": "Signup anonymous authenticated user. This endpoint is only used for multi tenant setups.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Signup",
+                "parameters": [
+                    {
+                        "description": "The request body resource",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/openapi.SignupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.SignupRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokens": {
+            "get": {
+                "description": "List the tokens of the authenticated user, admin users list the tokens of the organization",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Tokens"
+                ],
+                "summary": "List API Tokens",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/openapi.APIToken"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a token that authenticates on behalf of a user or service account.\nThe token is restricted to the routes and verbs of its scopes and it's returned only once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Tokens"
+                ],
+                "summary": "Create API Token",
+                "parameters": [
+                    {
+                        "description": "The request body resource",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/openapi.APITokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.APIToken"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokens/{id}": {
+            "delete": {
+                "description": "Revoke a token, only the owner of the token or admin users are allowed to revoke it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Tokens"
+                ],
+                "summary": "Revoke API Token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The unique identifier of the resource",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/userinfo": {
+            "get": {
+                "description": "Get own user's information",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Management"
+                ],
+                "summary": "Get UserInfo",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.UserInfo"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "description": "List all users",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Management"
+                ],
+                "summary": "List Users",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/openapi.User"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Inviting a user will pre configure user definitions like display name, profile picture, groups or his slack id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Management"
+                ],
+                "summary": "Invite User",
+                "parameters": [
+                    {
+                        "description": "The request body resource",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/openapi.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/groups": {
+            "get": {
+                "description": "List all groups from all users",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Management"
+                ],
+                "summary": "List User Groups",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/self/slack": {
+            "patch": {
+                "description": "Patch own user's slack id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Management"
+                ],
+                "summary": "Patch User Slack ID",
+                "parameters": [
+                    {
+                        "description": "The request body resource",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/openapi.UserPatchSlackID"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{emailOrID}": {
+            "get": {
+                "description": "Get user by email or subject id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Management"
+                ],
+                "summary": "Get User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The subject identifier or email of the user",
+                        "name": "emailOrID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.User"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "put": {
+                "description": "Updates an existing user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Management"
+                ],
+                "summary": "Update User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The subject identifier of the user",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The request body resource",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/openapi.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User Management"
+                ],
+                "summary": "Delete User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The subject identifier of the user",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks-dashboard": {
+            "get": {
+                "description": "Get webhooks dashboard url",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Features"
+                ],
+                "summary": "Get Webhooks Dashboard",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.WebhooksDashboardResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/openapi.HTTPError"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "openapi.APIToken": {
+            "type": "object",
+            "properties": {
+                "connections": {
+                    "description": "The connections the token is restricted to, it's empty when all connections are allowed",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "pgdemo"
+                    ]
+                },
+                "created_at": {
+                    "description": "The time the resource was created",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2024-07-25T15:56:35.317601Z"
+                },
+                "created_by": {
+                    "description": "The email of the user that created the token",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "john.wick@bad.org"
+                },
+                "email": {
+                    "description": "The email of the user or service account the token acts on behalf of",
+                    "type": "string",
+                    "example": "ci-bot@serviceaccount"
+                },
+                "expires_at": {
+                    "description": "The time the token expires, it's empty when the token never expires",
+                    "type": "string",
+                    "example": "2025-07-25T15:56:35.317601Z"
+                },
+                "id": {
+                    "description": "The resource identifier",
+                    "type": "string",
+                    "format": "uuid",
+                    "readOnly": true,
+                    "example": "15B5A2FD-0706-4A47-B1CF-B93CCFC5B3D7"
+                },
+                "last_used_at": {
+                    "description": "The last time the token was used to authenticate",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2024-07-25T15:56:35.317601Z"
+                },
+                "name": {
+                    "description": "The name of the token",
+                    "type": "string",
+                    "example": "ci-pipeline"
+                },
+                "revoked_at": {
+                    "description": "The time the token was revoked",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2024-07-25T15:56:35.317601Z"
+                },
+                "scopes": {
+                    "description": "The routes and verbs the token is allowed to access",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/openapi.APITokenScope"
+                    },
+                    "example": [
+                        "exec"
+                    ]
+                },
+                "status": {
+                    "description": "The status of the token",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/openapi.APITokenStatus"
+                        }
+                    ],
+                    "readOnly": true,
+                    "example": "active"
+                },
+                "subject": {
+                    "description": "The subject of the user or service account the token acts on behalf of",
+                    "type": "string",
+                    "example": "ci-bot@serviceaccount"
+                },
+                "token": {
+                    "description": "The value of the token, it's returned only when the token is created",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "hpat_0Q8yP2mvaXhKB1uTzJpXUl3H3Q4cE6nVqGdAf9Rk2sw"
+                },
+                "token_prefix": {
+                    "description": "The first characters of the token, used to identify it",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "hpat_0Q8yP2mv"
+                }
+            }
+        },
+        "openapi.APITokenRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "connections": {
+                    "description": "The connections the token is restricted to, an empty value allows all connections the user has access to",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "pgdemo"
+                    ]
+                },
+                "expires_at": {
+                    "description": "The time the token expires, the token never expires when it's empty",
+                    "type": "string",
+                    "example": "2025-07-25T15:56:35.317601Z"
+                },
+                "name": {
+                    "description": "The name of the token, it must be unique among the active tokens of the user",
+                    "type": "string",
+                    "example": "ci-pipeline"
+                },
+                "scopes": {
+                    "description": "The routes and verbs the token is allowed to access\n* sessions:read - list and read sessions\n* connections:read - list and read connections\n* exec - run one-off executions via the api or the exec command\n* connect - open interactive sessions and proxies to connections",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/openapi.APITokenScope"
+                    },
+                    "example": [
+                        "exec"
+                    ]
+                },
+                "subject": {
+                    "description": "The subject of the user or service account the token acts on behalf of, it defaults to the authenticated user.\nOnly admin users are allowed to create tokens for other users.",
+                    "type": "string",
+                    "example": "ci-bot@serviceaccount"
+                }
+            }
+        },
+        "openapi.APITokenScope": {
+            "type": "string",
+            "enum": [
+                "sessions:read",
+                "connections:read",
+                "exec",
+                "connect"
+            ],
+            "x-enum-varnames": [
+                "APITokenScopeSessionsRead",
+                "APITokenScopeConnectionsRead",
+                "APITokenScopeExec",
+                "APITokenScopeConnect"
+            ]
+        },
+        "openapi.APITokenStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "expired",
+                "revoked"
+            ],
+            "x-enum-varnames": [
+                "APITokenStatusActive",
+                "APITokenStatusExpired",
+                "APITokenStatusRevoked"
+            ]
+        },
+        "openapi.AWSAccount": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "description": "AccountID is the unique identifier for the AWS account",
+                    "type": "string",
+                    "example": "123456789012"
+                },
+                "email": {
+                    "description": "Email is the email address associated with the AWS account",
+                    "type": "string",
+                    "example": "aws-prod@example.com"
+                },
+                "joined_methods": {
+                    "description": "JoinedMethods indicates how the account joined the organization",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.AccountJoinedMethod"
+                        }
+                    ],
+                    "example": "INVITED"
+                },
+                "name": {
+                    "description": "Name is the friendly name of the AWS account",
+                    "type": "string",
+                    "example": "SandBox"
+                },
+                "status": {
+                    "description": "Status indicates whether the account is active, suspended, etc.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.AccountStatus"
+                        }
+                    ],
+                    "example": "ACTIVE"
+                }
+            }
+        },
+        "openapi.AWSDBInstance": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "description": "AccountID is the unique identifier for the AWS account that owns the database",
+                    "type": "string",
+                    "example": "123456789012"
+                },
+                "arn": {
+                    "description": "ARN is the Amazon Resource Name that uniquely identifies the database instance",
+                    "type": "string",
+                    "example": "arn:aws:rds:us-west-2:123456789012:db:my-postgres-db"
+                },
+                "availability_zone": {
+                    "description": "AvailabilityZone is the AWS availability zone where the database is deployed",
+                    "type": "string",
+                    "example": "us-west-2a"
+                },
+                "engine": {
+                    "description": "Engine is the database engine type (e.g., MySQL, PostgreSQL)",
+                    "type": "string",
+                    "example": "postgres"
+                },
+                "error": {
+                    "description": "Contains an error in case it was not able to list the db instances from the account id",
+                    "type": "string",
+                    "example": "IAM account does not have permission to list db instances in this account"
+                },
+                "name": {
+                    "description": "Name is the identifier for the database instance",
+                    "type": "string",
+                    "example": "my-postgres-db"
+                },
+                "status": {
+                    "description": "Status indicates the current state of the database instance",
+                    "type": "string",
+                    "example": "available"
+                },
+                "vpc_id": {
+                    "description": "VpcID is the ID of the Virtual Private Cloud where the database is deployed",
+                    "type": "string",
+                    "example": "vpc-0123456789abcdef0"
+                }
+            }
+        },
+        "openapi.AWSDBRoleJobSpec": {
+            "type": "object",
+            "properties": {
+                "account_arn": {
+                    "description": "AWS IAM ARN with permissions to execute this role creation job",
+                    "type": "string",
+                    "example": "arn:aws:iam:123456789012"
+                },
+                "db_arn": {
+                    "description": "ARN of the target RDS database instance where roles will be created",
+                    "type": "string",
+                    "example": "arn:aws:rds:us-west-2:123456789012:db:my-instance"
+                },
+                "db_engine": {
+                    "description": "Database engine type (e.g., \"postgres\", \"mysql\") of the RDS instance",
+                    "type": "string",
+                    "example": "postgres"
+                },
+                "db_name": {
+                    "description": "Logical database name within the RDS instance where roles will be applied",
+                    "type": "string",
+                    "example": "customers"
+                },
+                "db_tags": {
+                    "description": "Database Instance tags",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/openapi.DBTag"
+                    }
+                }
+            }
+        },
+        "openapi.AccessPolicy": {
+            "type": "object",
+            "properties": {
+                "client_origins": {
+                    "description": "Condition: the client origin of the request must be one of these values",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "client"
+                    ]
+                },
+                "connection_tags": {
+                    "description": "Condition: the connection must have all these tags",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "env": "staging"
+                    }
+                },
+                "connection_types": {
+                    "description": "Condition: the type or the subtype of the connection must be one of these values",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "postgres"
+                    ]
+                },
+                "created_at": {
+                    "description": "The time the resource was created",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2024-07-25T15:56:35.317601Z"
+                },
+                "description": {
+                    "description": "The description of the policy",
+                    "type": "string",
+                    "example": "Contractors may only exec on staging during business hours"
+                },
+                "effect": {
+                    "description": "How the policy affects the requests matching its conditions",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/openapi.AccessPolicyEffect"
+                        }
+                    ],
+                    "example": "allow"
+                },
+                "groups": {
+                    "description": "The policy applies to the users of any of these groups, it applies to all users when it's empty",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "contractors"
+                    ]
+                },
+                "id": {
+                    "description": "The resource identifier",
+                    "type": "string",
+                    "format": "uuid",
+                    "readOnly": true,
+                    "example": "15B5A2FD-0706-4A47-B1CF-B93CCFC5B3D7"
+                },
+                "name": {
+                    "description": "The name of the policy",
+                    "type": "string",
+                    "example": "contractors-staging"
+                },
+                "source_ips": {
+                    "description": "Condition: the source ip of the request must be one of these ip addresses or cidrs",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "10.0.0.0/8"
+                    ]
+                },
+                "time_window": {
+                    "description": "Condition: the time of the request must be within the window",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/openapi.AccessPolicyTimeWindow"
+                        }
+                    ]
+                },
+                "updated_at": {
+                    "description": "The time the resource was updated",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2024-07-25T15:56:35.317601Z"
+                },
+                "verbs": {
+                    "description": "Condition: the verb of the request must be one of these values",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "exec"
+                    ]
+                }
+            }
+        },
+        "openapi.AccessPolicyEffect": {
+            "type": "string",
+            "enum": [
+                "allow",
+                "deny"
+            ],
+            "x-enum-varnames": [
+                "AccessPolicyEffectAllow",
+                "AccessPolicyEffectDeny"
+            ]
+        },
+        "openapi.AccessPolicyEvaluation": {
+            "type": "object",
+            "properties": {
+                "access_control_allowed": {
+                    "description": "If the groups of the user are allowed to access the connection by the access control plugin",
+                    "type": "boolean",
+                    "example": true
+                },
+                "allowed": {
+                    "description": "If the user is allowed to access the connection",
+                    "type": "boolean",
+                    "example": false
+                },
+                "client_origin": {
+                    "description": "The client origin evaluated",
+                    "type": "string",
+                    "example": "client"
+                },
+                "connection": {
+                    "description": "The name of the connection evaluated",
+                    "type": "string",
+                    "example": "pgdemo"
+                },
+                "policies": {
+                    "description": "The outcome of each policy of the organization",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/openapi.AccessPolicyResult"
+                    }
+                },
+                "policies_allowed": {
+                    "description": "If the access policies allow the request",
+                    "type": "boolean",
+                    "example": false
+                },
+                "reason": {
+                    "description": "The reason of the decision",
+                    "type": "string",
+                    "example": "the request does not match any of the allow policies: contractors-staging"
+                },
+                "source_ip": {
+                    "description": "The source ip evaluated",
+                    "type": "string",
+                    "example": "10.0.0.1"
+                },
+                "time": {
+                    "description": "The time evaluated",
+                    "type": "string",
+                    "example": "2024-07-25T15:56:35.317601Z"
+                },
+                "user": {
+                    "description": "The email of the user evaluated",
+                    "type": "string",
+                    "example": "john.wick@bad.org"
+                },
+                "verb": {
+                    "description": "The verb evaluated, it's empty when reading the connection",
+                    "type": "string",
+                    "example": "exec"
+                }
+            }
+        },
+        "openapi.AccessPolicyRequest": {
+            "type": "object",
+            "required": [
+                "effect",
+                "name"
+            ],
+            "properties": {
+                "client_origins": {
+                    "description": "Condition: the client origin of the request must be one of these values.\nReading connections is allowed by allow policies and it's not denied by deny policies with this condition.",
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "client",
+                            "client-api",
+                            "client-api-runbooks",
+                            "client-proxymanager"
+                        ]
+                    },
+                    "example": [
+                        "client"
+                    ]
+                },
+                "connection_tags": {
+                    "description": "Condition: the connection must have all these tags",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "env": "staging"
                     }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "openapi.AWSAccount": {
-            "type": "object",
-            "properties": {
-                "account_id": {
-                    "description": "AccountID is the unique identifier for the AWS account",
-                    "type": "string",
-                    "example": "123456789012"
                 },
-                "email": {
-                    "description": "Email is the email address associated with the AWS account",
+                "connection_types": {
+                    "description": "Condition: the type or the subtype of the connection must be one of these values",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "postgres"
+                    ]
+                },
+                "description": {
+                    "description": "The description of the policy",
                     "type": "string",
-                    "example": "aws-prod@example.com"
+                    "example": "Contractors may only exec on staging during business hours"
                 },
-                "joined_methods": {
-                    "description": "JoinedMethods indicates how the account joined the organization",
+                "effect": {
+                    "description": "How the policy affects the requests matching its conditions\n* allow - the users of the policy are allowed to access only the requests matching the conditions of any allow policy\n* deny - the requests matching the conditions are denied, it takes precedence over allow policies",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/types.AccountJoinedMethod"
+                            "$ref": "#/definitions/openapi.AccessPolicyEffect"
                         }
                     ],
-                    "example": "INVITED"
+                    "example": "allow"
+                },
+                "groups": {
+                    "description": "The policy applies to the users of any of these groups, an empty value applies it to all users",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "contractors"
+                    ]
                 },
                 "name": {
-                    "description": "Name is the friendly name of the AWS account",
+                    "description": "The name of the policy, it must be unique in the organization",
                     "type": "string",
-                    "example": "SandBox"
+                    "example": "contractors-staging"
                 },
-                "status": {
-                    "description": "Status indicates whether the account is active, suspended, etc.",
+                "source_ips": {
+                    "description": "Condition: the source ip of the request must be one of these ip addresses or cidrs",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "10.0.0.0/8"
+                    ]
+                },
+                "time_window": {
+                    "description": "Condition: the time of the request must be within the window",
                     "allOf": [
                         {
-                            "$ref": "#/definitions/types.AccountStatus"
+                            "$ref": "#/definitions/openapi.AccessPolicyTimeWindow"
                         }
-                    ],
-                    "example": "ACTIVE"
+                    ]
+                },
+                "verbs": {
+                    "description": "Condition: the verb of the request must be one of these values.\nReading connections is allowed by allow policies and it's not denied by deny policies with this condition.",
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "exec",
+                            "connect"
+                        ]
+                    },
+                    "example": [
+                        "exec"
+                    ]
                 }
             }
         },
-        "openapi.AWSDBInstance": {
+        "openapi.AccessPolicyResult": {
             "type": "object",
             "properties": {
-                "account_id": {
-                    "description": "AccountID is the unique identifier for the AWS account that owns the database",
-                    "type": "string",
-                    "example": "123456789012"
-                },
-                "arn": {
-                    "description": "ARN is the Amazon Resource Name that uniquely identifies the database instance",
-                    "type": "string",
-                    "example": "arn:aws:rds:us-west-2:123456789012:db:my-postgres-db"
+                "applicable": {
+                    "description": "If the policy applies to the groups of the user",
+                    "type": "boolean",
+                    "example": true
                 },
-                "availability_zone": {
-                    "description": "AvailabilityZone is the AWS availability zone where the database is deployed",
-                    "type": "string",
-                    "example": "us-west-2a"
+                "effect": {
+                    "description": "The effect of the policy",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/openapi.AccessPolicyEffect"
+                        }
+                    ],
+                    "example": "allow"
                 },
-                "engine": {
-                    "description": "Engine is the database engine type (e.g., MySQL, PostgreSQL)",
+                "id": {
+                    "description": "The identifier of the policy",
                     "type": "string",
-                    "example": "postgres"
+                    "format": "uuid",
+                    "example": "15B5A2FD-0706-4A47-B1CF-B93CCFC5B3D7"
                 },
-                "error": {
-                    "description": "Contains an error in case it was not able to list the db instances from the account id",
-                    "type": "string",
-                    "example": "IAM account does not have permission to list db instances in this account"
+                "matched": {
+                    "description": "If all conditions of the policy matched the request",
+                    "type": "boolean",
+                    "example": false
                 },
                 "name": {
-                    "description": "Name is the identifier for the database instance",
-                    "type": "string",
-                    "example": "my-postgres-db"
-                },
-                "status": {
-                    "description": "Status indicates the current state of the database instance",
+                    "description": "The name of the policy",
                     "type": "string",
-                    "example": "available"
+                    "example": "contractors-staging"
                 },
-                "vpc_id": {
-                    "description": "VpcID is the ID of the Virtual Private Cloud where the database is deployed",
+                "reason": {
+                    "description": "Why the policy didn't match the request",
                     "type": "string",
-                    "example": "vpc-0123456789abcdef0"
+                    "example": "the time 20:00 is not between 09:00 and 18:00 (UTC)"
                 }
             }
         },
-        "openapi.AWSDBRoleJobSpec": {
+        "openapi.AccessPolicyTimeWindow": {
             "type": "object",
             "properties": {
-                "account_arn": {
-                    "description": "AWS IAM ARN with permissions to execute this role creation job",
-                    "type": "string",
-                    "example": "arn:aws:iam:123456789012"
+                "days": {
+                    "description": "The days of the week the window applies to, an empty value applies to all days",
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "sun",
+                            "mon",
+                            "tue",
+                            "wed",
+                            "thu",
+                            "fri",
+                            "sat"
+                        ]
+                    },
+                    "example": [
+                        "mon",
+                        "tue",
+                        "wed",
+                        "thu",
+                        "fri"
+                    ]
                 },
-                "db_arn": {
-                    "description": "ARN of the target RDS database instance where roles will be created",
+                "end_time": {
+                    "description": "The end of the window in the format HH:MM, it defaults to 24:00.\nWindows ending before they start cross midnight, e.g.: 22:00-06:00",
                     "type": "string",
-                    "example": "arn:aws:rds:us-west-2:123456789012:db:my-instance"
+                    "example": "18:00"
                 },
-                "db_engine": {
-                    "description": "Database engine type (e.g., \"postgres\", \"mysql\") of the RDS instance",
+                "start_time": {
+                    "description": "The start of the window in the format HH:MM, it defaults to 00:00",
                     "type": "string",
-                    "example": "postgres"
+                    "example": "09:00"
                 },
-                "db_name": {
-                    "description": "Logical database name within the RDS instance where roles will be applied",
+                "timezone": {
+                    "description": "The IANA timezone of the window, it defaults to UTC",
                     "type": "string",
-                    "example": "customers"
-                },
-                "db_tags": {
-                    "description": "Database Instance tags",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/openapi.DBTag"
-                    }
+                    "example": "America/Sao_Paulo"
                 }
             }
         },
@@ -4080,6 +5924,14 @@ const docTemplate = `{
                         "EMAIL_ADDRESS"
                     ]
                 },
+                "review_policy": {
+                    "description": "The rules to approve the reviews of this connection, the default policy\nrequires one approval of every reviewer group",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/openapi.ReviewPolicy"
+                        }
+                    ]
+                },
                 "reviewers": {
                     "description": "Reviewers is a list of groups that will review the connection before the user could execute it",
                     "type": "array",
@@ -4091,7 +5943,7 @@ const docTemplate = `{
                     ]
                 },
                 "secret": {
-                    "description": "Secrets are environment variables that are going to be exposed\nin the runtime of the connection:\n* { envvar:[env-key]: [base64-val] } - Expose the value as environment variable\n* { filesystem:[env-key]: [base64-val] } - Expose the value as a temporary file path creating the value in the filesystem\n\nThe value could also represent an integration with a external provider:\n* { envvar:[env-key]: _aws:[secret-name]:[secret-key] } - Obtain the value dynamically in the AWS secrets manager and expose as environment variable\n* { envvar:[env-key]: _envjson:[json-env-name]:[json-env-key] } - Obtain the value dynamically from a JSON env in the agent runtime. Example: MYENV={\"KEY\": \"val\"}",
+                    "description": "Secrets are environment variables that are going to be exposed\nin the runtime of the connection:\n* { envvar:[env-key]: [base64-val] } - Expose the value as environment variable\n* { filesystem:[env-key]: [base64-val] } - Expose the value as a temporary file path creating the value in the filesystem\n\nThe value could also represent an integration with a external provider:\n* { envvar:[env-key]: _aws:[secret-name]:[secret-key] } - Obtain the value dynamically in the AWS secrets manager and expose as environment variable\n* { envvar:[env-key]: _envjson:[json-env-name]:[json-env-key] } - Obtain the value dynamically from a JSON env in the agent runtime. Example: MYENV={\"KEY\": \"val\"}\n* { envvar:[env-key]: _file:[path]:[file-key] } - Obtain the value from a file (JSON object) or directory (one file per key) in the HOOP_SECRETS_DIR of the agent\n* { envvar:[env-key]: _exec:[secret-id]:[secret-key] } - Obtain the value from the JSON output of the HOOP_SECRETS_EXEC_COMMAND helper in the agent\n* { envvar:[env-key]: _gcpsm:[secret-name]:[secret-key] } - Obtain the value dynamically in the GCP Secret Manager and expose as environment variable\n* { envvar:[env-key]: _azurekv:[secret-name]:[secret-key] } - Obtain the value dynamically in the Azure Key Vault and expose as environment variable\n* { envvar:[env-key]: _vaultdb:[database-role]:[username|password] } - Issue short-lived credentials per session from the Vault database secrets engine, they are revoked when the session ends",
                     "type": "object",
                     "additionalProperties": {}
                 },
@@ -4105,7 +5957,7 @@ const docTemplate = `{
                     "readOnly": true
                 },
                 "subtype": {
-                    "description": "Sub Type is the underline implementation of the connection:\n* postgres - Implements Postgres protocol\n* mysql - Implements MySQL protocol\n* mongodb - Implements MongoDB Wire Protocol\n* mssql - Implements Microsoft SQL Server Protocol\n* tcp - Forwards a TCP connection",
+                    "description": "Sub Type is the underline implementation of the connection:\n* postgres - Implements Postgres protocol\n* mysql - Implements MySQL protocol\n* mongodb - Implements MongoDB Wire Protocol\n* redis - Implements Redis Serialization Protocol (RESP)\n* mssql - Implements Microsoft SQL Server Protocol\n* tcp - Forwards a TCP connection",
                     "type": "string",
                     "example": "postgres"
                 },
@@ -4635,6 +6487,89 @@ const docTemplate = `{
                 "FeatureStatusDisabled"
             ]
         },
+        "openapi.GuardRailMatchOffset": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "description": "The byte offset where the match ends (exclusive)",
+                    "type": "integer",
+                    "example": 30
+                },
+                "start": {
+                    "description": "The byte offset where the match starts (inclusive)",
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "openapi.GuardRailReplayResult": {
+            "type": "object",
+            "properties": {
+                "blocked": {
+                    "description": "The number of sessions that would have been blocked",
+                    "type": "integer",
+                    "example": 3
+                },
+                "blocked_sessions": {
+                    "description": "The sessions that would have been blocked",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/openapi.GuardRailReplaySession"
+                    }
+                },
+                "connection_name": {
+                    "description": "The connection that had its sessions replayed",
+                    "type": "string",
+                    "example": "pgdemo"
+                },
+                "masked": {
+                    "description": "The number of sessions that would have its output masked",
+                    "type": "integer",
+                    "example": 10
+                },
+                "reviewed": {
+                    "description": "The number of sessions that would have required a review",
+                    "type": "integer",
+                    "example": 1
+                },
+                "sessions_evaluated": {
+                    "description": "The number of sessions evaluated",
+                    "type": "integer",
+                    "example": 50
+                },
+                "warned": {
+                    "description": "The number of sessions that would have matched a rule with the warn action",
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "openapi.GuardRailReplaySession": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "The time the session was created",
+                    "type": "string",
+                    "example": "2024-07-25T15:56:35.317601Z"
+                },
+                "id": {
+                    "description": "The session identifier",
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "5701046A-7B7A-4A78-ABB0-A24C95E6FE54"
+                },
+                "reason": {
+                    "description": "The reason the session would have been blocked",
+                    "type": "string",
+                    "example": "validation error, match guard rails input rule, type=sql_deny_statements, statement=ddl"
+                },
+                "user_email": {
+                    "description": "The user that performed the session",
+                    "type": "string",
+                    "example": "john.doe@example.com"
+                }
+            }
+        },
         "openapi.GuardRailRuleRequest": {
             "type": "object",
             "required": [
@@ -4647,7 +6582,7 @@ const docTemplate = `{
                     "example": "description about this rule"
                 },
                 "input": {
-                    "description": "The input rule\n\n\t\t{\n\t\t\t\"name\": \"deny-select\",\n\t\t\t\"description\": \"\u003coptional-description\u003e\",\n\t\t\t\"input\": {\n\t\t\t\t\"rules\": [\n\t\t\t\t\t{\"type\": \"deny_words_list\", \"words\": [\"SELECT\"], \"pattern_regex\": \"\"}\n\t\t\t\t]\n\t\t\t},\n\t\t\t\"output\": {\n\t\t\t\t\"rules\": [\n\t\t\t\t\t{\"type\": \"pattern_match\", \"words\": [], \"pattern_regex\": \"[A-Z0-9]+\"}\n\t\t\t\t]\n\t\t\t}\n\t\t}",
+                    "description": "The input rule\n\n\t\t{\n\t\t\t\"name\": \"deny-select\",\n\t\t\t\"description\": \"\u003coptional-description\u003e\",\n\t\t\t\"input\": {\n\t\t\t\t\"rules\": [\n\t\t\t\t\t{\"type\": \"deny_words_list\", \"words\": [\"SELECT\"], \"pattern_regex\": \"\"},\n\t\t\t\t\t{\"type\": \"sql_deny_statements\", \"dialect\": \"postgres\", \"statements\": [\"ddl\", \"delete_without_where\"], \"action\": \"review\"}\n\t\t\t\t]\n\t\t\t},\n\t\t\t\"output\": {\n\t\t\t\t\"rules\": [\n\t\t\t\t\t{\"type\": \"pattern_match\", \"words\": [], \"pattern_regex\": \"[A-Z0-9]+\", \"action\": \"mask\"}\n\t\t\t\t]\n\t\t\t}\n\t\t}\n\n\t\tThe action of each rule defaults to block, the available values are:\n\t\t* block - reject the input or output\n\t\t* warn - allow it, recording the match in the session and sending a webhook event\n\t\t* review - route the input to a one time review (input rules only)\n\t\t* mask - replace the matched content of the output (output rules only)\n\n\t\tThe redis_deny_commands rule type blocks redis commands by name, e.g.:\n\t\t{\"type\": \"redis_deny_commands\", \"commands\": [\"FLUSHALL\", \"CONFIG SET\"]}\n\t\tThe scripting commands (EVAL, EVALSHA, FCALL and their read only variants) are always\n\t\tblocked by this rule type, the commands called by a script can't be validated.",
                     "type": "object",
                     "additionalProperties": {}
                 },
@@ -4663,47 +6598,151 @@ const docTemplate = `{
                 }
             }
         },
-        "openapi.GuardRailRuleResponse": {
+        "openapi.GuardRailRuleResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "The time the resource was created",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2024-07-25T15:56:35.317601Z"
+                },
+                "description": {
+                    "description": "The rule description",
+                    "type": "string",
+                    "example": "description about this rule"
+                },
+                "id": {
+                    "description": "The resource identifier",
+                    "type": "string",
+                    "format": "uuid",
+                    "readOnly": true,
+                    "example": "15B5A2FD-0706-4A47-B1CF-B93CCFC5B3D7"
+                },
+                "input": {
+                    "description": "The input rule\n\n\t\t{\n\t\t\t\"name\": \"deny-select\",\n\t\t\t\"description\": \"\u003coptional-description\u003e\",\n\t\t\t\"input\": {\n\t\t\t\t\"rules\": [\n\t\t\t\t\t{\"type\": \"deny_words_list\", \"words\": [\"SELECT\"], \"pattern_regex\": \"\", \"name\": \"\u003coptional-name\u003e\"}\n\t\t\t\t]\n\t\t\t},\n\t\t\t\"output\": {\n\t\t\t\t\"rules\": [\n\t\t\t\t\t{\"type\": \"pattern_match\", \"words\": [], \"pattern_regex\": \"[A-Z0-9]+\"}\n\t\t\t\t]\n\t\t\t}\n\t\t}",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "name": {
+                    "description": "Unique name for the rule",
+                    "type": "string",
+                    "example": "my-strict-rule"
+                },
+                "output": {
+                    "description": "The output rule\n\n\t\t{\n\t\t\t\"name\": \"deny-select\",\n\t\t\t\"description\": \"\u003coptional-description\u003e\",\n\t\t\t\"input\": {\n\t\t\t\t\"rules\": [\n\t\t\t\t\t{\"type\": \"deny_words_list\", \"words\": [\"SELECT\"], \"pattern_regex\": \"\", \"name\": \"\u003coptional-name\u003e\"}\n\t\t\t\t]\n\t\t\t},\n\t\t\t\"output\": {\n\t\t\t\t\"rules\": [\n\t\t\t\t\t{\"type\": \"pattern_match\", \"words\": [], \"pattern_regex\": \"[A-Z0-9]+\"}\n\t\t\t\t]\n\t\t\t}\n\t\t}",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "updated_at": {
+                    "description": "The time the resource was updated",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2024-07-25T15:56:35.317601Z"
+                }
+            }
+        },
+        "openapi.GuardRailTestMatch": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "The action of the rule",
+                    "type": "string",
+                    "enum": [
+                        "block",
+                        "warn",
+                        "review",
+                        "mask"
+                    ],
+                    "example": "block"
+                },
+                "direction": {
+                    "description": "The direction of the rule",
+                    "type": "string",
+                    "enum": [
+                        "input",
+                        "output"
+                    ],
+                    "example": "input"
+                },
+                "explanation": {
+                    "description": "A human readable explanation why the rule matched",
+                    "type": "string",
+                    "example": "the statement is classified as ddl"
+                },
+                "offsets": {
+                    "description": "The byte ranges of the sample that matched the rule",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/openapi.GuardRailMatchOffset"
+                    }
+                },
+                "rule_index": {
+                    "description": "The position of the rule in the list of rules of its direction",
+                    "type": "integer",
+                    "example": 0
+                },
+                "rule_type": {
+                    "description": "The type of the rule",
+                    "type": "string",
+                    "example": "sql_deny_statements"
+                }
+            }
+        },
+        "openapi.GuardRailTestRequest": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "description": "The time the resource was created",
-                    "type": "string",
-                    "readOnly": true,
-                    "example": "2024-07-25T15:56:35.317601Z"
-                },
-                "description": {
-                    "description": "The rule description",
-                    "type": "string",
-                    "example": "description about this rule"
-                },
-                "id": {
-                    "description": "The resource identifier",
+                "connection_name": {
+                    "description": "The connection to replay the rules against its latest sessions",
                     "type": "string",
-                    "format": "uuid",
-                    "readOnly": true,
-                    "example": "15B5A2FD-0706-4A47-B1CF-B93CCFC5B3D7"
+                    "example": "pgdemo"
                 },
                 "input": {
-                    "description": "The input rule\n\n\t\t{\n\t\t\t\"name\": \"deny-select\",\n\t\t\t\"description\": \"\u003coptional-description\u003e\",\n\t\t\t\"input\": {\n\t\t\t\t\"rules\": [\n\t\t\t\t\t{\"type\": \"deny_words_list\", \"words\": [\"SELECT\"], \"pattern_regex\": \"\", \"name\": \"\u003coptional-name\u003e\"}\n\t\t\t\t]\n\t\t\t},\n\t\t\t\"output\": {\n\t\t\t\t\"rules\": [\n\t\t\t\t\t{\"type\": \"pattern_match\", \"words\": [], \"pattern_regex\": \"[A-Z0-9]+\"}\n\t\t\t\t]\n\t\t\t}\n\t\t}",
+                    "description": "The input rule, it has the same format of the guard rail rule resource\n\n\t\t{\n\t\t\t\"rules\": [\n\t\t\t\t{\"type\": \"sql_deny_statements\", \"dialect\": \"postgres\", \"statements\": [\"ddl\"]}\n\t\t\t]\n\t\t}",
                     "type": "object",
                     "additionalProperties": {}
                 },
-                "name": {
-                    "description": "Unique name for the rule",
-                    "type": "string",
-                    "example": "my-strict-rule"
-                },
                 "output": {
-                    "description": "The output rule\n\n\t\t{\n\t\t\t\"name\": \"deny-select\",\n\t\t\t\"description\": \"\u003coptional-description\u003e\",\n\t\t\t\"input\": {\n\t\t\t\t\"rules\": [\n\t\t\t\t\t{\"type\": \"deny_words_list\", \"words\": [\"SELECT\"], \"pattern_regex\": \"\", \"name\": \"\u003coptional-name\u003e\"}\n\t\t\t\t]\n\t\t\t},\n\t\t\t\"output\": {\n\t\t\t\t\"rules\": [\n\t\t\t\t\t{\"type\": \"pattern_match\", \"words\": [], \"pattern_regex\": \"[A-Z0-9]+\"}\n\t\t\t\t]\n\t\t\t}\n\t\t}",
+                    "description": "The output rule, it has the same format of the guard rail rule resource\n\n\t\t{\n\t\t\t\"rules\": [\n\t\t\t\t{\"type\": \"pattern_match\", \"pattern_regex\": \"[0-9]{3}-[0-9]{2}-[0-9]{4}\", \"action\": \"mask\"}\n\t\t\t]\n\t\t}",
                     "type": "object",
                     "additionalProperties": {}
                 },
-                "updated_at": {
-                    "description": "The time the resource was updated",
+                "sample_input": {
+                    "description": "A sample input to validate against the input rule",
                     "type": "string",
-                    "readOnly": true,
-                    "example": "2024-07-25T15:56:35.317601Z"
+                    "example": "SELECT 1; DROP TABLE customers;"
+                },
+                "sample_output": {
+                    "description": "A sample output to validate against the output rule",
+                    "type": "string",
+                    "example": "ssn: 123-45-6789"
+                },
+                "sessions_limit": {
+                    "description": "The number of latest sessions of the connection to replay, it's required when connection_name is set",
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1,
+                    "example": 50
+                }
+            }
+        },
+        "openapi.GuardRailTestResponse": {
+            "type": "object",
+            "properties": {
+                "matches": {
+                    "description": "The rules that matched the sample input and output",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/openapi.GuardRailTestMatch"
+                    }
+                },
+                "replay": {
+                    "description": "The result of replaying the rules against the latest sessions of a connection",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/openapi.GuardRailReplayResult"
+                        }
+                    ]
                 }
             }
         },
@@ -5132,6 +7171,47 @@ const docTemplate = `{
                 }
             }
         },
+        "openapi.PermissionType": {
+            "type": "string",
+            "enum": [
+                "agents:read",
+                "agents:write",
+                "connections:read",
+                "connections:write",
+                "guardrails:read",
+                "guardrails:write",
+                "plugins:read",
+                "plugins:write",
+                "policies:read",
+                "policies:write",
+                "retention:read",
+                "retention:write",
+                "reviews:read",
+                "reviews:write",
+                "sessions:read",
+                "users:read",
+                "users:write"
+            ],
+            "x-enum-varnames": [
+                "PermissionAgentsRead",
+                "PermissionAgentsWrite",
+                "PermissionConnectionsRead",
+                "PermissionConnectionsWrite",
+                "PermissionGuardRailsRead",
+                "PermissionGuardRailsWrite",
+                "PermissionPluginsRead",
+                "PermissionPluginsWrite",
+                "PermissionPoliciesRead",
+                "PermissionPoliciesWrite",
+                "PermissionRetentionRead",
+                "PermissionRetentionWrite",
+                "PermissionReviewsRead",
+                "PermissionReviewsWrite",
+                "PermissionSessionsRead",
+                "PermissionUsersRead",
+                "PermissionUsersWrite"
+            ]
+        },
         "openapi.Plugin": {
             "type": "object",
             "required": [
@@ -5342,6 +7422,155 @@ const docTemplate = `{
                 }
             }
         },
+        "openapi.RetentionMode": {
+            "type": "string",
+            "enum": [
+                "event_stream",
+                "output",
+                "all"
+            ],
+            "x-enum-varnames": [
+                "RetentionModeEventStream",
+                "RetentionModeOutput",
+                "RetentionModeAll"
+            ]
+        },
+        "openapi.RetentionPolicy": {
+            "type": "object",
+            "properties": {
+                "connection_name": {
+                    "description": "The name of the connection the policy applies to, it's empty for organization policies",
+                    "type": "string",
+                    "example": "pgdemo"
+                },
+                "created_at": {
+                    "description": "The time the resource was created",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2024-07-25T15:56:35.317601Z"
+                },
+                "id": {
+                    "description": "The resource identifier",
+                    "type": "string",
+                    "format": "uuid",
+                    "readOnly": true,
+                    "example": "15B5A2FD-0706-4A47-B1CF-B93CCFC5B3D7"
+                },
+                "mode": {
+                    "description": "What is purged when a session is older than the retention days",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/openapi.RetentionMode"
+                        }
+                    ],
+                    "example": "output"
+                },
+                "retention_days": {
+                    "description": "The number of days after the end of a session to purge its content",
+                    "type": "integer",
+                    "example": 30
+                },
+                "updated_at": {
+                    "description": "The time the resource was updated",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2024-07-25T15:56:35.317601Z"
+                }
+            }
+        },
+        "openapi.RetentionPolicyRequest": {
+            "type": "object",
+            "required": [
+                "mode",
+                "retention_days"
+            ],
+            "properties": {
+                "connection_name": {
+                    "description": "The name of the connection the policy applies to, an empty value applies it to all\nconnections of the organization. A connection policy overrides the organization policy of the same mode.",
+                    "type": "string",
+                    "example": "pgdemo"
+                },
+                "mode": {
+                    "description": "What is purged when a session is older than the retention days\n* event_stream - the event stream of the session, the metadata and the input are kept\n* output - the output events of the session, the remaining events are kept\n* all - the session with all its content",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/openapi.RetentionMode"
+                        }
+                    ],
+                    "example": "output"
+                },
+                "retention_days": {
+                    "description": "The number of days after the end of a session to purge its content",
+                    "type": "integer",
+                    "example": 30
+                }
+            }
+        },
+        "openapi.RetentionPurge": {
+            "type": "object",
+            "properties": {
+                "connection": {
+                    "description": "The name of the connection of the session",
+                    "type": "string",
+                    "example": "pgdemo"
+                },
+                "id": {
+                    "description": "The resource identifier",
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "15B5A2FD-0706-4A47-B1CF-B93CCFC5B3D7"
+                },
+                "mode": {
+                    "description": "What was purged from the session",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/openapi.RetentionMode"
+                        }
+                    ],
+                    "example": "output"
+                },
+                "policy_id": {
+                    "description": "The policy that purged the session, it's empty when the policy was removed",
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "5701046A-7B7A-4A78-ABB0-A24C95E6FE54"
+                },
+                "purged_at": {
+                    "description": "The time the session was purged",
+                    "type": "string",
+                    "example": "2024-08-24T15:56:35.317601Z"
+                },
+                "session_created_at": {
+                    "description": "The time the session was created",
+                    "type": "string",
+                    "example": "2024-07-25T15:56:35.317601Z"
+                },
+                "session_id": {
+                    "description": "The purged session",
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "B19BBA55-8646-4D94-A40A-C3AFE2F4BAFD"
+                }
+            }
+        },
+        "openapi.RetentionPurgeList": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/openapi.RetentionPurge"
+                    }
+                },
+                "has_next_page": {
+                    "type": "boolean"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 100
+                }
+            }
+        },
         "openapi.Review": {
             "type": "object",
             "properties": {
@@ -5352,12 +7581,33 @@ const docTemplate = `{
                     "readOnly": true,
                     "example": 0
                 },
+                "access_reason": {
+                    "description": "The reason of a scheduled or break-glass review",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "database maintenance"
+                },
+                "access_start_at": {
+                    "description": "The start of the access window of a scheduled or break-glass review, the access ends at the revoke time",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2024-07-26T22:00:00Z"
+                },
                 "created_at": {
                     "description": "The time the resource was created",
                     "type": "string",
                     "readOnly": true,
                     "example": "2024-07-25T15:56:35.317601Z"
                 },
+                "extension": {
+                    "description": "The last request of additional access time of a jit review",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/openapi.ReviewExtension"
+                        }
+                    ],
+                    "readOnly": true
+                },
                 "id": {
                     "description": "Reousrce identifier",
                     "type": "string",
@@ -5382,6 +7632,12 @@ const docTemplate = `{
                         "-x"
                     ]
                 },
+                "jira_issue_key": {
+                    "description": "The Jira issue linked to a scheduled review",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "OPS-123"
+                },
                 "org": {
                     "description": "Organization identifier",
                     "type": "string",
@@ -5389,6 +7645,15 @@ const docTemplate = `{
                     "readOnly": true,
                     "example": "A72CF2A0-12D0-4E0D-A732-E34FFA3D9417"
                 },
+                "policy": {
+                    "description": "The policy evaluated to approve this review, it's the policy of the connection when the review was created",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/openapi.ReviewPolicy"
+                        }
+                    ],
+                    "readOnly": true
+                },
                 "review_connection": {
                     "description": "The review connection information",
                     "allOf": [
@@ -5429,48 +7694,132 @@ const docTemplate = `{
                     "example": "35DB0A2F-E5CE-4AD8-A308-55C3108956E5"
                 },
                 "status": {
-                    "description": "The status of the review\n* PENDING - The resource is waiting to be reviewed\n* APPROVED - The resource is fully approved\n* REJECTED - The resource is fully rejected\n* REVOKED - The resource was revoked after being approved\n* PROCESSING - The review is being executed\n* EXECUTED - The review was executed\n* UNKNOWN - Unable to know the status of the review",
+                    "description": "The status of the review\n* PENDING - The resource is waiting to be reviewed\n* APPROVED - The resource is fully approved\n* REJECTED - The resource is fully rejected\n* REVOKED - The resource was revoked after being approved\n* PROCESSING - The review is being executed\n* EXECUTED - The review was executed\n* UNKNOWN - Unable to know the status of the review\n* EXPIRED - The resource was not reviewed in time",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/openapi.ReviewStatusType"
+                        }
+                    ]
+                },
+                "type": {
+                    "description": "The type of this review\n* onetime - Represents a one time execution\n* jit - Represents a time based review\n* scheduled - Represents a time based review for a future time window\n* break_glass - Represents an emergency access granted before the review, the reviewers acknowledge it afterwards",
+                    "enum": [
+                        "onetime",
+                        "jit",
+                        "scheduled",
+                        "break_glass"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/openapi.ReviewType"
+                        }
+                    ],
+                    "readOnly": true
+                }
+            }
+        },
+        "openapi.ReviewApproval": {
+            "type": "object",
+            "properties": {
+                "review_date": {
+                    "description": "The date which the approval was performed",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2024-07-25T19:36:41Z"
+                },
+                "reviewed_by": {
+                    "description": "The member that approved the review",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/openapi.ReviewOwner"
+                        }
+                    ],
+                    "readOnly": true
+                }
+            }
+        },
+        "openapi.ReviewConnection": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "The resource identifier",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "20A5AABE-C35D-4F04-A5A7-C856EE6C7703"
+                },
+                "name": {
+                    "description": "The name of the connection",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "pgdemo"
+                }
+            }
+        },
+        "openapi.ReviewExtension": {
+            "type": "object",
+            "properties": {
+                "access_duration_sec": {
+                    "description": "The additional time in seconds requested by the owner of the review",
+                    "type": "integer",
+                    "readOnly": true,
+                    "example": 1800
+                },
+                "created_at": {
+                    "description": "The time the extension was requested",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2024-07-25T19:36:41Z"
+                },
+                "review_groups_data": {
+                    "description": "Contains the groups that requires to approve this extension, they are the same groups of the review",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/openapi.ReviewGroup"
+                    },
+                    "readOnly": true
+                },
+                "status": {
+                    "description": "The status of the extension\n* PENDING - The extension is waiting to be reviewed\n* APPROVED - The extension is approved, the revoke time of the review was pushed out\n* REJECTED - The extension is rejected",
                     "allOf": [
                         {
                             "$ref": "#/definitions/openapi.ReviewStatusType"
                         }
-                    ]
-                },
-                "type": {
-                    "description": "The type of this review\n* onetime - Represents a one time execution\n* jit - Represents a time based review",
-                    "enum": [
-                        "onetime",
-                        "jit"
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/openapi.ReviewType"
-                        }
                     ],
-                    "readOnly": true
+                    "readOnly": true,
+                    "example": "PENDING"
                 }
             }
         },
-        "openapi.ReviewConnection": {
+        "openapi.ReviewExtensionRequest": {
             "type": "object",
+            "required": [
+                "access_duration_sec"
+            ],
             "properties": {
-                "id": {
-                    "description": "The resource identifier",
-                    "type": "string",
-                    "readOnly": true,
-                    "example": "20A5AABE-C35D-4F04-A5A7-C856EE6C7703"
-                },
-                "name": {
-                    "description": "The name of the connection",
-                    "type": "string",
-                    "readOnly": true,
-                    "example": "pgdemo"
+                "access_duration_sec": {
+                    "description": "The additional time in seconds to request for the active jit review of the connection.\nIt must be between 60 seconds and 48 hours",
+                    "type": "integer",
+                    "example": 1800
                 }
             }
         },
         "openapi.ReviewGroup": {
             "type": "object",
             "properties": {
+                "approvals": {
+                    "description": "The approvals of the members of this group",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/openapi.ReviewApproval"
+                    },
+                    "readOnly": true
+                },
+                "escalation": {
+                    "description": "If this group was added by the escalation of the review",
+                    "type": "boolean",
+                    "readOnly": true,
+                    "example": false
+                },
                 "group": {
                     "description": "The group to approve this review",
                     "type": "string",
@@ -5540,6 +7889,71 @@ const docTemplate = `{
                 }
             }
         },
+        "openapi.ReviewPolicy": {
+            "type": "object",
+            "properties": {
+                "break_glass": {
+                    "description": "Allows users to open a session without waiting for the review in emergencies by stating a reason.\nThe reviewer groups are notified right away and must acknowledge the access afterwards",
+                    "type": "boolean",
+                    "default": false,
+                    "example": true
+                },
+                "break_glass_duration_sec": {
+                    "description": "The access time in seconds of a break-glass session, it must not be greater than 48 hours",
+                    "type": "integer",
+                    "default": 3600,
+                    "example": 1800
+                },
+                "break_glass_max_per_day": {
+                    "description": "The maximum of break-glass sessions of a user in the connection in a period of 24 hours",
+                    "type": "integer",
+                    "default": 1,
+                    "example": 2
+                },
+                "escalation_group": {
+                    "description": "The fallback group notified through Slack and webhooks when the review is escalated.\nThe approval of this group approves the review on behalf of the pending groups",
+                    "type": "string",
+                    "example": "managers"
+                },
+                "escalation_sec": {
+                    "description": "The time in seconds to notify the escalation group when nobody acts on a pending review.\nIt must be lower than the expiration time",
+                    "type": "integer",
+                    "example": 900
+                },
+                "expiration_sec": {
+                    "description": "The time in seconds to expire a pending review, the client waiting for the review is released with an error.\nA zero value means the review never expires",
+                    "type": "integer",
+                    "example": 3600
+                },
+                "min_approvals_per_group": {
+                    "description": "The minimum of distinct members of a group that must approve to consider the group approved",
+                    "type": "integer",
+                    "default": 1,
+                    "example": 2
+                },
+                "min_approvers": {
+                    "description": "The minimum of distinct approvers considering all groups",
+                    "type": "integer",
+                    "default": 1,
+                    "example": 2
+                },
+                "mode": {
+                    "description": "The groups that must approve the review\n* all - Every group must approve the review\n* any - At least one group must approve the review",
+                    "type": "string",
+                    "default": "all",
+                    "enum": [
+                        "all",
+                        "any"
+                    ],
+                    "example": "all"
+                },
+                "required_group": {
+                    "description": "A group that must always approve the review, it must be one of the reviewers of the connection",
+                    "type": "string",
+                    "example": "sre"
+                }
+            }
+        },
         "openapi.ReviewRequest": {
             "type": "object",
             "required": [
@@ -5579,7 +7993,8 @@ const docTemplate = `{
                 "REVOKED",
                 "PROCESSING",
                 "EXECUTED",
-                "UNKNOWN"
+                "UNKNOWN",
+                "EXPIRED"
             ],
             "x-enum-varnames": [
                 "ReviewStatusPending",
@@ -5588,20 +8003,120 @@ const docTemplate = `{
                 "ReviewStatusRevoked",
                 "ReviewStatusProcessing",
                 "ReviewStatusExecuted",
-                "ReviewStatusUnknown"
+                "ReviewStatusUnknown",
+                "ReviewStatusExpired"
             ]
         },
         "openapi.ReviewType": {
             "type": "string",
             "enum": [
                 "jit",
-                "onetime"
+                "onetime",
+                "scheduled",
+                "break_glass"
             ],
             "x-enum-varnames": [
                 "ReviewTypeJit",
-                "ReviewTypeOneTime"
+                "ReviewTypeOneTime",
+                "ReviewTypeScheduled",
+                "ReviewTypeBreakGlass"
             ]
         },
+        "openapi.Role": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "The time the resource was created",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2024-07-25T15:56:35.317601Z"
+                },
+                "description": {
+                    "description": "The description of the role",
+                    "type": "string",
+                    "example": "Manage connections without managing users"
+                },
+                "groups": {
+                    "description": "The groups of users granted with the role",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "dba-team"
+                    ]
+                },
+                "id": {
+                    "description": "The resource identifier",
+                    "type": "string",
+                    "format": "uuid",
+                    "readOnly": true,
+                    "example": "15B5A2FD-0706-4A47-B1CF-B93CCFC5B3D7"
+                },
+                "name": {
+                    "description": "The name of the role",
+                    "type": "string",
+                    "example": "connection-manager"
+                },
+                "permissions": {
+                    "description": "The permissions granted by the role",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/openapi.PermissionType"
+                    },
+                    "example": [
+                        "connections:read",
+                        "connections:write"
+                    ]
+                },
+                "updated_at": {
+                    "description": "The time the resource was updated",
+                    "type": "string",
+                    "readOnly": true,
+                    "example": "2024-07-25T15:56:35.317601Z"
+                }
+            }
+        },
+        "openapi.RoleRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "permissions"
+            ],
+            "properties": {
+                "description": {
+                    "description": "The description of the role",
+                    "type": "string",
+                    "example": "Manage connections without managing users"
+                },
+                "groups": {
+                    "description": "The groups of users granted with the role, usually the groups synchronized from the identity provider",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "dba-team"
+                    ]
+                },
+                "name": {
+                    "description": "The name of the role, it must be unique in the organization",
+                    "type": "string",
+                    "example": "connection-manager"
+                },
+                "permissions": {
+                    "description": "The permissions granted by the role, each permission grants access to the routes of a resource\n* <resource>:read - list and read the resources\n* <resource>:write - create, update and remove the resources\n* sessions:read - list and read the sessions of all users",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/openapi.PermissionType"
+                    },
+                    "example": [
+                        "connections:read",
+                        "connections:write"
+                    ]
+                }
+            }
+        },
         "openapi.Runbook": {
             "type": "object",
             "properties": {
@@ -5713,6 +8228,42 @@ const docTemplate = `{
                 }
             }
         },
+        "openapi.ScheduledReviewRequest": {
+            "type": "object",
+            "required": [
+                "access_duration_sec",
+                "connection",
+                "reason",
+                "start_at"
+            ],
+            "properties": {
+                "access_duration_sec": {
+                    "description": "The duration in seconds of the access window, it must be between 60 seconds and 48 hours",
+                    "type": "integer",
+                    "example": 7200
+                },
+                "connection": {
+                    "description": "The name or the id of the connection to access",
+                    "type": "string",
+                    "example": "pgdemo"
+                },
+                "jira_issue_key": {
+                    "description": "The Jira issue of the change, e.g.: the approved change request",
+                    "type": "string",
+                    "example": "OPS-123"
+                },
+                "reason": {
+                    "description": "The reason to access the connection",
+                    "type": "string",
+                    "example": "database maintenance"
+                },
+                "start_at": {
+                    "description": "The start of the access window, it must be in the future",
+                    "type": "string",
+                    "example": "2024-07-26T22:00:00Z"
+                }
+            }
+        },
         "openapi.SecretsManagerProviderType": {
             "type": "string",
             "enum": [
@@ -5948,6 +8499,11 @@ const docTemplate = `{
         "openapi.Session": {
             "type": "object",
             "properties": {
+                "break_glass": {
+                    "description": "Indicates the session was opened with break-glass access, without waiting for the review",
+                    "type": "boolean",
+                    "readOnly": true
+                },
                 "connection": {
                     "description": "The connection name of this resource",
                     "type": "string",
@@ -5969,7 +8525,7 @@ const docTemplate = `{
                     "example": 569
                 },
                 "event_stream": {
-                    "description": "The stream containing the output of the execution in the following format\n\n` + "`" + `[[0.268589438, \"i\", \"ZW52\"], ...]` + "`" + `\n\n* ` + "`" + `\u003cevent-time\u003e` + "`" + ` - relative time in miliseconds to start_date\n* ` + "`" + `\u003cevent-type\u003e` + "`" + ` - the event type as string (i: input, o: output e: output-error)\n* ` + "`" + `\u003cbase64-content\u003e` + "`" + ` - the content of the session encoded as base64 string",
+                    "description": "The stream containing the output of the execution in the following format\n\n` + "`" + `[[0.268589438, \"i\", \"ZW52\"], ...]` + "`" + `\n\n* ` + "`" + `\u003cevent-time\u003e` + "`" + ` - relative time in miliseconds to start_date\n* ` + "`" + `\u003cevent-type\u003e` + "`" + ` - the event type as string (i: input, o: output e: output-error, r: terminal resize)\n* ` + "`" + `\u003cbase64-content\u003e` + "`" + ` - the content of the session encoded as base64 string,\nthe content of resize events is the size of the terminal in the format ` + "`" + `rows,cols,x,y` + "`" + `",
                     "type": "array",
                     "items": {
                         "type": "integer"
@@ -5979,6 +8535,13 @@ const docTemplate = `{
                     "description": "The Linux exit code if it's available",
                     "type": "integer"
                 },
+                "guardrails_info": {
+                    "description": "The guard rail rules that matched this session without blocking it",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/openapi.SessionGuardRailsInfo"
+                    }
+                },
                 "id": {
                     "description": "The resource unique identifier",
                     "type": "string",
@@ -6037,6 +8600,13 @@ const docTemplate = `{
                     "type": "string",
                     "example": "2024-07-25T15:56:35.317601Z"
                 },
+                "statements": {
+                    "description": "The statements executed by the session, they are available for postgres and mysql\nconnections. This attribute is only returned when it's expanded.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/openapi.SessionStatement"
+                    }
+                },
                 "status": {
                     "description": "Status of the resource\n* ready - the resource is ready to be executed, after being approved by a user\n* open - the session started and it's running\n* done - the session has finished",
                     "allOf": [
@@ -6074,6 +8644,45 @@ const docTemplate = `{
                 }
             }
         },
+        "openapi.SessionGuardRailsInfo": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "The action applied when the rule matched\n* warn - the session proceeded and the match was recorded\n* review - the input was sent to a review\n* mask - the matched content was replaced in the output",
+                    "type": "string",
+                    "enum": [
+                        "warn",
+                        "review",
+                        "mask"
+                    ],
+                    "example": "warn"
+                },
+                "created_at": {
+                    "description": "The time the rule matched",
+                    "type": "string",
+                    "example": "2024-07-25T15:56:35.317601Z"
+                },
+                "direction": {
+                    "description": "The direction of the rule",
+                    "type": "string",
+                    "enum": [
+                        "input",
+                        "output"
+                    ],
+                    "example": "input"
+                },
+                "message": {
+                    "description": "The description of the match",
+                    "type": "string",
+                    "example": "validation error, match guard rails input rule, type=deny_words_list, words=[DELETE]"
+                },
+                "rule_type": {
+                    "description": "The type of the rule",
+                    "type": "string",
+                    "example": "deny_words_list"
+                }
+            }
+        },
         "openapi.SessionLabelsType": {
             "type": "object",
             "additionalProperties": {
@@ -6150,6 +8759,51 @@ const docTemplate = `{
                 "type": "string"
             }
         },
+        "openapi.SessionStatement": {
+            "type": "object",
+            "properties": {
+                "connection_id": {
+                    "description": "The client connection of the statement",
+                    "type": "string",
+                    "example": "1"
+                },
+                "duration_ms": {
+                    "description": "The duration of the statement in milliseconds",
+                    "type": "integer",
+                    "example": 35
+                },
+                "end_date": {
+                    "description": "When the statement ended",
+                    "type": "string",
+                    "example": "2024-07-25T15:56:35.352601Z"
+                },
+                "error_code": {
+                    "description": "The error code returned by the database server",
+                    "type": "string",
+                    "example": "42P01"
+                },
+                "error_message": {
+                    "description": "The error message returned by the database server",
+                    "type": "string",
+                    "example": "relation \"customers\" does not exist"
+                },
+                "rows": {
+                    "description": "The rows returned or affected by the statement. A null value indicates it's unknown",
+                    "type": "integer",
+                    "example": 10
+                },
+                "start_date": {
+                    "description": "When the statement started",
+                    "type": "string",
+                    "example": "2024-07-25T15:56:35.317601Z"
+                },
+                "statement": {
+                    "description": "The statement sent by the client",
+                    "type": "string",
+                    "example": "SELECT * FROM customers"
+                }
+            }
+        },
         "openapi.SessionStatusType": {
             "type": "string",
             "enum": [
@@ -6178,6 +8832,86 @@ const docTemplate = `{
                 }
             }
         },
+        "openapi.SessionVerification": {
+            "type": "object",
+            "properties": {
+                "anchored_at": {
+                    "description": "The time the digest was anchored in the external anchor file",
+                    "type": "string",
+                    "example": "2024-07-25T16:00:00.000000Z"
+                },
+                "digest": {
+                    "description": "The digest computed from the stored event stream",
+                    "type": "string",
+                    "example": "3f1c2b8a5d5e1f0c9a7b6d4e2f1a0b9c8d7e6f5a4b3c2d1e0f9a8b7c6d5e4f3a"
+                },
+                "events": {
+                    "description": "The number of events found in the stored event stream",
+                    "type": "integer",
+                    "example": 42
+                },
+                "key_id": {
+                    "description": "The identifier of the gateway key that signed the digest, it's empty when the digest is not signed",
+                    "type": "string",
+                    "example": "a1b2c3d4e5f60718"
+                },
+                "reason": {
+                    "description": "The description of the outcome",
+                    "type": "string",
+                    "example": "the event stream matches its signed digest"
+                },
+                "sealed_at": {
+                    "description": "The time the session was sealed",
+                    "type": "string",
+                    "example": "2024-07-25T15:56:35.317601Z"
+                },
+                "sealed_digest": {
+                    "description": "The digest when the session was sealed",
+                    "type": "string",
+                    "example": "3f1c2b8a5d5e1f0c9a7b6d4e2f1a0b9c8d7e6f5a4b3c2d1e0f9a8b7c6d5e4f3a"
+                },
+                "sealed_events": {
+                    "description": "The number of events when the session was sealed",
+                    "type": "integer",
+                    "example": 42
+                },
+                "session_id": {
+                    "description": "The id of the session",
+                    "type": "string",
+                    "format": "uuid",
+                    "example": "B19BBA55-8646-4D94-A40A-C3AFE2F4BAFD"
+                },
+                "signature_verified": {
+                    "description": "Indicates the signature was verified with the key of the gateway",
+                    "type": "boolean",
+                    "example": true
+                },
+                "status": {
+                    "description": "The outcome of the verification\n* valid - the event stream matches its sealed digest\n* tampered - the event stream was truncated or modified, or its seal is not valid\n* unsealed - the session was not sealed by the gateway\n* purged - the event stream was changed by a retention policy after the session was sealed",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/openapi.SessionVerificationStatus"
+                        }
+                    ],
+                    "example": "valid"
+                }
+            }
+        },
+        "openapi.SessionVerificationStatus": {
+            "type": "string",
+            "enum": [
+                "valid",
+                "tampered",
+                "unsealed",
+                "purged"
+            ],
+            "x-enum-varnames": [
+                "SessionVerificationStatusValid",
+                "SessionVerificationStatusTampered",
+                "SessionVerificationStatusUnsealed",
+                "SessionVerificationStatusPurged"
+            ]
+        },
         "openapi.SignupRequest": {
             "type": "object",
             "required": [
@@ -6369,6 +9103,17 @@ const docTemplate = `{
                     "type": "string",
                     "example": "password"
                 },
+                "permissions": {
+                    "description": "The permissions granted to the user by the custom roles of its groups",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/openapi.PermissionType"
+                    },
+                    "example": [
+                        "connections:read",
+                        "connections:write"
+                    ]
+                },
                 "picture": {
                     "description": "The profile picture url to display",
                     "type": "string",
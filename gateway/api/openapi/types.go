@@ -107,6 +107,8 @@ type UserInfo struct {
 	// * on - Disable the users management view on Webapp
 	WebAppUsersManagement  string `json:"webapp_users_management" enums:"on,off" default:"on"`
 	IntercomUserHmacDigest string `json:"intercom_hmac_digest"`
+	// The permissions granted to the user by the custom roles of its groups
+	Permissions []PermissionType `json:"permissions" example:"connections:read,connections:write"`
 }
 
 type ServiceAccountStatusType string
@@ -196,6 +198,7 @@ type Connection struct {
 	// * postgres - Implements Postgres protocol
 	// * mysql - Implements MySQL protocol
 	// * mongodb - Implements MongoDB Wire Protocol
+	// * redis - Implements Redis Serialization Protocol (RESP)
 	// * mssql - Implements Microsoft SQL Server Protocol
 	// * tcp - Forwards a TCP connection
 	SubType string `json:"subtype" example:"postgres"`
@@ -207,6 +210,11 @@ type Connection struct {
 	// The value could also represent an integration with a external provider:
 	// * { envvar:[env-key]: _aws:[secret-name]:[secret-key] } - Obtain the value dynamically in the AWS secrets manager and expose as environment variable
 	// * { envvar:[env-key]: _envjson:[json-env-name]:[json-env-key] } - Obtain the value dynamically from a JSON env in the agent runtime. Example: MYENV={"KEY": "val"}
+	// * { envvar:[env-key]: _file:[path]:[file-key] } - Obtain the value from a file (JSON object) or directory (one file per key) in the HOOP_SECRETS_DIR of the agent
+	// * { envvar:[env-key]: _exec:[secret-id]:[secret-key] } - Obtain the value from the JSON output of the HOOP_SECRETS_EXEC_COMMAND helper in the agent
+	// * { envvar:[env-key]: _gcpsm:[secret-name]:[secret-key] } - Obtain the value dynamically in the GCP Secret Manager and expose as environment variable
+	// * { envvar:[env-key]: _azurekv:[secret-name]:[secret-key] } - Obtain the value dynamically in the Azure Key Vault and expose as environment variable
+	// * { envvar:[env-key]: _vaultdb:[database-role]:[username|password] } - Issue short-lived credentials per session from the Vault database secrets engine, they are revoked when the session ends
 	Secrets map[string]any `json:"secret"`
 	// Default databases returns the configured value of the attribute secrets->'DB'
 	DefaultDatabase string `json:"default_database"`
@@ -252,6 +260,9 @@ type Connection struct {
 	GuardRailRules []string `json:"guardrail_rules" example:"5701046A-7B7A-4A78-ABB0-A24C95E6FE54,B19BBA55-8646-4D94-A40A-C3AFE2F4BAFD"`
 	// The jira issue templates ids associated to the connection
 	JiraIssueTemplateID string `json:"jira_issue_template_id" example:"B19BBA55-8646-4D94-A40A-C3AFE2F4BAFD"`
+	// The rules to approve the reviews of this connection, the default policy
+	// requires one approval of every reviewer group
+	ReviewPolicy *ReviewPolicy `json:"review_policy"`
 }
 
 type ConnectionTagCreateRequest struct {
@@ -402,6 +413,7 @@ type SessionGetByIDParams struct {
 	// * `i` - Input (stdin)
 	// * `o` - Output (stdout)
 	// * `e` - Error (stderr)
+	// * `r` - Resize of the terminal, the content is the size in the format `rows,cols,x,y`
 	Events []string `json:"events" example:"i,o,e"`
 	// Construct the file content adding a break line when parsing each event
 	NewLine string `json:"new_line" enums:"0,1" example:"1" default:"0"`
@@ -410,7 +422,9 @@ type SessionGetByIDParams struct {
 	// This option will parse the session output (o) and error (e) events as an utf-8 content in the session payload
 	EventStream string `json:"event_stream" enums:"utf8,base64" default:""`
 	// Expand the given attributes
-	Expand string `json:"expand" enums:"event_stream" example:"event_stream" default:""`
+	// * `event_stream` - the content of the session
+	// * `statements` - the statements executed by database connections
+	Expand string `json:"expand" enums:"event_stream,statements" example:"event_stream" default:""`
 }
 
 type SessionOption struct {
@@ -485,14 +499,22 @@ type Session struct {
 	Status SessionStatusType `json:"status"`
 	// The Linux exit code if it's available
 	ExitCode *int `json:"exit_code"`
+	// The guard rail rules that matched this session without blocking it
+	GuardRailsInfo []SessionGuardRailsInfo `json:"guardrails_info"`
+	// Indicates the session was opened with break-glass access, without waiting for the review
+	BreakGlass bool `json:"break_glass" readonly:"true"`
 	// The stream containing the output of the execution in the following format
 	//
 	// `[[0.268589438, "i", "ZW52"], ...]`
 	//
 	// * `<event-time>` - relative time in miliseconds to start_date
-	// * `<event-type>` - the event type as string (i: input, o: output e: output-error)
-	// * `<base64-content>` - the content of the session encoded as base64 string
+	// * `<event-type>` - the event type as string (i: input, o: output e: output-error, r: terminal resize)
+	// * `<base64-content>` - the content of the session encoded as base64 string,
+	// the content of resize events is the size of the terminal in the format `rows,cols,x,y`
 	EventStream json.RawMessage `json:"event_stream,omitempty" swagger:"type:string"`
+	// The statements executed by the session, they are available for postgres and mysql
+	// connections. This attribute is only returned when it's expanded.
+	Statements []SessionStatement `json:"statements,omitempty"`
 	// The stored resource size in bytes
 	EventSize int64 `json:"event_size" example:"569"`
 	// When the execution started
@@ -501,6 +523,79 @@ type Session struct {
 	EndSession *time.Time `json:"end_date" example:"2024-07-25T15:56:35.361101Z"`
 }
 
+type SessionGuardRailsInfo struct {
+	// The direction of the rule
+	Direction string `json:"direction" enums:"input,output" example:"input"`
+	// The type of the rule
+	RuleType string `json:"rule_type" example:"deny_words_list"`
+	// The action applied when the rule matched
+	// * warn - the session proceeded and the match was recorded
+	// * review - the input was sent to a review
+	// * mask - the matched content was replaced in the output
+	Action string `json:"action" enums:"warn,review,mask" example:"warn"`
+	// The description of the match
+	Message string `json:"message" example:"validation error, match guard rails input rule, type=deny_words_list, words=[DELETE]"`
+	// The time the rule matched
+	CreatedAt time.Time `json:"created_at" example:"2024-07-25T15:56:35.317601Z"`
+}
+
+type SessionStatement struct {
+	// The client connection of the statement
+	ConnectionID string `json:"connection_id" example:"1"`
+	// The statement sent by the client
+	Statement string `json:"statement" example:"SELECT * FROM customers"`
+	// The rows returned or affected by the statement. A null value indicates it's unknown
+	Rows *int64 `json:"rows" example:"10"`
+	// The error code returned by the database server
+	ErrorCode *string `json:"error_code" example:"42P01"`
+	// The error message returned by the database server
+	ErrorMessage *string `json:"error_message" example:"relation \"customers\" does not exist"`
+	// The duration of the statement in milliseconds
+	DurationMs int64 `json:"duration_ms" example:"35"`
+	// When the statement started
+	StartDate time.Time `json:"start_date" example:"2024-07-25T15:56:35.317601Z"`
+	// When the statement ended
+	EndDate time.Time `json:"end_date" example:"2024-07-25T15:56:35.352601Z"`
+}
+
+type SessionVerificationStatus string
+
+const (
+	SessionVerificationStatusValid    SessionVerificationStatus = "valid"
+	SessionVerificationStatusTampered SessionVerificationStatus = "tampered"
+	SessionVerificationStatusUnsealed SessionVerificationStatus = "unsealed"
+	SessionVerificationStatusPurged   SessionVerificationStatus = "purged"
+)
+
+type SessionVerification struct {
+	// The id of the session
+	SessionID string `json:"session_id" format:"uuid" example:"B19BBA55-8646-4D94-A40A-C3AFE2F4BAFD"`
+	// The outcome of the verification
+	// * valid - the event stream matches its sealed digest
+	// * tampered - the event stream was truncated or modified, or its seal is not valid
+	// * unsealed - the session was not sealed by the gateway
+	// * purged - the event stream was changed by a retention policy after the session was sealed
+	Status SessionVerificationStatus `json:"status" example:"valid"`
+	// The description of the outcome
+	Reason string `json:"reason" example:"the event stream matches its signed digest"`
+	// The number of events found in the stored event stream
+	Events int `json:"events" example:"42"`
+	// The number of events when the session was sealed
+	SealedEvents int `json:"sealed_events" example:"42"`
+	// The digest computed from the stored event stream
+	Digest string `json:"digest" example:"3f1c2b8a5d5e1f0c9a7b6d4e2f1a0b9c8d7e6f5a4b3c2d1e0f9a8b7c6d5e4f3a"`
+	// The digest when the session was sealed
+	SealedDigest string `json:"sealed_digest" example:"3f1c2b8a5d5e1f0c9a7b6d4e2f1a0b9c8d7e6f5a4b3c2d1e0f9a8b7c6d5e4f3a"`
+	// The identifier of the gateway key that signed the digest, it's empty when the digest is not signed
+	KeyID string `json:"key_id" example:"a1b2c3d4e5f60718"`
+	// Indicates the signature was verified with the key of the gateway
+	SignatureVerified bool `json:"signature_verified" example:"true"`
+	// The time the session was sealed
+	SealedAt *time.Time `json:"sealed_at" example:"2024-07-25T15:56:35.317601Z"`
+	// The time the digest was anchored in the external anchor file
+	AnchoredAt *time.Time `json:"anchored_at" example:"2024-07-25T16:00:00.000000Z"`
+}
+
 type SessionUpdateMetadataRequest struct {
 	// The metadata field
 	Metadata map[string]any `json:"metadata" swaggertype:"object,string" example:"reason:fix-issue"`
@@ -556,13 +651,16 @@ const (
 	ReviewStatusProcessing ReviewStatusType = "PROCESSING"
 	ReviewStatusExecuted   ReviewStatusType = "EXECUTED"
 	ReviewStatusUnknown    ReviewStatusType = "UNKNOWN"
+	ReviewStatusExpired    ReviewStatusType = "EXPIRED"
 
 	ReviewStatusRequestApprovedType ReviewRequestStatusType = ReviewRequestStatusType(ReviewStatusApproved)
 	ReviewStatusRequestRejectedType ReviewRequestStatusType = ReviewRequestStatusType(ReviewStatusRejected)
 	ReviewStatusRequestRevokedType  ReviewRequestStatusType = ReviewRequestStatusType(ReviewStatusRevoked)
 
-	ReviewTypeJit     ReviewType = "jit"
-	ReviewTypeOneTime ReviewType = "onetime"
+	ReviewTypeJit        ReviewType = "jit"
+	ReviewTypeOneTime    ReviewType = "onetime"
+	ReviewTypeScheduled  ReviewType = "scheduled"
+	ReviewTypeBreakGlass ReviewType = "break_glass"
 )
 
 type ReviewRequest struct {
@@ -583,7 +681,9 @@ type Review struct {
 	// The type of this review
 	// * onetime - Represents a one time execution
 	// * jit - Represents a time based review
-	Type ReviewType `json:"type" enums:"onetime,jit" readonly:"true"`
+	// * scheduled - Represents a time based review for a future time window
+	// * break_glass - Represents an emergency access granted before the review, the reviewers acknowledge it afterwards
+	Type ReviewType `json:"type" enums:"onetime,jit,scheduled,break_glass" readonly:"true"`
 	// The id of session
 	Session string `json:"session" format:"uuid" readonly:"true" example:"35DB0A2F-E5CE-4AD8-A308-55C3108956E5"`
 	// The input that was issued when the resource was created
@@ -600,6 +700,7 @@ type Review struct {
 	// * PROCESSING - The review is being executed
 	// * EXECUTED - The review was executed
 	// * UNKNOWN - Unable to know the status of the review
+	// * EXPIRED - The resource was not reviewed in time
 	Status ReviewStatusType `json:"status"`
 	// The time when this review was revoked
 	RevokeAt *time.Time `json:"revoke_at" readonly:"true" example:""`
@@ -609,6 +710,85 @@ type Review struct {
 	Connection ReviewConnection `json:"review_connection" readonly:"true"`
 	// Contains the groups that requires to approve this review
 	ReviewGroupsData []ReviewGroup `json:"review_groups_data" readonly:"true"`
+	// The policy evaluated to approve this review, it's the policy of the connection when the review was created
+	Policy ReviewPolicy `json:"policy" readonly:"true"`
+	// The last request of additional access time of a jit review
+	Extension *ReviewExtension `json:"extension" readonly:"true"`
+	// The start of the access window of a scheduled or break-glass review, the access ends at the revoke time
+	AccessStartAt *time.Time `json:"access_start_at" readonly:"true" example:"2024-07-26T22:00:00Z"`
+	// The reason of a scheduled or break-glass review
+	AccessReason string `json:"access_reason" readonly:"true" example:"database maintenance"`
+	// The Jira issue linked to a scheduled review
+	JiraIssueKey string `json:"jira_issue_key" readonly:"true" example:"OPS-123"`
+}
+
+type ScheduledReviewRequest struct {
+	// The name or the id of the connection to access
+	Connection string `json:"connection" binding:"required" example:"pgdemo"`
+	// The start of the access window, it must be in the future
+	StartAt time.Time `json:"start_at" binding:"required" example:"2024-07-26T22:00:00Z"`
+	// The duration in seconds of the access window, it must be between 60 seconds and 48 hours
+	AccessDurationSec int `json:"access_duration_sec" binding:"required" example:"7200"`
+	// The reason to access the connection
+	Reason string `json:"reason" binding:"required" example:"database maintenance"`
+	// The Jira issue of the change, e.g.: the approved change request
+	JiraIssueKey string `json:"jira_issue_key" example:"OPS-123"`
+}
+
+type ReviewExtensionRequest struct {
+	// The additional time in seconds to request for the active jit review of the connection.
+	// It must be between 60 seconds and 48 hours
+	AccessDurationSec int `json:"access_duration_sec" binding:"required" example:"1800"`
+}
+
+type ReviewExtension struct {
+	// The additional time in seconds requested by the owner of the review
+	AccessDurationSec int `json:"access_duration_sec" readonly:"true" example:"1800"`
+	// The status of the extension
+	// * PENDING - The extension is waiting to be reviewed
+	// * APPROVED - The extension is approved, the revoke time of the review was pushed out
+	// * REJECTED - The extension is rejected
+	Status ReviewStatusType `json:"status" readonly:"true" example:"PENDING"`
+	// The time the extension was requested
+	CreatedAt string `json:"created_at" readonly:"true" example:"2024-07-25T19:36:41Z"`
+	// Contains the groups that requires to approve this extension, they are the same groups of the review
+	ReviewGroupsData []ReviewGroup `json:"review_groups_data" readonly:"true"`
+}
+
+type ReviewPolicy struct {
+	// The minimum of distinct members of a group that must approve to consider the group approved
+	MinApprovalsPerGroup int `json:"min_approvals_per_group" default:"1" example:"2"`
+	// The minimum of distinct approvers considering all groups
+	MinApprovers int `json:"min_approvers" default:"1" example:"2"`
+	// The groups that must approve the review
+	// * all - Every group must approve the review
+	// * any - At least one group must approve the review
+	Mode string `json:"mode" enums:"all,any" default:"all" example:"all"`
+	// A group that must always approve the review, it must be one of the reviewers of the connection
+	RequiredGroup string `json:"required_group" example:"sre"`
+	// The time in seconds to expire a pending review, the client waiting for the review is released with an error.
+	// A zero value means the review never expires
+	ExpirationSec int `json:"expiration_sec" example:"3600"`
+	// The time in seconds to notify the escalation group when nobody acts on a pending review.
+	// It must be lower than the expiration time
+	EscalationSec int `json:"escalation_sec" example:"900"`
+	// The fallback group notified through Slack and webhooks when the review is escalated.
+	// The approval of this group approves the review on behalf of the pending groups
+	EscalationGroup string `json:"escalation_group" example:"managers"`
+	// Allows users to open a session without waiting for the review in emergencies by stating a reason.
+	// The reviewer groups are notified right away and must acknowledge the access afterwards
+	BreakGlass bool `json:"break_glass" default:"false" example:"true"`
+	// The access time in seconds of a break-glass session, it must not be greater than 48 hours
+	BreakGlassDurationSec int `json:"break_glass_duration_sec" default:"3600" example:"1800"`
+	// The maximum of break-glass sessions of a user in the connection in a period of 24 hours
+	BreakGlassMaxPerDay int `json:"break_glass_max_per_day" default:"1" example:"2"`
+}
+
+type ReviewApproval struct {
+	// The member that approved the review
+	ReviewedBy ReviewOwner `json:"reviewed_by" readonly:"true"`
+	// The date which the approval was performed
+	ReviewDate string `json:"review_date" readonly:"true" example:"2024-07-25T19:36:41Z"`
 }
 
 type ReviewOwner struct {
@@ -643,6 +823,10 @@ type ReviewGroup struct {
 	ReviewedBy *ReviewOwner `json:"reviewed_by" readonly:"true"`
 	// The date which this review was performed
 	ReviewDate *string `json:"review_date" readonly:"true" example:"2024-07-25T19:36:41Z"`
+	// The approvals of the members of this group
+	Approvals []ReviewApproval `json:"approvals" readonly:"true"`
+	// If this group was added by the escalation of the review
+	Escalation bool `json:"escalation" readonly:"true" example:"false"`
 }
 
 type Plugin struct {
@@ -1005,15 +1189,27 @@ type GuardRailRuleRequest struct {
 			"description": "<optional-description>",
 			"input": {
 				"rules": [
-					{"type": "deny_words_list", "words": ["SELECT"], "pattern_regex": ""}
+					{"type": "deny_words_list", "words": ["SELECT"], "pattern_regex": ""},
+					{"type": "sql_deny_statements", "dialect": "postgres", "statements": ["ddl", "delete_without_where"], "action": "review"}
 				]
 			},
 			"output": {
 				"rules": [
-					{"type": "pattern_match", "words": [], "pattern_regex": "[A-Z0-9]+"}
+					{"type": "pattern_match", "words": [], "pattern_regex": "[A-Z0-9]+", "action": "mask"}
 				]
 			}
 		}
+
+		The action of each rule defaults to block, the available values are:
+		* block - reject the input or output
+		* warn - allow it, recording the match in the session and sending a webhook event
+		* review - route the input to a one time review (input rules only)
+		* mask - replace the matched content of the output (output rules only)
+
+		The redis_deny_commands rule type blocks redis commands by name, e.g.:
+		{"type": "redis_deny_commands", "commands": ["FLUSHALL", "CONFIG SET"]}
+		The scripting commands (EVAL, EVALSHA, FCALL and their read only variants) are always
+		blocked by this rule type, the commands called by a script can't be validated.
 	*/
 	Input map[string]any `json:"input"`
 	// The output rule
@@ -1087,6 +1283,92 @@ type GuardRailRuleResponse struct {
 	UpdatedAt time.Time `json:"updated_at" readonly:"true" example:"2024-07-25T15:56:35.317601Z"`
 }
 
+type GuardRailTestRequest struct {
+	// The input rule, it has the same format of the guard rail rule resource
+	/*
+		{
+			"rules": [
+				{"type": "sql_deny_statements", "dialect": "postgres", "statements": ["ddl"]}
+			]
+		}
+	*/
+	Input map[string]any `json:"input"`
+	// The output rule, it has the same format of the guard rail rule resource
+	/*
+		{
+			"rules": [
+				{"type": "pattern_match", "pattern_regex": "[0-9]{3}-[0-9]{2}-[0-9]{4}", "action": "mask"}
+			]
+		}
+	*/
+	Output map[string]any `json:"output"`
+	// A sample input to validate against the input rule
+	SampleInput string `json:"sample_input" example:"SELECT 1; DROP TABLE customers;"`
+	// A sample output to validate against the output rule
+	SampleOutput string `json:"sample_output" example:"ssn: 123-45-6789"`
+	// The connection to replay the rules against its latest sessions
+	ConnectionName string `json:"connection_name" example:"pgdemo"`
+	// The number of latest sessions of the connection to replay, it's required when connection_name is set
+	SessionsLimit int `json:"sessions_limit" minimum:"1" maximum:"100" example:"50"`
+}
+
+type GuardRailTestResponse struct {
+	// The rules that matched the sample input and output
+	Matches []GuardRailTestMatch `json:"matches"`
+	// The result of replaying the rules against the latest sessions of a connection
+	Replay *GuardRailReplayResult `json:"replay"`
+}
+
+type GuardRailTestMatch struct {
+	// The direction of the rule
+	Direction string `json:"direction" enums:"input,output" example:"input"`
+	// The position of the rule in the list of rules of its direction
+	RuleIndex int `json:"rule_index" example:"0"`
+	// The type of the rule
+	RuleType string `json:"rule_type" example:"sql_deny_statements"`
+	// The action of the rule
+	Action string `json:"action" enums:"block,warn,review,mask" example:"block"`
+	// The byte ranges of the sample that matched the rule
+	Offsets []GuardRailMatchOffset `json:"offsets"`
+	// A human readable explanation why the rule matched
+	Explanation string `json:"explanation" example:"the statement is classified as ddl"`
+}
+
+type GuardRailMatchOffset struct {
+	// The byte offset where the match starts (inclusive)
+	Start int `json:"start" example:"10"`
+	// The byte offset where the match ends (exclusive)
+	End int `json:"end" example:"30"`
+}
+
+type GuardRailReplayResult struct {
+	// The connection that had its sessions replayed
+	ConnectionName string `json:"connection_name" example:"pgdemo"`
+	// The number of sessions evaluated
+	SessionsEvaluated int `json:"sessions_evaluated" example:"50"`
+	// The number of sessions that would have been blocked
+	Blocked int `json:"blocked" example:"3"`
+	// The number of sessions that would have matched a rule with the warn action
+	Warned int `json:"warned" example:"5"`
+	// The number of sessions that would have required a review
+	Reviewed int `json:"reviewed" example:"1"`
+	// The number of sessions that would have its output masked
+	Masked int `json:"masked" example:"10"`
+	// The sessions that would have been blocked
+	BlockedSessions []GuardRailReplaySession `json:"blocked_sessions"`
+}
+
+type GuardRailReplaySession struct {
+	// The session identifier
+	ID string `json:"id" format:"uuid" example:"5701046A-7B7A-4A78-ABB0-A24C95E6FE54"`
+	// The user that performed the session
+	UserEmail string `json:"user_email" example:"john.doe@example.com"`
+	// The reason the session would have been blocked
+	Reason string `json:"reason" example:"validation error, match guard rails input rule, type=sql_deny_statements, statement=ddl"`
+	// The time the session was created
+	CreatedAt time.Time `json:"created_at" example:"2024-07-25T15:56:35.317601Z"`
+}
+
 // Connection Schema Response is the response for the connection schema
 type ConnectionSchemaResponse struct {
 	Schemas []ConnectionSchema `json:"schemas"`
@@ -1350,3 +1632,316 @@ type DBRoleJobStatusResult struct {
 type DBRoleJobList struct {
 	Items []DBRoleJob `json:"items"`
 }
+
+type RetentionMode string
+
+const (
+	RetentionModeEventStream RetentionMode = "event_stream"
+	RetentionModeOutput      RetentionMode = "output"
+	RetentionModeAll         RetentionMode = "all"
+)
+
+type RetentionPolicyRequest struct {
+	// The name of the connection the policy applies to, an empty value applies it to all
+	// connections of the organization. A connection policy overrides the organization policy of the same mode.
+	ConnectionName string `json:"connection_name" example:"pgdemo"`
+	// What is purged when a session is older than the retention days
+	// * event_stream - the event stream of the session, the metadata and the input are kept
+	// * output - the output events of the session, the remaining events are kept
+	// * all - the session with all its content
+	Mode RetentionMode `json:"mode" binding:"required" example:"output"`
+	// The number of days after the end of a session to purge its content
+	RetentionDays int `json:"retention_days" binding:"required" example:"30"`
+}
+
+type RetentionPolicy struct {
+	// The resource identifier
+	ID string `json:"id" format:"uuid" readonly:"true" example:"15B5A2FD-0706-4A47-B1CF-B93CCFC5B3D7"`
+	// The name of the connection the policy applies to, it's empty for organization policies
+	ConnectionName string `json:"connection_name" example:"pgdemo"`
+	// What is purged when a session is older than the retention days
+	Mode RetentionMode `json:"mode" example:"output"`
+	// The number of days after the end of a session to purge its content
+	RetentionDays int `json:"retention_days" example:"30"`
+	// The time the resource was created
+	CreatedAt time.Time `json:"created_at" readonly:"true" example:"2024-07-25T15:56:35.317601Z"`
+	// The time the resource was updated
+	UpdatedAt time.Time `json:"updated_at" readonly:"true" example:"2024-07-25T15:56:35.317601Z"`
+}
+
+type RetentionPurge struct {
+	// The resource identifier
+	ID string `json:"id" format:"uuid" example:"15B5A2FD-0706-4A47-B1CF-B93CCFC5B3D7"`
+	// The policy that purged the session, it's empty when the policy was removed
+	PolicyID string `json:"policy_id" format:"uuid" example:"5701046A-7B7A-4A78-ABB0-A24C95E6FE54"`
+	// The purged session
+	SessionID string `json:"session_id" format:"uuid" example:"B19BBA55-8646-4D94-A40A-C3AFE2F4BAFD"`
+	// The name of the connection of the session
+	Connection string `json:"connection" example:"pgdemo"`
+	// What was purged from the session
+	Mode RetentionMode `json:"mode" example:"output"`
+	// The time the session was created
+	SessionCreatedAt time.Time `json:"session_created_at" example:"2024-07-25T15:56:35.317601Z"`
+	// The time the session was purged
+	PurgedAt time.Time `json:"purged_at" example:"2024-08-24T15:56:35.317601Z"`
+}
+
+type RetentionPurgeList struct {
+	Items       []RetentionPurge `json:"data"`
+	Total       int64            `json:"total" example:"100"`
+	HasNextPage bool             `json:"has_next_page"`
+}
+
+type APITokenScope string
+
+const (
+	APITokenScopeSessionsRead    APITokenScope = "sessions:read"
+	APITokenScopeConnectionsRead APITokenScope = "connections:read"
+	APITokenScopeExec            APITokenScope = "exec"
+	APITokenScopeConnect         APITokenScope = "connect"
+)
+
+type APITokenStatus string
+
+const (
+	APITokenStatusActive  APITokenStatus = "active"
+	APITokenStatusExpired APITokenStatus = "expired"
+	APITokenStatusRevoked APITokenStatus = "revoked"
+)
+
+type APITokenRequest struct {
+	// The name of the token, it must be unique among the active tokens of the user
+	Name string `json:"name" binding:"required" example:"ci-pipeline"`
+	// The subject of the user or service account the token acts on behalf of, it defaults to the authenticated user.
+	// Only admin users are allowed to create tokens for other users.
+	Subject string `json:"subject" example:"ci-bot@serviceaccount"`
+	// The routes and verbs the token is allowed to access
+	// * sessions:read - list and read sessions
+	// * connections:read - list and read connections
+	// * exec - run one-off executions via the api or the exec command
+	// * connect - open interactive sessions and proxies to connections
+	Scopes []APITokenScope `json:"scopes" binding:"required" example:"exec"`
+	// The connections the token is restricted to, an empty value allows all connections the user has access to
+	Connections []string `json:"connections" example:"pgdemo"`
+	// The time the token expires, the token never expires when it's empty
+	ExpiresAt *time.Time `json:"expires_at" example:"2025-07-25T15:56:35.317601Z"`
+}
+
+type APIToken struct {
+	// The resource identifier
+	ID string `json:"id" format:"uuid" readonly:"true" example:"15B5A2FD-0706-4A47-B1CF-B93CCFC5B3D7"`
+	// The name of the token
+	Name string `json:"name" example:"ci-pipeline"`
+	// The subject of the user or service account the token acts on behalf of
+	Subject string `json:"subject" example:"ci-bot@serviceaccount"`
+	// The email of the user or service account the token acts on behalf of
+	Email string `json:"email" example:"ci-bot@serviceaccount"`
+	// The value of the token, it's returned only when the token is created
+	Token string `json:"token,omitempty" readonly:"true" example:"hpat_0Q8yP2mvaXhKB1uTzJpXUl3H3Q4cE6nVqGdAf9Rk2sw"`
+	// The first characters of the token, used to identify it
+	TokenPrefix string `json:"token_prefix" readonly:"true" example:"hpat_0Q8yP2mv"`
+	// The routes and verbs the token is allowed to access
+	Scopes []APITokenScope `json:"scopes" example:"exec"`
+	// The connections the token is restricted to, it's empty when all connections are allowed
+	Connections []string `json:"connections" example:"pgdemo"`
+	// The status of the token
+	Status APITokenStatus `json:"status" readonly:"true" example:"active"`
+	// The time the token expires, it's empty when the token never expires
+	ExpiresAt *time.Time `json:"expires_at" example:"2025-07-25T15:56:35.317601Z"`
+	// The last time the token was used to authenticate
+	LastUsedAt *time.Time `json:"last_used_at" readonly:"true" example:"2024-07-25T15:56:35.317601Z"`
+	// The time the token was revoked
+	RevokedAt *time.Time `json:"revoked_at" readonly:"true" example:"2024-07-25T15:56:35.317601Z"`
+	// The email of the user that created the token
+	CreatedBy string `json:"created_by" readonly:"true" example:"john.wick@bad.org"`
+	// The time the resource was created
+	CreatedAt time.Time `json:"created_at" readonly:"true" example:"2024-07-25T15:56:35.317601Z"`
+}
+
+type PermissionType string
+
+const (
+	PermissionAgentsRead       PermissionType = "agents:read"
+	PermissionAgentsWrite      PermissionType = "agents:write"
+	PermissionConnectionsRead  PermissionType = "connections:read"
+	PermissionConnectionsWrite PermissionType = "connections:write"
+	PermissionGuardRailsRead   PermissionType = "guardrails:read"
+	PermissionGuardRailsWrite  PermissionType = "guardrails:write"
+	PermissionPluginsRead      PermissionType = "plugins:read"
+	PermissionPluginsWrite     PermissionType = "plugins:write"
+	PermissionPoliciesRead     PermissionType = "policies:read"
+	PermissionPoliciesWrite    PermissionType = "policies:write"
+	PermissionRetentionRead    PermissionType = "retention:read"
+	PermissionRetentionWrite   PermissionType = "retention:write"
+	PermissionReviewsRead      PermissionType = "reviews:read"
+	PermissionReviewsWrite     PermissionType = "reviews:write"
+	PermissionSessionsRead     PermissionType = "sessions:read"
+	PermissionUsersRead        PermissionType = "users:read"
+	PermissionUsersWrite       PermissionType = "users:write"
+)
+
+var AvailablePermissions = []PermissionType{
+	PermissionAgentsRead,
+	PermissionAgentsWrite,
+	PermissionConnectionsRead,
+	PermissionConnectionsWrite,
+	PermissionGuardRailsRead,
+	PermissionGuardRailsWrite,
+	PermissionPluginsRead,
+	PermissionPluginsWrite,
+	PermissionPoliciesRead,
+	PermissionPoliciesWrite,
+	PermissionRetentionRead,
+	PermissionRetentionWrite,
+	PermissionReviewsRead,
+	PermissionReviewsWrite,
+	PermissionSessionsRead,
+	PermissionUsersRead,
+	PermissionUsersWrite,
+}
+
+type RoleRequest struct {
+	// The name of the role, it must be unique in the organization
+	Name string `json:"name" binding:"required" example:"connection-manager"`
+	// The description of the role
+	Description string `json:"description" example:"Manage connections without managing users"`
+	// The permissions granted by the role, each permission grants access to the routes of a resource
+	// * <resource>:read - list and read the resources
+	// * <resource>:write - create, update and remove the resources
+	// * sessions:read - list and read the sessions of all users
+	Permissions []PermissionType `json:"permissions" binding:"required" example:"connections:read,connections:write"`
+	// The groups of users granted with the role, usually the groups synchronized from the identity provider
+	Groups []string `json:"groups" example:"dba-team"`
+}
+
+type Role struct {
+	// The resource identifier
+	ID string `json:"id" format:"uuid" readonly:"true" example:"15B5A2FD-0706-4A47-B1CF-B93CCFC5B3D7"`
+	// The name of the role
+	Name string `json:"name" example:"connection-manager"`
+	// The description of the role
+	Description string `json:"description" example:"Manage connections without managing users"`
+	// The permissions granted by the role
+	Permissions []PermissionType `json:"permissions" example:"connections:read,connections:write"`
+	// The groups of users granted with the role
+	Groups []string `json:"groups" example:"dba-team"`
+	// The time the resource was created
+	CreatedAt time.Time `json:"created_at" readonly:"true" example:"2024-07-25T15:56:35.317601Z"`
+	// The time the resource was updated
+	UpdatedAt time.Time `json:"updated_at" readonly:"true" example:"2024-07-25T15:56:35.317601Z"`
+}
+
+type AccessPolicyEffect string
+
+const (
+	AccessPolicyEffectAllow AccessPolicyEffect = "allow"
+	AccessPolicyEffectDeny  AccessPolicyEffect = "deny"
+)
+
+type AccessPolicyTimeWindow struct {
+	// The days of the week the window applies to, an empty value applies to all days
+	Days []string `json:"days" enums:"sun,mon,tue,wed,thu,fri,sat" example:"mon,tue,wed,thu,fri"`
+	// The start of the window in the format HH:MM, it defaults to 00:00
+	StartTime string `json:"start_time" example:"09:00"`
+	// The end of the window in the format HH:MM, it defaults to 24:00.
+	// Windows ending before they start cross midnight, e.g.: 22:00-06:00
+	EndTime string `json:"end_time" example:"18:00"`
+	// The IANA timezone of the window, it defaults to UTC
+	Timezone string `json:"timezone" example:"America/Sao_Paulo"`
+}
+
+type AccessPolicyRequest struct {
+	// The name of the policy, it must be unique in the organization
+	Name string `json:"name" binding:"required" example:"contractors-staging"`
+	// The description of the policy
+	Description string `json:"description" example:"Contractors may only exec on staging during business hours"`
+	// How the policy affects the requests matching its conditions
+	// * allow - the users of the policy are allowed to access only the requests matching the conditions of any allow policy
+	// * deny - the requests matching the conditions are denied, it takes precedence over allow policies
+	Effect AccessPolicyEffect `json:"effect" binding:"required" example:"allow"`
+	// The policy applies to the users of any of these groups, an empty value applies it to all users
+	Groups []string `json:"groups" example:"contractors"`
+	// Condition: the connection must have all these tags
+	ConnectionTags map[string]string `json:"connection_tags" example:"env:staging"`
+	// Condition: the type or the subtype of the connection must be one of these values
+	ConnectionTypes []string `json:"connection_types" example:"postgres"`
+	// Condition: the verb of the request must be one of these values.
+	// Reading connections is allowed by allow policies and it's not denied by deny policies with this condition.
+	Verbs []string `json:"verbs" enums:"exec,connect" example:"exec"`
+	// Condition: the client origin of the request must be one of these values.
+	// Reading connections is allowed by allow policies and it's not denied by deny policies with this condition.
+	ClientOrigins []string `json:"client_origins" enums:"client,client-api,client-api-runbooks,client-proxymanager" example:"client"`
+	// Condition: the source ip of the request must be one of these ip addresses or cidrs
+	SourceIPs []string `json:"source_ips" example:"10.0.0.0/8"`
+	// Condition: the time of the request must be within the window
+	TimeWindow *AccessPolicyTimeWindow `json:"time_window"`
+}
+
+type AccessPolicy struct {
+	// The resource identifier
+	ID string `json:"id" format:"uuid" readonly:"true" example:"15B5A2FD-0706-4A47-B1CF-B93CCFC5B3D7"`
+	// The name of the policy
+	Name string `json:"name" example:"contractors-staging"`
+	// The description of the policy
+	Description string `json:"description" example:"Contractors may only exec on staging during business hours"`
+	// How the policy affects the requests matching its conditions
+	Effect AccessPolicyEffect `json:"effect" example:"allow"`
+	// The policy applies to the users of any of these groups, it applies to all users when it's empty
+	Groups []string `json:"groups" example:"contractors"`
+	// Condition: the connection must have all these tags
+	ConnectionTags map[string]string `json:"connection_tags" example:"env:staging"`
+	// Condition: the type or the subtype of the connection must be one of these values
+	ConnectionTypes []string `json:"connection_types" example:"postgres"`
+	// Condition: the verb of the request must be one of these values
+	Verbs []string `json:"verbs" example:"exec"`
+	// Condition: the client origin of the request must be one of these values
+	ClientOrigins []string `json:"client_origins" example:"client"`
+	// Condition: the source ip of the request must be one of these ip addresses or cidrs
+	SourceIPs []string `json:"source_ips" example:"10.0.0.0/8"`
+	// Condition: the time of the request must be within the window
+	TimeWindow *AccessPolicyTimeWindow `json:"time_window"`
+	// The time the resource was created
+	CreatedAt time.Time `json:"created_at" readonly:"true" example:"2024-07-25T15:56:35.317601Z"`
+	// The time the resource was updated
+	UpdatedAt time.Time `json:"updated_at" readonly:"true" example:"2024-07-25T15:56:35.317601Z"`
+}
+
+type AccessPolicyResult struct {
+	// The identifier of the policy
+	ID string `json:"id" format:"uuid" example:"15B5A2FD-0706-4A47-B1CF-B93CCFC5B3D7"`
+	// The name of the policy
+	Name string `json:"name" example:"contractors-staging"`
+	// The effect of the policy
+	Effect AccessPolicyEffect `json:"effect" example:"allow"`
+	// If the policy applies to the groups of the user
+	Applicable bool `json:"applicable" example:"true"`
+	// If all conditions of the policy matched the request
+	Matched bool `json:"matched" example:"false"`
+	// Why the policy didn't match the request
+	Reason string `json:"reason" example:"the time 20:00 is not between 09:00 and 18:00 (UTC)"`
+}
+
+type AccessPolicyEvaluation struct {
+	// If the user is allowed to access the connection
+	Allowed bool `json:"allowed" example:"false"`
+	// The reason of the decision
+	Reason string `json:"reason" example:"the request does not match any of the allow policies: contractors-staging"`
+	// The email of the user evaluated
+	User string `json:"user" example:"john.wick@bad.org"`
+	// The name of the connection evaluated
+	Connection string `json:"connection" example:"pgdemo"`
+	// The verb evaluated, it's empty when reading the connection
+	Verb string `json:"verb" example:"exec"`
+	// The client origin evaluated
+	ClientOrigin string `json:"client_origin" example:"client"`
+	// The source ip evaluated
+	SourceIP string `json:"source_ip" example:"10.0.0.1"`
+	// The time evaluated
+	Time time.Time `json:"time" example:"2024-07-25T15:56:35.317601Z"`
+	// If the groups of the user are allowed to access the connection by the access control plugin
+	AccessControlAllowed bool `json:"access_control_allowed" example:"true"`
+	// If the access policies allow the request
+	PoliciesAllowed bool `json:"policies_allowed" example:"false"`
+	// The outcome of each policy of the organization
+	Policies []AccessPolicyResult `json:"policies"`
+}
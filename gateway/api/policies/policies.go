@@ -0,0 +1,364 @@
+package apipolicies
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hoophq/hoop/common/log"
+	"github.com/hoophq/hoop/gateway/accesspolicy"
+	apiconnections "github.com/hoophq/hoop/gateway/api/connections"
+	"github.com/hoophq/hoop/gateway/api/openapi"
+	"github.com/hoophq/hoop/gateway/models"
+	"github.com/hoophq/hoop/gateway/pgrest"
+	pguserauth "github.com/hoophq/hoop/gateway/pgrest/userauth"
+	"github.com/hoophq/hoop/gateway/storagev2"
+)
+
+// CreateAccessPolicy
+//
+//	@Summary		Create Access Policy
+//	@Description	Create a policy that allows or denies access to connections based on the groups of the user,
+//	@Description	the tags and the type of the connection, the verb, the client origin, the time and the source ip of the request.
+//	@Description	Policies restrict the access granted by the groups of the users, they're evaluated in the api and when clients connect to the gateway.
+//	@Tags			Access Policies
+//	@Accept			json
+//	@Produce		json
+//	@Param			request			body		openapi.AccessPolicyRequest	true	"The request body resource"
+//	@Success		201				{object}	openapi.AccessPolicy
+//	@Failure		400,409,422,500	{object}	openapi.HTTPError
+//	@Router			/policies [post]
+func Post(c *gin.Context) {
+	ctx := storagev2.ParseContext(c)
+	policy := parseRequestPayload(c)
+	if policy == nil {
+		return
+	}
+	policy.ID = uuid.NewString()
+	policy.OrgID = ctx.GetOrgID()
+	policy.CreatedAt = time.Now().UTC()
+	policy.UpdatedAt = time.Now().UTC()
+	err := models.CreateAccessPolicy(policy)
+	switch err {
+	case models.ErrAlreadyExists:
+		c.JSON(http.StatusConflict, gin.H{"message": fmt.Sprintf("a policy named %q already exists", policy.Name)})
+	case nil:
+		c.JSON(http.StatusCreated, toOpenApi(policy))
+	default:
+		log.Errorf("failed creating access policy, reason=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	}
+}
+
+// UpdateAccessPolicy
+//
+//	@Summary		Update Access Policy
+//	@Description	Update an Access Policy
+//	@Tags			Access Policies
+//	@Accept			json
+//	@Produce		json
+//	@Param			id					path		string						true	"The unique identifier of the resource"
+//	@Param			request				body		openapi.AccessPolicyRequest	true	"The request body resource"
+//	@Success		200					{object}	openapi.AccessPolicy
+//	@Failure		400,404,409,422,500	{object}	openapi.HTTPError
+//	@Router			/policies/{id} [put]
+func Put(c *gin.Context) {
+	ctx := storagev2.ParseContext(c)
+	policy := parseRequestPayload(c)
+	if policy == nil {
+		return
+	}
+	policy.ID = c.Param("id")
+	policy.OrgID = ctx.GetOrgID()
+	policy.UpdatedAt = time.Now().UTC()
+	err := models.UpdateAccessPolicy(policy)
+	switch err {
+	case models.ErrNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"message": "resource not found"})
+	case models.ErrAlreadyExists:
+		c.JSON(http.StatusConflict, gin.H{"message": fmt.Sprintf("a policy named %q already exists", policy.Name)})
+	case nil:
+		c.JSON(http.StatusOK, toOpenApi(policy))
+	default:
+		log.Errorf("failed updating access policy, reason=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	}
+}
+
+// ListAccessPolicies
+//
+//	@Summary		List Access Policies
+//	@Description	List the access policies of the organization
+//	@Tags			Access Policies
+//	@Produce		json
+//	@Success		200	{array}		openapi.AccessPolicy
+//	@Failure		500	{object}	openapi.HTTPError
+//	@Router			/policies [get]
+func List(c *gin.Context) {
+	ctx := storagev2.ParseContext(c)
+	items, err := models.ListAccessPolicies(ctx.GetOrgID())
+	if err != nil {
+		log.Errorf("failed listing access policies, reason=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	policies := []openapi.AccessPolicy{}
+	for _, p := range items {
+		policies = append(policies, *toOpenApi(&p))
+	}
+	c.JSON(http.StatusOK, policies)
+}
+
+// GetAccessPolicy
+//
+//	@Summary		Get Access Policy
+//	@Description	Get an Access Policy
+//	@Tags			Access Policies
+//	@Produce		json
+//	@Param			id		path		string	true	"The unique identifier of the resource"
+//	@Success		200		{object}	openapi.AccessPolicy
+//	@Failure		404,500	{object}	openapi.HTTPError
+//	@Router			/policies/{id} [get]
+func Get(c *gin.Context) {
+	ctx := storagev2.ParseContext(c)
+	policy, err := models.GetAccessPolicy(ctx.GetOrgID(), c.Param("id"))
+	switch err {
+	case models.ErrNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"message": "resource not found"})
+	case nil:
+		c.JSON(http.StatusOK, toOpenApi(policy))
+	default:
+		log.Errorf("failed fetching access policy, reason=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	}
+}
+
+// DeleteAccessPolicy
+//
+//	@Summary		Delete Access Policy
+//	@Description	Delete an Access Policy
+//	@Tags			Access Policies
+//	@Produce		json
+//	@Param			id	path	string	true	"The unique identifier of the resource"
+//	@Success		204
+//	@Failure		404,500	{object}	openapi.HTTPError
+//	@Router			/policies/{id} [delete]
+func Delete(c *gin.Context) {
+	ctx := storagev2.ParseContext(c)
+	err := models.DeleteAccessPolicy(ctx.GetOrgID(), c.Param("id"))
+	switch err {
+	case models.ErrNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"message": "resource not found"})
+	case nil:
+		c.Writer.WriteHeader(http.StatusNoContent)
+	default:
+		log.Errorf("failed removing access policy, reason=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	}
+}
+
+// EvaluateAccessPolicies
+//
+//	@Summary		Evaluate Access Policies
+//	@Description	Explain if a user is allowed to access a connection, the outcome of each policy is returned.
+//	@Description	The attributes of the request that are not provided default to the attributes of the current request.
+//	@Tags			Access Policies
+//	@Produce		json
+//	@Param			connection	query		string	true	"The name of the connection"
+//	@Param			user		query		string	false	"The email or the subject of the user, it defaults to the authenticated user"
+//	@Param			verb		query		string	false	"The verb of the request, an empty value evaluates reading the connection"	Enums(exec, connect)
+//	@Param			origin		query		string	false	"The client origin of the request"											Enums(client, client-api, client-api-runbooks, client-proxymanager)
+//	@Param			source_ip	query		string	false	"The source ip of the request, it defaults to the ip of the client"
+//	@Param			time		query		string	false	"The time of the request in RFC3339 format, it defaults to the current time"
+//	@Success		200			{object}	openapi.AccessPolicyEvaluation
+//	@Failure		404,422,500	{object}	openapi.HTTPError
+//	@Router			/policies/evaluate [get]
+func Evaluate(c *gin.Context) {
+	ctx := storagev2.ParseContext(c)
+	connectionName := c.Query("connection")
+	if connectionName == "" {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": "the connection query parameter is required"})
+		return
+	}
+	req := accesspolicy.Request{
+		Verb:     c.Query("verb"),
+		Origin:   c.Query("origin"),
+		SourceIP: c.DefaultQuery("source_ip", c.ClientIP()),
+		Time:     time.Now().UTC(),
+	}
+	if req.Verb != "" && !slices.Contains(accesspolicy.Verbs, req.Verb) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": fmt.Sprintf("invalid verb %q, accepted values are: %v",
+			req.Verb, strings.Join(accesspolicy.Verbs, ", "))})
+		return
+	}
+	if val := c.Query("time"); val != "" {
+		t, err := time.Parse(time.RFC3339, val)
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"message": fmt.Sprintf("invalid time %q, it must be in RFC3339 format", val)})
+			return
+		}
+		req.Time = t
+	}
+
+	var userCtx pgrest.Context = ctx
+	userEmail := ctx.UserEmail
+	if user := c.Query("user"); user != "" && user != ctx.UserID && user != ctx.UserEmail {
+		targetCtx, err := fetchUserContext(ctx.GetOrgID(), user)
+		if err != nil {
+			log.Errorf("failed fetching user context, reason=%v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "failed fetching user"})
+			return
+		}
+		if targetCtx == nil {
+			c.JSON(http.StatusNotFound, gin.H{"message": fmt.Sprintf("user %q not found", user)})
+			return
+		}
+		userCtx, userEmail = targetCtx, targetCtx.UserEmail
+	}
+
+	conn, err := models.GetConnectionByNameOrID(ctx.GetOrgID(), connectionName)
+	if err != nil {
+		log.Errorf("failed fetching connection, reason=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	if conn == nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "connection not found"})
+		return
+	}
+	accessControlFn, err := apiconnections.PluginAccessControlAllowed(userCtx)
+	if err != nil {
+		log.Errorf("failed validating connection access control, reason=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	policies, err := models.ListAccessPolicies(ctx.GetOrgID())
+	if err != nil {
+		log.Errorf("failed listing access policies, reason=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+
+	decision := accesspolicy.Evaluate(policies, apiconnections.NewAccessPolicyRequest(userCtx, conn, req))
+	resp := openapi.AccessPolicyEvaluation{
+		Allowed:              decision.Allowed,
+		Reason:               decision.Reason,
+		User:                 userEmail,
+		Connection:           conn.Name,
+		Verb:                 req.Verb,
+		ClientOrigin:         req.Origin,
+		SourceIP:             req.SourceIP,
+		Time:                 req.Time,
+		AccessControlAllowed: accessControlFn(conn.Name),
+		PoliciesAllowed:      decision.Allowed,
+		Policies:             []openapi.AccessPolicyResult{},
+	}
+	if !resp.AccessControlAllowed {
+		resp.Allowed = false
+		resp.Reason = "the groups of the user are not allowed to access the connection by the access control plugin"
+	}
+	for _, r := range decision.Results {
+		resp.Policies = append(resp.Policies, openapi.AccessPolicyResult{
+			ID:         r.Policy.ID,
+			Name:       r.Policy.Name,
+			Effect:     openapi.AccessPolicyEffect(r.Policy.Effect),
+			Applicable: r.Applicable,
+			Matched:    r.Matched,
+			Reason:     r.Reason,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// fetchUserContext returns the context of a user by its email or subject,
+// it returns an empty value if the user is not found in the organization
+func fetchUserContext(orgID, emailOrSubject string) (*pguserauth.Context, error) {
+	user, err := models.GetUserByEmailAndOrg(emailOrSubject, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		user, err = models.GetUserBySubjectAndOrg(emailOrSubject, orgID)
+		if err != nil || user == nil {
+			return nil, err
+		}
+	}
+	userCtx, err := pguserauth.New().FetchUserContext(user.Subject)
+	if err != nil || userCtx.IsEmpty() || userCtx.OrgID != orgID {
+		return nil, err
+	}
+	return userCtx, nil
+}
+
+func parseRequestPayload(c *gin.Context) *models.AccessPolicy {
+	req := openapi.AccessPolicyRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return nil
+	}
+	policy := &models.AccessPolicy{
+		Name:            req.Name,
+		Description:     sql.NullString{String: req.Description, Valid: req.Description != ""},
+		Effect:          string(req.Effect),
+		Groups:          toNonNil(req.Groups),
+		ConnectionTags:  req.ConnectionTags,
+		ConnectionTypes: toNonNil(req.ConnectionTypes),
+		Verbs:           toNonNil(req.Verbs),
+		ClientOrigins:   toNonNil(req.ClientOrigins),
+		SourceIPs:       toNonNil(req.SourceIPs),
+	}
+	if w := req.TimeWindow; w != nil {
+		policy.TimeWindow = &models.AccessPolicyTimeWindow{
+			Days:      toNonNil(w.Days),
+			StartTime: w.StartTime,
+			EndTime:   w.EndTime,
+			Timezone:  w.Timezone,
+		}
+	}
+	if err := accesspolicy.ValidatePolicy(policy); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+		return nil
+	}
+	return policy
+}
+
+func toNonNil(items []string) []string {
+	if items == nil {
+		return []string{}
+	}
+	return items
+}
+
+func toOpenApi(p *models.AccessPolicy) *openapi.AccessPolicy {
+	policy := &openapi.AccessPolicy{
+		ID:              p.ID,
+		Name:            p.Name,
+		Description:     p.Description.String,
+		Effect:          openapi.AccessPolicyEffect(p.Effect),
+		Groups:          toNonNil(p.Groups),
+		ConnectionTags:  p.ConnectionTags,
+		ConnectionTypes: toNonNil(p.ConnectionTypes),
+		Verbs:           toNonNil(p.Verbs),
+		ClientOrigins:   toNonNil(p.ClientOrigins),
+		SourceIPs:       toNonNil(p.SourceIPs),
+		CreatedAt:       p.CreatedAt,
+		UpdatedAt:       p.UpdatedAt,
+	}
+	if policy.ConnectionTags == nil {
+		policy.ConnectionTags = map[string]string{}
+	}
+	if w := p.TimeWindow; w != nil {
+		policy.TimeWindow = &openapi.AccessPolicyTimeWindow{
+			Days:      toNonNil(w.Days),
+			StartTime: w.StartTime,
+			EndTime:   w.EndTime,
+			Timezone:  w.Timezone,
+		}
+	}
+	return policy
+}
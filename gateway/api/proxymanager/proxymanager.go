@@ -8,7 +8,9 @@ import (
 	"github.com/getsentry/sentry-go"
 	"github.com/gin-gonic/gin"
 	"github.com/hoophq/hoop/common/log"
+	pb "github.com/hoophq/hoop/common/proto"
 	pbclient "github.com/hoophq/hoop/common/proto/client"
+	"github.com/hoophq/hoop/gateway/accesspolicy"
 	apiconnections "github.com/hoophq/hoop/gateway/api/connections"
 	"github.com/hoophq/hoop/gateway/api/openapi"
 	pgproxymanager "github.com/hoophq/hoop/gateway/pgrest/proxymanager"
@@ -44,7 +46,7 @@ func Get(c *gin.Context) {
 		return
 	}
 
-	conn, err := apiconnections.FetchByName(ctx, obj.RequestConnectionName)
+	conn, err := apiconnections.FetchByName(ctx, obj.RequestConnectionName, accesspolicy.Request{SourceIP: c.ClientIP()})
 	if err != nil {
 		log.Errorf("failed retrieving connection %v, reason=%v", obj.RequestConnectionName, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "internal error, failed to obtaining connection"})
@@ -95,7 +97,11 @@ func Post(c *gin.Context) {
 		return
 	}
 
-	conn, err := apiconnections.FetchByName(ctx, req.ConnectionName)
+	conn, err := apiconnections.FetchByName(ctx, req.ConnectionName, accesspolicy.Request{
+		Verb:     pb.ClientVerbConnect,
+		Origin:   pb.ConnectionOriginClientProxyManager,
+		SourceIP: c.ClientIP(),
+	})
 	if err != nil {
 		log.Errorf("failed retrieving connection %v, reason=%v", req.ConnectionName, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "internal error, failed to obtaining connection"})
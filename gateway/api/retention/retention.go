@@ -0,0 +1,246 @@
+package apiretention
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hoophq/hoop/common/log"
+	"github.com/hoophq/hoop/gateway/api/openapi"
+	"github.com/hoophq/hoop/gateway/models"
+	"github.com/hoophq/hoop/gateway/retention"
+	"github.com/hoophq/hoop/gateway/storagev2"
+)
+
+const (
+	defaultPurgeListLimit = 50
+	maxPurgeListLimit     = 100
+)
+
+// CreateRetentionPolicy
+//
+//	@Summary		Create Retention Policy
+//	@Description	Create a policy to purge the content of sessions after a number of days.
+//	@Description	The policies are enforced periodically by the gateway.
+//	@Tags			Retention Policies
+//	@Accept			json
+//	@Produce		json
+//	@Param			request			body		openapi.RetentionPolicyRequest	true	"The request body resource"
+//	@Success		201				{object}	openapi.RetentionPolicy
+//	@Failure		400,409,422,500	{object}	openapi.HTTPError
+//	@Router			/retention-policies [post]
+func Post(c *gin.Context) {
+	ctx := storagev2.ParseContext(c)
+	req := parseRequestPayload(c)
+	if req == nil {
+		return
+	}
+	policy := &models.RetentionPolicy{
+		ID:             uuid.NewString(),
+		OrgID:          ctx.GetOrgID(),
+		ConnectionName: sql.NullString{String: req.ConnectionName, Valid: req.ConnectionName != ""},
+		Mode:           string(req.Mode),
+		RetentionDays:  req.RetentionDays,
+		CreatedAt:      time.Now().UTC(),
+		UpdatedAt:      time.Now().UTC(),
+	}
+	err := models.CreateRetentionPolicy(policy)
+	switch err {
+	case models.ErrAlreadyExists:
+		c.JSON(http.StatusConflict, gin.H{"message": "a policy with this mode already exists for the connection"})
+	case nil:
+		c.JSON(http.StatusCreated, toOpenApi(policy))
+	default:
+		log.Errorf("failed creating retention policy, reason=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	}
+}
+
+// UpdateRetentionPolicy
+//
+//	@Summary		Update Retention Policy
+//	@Description	Update a Retention Policy
+//	@Tags			Retention Policies
+//	@Accept			json
+//	@Produce		json
+//	@Param			id					path		string							true	"The unique identifier of the resource"
+//	@Param			request				body		openapi.RetentionPolicyRequest	true	"The request body resource"
+//	@Success		200					{object}	openapi.RetentionPolicy
+//	@Failure		400,404,409,422,500	{object}	openapi.HTTPError
+//	@Router			/retention-policies/{id} [put]
+func Put(c *gin.Context) {
+	ctx := storagev2.ParseContext(c)
+	req := parseRequestPayload(c)
+	if req == nil {
+		return
+	}
+	policy := &models.RetentionPolicy{
+		ID:             c.Param("id"),
+		OrgID:          ctx.GetOrgID(),
+		ConnectionName: sql.NullString{String: req.ConnectionName, Valid: req.ConnectionName != ""},
+		Mode:           string(req.Mode),
+		RetentionDays:  req.RetentionDays,
+		UpdatedAt:      time.Now().UTC(),
+	}
+	err := models.UpdateRetentionPolicy(policy)
+	switch err {
+	case models.ErrNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"message": "resource not found"})
+	case models.ErrAlreadyExists:
+		c.JSON(http.StatusConflict, gin.H{"message": "a policy with this mode already exists for the connection"})
+	case nil:
+		c.JSON(http.StatusOK, toOpenApi(policy))
+	default:
+		log.Errorf("failed updating retention policy, reason=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	}
+}
+
+// ListRetentionPolicies
+//
+//	@Summary		List Retention Policies
+//	@Description	List the retention policies of the organization
+//	@Tags			Retention Policies
+//	@Produce		json
+//	@Success		200	{array}		openapi.RetentionPolicy
+//	@Failure		500	{object}	openapi.HTTPError
+//	@Router			/retention-policies [get]
+func List(c *gin.Context) {
+	ctx := storagev2.ParseContext(c)
+	items, err := models.ListRetentionPolicies(ctx.GetOrgID())
+	if err != nil {
+		log.Errorf("failed listing retention policies, reason=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	policies := []openapi.RetentionPolicy{}
+	for _, p := range items {
+		policies = append(policies, *toOpenApi(&p))
+	}
+	c.JSON(http.StatusOK, policies)
+}
+
+// GetRetentionPolicy
+//
+//	@Summary		Get Retention Policy
+//	@Description	Get a Retention Policy
+//	@Tags			Retention Policies
+//	@Produce		json
+//	@Param			id		path		string	true	"The unique identifier of the resource"
+//	@Success		200		{object}	openapi.RetentionPolicy
+//	@Failure		404,500	{object}	openapi.HTTPError
+//	@Router			/retention-policies/{id} [get]
+func Get(c *gin.Context) {
+	ctx := storagev2.ParseContext(c)
+	policy, err := models.GetRetentionPolicy(ctx.GetOrgID(), c.Param("id"))
+	switch err {
+	case models.ErrNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"message": "resource not found"})
+	case nil:
+		c.JSON(http.StatusOK, toOpenApi(policy))
+	default:
+		log.Errorf("failed fetching retention policy, reason=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	}
+}
+
+// DeleteRetentionPolicy
+//
+//	@Summary		Delete Retention Policy
+//	@Description	Delete a Retention Policy, the sessions already purged are not affected
+//	@Tags			Retention Policies
+//	@Produce		json
+//	@Param			id	path	string	true	"The unique identifier of the resource"
+//	@Success		204
+//	@Failure		404,500	{object}	openapi.HTTPError
+//	@Router			/retention-policies/{id} [delete]
+func Delete(c *gin.Context) {
+	ctx := storagev2.ParseContext(c)
+	err := models.DeleteRetentionPolicy(ctx.GetOrgID(), c.Param("id"))
+	switch err {
+	case models.ErrNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"message": "resource not found"})
+	case nil:
+		c.Writer.WriteHeader(http.StatusNoContent)
+	default:
+		log.Errorf("failed removing retention policy, reason=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	}
+}
+
+// ListRetentionPurges
+//
+//	@Summary		List Retention Purges
+//	@Description	List the sessions purged by retention policies, the most recent first
+//	@Tags			Retention Policies
+//	@Produce		json
+//	@Param			connection	query		string	false	"Filter by connection's name"
+//	@Param			limit		query		int		false	"Limit the amount of records to return (max: 100)"
+//	@Param			offset		query		int		false	"Offset to paginate through resources"
+//	@Success		200			{object}	openapi.RetentionPurgeList
+//	@Failure		500			{object}	openapi.HTTPError
+//	@Router			/retention-purges [get]
+func ListPurges(c *gin.Context) {
+	ctx := storagev2.ParseContext(c)
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	offset, _ := strconv.Atoi(c.Query("offset"))
+	if limit <= 0 {
+		limit = defaultPurgeListLimit
+	}
+	if limit > maxPurgeListLimit {
+		limit = maxPurgeListLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	purgeList, err := models.ListRetentionPurges(ctx.GetOrgID(), c.Query("connection"), limit, offset)
+	if err != nil {
+		log.Errorf("failed listing retention purges, reason=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	resp := openapi.RetentionPurgeList{
+		Items:       []openapi.RetentionPurge{},
+		Total:       purgeList.Total,
+		HasNextPage: purgeList.HasNextPage,
+	}
+	for _, p := range purgeList.Items {
+		resp.Items = append(resp.Items, openapi.RetentionPurge{
+			ID:               p.ID,
+			PolicyID:         p.PolicyID.String,
+			SessionID:        p.SessionID,
+			Connection:       p.Connection,
+			Mode:             openapi.RetentionMode(p.Mode),
+			SessionCreatedAt: p.SessionCreatedAt,
+			PurgedAt:         p.PurgedAt,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func parseRequestPayload(c *gin.Context) *openapi.RetentionPolicyRequest {
+	req := openapi.RetentionPolicyRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return nil
+	}
+	if err := retention.ValidatePolicy(string(req.Mode), req.RetentionDays); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+		return nil
+	}
+	return &req
+}
+
+func toOpenApi(p *models.RetentionPolicy) *openapi.RetentionPolicy {
+	return &openapi.RetentionPolicy{
+		ID:             p.ID,
+		ConnectionName: p.ConnectionName.String,
+		Mode:           openapi.RetentionMode(p.Mode),
+		RetentionDays:  p.RetentionDays,
+		CreatedAt:      p.CreatedAt,
+		UpdatedAt:      p.UpdatedAt,
+	}
+}
@@ -1,15 +1,20 @@
 package reviewapi
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/getsentry/sentry-go"
 	"github.com/gin-gonic/gin"
 	"github.com/hoophq/hoop/common/log"
+	"github.com/hoophq/hoop/gateway/api/openapi"
+	"github.com/hoophq/hoop/gateway/models"
 	"github.com/hoophq/hoop/gateway/pgrest"
 	pgreview "github.com/hoophq/hoop/gateway/pgrest/review"
 	"github.com/hoophq/hoop/gateway/review"
 	"github.com/hoophq/hoop/gateway/storagev2"
+	"github.com/hoophq/hoop/gateway/storagev2/types"
 )
 
 type handler struct {
@@ -88,3 +93,144 @@ func (h *handler) Put(c *gin.Context) { h.legacy.Put(c) }
 //	@Failure		404,500	{object}	openapi.HTTPError
 //	@Router			/sessions/{session_id}/review [put]
 func (h *handler) ReviewBySession(c *gin.Context) { h.legacy.Put(c) }
+
+// RequestReviewExtension
+//
+//	@Summary		Request Review Extension
+//	@Description	Request additional access time for the active jit review of the user in a connection.
+//	@Description	The extension is reviewed by the same groups of the review, when approved the revoke time of the review is pushed out without dropping the connected sessions.
+//	@Tags			Reviews
+//	@Param			nameOrID	path	string	true	"Name or UUID of the connection"
+//	@Accept			json
+//	@Produce		json
+//	@Param			request			body		openapi.ReviewExtensionRequest	true	"The request body resource"
+//	@Success		201				{object}	openapi.Review
+//	@Failure		400,404,409,500	{object}	openapi.HTTPError
+//	@Router			/connections/{nameOrID}/jit/extension [post]
+func (h *handler) RequestExtension(c *gin.Context) {
+	ctx := storagev2.ParseContext(c)
+	var req openapi.ReviewExtensionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	conn, err := models.GetConnectionByNameOrID(ctx.OrgID, c.Param("nameOrID"))
+	if err != nil {
+		log.Errorf("failed fetching connection, err=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "failed fetching connection"})
+		return
+	}
+	if conn == nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "connection not found"})
+		return
+	}
+	duration := time.Duration(req.AccessDurationSec) * time.Second
+	rev, err := h.legacy.Service.RequestExtension(ctx, conn.ID, duration)
+	switch err {
+	case review.ErrNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"message": "active jit review not found for this connection"})
+	case review.ErrInvalidExtension:
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+	case review.ErrExtensionPending:
+		c.JSON(http.StatusConflict, gin.H{"message": err.Error()})
+	case nil:
+		c.JSON(http.StatusCreated, pgreview.ToJson(*rev))
+	default:
+		log.Errorf("failed requesting review extension, err=%v", err)
+		sentry.CaptureException(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	}
+}
+
+// UpdateReviewExtension
+//
+//	@Summary		Update Review Extension Status
+//	@Description	Approve or reject the pending extension of a jit review
+//	@Tags			Reviews
+//	@Param			id	path	string	true	"Resource identifier of the review"
+//	@Accept			json
+//	@Produce		json
+//	@Param			request			body		openapi.ReviewRequest	true	"The request body resource"
+//	@Success		200				{object}	openapi.Review
+//	@Failure		400,403,404,500	{object}	openapi.HTTPError
+//	@Router			/reviews/{id}/extension [put]
+func (h *handler) PutExtension(c *gin.Context) {
+	ctx := storagev2.ParseContext(c)
+	var req openapi.ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	status := types.ReviewStatus(req.Status)
+	switch status {
+	case types.ReviewStatusApproved, types.ReviewStatusRejected:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid status, accepted values are: APPROVED, REJECTED"})
+		return
+	}
+	rev, err := h.legacy.Service.ReviewExtension(ctx, c.Param("id"), status)
+	switch err {
+	case review.ErrNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"message": "review extension not found"})
+	case review.ErrSelfApproval:
+		c.JSON(http.StatusForbidden, gin.H{"message": err.Error()})
+	case review.ErrNotEligible, review.ErrWrongState, review.ErrAlreadyReviewed:
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+	case nil:
+		c.JSON(http.StatusOK, pgreview.ToJson(*rev))
+	default:
+		log.Errorf("failed reviewing extension, err=%v", err)
+		sentry.CaptureException(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	}
+}
+
+// CreateScheduledReview
+//
+//	@Summary		Create Scheduled Review
+//	@Description	Request access to a connection for a future time window. The groups of the connection review the request in advance,
+//	@Description	when approved the sessions of the user are admitted only inside the window without a review per session.
+//	@Tags			Reviews
+//	@Accept			json
+//	@Produce		json
+//	@Param			request		body		openapi.ScheduledReviewRequest	true	"The request body resource"
+//	@Success		201			{object}	openapi.Review
+//	@Failure		400,404,500	{object}	openapi.HTTPError
+//	@Router			/reviews [post]
+func (h *handler) CreateScheduled(c *gin.Context) {
+	ctx := storagev2.ParseContext(c)
+	var req openapi.ScheduledReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	conn, err := models.GetConnectionByNameOrID(ctx.OrgID, req.Connection)
+	if err != nil {
+		log.Errorf("failed fetching connection, err=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "failed fetching connection"})
+		return
+	}
+	if conn == nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "connection not found"})
+		return
+	}
+	rev, err := h.legacy.Service.CreateScheduled(ctx, review.ScheduledAccess{
+		Connection:   types.ReviewConnection{Id: conn.ID, Name: conn.Name},
+		Reviewers:    conn.Reviewers,
+		Policy:       conn.ReviewPolicy,
+		StartAt:      req.StartAt,
+		Duration:     time.Duration(req.AccessDurationSec) * time.Second,
+		Reason:       req.Reason,
+		JiraIssueKey: req.JiraIssueKey,
+	})
+	switch {
+	case errors.Is(err, review.ErrInvalidSchedule):
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+	case err != nil:
+		log.Errorf("failed creating scheduled review, err=%v", err)
+		sentry.CaptureException(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	default:
+		c.JSON(http.StatusCreated, pgreview.ToJson(*rev))
+	}
+}
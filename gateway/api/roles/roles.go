@@ -0,0 +1,229 @@
+package apiroles
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hoophq/hoop/common/log"
+	"github.com/hoophq/hoop/gateway/api/openapi"
+	"github.com/hoophq/hoop/gateway/models"
+	"github.com/hoophq/hoop/gateway/storagev2"
+)
+
+// CreateRole
+//
+//	@Summary		Create Role
+//	@Description	Create a custom role that grants permissions to the users of its groups.
+//	@Description	The permissions are granted on top of the access provided by the default groups.
+//	@Tags			Roles
+//	@Accept			json
+//	@Produce		json
+//	@Param			request			body		openapi.RoleRequest	true	"The request body resource"
+//	@Success		201				{object}	openapi.Role
+//	@Failure		400,409,422,500	{object}	openapi.HTTPError
+//	@Router			/roles [post]
+func Post(c *gin.Context) {
+	ctx := storagev2.ParseContext(c)
+	req := parseRequestPayload(c)
+	if req == nil {
+		return
+	}
+	role := &models.Role{
+		ID:          uuid.NewString(),
+		OrgID:       ctx.GetOrgID(),
+		Name:        req.Name,
+		Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
+		Permissions: toPermissions(req.Permissions),
+		Groups:      req.Groups,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+	err := models.CreateRole(role)
+	switch err {
+	case models.ErrAlreadyExists:
+		c.JSON(http.StatusConflict, gin.H{"message": fmt.Sprintf("a role named %q already exists", req.Name)})
+	case nil:
+		c.JSON(http.StatusCreated, toOpenApi(role))
+	default:
+		log.Errorf("failed creating role, reason=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	}
+}
+
+// UpdateRole
+//
+//	@Summary		Update Role
+//	@Description	Update a Role, the changes are applied on the next request of the users
+//	@Tags			Roles
+//	@Accept			json
+//	@Produce		json
+//	@Param			id					path		string				true	"The unique identifier of the resource"
+//	@Param			request				body		openapi.RoleRequest	true	"The request body resource"
+//	@Success		200					{object}	openapi.Role
+//	@Failure		400,404,409,422,500	{object}	openapi.HTTPError
+//	@Router			/roles/{id} [put]
+func Put(c *gin.Context) {
+	ctx := storagev2.ParseContext(c)
+	req := parseRequestPayload(c)
+	if req == nil {
+		return
+	}
+	role := &models.Role{
+		ID:          c.Param("id"),
+		OrgID:       ctx.GetOrgID(),
+		Name:        req.Name,
+		Description: sql.NullString{String: req.Description, Valid: req.Description != ""},
+		Permissions: toPermissions(req.Permissions),
+		Groups:      req.Groups,
+		UpdatedAt:   time.Now().UTC(),
+	}
+	err := models.UpdateRole(role)
+	switch err {
+	case models.ErrNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"message": "resource not found"})
+	case models.ErrAlreadyExists:
+		c.JSON(http.StatusConflict, gin.H{"message": fmt.Sprintf("a role named %q already exists", req.Name)})
+	case nil:
+		c.JSON(http.StatusOK, toOpenApi(role))
+	default:
+		log.Errorf("failed updating role, reason=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	}
+}
+
+// ListRoles
+//
+//	@Summary		List Roles
+//	@Description	List the custom roles of the organization
+//	@Tags			Roles
+//	@Produce		json
+//	@Success		200	{array}		openapi.Role
+//	@Failure		500	{object}	openapi.HTTPError
+//	@Router			/roles [get]
+func List(c *gin.Context) {
+	ctx := storagev2.ParseContext(c)
+	items, err := models.ListRoles(ctx.GetOrgID())
+	if err != nil {
+		log.Errorf("failed listing roles, reason=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	roles := []openapi.Role{}
+	for _, r := range items {
+		roles = append(roles, *toOpenApi(&r))
+	}
+	c.JSON(http.StatusOK, roles)
+}
+
+// GetRole
+//
+//	@Summary		Get Role
+//	@Description	Get a Role
+//	@Tags			Roles
+//	@Produce		json
+//	@Param			id		path		string	true	"The unique identifier of the resource"
+//	@Success		200		{object}	openapi.Role
+//	@Failure		404,500	{object}	openapi.HTTPError
+//	@Router			/roles/{id} [get]
+func Get(c *gin.Context) {
+	ctx := storagev2.ParseContext(c)
+	role, err := models.GetRole(ctx.GetOrgID(), c.Param("id"))
+	switch err {
+	case models.ErrNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"message": "resource not found"})
+	case nil:
+		c.JSON(http.StatusOK, toOpenApi(role))
+	default:
+		log.Errorf("failed fetching role, reason=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	}
+}
+
+// DeleteRole
+//
+//	@Summary		Delete Role
+//	@Description	Delete a Role, the users of its groups lose the permissions granted by it
+//	@Tags			Roles
+//	@Produce		json
+//	@Param			id	path	string	true	"The unique identifier of the resource"
+//	@Success		204
+//	@Failure		404,500	{object}	openapi.HTTPError
+//	@Router			/roles/{id} [delete]
+func Delete(c *gin.Context) {
+	ctx := storagev2.ParseContext(c)
+	err := models.DeleteRole(ctx.GetOrgID(), c.Param("id"))
+	switch err {
+	case models.ErrNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"message": "resource not found"})
+	case nil:
+		c.Writer.WriteHeader(http.StatusNoContent)
+	default:
+		log.Errorf("failed removing role, reason=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	}
+}
+
+func parseRequestPayload(c *gin.Context) *openapi.RoleRequest {
+	req := openapi.RoleRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return nil
+	}
+	if err := validatePermissions(req.Permissions); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+		return nil
+	}
+	if req.Groups == nil {
+		req.Groups = []string{}
+	}
+	return &req
+}
+
+func validatePermissions(permissions []openapi.PermissionType) error {
+	accepted := strings.Join(toPermissions(openapi.AvailablePermissions), ", ")
+	if len(permissions) == 0 {
+		return fmt.Errorf("at least one permission is required, accepted values are: %v", accepted)
+	}
+	for i, p := range permissions {
+		if !slices.Contains(openapi.AvailablePermissions, p) {
+			return fmt.Errorf("invalid permission %q, accepted values are: %v", p, accepted)
+		}
+		if slices.Contains(permissions[:i], p) {
+			return fmt.Errorf("permission %q is repeated", p)
+		}
+	}
+	return nil
+}
+
+func toPermissions(permissions []openapi.PermissionType) []string {
+	var items []string
+	for _, p := range permissions {
+		items = append(items, string(p))
+	}
+	return items
+}
+
+func toOpenApi(r *models.Role) *openapi.Role {
+	role := &openapi.Role{
+		ID:          r.ID,
+		Name:        r.Name,
+		Description: r.Description.String,
+		Permissions: []openapi.PermissionType{},
+		Groups:      r.Groups,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+	for _, p := range r.Permissions {
+		role.Permissions = append(role.Permissions, openapi.PermissionType(p))
+	}
+	if role.Groups == nil {
+		role.Groups = []string{}
+	}
+	return role
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/hoophq/hoop/common/apiutils"
 	"github.com/hoophq/hoop/common/log"
 	"github.com/hoophq/hoop/common/proto"
+	"github.com/hoophq/hoop/gateway/accesspolicy"
 	"github.com/hoophq/hoop/gateway/api/apiroutes"
 	apiconnections "github.com/hoophq/hoop/gateway/api/connections"
 	"github.com/hoophq/hoop/gateway/api/openapi"
@@ -265,7 +266,11 @@ func RunExec(c *gin.Context) {
 }
 
 func getConnection(ctx pgrest.Context, c *gin.Context, connectionName string) (*models.Connection, error) {
-	conn, err := apiconnections.FetchByName(ctx, connectionName)
+	conn, err := apiconnections.FetchByName(ctx, connectionName, accesspolicy.Request{
+		Verb:     proto.ClientVerbExec,
+		Origin:   proto.ConnectionOriginClientAPIRunbooks,
+		SourceIP: c.ClientIP(),
+	})
 	if err != nil {
 		sentry.CaptureException(err)
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "failed retrieving connection"})
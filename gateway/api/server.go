@@ -29,15 +29,19 @@ import (
 	"github.com/hoophq/hoop/gateway/api/openapi"
 	apiorgs "github.com/hoophq/hoop/gateway/api/orgs"
 	apiplugins "github.com/hoophq/hoop/gateway/api/plugins"
+	apipolicies "github.com/hoophq/hoop/gateway/api/policies"
 	apiproxymanager "github.com/hoophq/hoop/gateway/api/proxymanager"
 	apipublicserverinfo "github.com/hoophq/hoop/gateway/api/publicserverinfo"
 	apireports "github.com/hoophq/hoop/gateway/api/reports"
+	apiretention "github.com/hoophq/hoop/gateway/api/retention"
 	reviewapi "github.com/hoophq/hoop/gateway/api/review"
+	apiroles "github.com/hoophq/hoop/gateway/api/roles"
 	apirunbooks "github.com/hoophq/hoop/gateway/api/runbooks"
 	apiserverinfo "github.com/hoophq/hoop/gateway/api/serverinfo"
 	serviceaccountapi "github.com/hoophq/hoop/gateway/api/serviceaccount"
 	sessionapi "github.com/hoophq/hoop/gateway/api/session"
 	signupapi "github.com/hoophq/hoop/gateway/api/signup"
+	apitokens "github.com/hoophq/hoop/gateway/api/tokens"
 	userapi "github.com/hoophq/hoop/gateway/api/user"
 	webhooksapi "github.com/hoophq/hoop/gateway/api/webhooks"
 	"github.com/hoophq/hoop/gateway/appconfig"
@@ -164,6 +168,7 @@ func (a *Api) StartAPI(sentryInit bool) {
 
 func (api *Api) buildRoutes(r *apiroutes.Router) {
 	reviewHandler := reviewapi.NewHandler(&api.ReviewHandler)
+	sessionapi.WithReviewService(api.ReviewHandler.Service)
 	loginHandler := loginapi.New(api.IDProvider)
 
 	r.GET("/healthz", apihealthz.LivenessHandler())
@@ -197,10 +202,12 @@ func (api *Api) buildRoutes(r *apiroutes.Router) {
 		userapi.GetUserInfo)
 	r.GET("/users",
 		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionUsersRead),
 		r.AuthMiddleware,
 		userapi.List)
 	r.GET("/users/:emailOrID",
 		apiroutes.ReadOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionUsersRead),
 		r.AuthMiddleware,
 		userapi.GetUserByEmailOrID)
 	r.PATCH("/users/self/slack",
@@ -209,44 +216,53 @@ func (api *Api) buildRoutes(r *apiroutes.Router) {
 		userapi.PatchSlackID)
 	r.GET("/users/groups",
 		apiroutes.ReadOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionUsersRead),
 		r.AuthMiddleware,
 		userapi.ListAllGroups)
 	r.POST("/users",
 		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionUsersWrite),
 		r.AuthMiddleware,
 		userapi.Create)
 	r.DELETE("/users/:id",
 		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionUsersWrite),
 		r.AuthMiddleware,
 		userapi.Delete)
 	r.PUT("/users/:id",
 		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionUsersWrite),
 		r.AuthMiddleware,
 		api.TrackRequest(analytics.EventUpdateUser),
 		userapi.Update)
 
 	r.GET("/serviceaccounts",
 		apiroutes.ReadOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionUsersRead),
 		r.AuthMiddleware,
 		serviceaccountapi.List)
 	r.POST("/serviceaccounts",
 		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionUsersWrite),
 		r.AuthMiddleware,
 		api.TrackRequest(analytics.EventCreateServiceAccount),
 		serviceaccountapi.Create)
 	r.PUT("/serviceaccounts/:subject",
 		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionUsersWrite),
 		r.AuthMiddleware,
 		api.TrackRequest(analytics.EventCreateServiceAccount),
 		serviceaccountapi.Update)
 
 	r.POST("/connections",
 		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionConnectionsWrite),
 		r.AuthMiddleware,
 		api.TrackRequest(analytics.EventCreateConnection),
 		apiconnections.Post)
 	r.PUT("/connections/:nameOrID",
 		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionConnectionsWrite),
 		r.AuthMiddleware,
 		api.TrackRequest(analytics.EventUpdateConnection),
 		apiconnections.Put)
@@ -257,13 +273,16 @@ func (api *Api) buildRoutes(r *apiroutes.Router) {
 		sessionapi.Post,
 	)
 	r.GET("/connections",
+		apiroutes.PermissionAccess(openapi.PermissionConnectionsRead),
 		r.AuthMiddleware,
 		apiconnections.List)
 	r.GET("/connections/:nameOrID",
+		apiroutes.PermissionAccess(openapi.PermissionConnectionsRead),
 		r.AuthMiddleware,
 		apiconnections.Get)
 	r.DELETE("/connections/:name",
 		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionConnectionsWrite),
 		r.AuthMiddleware,
 		api.TrackRequest(analytics.EventDeleteConnection),
 		apiconnections.Delete)
@@ -275,6 +294,10 @@ func (api *Api) buildRoutes(r *apiroutes.Router) {
 		apiroutes.ReadOnlyAccessRole,
 		r.AuthMiddleware,
 		apiconnections.GetDatabaseSchemas)
+	r.POST("/connections/:nameOrID/jit/extension",
+		r.AuthMiddleware,
+		api.TrackRequest(analytics.EventRequestReviewExtension),
+		reviewHandler.RequestExtension)
 
 	// TODO(san): needs more testing, will add these endpoints later on
 	// r.POST("/connection-tags",
@@ -294,6 +317,7 @@ func (api *Api) buildRoutes(r *apiroutes.Router) {
 	// )
 	r.GET("/connection-tags",
 		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionConnectionsRead),
 		r.AuthMiddleware,
 		apiconnections.ListTags,
 	)
@@ -311,43 +335,61 @@ func (api *Api) buildRoutes(r *apiroutes.Router) {
 		apiproxymanager.Get)
 
 	r.GET("/reviews",
+		apiroutes.PermissionAccess(openapi.PermissionReviewsRead),
 		r.AuthMiddleware,
 		api.TrackRequest(analytics.EventFetchReviews),
 		reviewHandler.List)
+	r.POST("/reviews",
+		r.AuthMiddleware,
+		api.TrackRequest(analytics.EventCreateScheduledReview),
+		reviewHandler.CreateScheduled)
 	r.GET("/reviews/:id",
+		apiroutes.PermissionAccess(openapi.PermissionReviewsRead),
 		r.AuthMiddleware,
 		api.TrackRequest(analytics.EventFetchReviews),
 		reviewHandler.Get)
 	r.PUT("/reviews/:id",
+		apiroutes.PermissionAccess(openapi.PermissionReviewsWrite),
 		r.AuthMiddleware,
 		api.TrackRequest(analytics.EventUpdateReview),
 		reviewHandler.Put)
+	r.PUT("/reviews/:id/extension",
+		apiroutes.PermissionAccess(openapi.PermissionReviewsWrite),
+		r.AuthMiddleware,
+		api.TrackRequest(analytics.EventUpdateReview),
+		reviewHandler.PutExtension)
 
 	r.POST("/agents",
 		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionAgentsWrite),
 		r.AuthMiddleware,
 		api.TrackRequest(analytics.EventCreateAgent),
 		apiagents.Post)
 	r.GET("/agents",
 		apiroutes.ReadOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionAgentsRead),
 		r.AuthMiddleware,
 		apiagents.List)
 	r.DELETE("/agents/:nameOrID",
 		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionAgentsWrite),
 		r.AuthMiddleware,
 		api.TrackRequest(analytics.EventDeleteAgent),
 		apiagents.Delete)
 
 	r.POST("/orgs/keys",
 		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionAgentsWrite),
 		r.AuthMiddleware,
 		apiorgs.CreateAgentKey)
 	r.GET("/orgs/keys",
 		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionAgentsRead),
 		r.AuthMiddleware,
 		apiorgs.GetAgentKey)
 	r.DELETE("/orgs/keys",
 		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionAgentsWrite),
 		r.AuthMiddleware,
 		apiorgs.RevokeAgentKey)
 
@@ -373,47 +415,66 @@ func (api *Api) buildRoutes(r *apiroutes.Router) {
 
 	r.POST("/plugins",
 		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionPluginsWrite),
 		r.AuthMiddleware,
 		api.TrackRequest(analytics.EventCreatePlugin),
 		apiplugins.Post)
 	r.PUT("/plugins/:name",
 		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionPluginsWrite),
 		r.AuthMiddleware,
 		api.TrackRequest(analytics.EventUpdatePlugin),
 		apiplugins.Put)
 	r.PUT("/plugins/:name/config",
 		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionPluginsWrite),
 		r.AuthMiddleware,
 		api.TrackRequest(analytics.EventUpdatePluginConfig),
 		apiplugins.PutConfig)
 	r.GET("/plugins",
 		apiroutes.ReadOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionPluginsRead),
 		r.AuthMiddleware,
 		apiplugins.List)
 	r.GET("/plugins/:name",
 		apiroutes.ReadOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionPluginsRead),
 		r.AuthMiddleware,
 		apiplugins.Get)
 
 	// alias routes
 	r.GET("/plugins/audit/sessions/:session_id",
 		apiroutes.ReadOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionSessionsRead),
 		r.AuthMiddleware,
 		sessionapi.Get)
 	r.GET("/plugins/audit/sessions",
 		apiroutes.ReadOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionSessionsRead),
 		r.AuthMiddleware,
 		sessionapi.List)
 
 	r.GET("/sessions/:session_id",
 		apiroutes.ReadOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionSessionsRead),
 		r.AuthMiddleware,
 		sessionapi.Get)
 	r.GET("/sessions/:session_id/download", sessionapi.DownloadSession)
+	r.GET("/sessions/:session_id/playback",
+		apiroutes.ReadOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionSessionsRead),
+		r.AuthMiddleware,
+		sessionapi.Playback)
+	r.GET("/sessions/:session_id/verify",
+		apiroutes.AuditorAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionSessionsRead),
+		r.AuthMiddleware,
+		sessionapi.Verify)
 	r.POST("/sessions/:session_id/kill",
 		r.AuthMiddleware,
 		sessionapi.Kill)
 	r.PUT("/sessions/:session_id/review",
+		apiroutes.PermissionAccess(openapi.PermissionReviewsWrite),
 		r.AuthMiddleware,
 		reviewHandler.ReviewBySession)
 	r.PATCH("/sessions/:session_id/metadata",
@@ -421,6 +482,7 @@ func (api *Api) buildRoutes(r *apiroutes.Router) {
 		sessionapi.PatchMetadata)
 	r.GET("/sessions",
 		apiroutes.ReadOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionSessionsRead),
 		r.AuthMiddleware,
 		sessionapi.List)
 	r.POST("/sessions",
@@ -434,6 +496,7 @@ func (api *Api) buildRoutes(r *apiroutes.Router) {
 
 	r.GET("/reports/sessions",
 		apiroutes.ReadOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionSessionsRead),
 		r.AuthMiddleware,
 		apireports.SessionReport)
 
@@ -559,25 +622,143 @@ func (api *Api) buildRoutes(r *apiroutes.Router) {
 
 	r.POST("/guardrails",
 		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionGuardRailsWrite),
 		r.AuthMiddleware,
 		api.TrackRequest(analytics.EventCreateGuardRailRules),
 		apiguardrails.Post)
+	r.POST("/guardrails/test",
+		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionGuardRailsWrite),
+		r.AuthMiddleware,
+		apiguardrails.Test)
 	r.PUT("/guardrails/:id",
 		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionGuardRailsWrite),
 		r.AuthMiddleware,
 		api.TrackRequest(analytics.EventUpdateGuardRailRules),
 		apiguardrails.Put)
 	r.GET("/guardrails",
 		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionGuardRailsRead),
 		r.AuthMiddleware,
 		apiguardrails.List)
 	r.GET("/guardrails/:id",
 		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionGuardRailsRead),
 		r.AuthMiddleware,
 		apiguardrails.Get)
 	r.DELETE("/guardrails/:id",
 		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionGuardRailsWrite),
 		r.AuthMiddleware,
 		api.TrackRequest(analytics.EventDeleteGuardRailRules),
 		apiguardrails.Delete)
+
+	r.POST("/retention-policies",
+		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionRetentionWrite),
+		r.AuthMiddleware,
+		api.TrackRequest(analytics.EventCreateRetentionPolicy),
+		apiretention.Post)
+	r.PUT("/retention-policies/:id",
+		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionRetentionWrite),
+		r.AuthMiddleware,
+		api.TrackRequest(analytics.EventUpdateRetentionPolicy),
+		apiretention.Put)
+	r.GET("/retention-policies",
+		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionRetentionRead),
+		r.AuthMiddleware,
+		apiretention.List)
+	r.GET("/retention-policies/:id",
+		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionRetentionRead),
+		r.AuthMiddleware,
+		apiretention.Get)
+	r.DELETE("/retention-policies/:id",
+		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionRetentionWrite),
+		r.AuthMiddleware,
+		api.TrackRequest(analytics.EventDeleteRetentionPolicy),
+		apiretention.Delete)
+	r.GET("/retention-purges",
+		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionRetentionRead),
+		r.AuthMiddleware,
+		apiretention.ListPurges)
+
+	// any user manages its own tokens, only admin users manage the tokens of other users
+	r.POST("/tokens",
+		apiroutes.ReadOnlyAccessRole,
+		r.AuthMiddleware,
+		api.TrackRequest(analytics.EventCreateAPIToken),
+		apitokens.Post)
+	r.GET("/tokens",
+		apiroutes.ReadOnlyAccessRole,
+		r.AuthMiddleware,
+		apitokens.List)
+	r.DELETE("/tokens/:id",
+		apiroutes.ReadOnlyAccessRole,
+		r.AuthMiddleware,
+		api.TrackRequest(analytics.EventRevokeAPIToken),
+		apitokens.Delete)
+
+	r.POST("/roles",
+		apiroutes.AdminOnlyAccessRole,
+		r.AuthMiddleware,
+		api.TrackRequest(analytics.EventCreateRole),
+		apiroles.Post)
+	r.PUT("/roles/:id",
+		apiroutes.AdminOnlyAccessRole,
+		r.AuthMiddleware,
+		api.TrackRequest(analytics.EventUpdateRole),
+		apiroles.Put)
+	r.GET("/roles",
+		apiroutes.AdminOnlyAccessRole,
+		r.AuthMiddleware,
+		apiroles.List)
+	r.GET("/roles/:id",
+		apiroutes.AdminOnlyAccessRole,
+		r.AuthMiddleware,
+		apiroles.Get)
+	r.DELETE("/roles/:id",
+		apiroutes.AdminOnlyAccessRole,
+		r.AuthMiddleware,
+		api.TrackRequest(analytics.EventDeleteRole),
+		apiroles.Delete)
+
+	r.POST("/policies",
+		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionPoliciesWrite),
+		r.AuthMiddleware,
+		api.TrackRequest(analytics.EventCreateAccessPolicy),
+		apipolicies.Post)
+	r.PUT("/policies/:id",
+		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionPoliciesWrite),
+		r.AuthMiddleware,
+		api.TrackRequest(analytics.EventUpdateAccessPolicy),
+		apipolicies.Put)
+	r.GET("/policies",
+		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionPoliciesRead),
+		r.AuthMiddleware,
+		apipolicies.List)
+	r.GET("/policies/evaluate",
+		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionPoliciesRead),
+		r.AuthMiddleware,
+		apipolicies.Evaluate)
+	r.GET("/policies/:id",
+		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionPoliciesRead),
+		r.AuthMiddleware,
+		apipolicies.Get)
+	r.DELETE("/policies/:id",
+		apiroutes.AdminOnlyAccessRole,
+		apiroutes.PermissionAccess(openapi.PermissionPoliciesWrite),
+		r.AuthMiddleware,
+		api.TrackRequest(analytics.EventDeleteAccessPolicy),
+		apipolicies.Delete)
 }
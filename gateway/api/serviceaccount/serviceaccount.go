@@ -1,6 +1,7 @@
 package serviceaccountapi
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/hoophq/hoop/common/log"
+	"github.com/hoophq/hoop/gateway/api/apiroutes"
 	"github.com/hoophq/hoop/gateway/api/openapi"
 	pgserviceaccounts "github.com/hoophq/hoop/gateway/pgrest/serviceaccounts"
 	"github.com/hoophq/hoop/gateway/storagev2"
@@ -72,6 +74,17 @@ func Create(c *gin.Context) {
 		c.JSON(http.StatusConflict, gin.H{"message": "service account already exists"})
 		return
 	}
+	err = apiroutes.ValidateGroupChange(ctx, nil, req.Groups)
+	switch {
+	case errors.Is(err, apiroutes.ErrForbiddenGroupChange):
+		c.JSON(http.StatusForbidden, gin.H{"message": err.Error()})
+		return
+	case err != nil:
+		log.Errorf("failed validating group change, reason=%v", err)
+		sentry.CaptureException(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "failed validating group change"})
+		return
+	}
 
 	obj := &openapi.ServiceAccount{
 		ID:      objID,
@@ -125,6 +138,17 @@ func Update(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"message": "service account not found"})
 		return
 	}
+	err = apiroutes.ValidateGroupChange(ctx, svcAccount.Groups, req.Groups)
+	switch {
+	case errors.Is(err, apiroutes.ErrForbiddenGroupChange):
+		c.JSON(http.StatusForbidden, gin.H{"message": err.Error()})
+		return
+	case err != nil:
+		log.Errorf("failed validating group change, reason=%v", err)
+		sentry.CaptureException(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "failed validating group change"})
+		return
+	}
 
 	svcAccount.Name = req.Name
 	svcAccount.Status = req.Status
@@ -40,6 +40,8 @@ func toOpenApiSession(s *models.Session) *openapi.Session {
 		Verb:                 s.Verb,
 		Status:               openapi.SessionStatusType(s.Status),
 		ExitCode:             s.ExitCode,
+		GuardRailsInfo:       toOpenApiGuardRailsInfo(s.GuardRailsInfo),
+		BreakGlass:           s.BreakGlass,
 		EventStream:          s.BlobStream,
 		EventSize:            s.BlobStreamSize,
 		StartSession:         s.CreatedAt,
@@ -47,6 +49,37 @@ func toOpenApiSession(s *models.Session) *openapi.Session {
 	}
 }
 
+func toOpenApiGuardRailsInfo(info []models.SessionGuardRailsInfo) []openapi.SessionGuardRailsInfo {
+	items := []openapi.SessionGuardRailsInfo{}
+	for _, i := range info {
+		items = append(items, openapi.SessionGuardRailsInfo{
+			Direction: i.Direction,
+			RuleType:  i.RuleType,
+			Action:    i.Action,
+			Message:   i.Message,
+			CreatedAt: i.CreatedAt,
+		})
+	}
+	return items
+}
+
+func toOpenApiSessionStatements(statements []models.SessionStatement) []openapi.SessionStatement {
+	items := []openapi.SessionStatement{}
+	for _, stmt := range statements {
+		items = append(items, openapi.SessionStatement{
+			ConnectionID: stmt.ConnectionID,
+			Statement:    stmt.Statement,
+			Rows:         stmt.Rows,
+			ErrorCode:    stmt.ErrorCode,
+			ErrorMessage: stmt.ErrorMessage,
+			DurationMs:   stmt.EndedAt.Sub(stmt.StartedAt).Milliseconds(),
+			StartDate:    stmt.StartedAt,
+			EndDate:      stmt.EndedAt,
+		})
+	}
+	return items
+}
+
 func toOpenApiSessionList(s *models.SessionList) *openapi.SessionList {
 	newObj := &openapi.SessionList{
 		Total:       s.Total,
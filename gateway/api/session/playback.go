@@ -0,0 +1,74 @@
+package sessionapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/gin-gonic/gin"
+	"github.com/hoophq/hoop/common/log"
+	"github.com/hoophq/hoop/gateway/api/apiroutes"
+	"github.com/hoophq/hoop/gateway/appconfig"
+	"github.com/hoophq/hoop/gateway/models"
+	"github.com/hoophq/hoop/gateway/security/apitoken"
+	"github.com/hoophq/hoop/gateway/session/asciicast"
+	"github.com/hoophq/hoop/gateway/storagev2"
+)
+
+// Playback
+//
+//	@Summary		Session Playback
+//	@Description	Stream the events of a session with their relative time in the asciinema v2 format (asciicast).
+//	@Description	The first line is the header with the initial size of the terminal, the next lines are the events in the format `[<time>, <code>, <data>]`
+//	@Description	* `o` - the output of the session
+//	@Description	* `i` - the input of the session
+//	@Description	* `r` - a resize of the client terminal in the format `<cols>x<rows>`
+//	@Tags			Sessions
+//	@Produce		application/x-asciicast,json
+//	@Param			session_id	path		string	true	"The id of the resource"
+//	@Success		200			{string}	string
+//	@Failure		403,404,500	{object}	openapi.HTTPError
+//	@Router			/sessions/{session_id}/playback [get]
+func Playback(c *gin.Context) {
+	ctx := storagev2.ParseContext(c)
+	sessionID := c.Param("session_id")
+	apiroutes.SetSidSpanAttr(c, sessionID)
+	if appconfig.Get().DisableSessionsDownload() {
+		c.JSON(http.StatusForbidden, gin.H{"message": "session download is not allowed."})
+		return
+	}
+	session, err := models.GetSessionByID(ctx.OrgID, sessionID)
+	switch err {
+	case models.ErrNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"message": "not found"})
+		return
+	case nil:
+	default:
+		log.Errorf("failed fetching session, err=%v", err)
+		sentry.CaptureException(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "failed fetching session"})
+		return
+	}
+	// if user is not admin or auditor and session is not owned by user, return 404
+	if session.UserID != ctx.UserID && !canReadAllSessions(ctx) ||
+		!apitoken.IsConnectionAllowed(ctx.TokenConnections, session.Connection) {
+		c.JSON(http.StatusNotFound, gin.H{"message": "not found"})
+		return
+	}
+
+	title := fmt.Sprintf("%s - %s", session.Connection, session.UserEmail)
+	header, events, err := asciicast.Parse(session.BlobStream, session.CreatedAt, title)
+	if err != nil {
+		log.With("sid", sessionID).Error(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "failed parsing blob stream"})
+		return
+	}
+	c.Header("Content-Type", asciicast.ContentType)
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%s.cast", sessionID))
+	c.Status(http.StatusOK)
+	if err := asciicast.Write(c.Writer, header, events); err != nil {
+		log.With("sid", sessionID).Warnf("failed writing session playback, reason=%v", err)
+		return
+	}
+	log.With("sid", sessionID).Infof("session playback sent, events=%v", len(events))
+}
@@ -14,9 +14,11 @@ import (
 	"github.com/hoophq/hoop/gateway/api/apiroutes"
 	"github.com/hoophq/hoop/gateway/api/openapi"
 	"github.com/hoophq/hoop/gateway/clientexec"
+	"github.com/hoophq/hoop/gateway/guardrails"
 	"github.com/hoophq/hoop/gateway/models"
 	pgplugins "github.com/hoophq/hoop/gateway/pgrest/plugins"
 	pgreview "github.com/hoophq/hoop/gateway/pgrest/review"
+	"github.com/hoophq/hoop/gateway/security/apitoken"
 	"github.com/hoophq/hoop/gateway/storagev2"
 	sessionstorage "github.com/hoophq/hoop/gateway/storagev2/session"
 	"github.com/hoophq/hoop/gateway/storagev2/types"
@@ -92,6 +94,11 @@ func RunReviewedExec(c *gin.Context) {
 		return
 	}
 
+	if !apitoken.IsConnectionAllowed(ctx.TokenConnections, session.Connection) {
+		c.JSON(http.StatusNotFound, gin.H{"message": "session not found"})
+		return
+	}
+
 	if session.UserEmail != ctx.UserEmail {
 		c.JSON(http.StatusBadRequest, gin.H{"message": "only the creator can trigger this action"})
 		return
@@ -119,7 +126,9 @@ func RunReviewedExec(c *gin.Context) {
 
 	// The plugin must be active to be able to change the state of the review
 	// after the execution, this will ensure that a review is executed only once.
-	if !hasReviewPlugin {
+	// Reviews created by guard rails are allowed because the execution is locked
+	// and the review state is changed when the execution finishes.
+	if !hasReviewPlugin && !hasGuardRailsReview(session) {
 		errMsg := fmt.Sprintf("review plugin is not enabled for the connection %s", review.Connection.Name)
 		log.Infof(errMsg)
 		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": errMsg})
@@ -183,6 +192,15 @@ func RunReviewedExec(c *gin.Context) {
 	}
 }
 
+func hasGuardRailsReview(session *models.Session) bool {
+	for _, info := range session.GuardRailsInfo {
+		if info.Action == guardrails.ActionReview {
+			return true
+		}
+	}
+	return false
+}
+
 var syncMutexExecMap = sync.RWMutex{}
 var mutexExecMap = map[string]any{}
 
@@ -22,6 +22,7 @@ import (
 	"github.com/hoophq/hoop/common/log"
 	"github.com/hoophq/hoop/common/memory"
 	pb "github.com/hoophq/hoop/common/proto"
+	"github.com/hoophq/hoop/gateway/accesspolicy"
 	"github.com/hoophq/hoop/gateway/api/apiroutes"
 	apiconnections "github.com/hoophq/hoop/gateway/api/connections"
 	"github.com/hoophq/hoop/gateway/api/openapi"
@@ -30,9 +31,13 @@ import (
 	"github.com/hoophq/hoop/gateway/guardrails"
 	"github.com/hoophq/hoop/gateway/jira"
 	"github.com/hoophq/hoop/gateway/models"
+	"github.com/hoophq/hoop/gateway/pgrest"
 	pgreview "github.com/hoophq/hoop/gateway/pgrest/review"
+	"github.com/hoophq/hoop/gateway/review"
+	"github.com/hoophq/hoop/gateway/security/apitoken"
 	"github.com/hoophq/hoop/gateway/storagev2"
 	"github.com/hoophq/hoop/gateway/storagev2/types"
+	transportext "github.com/hoophq/hoop/gateway/transport/extensions"
 	transportsystem "github.com/hoophq/hoop/gateway/transport/system"
 )
 
@@ -41,8 +46,18 @@ var (
 	defaultDownloadExpireTime  = time.Minute * 5
 	internalExitCode           = 254
 	defaultMaxSessionListLimit = 100
+
+	reviewService reviewCreator
 )
 
+// reviewCreator creates the reviews of guard rails rules notifying their groups
+type reviewCreator interface {
+	Create(ctx pgrest.OrgContext, rev *types.Review) error
+}
+
+// WithReviewService sets the service that creates the reviews of guard rails rules
+func WithReviewService(svc reviewCreator) { reviewService = svc }
+
 type SessionPostBody struct {
 	Script     string              `json:"script"`
 	Connection string              `json:"connection"`
@@ -85,7 +100,11 @@ func Post(c *gin.Context) {
 		return
 	}
 
-	conn, err := apiconnections.FetchByName(ctx, req.Connection)
+	conn, err := apiconnections.FetchByName(ctx, req.Connection, accesspolicy.Request{
+		Verb:     pb.ClientVerbExec,
+		Origin:   pb.ConnectionOriginClientAPI,
+		SourceIP: c.ClientIP(),
+	})
 	if err != nil {
 		log.Errorf("failed fetch connection %v for exec, err=%v", req.Connection, err)
 		sentry.CaptureException(err)
@@ -129,8 +148,9 @@ func Post(c *gin.Context) {
 		return
 	}
 
+	var guardRailsResult *guardrails.Result
 	if connRules != nil {
-		err = guardrails.Validate("input", connRules.GuardRailInputRules, []byte(req.Script))
+		guardRailsResult, err = guardrails.Evaluate("input", connRules.GuardRailInputRules, []byte(req.Script))
 		switch err.(type) {
 		case *guardrails.ErrRuleMatch:
 			// persist session to audit this attempt
@@ -204,6 +224,24 @@ func Post(c *gin.Context) {
 		return
 	}
 
+	if guardRailsResult != nil {
+		transportext.RecordGuardRailsMatches(ctx.OrgID, sid, conn.Name, guardRailsResult.Matches())
+		if guardRailsResult.Review != nil {
+			reviewURL, err := createGuardRailsReview(ctx, conn, sid, req, guardRailsResult.Review)
+			if err != nil {
+				log.Errorf("failed creating guard rails review, err=%v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"message": "failed creating review"})
+				return
+			}
+			c.JSON(http.StatusOK, clientexec.Response{
+				HasReview: true,
+				SessionID: sid,
+				Output:    reviewURL,
+			})
+			return
+		}
+	}
+
 	// TODO: refactor to use response from openapi package
 	client, err := clientexec.New(&clientexec.Options{
 		OrgID:          ctx.GetOrgID(),
@@ -240,6 +278,62 @@ func Post(c *gin.Context) {
 	}
 }
 
+// createGuardRailsReview routes the input to a one time review when
+// it matches a guard rail rule with the review action
+func createGuardRailsReview(ctx *storagev2.Context, conn *models.Connection, sid string, req SessionPostBody, match *guardrails.ErrRuleMatch) (string, error) {
+	groups := match.ReviewGroups()
+	if len(groups) == 0 {
+		groups = conn.Reviewers
+	}
+	if len(groups) == 0 {
+		groups = []string{types.GroupAdmin}
+	}
+	reviewGroups := []types.ReviewGroup{}
+	for _, group := range groups {
+		reviewGroups = append(reviewGroups, types.ReviewGroup{
+			Id:     uuid.NewString(),
+			Group:  group,
+			Status: types.ReviewStatusPending,
+		})
+	}
+	rev := &types.Review{
+		Id:              uuid.NewString(),
+		Type:            review.ReviewTypeOneTime,
+		OrgId:           ctx.OrgID,
+		CreatedAt:       time.Now().UTC(),
+		Session:         sid,
+		Input:           req.Script,
+		InputClientArgs: req.ClientArgs,
+		ConnectionId:    conn.ID,
+		Connection: types.ReviewConnection{
+			Id:   conn.ID,
+			Name: conn.Name,
+		},
+		CreatedBy: ctx.UserID,
+		ReviewOwner: types.ReviewOwner{
+			Id:      ctx.UserID,
+			Name:    ctx.UserName,
+			Email:   ctx.UserEmail,
+			SlackID: ctx.SlackID,
+		},
+		Status:           types.ReviewStatusPending,
+		ReviewGroupsIds:  groups,
+		ReviewGroupsData: reviewGroups,
+		Policy:           conn.ReviewPolicy,
+	}
+	log.With("sid", sid, "id", rev.Id, "user", ctx.UserID, "org", ctx.OrgID).
+		Infof("creating review from guard rails match, rule-type=%v", match.RuleType())
+	if err := reviewService.Create(ctx, rev); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/plugins/reviews/%s", appconfig.Get().FullApiURL(), rev.Id), nil
+}
+
+// canReadAllSessions returns true if the user is allowed to read the sessions of other users
+func canReadAllSessions(ctx *storagev2.Context) bool {
+	return ctx.IsAuditorOrAdminUser() || ctx.HasPermission(string(openapi.PermissionSessionsRead))
+}
+
 func CoerceMetadataFields(metadata map[string]any) error {
 	if len(metadata) > 20 {
 		return fmt.Errorf("metadata field must have less than 10 fields")
@@ -278,7 +372,7 @@ func List(c *gin.Context) {
 		if queryOptVal, ok := c.GetQuery(string(optKey)); ok {
 			switch optKey {
 			case openapi.SessionOptionUser:
-				if !ctx.IsAuditorOrAdminUser() {
+				if !canReadAllSessions(ctx) {
 					continue
 				}
 				option.User = queryOptVal
@@ -323,9 +417,11 @@ func List(c *gin.Context) {
 	}
 
 	// scope listing to the authenticated user
-	if !ctx.IsAuditorOrAdminUser() {
+	if !canReadAllSessions(ctx) {
 		option.User = ctx.UserID
 	}
+	// api tokens could be restricted to a list of connections
+	option.Connections = ctx.TokenConnections
 
 	if option.StartDate.Valid && !option.EndDate.Valid {
 		option.EndDate = sql.NullString{
@@ -375,7 +471,11 @@ func Get(c *gin.Context) {
 	}
 
 	// if user is not admin or auditor and session is not owned by user, return 404
-	if session.UserID != ctx.UserID && !ctx.IsAuditorOrAdminUser() {
+	if session.UserID != ctx.UserID && !canReadAllSessions(ctx) {
+		c.JSON(http.StatusNotFound, gin.H{"message": "not found"})
+		return
+	}
+	if !apitoken.IsConnectionAllowed(ctx.TokenConnections, session.Connection) {
 		c.JSON(http.StatusNotFound, gin.H{"message": "not found"})
 		return
 	}
@@ -451,6 +551,15 @@ func Get(c *gin.Context) {
 	if !slices.Contains(expandedFieldParts, "event_stream") {
 		obj.EventStream = nil
 	}
+	if slices.Contains(expandedFieldParts, "statements") {
+		statements, err := models.ListSessionStatements(ctx.GetOrgID(), sessionID)
+		if err != nil {
+			log.With("sid", sessionID).Errorf("failed listing session statements, reason=%v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "failed obtaining session statements"})
+			return
+		}
+		obj.Statements = toOpenApiSessionStatements(statements)
+	}
 	// encode the object manually to obtain any encoding errors.
 	c.Writer.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(c.Writer).Encode(obj); err != nil {
@@ -0,0 +1,68 @@
+package sessionapi
+
+import (
+	"net/http"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/gin-gonic/gin"
+	"github.com/hoophq/hoop/common/log"
+	"github.com/hoophq/hoop/gateway/api/apiroutes"
+	"github.com/hoophq/hoop/gateway/api/openapi"
+	"github.com/hoophq/hoop/gateway/appconfig"
+	"github.com/hoophq/hoop/gateway/models"
+	"github.com/hoophq/hoop/gateway/security/apitoken"
+	"github.com/hoophq/hoop/gateway/session/integrity"
+	"github.com/hoophq/hoop/gateway/storagev2"
+)
+
+// Verify
+//
+//	@Summary		Verify Session
+//	@Description	Verify the event stream of a session against the digest sealed by the gateway when the session ended.
+//	@Description	It detects when the event stream was truncated or modified and when the seal doesn't match its signature or the anchored digest.
+//	@Tags			Sessions
+//	@Produce		json
+//	@Param			session_id	path		string	true	"The id of the resource"
+//	@Success		200			{object}	openapi.SessionVerification
+//	@Failure		404,500		{object}	openapi.HTTPError
+//	@Router			/sessions/{session_id}/verify [get]
+func Verify(c *gin.Context) {
+	ctx := storagev2.ParseContext(c)
+	sessionID := c.Param("session_id")
+	apiroutes.SetSidSpanAttr(c, sessionID)
+	session, err := models.GetSessionByID(ctx.OrgID, sessionID)
+	switch err {
+	case models.ErrNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"message": "not found"})
+		return
+	case nil:
+	default:
+		log.Errorf("failed fetching session, err=%v", err)
+		sentry.CaptureException(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "failed fetching session"})
+		return
+	}
+	if !apitoken.IsConnectionAllowed(ctx.TokenConnections, session.Connection) {
+		c.JSON(http.StatusNotFound, gin.H{"message": "not found"})
+		return
+	}
+	appc := appconfig.Get()
+	res := integrity.Verify(session, appc.SessionSigningKey(), appc.SessionAnchorFile())
+	resp := openapi.SessionVerification{
+		SessionID:         session.ID,
+		Status:            openapi.SessionVerificationStatus(res.Status),
+		Reason:            res.Reason,
+		Events:            res.Events,
+		Digest:            res.Digest,
+		SignatureVerified: res.SignatureVerified,
+		AnchoredAt:        session.IntegrityAnchoredAt,
+	}
+	if seal := res.Seal; seal != nil {
+		resp.SealedEvents = seal.Events
+		resp.SealedDigest = seal.Digest
+		resp.KeyID = seal.KeyID
+		resp.SealedAt = &seal.SealedAt
+	}
+	log.With("sid", sessionID).Infof("session verified, status=%v, reason=%v", res.Status, res.Reason)
+	c.JSON(http.StatusOK, resp)
+}
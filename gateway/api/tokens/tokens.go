@@ -0,0 +1,214 @@
+package apitokens
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hoophq/hoop/common/log"
+	"github.com/hoophq/hoop/gateway/api/openapi"
+	"github.com/hoophq/hoop/gateway/models"
+	pguserauth "github.com/hoophq/hoop/gateway/pgrest/userauth"
+	"github.com/hoophq/hoop/gateway/security/apitoken"
+	"github.com/hoophq/hoop/gateway/storagev2"
+	"github.com/hoophq/hoop/gateway/storagev2/types"
+)
+
+// CreateAPIToken
+//
+//	@Summary		Create API Token
+//	@Description	Create a token that authenticates on behalf of a user or service account.
+//	@Description	The token is restricted to the routes and verbs of its scopes and it's returned only once.
+//	@Tags			API Tokens
+//	@Accept			json
+//	@Produce		json
+//	@Param			request				body		openapi.APITokenRequest	true	"The request body resource"
+//	@Success		201					{object}	openapi.APIToken
+//	@Failure		400,403,409,422,500	{object}	openapi.HTTPError
+//	@Router			/tokens [post]
+func Post(c *gin.Context) {
+	ctx := storagev2.ParseContext(c)
+	var req openapi.APITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+	var scopes []string
+	for _, scope := range req.Scopes {
+		scopes = append(scopes, string(scope))
+	}
+	if err := apitoken.ValidateScopes(scopes); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": err.Error()})
+		return
+	}
+	now := time.Now().UTC()
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": "expires_at must be a time in the future"})
+		return
+	}
+
+	subject, email := ctx.UserID, ctx.UserEmail
+	if req.Subject != "" && req.Subject != ctx.UserID {
+		if !ctx.IsAdmin() {
+			c.JSON(http.StatusForbidden, gin.H{"message": "only admin users are allowed to create tokens for other users"})
+			return
+		}
+		owner, err := pguserauth.New().FetchUserContext(req.Subject)
+		if err != nil {
+			log.Errorf("failed fetching token owner, reason=%v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "failed fetching user"})
+			return
+		}
+		if owner.IsEmpty() || owner.OrgID != ctx.OrgID || owner.UserStatus != string(types.UserStatusActive) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"message": fmt.Sprintf("active user or service account %q not found", req.Subject)})
+			return
+		}
+		subject, email = owner.UserSubject, owner.UserEmail
+	}
+
+	token, tokenHash, err := apitoken.Generate()
+	if err != nil {
+		log.Errorf("failed generating api token, reason=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	apiToken := &models.APIToken{
+		ID:          uuid.NewString(),
+		OrgID:       ctx.OrgID,
+		Name:        req.Name,
+		UserSubject: subject,
+		UserEmail:   email,
+		TokenHash:   tokenHash,
+		TokenPrefix: apitoken.DisplayPrefix(token),
+		Scopes:      scopes,
+		Connections: req.Connections,
+		CreatedBy:   ctx.UserEmail,
+		CreatedAt:   now,
+	}
+	if apiToken.Connections == nil {
+		apiToken.Connections = []string{}
+	}
+	if req.ExpiresAt != nil {
+		apiToken.ExpiresAt = sql.NullTime{Time: req.ExpiresAt.UTC(), Valid: true}
+	}
+	err = models.CreateAPIToken(apiToken)
+	switch err {
+	case models.ErrAlreadyExists:
+		c.JSON(http.StatusConflict, gin.H{"message": fmt.Sprintf("an active token named %q already exists for the user", req.Name)})
+	case nil:
+		resp := toOpenApi(apiToken, now)
+		resp.Token = token
+		c.JSON(http.StatusCreated, resp)
+	default:
+		log.Errorf("failed creating api token, reason=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	}
+}
+
+// ListAPITokens
+//
+//	@Summary		List API Tokens
+//	@Description	List the tokens of the authenticated user, admin users list the tokens of the organization
+//	@Tags			API Tokens
+//	@Produce		json
+//	@Success		200	{array}		openapi.APIToken
+//	@Failure		500	{object}	openapi.HTTPError
+//	@Router			/tokens [get]
+func List(c *gin.Context) {
+	ctx := storagev2.ParseContext(c)
+	userSubject := ctx.UserID
+	if ctx.IsAdmin() {
+		userSubject = ""
+	}
+	items, err := models.ListAPITokens(ctx.OrgID, userSubject)
+	if err != nil {
+		log.Errorf("failed listing api tokens, reason=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	now := time.Now().UTC()
+	tokens := []openapi.APIToken{}
+	for _, t := range items {
+		tokens = append(tokens, *toOpenApi(&t, now))
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+// RevokeAPIToken
+//
+//	@Summary		Revoke API Token
+//	@Description	Revoke a token, only the owner of the token or admin users are allowed to revoke it
+//	@Tags			API Tokens
+//	@Produce		json
+//	@Param			id	path	string	true	"The unique identifier of the resource"
+//	@Success		204
+//	@Failure		404,500	{object}	openapi.HTTPError
+//	@Router			/tokens/{id} [delete]
+func Delete(c *gin.Context) {
+	ctx := storagev2.ParseContext(c)
+	apiToken, err := models.GetAPIToken(ctx.OrgID, c.Param("id"))
+	switch err {
+	case models.ErrNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"message": "resource not found"})
+		return
+	case nil:
+	default:
+		log.Errorf("failed fetching api token, reason=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+		return
+	}
+	if apiToken.UserSubject != ctx.UserID && !ctx.IsAdmin() {
+		c.JSON(http.StatusNotFound, gin.H{"message": "resource not found"})
+		return
+	}
+	err = models.RevokeAPIToken(ctx.OrgID, apiToken.ID)
+	switch err {
+	case models.ErrNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"message": "resource not found"})
+	case nil:
+		c.Writer.WriteHeader(http.StatusNoContent)
+	default:
+		log.Errorf("failed revoking api token, reason=%v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	}
+}
+
+func toOpenApi(t *models.APIToken, now time.Time) *openapi.APIToken {
+	resp := &openapi.APIToken{
+		ID:          t.ID,
+		Name:        t.Name,
+		Subject:     t.UserSubject,
+		Email:       t.UserEmail,
+		TokenPrefix: t.TokenPrefix,
+		Scopes:      []openapi.APITokenScope{},
+		Connections: t.Connections,
+		Status:      openapi.APITokenStatusActive,
+		CreatedBy:   t.CreatedBy,
+		CreatedAt:   t.CreatedAt,
+	}
+	for _, scope := range t.Scopes {
+		resp.Scopes = append(resp.Scopes, openapi.APITokenScope(scope))
+	}
+	if resp.Connections == nil {
+		resp.Connections = []string{}
+	}
+	if t.ExpiresAt.Valid {
+		resp.ExpiresAt = &t.ExpiresAt.Time
+	}
+	if t.LastUsedAt.Valid {
+		resp.LastUsedAt = &t.LastUsedAt.Time
+	}
+	if t.RevokedAt.Valid {
+		resp.RevokedAt = &t.RevokedAt.Time
+	}
+	switch {
+	case t.RevokedAt.Valid:
+		resp.Status = openapi.APITokenStatusRevoked
+	case !t.IsActive(now):
+		resp.Status = openapi.APITokenStatusExpired
+	}
+	return resp
+}
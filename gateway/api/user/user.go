@@ -15,13 +15,16 @@ import (
 	"github.com/hoophq/hoop/common/apiutils"
 	"github.com/hoophq/hoop/common/log"
 	"github.com/hoophq/hoop/gateway/analytics"
+	"github.com/hoophq/hoop/gateway/api/apiroutes"
 	"github.com/hoophq/hoop/gateway/api/openapi"
 	"github.com/hoophq/hoop/gateway/appconfig"
 	"github.com/hoophq/hoop/gateway/models"
 	"github.com/hoophq/hoop/gateway/pgrest"
 	pgaudit "github.com/hoophq/hoop/gateway/pgrest/audit"
+	pgreview "github.com/hoophq/hoop/gateway/pgrest/review"
 	"github.com/hoophq/hoop/gateway/storagev2"
 	"github.com/hoophq/hoop/gateway/storagev2/types"
+	"github.com/hoophq/hoop/gateway/transport/streamclient"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
@@ -64,6 +67,17 @@ func Create(c *gin.Context) {
 		c.JSON(http.StatusConflict, gin.H{"message": fmt.Sprintf("user already exists with email %s", newUser.Email)})
 		return
 	}
+	err = apiroutes.ValidateGroupChange(ctx, nil, newUser.Groups)
+	switch {
+	case errors.Is(err, apiroutes.ErrForbiddenGroupChange):
+		c.JSON(http.StatusForbidden, gin.H{"message": err.Error()})
+		return
+	case err != nil:
+		log.Errorf("failed validating group change, reason=%v", err)
+		sentry.CaptureException(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "failed validating group change"})
+		return
+	}
 
 	newUser.ID = uuid.NewString()
 	var hashedPassword string
@@ -193,10 +207,21 @@ func Update(c *gin.Context) {
 		userGroupsList = append(userGroupsList, userGroups[ug].Name)
 	}
 
-	// don't let admin users to remove admin group from themselves
+	err = apiroutes.ValidateGroupChange(ctx, userGroupsList, req.Groups)
+	switch {
+	case errors.Is(err, apiroutes.ErrForbiddenGroupChange):
+		c.JSON(http.StatusForbidden, gin.H{"message": err.Error()})
+		return
+	case err != nil:
+		log.Errorf("failed validating group change, reason=%v", err)
+		sentry.CaptureException(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "failed validating group change"})
+		return
+	}
+
 	if existingUser.Subject == ctx.UserID {
-		// TODO: maybe refactor this to get from userGroups directly
-		if !slices.Contains(req.Groups, types.GroupAdmin) {
+		// don't let admin users to remove admin group from themselves
+		if ctx.IsAdmin() && !slices.Contains(req.Groups, types.GroupAdmin) {
 			req.Groups = append(req.Groups, types.GroupAdmin)
 		}
 		if req.Status != openapi.StatusActive {
@@ -227,6 +252,9 @@ func Update(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "failed updating user and user groups"})
 		return
 	}
+	if existingUser.Status == string(openapi.StatusInactive) {
+		revokeUserAccess(ctx.OrgID, existingUser.Subject, streamclient.ErrUserDeactivated)
+	}
 
 	analytics.New().Identify(&types.APIContext{
 		OrgID:      ctx.OrgID,
@@ -334,15 +362,47 @@ func Delete(c *gin.Context) {
 		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": "cannot delete yourself"})
 		return
 	}
+	userGroups, err := models.GetUserGroupsByUserID(user.ID)
+	if err != nil {
+		log.Errorf("failed getting user groups for user %s, err=%v", user.ID, err)
+		sentry.CaptureException(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "failed getting user groups"})
+		return
+	}
+	var userGroupsList []string
+	for _, ug := range userGroups {
+		userGroupsList = append(userGroupsList, ug.Name)
+	}
+	err = apiroutes.ValidateGroupChange(ctx, userGroupsList, nil)
+	switch {
+	case errors.Is(err, apiroutes.ErrForbiddenGroupChange):
+		c.JSON(http.StatusForbidden, gin.H{"message": err.Error()})
+		return
+	case err != nil:
+		log.Errorf("failed validating group change, reason=%v", err)
+		sentry.CaptureException(err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "failed validating group change"})
+		return
+	}
 	if err := models.DeleteUser(ctx.OrgID, subject); err != nil {
 		log.Errorf("failed removing user %s, err=%v", subject, err)
 		sentry.CaptureException(err)
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "failed deleting user"})
 		return
 	}
+	revokeUserAccess(ctx.OrgID, subject, streamclient.ErrUserDeleted)
 	c.Writer.WriteHeader(204)
 }
 
+// revokeUserAccess revokes the jit and scheduled access of a user and terminates all of its live sessions
+func revokeUserAccess(orgID, subject string, reason error) {
+	if err := pgreview.New().RevokeAccessByOwner(pgrest.NewOrgContext(orgID), subject); err != nil {
+		log.With("user", subject).Warnf("failed revoking jit and scheduled reviews, reason=%v", err)
+	}
+	total := streamclient.DisconnectProxiesByUser(orgID, subject, reason)
+	log.With("user", subject).Infof("user access revoked, terminated sessions=%v", total)
+}
+
 // GetUserByEmailOrID
 //
 //	@Summary		Get User
@@ -474,6 +534,10 @@ func GetUserInfo(c *gin.Context) {
 		FeatureAskAI:           askAIFeatureStatus,
 		WebAppUsersManagement:  appconfig.Get().WebappUsersManagement(),
 		IntercomUserHmacDigest: intercomUserHash,
+		Permissions:            []openapi.PermissionType{},
+	}
+	for _, permission := range ctx.Permissions {
+		userInfoData.Permissions = append(userInfoData.Permissions, openapi.PermissionType(permission))
 	}
 	if ctx.IsAnonymous() {
 		intercomUserHash, _ := analytics.GenerateIntercomHmacDigest(ctx.UserAnonEmail)
@@ -1,6 +1,7 @@
 package appconfig
 
 import (
+	"crypto/ed25519"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/base64"
@@ -10,6 +11,7 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/hoophq/hoop/common/envloader"
 )
@@ -21,6 +23,15 @@ const (
 	defaultWebappStaticUiPath string = "/app/ui/public"
 )
 
+// S3StorageConfig is the configuration of an S3 compatible object store
+type S3StorageConfig struct {
+	Bucket          string
+	Region          string
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
 type pgCredentials struct {
 	connectionString string
 	username         string
@@ -59,6 +70,11 @@ type Config struct {
 	gatewayTLSCert                  string
 	sshClientHostKey                string
 	integrationAWSInstanceRoleAllow bool
+	sessionStorageBackend           string
+	sessionStorageS3                *S3StorageConfig
+	sessionSigningKey               ed25519.PrivateKey
+	sessionAnchorFile               string
+	sessionAnchorInterval           time.Duration
 
 	isLoaded bool
 }
@@ -169,6 +185,23 @@ func Load() error {
 		}
 	}
 
+	sessionStorageBackend, sessionStorageS3, err := loadSessionStorage()
+	if err != nil {
+		return err
+	}
+
+	sessionSigningKey, err := loadSessionSigningKey()
+	if err != nil {
+		return err
+	}
+	sessionAnchorInterval := time.Hour
+	if val := os.Getenv("SESSION_INTEGRITY_ANCHOR_INTERVAL"); val != "" {
+		sessionAnchorInterval, err = time.ParseDuration(val)
+		if err != nil || sessionAnchorInterval < time.Minute {
+			return fmt.Errorf("SESSION_INTEGRITY_ANCHOR_INTERVAL must be a duration of at least 1m, got=%q", val)
+		}
+	}
+
 	runtimeConfig = Config{
 		apiKey:                          os.Getenv("API_KEY"),
 		apiURL:                          fmt.Sprintf("%s://%s", apiRawURL.Scheme, apiRawURL.Host),
@@ -202,6 +235,11 @@ func Load() error {
 		gatewayTLSCert:                  gatewayTLSCert,
 		sshClientHostKey:                sshClientHostKey,
 		integrationAWSInstanceRoleAllow: os.Getenv("INTEGRATION_AWS_INSTANCE_ROLE_ALLOW") == "true",
+		sessionStorageBackend:           sessionStorageBackend,
+		sessionStorageS3:                sessionStorageS3,
+		sessionSigningKey:               sessionSigningKey,
+		sessionAnchorFile:               os.Getenv("SESSION_INTEGRITY_ANCHOR_FILE"),
+		sessionAnchorInterval:           sessionAnchorInterval,
 	}
 	return nil
 }
@@ -283,6 +321,60 @@ func loadAskAICredentials() (*url.URL, error) {
 	return u, nil
 }
 
+// loadSessionStorage loads where the event streams of sessions are stored,
+// the default is to keep them in the Postgres database of the gateway
+func loadSessionStorage() (string, *S3StorageConfig, error) {
+	backend := os.Getenv("SESSION_STORAGE_BACKEND")
+	switch backend {
+	case "", "postgres":
+		return "postgres", nil, nil
+	case "s3":
+		cfg := &S3StorageConfig{
+			Bucket:          os.Getenv("SESSION_STORAGE_S3_BUCKET"),
+			Region:          os.Getenv("SESSION_STORAGE_S3_REGION"),
+			Endpoint:        os.Getenv("SESSION_STORAGE_S3_ENDPOINT"),
+			AccessKeyID:     os.Getenv("SESSION_STORAGE_S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("SESSION_STORAGE_S3_SECRET_ACCESS_KEY"),
+		}
+		if cfg.Bucket == "" || cfg.Region == "" {
+			return "", nil, fmt.Errorf("missing SESSION_STORAGE_S3_BUCKET or SESSION_STORAGE_S3_REGION env")
+		}
+		if (cfg.AccessKeyID == "") != (cfg.SecretAccessKey == "") {
+			return "", nil, fmt.Errorf("SESSION_STORAGE_S3_ACCESS_KEY_ID and SESSION_STORAGE_S3_SECRET_ACCESS_KEY envs must be set together")
+		}
+		if cfg.Endpoint != "" {
+			if _, err := url.Parse(cfg.Endpoint); err != nil {
+				return "", nil, fmt.Errorf("failed parsing SESSION_STORAGE_S3_ENDPOINT, reason=%v", err)
+			}
+		}
+		return backend, cfg, nil
+	}
+	return "", nil, fmt.Errorf("unknown SESSION_STORAGE_BACKEND %q, accepted values are: postgres, s3", backend)
+}
+
+// loadSessionSigningKey loads the ed25519 key used to sign the digest of sessions,
+// the key is a PKCS8 private key in PEM format encoded in base64
+func loadSessionSigningKey() (ed25519.PrivateKey, error) {
+	b64EncPrivateKey := os.Getenv("SESSION_SIGNING_KEY")
+	if b64EncPrivateKey == "" {
+		return nil, nil
+	}
+	privKeyBytes, err := base64.StdEncoding.DecodeString(b64EncPrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load SESSION_SIGNING_KEY, reason=%v", err)
+	}
+	block, _ := pem.Decode(privKeyBytes)
+	if block == nil {
+		return nil, fmt.Errorf("unable to load SESSION_SIGNING_KEY: it is not in PEM format")
+	}
+	obj, _ := x509.ParsePKCS8PrivateKey(block.Bytes)
+	privkey, ok := obj.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("unable to load SESSION_SIGNING_KEY: it is not an ed25519 private key, got=%T", obj)
+	}
+	return privkey, nil
+}
+
 func loadGcpDLPCredentials() (string, error) {
 	jsonCred := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS_JSON")
 	if jsonCred == "" {
@@ -357,6 +449,21 @@ func (c Config) GatewayTLSKey() string                 { return c.gatewayTLSKey
 func (c Config) GatewayTLSCert() string                { return c.gatewayTLSCert }
 func (c Config) SSHClientHostKey() string              { return c.sshClientHostKey }
 func (c Config) IntegrationAWSInstanceRoleAllow() bool { return c.integrationAWSInstanceRoleAllow }
+
+// SessionStorageBackend is where the event streams of sessions are stored: postgres or s3
+func (c Config) SessionStorageBackend() string { return c.sessionStorageBackend }
+
+// SessionStorageS3 returns the configuration of the object store when the backend is s3
+func (c Config) SessionStorageS3() *S3StorageConfig { return c.sessionStorageS3 }
+
+// SessionSigningKey returns the key that signs the digest of sessions, it's nil when it's not configured
+func (c Config) SessionSigningKey() ed25519.PrivateKey { return c.sessionSigningKey }
+
+// SessionAnchorFile returns the path of the append only file where the digest of sessions are anchored
+func (c Config) SessionAnchorFile() string { return c.sessionAnchorFile }
+
+// SessionAnchorInterval returns how often the digest of sessions are anchored
+func (c Config) SessionAnchorInterval() time.Duration { return c.sessionAnchorInterval }
 func (c Config) AskAIApiURL() (u string) {
 	if c.IsAskAIAvailable() {
 		return fmt.Sprintf("%s://%s", c.askAICredentials.Scheme, c.askAICredentials.Host)
@@ -0,0 +1,143 @@
+package guardrails
+
+import "slices"
+
+// wordMatcher is an Aho-Corasick automaton that finds all words of a list
+// in a single pass over the data. The initial state has a full transition table,
+// the other states keep only the transitions of the trie and follow the failure
+// links, it keeps the memory proportional to the size of the word list.
+type wordMatcher struct {
+	// root is the transition table of the initial state, zero means no transition
+	root [256]int32
+	// edges are the transitions of the trie of each state sorted by byte,
+	// the initial state uses the root table
+	edges [][]edge
+	// fail is the failure link of each state
+	fail []int32
+	// out contains the length of the words ending at each state,
+	// including the words reachable by the failure links
+	out [][]int
+}
+
+type edge struct {
+	ch   byte
+	next int32
+}
+
+func newWordMatcher(words []string) *wordMatcher {
+	m := &wordMatcher{edges: make([][]edge, 1), out: make([][]int, 1)}
+	// build the trie, zero means no transition
+	for _, word := range words {
+		if word == "" {
+			continue
+		}
+		state := int32(0)
+		for i := 0; i < len(word); i++ {
+			ch := word[i]
+			next := m.child(state, ch)
+			if next == 0 {
+				m.edges = append(m.edges, nil)
+				m.out = append(m.out, nil)
+				next = int32(len(m.edges) - 1)
+				m.addChild(state, ch, next)
+			}
+			state = next
+		}
+		if !slices.Contains(m.out[state], len(word)) {
+			m.out[state] = append(m.out[state], len(word))
+		}
+	}
+
+	// compute the failure links in breadth first order
+	m.fail = make([]int32, len(m.edges))
+	queue := make([]int32, 0, len(m.edges))
+	for ch := 0; ch < 256; ch++ {
+		if s := m.root[ch]; s != 0 {
+			queue = append(queue, s)
+		}
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for _, size := range m.out[m.fail[state]] {
+			if !slices.Contains(m.out[state], size) {
+				m.out[state] = append(m.out[state], size)
+			}
+		}
+		for _, e := range m.edges[state] {
+			m.fail[e.next] = m.step(m.fail[state], e.ch)
+			queue = append(queue, e.next)
+		}
+	}
+	return m
+}
+
+// child returns the transition of the trie, zero means no transition
+func (m *wordMatcher) child(state int32, ch byte) int32 {
+	if state == 0 {
+		return m.root[ch]
+	}
+	edges := m.edges[state]
+	if i, found := slices.BinarySearchFunc(edges, ch, compareEdge); found {
+		return edges[i].next
+	}
+	return 0
+}
+
+func (m *wordMatcher) addChild(state int32, ch byte, next int32) {
+	if state == 0 {
+		m.root[ch] = next
+		return
+	}
+	i, _ := slices.BinarySearchFunc(m.edges[state], ch, compareEdge)
+	m.edges[state] = slices.Insert(m.edges[state], i, edge{ch: ch, next: next})
+}
+
+func compareEdge(e edge, ch byte) int { return int(e.ch) - int(ch) }
+
+// step returns the next state of the automaton following the failure links
+func (m *wordMatcher) step(state int32, ch byte) int32 {
+	for state != 0 {
+		if next := m.child(state, ch); next != 0 {
+			return next
+		}
+		state = m.fail[state]
+	}
+	return m.root[ch]
+}
+
+// match reports if any word is found in the data
+func (m *wordMatcher) match(data []byte) bool {
+	state := int32(0)
+	for _, ch := range data {
+		state = m.step(state, ch)
+		if len(m.out[state]) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// findAll returns the offsets of all words found in the data, overlapping matches are merged
+func (m *wordMatcher) findAll(data []byte) []Offset {
+	var offsets []Offset
+	state := int32(0)
+	for i, ch := range data {
+		state = m.step(state, ch)
+		if len(m.out[state]) == 0 {
+			continue
+		}
+		end := i + 1
+		start := end
+		for _, size := range m.out[state] {
+			start = min(start, end-size)
+		}
+		// a longer word may overlap more than one previous match
+		for len(offsets) > 0 && start < offsets[len(offsets)-1].End {
+			start = min(start, offsets[len(offsets)-1].Start)
+			offsets = offsets[:len(offsets)-1]
+		}
+		offsets = append(offsets, Offset{Start: start, End: end})
+	}
+	return offsets
+}
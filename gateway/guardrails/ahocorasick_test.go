@@ -0,0 +1,37 @@
+package guardrails
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWordMatcher(t *testing.T) {
+	for _, tt := range []struct {
+		msg   string
+		words []string
+		input string
+		want  []Offset
+	}{
+		{msg: "it should find all words", words: []string{"foo", "bar"}, input: "foo and bar and foo",
+			want: []Offset{{0, 3}, {8, 11}, {16, 19}}},
+		{msg: "it should find words by its suffix", words: []string{"he", "she", "hers"}, input: "ushers",
+			want: []Offset{{1, 6}}},
+		{msg: "it should merge overlapping words", words: []string{"abc", "bcd"}, input: "xabcdx",
+			want: []Offset{{1, 5}}},
+		{msg: "it should merge words contained by a longer word", words: []string{"b", "c", "abcd"}, input: "abcd",
+			want: []Offset{{0, 4}}},
+		{msg: "it should not merge adjacent words", words: []string{"foo", "bar"}, input: "foobar",
+			want: []Offset{{0, 3}, {3, 6}}},
+		{msg: "it should follow the failure links of partial matches", words: []string{"abcd", "bce", "cex"}, input: "abcexabcd",
+			want: []Offset{{1, 5}, {5, 9}}},
+		{msg: "it should skip empty words", words: []string{""}, input: "foo", want: nil},
+		{msg: "it should match case sensitive", words: []string{"SELECT"}, input: "select", want: nil},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			m := newWordMatcher(tt.words)
+			assert.Equal(t, tt.want, m.findAll([]byte(tt.input)))
+			assert.Equal(t, len(tt.want) > 0, m.match([]byte(tt.input)))
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package guardrails
+
+import (
+	"crypto/sha256"
+	"sync"
+	"sync/atomic"
+)
+
+// maxCachedRuleSets limits the memory used by the cache,
+// all entries are evicted when the limit is reached
+const maxCachedRuleSets = 1024
+
+var ruleSetCache = struct {
+	mu    sync.RWMutex
+	items map[[sha256.Size]byte]*RuleSet
+}{items: map[[sha256.Size]byte]*RuleSet{}}
+
+// cacheGeneration is incremented every time the cache is invalidated
+var cacheGeneration atomic.Int64
+
+// Compile returns the compiled rule set of the rule data.
+// The rule sets are cached by the content of the rules, a new version
+// of the rules is compiled once and shared by all sessions using it.
+func Compile(ruleData []byte) (*RuleSet, error) {
+	if len(ruleData) == 0 {
+		return &RuleSet{}, nil
+	}
+	key := sha256.Sum256(ruleData)
+	ruleSetCache.mu.RLock()
+	rs, ok := ruleSetCache.items[key]
+	ruleSetCache.mu.RUnlock()
+	if ok {
+		return rs, nil
+	}
+	rs, err := compile(ruleData)
+	if err != nil {
+		return nil, err
+	}
+	ruleSetCache.mu.Lock()
+	defer ruleSetCache.mu.Unlock()
+	if len(ruleSetCache.items) >= maxCachedRuleSets {
+		clear(ruleSetCache.items)
+	}
+	ruleSetCache.items[key] = rs
+	return rs, nil
+}
+
+// InvalidateCache removes all compiled rule sets from the cache.
+// It must be called when guard rail rules are updated or removed.
+func InvalidateCache() {
+	ruleSetCache.mu.Lock()
+	defer ruleSetCache.mu.Unlock()
+	clear(ruleSetCache.items)
+	cacheGeneration.Add(1)
+}
+
+// CacheGeneration returns the current generation of the cache. Callers holding
+// a rule set must reload it when the generation changes.
+func CacheGeneration() int64 { return cacheGeneration.Load() }
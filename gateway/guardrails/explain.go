@@ -0,0 +1,170 @@
+package guardrails
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Offset is a byte range [Start, End) of the data matched by a rule
+type Offset struct {
+	Start int
+	End   int
+}
+
+// Explanation describes where and why a rule matched the data
+type Explanation struct {
+	StreamDirection string
+	// RuleIndex is the position of the rule in the list of rules
+	RuleIndex int
+	RuleType  string
+	Action    string
+	Offsets   []Offset
+	Reason    string
+}
+
+// decodeRules decodes the rules of a direction (input or output) as it's stored in the guard rails resource
+func decodeRules(streamDirection string, rules map[string]any) (*DataRules, error) {
+	jsonData, err := json.Marshal(rules)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode %v rules, reason=%v", streamDirection, err)
+	}
+	var dataRules DataRules
+	if err := json.Unmarshal(jsonData, &dataRules); err != nil {
+		return nil, fmt.Errorf("unable to decode %v rules, reason=%v", streamDirection, err)
+	}
+	return &dataRules, nil
+}
+
+// Explain evaluates all rules of a direction (input or output) against the data without
+// stopping at the first match. It returns every rule that matched, where it matched and why.
+// The mask action is not applied, the offsets are always relative to the original data.
+func Explain(streamDirection string, rules map[string]any, data []byte) ([]Explanation, error) {
+	if len(rules) == 0 || len(data) == 0 {
+		return nil, nil
+	}
+	dataRules, err := decodeRules(streamDirection, rules)
+	if err != nil {
+		return nil, err
+	}
+	var items []Explanation
+	for i, rule := range dataRules.Items {
+		matches, err := rule.explain(data)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			m.StreamDirection = streamDirection
+			m.RuleIndex = i
+			m.RuleType = rule.Type
+			m.Action = rule.action()
+			items = append(items, m)
+		}
+	}
+	return items, nil
+}
+
+func (r *Rule) explain(data []byte) ([]Explanation, error) {
+	var items []Explanation
+	switch r.Type {
+	case denyWordListType:
+		for _, word := range r.Words {
+			if word == "" {
+				continue
+			}
+			var offsets []Offset
+			for pos := 0; pos < len(data); {
+				idx := bytes.Index(data[pos:], []byte(word))
+				if idx == -1 {
+					break
+				}
+				start := pos + idx
+				offsets = append(offsets, Offset{Start: start, End: start + len(word)})
+				pos = start + len(word)
+			}
+			if len(offsets) > 0 {
+				items = append(items, Explanation{Offsets: offsets,
+					Reason: fmt.Sprintf("found the denied word %q %v time(s)", word, len(offsets))})
+			}
+		}
+	case patternMatchRegexType:
+		if r.PatternRegex == "" {
+			return nil, nil
+		}
+		regex, err := regexp.Compile(r.PatternRegex)
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing regex, reason=%v", err)
+		}
+		var offsets []Offset
+		for _, loc := range regex.FindAllIndex(data, -1) {
+			offsets = append(offsets, Offset{Start: loc[0], End: loc[1]})
+		}
+		if len(offsets) > 0 {
+			items = append(items, Explanation{Offsets: offsets,
+				Reason: fmt.Sprintf("matched the pattern %q %v time(s)", r.PatternRegex, len(offsets))})
+		}
+	case sqlDenyStatementsType, sqlAllowedTablesType, sqlMaxStatementsType:
+		return r.explainSQL(data)
+	case redisDenyCommandsType:
+		return r.explainRedis(data), nil
+	default:
+		return nil, fmt.Errorf("unknown rule type %q", r.Type)
+	}
+	return items, nil
+}
+
+func (r *Rule) explainSQL(data []byte) ([]Explanation, error) {
+	var items []Explanation
+	stmts := parseSQL(r.Dialect, data)
+	switch r.Type {
+	case sqlMaxStatementsType:
+		if r.MaxStatements <= 0 || len(stmts) <= r.MaxStatements {
+			return nil, nil
+		}
+		var offsets []Offset
+		for _, stmt := range stmts[r.MaxStatements:] {
+			start, end := stmt.span()
+			offsets = append(offsets, Offset{Start: start, End: end})
+		}
+		items = append(items, Explanation{Offsets: offsets,
+			Reason: fmt.Sprintf("found %v statements, the maximum allowed is %v", len(stmts), r.MaxStatements)})
+	case sqlDenyStatementsType:
+		for _, class := range r.Statements {
+			if _, ok := sqlStatementClasses[class]; !ok {
+				return nil, fmt.Errorf("unknown sql statement class %q", class)
+			}
+		}
+		for _, stmt := range stmts {
+			class := stmt.class()
+			if class == "" {
+				continue
+			}
+			for _, deniedClass := range r.Statements {
+				if class != deniedClass {
+					continue
+				}
+				start, end := stmt.span()
+				items = append(items, Explanation{Offsets: []Offset{{Start: start, End: end}},
+					Reason: fmt.Sprintf("the statement is classified as %v", class)})
+				break
+			}
+		}
+	case sqlAllowedTablesType:
+		if len(r.Tables) == 0 && len(r.Schemas) == 0 {
+			return nil, nil
+		}
+		for _, stmt := range stmts {
+			for _, table := range stmt.tables() {
+				if r.isTableAllowed(table.parts) {
+					continue
+				}
+				items = append(items, Explanation{Offsets: []Offset{{Start: table.start, End: table.end}},
+					Reason: fmt.Sprintf("the table %v is not in the allowed tables or schemas",
+						strings.Join(table.parts, "."))})
+			}
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,82 @@
+package guardrails
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplain(t *testing.T) {
+	for _, tt := range []struct {
+		msg   string
+		rules []any
+		input string
+		want  []Explanation
+	}{
+		{
+			msg:   "it should return all offsets of the denied words",
+			rules: []any{map[string]any{"type": "deny_words_list", "words": []string{"foo"}}},
+			input: "foo bar foo",
+			want: []Explanation{{RuleIndex: 0, RuleType: denyWordListType, Action: ActionBlock,
+				Offsets: []Offset{{0, 3}, {8, 11}}, Reason: `found the denied word "foo" 2 time(s)`}},
+		},
+		{
+			msg: "it should return all rules that matched",
+			rules: []any{
+				map[string]any{"type": "deny_words_list", "words": []string{"nomatch"}},
+				map[string]any{"type": "pattern_match", "pattern_regex": "[0-9]+", "action": "warn"},
+				map[string]any{"type": "deny_words_list", "words": []string{"bar"}},
+			},
+			input: "foo 123 bar",
+			want: []Explanation{
+				{RuleIndex: 1, RuleType: patternMatchRegexType, Action: ActionWarn,
+					Offsets: []Offset{{4, 7}}, Reason: `matched the pattern "[0-9]+" 1 time(s)`},
+				{RuleIndex: 2, RuleType: denyWordListType, Action: ActionBlock,
+					Offsets: []Offset{{8, 11}}, Reason: `found the denied word "bar" 1 time(s)`},
+			},
+		},
+		{
+			msg:   "it should return the offsets of denied statements",
+			rules: []any{map[string]any{"type": "sql_deny_statements", "statements": []string{"ddl"}}},
+			input: "SELECT 1; DROP TABLE users;",
+			want: []Explanation{{RuleIndex: 0, RuleType: sqlDenyStatementsType, Action: ActionBlock,
+				Offsets: []Offset{{10, 26}}, Reason: "the statement is classified as ddl"}},
+		},
+		{
+			msg:   "it should return the offsets of tables not allowed",
+			rules: []any{map[string]any{"type": "sql_allowed_tables", "tables": []string{"users"}}},
+			input: `SELECT * FROM users JOIN "Sales".orders o ON o.uid = users.id`,
+			want: []Explanation{{RuleIndex: 0, RuleType: sqlAllowedTablesType, Action: ActionBlock,
+				Offsets: []Offset{{25, 39}}, Reason: "the table sales.orders is not in the allowed tables or schemas"}},
+		},
+		{
+			msg:   "it should return the offsets of the statements exceeding the max",
+			rules: []any{map[string]any{"type": "sql_max_statements", "max_statements": 1}},
+			input: "SELECT 1; SELECT 2; SELECT 3",
+			want: []Explanation{{RuleIndex: 0, RuleType: sqlMaxStatementsType, Action: ActionBlock,
+				Offsets: []Offset{{10, 18}, {20, 28}}, Reason: "found 3 statements, the maximum allowed is 1"}},
+		},
+		{
+			msg:   "it should return the offsets of denied redis commands",
+			rules: []any{map[string]any{"type": "redis_deny_commands", "commands": []string{"FLUSHALL"}}},
+			input: "GET foo\r\n*1\r\n$8\r\nflushall\r\n",
+			want: []Explanation{{RuleIndex: 0, RuleType: redisDenyCommandsType, Action: ActionBlock,
+				Offsets: []Offset{{9, 27}}, Reason: "the command FLUSHALL is denied"}},
+		},
+		{
+			msg:   "it should return empty when there are no matches",
+			rules: []any{map[string]any{"type": "deny_words_list", "words": []string{"foo"}}},
+			input: "bar",
+			want:  nil,
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			got, err := Explain("input", map[string]any{"rules": tt.rules}, []byte(tt.input))
+			assert.Nil(t, err)
+			for i := range tt.want {
+				tt.want[i].StreamDirection = "input"
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
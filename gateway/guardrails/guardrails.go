@@ -4,19 +4,40 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
-	"strings"
 )
 
 const (
 	denyWordListType      string = "deny_words_list"
 	patternMatchRegexType string = "pattern_match"
+	sqlDenyStatementsType string = "sql_deny_statements"
+	sqlAllowedTablesType  string = "sql_allowed_tables"
+	sqlMaxStatementsType  string = "sql_max_statements"
+	redisDenyCommandsType string = "redis_deny_commands"
+
+	// ActionBlock rejects the input or output, it's the default action of a rule
+	ActionBlock string = "block"
+	// ActionWarn allows the input or output and records the match
+	ActionWarn string = "warn"
+	// ActionReview routes the input to a one time review
+	ActionReview string = "review"
+	// ActionMask replaces the matched content of the output
+	ActionMask string = "mask"
+
+	maskValue string = "[MASKED]"
 )
 
 type ErrRuleMatch struct {
 	streamDirection string
 	ruleType        string
+	action          string
+	reviewGroups    []string
 	words           []string
 	patternRegex    string
+	statementClass  string
+	table           string
+	statementCount  int
+	maxStatements   int
+	command         string
 }
 
 func (e ErrRuleMatch) Error() string {
@@ -27,10 +48,27 @@ func (e ErrRuleMatch) Error() string {
 	case patternMatchRegexType:
 		return fmt.Sprintf("validation error, match guard rails %v rule, type=%v, pattern=%v",
 			e.streamDirection, e.ruleType, e.patternRegex)
+	case sqlDenyStatementsType:
+		return fmt.Sprintf("validation error, match guard rails %v rule, type=%v, statement=%v",
+			e.streamDirection, e.ruleType, e.statementClass)
+	case sqlAllowedTablesType:
+		return fmt.Sprintf("validation error, match guard rails %v rule, type=%v, table=%v",
+			e.streamDirection, e.ruleType, e.table)
+	case sqlMaxStatementsType:
+		return fmt.Sprintf("validation error, match guard rails %v rule, type=%v, statements=%v, max=%v",
+			e.streamDirection, e.ruleType, e.statementCount, e.maxStatements)
+	case redisDenyCommandsType:
+		return fmt.Sprintf("validation error, match guard rails %v rule, type=%v, command=%v",
+			e.streamDirection, e.ruleType, e.command)
 	}
 	return fmt.Sprintf("validation error, match guard rails %v rule, type=%v", e.streamDirection, e.ruleType)
 }
 
+func (e ErrRuleMatch) StreamDirection() string { return e.streamDirection }
+func (e ErrRuleMatch) RuleType() string        { return e.ruleType }
+func (e ErrRuleMatch) Action() string          { return e.action }
+func (e ErrRuleMatch) ReviewGroups() []string  { return e.reviewGroups }
+
 type DataRules struct {
 	Items []Rule `json:"rules"`
 }
@@ -39,35 +77,120 @@ type Rule struct {
 	Type         string   `json:"type"`
 	Words        []string `json:"words"`
 	PatternRegex string   `json:"pattern_regex"`
+	// Action is what to do when the rule matches: block, warn, review or mask.
+	// Empty values defaults to block
+	Action string `json:"action,omitempty"`
+	// ReviewGroups are the groups approving the input when the action is review.
+	// It defaults to the reviewers of the connection
+	ReviewGroups []string `json:"review_groups,omitempty"`
+
+	// SQL rules attributes
+
+	// Dialect enables syntax specific to a database: postgres, mysql or mssql
+	Dialect string `json:"dialect,omitempty"`
+	// Statements are the statement classes denied by the sql_deny_statements rule type:
+	// ddl, truncate, grant, revoke, delete_without_where and update_without_where
+	Statements []string `json:"statements,omitempty"`
+	// Tables and Schemas are the relations allowed by the sql_allowed_tables rule type
+	Tables  []string `json:"tables,omitempty"`
+	Schemas []string `json:"schemas,omitempty"`
+	// MaxStatements is the maximum number of statements allowed by the sql_max_statements rule type
+	MaxStatements int `json:"max_statements,omitempty"`
+
+	// Redis rules attributes
+
+	// Commands are the commands denied by the redis_deny_commands rule type.
+	// A subcommand could be denied with its container command, e.g.: CONFIG SET
+	Commands []string `json:"commands,omitempty"`
 }
 
-func (r *Rule) validate(streamDirection string, data []byte) error {
+func (r *Rule) action() string {
+	if r.Action == "" {
+		return ActionBlock
+	}
+	return r.Action
+}
+
+// compile compiles the patterns of the rule, the word lists are turned into
+// an Aho-Corasick automaton and the regex is compiled once.
+func (r *Rule) compile() (*compiledRule, error) {
+	cr := &compiledRule{Rule: r}
 	switch r.Type {
 	case denyWordListType:
-		for _, word := range r.Words {
-			// skip empty rules
-			if word == "" {
-				continue
-			}
-			if !strings.Contains(string(data), word) {
-				continue
-			}
-			return &ErrRuleMatch{streamDirection: streamDirection, ruleType: r.Type, words: r.Words}
-		}
+		cr.words = newWordMatcher(r.Words)
 	case patternMatchRegexType:
 		// skip empty regex
 		if r.PatternRegex == "" {
-			return nil
+			return cr, nil
 		}
 		regex, err := regexp.Compile(r.PatternRegex)
 		if err != nil {
-			return fmt.Errorf("failed parsing regex, reason=%v", err)
+			return nil, fmt.Errorf("failed parsing regex, reason=%v", err)
 		}
-		if regex.Match(data) {
-			return &ErrRuleMatch{streamDirection: streamDirection, ruleType: r.Type, patternRegex: r.PatternRegex}
+		cr.regex = regex
+	case sqlDenyStatementsType, sqlAllowedTablesType, sqlMaxStatementsType, redisDenyCommandsType:
+	default:
+		return nil, fmt.Errorf("unknown rule type %q", r.Type)
+	}
+	return cr, nil
+}
+
+// validate compiles the rule and validates the data against it
+func (r *Rule) validate(streamDirection string, data []byte) error {
+	cr, err := r.compile()
+	if err != nil {
+		return err
+	}
+	return cr.validate(streamDirection, data)
+}
+
+type compiledRule struct {
+	*Rule
+	words *wordMatcher
+	regex *regexp.Regexp
+}
+
+// mask replaces the content matched by the rule
+func (r *compiledRule) mask(data []byte) ([]byte, error) {
+	switch r.Type {
+	case denyWordListType:
+		offsets := r.words.findAll(data)
+		if len(offsets) == 0 {
+			return data, nil
 		}
+		masked := make([]byte, 0, len(data))
+		pos := 0
+		for _, o := range offsets {
+			masked = append(masked, data[pos:o.Start]...)
+			masked = append(masked, maskValue...)
+			pos = o.End
+		}
+		data = append(masked, data[pos:]...)
+	case patternMatchRegexType:
+		if r.regex == nil {
+			return data, nil
+		}
+		data = r.regex.ReplaceAllLiteral(data, []byte(maskValue))
 	default:
-		return fmt.Errorf("unknown rule type %q", r.Type)
+		return nil, fmt.Errorf("mask action is not supported for rule type %q", r.Type)
+	}
+	return data, nil
+}
+
+func (r *compiledRule) validate(streamDirection string, data []byte) error {
+	switch r.Type {
+	case denyWordListType:
+		if r.words.match(data) {
+			return &ErrRuleMatch{streamDirection: streamDirection, ruleType: r.Type, words: r.Words}
+		}
+	case patternMatchRegexType:
+		if r.regex != nil && r.regex.Match(data) {
+			return &ErrRuleMatch{streamDirection: streamDirection, ruleType: r.Type, patternRegex: r.PatternRegex}
+		}
+	case sqlDenyStatementsType, sqlAllowedTablesType, sqlMaxStatementsType:
+		return r.validateSQL(streamDirection, data)
+	case redisDenyCommandsType:
+		return r.validateRedis(streamDirection, data)
 	}
 	return nil
 }
@@ -80,19 +203,169 @@ func Decode(data []byte) ([]DataRules, error) {
 	return dataRules, nil
 }
 
-func Validate(streamDirection string, ruleData, data []byte) error {
+// RuleSet are the rules of a connection compiled ahead of the evaluation
+type RuleSet struct {
+	rules []*compiledRule
+}
+
+func compile(ruleData []byte) (*RuleSet, error) {
+	rs := &RuleSet{}
 	if len(ruleData) == 0 {
-		return nil
+		return rs, nil
 	}
 	dataRules, err := Decode(ruleData)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	for _, dataRule := range dataRules {
-		for _, rule := range dataRule.Items {
-			if err := rule.validate(streamDirection, data); err != nil {
-				return err
+		for i := range dataRule.Items {
+			cr, err := dataRule.Items[i].compile()
+			if err != nil {
+				return nil, err
+			}
+			rs.rules = append(rs.rules, cr)
+		}
+	}
+	return rs, nil
+}
+
+// Validate returns the first rule matching the data regardless of its action
+func Validate(streamDirection string, ruleData, data []byte) error {
+	rs, err := Compile(ruleData)
+	if err != nil {
+		return err
+	}
+	return rs.Validate(streamDirection, data)
+}
+
+// Validate returns the first rule matching the data regardless of its action
+func (rs *RuleSet) Validate(streamDirection string, data []byte) error {
+	for _, rule := range rs.rules {
+		if err := rule.validate(streamDirection, data); err != nil {
+			if match, ok := err.(*ErrRuleMatch); ok {
+				match.action = rule.action()
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+type Result struct {
+	// Data is the content after applying the mask rules
+	Data []byte
+	// Warnings are the matches of the rules with the warn action
+	Warnings []*ErrRuleMatch
+	// Masks are the matches of the rules with the mask action
+	Masks []*ErrRuleMatch
+	// Review is the first match of a rule with the review action
+	Review *ErrRuleMatch
+}
+
+// Matches returns all non blocking matches of the result
+func (r *Result) Matches() []*ErrRuleMatch {
+	matches := append([]*ErrRuleMatch{}, r.Warnings...)
+	matches = append(matches, r.Masks...)
+	if r.Review != nil {
+		matches = append(matches, r.Review)
+	}
+	return matches
+}
+
+// Evaluate validates the data against all rules applying the action of each matched rule.
+// It returns an *ErrRuleMatch error when a rule with the block action matches.
+func Evaluate(streamDirection string, ruleData, data []byte) (*Result, error) {
+	rs, err := Compile(ruleData)
+	if err != nil {
+		return nil, err
+	}
+	return rs.Evaluate(streamDirection, data)
+}
+
+// Evaluate validates the data against all rules applying the action of each matched rule.
+// It returns an *ErrRuleMatch error when a rule with the block action matches.
+func (rs *RuleSet) Evaluate(streamDirection string, data []byte) (*Result, error) {
+	result := &Result{Data: data}
+	for _, rule := range rs.rules {
+		err := rule.validate(streamDirection, result.Data)
+		match, ok := err.(*ErrRuleMatch)
+		if !ok {
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		match.action = rule.action()
+		switch match.action {
+		case ActionWarn:
+			result.Warnings = append(result.Warnings, match)
+		case ActionReview:
+			match.reviewGroups = rule.ReviewGroups
+			if result.Review == nil {
+				result.Review = match
+			}
+		case ActionMask:
+			result.Data, err = rule.mask(result.Data)
+			if err != nil {
+				return nil, err
+			}
+			result.Masks = append(result.Masks, match)
+		default:
+			return nil, match
+		}
+	}
+	return result, nil
+}
+
+// ValidateRules validates the rules of a direction (input or output) before persisting it
+func ValidateRules(streamDirection string, rules map[string]any) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	dataRules, err := decodeRules(streamDirection, rules)
+	if err != nil {
+		return err
+	}
+	for _, rule := range dataRules.Items {
+		switch rule.Type {
+		case denyWordListType, sqlDenyStatementsType, sqlAllowedTablesType, sqlMaxStatementsType:
+		case redisDenyCommandsType:
+			if len(rule.Commands) == 0 {
+				return fmt.Errorf("rule type %q requires at least one command", rule.Type)
+			}
+		case patternMatchRegexType:
+			if _, err := regexp.Compile(rule.PatternRegex); err != nil {
+				return fmt.Errorf("failed parsing regex %q, reason=%v", rule.PatternRegex, err)
+			}
+		default:
+			return fmt.Errorf("unknown rule type %q", rule.Type)
+		}
+		switch rule.Dialect {
+		case "", sqlDialectPostgres, sqlDialectMySQL, sqlDialectMSSQL:
+		default:
+			return fmt.Errorf("unknown sql dialect %q, accepted values are: %v, %v, %v",
+				rule.Dialect, sqlDialectPostgres, sqlDialectMySQL, sqlDialectMSSQL)
+		}
+		for _, class := range rule.Statements {
+			if _, ok := sqlStatementClasses[class]; !ok {
+				return fmt.Errorf("unknown sql statement class %q", class)
+			}
+		}
+		switch rule.action() {
+		case ActionBlock, ActionWarn:
+		case ActionReview:
+			if streamDirection != "input" {
+				return fmt.Errorf("review action is only supported for input rules")
+			}
+		case ActionMask:
+			if streamDirection != "output" {
+				return fmt.Errorf("mask action is only supported for output rules")
+			}
+			if rule.Type != denyWordListType && rule.Type != patternMatchRegexType {
+				return fmt.Errorf("mask action is not supported for rule type %q", rule.Type)
 			}
+		default:
+			return fmt.Errorf("unknown rule action %q", rule.Action)
 		}
 	}
 	return nil
@@ -1,7 +1,10 @@
 package guardrails
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -57,3 +60,266 @@ func TestGuardRailRules(t *testing.T) {
 	}
 
 }
+
+func TestEvaluateActions(t *testing.T) {
+	for _, tt := range []struct {
+		msg          string
+		rules        string
+		input        string
+		wantData     string
+		wantWarnings int
+		wantMasks    int
+		wantReview   bool
+		err          string
+	}{
+		{
+			msg:      "it should block by default",
+			rules:    `[{"rules": [{"type": "deny_words_list", "words": ["foo"]}]}]`,
+			input:    "foo",
+			err:      "validation error, match guard rails output rule, type=deny_words_list, words=[foo]",
+			wantData: "",
+		},
+		{
+			msg:          "it should allow the data when the rule has the warn action",
+			rules:        `[{"rules": [{"type": "deny_words_list", "words": ["foo"], "action": "warn"}]}]`,
+			input:        "foo bar",
+			wantData:     "foo bar",
+			wantWarnings: 1,
+		},
+		{
+			msg:        "it should return the review match",
+			rules:      `[{"rules": [{"type": "sql_deny_statements", "statements": ["ddl"], "action": "review", "review_groups": ["dba"]}]}]`,
+			input:      "DROP TABLE users",
+			wantData:   "DROP TABLE users",
+			wantReview: true,
+		},
+		{
+			msg:       "it should mask words and patterns",
+			rules:     `[{"rules": [{"type": "deny_words_list", "words": ["secret"], "action": "mask"}, {"type": "pattern_match", "pattern_regex": "[0-9]{3}-[0-9]{4}", "action": "mask"}]}]`,
+			input:     "secret: 555-1234",
+			wantData:  "[MASKED]: [MASKED]",
+			wantMasks: 2,
+		},
+		{
+			msg:      "it should block after applying the mask rules",
+			rules:    `[{"rules": [{"type": "deny_words_list", "words": ["foo"], "action": "mask"}, {"type": "deny_words_list", "words": ["bar"]}]}]`,
+			input:    "foo bar",
+			err:      "validation error, match guard rails output rule, type=deny_words_list, words=[bar]",
+			wantData: "",
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			result, err := Evaluate("output", []byte(tt.rules), []byte(tt.input))
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.wantData, string(result.Data))
+			assert.Len(t, result.Warnings, tt.wantWarnings)
+			assert.Len(t, result.Masks, tt.wantMasks)
+			assert.Equal(t, tt.wantReview, result.Review != nil)
+			if result.Review != nil {
+				assert.Equal(t, []string{"dba"}, result.Review.ReviewGroups())
+			}
+		})
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	for _, tt := range []struct {
+		msg             string
+		streamDirection string
+		rules           map[string]any
+		err             string
+	}{
+		{
+			msg:             "it should accept valid rules",
+			streamDirection: "output",
+			rules: map[string]any{"rules": []any{
+				map[string]any{"type": "pattern_match", "pattern_regex": "[0-9]+", "action": "mask"},
+				map[string]any{"type": "deny_words_list", "words": []string{"foo"}, "action": "warn"},
+			}},
+		},
+		{
+			msg:             "it should return error with unknown rule types",
+			streamDirection: "input",
+			rules:           map[string]any{"rules": []any{map[string]any{"type": "unknown"}}},
+			err:             `unknown rule type "unknown"`,
+		},
+		{
+			msg:             "it should return error with invalid regex",
+			streamDirection: "input",
+			rules:           map[string]any{"rules": []any{map[string]any{"type": "pattern_match", "pattern_regex": "[a-"}}},
+			err:             "failed parsing regex \"[a-\", reason=error parsing regexp: missing closing ]: `[a-`",
+		},
+		{
+			msg:             "it should return error with unknown sql dialects",
+			streamDirection: "input",
+			rules:           map[string]any{"rules": []any{map[string]any{"type": "sql_deny_statements", "dialect": "oracle", "statements": []string{"ddl"}}}},
+			err:             `unknown sql dialect "oracle", accepted values are: postgres, mysql, mssql`,
+		},
+		{
+			msg:             "it should return error with unknown actions",
+			streamDirection: "input",
+			rules:           map[string]any{"rules": []any{map[string]any{"type": "deny_words_list", "action": "drop"}}},
+			err:             `unknown rule action "drop"`,
+		},
+		{
+			msg:             "it should only allow the review action for input rules",
+			streamDirection: "output",
+			rules:           map[string]any{"rules": []any{map[string]any{"type": "deny_words_list", "action": "review"}}},
+			err:             "review action is only supported for input rules",
+		},
+		{
+			msg:             "it should only allow the mask action for output rules",
+			streamDirection: "input",
+			rules:           map[string]any{"rules": []any{map[string]any{"type": "deny_words_list", "action": "mask"}}},
+			err:             "mask action is only supported for output rules",
+		},
+		{
+			msg:             "it should not allow the mask action for sql rules",
+			streamDirection: "output",
+			rules:           map[string]any{"rules": []any{map[string]any{"type": "sql_max_statements", "action": "mask"}}},
+			err:             `mask action is not supported for rule type "sql_max_statements"`,
+		},
+		{
+			msg:             "it should return error with redis rules without commands",
+			streamDirection: "input",
+			rules:           map[string]any{"rules": []any{map[string]any{"type": "redis_deny_commands"}}},
+			err:             `rule type "redis_deny_commands" requires at least one command`,
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			err := ValidateRules(tt.streamDirection, tt.rules)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestCompileCache(t *testing.T) {
+	ruleData := []byte(`[{"rules": [{"type": "deny_words_list", "words": ["foo"]}]}]`)
+	rs1, err := Compile(ruleData)
+	assert.Nil(t, err)
+	rs2, err := Compile(ruleData)
+	assert.Nil(t, err)
+	assert.Same(t, rs1, rs2, "it should return the cached rule set")
+
+	generation := CacheGeneration()
+	InvalidateCache()
+	assert.Equal(t, generation+1, CacheGeneration())
+	rs3, err := Compile(ruleData)
+	assert.Nil(t, err)
+	assert.NotSame(t, rs1, rs3, "it should compile the rule set again after invalidating the cache")
+
+	_, err = Compile([]byte(`[{"rules": [{"type": "pattern_match", "pattern_regex": "[a-"}]}]`))
+	assert.EqualError(t, err, "failed parsing regex, reason=error parsing regexp: missing closing ]: `[a-`")
+}
+
+func newBenchmarkRules(b *testing.B, action string) []byte {
+	var words []string
+	for i := 0; i < 100; i++ {
+		words = append(words, fmt.Sprintf("secret-word-%03d", i))
+	}
+	ruleData, err := json.Marshal([]DataRules{{Items: []Rule{
+		{Type: denyWordListType, Words: words, Action: action},
+		{Type: patternMatchRegexType, PatternRegex: `[0-9]{3}-[0-9]{2}-[0-9]{4}`, Action: action},
+	}}})
+	if err != nil {
+		b.Fatal(err)
+	}
+	return ruleData
+}
+
+// newBenchmarkPayload returns a payload without matches, the worst case for the rules
+func newBenchmarkPayload(size int) []byte {
+	line := []byte("id=42, name=john doe, email=john.doe@example.com, status=active\n")
+	return bytes.Repeat(line, size/len(line)+1)[:size]
+}
+
+func BenchmarkEvaluate(b *testing.B) {
+	for _, size := range []int{1 << 10, 64 << 10, 1 << 20} {
+		payload := newBenchmarkPayload(size)
+		ruleData := newBenchmarkRules(b, ActionBlock)
+		b.Run(fmt.Sprintf("uncached/%vKB", size>>10), func(b *testing.B) {
+			b.SetBytes(int64(size))
+			for i := 0; i < b.N; i++ {
+				rs, err := compile(ruleData)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if _, err := rs.Evaluate("output", payload); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("cached/%vKB", size>>10), func(b *testing.B) {
+			b.SetBytes(int64(size))
+			for i := 0; i < b.N; i++ {
+				if _, err := Evaluate("output", ruleData, payload); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("compiled/%vKB", size>>10), func(b *testing.B) {
+			rs, err := Compile(ruleData)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.SetBytes(int64(size))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := rs.Evaluate("output", payload); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkEvaluateMask(b *testing.B) {
+	payload := bytes.Repeat([]byte("user secret-word-042 ssn 123-45-6789\n"), (1<<20)/37)
+	rs, err := Compile(newBenchmarkRules(b, ActionMask))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(payload)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := rs.Evaluate("output", payload); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDenyWords(b *testing.B) {
+	var words []string
+	for i := 0; i < 100; i++ {
+		words = append(words, fmt.Sprintf("secret-word-%03d", i))
+	}
+	payload := newBenchmarkPayload(1 << 20)
+	b.Run("contains", func(b *testing.B) {
+		b.SetBytes(int64(len(payload)))
+		for i := 0; i < b.N; i++ {
+			for _, word := range words {
+				if strings.Contains(string(payload), word) {
+					b.Fatal("unexpected match")
+				}
+			}
+		}
+	})
+	b.Run("automaton", func(b *testing.B) {
+		m := newWordMatcher(words)
+		b.SetBytes(int64(len(payload)))
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if m.match(payload) {
+				b.Fatal("unexpected match")
+			}
+		}
+	})
+}
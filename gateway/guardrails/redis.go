@@ -0,0 +1,100 @@
+package guardrails
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/hoophq/hoop/common/redistypes"
+)
+
+// redisCommand is a command decoded from the data and its position in it
+type redisCommand struct {
+	cmd   redistypes.Command
+	start int
+	end   int
+}
+
+// countingReader counts the bytes read from the underlying reader
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
+// parseRedisCommands decodes the commands of the data, it accepts the RESP
+// format sent by the clients and inline commands typed by users.
+// Data that isn't a valid command is ignored, the redis server would reject it.
+func parseRedisCommands(data []byte) []redisCommand {
+	cr := &countingReader{r: bytes.NewReader(data)}
+	r := bufio.NewReader(cr)
+	var commands []redisCommand
+	for {
+		start := cr.n - r.Buffered()
+		raw, err := redistypes.ReadCommand(r)
+		if err != nil {
+			return commands
+		}
+		end := cr.n - r.Buffered()
+		cmds, err := redistypes.DecodeCommands(raw)
+		if err != nil {
+			continue
+		}
+		for _, cmd := range cmds {
+			commands = append(commands, redisCommand{cmd: cmd, start: start, end: end})
+		}
+	}
+}
+
+// redisScriptingCommands run scripts and functions on the server, the commands
+// called by them are not sent by the client and can't be validated
+var redisScriptingCommands = []string{"EVAL", "EVAL_RO", "EVALSHA", "EVALSHA_RO", "FCALL", "FCALL_RO"}
+
+// deniedRedisCommand returns the entry of the deny list matching the command.
+// The entries are command names or container commands with a subcommand, e.g.: FLUSHALL, CONFIG SET.
+// The scripting commands are always denied, a script could call any of the denied commands.
+func (r *Rule) deniedRedisCommand(cmd redistypes.Command) string {
+	name := cmd.Name()
+	var subcommand string
+	if len(cmd) > 1 {
+		subcommand = name + " " + strings.ToUpper(cmd[1])
+	}
+	for _, denied := range r.Commands {
+		denied = strings.ToUpper(strings.Join(strings.Fields(denied), " "))
+		if denied != "" && (denied == name || denied == subcommand) {
+			return denied
+		}
+	}
+	if slices.Contains(redisScriptingCommands, name) {
+		return name
+	}
+	return ""
+}
+
+func (r *Rule) validateRedis(streamDirection string, data []byte) error {
+	for _, c := range parseRedisCommands(data) {
+		if denied := r.deniedRedisCommand(c.cmd); denied != "" {
+			return &ErrRuleMatch{streamDirection: streamDirection, ruleType: r.Type, command: denied}
+		}
+	}
+	return nil
+}
+
+func (r *Rule) explainRedis(data []byte) []Explanation {
+	var items []Explanation
+	for _, c := range parseRedisCommands(data) {
+		if denied := r.deniedRedisCommand(c.cmd); denied != "" {
+			items = append(items, Explanation{Offsets: []Offset{{Start: c.start, End: c.end}},
+				Reason: fmt.Sprintf("the command %v is denied", denied)})
+		}
+	}
+	return items
+}
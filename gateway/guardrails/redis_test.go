@@ -0,0 +1,72 @@
+package guardrails
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisGuardRailRules(t *testing.T) {
+	for _, tt := range []struct {
+		msg   string
+		rule  *Rule
+		input string
+		err   error
+	}{
+		{
+			msg:   "it should match denied commands sent by clients",
+			rule:  &Rule{Type: redisDenyCommandsType, Commands: []string{"flushall"}},
+			input: "*2\r\n$3\r\nGET\r\n$3\r\nfoo\r\n*1\r\n$8\r\nFlushAll\r\n",
+			err: fmt.Errorf("validation error, match guard rails <dunno> rule, type=%v, command=FLUSHALL",
+				redisDenyCommandsType),
+		},
+		{
+			msg:   "it should match denied inline commands",
+			rule:  &Rule{Type: redisDenyCommandsType, Commands: []string{"DEL"}},
+			input: "GET foo\ndel foo\n",
+			err: fmt.Errorf("validation error, match guard rails <dunno> rule, type=%v, command=DEL",
+				redisDenyCommandsType),
+		},
+		{
+			msg:   "it should match denied subcommands",
+			rule:  &Rule{Type: redisDenyCommandsType, Commands: []string{"config  set"}},
+			input: "CONFIG SET maxmemory 10mb",
+			err: fmt.Errorf("validation error, match guard rails <dunno> rule, type=%v, command=CONFIG SET",
+				redisDenyCommandsType),
+		},
+		{
+			msg:   "it should allow other subcommands",
+			rule:  &Rule{Type: redisDenyCommandsType, Commands: []string{"CONFIG SET"}},
+			input: "CONFIG GET maxmemory",
+		},
+		{
+			msg:   "it should not match denied commands in arguments",
+			rule:  &Rule{Type: redisDenyCommandsType, Commands: []string{"FLUSHALL"}},
+			input: "*3\r\n$3\r\nSET\r\n$3\r\nfoo\r\n$8\r\nFLUSHALL\r\n",
+		},
+		{
+			msg:   "it should deny scripts, they could call denied commands",
+			rule:  &Rule{Type: redisDenyCommandsType, Commands: []string{"FLUSHALL"}},
+			input: "EVAL \"return redis.call('FLUSHALL')\" 0",
+			err: fmt.Errorf("validation error, match guard rails <dunno> rule, type=%v, command=EVAL",
+				redisDenyCommandsType),
+		},
+		{
+			msg:   "it should deny functions, they could call denied commands",
+			rule:  &Rule{Type: redisDenyCommandsType, Commands: []string{"FLUSHALL"}},
+			input: "*3\r\n$5\r\nfcall\r\n$5\r\nflush\r\n$1\r\n0\r\n",
+			err: fmt.Errorf("validation error, match guard rails <dunno> rule, type=%v, command=FCALL",
+				redisDenyCommandsType),
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			err := tt.rule.validate("<dunno>", []byte(tt.input))
+			if err != nil {
+				assert.EqualError(t, err, tt.err.Error())
+				return
+			}
+			assert.Nil(t, tt.err)
+		})
+	}
+}
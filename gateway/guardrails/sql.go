@@ -0,0 +1,614 @@
+package guardrails
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	sqlDialectPostgres string = "postgres"
+	sqlDialectMySQL    string = "mysql"
+	sqlDialectMSSQL    string = "mssql"
+
+	sqlClassDDL                string = "ddl"
+	sqlClassTruncate           string = "truncate"
+	sqlClassGrant              string = "grant"
+	sqlClassRevoke             string = "revoke"
+	sqlClassDeleteWithoutWhere string = "delete_without_where"
+	sqlClassUpdateWithoutWhere string = "update_without_where"
+)
+
+var sqlStatementClasses = map[string]struct{}{
+	sqlClassDDL:                {},
+	sqlClassTruncate:           {},
+	sqlClassGrant:              {},
+	sqlClassRevoke:             {},
+	sqlClassDeleteWithoutWhere: {},
+	sqlClassUpdateWithoutWhere: {},
+}
+
+type sqlTokenType int
+
+const (
+	sqlTokenWord sqlTokenType = iota
+	sqlTokenQuotedIdent
+	sqlTokenString
+	sqlTokenPunct
+	sqlTokenOther
+)
+
+type sqlToken struct {
+	typ sqlTokenType
+	// value contains the upper case representation of words,
+	// the unquoted value of identifiers or the raw value of anything else
+	value string
+	// start and end are the byte offsets of the token in the input
+	start, end int
+}
+
+func (t sqlToken) isWord(words ...string) bool {
+	if t.typ != sqlTokenWord {
+		return false
+	}
+	for _, w := range words {
+		if t.value == w {
+			return true
+		}
+	}
+	return false
+}
+
+func (t sqlToken) isPunct(p string) bool { return t.typ == sqlTokenPunct && t.value == p }
+
+func (t sqlToken) isIdent() bool { return t.typ == sqlTokenWord || t.typ == sqlTokenQuotedIdent }
+
+type sqlStatement []sqlToken
+
+// sqlTable is a relation referenced by a statement
+type sqlTable struct {
+	// parts are the lower case parts of the qualified name, e.g.: [schema, table]
+	parts []string
+	// start and end are the byte offsets of the name in the input
+	start, end int
+}
+
+// span returns the byte offsets of the statement in the input
+func (s sqlStatement) span() (int, int) {
+	if len(s) == 0 {
+		return 0, 0
+	}
+	return s[0].start, s[len(s)-1].end
+}
+
+// parseSQL split the input into statements ignoring comments, string literals and quoted identifiers.
+// The dialect enables specific syntax: mysql hash comments, mssql bracket identifiers and
+// the GO batch separator. An empty dialect handles the common syntax, including postgres dollar quotes.
+func parseSQL(dialect string, data []byte) []sqlStatement {
+	var stmts []sqlStatement
+	var stmt sqlStatement
+	flush := func() {
+		if len(stmt) > 0 {
+			stmts = append(stmts, stmt)
+		}
+		stmt = nil
+	}
+	input := string(data)
+	lineStart := true
+	for i := 0; i < len(input); {
+		ch := input[i]
+		switch {
+		case ch == '\n':
+			lineStart = true
+			i++
+			continue
+		case ch == ' ' || ch == '\t' || ch == '\r' || ch == '\f' || ch == '\v':
+			i++
+			continue
+		case ch == '-' && strings.HasPrefix(input[i:], "--"),
+			ch == '#' && dialect == sqlDialectMySQL:
+			for i < len(input) && input[i] != '\n' {
+				i++
+			}
+			continue
+		case ch == '/' && strings.HasPrefix(input[i:], "/*"):
+			i = skipBlockComment(input, i)
+			continue
+		}
+		isLineStart := lineStart
+		lineStart = false
+		switch {
+		case ch == ';':
+			flush()
+			i++
+		case ch == '\'':
+			// backslash escapes are enabled by default only in mysql, in postgres it requires the E prefix
+			escape := dialect == sqlDialectMySQL || (len(stmt) > 0 && stmt[len(stmt)-1].isWord("E"))
+			end := skipQuoted(input, i, '\'', escape)
+			stmt = append(stmt, sqlToken{typ: sqlTokenString, value: input[i:end], start: i, end: end})
+			i = end
+		case ch == '"', ch == '`':
+			end := skipQuoted(input, i, ch, false)
+			stmt = append(stmt, sqlToken{typ: sqlTokenQuotedIdent, value: unquoteIdent(input[i:end], ch, ch), start: i, end: end})
+			i = end
+		case ch == '[' && dialect == sqlDialectMSSQL:
+			end := skipQuoted(input, i, ']', false)
+			stmt = append(stmt, sqlToken{typ: sqlTokenQuotedIdent, value: unquoteIdent(input[i:end], '[', ']'), start: i, end: end})
+			i = end
+		case ch == '$' && dialect != sqlDialectMySQL && dialect != sqlDialectMSSQL:
+			if end, ok := skipDollarQuoted(input, i); ok {
+				stmt = append(stmt, sqlToken{typ: sqlTokenString, value: input[i:end], start: i, end: end})
+				i = end
+				continue
+			}
+			stmt = append(stmt, sqlToken{typ: sqlTokenOther, value: "$", start: i, end: i + 1})
+			i++
+		case isSQLWordStart(input, i):
+			end := i
+			for end < len(input) {
+				r, size := utf8.DecodeRuneInString(input[end:])
+				if !isSQLWordRune(r) {
+					break
+				}
+				end += size
+			}
+			start := i
+			word := strings.ToUpper(input[i:end])
+			i = end
+			if dialect == sqlDialectMSSQL && word == "GO" && isLineStart && restOfLineIsEmpty(input, i) {
+				flush()
+				continue
+			}
+			stmt = append(stmt, sqlToken{typ: sqlTokenWord, value: word, start: start, end: end})
+		case strings.ContainsRune("(),.", rune(ch)):
+			stmt = append(stmt, sqlToken{typ: sqlTokenPunct, value: string(ch), start: i, end: i + 1})
+			i++
+		default:
+			_, size := utf8.DecodeRuneInString(input[i:])
+			stmt = append(stmt, sqlToken{typ: sqlTokenOther, value: input[i : i+size], start: i, end: i + size})
+			i += size
+		}
+	}
+	flush()
+	return stmts
+}
+
+func isSQLWordStart(input string, i int) bool {
+	r, _ := utf8.DecodeRuneInString(input[i:])
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isSQLWordRune(r rune) bool {
+	return r == '_' || r == '$' || r == '@' || r == '#' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func restOfLineIsEmpty(input string, i int) bool {
+	for ; i < len(input) && input[i] != '\n'; i++ {
+		if !unicode.IsSpace(rune(input[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// skipBlockComment returns the position after the end of a (possibly nested) block comment
+func skipBlockComment(input string, i int) int {
+	depth := 0
+	for i < len(input) {
+		switch {
+		case strings.HasPrefix(input[i:], "/*"):
+			depth++
+			i += 2
+		case strings.HasPrefix(input[i:], "*/"):
+			depth--
+			i += 2
+			if depth == 0 {
+				return i
+			}
+		default:
+			i++
+		}
+	}
+	return i
+}
+
+// skipQuoted returns the position after the closing quote, a doubled closing quote is treated as escaped.
+func skipQuoted(input string, i int, closeCh byte, backslashEscape bool) int {
+	for i++; i < len(input); i++ {
+		switch {
+		case input[i] == '\\' && backslashEscape:
+			i++
+		case input[i] == closeCh:
+			if i+1 < len(input) && input[i+1] == closeCh {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(input)
+}
+
+func skipDollarQuoted(input string, i int) (int, bool) {
+	end := strings.IndexByte(input[i+1:], '$')
+	if end == -1 {
+		return 0, false
+	}
+	tag := input[i : i+end+2]
+	for idx, r := range tag[1 : len(tag)-1] {
+		if !(r == '_' || unicode.IsLetter(r) || (idx > 0 && unicode.IsDigit(r))) {
+			return 0, false
+		}
+	}
+	body := i + len(tag)
+	closeIdx := strings.Index(input[body:], tag)
+	if closeIdx == -1 {
+		return len(input), true
+	}
+	return body + closeIdx + len(tag), true
+}
+
+func unquoteIdent(v string, openCh, closeCh byte) string {
+	v = strings.TrimPrefix(v, string(openCh))
+	v = strings.TrimSuffix(v, string(closeCh))
+	return strings.ReplaceAll(v, string([]byte{closeCh, closeCh}), string(closeCh))
+}
+
+// verbIndex returns the position of the main verb of the statement,
+// skipping common table expressions and explain prefixes.
+func (s sqlStatement) verbIndex() int {
+	if len(s) == 0 || !s[0].isWord("WITH", "EXPLAIN") {
+		return 0
+	}
+	depth := 0
+	for i, tk := range s[1:] {
+		switch {
+		case tk.isPunct("("):
+			depth++
+		case tk.isPunct(")"):
+			depth--
+		case depth == 0 && tk.isWord("SELECT", "INSERT", "UPDATE", "DELETE", "MERGE", "VALUES", "TABLE"):
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// class returns the denied statement class of the statement or an empty string.
+// Common table expressions are classified as well, they could modify data in postgres,
+// e.g.: WITH d AS (DELETE FROM users RETURNING *) SELECT * FROM d
+func (s sqlStatement) class() string {
+	for _, body := range s.cteBodies() {
+		if class := body.class(); class != "" {
+			return class
+		}
+	}
+	idx := s.verbIndex()
+	if idx >= len(s) || s[idx].typ != sqlTokenWord {
+		return ""
+	}
+	switch s[idx].value {
+	case "CREATE", "ALTER", "DROP", "RENAME", "COMMENT":
+		return sqlClassDDL
+	case "TRUNCATE":
+		return sqlClassTruncate
+	case "GRANT":
+		return sqlClassGrant
+	case "REVOKE":
+		return sqlClassRevoke
+	case "DELETE":
+		if !s.hasTopLevelWhere(idx) {
+			return sqlClassDeleteWithoutWhere
+		}
+	case "UPDATE":
+		if !s.hasTopLevelWhere(idx) {
+			return sqlClassUpdateWithoutWhere
+		}
+	}
+	return ""
+}
+
+func (s sqlStatement) hasTopLevelWhere(from int) bool {
+	depth := 0
+	for _, tk := range s[from:] {
+		switch {
+		case tk.isPunct("("):
+			depth++
+		case tk.isPunct(")"):
+			depth--
+		case depth == 0 && tk.isWord("WHERE"):
+			return true
+		}
+	}
+	return false
+}
+
+// cteNames returns the names defined by common table expressions
+func (s sqlStatement) cteNames() map[string]struct{} {
+	names := map[string]struct{}{}
+	if len(s) == 0 || !s[0].isWord("WITH") {
+		return names
+	}
+	depth := 0
+	for i := 1; i < s.verbIndex(); i++ {
+		tk := s[i]
+		switch {
+		case tk.isPunct("("):
+			depth++
+			continue
+		case tk.isPunct(")"):
+			depth--
+			continue
+		}
+		if depth != 0 || !tk.isIdent() || i+1 >= len(s) {
+			continue
+		}
+		next := i + 1
+		if s[next].isPunct("(") {
+			next = s.closingParen(next) + 1
+		}
+		if next < len(s) && s[next].isWord("AS") {
+			names[strings.ToLower(tk.value)] = struct{}{}
+		}
+	}
+	return names
+}
+
+// cteBodies returns the statements of the common table expressions,
+// e.g.: WITH name [(columns)] AS [[NOT] MATERIALIZED] (<body>)
+func (s sqlStatement) cteBodies() []sqlStatement {
+	if len(s) == 0 || !s[0].isWord("WITH") {
+		return nil
+	}
+	end := s.verbIndex()
+	if end == 0 {
+		end = len(s)
+	}
+	var bodies []sqlStatement
+	for i := 1; i < end; i++ {
+		switch {
+		case s[i].isWord("AS"):
+			next := i + 1
+			for next < end && s[next].isWord("NOT", "MATERIALIZED") {
+				next++
+			}
+			if next < end && s[next].isPunct("(") {
+				closeIdx := s.closingParen(next)
+				bodies = append(bodies, s[next+1:closeIdx])
+				i = closeIdx
+			}
+		case s[i].isPunct("("):
+			// the list of columns of the expression
+			i = s.closingParen(i)
+		}
+	}
+	return bodies
+}
+
+func (s sqlStatement) closingParen(i int) int {
+	depth := 0
+	for ; i < len(s); i++ {
+		switch {
+		case s[i].isPunct("("):
+			depth++
+		case s[i].isPunct(")"):
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return i
+}
+
+// tables returns the (possibly qualified) names of the relations referenced by the statement,
+// each name is returned as a list of lower case parts, e.g.: public.users => [public users]
+func (s sqlStatement) tables() []sqlTable {
+	var tables []sqlTable
+	ctes := s.cteNames()
+	isRevoke := len(s) > 0 && s[0].isWord("REVOKE")
+	// the word preceding each open parenthesis, used to skip
+	// function calls that accepts the FROM keyword, e.g.: EXTRACT(YEAR FROM col)
+	var parenStack []string
+	for i := 0; i < len(s); i++ {
+		tk := s[i]
+		switch {
+		case tk.isPunct("("):
+			var prev string
+			if i > 0 && s[i-1].typ == sqlTokenWord {
+				prev = s[i-1].value
+			}
+			parenStack = append(parenStack, prev)
+			continue
+		case tk.isPunct(")"):
+			if len(parenStack) > 0 {
+				parenStack = parenStack[:len(parenStack)-1]
+			}
+			continue
+		}
+		if !tk.isWord("FROM", "JOIN", "UPDATE", "INTO", "TABLE", "TRUNCATE", "USING", "COPY") {
+			continue
+		}
+		if len(parenStack) > 0 && isSQLFunctionWithFrom(parenStack[len(parenStack)-1]) {
+			continue
+		}
+		var prev sqlToken
+		if i > 0 {
+			prev = s[i-1]
+		}
+		switch {
+		// REVOKE ... FROM role
+		case tk.isWord("FROM") && isRevoke,
+			// a IS DISTINCT FROM b
+			tk.isWord("FROM") && prev.isWord("DISTINCT"),
+			// SELECT ... FOR UPDATE, ON DUPLICATE KEY UPDATE
+			tk.isWord("UPDATE") && prev.isWord("FOR", "KEY"):
+			continue
+		}
+		// ALTER TABLE, DROP TABLE, TRUNCATE TABLE, etc are handled by the TABLE keyword
+		if tk.isWord("TRUNCATE") && i+1 < len(s) && s[i+1].isWord("TABLE") {
+			continue
+		}
+		for {
+			// table functions are only allowed in the from clause, e.g.: FROM generate_series(1, 10)
+			allowFunc := tk.isWord("FROM", "JOIN", "USING")
+			table, next := s.parseQualifiedName(i+1, allowFunc)
+			if len(table.parts) == 0 {
+				break
+			}
+			if _, isCte := ctes[table.parts[len(table.parts)-1]]; !(isCte && len(table.parts) == 1) {
+				tables = append(tables, table)
+			}
+			next = s.skipAlias(next)
+			// FROM a, b and TRUNCATE a, b
+			if next < len(s) && s[next].isPunct(",") && tk.isWord("FROM", "TRUNCATE", "TABLE", "USING", "UPDATE") {
+				i = next
+				continue
+			}
+			i = next - 1
+			break
+		}
+	}
+	return tables
+}
+
+func isSQLFunctionWithFrom(name string) bool {
+	switch name {
+	case "EXTRACT", "SUBSTRING", "TRIM", "POSITION", "OVERLAY":
+		return true
+	}
+	return false
+}
+
+// parseQualifiedName parses a name in the form of part[.part...] skipping
+// modifiers keywords like ONLY and IF [NOT] EXISTS.
+func (s sqlStatement) parseQualifiedName(i int, allowFunc bool) (sqlTable, int) {
+	for i < len(s) && s[i].isWord("ONLY", "LATERAL", "IF", "NOT", "EXISTS", "IGNORE", "LOW_PRIORITY", "QUICK", "TOP") {
+		i++
+	}
+	var table sqlTable
+	for i < len(s) {
+		tk := s[i]
+		if !tk.isIdent() || (tk.typ == sqlTokenWord && isSQLReservedWord(tk.value)) {
+			break
+		}
+		if len(table.parts) == 0 {
+			table.start = tk.start
+		}
+		table.parts = append(table.parts, strings.ToLower(tk.value))
+		table.end = tk.end
+		i++
+		if i < len(s) && s[i].isPunct(".") {
+			i++
+			continue
+		}
+		break
+	}
+	if allowFunc && i < len(s) && s[i].isPunct("(") && len(table.parts) > 0 {
+		return sqlTable{}, i
+	}
+	return table, i
+}
+
+func (s sqlStatement) skipAlias(i int) int {
+	if i < len(s) && s[i].isWord("AS") {
+		i++
+	}
+	if i < len(s) && s[i].isIdent() && !(s[i].typ == sqlTokenWord && isSQLReservedWord(s[i].value)) {
+		i++
+	}
+	return i
+}
+
+func isSQLReservedWord(word string) bool {
+	switch word {
+	case "SELECT", "FROM", "WHERE", "JOIN", "INNER", "LEFT", "RIGHT", "FULL", "OUTER", "CROSS", "NATURAL",
+		"ON", "USING", "GROUP", "ORDER", "HAVING", "LIMIT", "OFFSET", "UNION", "INTERSECT", "EXCEPT",
+		"SET", "VALUES", "RETURNING", "AS", "WINDOW", "FOR", "FETCH", "DEFAULT", "OUTPUT", "WITH",
+		"CASCADE", "RESTRICT", "RESTART", "CONTINUE", "IDENTITY", "ADD", "COLUMN", "RENAME", "TO",
+		"PARTITION", "INTO", "AND", "OR", "NOT", "ONLY", "LATERAL", "TABLE", "UPDATE", "DELETE":
+		return true
+	}
+	return false
+}
+
+func (r *Rule) validateSQL(streamDirection string, data []byte) error {
+	stmts := parseSQL(r.Dialect, data)
+	switch r.Type {
+	case sqlMaxStatementsType:
+		if r.MaxStatements > 0 && len(stmts) > r.MaxStatements {
+			return &ErrRuleMatch{streamDirection: streamDirection, ruleType: r.Type,
+				statementCount: len(stmts), maxStatements: r.MaxStatements}
+		}
+	case sqlDenyStatementsType:
+		for _, class := range r.Statements {
+			if _, ok := sqlStatementClasses[class]; !ok {
+				return fmt.Errorf("unknown sql statement class %q", class)
+			}
+		}
+		for _, stmt := range stmts {
+			class := stmt.class()
+			if class == "" {
+				continue
+			}
+			for _, deniedClass := range r.Statements {
+				if class == deniedClass {
+					return &ErrRuleMatch{streamDirection: streamDirection, ruleType: r.Type, statementClass: class}
+				}
+			}
+		}
+	case sqlAllowedTablesType:
+		// skip empty allow lists
+		if len(r.Tables) == 0 && len(r.Schemas) == 0 {
+			return nil
+		}
+		for _, stmt := range stmts {
+			for _, table := range stmt.tables() {
+				if !r.isTableAllowed(table.parts) {
+					return &ErrRuleMatch{streamDirection: streamDirection, ruleType: r.Type,
+						table: strings.Join(table.parts, ".")}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// isTableAllowed reports if the table is in the allowed schemas or tables.
+// Names are compared by its trailing parts, an unqualified name in the rule allows the table
+// of any schema. Unqualified tables in the statement are allowed only by unqualified names in
+// the rule, their schema is resolved by the search path of the session which could be changed.
+func (r *Rule) isTableAllowed(table []string) bool {
+	if len(table) >= 2 {
+		schema := table[len(table)-2]
+		for _, allowedSchema := range r.Schemas {
+			if strings.EqualFold(schema, allowedSchema) {
+				return true
+			}
+		}
+	}
+	for _, allowedTable := range r.Tables {
+		if allowedTable == "" {
+			continue
+		}
+		allowedParts := strings.Split(strings.ToLower(allowedTable), ".")
+		if hasSuffixParts(table, allowedParts) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasSuffixParts(parts, suffix []string) bool {
+	if len(suffix) > len(parts) {
+		return false
+	}
+	offset := len(parts) - len(suffix)
+	for i := range suffix {
+		if parts[offset+i] != suffix[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,181 @@
+package guardrails
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSQLStatements(t *testing.T) {
+	for _, tt := range []struct {
+		msg     string
+		dialect string
+		input   string
+		want    int
+	}{
+		{msg: "it should split statements", input: "SELECT 1; SELECT 2;", want: 2},
+		{msg: "it should ignore empty statements", input: ";; SELECT 1 ;\n;", want: 1},
+		{msg: "it should ignore separators in strings", input: "SELECT 'a;b'; SELECT 'it''s;'", want: 2},
+		{msg: "it should ignore separators in comments", input: "SELECT 1 -- ;\n/* ; /* ; */ */", want: 1},
+		{msg: "it should ignore separators in dollar quotes", input: "DO $fn$ BEGIN; END $fn$; SELECT 1", want: 2},
+		{msg: "it should ignore separators in escaped strings", input: `SELECT E'\';'; SELECT 1`, want: 2},
+		{msg: "it should ignore mysql hash comments", dialect: sqlDialectMySQL, input: "SELECT 1 # ;\n", want: 1},
+		{msg: "it should split mssql batches", dialect: sqlDialectMSSQL, input: "SELECT 1\nGO\nSELECT 2\n  go  \n", want: 2},
+		{msg: "it should not split mssql go identifiers", dialect: sqlDialectMSSQL, input: "SELECT go FROM t", want: 1},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			assert.Len(t, parseSQL(tt.dialect, []byte(tt.input)), tt.want)
+		})
+	}
+}
+
+func TestSQLStatementClass(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		want  string
+	}{
+		{input: "dRoP TaBlE users", want: sqlClassDDL},
+		{input: "create index idx on users (id)", want: sqlClassDDL},
+		{input: "ALTER TABLE users ADD COLUMN foo text", want: sqlClassDDL},
+		{input: "TRUNCATE users", want: sqlClassTruncate},
+		{input: "GRANT SELECT ON users TO bob", want: sqlClassGrant},
+		{input: "REVOKE SELECT ON users FROM bob", want: sqlClassRevoke},
+		{input: "DELETE FROM users", want: sqlClassDeleteWithoutWhere},
+		{input: "DELETE FROM users WHERE id = 1", want: ""},
+		{input: "UPDATE users SET a = (SELECT b FROM c WHERE d = 1)", want: sqlClassUpdateWithoutWhere},
+		{input: "UPDATE users SET a = 1 WHERE id = 1", want: ""},
+		{input: "WITH x AS (SELECT id FROM a WHERE b = 1) DELETE FROM users", want: sqlClassDeleteWithoutWhere},
+		{input: "EXPLAIN ANALYZE DELETE FROM users", want: sqlClassDeleteWithoutWhere},
+		{input: "WITH d AS (DELETE FROM users RETURNING *) SELECT * FROM d", want: sqlClassDeleteWithoutWhere},
+		{input: "WITH d AS (DELETE FROM users WHERE id = 1 RETURNING *) SELECT * FROM d", want: ""},
+		{input: "WITH a AS (SELECT 1), u (id) AS NOT MATERIALIZED (UPDATE users SET a = 1 RETURNING id) SELECT * FROM u", want: sqlClassUpdateWithoutWhere},
+		{input: "WITH i AS (INSERT INTO logs SELECT 1 RETURNING *), d AS (DELETE FROM users) SELECT * FROM i", want: sqlClassDeleteWithoutWhere},
+		{input: "WITH i AS (INSERT INTO logs VALUES (1) RETURNING *) SELECT * FROM i", want: ""},
+		{input: "WITH outer_cte AS (WITH d AS (DELETE FROM users RETURNING *) SELECT * FROM d) SELECT * FROM outer_cte", want: sqlClassDeleteWithoutWhere},
+		{input: "SELECT 'DROP TABLE users' -- DROP TABLE users", want: ""},
+		{input: `SELECT "drop" FROM users`, want: ""},
+	} {
+		t.Run(tt.input, func(t *testing.T) {
+			stmts := parseSQL("", []byte(tt.input))
+			assert.Len(t, stmts, 1)
+			assert.Equal(t, tt.want, stmts[0].class())
+		})
+	}
+}
+
+func TestSQLStatementTables(t *testing.T) {
+	for _, tt := range []struct {
+		dialect string
+		input   string
+		want    []string
+	}{
+		{input: "SELECT * FROM users", want: []string{"users"}},
+		{input: "SELECT * FROM public.users u JOIN sales.orders AS o ON o.uid = u.id", want: []string{"public.users", "sales.orders"}},
+		{input: "SELECT * FROM a, b x, c", want: []string{"a", "b", "c"}},
+		{input: `SELECT * FROM "Public"."Users"`, want: []string{"public.users"}},
+		{input: "SELECT * FROM (SELECT id FROM a) AS sub", want: []string{"a"}},
+		{input: "SELECT extract(year FROM created_at) FROM a", want: []string{"a"}},
+		{input: "SELECT * FROM generate_series(1, 10)", want: nil},
+		{input: "WITH recent AS (SELECT * FROM a) SELECT * FROM recent", want: []string{"a"}},
+		{input: "INSERT INTO a (id, name) VALUES (1, 'b')", want: []string{"a"}},
+		{input: "UPDATE ONLY a SET b = 1 FROM c WHERE a.id = c.id", want: []string{"a", "c"}},
+		{input: "DELETE FROM a USING b WHERE a.id = b.id", want: []string{"a", "b"}},
+		{input: "TRUNCATE TABLE a, b", want: []string{"a", "b"}},
+		{input: "DROP TABLE IF EXISTS a", want: []string{"a"}},
+		{input: "SELECT * FROM a FOR UPDATE NOWAIT", want: []string{"a"}},
+		{input: "REVOKE SELECT ON TABLE a FROM bob", want: []string{"a"}},
+		{input: "SELECT * FROM a WHERE b IS DISTINCT FROM c", want: []string{"a"}},
+		{dialect: sqlDialectMSSQL, input: "SELECT * FROM [db].[dbo].[users]", want: []string{"db.dbo.users"}},
+		{dialect: sqlDialectMySQL, input: "SELECT * FROM `db`.`users`", want: []string{"db.users"}},
+	} {
+		t.Run(tt.input, func(t *testing.T) {
+			stmts := parseSQL(tt.dialect, []byte(tt.input))
+			assert.Len(t, stmts, 1)
+			var got []string
+			for _, table := range stmts[0].tables() {
+				got = append(got, strings.Join(table.parts, "."))
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSQLGuardRailRules(t *testing.T) {
+	for _, tt := range []struct {
+		msg   string
+		rule  *Rule
+		input string
+		err   error
+	}{
+		{
+			msg:   "it should match denied statements regardless of the case",
+			rule:  &Rule{Type: sqlDenyStatementsType, Statements: []string{sqlClassDDL}},
+			input: "SELECT 1; dRoP TaBlE users",
+			err: fmt.Errorf("validation error, match guard rails <dunno> rule, type=%v, statement=%v",
+				sqlDenyStatementsType, sqlClassDDL),
+		},
+		{
+			msg:   "it should not match denied statements in comments",
+			rule:  &Rule{Type: sqlDenyStatementsType, Statements: []string{sqlClassDDL}},
+			input: "SELECT 1 -- DROP TABLE users",
+		},
+		{
+			msg:   "it should return error with unknown statement classes",
+			rule:  &Rule{Type: sqlDenyStatementsType, Statements: []string{"unknown"}},
+			input: "SELECT 1",
+			err:   fmt.Errorf(`unknown sql statement class "unknown"`),
+		},
+		{
+			msg:   "it should match tables not in the allow list",
+			rule:  &Rule{Type: sqlAllowedTablesType, Tables: []string{"public.users"}},
+			input: "SELECT * FROM public.users u JOIN public.orders o ON o.uid = u.id",
+			err: fmt.Errorf("validation error, match guard rails <dunno> rule, type=%v, table=%v",
+				sqlAllowedTablesType, "public.orders"),
+		},
+		{
+			msg:   "it should match unqualified tables when the allow list is qualified",
+			rule:  &Rule{Type: sqlAllowedTablesType, Tables: []string{"public.users"}},
+			input: "SET search_path = secret; SELECT * FROM users",
+			err: fmt.Errorf("validation error, match guard rails <dunno> rule, type=%v, table=%v",
+				sqlAllowedTablesType, "users"),
+		},
+		{
+			msg:   "it should allow tables of any schema when the allow list is unqualified",
+			rule:  &Rule{Type: sqlAllowedTablesType, Tables: []string{"users"}},
+			input: "SELECT * FROM users JOIN public.users pu ON pu.id = users.id",
+		},
+		{
+			msg:   "it should allow tables from allowed schemas",
+			rule:  &Rule{Type: sqlAllowedTablesType, Tables: []string{"users"}, Schemas: []string{"sales"}},
+			input: "SELECT * FROM users JOIN Sales.orders o ON o.uid = users.id",
+		},
+		{
+			msg:   "it should skip empty allow lists",
+			rule:  &Rule{Type: sqlAllowedTablesType},
+			input: "SELECT * FROM users",
+		},
+		{
+			msg:   "it should match the max number of statements",
+			rule:  &Rule{Type: sqlMaxStatementsType, MaxStatements: 1},
+			input: "SELECT 1; SELECT 2",
+			err: fmt.Errorf("validation error, match guard rails <dunno> rule, type=%v, statements=2, max=1",
+				sqlMaxStatementsType),
+		},
+		{
+			msg:   "it should skip max statements when it's not set",
+			rule:  &Rule{Type: sqlMaxStatementsType},
+			input: "SELECT 1; SELECT 2",
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			err := tt.rule.validate("<dunno>", []byte(tt.input))
+			if err != nil {
+				assert.EqualError(t, err, tt.err.Error())
+				return
+			}
+			assert.Nil(t, tt.err)
+		})
+	}
+}
@@ -44,6 +44,10 @@ type Session struct {
 	StartDate         string `json:"started"`
 	EndDate           string `json:"completed"`
 	Duration          int64  `json:"duration"`
+	// the statements executed by database connections
+	// and the duration in milliseconds of the slowest one
+	Statements       int64 `json:"statements"`
+	SlowestStatement int64 `json:"slowest_statement"`
 }
 
 func newDefautFieldMapping(fieldType, fieldAnalyzer string) *mapping.FieldMapping {
@@ -77,6 +81,8 @@ func newSessionMapping() mapping.IndexMapping {
 	m.DefaultMapping.AddFieldMappingsAt(searchquery.QualifierBoolOutputTruncated, newDefautFieldMapping("boolean", ""))
 	m.DefaultMapping.AddFieldMappingsAt(searchquery.QualifierBoolError, newDefautFieldMapping("boolean", ""))
 	m.DefaultMapping.AddFieldMappingsAt(searchquery.QualifierFilterDuration, newDefautFieldMapping("number", ""))
+	m.DefaultMapping.AddFieldMappingsAt(searchquery.QualifierFilterStatements, newDefautFieldMapping("number", ""))
+	m.DefaultMapping.AddFieldMappingsAt(searchquery.QualifierFilterSlowestStatement, newDefautFieldMapping("number", ""))
 	m.DefaultMapping.AddFieldMappingsAt(searchquery.QualifierFilterStartDate, newDefautFieldMapping("datetime", ""))
 	m.DefaultMapping.AddFieldMappingsAt(searchquery.QualifierFilterCompleteDate, newDefautFieldMapping("datetime", ""))
 
@@ -184,6 +190,28 @@ func (i *Indexer) Index(sessionID string, data any) error {
 	return i.idx.Index(sessionID, data)
 }
 
+// Delete removes a session from the index
+func (i *Indexer) Delete(sessionID string) error {
+	return i.idx.Delete(sessionID)
+}
+
+// PurgeOutput indexes a session again without its output, the remaining stored fields are kept
+func (i *Indexer) PurgeOutput(sessionID string) error {
+	req := bleve.NewSearchRequest(bleve.NewDocIDQuery([]string{sessionID}))
+	req.Fields = []string{"*"}
+	res, err := i.Search(req)
+	if err != nil {
+		return err
+	}
+	if len(res.Hits) == 0 {
+		return nil
+	}
+	fields := res.Hits[0].Fields
+	fields[searchquery.QualifierQueryInOutput] = ""
+	fields[searchquery.QualifierBoolOutputTruncated] = false
+	return i.idx.Index(sessionID, fields)
+}
+
 func (i *Indexer) Search(req *bleve.SearchRequest) (*bleve.SearchResult, error) {
 	ctx, cancelFn := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancelFn()
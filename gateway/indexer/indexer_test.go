@@ -0,0 +1,72 @@
+package indexer
+
+import (
+	"testing"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/hoophq/hoop/gateway/indexer/searchquery"
+	plugintypes "github.com/hoophq/hoop/gateway/transport/plugins/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestIndexer(t *testing.T) *Indexer {
+	plugintypes.IndexPath = t.TempDir()
+	index, err := NewIndexer("test-org")
+	if err != nil {
+		t.Fatalf("failed creating indexer: %v", err)
+	}
+	t.Cleanup(func() { _ = index.Close() })
+	return index
+}
+
+func searchByID(t *testing.T, index *Indexer, sessionID string) map[string]any {
+	req := bleve.NewSearchRequest(bleve.NewDocIDQuery([]string{sessionID}))
+	req.Fields = []string{"*"}
+	res, err := index.Search(req)
+	assert.NoError(t, err)
+	if len(res.Hits) == 0 {
+		return nil
+	}
+	return res.Hits[0].Fields
+}
+
+func TestPurgeOutput(t *testing.T) {
+	index := newTestIndexer(t)
+	err := index.Index("sid-1", &Session{
+		ID:                "sid-1",
+		User:              "user@domain.tld",
+		Connection:        "pgdemo",
+		Input:             "SELECT * FROM customers",
+		Output:            "john,doe,555-1234",
+		IsOutputTruncated: true,
+		StartDate:         "2024-01-01T10:00:00Z",
+		Duration:          30,
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, index.PurgeOutput("sid-1"))
+	fields := searchByID(t, index, "sid-1")
+	assert.Equal(t, "", fields[searchquery.QualifierQueryInOutput])
+	assert.Equal(t, false, fields[searchquery.QualifierBoolOutputTruncated])
+	assert.Equal(t, "SELECT * FROM customers", fields[searchquery.QualifierQueryInInput])
+	assert.Equal(t, "pgdemo", fields[searchquery.QualifierFilterConnection])
+	assert.Equal(t, float64(30), fields[searchquery.QualifierFilterDuration])
+
+	// the output must not be searchable anymore
+	req := bleve.NewSearchRequest(bleve.NewMatchQuery("john"))
+	res, err := index.Search(req)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), res.Total)
+
+	// purging sessions that are not indexed is a noop
+	assert.NoError(t, index.PurgeOutput("sid-unknown"))
+}
+
+func TestDelete(t *testing.T) {
+	index := newTestIndexer(t)
+	assert.NoError(t, index.Index("sid-1", &Session{ID: "sid-1", Connection: "pgdemo"}))
+	assert.NotNil(t, searchByID(t, index, "sid-1"))
+
+	assert.NoError(t, index.Delete("sid-1"))
+	assert.Nil(t, searchByID(t, index, "sid-1"))
+}
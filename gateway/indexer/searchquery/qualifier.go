@@ -77,7 +77,8 @@ func (q *qualifier) parseQualifierFilter() (filter query.Query, err error) {
 			Term:     q.value,
 			FieldVal: q.attribute,
 		}
-	case QualifierFilterDuration, QualifierFilterSize:
+	case QualifierFilterDuration, QualifierFilterSize,
+		QualifierFilterStatements, QualifierFilterSlowestStatement:
 		min, max, err := parseNumericOperator(q.value)
 		if err != nil {
 			return nil, err
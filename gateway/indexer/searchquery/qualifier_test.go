@@ -146,6 +146,14 @@ func TestParseNumericQualifiers(t *testing.T) {
 				"size": {FieldVal: "size", Min: pfloat64(10, false), Max: pfloat64(100, false)},
 			},
 		},
+		{
+			msg:   "it must parse statement numeric ranges",
+			query: "statements:>10 slowest_statement:500..3000",
+			want: map[string]*query.NumericRangeQuery{
+				"statements":        {FieldVal: "statements", Min: pfloat64(10, false), Max: nil},
+				"slowest_statement": {FieldVal: "slowest_statement", Min: pfloat64(500, false), Max: pfloat64(3000, false)},
+			},
+		},
 		{
 			msg:   "it must fail passing an invalid operator",
 			query: "size:>-10",
@@ -30,8 +30,12 @@ const (
 	QualifierFilterVerb           = "verb"
 	QualifierFilterSize           = "size"
 	QualifierFilterDuration       = "duration"
-	QualifierFilterStartDate      = "started"
-	QualifierFilterCompleteDate   = "completed"
+	// QualifierFilterStatements is the number of statements of database sessions
+	QualifierFilterStatements = "statements"
+	// QualifierFilterSlowestStatement is the duration in milliseconds of the slowest statement
+	QualifierFilterSlowestStatement = "slowest_statement"
+	QualifierFilterStartDate        = "started"
+	QualifierFilterCompleteDate     = "completed"
 )
 
 var (
@@ -41,17 +45,19 @@ var (
 )
 
 var registeredQualifiers = map[string]any{
-	QualifierQueryIn:              nil,
-	QualifierBoolFilterIs:         nil,
-	QualifierFilterConnection:     nil,
-	QualifierFilterConnectionType: nil,
-	QualifierFilterSession:        nil,
-	QualifierFilterUser:           nil,
-	QualifierFilterVerb:           nil,
-	QualifierFilterSize:           nil,
-	QualifierFilterDuration:       nil,
-	QualifierFilterStartDate:      nil,
-	QualifierFilterCompleteDate:   nil,
+	QualifierQueryIn:                nil,
+	QualifierBoolFilterIs:           nil,
+	QualifierFilterConnection:       nil,
+	QualifierFilterConnectionType:   nil,
+	QualifierFilterSession:          nil,
+	QualifierFilterUser:             nil,
+	QualifierFilterVerb:             nil,
+	QualifierFilterSize:             nil,
+	QualifierFilterDuration:         nil,
+	QualifierFilterStatements:       nil,
+	QualifierFilterSlowestStatement: nil,
+	QualifierFilterStartDate:        nil,
+	QualifierFilterCompleteDate:     nil,
 
 	QualifierQueryFuzzy: nil,
 }
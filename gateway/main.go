@@ -19,8 +19,11 @@ import (
 	"github.com/hoophq/hoop/gateway/models"
 	"github.com/hoophq/hoop/gateway/pgrest"
 	pgorgs "github.com/hoophq/hoop/gateway/pgrest/orgs"
+	"github.com/hoophq/hoop/gateway/retention"
 	"github.com/hoophq/hoop/gateway/review"
 	"github.com/hoophq/hoop/gateway/security/idp"
+	"github.com/hoophq/hoop/gateway/session/blobstore"
+	"github.com/hoophq/hoop/gateway/session/integrity"
 	"github.com/hoophq/hoop/gateway/transport"
 	"github.com/hoophq/hoop/gateway/webappjs"
 
@@ -67,6 +70,9 @@ func Run() {
 	if err := models.InitDatabaseConnection(); err != nil {
 		log.Fatal(err)
 	}
+	if err := blobstore.Load(); err != nil {
+		log.Fatalf("failed loading session storage, reason=%v", err)
+	}
 
 	reviewService := review.Service{}
 	if !appconfig.Get().OrgMultitenant() {
@@ -131,6 +137,9 @@ func Run() {
 	}
 	connectionstatus.InitConciliationProcess()
 	streamclient.InitProxyMemoryCleanup()
+	reviewService.InitExpirationProcess()
+	retention.InitPurgeProcess()
+	integrity.InitAnchorProcess()
 
 	if grpc.ShouldDebugGrpc() {
 		log.SetGrpcLogger()
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const tableAccessPolicies = "private.access_policies"
+
+// AccessPolicy allows or denies access to connections based on the attributes
+// of the user, the connection and the request
+type AccessPolicy struct {
+	ID              string                  `gorm:"column:id"`
+	OrgID           string                  `gorm:"column:org_id"`
+	Name            string                  `gorm:"column:name"`
+	Description     sql.NullString          `gorm:"column:description"`
+	Effect          string                  `gorm:"column:effect"`
+	Groups          pq.StringArray          `gorm:"column:groups;type:text[]"`
+	ConnectionTags  map[string]string       `gorm:"column:connection_tags;serializer:json"`
+	ConnectionTypes pq.StringArray          `gorm:"column:connection_types;type:text[]"`
+	Verbs           pq.StringArray          `gorm:"column:verbs;type:text[]"`
+	ClientOrigins   pq.StringArray          `gorm:"column:client_origins;type:text[]"`
+	SourceIPs       pq.StringArray          `gorm:"column:source_ips;type:text[]"`
+	TimeWindow      *AccessPolicyTimeWindow `gorm:"column:time_window;serializer:json"`
+	CreatedAt       time.Time               `gorm:"column:created_at"`
+	UpdatedAt       time.Time               `gorm:"column:updated_at"`
+}
+
+type AccessPolicyTimeWindow struct {
+	Days      []string `json:"days"`
+	StartTime string   `json:"start_time"`
+	EndTime   string   `json:"end_time"`
+	Timezone  string   `json:"timezone"`
+}
+
+func ListAccessPolicies(orgID string) ([]AccessPolicy, error) {
+	var items []AccessPolicy
+	return items, DB.Table(tableAccessPolicies).
+		Where("org_id = ?", orgID).
+		Order("name").
+		Find(&items).Error
+}
+
+func GetAccessPolicy(orgID, id string) (*AccessPolicy, error) {
+	var policy AccessPolicy
+	err := DB.Table(tableAccessPolicies).
+		Where("org_id = ? AND id = ?", orgID, id).
+		First(&policy).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func CreateAccessPolicy(policy *AccessPolicy) error {
+	err := DB.Table(tableAccessPolicies).Create(policy).Error
+	if err == gorm.ErrDuplicatedKey {
+		return ErrAlreadyExists
+	}
+	return err
+}
+
+func UpdateAccessPolicy(policy *AccessPolicy) error {
+	res := DB.Table(tableAccessPolicies).
+		Model(policy).
+		Clauses(clause.Returning{}).
+		Where("org_id = ? AND id = ?", policy.OrgID, policy.ID).
+		Select("name", "description", "effect", "groups", "connection_tags", "connection_types",
+			"verbs", "client_origins", "source_ips", "time_window", "updated_at").
+		Updates(policy)
+	if res.Error == gorm.ErrDuplicatedKey {
+		return ErrAlreadyExists
+	}
+	if res.Error == nil && res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return res.Error
+}
+
+func DeleteAccessPolicy(orgID, id string) error {
+	res := DB.Table(tableAccessPolicies).
+		Where("org_id = ? AND id = ?", orgID, id).
+		Delete(&AccessPolicy{})
+	if res.Error == nil && res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return res.Error
+}
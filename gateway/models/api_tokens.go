@@ -0,0 +1,101 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+const tableAPITokens = "private.api_tokens"
+
+// APIToken is a token that authenticates on behalf of a user or service account.
+// Only the hash of the token is stored, the prefix is kept to identify it.
+type APIToken struct {
+	ID          string         `gorm:"column:id"`
+	OrgID       string         `gorm:"column:org_id"`
+	Name        string         `gorm:"column:name"`
+	UserSubject string         `gorm:"column:user_subject"`
+	UserEmail   string         `gorm:"column:user_email"`
+	TokenHash   string         `gorm:"column:token_hash"`
+	TokenPrefix string         `gorm:"column:token_prefix"`
+	Scopes      pq.StringArray `gorm:"column:scopes;type:text[]"`
+	Connections pq.StringArray `gorm:"column:connections;type:text[]"`
+	ExpiresAt   sql.NullTime   `gorm:"column:expires_at"`
+	LastUsedAt  sql.NullTime   `gorm:"column:last_used_at"`
+	RevokedAt   sql.NullTime   `gorm:"column:revoked_at"`
+	CreatedBy   string         `gorm:"column:created_by"`
+	CreatedAt   time.Time      `gorm:"column:created_at"`
+}
+
+// IsActive returns true if the token is not revoked and not expired
+func (t *APIToken) IsActive(now time.Time) bool {
+	if t.RevokedAt.Valid {
+		return false
+	}
+	return !t.ExpiresAt.Valid || now.Before(t.ExpiresAt.Time)
+}
+
+func CreateAPIToken(token *APIToken) error {
+	err := DB.Table(tableAPITokens).Create(token).Error
+	if err == gorm.ErrDuplicatedKey {
+		return ErrAlreadyExists
+	}
+	return err
+}
+
+// ListAPITokens returns the tokens of an organization, the tokens
+// are filtered by the user subject when it's not empty
+func ListAPITokens(orgID, userSubject string) ([]APIToken, error) {
+	var items []APIToken
+	query := DB.Table(tableAPITokens).Where("org_id = ?", orgID)
+	if userSubject != "" {
+		query = query.Where("user_subject = ?", userSubject)
+	}
+	return items, query.Order("created_at DESC").Find(&items).Error
+}
+
+func GetAPIToken(orgID, id string) (*APIToken, error) {
+	var token APIToken
+	err := DB.Table(tableAPITokens).
+		Where("org_id = ? AND id = ?", orgID, id).
+		First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// GetAPITokenByHash returns a token of any organization by the hash of its value
+func GetAPITokenByHash(tokenHash string) (*APIToken, error) {
+	var token APIToken
+	err := DB.Table(tableAPITokens).
+		Where("token_hash = ?", tokenHash).
+		First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RevokeAPIToken marks a token as revoked, revoking a token more than once is a noop
+func RevokeAPIToken(orgID, id string) error {
+	res := DB.Exec(`UPDATE private.api_tokens SET revoked_at = COALESCE(revoked_at, NOW()) WHERE org_id = ? AND id = ?`,
+		orgID, id)
+	if res.Error == nil && res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return res.Error
+}
+
+func UpdateAPITokenLastUsedAt(id string, lastUsedAt time.Time) error {
+	return DB.Exec(`UPDATE private.api_tokens SET last_used_at = ? WHERE id = ?`, lastUsedAt, id).Error
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/hoophq/hoop/gateway/pgrest"
 	pgplugins "github.com/hoophq/hoop/gateway/pgrest/plugins"
+	"github.com/hoophq/hoop/gateway/storagev2/types"
 	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
@@ -32,21 +33,22 @@ const (
 )
 
 type Connection struct {
-	OrgID               string         `gorm:"column:org_id"`
-	ID                  string         `gorm:"column:id"`
-	AgentID             sql.NullString `gorm:"column:agent_id"`
-	Name                string         `gorm:"column:name"`
-	Command             pq.StringArray `gorm:"column:command;type:text[]"`
-	Type                string         `gorm:"column:type"`
-	SubType             sql.NullString `gorm:"column:subtype"`
-	Status              string         `gorm:"column:status"`
-	ManagedBy           sql.NullString `gorm:"column:managed_by"`
-	Tags                pq.StringArray `gorm:"column:_tags;type:text[]"`
-	AccessModeRunbooks  string         `gorm:"column:access_mode_runbooks"`
-	AccessModeExec      string         `gorm:"column:access_mode_exec"`
-	AccessModeConnect   string         `gorm:"column:access_mode_connect"`
-	AccessSchema        string         `gorm:"column:access_schema"`
-	JiraIssueTemplateID sql.NullString `gorm:"column:jira_issue_template_id"`
+	OrgID               string              `gorm:"column:org_id"`
+	ID                  string              `gorm:"column:id"`
+	AgentID             sql.NullString      `gorm:"column:agent_id"`
+	Name                string              `gorm:"column:name"`
+	Command             pq.StringArray      `gorm:"column:command;type:text[]"`
+	Type                string              `gorm:"column:type"`
+	SubType             sql.NullString      `gorm:"column:subtype"`
+	Status              string              `gorm:"column:status"`
+	ManagedBy           sql.NullString      `gorm:"column:managed_by"`
+	Tags                pq.StringArray      `gorm:"column:_tags;type:text[]"`
+	AccessModeRunbooks  string              `gorm:"column:access_mode_runbooks"`
+	AccessModeExec      string              `gorm:"column:access_mode_exec"`
+	AccessModeConnect   string              `gorm:"column:access_mode_connect"`
+	AccessSchema        string              `gorm:"column:access_schema"`
+	JiraIssueTemplateID sql.NullString      `gorm:"column:jira_issue_template_id"`
+	ReviewPolicy        *types.ReviewPolicy `gorm:"column:review_policy;serializer:json"`
 
 	// Read Only fields
 	RedactEnabled             bool              `gorm:"column:redact_enabled;->"`
@@ -237,6 +239,23 @@ func GetConnectionGuardRailRules(orgID, name string) (*ConnectionGuardRailRules,
 	return &conn, nil
 }
 
+// GetConnectionReviewPolicy returns the review policy of a connection,
+// it returns nil if the connection doesn't have a policy.
+func GetConnectionReviewPolicy(orgID, connectionID string) (*types.ReviewPolicy, error) {
+	var conn Connection
+	err := DB.Table(tableConnections).
+		Select("review_policy").
+		Where("org_id = ? AND id = ?", orgID, connectionID).
+		First(&conn).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return conn.ReviewPolicy, nil
+}
+
 func GetConnectionByNameOrID(orgID, nameOrID string) (*Connection, error) {
 	var conn Connection
 	err := DB.Model(&Connection{}).Raw(`
@@ -244,7 +263,7 @@ func GetConnectionByNameOrID(orgID, nameOrID string) (*Connection, error) {
 		c.id, c.org_id, c.name, c.command, c.status, c.type, c.subtype, c.managed_by,
 		c.access_mode_runbooks, c.access_mode_exec, c.access_mode_connect, c.access_schema,
 		c.agent_id, a.name AS agent_name, a.mode AS agent_mode,
-		c.jira_issue_template_id, it.issue_transition_name_on_close,
+		c.jira_issue_template_id, it.issue_transition_name_on_close, c.review_policy,
 		COALESCE(c._tags, ARRAY[]::TEXT[]) AS _tags,
 		( SELECT JSONB_OBJECT_AGG(ct.key, ct.value)
 		 FROM private.connection_tags_association cta
@@ -355,7 +374,7 @@ func ListConnections(orgID string, opts ConnectionFilterOption) ([]Connection, e
 	SELECT
 		c.id, c.org_id, c.agent_id, c.name, c.command, c.status, c.type, c.subtype, c.managed_by,
 		c.access_mode_runbooks, c.access_mode_exec, c.access_mode_connect, c.access_schema,
-		c.jira_issue_template_id,
+		c.jira_issue_template_id, c.review_policy,
 		-- legacy tags
 		COALESCE(c._tags, ARRAY[]::TEXT[]) AS _tags,
 		( SELECT JSONB_OBJECT_AGG(ct.key, ct.value)
@@ -5,4 +5,7 @@ import "fmt"
 var (
 	ErrNotFound      = fmt.Errorf("resource not found")
 	ErrAlreadyExists = fmt.Errorf("resource already exists")
+	// ErrBlobStreamNotFound is returned when the object of a session stream
+	// persisted in an object store no longer exists
+	ErrBlobStreamNotFound = fmt.Errorf("session blob stream not found")
 )
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// TryAdvisoryLock runs fn holding the transaction advisory lock of key, it's used to
+// run a process in only one gateway replica. It returns false without running fn
+// when the lock is held by another session.
+func TryAdvisoryLock(key string, fn func() error) (locked bool, err error) {
+	err = DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Raw(`SELECT pg_try_advisory_xact_lock(hashtext(?))`, key).Scan(&locked).Error; err != nil {
+			return fmt.Errorf("failed acquiring advisory lock, reason=%v", err)
+		}
+		if !locked {
+			return nil
+		}
+		return fn()
+	})
+	return locked, err
+}
+
+// WithAdvisoryLock runs fn holding the transaction advisory lock of key,
+// it waits for the lock when it's held by another session
+func WithAdvisoryLock(key string, fn func() error) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext(?))`, key).Error; err != nil {
+			return fmt.Errorf("failed acquiring advisory lock, reason=%v", err)
+		}
+		return fn()
+	})
+}
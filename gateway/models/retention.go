@@ -0,0 +1,307 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hoophq/hoop/common/log"
+	"github.com/hoophq/hoop/gateway/session/blobstore"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	tableRetentionPolicies = "private.retention_policies"
+	tableRetentionPurges   = "private.retention_purges"
+)
+
+type RetentionPolicy struct {
+	ID             string         `gorm:"column:id"`
+	OrgID          string         `gorm:"column:org_id"`
+	ConnectionName sql.NullString `gorm:"column:connection_name"`
+	Mode           string         `gorm:"column:mode"`
+	RetentionDays  int            `gorm:"column:retention_days"`
+	CreatedAt      time.Time      `gorm:"column:created_at"`
+	UpdatedAt      time.Time      `gorm:"column:updated_at"`
+}
+
+// RetentionPurge is the record of a session purged by a retention policy
+type RetentionPurge struct {
+	ID               string         `gorm:"column:id"`
+	OrgID            string         `gorm:"column:org_id"`
+	PolicyID         sql.NullString `gorm:"column:policy_id"`
+	SessionID        string         `gorm:"column:session_id"`
+	Connection       string         `gorm:"column:connection"`
+	Mode             string         `gorm:"column:mode"`
+	SessionCreatedAt time.Time      `gorm:"column:session_created_at"`
+	PurgedAt         time.Time      `gorm:"column:purged_at"`
+}
+
+type RetentionPurgeList struct {
+	Total       int64
+	HasNextPage bool
+	Items       []RetentionPurge
+}
+
+// RetentionSession is a session eligible to be purged by a retention policy
+type RetentionSession struct {
+	ID           string         `gorm:"column:id"`
+	OrgID        string         `gorm:"column:org_id"`
+	Connection   string         `gorm:"column:connection"`
+	BlobInputID  sql.NullString `gorm:"column:blob_input_id"`
+	BlobStreamID sql.NullString `gorm:"column:blob_stream_id"`
+	CreatedAt    time.Time      `gorm:"column:created_at"`
+
+	Integrity *SessionIntegrity `gorm:"column:integrity;serializer:json"`
+}
+
+func ListRetentionPolicies(orgID string) ([]RetentionPolicy, error) {
+	var items []RetentionPolicy
+	return items, DB.Table(tableRetentionPolicies).
+		Where("org_id = ?", orgID).
+		Order("connection_name NULLS FIRST, mode").
+		Find(&items).Error
+}
+
+// ListAllRetentionPolicies returns the policies of all organizations
+func ListAllRetentionPolicies() ([]RetentionPolicy, error) {
+	var items []RetentionPolicy
+	return items, DB.Table(tableRetentionPolicies).
+		Order("org_id, connection_name NULLS FIRST, mode").
+		Find(&items).Error
+}
+
+func GetRetentionPolicy(orgID, id string) (*RetentionPolicy, error) {
+	var policy RetentionPolicy
+	err := DB.Table(tableRetentionPolicies).
+		Where("org_id = ? AND id = ?", orgID, id).
+		First(&policy).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func CreateRetentionPolicy(policy *RetentionPolicy) error {
+	err := DB.Table(tableRetentionPolicies).Create(policy).Error
+	if err == gorm.ErrDuplicatedKey {
+		return ErrAlreadyExists
+	}
+	return err
+}
+
+func UpdateRetentionPolicy(policy *RetentionPolicy) error {
+	res := DB.Table(tableRetentionPolicies).
+		Model(policy).
+		Clauses(clause.Returning{}).
+		Where("org_id = ? AND id = ?", policy.OrgID, policy.ID).
+		Select("connection_name", "mode", "retention_days", "updated_at").
+		Updates(policy)
+	if res.Error == gorm.ErrDuplicatedKey {
+		return ErrAlreadyExists
+	}
+	if res.Error == nil && res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return res.Error
+}
+
+func DeleteRetentionPolicy(orgID, id string) error {
+	res := DB.Table(tableRetentionPolicies).
+		Where("org_id = ? AND id = ?", orgID, id).
+		Delete(&RetentionPolicy{})
+	if res.Error == nil && res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return res.Error
+}
+
+// ListSessionsToPurge returns the sessions that ended before the cutoff date and were not purged yet by the mode
+// of the policy. Organization policies skip the connections in exceptConnections, which have their own policy.
+// The sessions are listed by the time they ended, offset skips the first sessions of the listing.
+func ListSessionsToPurge(policy RetentionPolicy, exceptConnections []string, cutoff time.Time, offset, limit int) ([]RetentionSession, error) {
+	var items []RetentionSession
+	query := DB.Table(tableSessions).
+		Select("id, org_id, connection, blob_input_id, blob_stream_id, integrity, created_at").
+		Where("org_id = ? AND ended_at IS NOT NULL AND ended_at < ?", policy.OrgID, cutoff)
+	switch policy.Mode {
+	case "event_stream":
+		query = query.Where("blob_stream_id IS NOT NULL AND stream_purged_at IS NULL")
+	case "output":
+		query = query.Where("blob_stream_id IS NOT NULL AND stream_purged_at IS NULL AND output_purged_at IS NULL")
+	}
+	if policy.ConnectionName.Valid {
+		query = query.Where("connection = ?", policy.ConnectionName.String)
+	} else if len(exceptConnections) > 0 {
+		query = query.Where("connection NOT IN ?", exceptConnections)
+	}
+	return items, query.Order("ended_at ASC, id ASC").Offset(offset).Limit(limit).Find(&items).Error
+}
+
+// PurgeSessionStream removes the event stream of a session keeping its metadata,
+// the seal of the session is replaced by the seal with the purge record
+func PurgeSessionStream(sess RetentionSession, policy RetentionPolicy, seal *SessionIntegrity) error {
+	integrity, err := encodeIntegrity(seal)
+	if err != nil {
+		return err
+	}
+	var objects []Blob
+	err = DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`UPDATE private.sessions SET blob_stream_id = NULL, stream_purged_at = NOW(), integrity = COALESCE(?::JSONB, integrity)
+		WHERE org_id = ? AND id = ?`, integrity, sess.OrgID, sess.ID).Error
+		if err != nil {
+			return fmt.Errorf("failed updating session, reason=%v", err)
+		}
+		if objects, err = deleteBlobs(tx, sess.OrgID, sess.BlobStreamID); err != nil {
+			return err
+		}
+		return createRetentionPurge(tx, sess, policy)
+	})
+	if err != nil {
+		return err
+	}
+	deleteBlobObjects(objects)
+	return nil
+}
+
+// PurgeSessionOutput replaces the event stream of a session with the stream without the output events,
+// the seal of the session is replaced by the seal with the purge record
+func PurgeSessionOutput(sess RetentionSession, policy RetentionPolicy, blobStream json.RawMessage, seal *SessionIntegrity) error {
+	integrity, err := encodeIntegrity(seal)
+	if err != nil {
+		return err
+	}
+	return writeSessionBlobStream(sess.OrgID, sess.ID, blobStream, func(tx *gorm.DB, _ sql.NullString) error {
+		err := tx.Exec(`UPDATE private.sessions SET output_purged_at = NOW(), integrity = COALESCE(?::JSONB, integrity)
+		WHERE org_id = ? AND id = ?`, integrity, sess.OrgID, sess.ID).Error
+		if err != nil {
+			return fmt.Errorf("failed updating session, reason=%v", err)
+		}
+		return createRetentionPurge(tx, sess, policy)
+	})
+}
+
+// PurgeSession removes a session with all its content
+func PurgeSession(sess RetentionSession, policy RetentionPolicy) error {
+	var objects []Blob
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		// the statements are removed in cascade
+		err := tx.Exec(`DELETE FROM private.sessions WHERE org_id = ? AND id = ?`, sess.OrgID, sess.ID).Error
+		if err != nil {
+			return fmt.Errorf("failed removing session, reason=%v", err)
+		}
+		if objects, err = deleteBlobs(tx, sess.OrgID, sess.BlobInputID, sess.BlobStreamID); err != nil {
+			return err
+		}
+		return createRetentionPurge(tx, sess, policy)
+	})
+	if err != nil {
+		return err
+	}
+	deleteBlobObjects(objects)
+	return nil
+}
+
+// deleteBlobs removes the metadata of blobs, it returns the blobs with the payload located in an
+// object store. The caller must remove their objects with deleteBlobObjects after the transaction commits.
+func deleteBlobs(tx *gorm.DB, orgID string, blobIDs ...sql.NullString) ([]Blob, error) {
+	var ids []string
+	for _, id := range blobIDs {
+		if id.Valid {
+			ids = append(ids, id.String)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var blobs []Blob
+	err := tx.Table(tableBlobs).
+		Select("id, org_id, type, storage_backend, storage_key").
+		Where("org_id = ? AND id IN ?", orgID, ids).
+		Find(&blobs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed fetching blobs, reason=%v", err)
+	}
+	var objects []Blob
+	for _, blob := range blobs {
+		if blob.StorageBackend == "" || blob.StorageBackend == blobstore.BackendPostgres {
+			continue
+		}
+		// the objects could only be removed by the store they're located
+		if _, err := blobstore.ObjectStoreFor(blob.StorageBackend); err != nil {
+			return nil, err
+		}
+		objects = append(objects, blob)
+	}
+	err = tx.Exec(`DELETE FROM private.blobs WHERE org_id = ? AND id IN ?`, orgID, ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed removing blobs, reason=%v", err)
+	}
+	return objects, nil
+}
+
+// deleteBlobObjects removes the payload of blobs from their object store,
+// a failure leaves an orphan object that is not referenced anymore
+func deleteBlobObjects(blobs []Blob) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	for _, blob := range blobs {
+		store, err := blobstore.ObjectStoreFor(blob.StorageBackend)
+		if err == nil {
+			err = store.Delete(ctx, blob.StorageKey.String)
+		}
+		if err != nil {
+			log.Warnf("failed removing blob object, id=%v, key=%v, reason=%v", blob.ID, blob.StorageKey.String, err)
+		}
+	}
+}
+
+func createRetentionPurge(tx *gorm.DB, sess RetentionSession, policy RetentionPolicy) error {
+	err := tx.Table(tableRetentionPurges).Create(&RetentionPurge{
+		ID:               uuid.NewString(),
+		OrgID:            sess.OrgID,
+		PolicyID:         sql.NullString{String: policy.ID, Valid: policy.ID != ""},
+		SessionID:        sess.ID,
+		Connection:       sess.Connection,
+		Mode:             policy.Mode,
+		SessionCreatedAt: sess.CreatedAt,
+		PurgedAt:         time.Now().UTC(),
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed creating retention purge record, reason=%v", err)
+	}
+	return nil
+}
+
+// ListRetentionPurges returns the sessions purged by retention policies, the most recent first
+func ListRetentionPurges(orgID, connection string, limit, offset int) (*RetentionPurgeList, error) {
+	purgeList := &RetentionPurgeList{Items: []RetentionPurge{}}
+	query := func() *gorm.DB {
+		q := DB.Table(tableRetentionPurges).Where("org_id = ?", orgID)
+		if connection != "" {
+			q = q.Where("connection = ?", connection)
+		}
+		return q
+	}
+	if err := query().Count(&purgeList.Total).Error; err != nil {
+		return nil, fmt.Errorf("unable to obtain total count of purges, reason=%v", err)
+	}
+	err := query().
+		Order("purged_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&purgeList.Items).Error
+	if err != nil {
+		return nil, err
+	}
+	purgeList.HasNextPage = int64(offset+len(purgeList.Items)) < purgeList.Total
+	return purgeList, nil
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const tableRoles = "private.roles"
+
+// Role is a custom role that grants a set of permissions to the users of its groups
+type Role struct {
+	ID          string         `gorm:"column:id"`
+	OrgID       string         `gorm:"column:org_id"`
+	Name        string         `gorm:"column:name"`
+	Description sql.NullString `gorm:"column:description"`
+	Permissions pq.StringArray `gorm:"column:permissions;type:text[]"`
+	Groups      pq.StringArray `gorm:"column:groups;type:text[]"`
+	CreatedAt   time.Time      `gorm:"column:created_at"`
+	UpdatedAt   time.Time      `gorm:"column:updated_at"`
+}
+
+func ListRoles(orgID string) ([]Role, error) {
+	var items []Role
+	return items, DB.Table(tableRoles).
+		Where("org_id = ?", orgID).
+		Order("name").
+		Find(&items).Error
+}
+
+func GetRole(orgID, id string) (*Role, error) {
+	var role Role
+	err := DB.Table(tableRoles).
+		Where("org_id = ? AND id = ?", orgID, id).
+		First(&role).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+func CreateRole(role *Role) error {
+	err := DB.Table(tableRoles).Create(role).Error
+	if err == gorm.ErrDuplicatedKey {
+		return ErrAlreadyExists
+	}
+	return err
+}
+
+func UpdateRole(role *Role) error {
+	res := DB.Table(tableRoles).
+		Model(role).
+		Clauses(clause.Returning{}).
+		Where("org_id = ? AND id = ?", role.OrgID, role.ID).
+		Select("name", "description", "permissions", "groups", "updated_at").
+		Updates(role)
+	if res.Error == gorm.ErrDuplicatedKey {
+		return ErrAlreadyExists
+	}
+	if res.Error == nil && res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return res.Error
+}
+
+func DeleteRole(orgID, id string) error {
+	res := DB.Table(tableRoles).
+		Where("org_id = ? AND id = ?", orgID, id).
+		Delete(&Role{})
+	if res.Error == nil && res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return res.Error
+}
+
+// ListPermissionsByGroups returns the distinct permissions granted
+// by the roles mapped to any of the groups
+func ListPermissionsByGroups(orgID string, groups []string) ([]string, error) {
+	if len(groups) == 0 {
+		return nil, nil
+	}
+	var permissions []string
+	err := DB.Raw(`
+	SELECT DISTINCT UNNEST(permissions)
+	FROM private.roles
+	WHERE org_id = ? AND groups && ?::TEXT[]
+	ORDER BY 1`, orgID, pq.StringArray(groups)).
+		Scan(&permissions).Error
+	return permissions, err
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
@@ -8,12 +9,17 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/hoophq/hoop/common/log"
+	"github.com/hoophq/hoop/gateway/session/blobstore"
+	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
 
 const (
 	tableSessions string = "private.sessions"
 	tableBlobs    string = "private.blobs"
+
+	tableSessionStatements string = "private.session_statements"
 )
 
 type BlobInputType string
@@ -43,6 +49,16 @@ type SessionOption struct {
 	EndDate        sql.NullString
 	Offset         int
 	Limit          int
+
+	// Connections restricts the listing to a subset of connections when it's not empty
+	Connections []string
+}
+
+func (o SessionOption) getConnectionsAsArray() any {
+	if len(o.Connections) == 0 {
+		return nil
+	}
+	return pq.StringArray(o.Connections)
 }
 
 func NewSessionOption() SessionOption {
@@ -70,16 +86,64 @@ type Session struct {
 	BlobInput            BlobInputType     `gorm:"column:blob_input;->"`
 	BlobStream           json.RawMessage   `gorm:"column:blob_stream;->"`
 	BlobStreamSize       int64             `gorm:"column:blob_stream_size;->"`
+	BlobStreamBackend    string            `gorm:"column:blob_stream_backend;->"`
+	BlobStreamKey        string            `gorm:"column:blob_stream_key;->"`
 	UserID               string            `gorm:"column:user_id"`
 	UserName             string            `gorm:"column:user_name"`
 	UserEmail            string            `gorm:"column:user_email"`
 	Status               string            `gorm:"column:status"`
 	ExitCode             *int              `gorm:"column:exit_code"`
 
+	GuardRailsInfo []SessionGuardRailsInfo `gorm:"column:guardrails_info;serializer:json;->"`
+	BreakGlass     bool                    `gorm:"column:break_glass;->"`
+
+	Integrity           *SessionIntegrity `gorm:"column:integrity;serializer:json;->"`
+	IntegrityAnchoredAt *time.Time        `gorm:"column:integrity_anchored_at;->"`
+	StreamPurgedAt      *time.Time        `gorm:"column:stream_purged_at;->"`
+	OutputPurgedAt      *time.Time        `gorm:"column:output_purged_at;->"`
+
 	CreatedAt  time.Time  `gorm:"column:created_at"`
 	EndSession *time.Time `gorm:"column:ended_at"`
 }
 
+// SessionGuardRailsInfo is a guard rail rule that matched
+// the session without blocking it (warn, review or mask actions)
+type SessionGuardRailsInfo struct {
+	Direction string    `json:"direction"`
+	RuleType  string    `json:"rule_type"`
+	Action    string    `json:"action"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SessionIntegrity is the seal of the event stream of a session, the digest is
+// the last hash of the chain of events and the signature is made by the gateway key
+type SessionIntegrity struct {
+	Version   string `json:"version"`
+	Algorithm string `json:"algorithm"`
+	Events    int    `json:"events"`
+	Digest    string `json:"digest"`
+	// WalVerified indicates if the chain of the write ahead log was intact when the session was sealed
+	WalVerified bool      `json:"wal_verified"`
+	Signature   string    `json:"signature,omitempty"`
+	KeyID       string    `json:"key_id,omitempty"`
+	SealedAt    time.Time `json:"sealed_at"`
+	// Purge is set when a retention policy changed the event stream after it was sealed
+	Purge *SessionIntegrityPurge `json:"purge,omitempty"`
+}
+
+// SessionIntegrityPurge records the purge of the event stream of a sealed session, the seal is signed
+// again with this record. It keeps the attributes of the first seal of the session to verify its anchor.
+type SessionIntegrityPurge struct {
+	Mode     string    `json:"mode"`
+	PurgedAt time.Time `json:"purged_at"`
+	// StreamVerified indicates if the event stream matched its seal when it was purged
+	StreamVerified bool   `json:"stream_verified"`
+	Events         int    `json:"events"`
+	Digest         string `json:"digest"`
+	Signature      string `json:"signature,omitempty"`
+}
+
 type SessionDone struct {
 	ID         string
 	OrgID      string
@@ -88,6 +152,21 @@ type SessionDone struct {
 	ExitCode   *int
 	Status     string
 	EndSession *time.Time
+	Statements []SessionStatement
+	Integrity  *SessionIntegrity
+}
+
+// SessionStatement is a statement executed by a database session
+type SessionStatement struct {
+	OrgID        string    `gorm:"column:org_id"`
+	SessionID    string    `gorm:"column:session_id"`
+	ConnectionID string    `gorm:"column:connection_id"`
+	Statement    string    `gorm:"column:statement"`
+	Rows         *int64    `gorm:"column:rows"`
+	ErrorCode    *string   `gorm:"column:error_code"`
+	ErrorMessage *string   `gorm:"column:error_message"`
+	StartedAt    time.Time `gorm:"column:started_at"`
+	EndedAt      time.Time `gorm:"column:ended_at"`
 }
 
 type sessionDone struct {
@@ -108,10 +187,12 @@ type SessionList struct {
 }
 
 type Blob struct {
-	ID         string          `gorm:"column:id"`
-	OrgID      string          `gorm:"column:org_id"`
-	BlobStream json.RawMessage `gorm:"column:blob_stream"`
-	Type       string          `gorm:"column:type"`
+	ID             string          `gorm:"column:id"`
+	OrgID          string          `gorm:"column:org_id"`
+	BlobStream     json.RawMessage `gorm:"column:blob_stream"`
+	Type           string          `gorm:"column:type"`
+	StorageBackend string          `gorm:"column:storage_backend"`
+	StorageKey     sql.NullString  `gorm:"column:storage_key"`
 }
 
 func GetSessionByID(orgID, sid string) (*Session, error) {
@@ -120,7 +201,9 @@ func GetSessionByID(orgID, sid string) (*Session, error) {
 	SELECT
 		s.id, s.org_id, s.connection, s.connection_type, s.connection_subtype, s.verb, s.labels, s.exit_code,
 		s.user_id, s.user_name, s.user_email, s.status, s.metadata, s.integrations_metadata, s.metrics,
-		bi.blob_stream AS blob_input, bs.blob_stream AS blob_stream, metrics->>'event_size' AS blob_stream_size,
+		s.guardrails_info, s.break_glass, bi.blob_stream AS blob_input, bs.blob_stream AS blob_stream, metrics->>'event_size' AS blob_stream_size,
+		bs.storage_backend AS blob_stream_backend, bs.storage_key AS blob_stream_key,
+		s.integrity, s.integrity_anchored_at, s.stream_purged_at, s.output_purged_at,
 		s.created_at, s.ended_at
 	FROM private.sessions s
 	LEFT JOIN private.blobs AS bi ON bi.type = 'session-input' AND  bi.id = s.blob_input_id
@@ -133,9 +216,36 @@ func GetSessionByID(orgID, sid string) (*Session, error) {
 		}
 		return nil, err
 	}
+	if err := session.loadObjectBlobStream(); err != nil {
+		return nil, err
+	}
 	return &session, nil
 }
 
+// loadObjectBlobStream fetches the event stream of sessions
+// persisted in an object store, postgres streams are loaded by the query
+func (s *Session) loadObjectBlobStream() error {
+	if s.BlobStreamBackend == "" || s.BlobStreamBackend == blobstore.BackendPostgres {
+		return nil
+	}
+	store, err := blobstore.ObjectStoreFor(s.BlobStreamBackend)
+	if err != nil {
+		return fmt.Errorf("failed loading session blob stream, reason=%v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	data, err := store.Get(ctx, s.BlobStreamKey)
+	switch err {
+	case blobstore.ErrNotFound:
+		return fmt.Errorf("%w, backend=%v, key=%v", ErrBlobStreamNotFound, s.BlobStreamBackend, s.BlobStreamKey)
+	case nil:
+		s.BlobStream = data
+	default:
+		return fmt.Errorf("failed loading session blob stream, reason=%v", err)
+	}
+	return nil
+}
+
 func ListSessions(orgID string, opt SessionOption) (*SessionList, error) {
 	sessionList := &SessionList{Items: []Session{}}
 	return sessionList, DB.Transaction(func(tx *gorm.DB) error {
@@ -146,6 +256,10 @@ func ListSessions(orgID string, opt SessionOption) (*SessionList, error) {
 		(
 			COALESCE(s.user_id::text, '') LIKE @user_id AND
 			COALESCE(s.connection::text, '') LIKE @connection AND
+			CASE WHEN (@connections)::text[] IS NOT NULL
+				THEN s.connection = ANY((@connections)::text[])
+				ELSE true
+			END AND
 			COALESCE(s.connection_type::text, '')::TEXT LIKE @connection_type AND
 			CASE WHEN (@start_date)::text IS NOT NULL
 				THEN s.created_at BETWEEN @start_date AND @end_date
@@ -155,6 +269,7 @@ func ListSessions(orgID string, opt SessionOption) (*SessionList, error) {
 			"org_id":          orgID,
 			"user_id":         opt.User,
 			"connection":      opt.ConnectionName,
+			"connections":     opt.getConnectionsAsArray(),
 			"connection_type": opt.ConnectionType,
 			"start_date":      opt.StartDate,
 			"end_date":        opt.EndDate,
@@ -167,13 +282,17 @@ func ListSessions(orgID string, opt SessionOption) (*SessionList, error) {
 		SELECT
 			s.id, s.org_id, s.connection, s.connection_type, s.connection_subtype, s.verb, s.labels, s.exit_code,
 			s.user_id, s.user_name, s.user_email, s.status, s.metadata, s.integrations_metadata, s.metrics,
-			metrics->>'event_size' AS blob_stream_size,
+			s.guardrails_info, s.break_glass, metrics->>'event_size' AS blob_stream_size,
 			s.created_at, s.ended_at
 		FROM private.sessions s
 		WHERE s.org_id = @org_id AND
 		(
 			COALESCE(s.user_id::text, '') LIKE @user_id AND
 			COALESCE(s.connection::text, '') LIKE @connection AND
+			CASE WHEN (@connections)::text[] IS NOT NULL
+				THEN s.connection = ANY((@connections)::text[])
+				ELSE true
+			END AND
 			COALESCE(s.connection_type::text, '')::TEXT LIKE @connection_type AND
 			CASE WHEN (@start_date)::text IS NOT NULL
 				THEN s.created_at BETWEEN @start_date AND @end_date
@@ -187,6 +306,7 @@ func ListSessions(orgID string, opt SessionOption) (*SessionList, error) {
 			"org_id":          orgID,
 			"user_id":         opt.User,
 			"connection":      opt.ConnectionName,
+			"connections":     opt.getConnectionsAsArray(),
 			"connection_type": opt.ConnectionType,
 			"start_date":      opt.StartDate,
 			"end_date":        opt.EndDate,
@@ -211,10 +331,11 @@ func UpsertSession(sess Session) error {
 		}
 
 		blobInput := Blob{
-			ID:         blobInputID.String,
-			OrgID:      sess.OrgID,
-			Type:       "session-input",
-			BlobStream: json.RawMessage(fmt.Sprintf("[%q]", sess.BlobInput)),
+			ID:             blobInputID.String,
+			OrgID:          sess.OrgID,
+			Type:           "session-input",
+			BlobStream:     json.RawMessage(fmt.Sprintf("[%q]", sess.BlobInput)),
+			StorageBackend: blobstore.BackendPostgres,
 		}
 		res := tx.Table(tableBlobs).
 			Where("org_id = ? AND id = ?", sess.OrgID, blobInputID.String).
@@ -252,28 +373,28 @@ func UpsertSession(sess Session) error {
 
 // UpdateSessionEventStream updates a session partially
 func UpdateSessionEventStream(sess SessionDone) error {
-	return DB.Transaction(func(tx *gorm.DB) error {
-		// generate deterministic uuid based on the session id to avoid duplicates
-		blobStreamID := sql.NullString{
-			String: uuid.NewSHA1(uuid.NameSpaceURL, []byte(fmt.Sprintf("blobstream:%s", sess.ID))).String(),
-			Valid:  true,
+	return writeSessionBlobStream(sess.OrgID, sess.ID, sess.BlobStream, func(tx *gorm.DB, blobStreamID sql.NullString) error {
+		// the statements are replaced when the session is persisted again
+		err := tx.Exec(`DELETE FROM private.session_statements WHERE org_id = ? AND session_id = ?`,
+			sess.OrgID, sess.ID).Error
+		if err != nil {
+			return fmt.Errorf("failed removing session statements, reason=%v", err)
 		}
-
-		blobStream := Blob{
-			ID:         blobStreamID.String,
-			OrgID:      sess.OrgID,
-			BlobStream: sess.BlobStream,
-			Type:       "session-stream",
-		}
-		res := tx.Table(tableBlobs).
-			Where("org_id = ? AND id = ?", sess.OrgID, blobStreamID.String).
-			Updates(blobStream)
-		if res.Error == nil && res.RowsAffected == 0 {
-			res.Error = tx.Table(tableBlobs).Create(blobStream).Error
+		if len(sess.Statements) > 0 {
+			if err := tx.Table(tableSessionStatements).CreateInBatches(sess.Statements, 500).Error; err != nil {
+				return fmt.Errorf("failed creating session statements, reason=%v", err)
+			}
 		}
 
-		if res.Error != nil {
-			return fmt.Errorf("failed creating session blob stream, reason=%v", res.Error)
+		// a new event stream replaces the previous seal and its anchor
+		integrity, err := encodeIntegrity(sess.Integrity)
+		if err != nil {
+			return err
+		}
+		err = tx.Exec(`UPDATE private.sessions SET integrity = ?::JSONB, integrity_anchored_at = NULL WHERE org_id = ? AND id = ?`,
+			integrity, sess.OrgID, sess.ID).Error
+		if err != nil {
+			return fmt.Errorf("failed updating session integrity, reason=%v", err)
 		}
 
 		// update: status, labels, metrics, end_date, exit_code, event_stream
@@ -292,6 +413,98 @@ func UpdateSessionEventStream(sess SessionDone) error {
 	})
 }
 
+// writeSessionBlobStream persists the event stream of a session and calls fn in the same transaction.
+// Streams kept in an object store are uploaded before the transaction under a new key, the object
+// is removed if the transaction fails, otherwise the object it replaces is removed.
+func writeSessionBlobStream(orgID, sid string, data json.RawMessage, fn func(tx *gorm.DB, blobStreamID sql.NullString) error) error {
+	// generate deterministic uuid based on the session id to avoid duplicates
+	blobStreamID := sql.NullString{
+		String: uuid.NewSHA1(uuid.NameSpaceURL, []byte(fmt.Sprintf("blobstream:%s", sid))).String(),
+		Valid:  true,
+	}
+	store := blobstore.ObjectStore()
+	if store == nil {
+		return DB.Transaction(func(tx *gorm.DB) error {
+			store := blobstore.NewPostgres(tx)
+			if err := upsertSessionBlobStream(tx, orgID, blobStreamID.String, store.Backend(), blobStreamID.String); err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute*5)
+			defer cancel()
+			if err := store.Put(ctx, blobStreamID.String, data); err != nil {
+				return fmt.Errorf("failed storing session blob stream (%v), reason=%v", store.Backend(), err)
+			}
+			return fn(tx, blobStreamID)
+		})
+	}
+
+	// a new key per write keeps the object referenced by the current metadata until the transaction commits
+	key := store.Key(orgID, fmt.Sprintf("%s-%s", blobStreamID.String, uuid.NewString()))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*5)
+	defer cancel()
+	if err := store.Put(ctx, key, data); err != nil {
+		return fmt.Errorf("failed storing session blob stream (%v), reason=%v", store.Backend(), err)
+	}
+	var prevKey sql.NullString
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Raw(`SELECT storage_key FROM private.blobs WHERE org_id = ? AND id = ? AND storage_backend = ?`,
+			orgID, blobStreamID.String, store.Backend()).
+			Scan(&prevKey).Error
+		if err != nil {
+			return fmt.Errorf("failed fetching session blob stream, reason=%v", err)
+		}
+		if err := upsertSessionBlobStream(tx, orgID, blobStreamID.String, store.Backend(), key); err != nil {
+			return err
+		}
+		return fn(tx, blobStreamID)
+	})
+	// remove the object that is no longer referenced by the metadata
+	staleKey := prevKey.String
+	if err != nil {
+		staleKey = key
+	}
+	if staleKey != "" {
+		if delErr := store.Delete(ctx, staleKey); delErr != nil {
+			log.Warnf("sid=%v - failed removing stale session blob stream, key=%v, reason=%v", sid, staleKey, delErr)
+		}
+	}
+	return err
+}
+
+// upsertSessionBlobStream writes the metadata of the event stream of a session
+func upsertSessionBlobStream(tx *gorm.DB, orgID, blobStreamID, backend, key string) error {
+	blobStream := Blob{
+		ID:             blobStreamID,
+		OrgID:          orgID,
+		Type:           "session-stream",
+		StorageBackend: backend,
+		StorageKey:     sql.NullString{String: key, Valid: true},
+	}
+	res := tx.Table(tableBlobs).
+		Where("org_id = ? AND id = ?", orgID, blobStreamID).
+		Select("blob_stream", "storage_backend", "storage_key").
+		Updates(blobStream)
+	if res.Error == nil && res.RowsAffected == 0 {
+		res.Error = tx.Table(tableBlobs).Create(blobStream).Error
+	}
+	if res.Error != nil {
+		return fmt.Errorf("failed creating session blob stream, reason=%v", res.Error)
+	}
+	return nil
+}
+
+// ListSessionStatements returns the statements of a session ordered by the time they started
+func ListSessionStatements(orgID, sid string) ([]SessionStatement, error) {
+	var items []SessionStatement
+	err := DB.Raw(`
+	SELECT org_id, session_id, connection_id, statement, rows, error_code, error_message, started_at, ended_at
+	FROM private.session_statements
+	WHERE org_id = ? AND session_id = ?
+	ORDER BY started_at ASC`, orgID, sid).
+		Find(&items).Error
+	return items, err
+}
+
 func UpdateSessionIntegrationMetadata(orgID, sid string, metadata map[string]any) error {
 	res := DB.Table(tableSessions).
 		Where("org_id = ? AND id = ?", orgID, sid).
@@ -312,6 +525,66 @@ func UpdateSessionMetadata(orgID, userEmail, sid string, metadata map[string]any
 	return res.Error
 }
 
+// MarkSessionBreakGlass flags a session opened without waiting for its review
+func MarkSessionBreakGlass(orgID, sid string) error {
+	res := DB.Exec(`UPDATE private.sessions SET break_glass = TRUE WHERE org_id = ? AND id = ?`, orgID, sid)
+	if res.Error == nil && res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return res.Error
+}
+
+// AppendSessionGuardRailsInfo adds the guard rails matches to the session
+func AppendSessionGuardRailsInfo(orgID, sid string, info []SessionGuardRailsInfo) error {
+	if len(info) == 0 {
+		return nil
+	}
+	jsonInfo, err := json.Marshal(info)
+	if err != nil {
+		return fmt.Errorf("failed encoding guard rails info, reason=%v", err)
+	}
+	res := DB.Exec(`
+	UPDATE private.sessions
+	SET guardrails_info = COALESCE(guardrails_info, '[]'::JSONB) || ?::JSONB
+	WHERE org_id = ? AND id = ?`, string(jsonInfo), orgID, sid)
+	if res.Error == nil && res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return res.Error
+}
+
+func encodeIntegrity(seal *SessionIntegrity) (sql.NullString, error) {
+	if seal == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(seal)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("failed encoding session integrity, reason=%v", err)
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// ListSessionsToAnchor returns the sealed sessions of all organizations that
+// were not anchored yet, ordered by the time they ended
+func ListSessionsToAnchor(limit int) ([]Session, error) {
+	var items []Session
+	return items, DB.Table(tableSessions).
+		Select("id, org_id, integrity, created_at, ended_at").
+		Where("integrity IS NOT NULL AND integrity_anchored_at IS NULL").
+		Order("ended_at ASC").
+		Limit(limit).
+		Find(&items).Error
+}
+
+// MarkSessionsAnchored records when the digest of the sessions were anchored
+func MarkSessionsAnchored(sessionIDs []string, anchoredAt time.Time) error {
+	if len(sessionIDs) == 0 {
+		return nil
+	}
+	return DB.Exec(`UPDATE private.sessions SET integrity_anchored_at = ? WHERE id IN ?`,
+		anchoredAt, sessionIDs).Error
+}
+
 func GetSessionJiraIssueByID(orgID, sid string) (string, error) {
 	var jiraIssueKey string
 	err := DB.Raw(`
@@ -275,13 +275,14 @@ CREATE VIEW reviews AS
     SELECT
         id, org_id, session_id, connection_id, connection_name, type, blob_input_id,
         input_env_vars, input_client_args, access_duration_sec, status,
-        owner_id, owner_email, owner_name, owner_slack_id, created_at, revoked_at
+        owner_id, owner_email, owner_name, owner_slack_id, policy, extension,
+        access_start_at, access_reason, jira_issue_key, created_at, revoked_at
     FROM private.reviews;
 
 CREATE VIEW review_groups AS
     SELECT
         id, org_id, review_id, group_name, status,
-        owner_id, owner_email, owner_name, owner_slack_id, reviewed_at
+        owner_id, owner_email, owner_name, owner_slack_id, approvals, escalation, reviewed_at
     FROM private.review_groups;
 
 CREATE FUNCTION blob_input(reviews) RETURNS SETOF blobs ROWS 1 AS $$
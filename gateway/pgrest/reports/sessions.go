@@ -3,6 +3,7 @@ package pgreports
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/hoophq/hoop/gateway/pgrest"
@@ -74,15 +75,60 @@ func GetSessionReport(ctx pgrest.OrgContext, opts ...*SessionOption) (*pgrest.Se
 
 	request["org_id"] = ctx.GetOrgID()
 	sessionReport := pgrest.SessionReport{Items: []pgrest.SessionReportItem{}}
-	err := pgrest.New("/rpc/session_report").RpcCreate(request).DecodeInto(&sessionReport.Items)
-	switch err {
-	case pgrest.ErrNotFound:
-		return &sessionReport, nil
-	default:
-		for _, item := range sessionReport.Items {
-			sessionReport.TotalRedactCount += item.RedactTotal
-			sessionReport.TotalTransformedBytes += item.TransformedBytes
+	for _, connectionName := range reportConnections(ctx, fmt.Sprintf("%v", request["connection_name"])) {
+		request["connection_name"] = connectionName
+		items, err := sessionReportRPC(request)
+		if err != nil {
+			return &sessionReport, err
 		}
-		return &sessionReport, err
+		sessionReport.Items = mergeReportItems(sessionReport.Items, items)
 	}
+	for _, item := range sessionReport.Items {
+		sessionReport.TotalRedactCount += item.RedactTotal
+		sessionReport.TotalTransformedBytes += item.TransformedBytes
+	}
+	return &sessionReport, nil
+}
+
+// reportConnections returns the connections filters of the report, an empty name reports on all connections.
+// Api tokens restricted to a list of connections only report on their connections.
+func reportConnections(ctx pgrest.OrgContext, connectionName string) []string {
+	var tokenConnections []string
+	if tokenCtx, ok := ctx.(pgrest.TokenContext); ok {
+		tokenConnections = tokenCtx.GetTokenConnections()
+	}
+	switch {
+	case len(tokenConnections) == 0:
+		return []string{connectionName}
+	case connectionName == "":
+		return tokenConnections
+	case slices.Contains(tokenConnections, connectionName):
+		return []string{connectionName}
+	}
+	return nil
+}
+
+// mergeReportItems sums the items of the same resource and info type
+func mergeReportItems(items, newItems []pgrest.SessionReportItem) []pgrest.SessionReportItem {
+	for _, newItem := range newItems {
+		idx := slices.IndexFunc(items, func(item pgrest.SessionReportItem) bool {
+			return item.ResourceName == newItem.ResourceName && item.InfoType == newItem.InfoType
+		})
+		if idx == -1 {
+			items = append(items, newItem)
+			continue
+		}
+		items[idx].RedactTotal += newItem.RedactTotal
+		items[idx].TransformedBytes += newItem.TransformedBytes
+	}
+	return items
+}
+
+var sessionReportRPC = func(request map[OptionKey]any) ([]pgrest.SessionReportItem, error) {
+	var items []pgrest.SessionReportItem
+	err := pgrest.New("/rpc/session_report").RpcCreate(request).DecodeInto(&items)
+	if err == pgrest.ErrNotFound {
+		return nil, nil
+	}
+	return items, err
 }
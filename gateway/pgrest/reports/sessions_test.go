@@ -0,0 +1,84 @@
+package pgreports
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hoophq/hoop/gateway/pgrest"
+	"github.com/hoophq/hoop/gateway/storagev2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSessionReportTokenConnections(t *testing.T) {
+	itemsByConnection := map[string][]pgrest.SessionReportItem{
+		"pg-prod":  {{ResourceName: "john@doe.com", InfoType: "EMAIL_ADDRESS", RedactTotal: 2, TransformedBytes: 20}},
+		"pg-stage": {{ResourceName: "john@doe.com", InfoType: "EMAIL_ADDRESS", RedactTotal: 3, TransformedBytes: 30}},
+		"mysql":    {{ResourceName: "jane@doe.com", InfoType: "PHONE_NUMBER", RedactTotal: 5, TransformedBytes: 50}},
+	}
+	var requested []string
+	sessionReportRPC = func(request map[OptionKey]any) ([]pgrest.SessionReportItem, error) {
+		connectionName := fmt.Sprintf("%v", request["connection_name"])
+		requested = append(requested, connectionName)
+		if connectionName == "" {
+			var items []pgrest.SessionReportItem
+			for _, name := range []string{"pg-prod", "pg-stage", "mysql"} {
+				items = mergeReportItems(items, itemsByConnection[name])
+			}
+			return items, nil
+		}
+		return itemsByConnection[connectionName], nil
+	}
+	for _, tt := range []struct {
+		msg           string
+		ctx           pgrest.OrgContext
+		opts          []*SessionOption
+		wantRequested []string
+		wantItems     []pgrest.SessionReportItem
+		wantRedact    int64
+	}{
+		{
+			msg:           "it must report on all connections without an api token",
+			ctx:           storagev2.NewOrganizationContext("org"),
+			wantRequested: []string{""},
+			wantItems: []pgrest.SessionReportItem{
+				{ResourceName: "john@doe.com", InfoType: "EMAIL_ADDRESS", RedactTotal: 5, TransformedBytes: 50},
+				{ResourceName: "jane@doe.com", InfoType: "PHONE_NUMBER", RedactTotal: 5, TransformedBytes: 50},
+			},
+			wantRedact: 10,
+		},
+		{
+			msg:           "it must report only on the connections of a restricted api token",
+			ctx:           storagev2.NewOrganizationContext("org").WithAPIToken("token", []string{"pg-prod", "pg-stage"}),
+			wantRequested: []string{"pg-prod", "pg-stage"},
+			wantItems: []pgrest.SessionReportItem{
+				{ResourceName: "john@doe.com", InfoType: "EMAIL_ADDRESS", RedactTotal: 5, TransformedBytes: 50},
+			},
+			wantRedact: 5,
+		},
+		{
+			msg:           "it must report on a connection allowed by the api token",
+			ctx:           storagev2.NewOrganizationContext("org").WithAPIToken("token", []string{"pg-prod"}),
+			opts:          []*SessionOption{{OptionKey: "connection_name", OptionVal: "pg-prod"}},
+			wantRequested: []string{"pg-prod"},
+			wantItems: []pgrest.SessionReportItem{
+				{ResourceName: "john@doe.com", InfoType: "EMAIL_ADDRESS", RedactTotal: 2, TransformedBytes: 20},
+			},
+			wantRedact: 2,
+		},
+		{
+			msg:       "it must return an empty report for a connection not allowed by the api token",
+			ctx:       storagev2.NewOrganizationContext("org").WithAPIToken("token", []string{"pg-prod"}),
+			opts:      []*SessionOption{{OptionKey: "connection_name", OptionVal: "mysql"}},
+			wantItems: []pgrest.SessionReportItem{},
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			requested = nil
+			report, err := GetSessionReport(tt.ctx, tt.opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantRequested, requested)
+			assert.Equal(t, tt.wantItems, report.Items)
+			assert.Equal(t, tt.wantRedact, report.TotalRedactCount)
+		})
+	}
+}
@@ -41,6 +41,11 @@ func (r *review) Upsert(rev *types.Review) error {
 		"owner_name":          rev.ReviewOwner.Name,
 		"owner_slack_id":      rev.ReviewOwner.SlackID,
 		"revoked_at":          rev.RevokeAt,
+		"policy":              rev.Policy,
+		"extension":           rev.Extension,
+		"access_start_at":     rev.AccessStartAt,
+		"access_reason":       toStringPtr(rev.AccessReason),
+		"jira_issue_key":      toStringPtr(rev.JiraIssueKey),
 		// required only for migrating resources from xtdb to postgrest
 		"created_at": toStringPtr(createdAt),
 	}).Error()
@@ -67,6 +72,8 @@ func (r *review) Upsert(rev *types.Review) error {
 			"group_name":  revgroup.Group,
 			"status":      revgroup.Status,
 			"reviewed_at": revgroup.ReviewDate,
+			"approvals":   revgroup.Approvals,
+			"escalation":  revgroup.Escalation,
 		}
 		var reviewedBy types.ReviewOwner
 		if revgroup.ReviewedBy != nil {
@@ -133,6 +140,26 @@ func (r *review) FetchOneBySid(ctx pgrest.OrgContext, sid string) (*types.Review
 	return parseReview(rev), nil
 }
 
+// FetchAllPendingWithPolicy returns the pending reviews of all organizations which have a policy,
+// break-glass reviews are pending until acknowledged and they never expire
+func (r *review) FetchAllPendingWithPolicy() ([]types.Review, error) {
+	var items []Review
+	err := pgrest.New("/reviews?status=eq.PENDING&type=neq.break_glass&policy=not.is.null&select=*,review_groups(*),blob_input(*)").
+		List().
+		DecodeInto(&items)
+	if err != nil {
+		if err == pgrest.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var result []types.Review
+	for _, r := range items {
+		result = append(result, *parseReview(r))
+	}
+	return result, nil
+}
+
 func (r *review) FetchJit(ctx pgrest.OrgContext, ownerUserID, connectionID string) (*types.Review, error) {
 	var rev Review
 	err := pgrest.New("/reviews?org_id=eq.%s&type=eq.jit&status=eq.APPROVED&owner_id=eq.%s&connection_id=eq.%s&select=*,review_groups(*)&order=created_at.desc&limit=1",
@@ -149,6 +176,42 @@ func (r *review) FetchJit(ctx pgrest.OrgContext, ownerUserID, connectionID strin
 	return parseReview(rev), nil
 }
 
+// CountBreakGlass returns the number of break-glass reviews of a user
+// in a connection created since the given time
+func (r *review) CountBreakGlass(ctx pgrest.OrgContext, ownerUserID, connectionID string, since time.Time) (int, error) {
+	var items []Review
+	err := pgrest.New("/reviews?org_id=eq.%s&type=eq.break_glass&owner_id=eq.%s&connection_id=eq.%s&created_at=gte.%s&select=id",
+		ctx.GetOrgID(),
+		url.QueryEscape(ownerUserID),
+		url.QueryEscape(connectionID),
+		since.UTC().Format("2006-01-02T15:04:05"),
+	).List().DecodeInto(&items)
+	if err != nil && err != pgrest.ErrNotFound {
+		return 0, err
+	}
+	return len(items), nil
+}
+
+// FetchScheduled returns the approved scheduled review of a user which
+// has an access window to the connection at the given time
+func (r *review) FetchScheduled(ctx pgrest.OrgContext, ownerUserID, connectionID string, t time.Time) (*types.Review, error) {
+	ts := t.UTC().Format("2006-01-02T15:04:05")
+	var rev Review
+	err := pgrest.New("/reviews?org_id=eq.%s&type=eq.scheduled&status=eq.APPROVED&owner_id=eq.%s&connection_id=eq.%s&access_start_at=lte.%s&revoked_at=gt.%s&select=*,review_groups(*)&order=access_start_at.desc&limit=1",
+		ctx.GetOrgID(),
+		url.QueryEscape(ownerUserID),
+		url.QueryEscape(connectionID),
+		ts, ts,
+	).FetchOne().DecodeInto(&rev)
+	if err != nil {
+		if err == pgrest.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return parseReview(rev), nil
+}
+
 func ToJson(rev types.Review) *types.ReviewJSON {
 	return &types.ReviewJSON{
 		Id:        rev.Id,
@@ -169,7 +232,38 @@ func ToJson(rev types.Review) *types.ReviewJSON {
 			Id:   rev.Connection.Id,
 			Name: rev.Connection.Name,
 		},
+		Policy:        PolicyOrDefault(rev.Policy),
+		Extension:     rev.Extension,
+		AccessStartAt: rev.AccessStartAt,
+		AccessReason:  rev.AccessReason,
+		JiraIssueKey:  rev.JiraIssueKey,
+	}
+}
+
+// PolicyOrDefault returns the policy of a review, reviews without a policy
+// require an approval of every group, a single approval is enough for each group.
+func PolicyOrDefault(p *types.ReviewPolicy) types.ReviewPolicy {
+	policy := types.ReviewPolicy{MinApprovalsPerGroup: 1, MinApprovers: 1, Mode: types.ReviewPolicyModeAll}
+	if p == nil {
+		return policy
+	}
+	if p.MinApprovalsPerGroup > 0 {
+		policy.MinApprovalsPerGroup = p.MinApprovalsPerGroup
+	}
+	if p.MinApprovers > 0 {
+		policy.MinApprovers = p.MinApprovers
 	}
+	if p.Mode != "" {
+		policy.Mode = p.Mode
+	}
+	policy.RequiredGroup = p.RequiredGroup
+	policy.ExpirationSec = p.ExpirationSec
+	policy.EscalationSec = p.EscalationSec
+	policy.EscalationGroup = p.EscalationGroup
+	policy.BreakGlass = p.BreakGlass
+	policy.BreakGlassDurationSec = p.BreakGlassDurationSec
+	policy.BreakGlassMaxPerDay = p.BreakGlassMaxPerDay
+	return policy
 }
 
 func parseReview(r Review) *types.Review {
@@ -200,6 +294,11 @@ func parseReview(r Review) *types.Review {
 		// when the entity exists this field is a map, otherwise is a string containing the xtid
 		ConnectionId:    r.ConnectionID,
 		ReviewGroupsIds: []string{},
+		Policy:          r.Policy,
+		Extension:       r.Extension,
+		AccessStartAt:   r.GetAccessStartAt(),
+		AccessReason:    toString(r.AccessReason),
+		JiraIssueKey:    toString(r.JiraIssueKey),
 	}
 	for _, rg := range r.ReviewGroups {
 		revGroup := types.ReviewGroup{
@@ -208,6 +307,8 @@ func parseReview(r Review) *types.Review {
 			Status:     types.ReviewStatus(rg.Status),
 			ReviewedBy: nil,
 			ReviewDate: rg.ReviewedAt,
+			Approvals:  rg.Approvals,
+			Escalation: rg.Escalation,
 		}
 		if rg.OwnerUserID != nil {
 			revGroup.ReviewedBy = &types.ReviewOwner{
@@ -228,3 +329,45 @@ func (r *review) PatchStatus(ctx pgrest.OrgContext, reviewID string, status type
 		Patch(map[string]any{"status": status}).
 		Error()
 }
+
+// PatchPendingStatus updates the status of a review only if it's pending,
+// it returns pgrest.ErrNotFound when the review was reviewed in the meantime
+func (r *review) PatchPendingStatus(ctx pgrest.OrgContext, reviewID string, status types.ReviewStatus) error {
+	var items []map[string]any
+	return pgrest.New("/reviews?org_id=eq.%s&id=eq.%s&status=eq.PENDING", ctx.GetOrgID(), url.QueryEscape(reviewID)).
+		Patch(map[string]any{"status": status}).
+		DecodeInto(&items)
+}
+
+// PatchEscalationGroup marks a group of a review as an escalation, the group
+// is added as a reviewer when it's not one yet. The status of the other groups is kept.
+func (r *review) PatchEscalationGroup(ctx pgrest.OrgContext, reviewID, group string) error {
+	var items []map[string]any
+	err := pgrest.New("/review_groups?org_id=eq.%s&review_id=eq.%s&group_name=eq.%s",
+		ctx.GetOrgID(), url.QueryEscape(reviewID), url.QueryEscape(group)).
+		Patch(map[string]any{"escalation": true}).
+		DecodeInto(&items)
+	if err != pgrest.ErrNotFound {
+		return err
+	}
+	return pgrest.New("/review_groups").Create(map[string]any{
+		"id":         uuid.NewString(),
+		"org_id":     ctx.GetOrgID(),
+		"review_id":  reviewID,
+		"group_name": group,
+		"status":     types.ReviewStatusPending,
+		"escalation": true,
+	}).Error()
+}
+
+// RevokeAccessByOwner revokes all the approved jit and scheduled reviews of a user
+func (r *review) RevokeAccessByOwner(ctx pgrest.OrgContext, ownerUserID string) error {
+	err := pgrest.New("/reviews?org_id=eq.%s&type=in.(jit,scheduled)&status=eq.APPROVED&owner_id=eq.%s",
+		ctx.GetOrgID(), url.QueryEscape(ownerUserID)).
+		Patch(map[string]any{"status": types.ReviewStatusRevoked}).
+		Error()
+	if err == pgrest.ErrNotFound {
+		return nil
+	}
+	return err
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/hoophq/hoop/gateway/pgrest"
+	"github.com/hoophq/hoop/gateway/storagev2/types"
 )
 
 type ReviewGroup struct {
@@ -18,26 +19,34 @@ type ReviewGroup struct {
 	OwnerName    *string `json:"owner_name"`
 	OwnerSlackID *string `json:"owner_slack_id"`
 	ReviewedAt   *string `json:"reviewed_at"`
+
+	Approvals  []types.ReviewApproval `json:"approvals"`
+	Escalation bool                   `json:"escalation"`
 }
 
 type Review struct {
-	ID                string            `json:"id"`
-	OrgID             string            `json:"org_id"`
-	SessionID         *string           `json:"session_id"`
-	ConnectionID      *string           `json:"connection_id"`
-	ConnectionName    string            `json:"connection_name"`
-	Type              string            `json:"type"`
-	BlobInputID       *string           `json:"blob_input_id"`
-	InputEnvVars      map[string]string `json:"input_env_vars"`
-	InputClientArgs   []string          `json:"input_client_args"`
-	AccessDurationSec int               `json:"access_duration_sec"`
-	Status            string            `json:"status"`
-	OwnerUserID       string            `json:"owner_id"`
-	OwnerEmail        string            `json:"owner_email"`
-	OwnerName         *string           `json:"owner_name"`
-	OwnerSlackID      *string           `json:"owner_slack_id"`
-	CreatedAt         string            `json:"created_at"`
-	RevokedAt         *string           `json:"revoked_at"`
+	ID                string                 `json:"id"`
+	OrgID             string                 `json:"org_id"`
+	SessionID         *string                `json:"session_id"`
+	ConnectionID      *string                `json:"connection_id"`
+	ConnectionName    string                 `json:"connection_name"`
+	Type              string                 `json:"type"`
+	BlobInputID       *string                `json:"blob_input_id"`
+	InputEnvVars      map[string]string      `json:"input_env_vars"`
+	InputClientArgs   []string               `json:"input_client_args"`
+	AccessDurationSec int                    `json:"access_duration_sec"`
+	Status            string                 `json:"status"`
+	OwnerUserID       string                 `json:"owner_id"`
+	OwnerEmail        string                 `json:"owner_email"`
+	OwnerName         *string                `json:"owner_name"`
+	OwnerSlackID      *string                `json:"owner_slack_id"`
+	CreatedAt         string                 `json:"created_at"`
+	RevokedAt         *string                `json:"revoked_at"`
+	Policy            *types.ReviewPolicy    `json:"policy"`
+	Extension         *types.ReviewExtension `json:"extension"`
+	AccessStartAt     *string                `json:"access_start_at"`
+	AccessReason      *string                `json:"access_reason"`
+	JiraIssueKey      *string                `json:"jira_issue_key"`
 
 	BlobInput    *pgrest.Blob  `json:"blob_input"`
 	ReviewGroups []ReviewGroup `json:"review_groups"`
@@ -63,6 +72,14 @@ func (r *Review) GetRevokedAt() *time.Time {
 	return nil
 }
 
+func (r *Review) GetAccessStartAt() *time.Time {
+	if r.AccessStartAt != nil {
+		startAt, _ := time.ParseInLocation("2006-01-02T15:04:05", *r.AccessStartAt, time.UTC)
+		return &startAt
+	}
+	return nil
+}
+
 func (r *Review) GetBlobInput() (v string) {
 	if r.BlobInput != nil {
 		if len(r.BlobInput.BlobStream) > 0 {
@@ -10,6 +10,12 @@ type Context interface {
 	GetUserGroups() []string
 }
 
+// TokenContext is a context authenticated with an api token,
+// the token could be restricted to a list of connections
+type TokenContext interface {
+	GetTokenConnections() []string
+}
+
 type OrgContext interface {
 	GetOrgID() string
 }
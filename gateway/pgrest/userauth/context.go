@@ -26,6 +26,14 @@ type Context struct {
 	UserAnonPicture       string
 	UserAnonEmail         string
 	UserAnonEmailVerified *bool
+
+	// TokenID is set when the user is authenticated with an api token,
+	// the token could be restricted to a list of connections
+	TokenID          string
+	TokenConnections []string
+
+	// Permissions granted by the custom roles of the user groups
+	Permissions []string
 }
 
 // IsEmpty returns true if the user is not logged in and has not signed up yet.
@@ -43,6 +51,9 @@ func (c *Context) GetUserGroups() []string { return c.UserGroups }
 func (c *Context) IsAdmin() bool           { return slices.Contains(c.UserGroups, types.GroupAdmin) }
 func (c *Context) IsAuditor() bool         { return slices.Contains(c.UserGroups, types.GroupAuditor) }
 
+// GetTokenConnections returns the connections an api token is restricted to
+func (c *Context) GetTokenConnections() []string { return c.TokenConnections }
+
 func (c Context) ToAPIContext() *types.APIContext {
 	return &types.APIContext{
 		OrgID:          c.OrgID,
@@ -55,5 +66,8 @@ func (c Context) ToAPIContext() *types.APIContext {
 		UserGroups:     c.UserGroups,
 		UserStatus:     string(c.UserStatus),
 		SlackID:        c.UserSlackID,
+
+		TokenID:          c.TokenID,
+		TokenConnections: c.TokenConnections,
 	}
 }
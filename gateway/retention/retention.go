@@ -0,0 +1,226 @@
+package retention
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hoophq/hoop/common/log"
+	"github.com/hoophq/hoop/gateway/appconfig"
+	"github.com/hoophq/hoop/gateway/indexer"
+	"github.com/hoophq/hoop/gateway/models"
+	"github.com/hoophq/hoop/gateway/session/integrity"
+)
+
+const (
+	// ModeEventStream removes the event stream of sessions, the metadata and input are kept
+	ModeEventStream = "event_stream"
+	// ModeOutput removes the output events from the event stream of sessions
+	ModeOutput = "output"
+	// ModeAll removes sessions with all their content
+	ModeAll = "all"
+
+	// MaxRetentionDays is the maximum number of days accepted by a policy (10 years)
+	MaxRetentionDays = 3650
+)
+
+var (
+	purgeBackoffDuration = time.Hour
+	purgeBatchSize       = 500
+)
+
+// ValidatePolicy validates the attributes of a retention policy
+func ValidatePolicy(mode string, retentionDays int) error {
+	switch mode {
+	case ModeEventStream, ModeOutput, ModeAll:
+	default:
+		return fmt.Errorf("invalid mode %q, accepted values are: %v, %v, %v", mode, ModeEventStream, ModeOutput, ModeAll)
+	}
+	if retentionDays <= 0 || retentionDays > MaxRetentionDays {
+		return fmt.Errorf("retention_days must be between 1 and %v", MaxRetentionDays)
+	}
+	return nil
+}
+
+// InitPurgeProcess enforces the retention policies of all organizations periodically
+func InitPurgeProcess() {
+	log.Infof("initializing session retention purge process")
+	go func() {
+		for {
+			if err := purge(time.Now().UTC()); err != nil {
+				log.Warnf("failed purging sessions, reason=%v", err)
+			}
+			time.Sleep(purgeBackoffDuration)
+		}
+	}()
+}
+
+// rule is a policy with the connections it must skip
+type rule struct {
+	policy            models.RetentionPolicy
+	exceptConnections []string
+}
+
+// effectiveRules resolves the policies in the order they must be applied.
+// A connection policy overrides the organization policy of the same mode and
+// the modes are applied from the most to the least destructive one.
+func effectiveRules(policies []models.RetentionPolicy) []rule {
+	overrides := map[string][]string{}
+	for _, p := range policies {
+		if p.ConnectionName.Valid {
+			key := p.OrgID + ":" + p.Mode
+			overrides[key] = append(overrides[key], p.ConnectionName.String)
+		}
+	}
+	var rules []rule
+	for _, mode := range []string{ModeAll, ModeEventStream, ModeOutput} {
+		for _, p := range policies {
+			if p.Mode != mode {
+				continue
+			}
+			r := rule{policy: p}
+			if !p.ConnectionName.Valid {
+				r.exceptConnections = overrides[p.OrgID+":"+p.Mode]
+			}
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+func purge(now time.Time) error {
+	policies, err := models.ListAllRetentionPolicies()
+	if err != nil {
+		return fmt.Errorf("failed fetching retention policies: %v", err)
+	}
+	for _, r := range effectiveRules(policies) {
+		p := r.policy
+		cutoff := now.Add(-time.Duration(p.RetentionDays) * 24 * time.Hour)
+		// purged sessions leave the listing, the ones that failed are skipped by the offset
+		purged, failed := 0, 0
+		for {
+			sessions, err := models.ListSessionsToPurge(p, r.exceptConnections, cutoff, failed, purgeBatchSize)
+			if err != nil {
+				log.With("org", p.OrgID, "policy", p.ID).Warnf("failed listing sessions to purge, reason=%v", err)
+				break
+			}
+			for _, sess := range sessions {
+				if err := purgeSession(sess, p); err != nil {
+					log.With("org", p.OrgID, "policy", p.ID, "sid", sess.ID).Warnf("failed purging session, reason=%v", err)
+					failed++
+					continue
+				}
+				purged++
+			}
+			if len(sessions) < purgeBatchSize {
+				break
+			}
+		}
+		if purged > 0 {
+			log.With("org", p.OrgID, "policy", p.ID).Infof("purged sessions, mode=%v, retention-days=%v, total=%v",
+				p.Mode, p.RetentionDays, purged)
+		}
+	}
+	return nil
+}
+
+func purgeSession(sess models.RetentionSession, policy models.RetentionPolicy) error {
+	switch policy.Mode {
+	case ModeAll:
+		if err := models.PurgeSession(sess, policy); err != nil {
+			return err
+		}
+		return purgeIndex(sess, (*indexer.Indexer).Delete)
+	case ModeEventStream:
+		var seal *models.SessionIntegrity
+		if sess.Integrity != nil {
+			var prevBlobStream json.RawMessage
+			s, err := models.GetSessionByID(sess.OrgID, sess.ID)
+			switch {
+			case err == nil:
+				prevBlobStream = s.BlobStream
+			// the stream is purged anyway, the seal records it could not be verified
+			case errors.Is(err, models.ErrBlobStreamNotFound):
+				log.Warnf("sid=%v - purging session with a missing stream object, reason=%v", sess.ID, err)
+			default:
+				return fmt.Errorf("failed fetching session: %v", err)
+			}
+			seal, err = sealPurge(sess, policy, prevBlobStream, nil)
+			if err != nil {
+				return err
+			}
+		}
+		if err := models.PurgeSessionStream(sess, policy, seal); err != nil {
+			return err
+		}
+		return purgeIndex(sess, (*indexer.Indexer).PurgeOutput)
+	case ModeOutput:
+		s, err := models.GetSessionByID(sess.OrgID, sess.ID)
+		if err != nil {
+			return fmt.Errorf("failed fetching session: %v", err)
+		}
+		blobStream, err := removeOutputEvents(s.BlobStream)
+		if err != nil {
+			return err
+		}
+		seal, err := sealPurge(sess, policy, s.BlobStream, blobStream)
+		if err != nil {
+			return err
+		}
+		if err := models.PurgeSessionOutput(sess, policy, blobStream, seal); err != nil {
+			return err
+		}
+		return purgeIndex(sess, (*indexer.Indexer).PurgeOutput)
+	}
+	return fmt.Errorf("unknown retention mode %q", policy.Mode)
+}
+
+// sealPurge seals the event stream left by the policy with a signed record of the purge,
+// the verification of sessions doesn't rely on the purge columns that could be changed in the database
+func sealPurge(sess models.RetentionSession, policy models.RetentionPolicy, prevBlobStream, blobStream json.RawMessage) (*models.SessionIntegrity, error) {
+	seal, err := integrity.SealPurge(sess.OrgID, sess.ID, sess.Integrity, policy.Mode, prevBlobStream, blobStream,
+		appconfig.Get().SessionSigningKey(), time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed sealing purged event stream: %v", err)
+	}
+	return seal, nil
+}
+
+// purgeIndex removes the content of a session from the search index of the organization
+func purgeIndex(sess models.RetentionSession, purgeFn func(*indexer.Indexer, string) error) error {
+	index, err := indexer.NewIndexer(sess.OrgID)
+	if err != nil {
+		return fmt.Errorf("failed opening index: %v", err)
+	}
+	if err := purgeFn(index, sess.ID); err != nil {
+		return fmt.Errorf("failed purging session from index: %v", err)
+	}
+	return nil
+}
+
+// removeOutputEvents removes the output (o) and error (e) events of an event
+// stream in the format [[<event-time>, <event-type>, <base64-content>], ...]
+func removeOutputEvents(blobStream json.RawMessage) (json.RawMessage, error) {
+	if len(blobStream) == 0 {
+		return json.RawMessage(`[]`), nil
+	}
+	var eventStream []json.RawMessage
+	if err := json.Unmarshal(blobStream, &eventStream); err != nil {
+		return nil, fmt.Errorf("failed decoding event stream: %v", err)
+	}
+	events := []json.RawMessage{}
+	for _, raw := range eventStream {
+		var event []any
+		if err := json.Unmarshal(raw, &event); err != nil {
+			return nil, fmt.Errorf("failed decoding event: %v", err)
+		}
+		if len(event) >= 2 {
+			if eventType, _ := event[1].(string); eventType == "o" || eventType == "e" {
+				continue
+			}
+		}
+		events = append(events, raw)
+	}
+	return json.Marshal(events)
+}
@@ -0,0 +1,122 @@
+package retention
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hoophq/hoop/gateway/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePolicy(t *testing.T) {
+	for _, tt := range []struct {
+		msg     string
+		mode    string
+		days    int
+		wantErr string
+	}{
+		{msg: "it must accept the event stream mode", mode: ModeEventStream, days: 30},
+		{msg: "it must accept the output mode", mode: ModeOutput, days: 30},
+		{msg: "it must accept the all mode", mode: ModeAll, days: MaxRetentionDays},
+		{
+			msg:     "it must return error with an unknown mode",
+			mode:    "input",
+			days:    30,
+			wantErr: `invalid mode "input", accepted values are: event_stream, output, all`,
+		},
+		{
+			msg:     "it must return error when the retention days is zero",
+			mode:    ModeAll,
+			wantErr: fmt.Sprintf("retention_days must be between 1 and %v", MaxRetentionDays),
+		},
+		{
+			msg:     "it must return error when the retention days is above the maximum",
+			mode:    ModeAll,
+			days:    MaxRetentionDays + 1,
+			wantErr: fmt.Sprintf("retention_days must be between 1 and %v", MaxRetentionDays),
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			err := ValidatePolicy(tt.mode, tt.days)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func newPolicy(id, orgID, connection, mode string) models.RetentionPolicy {
+	return models.RetentionPolicy{
+		ID:             id,
+		OrgID:          orgID,
+		ConnectionName: sql.NullString{String: connection, Valid: connection != ""},
+		Mode:           mode,
+		RetentionDays:  30,
+	}
+}
+
+func TestEffectiveRules(t *testing.T) {
+	rules := effectiveRules([]models.RetentionPolicy{
+		newPolicy("org-output", "org1", "", ModeOutput),
+		newPolicy("org-all", "org1", "", ModeAll),
+		newPolicy("pg-output", "org1", "pgdemo", ModeOutput),
+		newPolicy("mysql-output", "org1", "mysqldemo", ModeOutput),
+		newPolicy("org2-output", "org2", "", ModeOutput),
+		newPolicy("org2-stream", "org2", "pgdemo", ModeEventStream),
+	})
+	var got []string
+	for _, r := range rules {
+		got = append(got, fmt.Sprintf("%s:%v", r.policy.ID, r.exceptConnections))
+	}
+	assert.Equal(t, []string{
+		"org-all:[]",
+		"org2-stream:[]",
+		"org-output:[pgdemo mysqldemo]",
+		"pg-output:[]",
+		"mysql-output:[]",
+		"org2-output:[]",
+	}, got)
+}
+
+func TestRemoveOutputEvents(t *testing.T) {
+	for _, tt := range []struct {
+		msg        string
+		blobStream string
+		want       string
+		wantErr    bool
+	}{
+		{
+			msg:        "it must remove the output and error events",
+			blobStream: `[[0.1,"i","U0VMRUNUIDE="],[0.2,"o","MQ=="],[0.3,"e","ZXJy"],[0.4,"r","MjQsODAsMCww"]]`,
+			want:       `[[0.1,"i","U0VMRUNUIDE="],[0.4,"r","MjQsODAsMCww"]]`,
+		},
+		{
+			msg:        "it must return an empty stream when there are only output events",
+			blobStream: `[[0.2,"o","MQ=="]]`,
+			want:       `[]`,
+		},
+		{
+			msg:  "it must return an empty stream when the stream is empty",
+			want: `[]`,
+		},
+		{
+			msg:        "it must return error when the stream is not valid",
+			blobStream: `{"o": "MQ=="}`,
+			wantErr:    true,
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			got, err := removeOutputEvents(json.RawMessage(tt.blobStream))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
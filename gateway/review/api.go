@@ -3,6 +3,7 @@ package review
 import (
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/hoophq/hoop/common/log"
@@ -25,6 +26,10 @@ type (
 		Revoke(ctx pgrest.OrgContext, id string) (*types.Review, error)
 		RevokeBySid(ctx pgrest.OrgContext, sid string) (*types.Review, error)
 		Persist(ctx pgrest.OrgContext, review *types.Review) error
+		Create(ctx pgrest.OrgContext, review *types.Review) error
+		RequestExtension(ctx *storagev2.Context, connectionID string, duration time.Duration) (*types.Review, error)
+		ReviewExtension(ctx *storagev2.Context, id string, status types.ReviewStatus) (*types.Review, error)
+		CreateScheduled(ctx *storagev2.Context, req ScheduledAccess) (*types.Review, error)
 	}
 )
 
@@ -71,7 +76,7 @@ func (h *Handler) Put(c *gin.Context) {
 	switch err {
 	case ErrNotFound:
 		c.JSON(http.StatusNotFound, gin.H{"message": "not found"})
-	case ErrNotEligible, ErrWrongState:
+	case ErrNotEligible, ErrWrongState, ErrAlreadyReviewed:
 		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 	case nil:
 		c.JSON(http.StatusOK, sanitizeReview(review))
@@ -0,0 +1,126 @@
+package review
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hoophq/hoop/common/log"
+	"github.com/hoophq/hoop/gateway/models"
+	"github.com/hoophq/hoop/gateway/pgrest"
+	pgreview "github.com/hoophq/hoop/gateway/pgrest/review"
+	"github.com/hoophq/hoop/gateway/storagev2/types"
+)
+
+const (
+	defaultBreakGlassDuration  = time.Hour
+	defaultBreakGlassMaxPerDay = 1
+)
+
+var (
+	ErrBreakGlassDisabled    = errors.New("break-glass access is not enabled for this connection")
+	ErrBreakGlassRateLimited = errors.New("reached the maximum of break-glass sessions in the last 24 hours for this connection")
+	ErrBreakGlassReason      = errors.New("the reason is required to open a break-glass session")
+)
+
+// BreakGlassAccess is a request of immediate access to a connection with review,
+// the review is performed by the reviewers after the session is opened.
+type BreakGlassAccess struct {
+	SessionID  string
+	Owner      types.ReviewOwner
+	Connection types.ReviewConnection
+	Reviewers  []string
+	Policy     *types.ReviewPolicy
+	Reason     string
+}
+
+// validateBreakGlass validates the request against the policy of the connection, total
+// is the number of break-glass sessions of the user in the connection in the last 24 hours.
+func validateBreakGlass(req BreakGlassAccess, total int) error {
+	if req.Policy == nil || !req.Policy.BreakGlass {
+		return ErrBreakGlassDisabled
+	}
+	if req.Reason == "" {
+		return ErrBreakGlassReason
+	}
+	maxPerDay := req.Policy.BreakGlassMaxPerDay
+	if maxPerDay <= 0 {
+		maxPerDay = defaultBreakGlassMaxPerDay
+	}
+	if total >= maxPerDay {
+		return ErrBreakGlassRateLimited
+	}
+	return nil
+}
+
+func breakGlassDuration(policy *types.ReviewPolicy) time.Duration {
+	if policy == nil || policy.BreakGlassDurationSec <= 0 {
+		return defaultBreakGlassDuration
+	}
+	return time.Duration(policy.BreakGlassDurationSec) * time.Second
+}
+
+// breakGlassLock serializes the creation of the break-glass reviews of a user in a connection,
+// it allows validating the maximum per day and persisting the review atomically
+var breakGlassLock = models.WithAdvisoryLock
+
+// CreateBreakGlass grants immediate and time-boxed access to a session of a connection with review.
+// It creates a pending review that the reviewer groups must acknowledge, a rejection terminates the session.
+func (s *Service) CreateBreakGlass(ctx pgrest.OrgContext, req BreakGlassAccess) (*types.Review, error) {
+	var rev *types.Review
+	lockKey := fmt.Sprintf("break-glass:%s:%s:%s", ctx.GetOrgID(), req.Owner.Id, req.Connection.Id)
+	err := breakGlassLock(lockKey, func() error {
+		now := time.Now().UTC()
+		total, err := pgreview.New().CountBreakGlass(ctx, req.Owner.Id, req.Connection.Id, now.Add(-time.Hour*24))
+		if err != nil {
+			return fmt.Errorf("failed counting break-glass reviews: %v", err)
+		}
+		if err := validateBreakGlass(req, total); err != nil {
+			return err
+		}
+		rev = newBreakGlassReview(ctx, req, now)
+		if err := s.Persist(ctx, rev); err != nil {
+			return fmt.Errorf("saving review error: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.With("sid", rev.Session, "id", rev.Id, "user", req.Owner.Email, "org", rev.OrgId, "connection", req.Connection.Name).
+		Warnf("break-glass access granted, duration=%v, reason=%v", rev.AccessDuration, req.Reason)
+	s.TransportService.ReviewBreakGlass(rev)
+	return rev, nil
+}
+
+func newBreakGlassReview(ctx pgrest.OrgContext, req BreakGlassAccess, now time.Time) *types.Review {
+	duration := breakGlassDuration(req.Policy)
+	revokeAt := now.Add(duration)
+	rev := &types.Review{
+		Id:               uuid.NewString(),
+		Type:             ReviewTypeBreakGlass,
+		OrgId:            ctx.GetOrgID(),
+		CreatedAt:        now,
+		Session:          req.SessionID,
+		ConnectionId:     req.Connection.Id,
+		Connection:       req.Connection,
+		CreatedBy:        req.Owner.Id,
+		ReviewOwner:      req.Owner,
+		AccessDuration:   duration,
+		AccessStartAt:    &now,
+		AccessReason:     req.Reason,
+		RevokeAt:         &revokeAt,
+		Status:           types.ReviewStatusPending,
+		ReviewGroupsIds:  req.Reviewers,
+		ReviewGroupsData: []types.ReviewGroup{},
+		Policy:           req.Policy,
+	}
+	for _, group := range req.Reviewers {
+		rev.ReviewGroupsData = append(rev.ReviewGroupsData, types.ReviewGroup{
+			Group:  group,
+			Status: types.ReviewStatusPending,
+		})
+	}
+	return rev
+}
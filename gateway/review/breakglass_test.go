@@ -0,0 +1,195 @@
+package review
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hoophq/hoop/gateway/pgrest"
+	"github.com/hoophq/hoop/gateway/storagev2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBreakGlass(t *testing.T) {
+	for _, tt := range []struct {
+		msg     string
+		policy  *types.ReviewPolicy
+		reason  string
+		total   int
+		wantErr error
+	}{
+		{
+			msg:    "it must accept the first break-glass session of the day",
+			policy: &types.ReviewPolicy{BreakGlass: true},
+			reason: "production is down",
+		},
+		{
+			msg:     "it must return error when the connection doesn't have a policy",
+			policy:  nil,
+			reason:  "production is down",
+			wantErr: ErrBreakGlassDisabled,
+		},
+		{
+			msg:     "it must return error when break-glass is disabled",
+			policy:  &types.ReviewPolicy{MinApprovers: 2},
+			reason:  "production is down",
+			wantErr: ErrBreakGlassDisabled,
+		},
+		{
+			msg:     "it must return error when the reason is empty",
+			policy:  &types.ReviewPolicy{BreakGlass: true},
+			wantErr: ErrBreakGlassReason,
+		},
+		{
+			msg:     "it must return error when the user reached the default maximum per day",
+			policy:  &types.ReviewPolicy{BreakGlass: true},
+			reason:  "production is down",
+			total:   1,
+			wantErr: ErrBreakGlassRateLimited,
+		},
+		{
+			msg:    "it must accept sessions below the maximum per day of the policy",
+			policy: &types.ReviewPolicy{BreakGlass: true, BreakGlassMaxPerDay: 3},
+			reason: "production is down",
+			total:  2,
+		},
+		{
+			msg:     "it must return error when the user reached the maximum per day of the policy",
+			policy:  &types.ReviewPolicy{BreakGlass: true, BreakGlassMaxPerDay: 3},
+			reason:  "production is down",
+			total:   3,
+			wantErr: ErrBreakGlassRateLimited,
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			err := validateBreakGlass(BreakGlassAccess{Policy: tt.policy, Reason: tt.reason}, tt.total)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestBreakGlassDuration(t *testing.T) {
+	assert.Equal(t, defaultBreakGlassDuration, breakGlassDuration(nil))
+	assert.Equal(t, defaultBreakGlassDuration, breakGlassDuration(&types.ReviewPolicy{BreakGlass: true}))
+	assert.Equal(t, time.Minute*30, breakGlassDuration(&types.ReviewPolicy{BreakGlass: true, BreakGlassDurationSec: 1800}))
+}
+
+type clientFunc func(req *http.Request) (*http.Response, error)
+
+func (f clientFunc) Do(req *http.Request) (*http.Response, error) { return f(req) }
+
+type fakeTransport struct{}
+
+func (fakeTransport) ReviewStatusChange(*types.Review)       {}
+func (fakeTransport) ReviewEscalated(*types.Review, string)  {}
+func (fakeTransport) ReviewExtensionRequested(*types.Review) {}
+func (fakeTransport) ReviewExtensionChange(*types.Review)    {}
+func (fakeTransport) ReviewScheduled(*types.Review)          {}
+func (fakeTransport) ReviewBreakGlass(*types.Review)         {}
+func (fakeTransport) ReviewCreated(*types.Review)            {}
+
+// newFakeReviewsServer stores the reviews created in memory, the count of reviews is
+// delayed to let concurrent requests interleave between counting and persisting them
+func newFakeReviewsServer() clientFunc {
+	var mu sync.Mutex
+	var reviews []map[string]any
+	response := func(body string) *http.Response {
+		return &http.Response{
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+		}
+	}
+	return clientFunc(func(req *http.Request) (*http.Response, error) {
+		switch {
+		case req.Method == "GET" && req.URL.Path == "/reviews":
+			mu.Lock()
+			items := []map[string]any{}
+			for _, rev := range reviews {
+				items = append(items, map[string]any{"id": rev["id"]})
+			}
+			mu.Unlock()
+			time.Sleep(time.Millisecond * 20)
+			data, _ := json.Marshal(items)
+			return response(string(data)), nil
+		case req.Method == "POST" && req.URL.Path == "/reviews":
+			var rev map[string]any
+			if err := json.NewDecoder(req.Body).Decode(&rev); err != nil {
+				return nil, err
+			}
+			mu.Lock()
+			reviews = append(reviews, rev)
+			mu.Unlock()
+			return response(`[]`), nil
+		case req.Method == "POST" && req.URL.Path == "/review_groups":
+			return response(`[]`), nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusInternalServerError,
+			Body:       io.NopCloser(strings.NewReader(`{"msg": "test not implemented"}`)),
+		}, nil
+	})
+}
+
+func TestCreateBreakGlassConcurrency(t *testing.T) {
+	u, _ := url.Parse("http://localhost:3000")
+	pgrest.WithBaseURL(u)
+	pgrest.WithHttpClient(newFakeReviewsServer())
+
+	// the advisory lock of the database is replaced by an in memory lock of each key
+	var locksMu sync.Mutex
+	locks := map[string]*sync.Mutex{}
+	defer func(lockFn func(string, func() error) error) { breakGlassLock = lockFn }(breakGlassLock)
+	breakGlassLock = func(key string, fn func() error) error {
+		locksMu.Lock()
+		if _, ok := locks[key]; !ok {
+			locks[key] = &sync.Mutex{}
+		}
+		lock := locks[key]
+		locksMu.Unlock()
+		lock.Lock()
+		defer lock.Unlock()
+		return fn()
+	}
+
+	svc := &Service{TransportService: fakeTransport{}}
+	req := BreakGlassAccess{
+		Owner:      types.ReviewOwner{Id: "user-id", Email: "john@doe.com"},
+		Connection: types.ReviewConnection{Id: "conn-id", Name: "pgdemo"},
+		Reviewers:  []string{"sre"},
+		Policy:     &types.ReviewPolicy{BreakGlass: true, BreakGlassMaxPerDay: 2},
+		Reason:     "production is down",
+	}
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.CreateBreakGlass(pgrest.NewOrgContext("org-id"), req)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	var granted, rateLimited int
+	for err := range errs {
+		switch err {
+		case nil:
+			granted++
+		case ErrBreakGlassRateLimited:
+			rateLimited++
+		default:
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	assert.Equal(t, 2, granted)
+	assert.Equal(t, 8, rateLimited)
+}
@@ -0,0 +1,130 @@
+package review
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hoophq/hoop/common/log"
+	"github.com/hoophq/hoop/gateway/models"
+	"github.com/hoophq/hoop/gateway/pgrest"
+	pgreview "github.com/hoophq/hoop/gateway/pgrest/review"
+	"github.com/hoophq/hoop/gateway/storagev2/types"
+)
+
+var expirationBackoffDuration = time.Second * 30
+
+// InitExpirationProcess expires and escalates the pending reviews
+// based on the policy of each review.
+func (s *Service) InitExpirationProcess() {
+	log.Infof("initializing review expiration process")
+	go func() {
+		for {
+			// the reviews are processed by only one gateway replica
+			_, err := models.TryAdvisoryLock("review-expiration", func() error {
+				return s.processPendingReviews(time.Now().UTC())
+			})
+			if err != nil {
+				log.Warnf("failed processing the expiration of pending reviews, reason=%v", err)
+			}
+			time.Sleep(expirationBackoffDuration)
+		}
+	}()
+}
+
+func (s *Service) processPendingReviews(now time.Time) error {
+	reviews, err := pgreview.New().FetchAllPendingWithPolicy()
+	if err != nil {
+		return fmt.Errorf("failed fetching pending reviews: %v", err)
+	}
+	for i := range reviews {
+		rev := &reviews[i]
+		if rev.CreatedAt.IsZero() {
+			continue
+		}
+		policy := pgreview.PolicyOrDefault(rev.Policy)
+		var err error
+		switch {
+		case isExpired(rev, policy, now):
+			err = s.expire(rev)
+		case shouldEscalate(rev, policy, now):
+			err = s.escalate(rev, policy.EscalationGroup)
+		}
+		if err != nil {
+			log.With("sid", rev.Session, "id", rev.Id, "org", rev.OrgId).Warn(err)
+		}
+	}
+	return nil
+}
+
+// isExpired counts the expiration time from the creation of the review, scheduled
+// reviews are counted from the start of the access window, reviewers could approve
+// them at any time before the window opens
+func isExpired(rev *types.Review, policy types.ReviewPolicy, now time.Time) bool {
+	if policy.ExpirationSec <= 0 {
+		return false
+	}
+	startAt := rev.CreatedAt
+	if rev.Type == ReviewTypeScheduled && rev.AccessStartAt != nil {
+		startAt = *rev.AccessStartAt
+	}
+	return now.After(startAt.Add(time.Duration(policy.ExpirationSec) * time.Second))
+}
+
+func shouldEscalate(rev *types.Review, policy types.ReviewPolicy, now time.Time) bool {
+	if policy.EscalationSec <= 0 || policy.EscalationGroup == "" || isEscalated(rev) {
+		return false
+	}
+	return now.After(rev.CreatedAt.Add(time.Duration(policy.EscalationSec) * time.Second))
+}
+
+// expire moves the review to the expired state and releases the client waiting for it.
+// The transition is skipped when the review was reviewed after it was fetched.
+func (s *Service) expire(rev *types.Review) error {
+	err := pgreview.New().PatchPendingStatus(pgrest.NewOrgContext(rev.OrgId), rev.Id, types.ReviewStatusExpired)
+	switch err {
+	case pgrest.ErrNotFound:
+		log.With("sid", rev.Session, "id", rev.Id, "org", rev.OrgId).Infof("review is not pending anymore, skipping expiration")
+		return nil
+	case nil:
+	default:
+		return fmt.Errorf("failed saving expired review: %v", err)
+	}
+	rev.Status = types.ReviewStatusExpired
+	log.With("sid", rev.Session, "id", rev.Id, "org", rev.OrgId).Infof("review expired")
+	s.TransportService.ReviewStatusChange(rev)
+	return nil
+}
+
+// escalate adds the escalation group as a reviewer of the review and notifies it.
+// Only the escalation group is updated, the reviews of the other groups are kept.
+func (s *Service) escalate(rev *types.Review, group string) error {
+	ctx := pgrest.NewOrgContext(rev.OrgId)
+	current, err := pgreview.New().FetchOneByID(ctx, rev.Id)
+	if err != nil {
+		return fmt.Errorf("failed fetching review: %v", err)
+	}
+	if current == nil || current.Status != types.ReviewStatusPending {
+		return nil
+	}
+	if err := pgreview.New().PatchEscalationGroup(ctx, rev.Id, group); err != nil {
+		return fmt.Errorf("failed saving escalated review: %v", err)
+	}
+	found := false
+	for i, g := range current.ReviewGroupsData {
+		if g.Group == group {
+			current.ReviewGroupsData[i].Escalation = true
+			found = true
+		}
+	}
+	if !found {
+		current.ReviewGroupsIds = append(current.ReviewGroupsIds, group)
+		current.ReviewGroupsData = append(current.ReviewGroupsData, types.ReviewGroup{
+			Group:      group,
+			Status:     types.ReviewStatusPending,
+			Escalation: true,
+		})
+	}
+	log.With("sid", rev.Session, "id", rev.Id, "org", rev.OrgId).Infof("review escalated to group %v", group)
+	s.TransportService.ReviewEscalated(current, group)
+	return nil
+}
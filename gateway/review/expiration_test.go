@@ -0,0 +1,60 @@
+package review
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hoophq/hoop/gateway/storagev2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpirationAndEscalation(t *testing.T) {
+	createdAt := time.Date(2024, time.July, 25, 10, 0, 0, 0, time.UTC)
+	policy := types.ReviewPolicy{ExpirationSec: 3600, EscalationSec: 900, EscalationGroup: "managers"}
+	for _, tt := range []struct {
+		msg          string
+		policy       types.ReviewPolicy
+		groups       []types.ReviewGroup
+		elapsed      time.Duration
+		wantExpired  bool
+		wantEscalate bool
+	}{
+		{msg: "it must not expire or escalate a fresh review", policy: policy, elapsed: time.Minute},
+		{msg: "it must escalate after the escalation time", policy: policy, elapsed: time.Minute * 20, wantEscalate: true},
+		{
+			msg:     "it must not escalate a review twice",
+			policy:  policy,
+			groups:  []types.ReviewGroup{{Group: "managers", Status: types.ReviewStatusPending, Escalation: true}},
+			elapsed: time.Minute * 20,
+		},
+		{msg: "it must expire after the expiration time", policy: policy, elapsed: time.Hour * 2, wantExpired: true, wantEscalate: true},
+		{msg: "it must never expire reviews without an expiration time", policy: types.ReviewPolicy{}, elapsed: time.Hour * 48},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			rev := &types.Review{CreatedAt: createdAt, ReviewGroupsData: tt.groups}
+			now := createdAt.Add(tt.elapsed)
+			assert.Equal(t, tt.wantExpired, isExpired(rev, tt.policy, now))
+			assert.Equal(t, tt.wantEscalate, shouldEscalate(rev, tt.policy, now))
+		})
+	}
+}
+
+func TestExpirationScheduledReview(t *testing.T) {
+	createdAt := time.Date(2024, time.July, 25, 10, 0, 0, 0, time.UTC)
+	startAt := createdAt.Add(time.Hour * 24)
+	policy := types.ReviewPolicy{ExpirationSec: 3600}
+	for _, tt := range []struct {
+		msg         string
+		elapsed     time.Duration
+		wantExpired bool
+	}{
+		{msg: "it must not expire before the access window opens", elapsed: time.Hour * 12},
+		{msg: "it must not expire before the expiration time after the window opens", elapsed: time.Hour * 24},
+		{msg: "it must expire after the expiration time counted from the start of the window", elapsed: time.Hour * 26, wantExpired: true},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			rev := &types.Review{Type: ReviewTypeScheduled, CreatedAt: createdAt, AccessStartAt: &startAt}
+			assert.Equal(t, tt.wantExpired, isExpired(rev, policy, createdAt.Add(tt.elapsed)))
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package review
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hoophq/hoop/common/log"
+	pb "github.com/hoophq/hoop/common/proto"
+	pgreview "github.com/hoophq/hoop/gateway/pgrest/review"
+	"github.com/hoophq/hoop/gateway/storagev2"
+	"github.com/hoophq/hoop/gateway/storagev2/types"
+)
+
+// MaxExtensionDuration is the maximum additional time of an extension request,
+// it's the same limit of the access duration of a jit review.
+const MaxExtensionDuration = MaxAccessDuration
+
+var (
+	ErrInvalidExtension = fmt.Errorf("the extension must be between 1 minute and %v hours", MaxExtensionDuration.Hours())
+	ErrExtensionPending = errors.New("there is a pending extension for this review")
+)
+
+// RequestExtension requests additional access time for the active jit review
+// of the user in a connection. The extension is reviewed by the same groups of the review.
+func (s *Service) RequestExtension(ctx *storagev2.Context, connectionID string, duration time.Duration) (*types.Review, error) {
+	if duration < time.Minute || duration > MaxExtensionDuration {
+		return nil, ErrInvalidExtension
+	}
+	rev, err := pgreview.New().FetchJit(ctx, ctx.UserID, connectionID)
+	if err != nil {
+		return nil, fmt.Errorf("fetch review error: %v", err)
+	}
+	if rev == nil || rev.RevokeAt == nil || rev.RevokeAt.Before(time.Now().UTC()) {
+		return nil, ErrNotFound
+	}
+	if rev.Extension != nil && rev.Extension.Status == types.ReviewStatusPending {
+		return nil, ErrExtensionPending
+	}
+
+	rev.Extension = &types.ReviewExtension{
+		AccessDurationSec: int(duration.Seconds()),
+		Status:            types.ReviewStatusPending,
+		CreatedAt:         time.Now().UTC().Format(time.RFC3339),
+	}
+	for _, g := range rev.ReviewGroupsData {
+		if g.Escalation {
+			continue
+		}
+		rev.Extension.ReviewGroupsData = append(rev.Extension.ReviewGroupsData, types.ReviewGroup{
+			Group:  g.Group,
+			Status: types.ReviewStatusPending,
+		})
+	}
+	if err := s.Persist(ctx, rev); err != nil {
+		return nil, fmt.Errorf("saving review error: %v", err)
+	}
+	log.With("sid", rev.Session, "id", rev.Id, "org", rev.OrgId).
+		Infof("jit extension requested, duration=%v", duration)
+	s.TransportService.ReviewExtensionRequested(rev)
+	return rev, nil
+}
+
+// ReviewExtension approves or rejects the pending extension of a jit review.
+// When approved, the revoke time of the review and of its live sessions is pushed out.
+func (s *Service) ReviewExtension(ctx *storagev2.Context, reviewID string, status types.ReviewStatus) (*types.Review, error) {
+	rev, err := s.FindOne(ctx, reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("fetch review error: %v", err)
+	}
+	if rev == nil || rev.Extension == nil {
+		return nil, ErrNotFound
+	}
+	ext := rev.Extension
+	if ext.Status != types.ReviewStatusPending || rev.Status != types.ReviewStatusApproved {
+		return rev, ErrWrongState
+	}
+	if rev.ReviewOwner.Id == ctx.UserID && !ctx.IsAdmin() {
+		return nil, ErrSelfApproval
+	}
+
+	isEligibleReviewer := false
+	for _, g := range ext.ReviewGroupsData {
+		if pb.IsInList(g.Group, ctx.UserGroups) {
+			isEligibleReviewer = true
+			break
+		}
+	}
+	if !isEligibleReviewer {
+		return nil, ErrNotEligible
+	}
+
+	policy := pgreview.PolicyOrDefault(rev.Policy)
+	reviewer := types.ReviewOwner{
+		Id:      ctx.UserID,
+		Name:    ctx.UserName,
+		Email:   ctx.UserEmail,
+		SlackID: ctx.SlackID,
+	}
+	t := time.Now().UTC().Format(time.RFC3339)
+	if status == types.ReviewStatusRejected {
+		ext.Status = status
+		for i, g := range ext.ReviewGroupsData {
+			if pb.IsInList(g.Group, ctx.UserGroups) {
+				ext.ReviewGroupsData[i].Status = status
+				ext.ReviewGroupsData[i].ReviewedBy = &reviewer
+				ext.ReviewGroupsData[i].ReviewDate = &t
+			}
+		}
+	} else {
+		approved := false
+		for i, g := range ext.ReviewGroupsData {
+			if pb.IsInList(g.Group, ctx.UserGroups) &&
+				addGroupApproval(&ext.ReviewGroupsData[i], policy, reviewer, t) {
+				approved = true
+			}
+		}
+		if !approved {
+			return nil, ErrAlreadyReviewed
+		}
+		if isApproved(ext.ReviewGroupsData, policy) {
+			ext.Status = types.ReviewStatusApproved
+			extendAccess(rev, time.Now().UTC())
+		}
+	}
+
+	if err := s.Persist(ctx, rev); err != nil {
+		return nil, fmt.Errorf("saving review error: %v", err)
+	}
+	if ext.Status != types.ReviewStatusPending {
+		s.TransportService.ReviewExtensionChange(rev)
+	}
+	return rev, nil
+}
+
+// extendAccess pushes out the revoke time of the review with the extension duration,
+// a review that has already expired is extended from now on.
+func extendAccess(rev *types.Review, now time.Time) {
+	duration := time.Duration(rev.Extension.AccessDurationSec) * time.Second
+	revokeAt := now
+	if rev.RevokeAt != nil && rev.RevokeAt.After(now) {
+		revokeAt = *rev.RevokeAt
+	}
+	revokeAt = revokeAt.Add(duration)
+	rev.RevokeAt = &revokeAt
+	rev.AccessDuration += duration
+}
@@ -0,0 +1,54 @@
+package review
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hoophq/hoop/gateway/storagev2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtendAccess(t *testing.T) {
+	now := time.Date(2024, 7, 25, 19, 0, 0, 0, time.UTC)
+	timePtr := func(t time.Time) *time.Time { return &t }
+	for _, tt := range []struct {
+		msg              string
+		revokeAt         *time.Time
+		extensionSec     int
+		wantRevokeAt     time.Time
+		wantAccessDurSec int
+	}{
+		{
+			msg:              "it must push out the revoke time of an active review",
+			revokeAt:         timePtr(now.Add(time.Minute * 10)),
+			extensionSec:     1800,
+			wantRevokeAt:     now.Add(time.Minute * 40),
+			wantAccessDurSec: 3600 + 1800,
+		},
+		{
+			msg:              "it must extend from now on when the review has expired",
+			revokeAt:         timePtr(now.Add(-time.Minute * 10)),
+			extensionSec:     600,
+			wantRevokeAt:     now.Add(time.Minute * 10),
+			wantAccessDurSec: 3600 + 600,
+		},
+		{
+			msg:              "it must extend from now on when the review doesn't have a revoke time",
+			revokeAt:         nil,
+			extensionSec:     600,
+			wantRevokeAt:     now.Add(time.Minute * 10),
+			wantAccessDurSec: 3600 + 600,
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			rev := &types.Review{
+				AccessDuration: time.Hour,
+				RevokeAt:       tt.revokeAt,
+				Extension:      &types.ReviewExtension{AccessDurationSec: tt.extensionSec},
+			}
+			extendAccess(rev, now)
+			assert.Equal(t, tt.wantRevokeAt, *rev.RevokeAt)
+			assert.Equal(t, time.Duration(tt.wantAccessDurSec)*time.Second, rev.AccessDuration)
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package review
+
+import (
+	"fmt"
+	"time"
+
+	pb "github.com/hoophq/hoop/common/proto"
+	"github.com/hoophq/hoop/gateway/storagev2/types"
+)
+
+// ValidatePolicy validates the review policy of a connection, the required group
+// must be one of the reviewers of the connection.
+func ValidatePolicy(p *types.ReviewPolicy, reviewers []string) error {
+	if p == nil {
+		return nil
+	}
+	switch p.Mode {
+	case "", types.ReviewPolicyModeAll, types.ReviewPolicyModeAny:
+	default:
+		return fmt.Errorf("invalid review policy mode %q, accepted values are: %v, %v",
+			p.Mode, types.ReviewPolicyModeAll, types.ReviewPolicyModeAny)
+	}
+	if p.MinApprovalsPerGroup < 0 || p.MinApprovers < 0 {
+		return fmt.Errorf("the minimum of approvals of a review policy must be a positive number")
+	}
+	if p.RequiredGroup != "" && !pb.IsInList(p.RequiredGroup, reviewers) {
+		return fmt.Errorf("the required group %q of the review policy is not a reviewer of the connection", p.RequiredGroup)
+	}
+	if p.ExpirationSec < 0 || p.EscalationSec < 0 {
+		return fmt.Errorf("the expiration and escalation of a review policy must be a positive number of seconds")
+	}
+	if (p.EscalationSec > 0) != (p.EscalationGroup != "") {
+		return fmt.Errorf("the escalation of a review policy requires both the escalation group and the escalation time")
+	}
+	if p.ExpirationSec > 0 && p.EscalationSec >= p.ExpirationSec {
+		return fmt.Errorf("the escalation time of a review policy must be lower than the expiration time")
+	}
+	if p.EscalationGroup != "" && pb.IsInList(p.EscalationGroup, reviewers) {
+		return fmt.Errorf("the escalation group %q of the review policy must not be a reviewer of the connection", p.EscalationGroup)
+	}
+	if p.BreakGlassDurationSec < 0 || p.BreakGlassMaxPerDay < 0 {
+		return fmt.Errorf("the duration and the maximum of break-glass sessions of a review policy must be a positive number")
+	}
+	if time.Duration(p.BreakGlassDurationSec)*time.Second > MaxAccessDuration {
+		return fmt.Errorf("the duration of break-glass sessions of a review policy must not be greater than %v hours",
+			MaxAccessDuration.Hours())
+	}
+	return nil
+}
+
+// groupApprovals returns the approvals of a group, groups approved
+// before the review policies have only the last reviewer.
+func groupApprovals(g types.ReviewGroup) []types.ReviewApproval {
+	if len(g.Approvals) == 0 && g.Status == types.ReviewStatusApproved && g.ReviewedBy != nil {
+		var reviewDate string
+		if g.ReviewDate != nil {
+			reviewDate = *g.ReviewDate
+		}
+		return []types.ReviewApproval{{ReviewedBy: *g.ReviewedBy, ReviewDate: reviewDate}}
+	}
+	return g.Approvals
+}
+
+func hasApproved(approvals []types.ReviewApproval, userID string) bool {
+	for _, a := range approvals {
+		if a.ReviewedBy.Id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// addGroupApproval adds the approval of the reviewer to the group, the group is approved
+// when it has the minimum of approvals of the policy. It returns false if the
+// reviewer has already approved the group.
+func addGroupApproval(g *types.ReviewGroup, policy types.ReviewPolicy, reviewer types.ReviewOwner, reviewDate string) bool {
+	approvals := groupApprovals(*g)
+	if hasApproved(approvals, reviewer.Id) {
+		return false
+	}
+	g.Approvals = append(approvals, types.ReviewApproval{ReviewedBy: reviewer, ReviewDate: reviewDate})
+	g.ReviewedBy = &reviewer
+	g.ReviewDate = &reviewDate
+	if len(g.Approvals) >= policy.MinApprovalsPerGroup {
+		g.Status = types.ReviewStatusApproved
+	}
+	return true
+}
+
+// isApproved evaluates if the review groups fulfill the policy. A required group
+// which isn't a group of the review, e.g.: the groups of a guard rail rule, is ignored.
+// The approval of an escalation group approves the review on behalf of the pending groups.
+func isApproved(groups []types.ReviewGroup, policy types.ReviewPolicy) bool {
+	if len(groups) == 0 {
+		return false
+	}
+	for _, g := range groups {
+		if g.Escalation && g.Status == types.ReviewStatusApproved {
+			return true
+		}
+	}
+	reviewGroups := 0
+	approvedGroups := 0
+	approvers := map[string]struct{}{}
+	for _, g := range groups {
+		if g.Escalation {
+			continue
+		}
+		reviewGroups++
+		if g.Status == types.ReviewStatusApproved {
+			approvedGroups++
+		} else if g.Group == policy.RequiredGroup {
+			return false
+		}
+		for _, a := range groupApprovals(g) {
+			approvers[a.ReviewedBy.Id] = struct{}{}
+		}
+	}
+	if len(approvers) < policy.MinApprovers {
+		return false
+	}
+	if policy.Mode == types.ReviewPolicyModeAny {
+		return approvedGroups > 0
+	}
+	return reviewGroups > 0 && approvedGroups == reviewGroups
+}
+
+// requiresDistinctApprovers returns true if the policy requires the approval of more than one member,
+// the owner of a review must not count toward them
+func requiresDistinctApprovers(policy types.ReviewPolicy) bool {
+	return policy.MinApprovers > 1 || policy.MinApprovalsPerGroup > 1
+}
+
+// isEscalated returns true if the review has notified its escalation group
+func isEscalated(rev *types.Review) bool {
+	for _, g := range rev.ReviewGroupsData {
+		if g.Escalation {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,209 @@
+package review
+
+import (
+	"testing"
+
+	pgreview "github.com/hoophq/hoop/gateway/pgrest/review"
+	"github.com/hoophq/hoop/gateway/storagev2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newApproval(userID string) types.ReviewApproval {
+	return types.ReviewApproval{ReviewedBy: types.ReviewOwner{Id: userID}, ReviewDate: "2024-07-25T19:36:41Z"}
+}
+
+func newReviewGroup(name string, approvedBy ...string) types.ReviewGroup {
+	g := types.ReviewGroup{Group: name, Status: types.ReviewStatusPending}
+	for _, userID := range approvedBy {
+		g.Approvals = append(g.Approvals, newApproval(userID))
+	}
+	if len(approvedBy) > 0 {
+		g.Status = types.ReviewStatusApproved
+	}
+	return g
+}
+
+func TestValidatePolicy(t *testing.T) {
+	for _, tt := range []struct {
+		msg       string
+		policy    *types.ReviewPolicy
+		reviewers []string
+		wantErr   string
+	}{
+		{msg: "it must accept connections without a policy", policy: nil},
+		{msg: "it must accept a policy with default values", policy: &types.ReviewPolicy{}},
+		{
+			msg:       "it must accept a valid policy",
+			policy:    &types.ReviewPolicy{MinApprovalsPerGroup: 2, MinApprovers: 3, Mode: "any", RequiredGroup: "sre"},
+			reviewers: []string{"sre", "dba"},
+		},
+		{
+			msg:     "it must return error with an unknown mode",
+			policy:  &types.ReviewPolicy{Mode: "majority"},
+			wantErr: `invalid review policy mode "majority", accepted values are: all, any`,
+		},
+		{
+			msg:     "it must return error with negative minimum approvals",
+			policy:  &types.ReviewPolicy{MinApprovers: -1},
+			wantErr: "the minimum of approvals of a review policy must be a positive number",
+		},
+		{
+			msg:       "it must return error when the required group is not a reviewer",
+			policy:    &types.ReviewPolicy{RequiredGroup: "security"},
+			reviewers: []string{"sre"},
+			wantErr:   `the required group "security" of the review policy is not a reviewer of the connection`,
+		},
+		{
+			msg:       "it must accept a policy with expiration and escalation",
+			policy:    &types.ReviewPolicy{ExpirationSec: 3600, EscalationSec: 900, EscalationGroup: "managers"},
+			reviewers: []string{"sre"},
+		},
+		{
+			msg:     "it must return error with a negative expiration",
+			policy:  &types.ReviewPolicy{ExpirationSec: -1},
+			wantErr: "the expiration and escalation of a review policy must be a positive number of seconds",
+		},
+		{
+			msg:     "it must return error when the escalation group is missing",
+			policy:  &types.ReviewPolicy{EscalationSec: 900},
+			wantErr: "the escalation of a review policy requires both the escalation group and the escalation time",
+		},
+		{
+			msg:     "it must return error when the escalation happens after the expiration",
+			policy:  &types.ReviewPolicy{ExpirationSec: 900, EscalationSec: 900, EscalationGroup: "managers"},
+			wantErr: "the escalation time of a review policy must be lower than the expiration time",
+		},
+		{
+			msg:       "it must return error when the escalation group is a reviewer",
+			policy:    &types.ReviewPolicy{EscalationSec: 900, EscalationGroup: "sre"},
+			reviewers: []string{"sre"},
+			wantErr:   `the escalation group "sre" of the review policy must not be a reviewer of the connection`,
+		},
+		{
+			msg:     "it must return error with a negative maximum of break-glass sessions",
+			policy:  &types.ReviewPolicy{BreakGlass: true, BreakGlassMaxPerDay: -1},
+			wantErr: "the duration and the maximum of break-glass sessions of a review policy must be a positive number",
+		},
+		{
+			msg:     "it must return error when the break-glass duration is greater than the maximum",
+			policy:  &types.ReviewPolicy{BreakGlass: true, BreakGlassDurationSec: 3600 * 49},
+			wantErr: "the duration of break-glass sessions of a review policy must not be greater than 48 hours",
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			err := ValidatePolicy(tt.policy, tt.reviewers)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAddGroupApproval(t *testing.T) {
+	policy := pgreview.PolicyOrDefault(&types.ReviewPolicy{MinApprovalsPerGroup: 2})
+	g := newReviewGroup("sre")
+
+	assert.True(t, addGroupApproval(&g, policy, types.ReviewOwner{Id: "u1"}, "2024-07-25T19:36:41Z"))
+	assert.Equal(t, types.ReviewStatusPending, g.Status)
+	assert.False(t, addGroupApproval(&g, policy, types.ReviewOwner{Id: "u1"}, "2024-07-25T19:36:42Z"),
+		"it must not count the approval of the same member twice")
+	assert.Equal(t, types.ReviewStatusPending, g.Status)
+
+	assert.True(t, addGroupApproval(&g, policy, types.ReviewOwner{Id: "u2"}, "2024-07-25T19:36:43Z"))
+	assert.Equal(t, types.ReviewStatusApproved, g.Status)
+	assert.Len(t, g.Approvals, 2)
+	assert.Equal(t, "u2", g.ReviewedBy.Id)
+}
+
+func TestRequiresDistinctApprovers(t *testing.T) {
+	assert.False(t, requiresDistinctApprovers(pgreview.PolicyOrDefault(nil)))
+	assert.False(t, requiresDistinctApprovers(types.ReviewPolicy{MinApprovers: 1, MinApprovalsPerGroup: 1}))
+	assert.True(t, requiresDistinctApprovers(types.ReviewPolicy{MinApprovers: 2}),
+		"the owner must not be one of the distinct approvers")
+	assert.True(t, requiresDistinctApprovers(types.ReviewPolicy{MinApprovalsPerGroup: 2}),
+		"the owner must not be one of the approvals of a group")
+}
+
+func TestIsApproved(t *testing.T) {
+	legacyGroup := types.ReviewGroup{
+		Group:      "sre",
+		Status:     types.ReviewStatusApproved,
+		ReviewedBy: &types.ReviewOwner{Id: "u1"},
+	}
+	for _, tt := range []struct {
+		msg    string
+		policy *types.ReviewPolicy
+		groups []types.ReviewGroup
+		want   bool
+	}{
+		{
+			msg:    "default policy: it must approve when every group is approved",
+			groups: []types.ReviewGroup{newReviewGroup("sre", "u1"), newReviewGroup("dba", "u2")},
+			want:   true,
+		},
+		{
+			msg:    "default policy: it must not approve when a group is pending",
+			groups: []types.ReviewGroup{newReviewGroup("sre", "u1"), newReviewGroup("dba")},
+			want:   false,
+		},
+		{
+			msg:    "default policy: it must approve groups approved before the policies",
+			groups: []types.ReviewGroup{legacyGroup},
+			want:   true,
+		},
+		{
+			msg:    "it must not approve reviews without groups",
+			groups: nil,
+			want:   false,
+		},
+		{
+			msg:    "any mode: it must approve when one group is approved",
+			policy: &types.ReviewPolicy{Mode: types.ReviewPolicyModeAny},
+			groups: []types.ReviewGroup{newReviewGroup("sre", "u1"), newReviewGroup("dba")},
+			want:   true,
+		},
+		{
+			msg:    "any mode: it must not approve when the required group is pending",
+			policy: &types.ReviewPolicy{Mode: types.ReviewPolicyModeAny, RequiredGroup: "dba"},
+			groups: []types.ReviewGroup{newReviewGroup("sre", "u1"), newReviewGroup("dba")},
+			want:   false,
+		},
+		{
+			msg:    "any mode: it must ignore a required group which is not a group of the review",
+			policy: &types.ReviewPolicy{Mode: types.ReviewPolicyModeAny, RequiredGroup: "security"},
+			groups: []types.ReviewGroup{newReviewGroup("sre", "u1"), newReviewGroup("dba")},
+			want:   true,
+		},
+		{
+			msg:    "it must not approve when there are less distinct approvers than the minimum",
+			policy: &types.ReviewPolicy{MinApprovers: 2},
+			groups: []types.ReviewGroup{newReviewGroup("sre", "u1"), newReviewGroup("dba", "u1")},
+			want:   false,
+		},
+		{
+			msg:    "it must approve when there are the minimum of distinct approvers",
+			policy: &types.ReviewPolicy{MinApprovers: 2},
+			groups: []types.ReviewGroup{newReviewGroup("sre", "u1"), newReviewGroup("dba", "u1", "u2")},
+			want:   true,
+		},
+		{
+			msg:    "it must not require the approval of a pending escalation group",
+			groups: []types.ReviewGroup{newReviewGroup("sre", "u1"), {Group: "managers", Status: types.ReviewStatusPending, Escalation: true}},
+			want:   true,
+		},
+		{
+			msg:    "it must approve on behalf of the pending groups when the escalation group approves",
+			policy: &types.ReviewPolicy{MinApprovers: 2, RequiredGroup: "dba"},
+			groups: []types.ReviewGroup{newReviewGroup("sre"), newReviewGroup("dba"),
+				func() types.ReviewGroup { g := newReviewGroup("managers", "u3"); g.Escalation = true; return g }()},
+			want: true,
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			got := isApproved(tt.groups, pgreview.PolicyOrDefault(tt.policy))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package review
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hoophq/hoop/common/log"
+	"github.com/hoophq/hoop/gateway/storagev2"
+	"github.com/hoophq/hoop/gateway/storagev2/types"
+)
+
+var ErrInvalidSchedule = errors.New("invalid scheduled access")
+
+// ScheduledAccess is a request of access to a connection for a future time window
+type ScheduledAccess struct {
+	Connection   types.ReviewConnection
+	Reviewers    []string
+	Policy       *types.ReviewPolicy
+	StartAt      time.Time
+	Duration     time.Duration
+	Reason       string
+	JiraIssueKey string
+}
+
+func validateScheduledAccess(req ScheduledAccess, now time.Time) error {
+	if len(req.Reviewers) == 0 {
+		return fmt.Errorf("%w, the connection %q doesn't have reviewers", ErrInvalidSchedule, req.Connection.Name)
+	}
+	if !req.StartAt.After(now) {
+		return fmt.Errorf("%w, the start time must be in the future", ErrInvalidSchedule)
+	}
+	if req.Duration < time.Minute || req.Duration > MaxAccessDuration {
+		return fmt.Errorf("%w, the duration must be between 1 minute and %v hours",
+			ErrInvalidSchedule, MaxAccessDuration.Hours())
+	}
+	if req.Reason == "" {
+		return fmt.Errorf("%w, the reason is required", ErrInvalidSchedule)
+	}
+	return nil
+}
+
+// CreateScheduled creates a review granting access to a connection inside a future time window.
+// The review is approved in advance and the sessions are admitted only inside the window.
+func (s *Service) CreateScheduled(ctx *storagev2.Context, req ScheduledAccess) (*types.Review, error) {
+	if err := validateScheduledAccess(req, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	startAt := req.StartAt.UTC()
+	revokeAt := startAt.Add(req.Duration)
+	reviewID := uuid.NewString()
+	rev := &types.Review{
+		Id:        reviewID,
+		Type:      ReviewTypeScheduled,
+		OrgId:     ctx.OrgID,
+		CreatedAt: time.Now().UTC(),
+		// scheduled reviews aren't bound to a session,
+		// the id of the review keeps it unique by session
+		Session:      reviewID,
+		ConnectionId: req.Connection.Id,
+		Connection:   req.Connection,
+		CreatedBy:    ctx.UserID,
+		ReviewOwner: types.ReviewOwner{
+			Id:      ctx.UserID,
+			Name:    ctx.UserName,
+			Email:   ctx.UserEmail,
+			SlackID: ctx.SlackID,
+		},
+		AccessDuration:  req.Duration,
+		AccessStartAt:   &startAt,
+		AccessReason:    req.Reason,
+		JiraIssueKey:    req.JiraIssueKey,
+		RevokeAt:        &revokeAt,
+		Status:          types.ReviewStatusPending,
+		ReviewGroupsIds: req.Reviewers,
+		Policy:          req.Policy,
+	}
+	for _, group := range req.Reviewers {
+		rev.ReviewGroupsData = append(rev.ReviewGroupsData, types.ReviewGroup{
+			Group:  group,
+			Status: types.ReviewStatusPending,
+		})
+	}
+	if err := s.Persist(ctx, rev); err != nil {
+		return nil, fmt.Errorf("saving review error: %v", err)
+	}
+	log.With("id", rev.Id, "user", ctx.UserEmail, "org", ctx.OrgID, "connection", req.Connection.Name).
+		Infof("scheduled review created, start-at=%v, duration=%v", startAt.Format(time.RFC3339), req.Duration)
+	s.TransportService.ReviewScheduled(rev)
+	return rev, nil
+}
+
+// isTimeBased returns true if the review grants access to a connection for a period of time
+func isTimeBased(rev *types.Review) bool {
+	switch rev.Type {
+	case ReviewTypeJit, ReviewTypeScheduled, ReviewTypeBreakGlass:
+		return true
+	}
+	return false
+}
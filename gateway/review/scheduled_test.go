@@ -0,0 +1,68 @@
+package review
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hoophq/hoop/gateway/storagev2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateScheduledAccess(t *testing.T) {
+	now := time.Date(2024, 7, 25, 19, 0, 0, 0, time.UTC)
+	newRequest := func(fn func(r *ScheduledAccess)) ScheduledAccess {
+		r := ScheduledAccess{
+			Connection: types.ReviewConnection{Id: "1", Name: "pgdemo"},
+			Reviewers:  []string{"sre"},
+			StartAt:    now.Add(time.Hour),
+			Duration:   time.Hour * 2,
+			Reason:     "database maintenance",
+		}
+		if fn != nil {
+			fn(&r)
+		}
+		return r
+	}
+	for _, tt := range []struct {
+		msg     string
+		req     ScheduledAccess
+		wantErr string
+	}{
+		{msg: "it must accept a valid request", req: newRequest(nil)},
+		{
+			msg:     "it must return error when the connection doesn't have reviewers",
+			req:     newRequest(func(r *ScheduledAccess) { r.Reviewers = nil }),
+			wantErr: `invalid scheduled access, the connection "pgdemo" doesn't have reviewers`,
+		},
+		{
+			msg:     "it must return error when the start time is in the past",
+			req:     newRequest(func(r *ScheduledAccess) { r.StartAt = now.Add(-time.Minute) }),
+			wantErr: "invalid scheduled access, the start time must be in the future",
+		},
+		{
+			msg:     "it must return error when the duration is lower than a minute",
+			req:     newRequest(func(r *ScheduledAccess) { r.Duration = time.Second * 30 }),
+			wantErr: "invalid scheduled access, the duration must be between 1 minute and 48 hours",
+		},
+		{
+			msg:     "it must return error when the duration is greater than the maximum",
+			req:     newRequest(func(r *ScheduledAccess) { r.Duration = MaxAccessDuration + time.Minute }),
+			wantErr: "invalid scheduled access, the duration must be between 1 minute and 48 hours",
+		},
+		{
+			msg:     "it must return error when the reason is empty",
+			req:     newRequest(func(r *ScheduledAccess) { r.Reason = "" }),
+			wantErr: "invalid scheduled access, the reason is required",
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			err := validateScheduledAccess(tt.req, now)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.ErrorIs(t, err, ErrInvalidSchedule)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
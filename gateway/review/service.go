@@ -22,6 +22,12 @@ type (
 
 	transportService interface {
 		ReviewStatusChange(rev *types.Review)
+		ReviewEscalated(rev *types.Review, group string)
+		ReviewExtensionRequested(rev *types.Review)
+		ReviewExtensionChange(rev *types.Review)
+		ReviewScheduled(rev *types.Review)
+		ReviewBreakGlass(rev *types.Review)
+		ReviewCreated(rev *types.Review)
 	}
 )
 
@@ -30,11 +36,18 @@ var (
 	ErrWrongState   = errors.New("review is in wrong state")
 	ErrNotEligible  = errors.New("not eligible for review")
 	ErrSelfApproval = errors.New("unable to self approve review")
+	// ErrAlreadyReviewed is returned when the user has approved all of its groups
+	ErrAlreadyReviewed = errors.New("review already approved by the user")
 )
 
 const (
-	ReviewTypeJit     = "jit"
-	ReviewTypeOneTime = "onetime"
+	ReviewTypeJit        = "jit"
+	ReviewTypeOneTime    = "onetime"
+	ReviewTypeScheduled  = "scheduled"
+	ReviewTypeBreakGlass = "break_glass"
+
+	// MaxAccessDuration is the maximum access time of time based reviews
+	MaxAccessDuration = time.Hour * 48
 )
 
 func (s *Service) FindOne(ctx pgrest.OrgContext, id string) (*types.Review, error) {
@@ -75,6 +88,11 @@ func (s *Service) Persist(ctx pgrest.OrgContext, review *types.Review) error {
 		Status:           review.Status,
 		ReviewGroupsIds:  review.ReviewGroupsIds,
 		ReviewGroupsData: review.ReviewGroupsData,
+		Policy:           review.Policy,
+		Extension:        review.Extension,
+		AccessStartAt:    review.AccessStartAt,
+		AccessReason:     review.AccessReason,
+		JiraIssueKey:     review.JiraIssueKey,
 	}
 
 	if err := pgreview.New().Upsert(parsedReview); err != nil {
@@ -83,13 +101,23 @@ func (s *Service) Persist(ctx pgrest.OrgContext, review *types.Review) error {
 	return nil
 }
 
+// Create persists a review created outside of the stream of a session, e.g.: by guard rails rules,
+// and notifies its groups like the reviews created when the session is opened.
+func (s *Service) Create(ctx pgrest.OrgContext, rev *types.Review) error {
+	if err := s.Persist(ctx, rev); err != nil {
+		return err
+	}
+	s.TransportService.ReviewCreated(rev)
+	return nil
+}
+
 func (s *Service) RevokeBySid(ctx pgrest.OrgContext, sid string) (*types.Review, error) {
 	rev, err := s.FindBySessionID(ctx, sid)
 	if err != nil {
 		return nil, err
 	}
-	// non-jit type reviews cannot be revoked
-	if rev == nil || rev.Type != ReviewTypeJit {
+	// only time based reviews could be revoked
+	if rev == nil || !isTimeBased(rev) {
 		return nil, ErrNotFound
 	}
 	// only approved reviews could be revoked
@@ -101,7 +129,8 @@ func (s *Service) RevokeBySid(ctx pgrest.OrgContext, sid string) (*types.Review,
 	if err := s.Persist(ctx, rev); err != nil {
 		return nil, fmt.Errorf("saving review error: %v", err)
 	}
-
+	// terminate the sessions opened with the revoked access
+	s.TransportService.ReviewStatusChange(rev)
 	return rev, nil
 }
 
@@ -110,8 +139,8 @@ func (s *Service) Revoke(ctx pgrest.OrgContext, reviewID string) (*types.Review,
 	if err != nil {
 		return nil, err
 	}
-	// non-jit type reviews cannot be revoked
-	if rev == nil || rev.Type != ReviewTypeJit {
+	// only time based reviews could be revoked
+	if rev == nil || !isTimeBased(rev) {
 		return nil, ErrNotFound
 	}
 	// only approved reviews could be revoked
@@ -123,7 +152,8 @@ func (s *Service) Revoke(ctx pgrest.OrgContext, reviewID string) (*types.Review,
 	if err := s.Persist(ctx, rev); err != nil {
 		return nil, fmt.Errorf("saving review error: %v", err)
 	}
-
+	// terminate the sessions opened with the revoked access
+	s.TransportService.ReviewStatusChange(rev)
 	return rev, nil
 }
 
@@ -153,8 +183,15 @@ func (s *Service) Review(ctx *storagev2.Context, reviewID string, status types.R
 		return rev, ErrWrongState
 	}
 
-	if rev.ReviewOwner.Id == ctx.UserID && !ctx.IsAdmin() {
-		return nil, ErrSelfApproval
+	policy := pgreview.PolicyOrDefault(rev.Policy)
+	if rev.ReviewOwner.Id == ctx.UserID {
+		if !ctx.IsAdmin() {
+			return nil, ErrSelfApproval
+		}
+		// admin users approving their own reviews would fulfill a policy of distinct approvers
+		if status == types.ReviewStatusApproved && requiresDistinctApprovers(policy) {
+			return nil, ErrSelfApproval
+		}
 	}
 
 	isEligibleReviewer := false
@@ -168,38 +205,56 @@ func (s *Service) Review(ctx *storagev2.Context, reviewID string, status types.R
 		return nil, ErrNotEligible
 	}
 
-	reviewsCount := len(rev.ReviewGroupsData)
-	approvedCount := 0
-
+	reviewer := types.ReviewOwner{
+		Id:      ctx.UserID,
+		Name:    ctx.UserName,
+		Email:   ctx.UserEmail,
+		SlackID: ctx.SlackID,
+	}
+	t := time.Now().UTC().Format(time.RFC3339)
 	if status == types.ReviewStatusRejected {
 		rev.Status = status
-	}
-
-	for i, r := range rev.ReviewGroupsData {
-		if pb.IsInList(r.Group, ctx.UserGroups) {
-			t := time.Now().UTC().Format(time.RFC3339)
-			rev.ReviewGroupsData[i].Status = status
-			rev.ReviewGroupsData[i].ReviewedBy = &types.ReviewOwner{
-				Id:      ctx.UserID,
-				Name:    ctx.UserName,
-				Email:   ctx.UserEmail,
-				SlackID: ctx.SlackID,
+		for i, r := range rev.ReviewGroupsData {
+			if pb.IsInList(r.Group, ctx.UserGroups) {
+				rev.ReviewGroupsData[i].Status = status
+				rev.ReviewGroupsData[i].ReviewedBy = &reviewer
+				rev.ReviewGroupsData[i].ReviewDate = &t
 			}
-			rev.ReviewGroupsData[i].ReviewDate = &t
 		}
-		if rev.ReviewGroupsData[i].Status == types.ReviewStatusApproved {
-			approvedCount++
+	} else {
+		approved := false
+		for i, r := range rev.ReviewGroupsData {
+			if pb.IsInList(r.Group, ctx.UserGroups) &&
+				addGroupApproval(&rev.ReviewGroupsData[i], policy, reviewer, t) {
+				approved = true
+			}
+		}
+		if !approved {
+			return nil, ErrAlreadyReviewed
+		}
+		if isApproved(rev.ReviewGroupsData, policy) {
+			// scheduled and break-glass reviews keep the revoke time of the access granted
+			if rev.Type != ReviewTypeScheduled && rev.Type != ReviewTypeBreakGlass {
+				rev.RevokeAt = func() *time.Time { t := time.Now().UTC().Add(rev.AccessDuration); return &t }()
+			}
+			rev.Status = types.ReviewStatusApproved
 		}
-	}
-
-	if reviewsCount == approvedCount {
-		rev.RevokeAt = func() *time.Time { t := time.Now().UTC().Add(rev.AccessDuration); return &t }()
-		rev.Status = types.ReviewStatusApproved
 	}
 
 	if err := s.Persist(ctx, rev); err != nil {
 		return nil, fmt.Errorf("saving review error: %v", err)
 	}
+	switch rev.Type {
+	case ReviewTypeScheduled:
+		// scheduled reviews aren't bound to a session, there's no client waiting for them
+		return rev, nil
+	case ReviewTypeBreakGlass:
+		// the session was opened without waiting, a rejection terminates it
+		if rev.Status == types.ReviewStatusRejected {
+			s.TransportService.ReviewStatusChange(rev)
+		}
+		return rev, nil
+	}
 	switch rev.Status {
 	case types.ReviewStatusApproved:
 		if err := pgsession.New().UpdateStatus(ctx, rev.Session, string(openapi.SessionStatusReady)); err != nil {
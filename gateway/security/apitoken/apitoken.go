@@ -0,0 +1,197 @@
+package apitoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	pb "github.com/hoophq/hoop/common/proto"
+	"github.com/hoophq/hoop/gateway/models"
+	pguserauth "github.com/hoophq/hoop/gateway/pgrest/userauth"
+	"github.com/hoophq/hoop/gateway/storagev2/types"
+)
+
+// Prefix identifies an api token in the authorization header
+const Prefix = "hpat_"
+
+const (
+	// ScopeSessionsRead allows listing and reading sessions
+	ScopeSessionsRead = "sessions:read"
+	// ScopeConnectionsRead allows listing and reading connections
+	ScopeConnectionsRead = "connections:read"
+	// ScopeExec allows running one-off executions via the api or the exec verb
+	ScopeExec = "exec"
+	// ScopeConnect allows opening interactive sessions and proxies to connections
+	ScopeConnect = "connect"
+
+	// displayPrefixSize is the amount of characters of a token
+	// stored in plain text to identify it
+	displayPrefixSize = len(Prefix) + 8
+
+	// lastUsedAtResolution avoids updating the token on every request
+	lastUsedAtResolution = time.Minute
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid api token")
+
+	Scopes = []string{ScopeSessionsRead, ScopeConnectionsRead, ScopeExec, ScopeConnect}
+)
+
+type route struct {
+	method string
+	path   string
+}
+
+// commonRoutes are allowed to any token, they're used by clients
+// to obtain information about the gateway and the token owner
+var commonRoutes = []route{
+	{"GET", "/serverinfo"},
+	{"GET", "/userinfo"},
+}
+
+// scopeRoutes maps the scopes to the routes they grant access to. The paths
+// are relative to the api prefix and must match the ones registered in the router.
+var scopeRoutes = map[string][]route{
+	ScopeSessionsRead: {
+		{"GET", "/sessions"},
+		{"GET", "/sessions/:session_id"},
+		{"GET", "/sessions/:session_id/playback"},
+		{"GET", "/sessions/:session_id/verify"},
+		{"GET", "/plugins/audit/sessions"},
+		{"GET", "/plugins/audit/sessions/:session_id"},
+		{"GET", "/reports/sessions"},
+	},
+	ScopeConnectionsRead: {
+		{"GET", "/connections"},
+		{"GET", "/connections/:nameOrID"},
+		{"GET", "/connections/:nameOrID/databases"},
+		{"GET", "/connections/:nameOrID/schemas"},
+	},
+	ScopeExec: {
+		{"POST", "/sessions"},
+		{"POST", "/sessions/:session_id/exec"},
+		{"POST", "/connections/:name/exec"},
+		// allow fetching the result of executions that are still running
+		{"GET", "/sessions/:session_id"},
+	},
+}
+
+// Generate returns a new token and its hash
+func Generate() (token, tokenHash string, err error) {
+	secretRandomBytes := make([]byte, 32)
+	if _, err := rand.Read(secretRandomBytes); err != nil {
+		return "", "", fmt.Errorf("failed generating entropy, err=%v", err)
+	}
+	token = Prefix + base64.RawURLEncoding.EncodeToString(secretRandomBytes)
+	return token, Hash(token), nil
+}
+
+// Hash returns the sha256 of a token in hex format
+func Hash(token string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(token)))
+}
+
+// IsToken returns true if the bearer token is an api token
+func IsToken(token string) bool { return strings.HasPrefix(token, Prefix) }
+
+// DisplayPrefix returns the part of the token that is safe to display
+func DisplayPrefix(token string) string {
+	if len(token) < displayPrefixSize {
+		return token
+	}
+	return token[:displayPrefixSize]
+}
+
+// ValidateScopes validates if the scopes are known and not repeated
+func ValidateScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return fmt.Errorf("at least one scope is required, accepted values are: %v", strings.Join(Scopes, ", "))
+	}
+	for i, scope := range scopes {
+		if !slices.Contains(Scopes, scope) {
+			return fmt.Errorf("invalid scope %q, accepted values are: %v", scope, strings.Join(Scopes, ", "))
+		}
+		if slices.Contains(scopes[:i], scope) {
+			return fmt.Errorf("scope %q is repeated", scope)
+		}
+	}
+	return nil
+}
+
+// IsRouteAllowed validates if any of the scopes grants access to a route.
+// The path is the route template relative to the api prefix, e.g.: /sessions/:session_id
+func IsRouteAllowed(scopes []string, method, path string) bool {
+	if method == "HEAD" {
+		method = "GET"
+	}
+	r := route{method, path}
+	if slices.Contains(commonRoutes, r) {
+		return true
+	}
+	for _, scope := range scopes {
+		if slices.Contains(scopeRoutes[scope], r) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsVerbAllowed validates if any of the scopes grants access to a client verb.
+// Plain executions are issued by the gateway on behalf of routes already
+// validated by the scopes of the token, thus they're always allowed.
+func IsVerbAllowed(scopes []string, verb string) bool {
+	switch verb {
+	case pb.ClientVerbPlainExec:
+		return true
+	case pb.ClientVerbExec:
+		return slices.Contains(scopes, ScopeExec)
+	case pb.ClientVerbConnect:
+		return slices.Contains(scopes, ScopeConnect)
+	}
+	return false
+}
+
+// IsConnectionAllowed validates if a token restricted to a list of connections could access a connection
+func IsConnectionAllowed(connections []string, name string) bool {
+	return len(connections) == 0 || slices.Contains(connections, name)
+}
+
+// Authenticate validates the token and returns the context of the user or service account
+// it acts on behalf of. The scopes of the token are returned to be validated by the caller.
+func Authenticate(token string) (*pguserauth.Context, []string, error) {
+	apiToken, err := models.GetAPITokenByHash(Hash(token))
+	if err != nil {
+		if err == models.ErrNotFound {
+			return nil, nil, ErrInvalidToken
+		}
+		return nil, nil, fmt.Errorf("failed fetching api token: %v", err)
+	}
+	now := time.Now().UTC()
+	if !apiToken.IsActive(now) {
+		return nil, nil, fmt.Errorf("%w, the token is expired or revoked", ErrInvalidToken)
+	}
+	ctx, err := pguserauth.New().FetchUserContext(apiToken.UserSubject)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ctx.IsEmpty() || ctx.OrgID != apiToken.OrgID {
+		return nil, nil, fmt.Errorf("%w, the owner of the token does not exist", ErrInvalidToken)
+	}
+	if ctx.UserStatus != string(types.UserStatusActive) {
+		return nil, nil, fmt.Errorf("%w, the owner of the token is not active", ErrInvalidToken)
+	}
+	if !apiToken.LastUsedAt.Valid || now.Sub(apiToken.LastUsedAt.Time) > lastUsedAtResolution {
+		if err := models.UpdateAPITokenLastUsedAt(apiToken.ID, now); err != nil {
+			return nil, nil, fmt.Errorf("failed updating api token: %v", err)
+		}
+	}
+	ctx.TokenID = apiToken.ID
+	ctx.TokenConnections = apiToken.Connections
+	return ctx, apiToken.Scopes, nil
+}
@@ -0,0 +1,107 @@
+package apitoken
+
+import (
+	"testing"
+
+	pb "github.com/hoophq/hoop/common/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	token, tokenHash, err := Generate()
+	assert.NoError(t, err)
+	assert.True(t, IsToken(token))
+	assert.Equal(t, Hash(token), tokenHash)
+	assert.Len(t, DisplayPrefix(token), len(Prefix)+8)
+	assert.Equal(t, token[:len(Prefix)+8], DisplayPrefix(token))
+
+	other, _, err := Generate()
+	assert.NoError(t, err)
+	assert.NotEqual(t, token, other)
+}
+
+func TestValidateScopes(t *testing.T) {
+	for _, tt := range []struct {
+		msg     string
+		scopes  []string
+		wantErr string
+	}{
+		{msg: "it must accept a single scope", scopes: []string{ScopeSessionsRead}},
+		{msg: "it must accept all scopes", scopes: Scopes},
+		{
+			msg:     "it must return error when there are no scopes",
+			wantErr: "at least one scope is required, accepted values are: sessions:read, connections:read, exec, connect",
+		},
+		{
+			msg:     "it must return error with an unknown scope",
+			scopes:  []string{ScopeExec, "admin"},
+			wantErr: `invalid scope "admin", accepted values are: sessions:read, connections:read, exec, connect`,
+		},
+		{
+			msg:     "it must return error with repeated scopes",
+			scopes:  []string{ScopeExec, ScopeExec},
+			wantErr: `scope "exec" is repeated`,
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			err := ValidateScopes(tt.scopes)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestIsRouteAllowed(t *testing.T) {
+	for _, tt := range []struct {
+		msg    string
+		scopes []string
+		method string
+		path   string
+		want   bool
+	}{
+		{msg: "it must allow listing sessions", scopes: []string{ScopeSessionsRead}, method: "GET", path: "/sessions", want: true},
+		{msg: "it must allow head requests of read routes", scopes: []string{ScopeSessionsRead}, method: "HEAD", path: "/sessions/:session_id", want: true},
+		{msg: "it must allow fetching exec results", scopes: []string{ScopeExec}, method: "GET", path: "/sessions/:session_id", want: true},
+		{msg: "it must allow running executions", scopes: []string{ScopeExec}, method: "POST", path: "/sessions", want: true},
+		{msg: "it must allow reading connections", scopes: []string{ScopeConnectionsRead}, method: "GET", path: "/connections/:nameOrID", want: true},
+		{msg: "it must allow the common routes without scopes", method: "GET", path: "/userinfo", want: true},
+		{msg: "it must deny running executions with read scopes", scopes: []string{ScopeSessionsRead}, method: "POST", path: "/sessions"},
+		{msg: "it must deny listing sessions with exec scope", scopes: []string{ScopeExec}, method: "GET", path: "/sessions"},
+		{msg: "it must deny changing connections", scopes: Scopes, method: "PUT", path: "/connections/:nameOrID"},
+		{msg: "it must deny managing tokens", scopes: Scopes, method: "POST", path: "/tokens"},
+		{msg: "it must deny the connect scope on api routes", scopes: []string{ScopeConnect}, method: "GET", path: "/connections"},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsRouteAllowed(tt.scopes, tt.method, tt.path))
+		})
+	}
+}
+
+func TestIsVerbAllowed(t *testing.T) {
+	for _, tt := range []struct {
+		msg    string
+		scopes []string
+		verb   string
+		want   bool
+	}{
+		{msg: "it must allow exec with the exec scope", scopes: []string{ScopeExec}, verb: pb.ClientVerbExec, want: true},
+		{msg: "it must allow connect with the connect scope", scopes: []string{ScopeConnect}, verb: pb.ClientVerbConnect, want: true},
+		{msg: "it must allow plain executions issued by the gateway", verb: pb.ClientVerbPlainExec, want: true},
+		{msg: "it must deny connect with the exec scope", scopes: []string{ScopeExec}, verb: pb.ClientVerbConnect},
+		{msg: "it must deny exec with read scopes", scopes: []string{ScopeSessionsRead, ScopeConnectionsRead}, verb: pb.ClientVerbExec},
+		{msg: "it must deny unknown verbs", scopes: Scopes, verb: ""},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsVerbAllowed(tt.scopes, tt.verb))
+		})
+	}
+}
+
+func TestIsConnectionAllowed(t *testing.T) {
+	assert.True(t, IsConnectionAllowed(nil, "pgdemo"))
+	assert.True(t, IsConnectionAllowed([]string{"mysqldemo", "pgdemo"}, "pgdemo"))
+	assert.False(t, IsConnectionAllowed([]string{"mysqldemo"}, "pgdemo"))
+}
@@ -0,0 +1,147 @@
+// Package asciicast encodes the event stream of sessions in the asciinema v2 file format.
+//
+// https://docs.asciinema.org/manual/asciicast/v2/
+package asciicast
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	Version     = 2
+	ContentType = "application/x-asciicast"
+
+	defaultWidth  = 80
+	defaultHeight = 24
+)
+
+// Header is the first line of an asciicast file
+type Header struct {
+	Version   int    `json:"version"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Timestamp int64  `json:"timestamp,omitempty"`
+	Title     string `json:"title,omitempty"`
+}
+
+// Event is a line of an asciicast file, it's encoded as [time, code, data]
+type Event struct {
+	// the time in seconds relative to the start of the session
+	Time float64
+	// o: output, i: input, r: resize
+	Code string
+	Data string
+}
+
+func (e Event) MarshalJSON() ([]byte, error) {
+	// the output of terminals is kept as is, without escaping html characters
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode([]any{e.Time, e.Code, e.Data}); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func (e *Event) UnmarshalJSON(data []byte) error {
+	var v []any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if len(v) != 3 {
+		return fmt.Errorf("invalid event, expected 3 elements, got=%v", len(v))
+	}
+	var ok1, ok2, ok3 bool
+	e.Time, ok1 = v[0].(float64)
+	e.Code, ok2 = v[1].(string)
+	e.Data, ok3 = v[2].(string)
+	if !ok1 || !ok2 || !ok3 {
+		return fmt.Errorf("invalid event, expected the format [time, code, data]")
+	}
+	return nil
+}
+
+// Parse converts the event stream of a session to asciicast events, the stream
+// has the format [[<event-time>, <event-type>, <base64-content>], ...].
+// The initial size of the terminal is the first resize event of the stream.
+func Parse(blobStream []byte, startDate time.Time, title string) (*Header, []Event, error) {
+	header := &Header{Version: Version, Timestamp: startDate.Unix(), Title: title}
+	var eventStream [][]any
+	if len(blobStream) > 0 {
+		if err := json.Unmarshal(blobStream, &eventStream); err != nil {
+			return nil, nil, fmt.Errorf("failed decoding event stream: %v", err)
+		}
+	}
+	events := []Event{}
+	for _, event := range eventStream {
+		if len(event) < 3 {
+			continue
+		}
+		eventTime, _ := event[0].(float64)
+		eventType, _ := event[1].(string)
+		eventData, _ := event[2].(string)
+		data, err := base64.StdEncoding.DecodeString(eventData)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed decoding event data at %v: %v", eventTime, err)
+		}
+		switch eventType {
+		case "o", "e":
+			events = append(events, Event{Time: eventTime, Code: "o", Data: string(data)})
+		case "i":
+			events = append(events, Event{Time: eventTime, Code: "i", Data: string(data)})
+		case "r":
+			rows, cols, ok := parseTerminalSize(string(data))
+			if !ok {
+				continue
+			}
+			if header.Width == 0 {
+				header.Width, header.Height = cols, rows
+			}
+			events = append(events, Event{Time: eventTime, Code: "r", Data: fmt.Sprintf("%dx%d", cols, rows)})
+		}
+	}
+	if header.Width == 0 {
+		header.Width, header.Height = defaultWidth, defaultHeight
+	}
+	return header, events, nil
+}
+
+// Write encodes the header and the events as newline delimited json
+func Write(w io.Writer, header *Header, events []Event) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(header); err != nil {
+		return err
+	}
+	for _, ev := range events {
+		if err := enc.Encode(ev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseTerminalSize parses the size sent by clients in the format: rows,cols,x,y
+func parseTerminalSize(size string) (rows, cols int, ok bool) {
+	parts := strings.Split(size, ",")
+	if len(parts) < 2 {
+		return 0, 0, false
+	}
+	rows, err := strconv.Atoi(parts[0])
+	if err != nil || rows <= 0 {
+		return 0, 0, false
+	}
+	cols, err = strconv.Atoi(parts[1])
+	if err != nil || cols <= 0 {
+		return 0, 0, false
+	}
+	return rows, cols, true
+}
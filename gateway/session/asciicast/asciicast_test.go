@@ -0,0 +1,106 @@
+package asciicast
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newEventStream(events ...[3]any) []byte {
+	var items []string
+	for _, ev := range events {
+		items = append(items, fmt.Sprintf("[%v, %q, %q]", ev[0], ev[1],
+			base64.StdEncoding.EncodeToString([]byte(ev[2].(string)))))
+	}
+	return []byte("[" + strings.Join(items, ",") + "]")
+}
+
+func TestParse(t *testing.T) {
+	startDate := time.Date(2024, 7, 25, 19, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		msg        string
+		stream     []byte
+		wantHeader Header
+		wantEvents []Event
+		wantErr    string
+	}{
+		{
+			msg:        "it must use the default size when there are no resize events",
+			stream:     newEventStream([3]any{0.5, "o", "bash-5.2$ "}),
+			wantHeader: Header{Version: 2, Width: 80, Height: 24, Timestamp: startDate.Unix(), Title: "bash"},
+			wantEvents: []Event{{Time: 0.5, Code: "o", Data: "bash-5.2$ "}},
+		},
+		{
+			msg: "it must use the first resize event as the size of the terminal",
+			stream: newEventStream(
+				[3]any{0.1, "r", "40,120,0,0"},
+				[3]any{0.2, "i", "ls\r"},
+				[3]any{0.3, "o", "file.txt\r\n"},
+				[3]any{0.4, "e", "permission denied"},
+				[3]any{1.5, "r", "50,200,0,0"},
+			),
+			wantHeader: Header{Version: 2, Width: 120, Height: 40, Timestamp: startDate.Unix(), Title: "bash"},
+			wantEvents: []Event{
+				{Time: 0.1, Code: "r", Data: "120x40"},
+				{Time: 0.2, Code: "i", Data: "ls\r"},
+				{Time: 0.3, Code: "o", Data: "file.txt\r\n"},
+				{Time: 0.4, Code: "o", Data: "permission denied"},
+				{Time: 1.5, Code: "r", Data: "200x50"},
+			},
+		},
+		{
+			msg:        "it must ignore invalid resize events and unknown event types",
+			stream:     newEventStream([3]any{0.1, "r", "invalid"}, [3]any{0.2, "q", "SELECT 1"}),
+			wantHeader: Header{Version: 2, Width: 80, Height: 24, Timestamp: startDate.Unix(), Title: "bash"},
+			wantEvents: []Event{},
+		},
+		{
+			msg:        "it must parse an empty event stream",
+			stream:     nil,
+			wantHeader: Header{Version: 2, Width: 80, Height: 24, Timestamp: startDate.Unix(), Title: "bash"},
+			wantEvents: []Event{},
+		},
+		{
+			msg:     "it must return error with an invalid event stream",
+			stream:  []byte(`{}`),
+			wantErr: "failed decoding event stream: json: cannot unmarshal object into Go value of type [][]interface {}",
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			header, events, err := Parse(tt.stream, startDate, "bash")
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantHeader, *header)
+			assert.Equal(t, tt.wantEvents, events)
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	header := &Header{Version: 2, Width: 120, Height: 40, Timestamp: 1721934000}
+	events := []Event{{Time: 0.25, Code: "o", Data: "<html>\r\n"}, {Time: 1, Code: "r", Data: "200x50"}}
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, header, events))
+	want := `{"version":2,"width":120,"height":40,"timestamp":1721934000}
+[0.25,"o","<html>\r\n"]
+[1,"r","200x50"]
+`
+	assert.Equal(t, want, buf.String())
+}
+
+func TestUnmarshalEvent(t *testing.T) {
+	var ev Event
+	assert.NoError(t, json.Unmarshal([]byte(`[0.25,"o","<html>\r\n"]`), &ev))
+	assert.Equal(t, Event{Time: 0.25, Code: "o", Data: "<html>\r\n"}, ev)
+	assert.EqualError(t, json.Unmarshal([]byte(`[0.25,"o"]`), &ev), "invalid event, expected 3 elements, got=2")
+	assert.EqualError(t, json.Unmarshal([]byte(`["0.25","o","ls"]`), &ev), "invalid event, expected the format [time, code, data]")
+}
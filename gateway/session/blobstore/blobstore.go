@@ -0,0 +1,77 @@
+// Package blobstore persists the payload of session blobs. The metadata of
+// blobs is always stored in Postgres (private.blobs), the payload is kept in
+// the backend configured by the SESSION_STORAGE_BACKEND env.
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/hoophq/hoop/gateway/appconfig"
+)
+
+const (
+	BackendPostgres = "postgres"
+	BackendS3       = "s3"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+// Store persists the payload of blobs addressed by a key
+type Store interface {
+	// Backend is the name stored along with the metadata of the blob
+	Backend() string
+	// Key returns the location of a blob in the store
+	Key(orgID, blobID string) string
+	Put(ctx context.Context, key string, data []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+var (
+	mu          sync.RWMutex
+	objectStore Store
+)
+
+// Load configures the object store based on the runtime configuration.
+// It's a noop when the payloads are kept in Postgres.
+func Load() error {
+	cfg := appconfig.Get()
+	if cfg.SessionStorageBackend() != BackendS3 {
+		return nil
+	}
+	store, err := NewS3(context.Background(), cfg.SessionStorageS3())
+	if err != nil {
+		return err
+	}
+	SetObjectStore(store)
+	return nil
+}
+
+// SetObjectStore sets the store used to write new blobs, a nil value
+// persists new blobs in Postgres
+func SetObjectStore(s Store) {
+	mu.Lock()
+	defer mu.Unlock()
+	objectStore = s
+}
+
+// ObjectStore returns the configured object store or nil if
+// the blobs are persisted in Postgres
+func ObjectStore() Store {
+	mu.RLock()
+	defer mu.RUnlock()
+	return objectStore
+}
+
+// ObjectStoreFor returns the object store of a backend, it returns an error if the
+// gateway is not configured with it, e.g.: blobs written by a previous configuration
+func ObjectStoreFor(backend string) (Store, error) {
+	store := ObjectStore()
+	if store == nil || store.Backend() != backend {
+		return nil, fmt.Errorf("session storage backend %q is not configured", backend)
+	}
+	return store, nil
+}
@@ -0,0 +1,46 @@
+package blobstore
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type postgresStore struct {
+	db *gorm.DB
+}
+
+// NewPostgres returns a store that keeps the payload in the blob_stream
+// column of private.blobs, the key is the id of the blob. The row
+// of the blob must exist before storing its payload.
+func NewPostgres(db *gorm.DB) Store { return &postgresStore{db: db} }
+
+func (s *postgresStore) Backend() string                 { return BackendPostgres }
+func (s *postgresStore) Key(orgID, blobID string) string { return blobID }
+
+func (s *postgresStore) Put(ctx context.Context, key string, data []byte) error {
+	res := s.db.WithContext(ctx).
+		Exec(`UPDATE private.blobs SET blob_stream = ? WHERE id = ?`, string(data), key)
+	if res.Error == nil && res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return res.Error
+}
+
+func (s *postgresStore) Get(ctx context.Context, key string) ([]byte, error) {
+	var data []byte
+	res := s.db.WithContext(ctx).
+		Raw(`SELECT blob_stream FROM private.blobs WHERE id = ?`, key).
+		Scan(&data)
+	if res.Error == nil && res.RowsAffected == 0 {
+		return nil, ErrNotFound
+	}
+	return data, res.Error
+}
+
+// Delete clears the payload, the metadata of the blob is managed by the caller
+func (s *postgresStore) Delete(ctx context.Context, key string) error {
+	return s.db.WithContext(ctx).
+		Exec(`UPDATE private.blobs SET blob_stream = NULL WHERE id = ?`, key).
+		Error
+}
@@ -0,0 +1,93 @@
+package blobstore
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hoophq/hoop/gateway/appconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeObjectStore emulates the path style api of S3 compatible stores (e.g.: MinIO)
+type fakeObjectStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeObjectStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = data
+	case http.MethodGet:
+		data, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`)
+			return
+		}
+		_, _ = w.Write(data)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	fake := &fakeObjectStore{objects: map[string][]byte{}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	store, err := NewS3(context.Background(), &appconfig.S3StorageConfig{
+		Bucket:          "sessions",
+		Region:          "us-east-1",
+		Endpoint:        srv.URL,
+		AccessKeyID:     "minioadmin",
+		SecretAccessKey: "minioadmin",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, BackendS3, store.Backend())
+
+	ctx := context.Background()
+	key := store.Key("org-id", "blob-id")
+	assert.Equal(t, "org-id/blobs/blob-id.json", key)
+
+	payload := []byte(`[[0.1,"o","aGVsbG8="]]`)
+	assert.NoError(t, store.Put(ctx, key, payload))
+	assert.Contains(t, fake.objects, "/sessions/org-id/blobs/blob-id.json")
+
+	data, err := store.Get(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, payload, data)
+
+	assert.NoError(t, store.Delete(ctx, key))
+	_, err = store.Get(ctx, key)
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestObjectStoreFor(t *testing.T) {
+	defer SetObjectStore(nil)
+
+	_, err := ObjectStoreFor(BackendS3)
+	assert.Error(t, err)
+
+	store, err := NewS3(context.Background(), &appconfig.S3StorageConfig{Bucket: "sessions", Region: "us-east-1"})
+	assert.NoError(t, err)
+	SetObjectStore(store)
+	got, err := ObjectStoreFor(BackendS3)
+	assert.NoError(t, err)
+	assert.Equal(t, store, got)
+
+	_, err = ObjectStoreFor("gcs")
+	assert.True(t, strings.Contains(err.Error(), `"gcs" is not configured`))
+}
@@ -0,0 +1,60 @@
+package eventlogv1
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+)
+
+const prevHashKeyName string = "__prev_hash"
+
+var ErrChainBroken = errors.New("the event log does not match the hash of the previous log")
+
+// Encoded is an event log already encoded
+type Encoded []byte
+
+func (e Encoded) Encode() ([]byte, error) { return e, nil }
+
+// Chain links a sequence of event logs, each log holds the hash of the previous
+// encoded log in its metadata. Removing, reordering or changing any log breaks
+// the chain from that point on.
+type Chain struct {
+	prevHash []byte
+}
+
+// NewChain starts a chain, the seed is the hash of the previous log of the first event log
+func NewChain(seed []byte) *Chain {
+	h := sha256.Sum256(seed)
+	return &Chain{prevHash: h[:]}
+}
+
+// Link adds the hash of the previous log to the event log and returns it encoded
+func (c *Chain) Link(e *EventLog) (Encoded, error) {
+	if e.metadata == nil {
+		e.metadata = map[string][]byte{}
+	}
+	data, err := e.WithMetadata(prevHashKeyName, c.prevHash).Encode()
+	if err != nil {
+		return nil, err
+	}
+	c.next(data)
+	return data, nil
+}
+
+// Verify checks if the encoded event log is the next one of the chain
+func (c *Chain) Verify(data []byte) error {
+	ev, err := Decode(data)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(ev.GetMetadata(prevHashKeyName), c.prevHash) {
+		return ErrChainBroken
+	}
+	c.next(data)
+	return nil
+}
+
+func (c *Chain) next(data []byte) {
+	h := sha256.Sum256(data)
+	c.prevHash = h[:]
+}
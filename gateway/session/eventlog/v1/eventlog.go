@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 )
 
@@ -23,9 +24,21 @@ const (
 	InputType  EventType = 'i'
 	OutputType EventType = 'o'
 	ErrorType  EventType = 'e'
+	// StatementType is a statement decoded from a database protocol,
+	// the payload is the statement and the metadata its attributes.
+	StatementType EventType = 'q'
+	// ResizeType is a resize of the client terminal,
+	// the payload is the size in the format: rows,cols,x,y
+	ResizeType EventType = 'r'
 
 	commitErrKeyName string = "__commit_error"
 
+	stmtConnectionIDKeyName string = "stmt_conn"
+	stmtEndTimeKeyName      string = "stmt_end"
+	stmtRowsKeyName         string = "stmt_rows"
+	stmtErrorCodeKeyName    string = "stmt_err_code"
+	stmtErrorMessageKeyName string = "stmt_err_msg"
+
 	Version = "v1"
 )
 
@@ -68,6 +81,49 @@ func NewCommitError(eventTime time.Time, errPayload string) *EventLog {
 	}
 }
 
+// StatementInfo are the attributes of a statement event
+type StatementInfo struct {
+	ConnectionID string
+	EndTime      time.Time
+	// Rows returned or affected by the statement, -1 when it's unknown
+	Rows         int64
+	ErrorCode    string
+	ErrorMessage string
+}
+
+// NewStatement creates a statement event, the event time is when the statement started
+func NewStatement(startTime time.Time, statement []byte, info StatementInfo) *EventLog {
+	return New(startTime, StatementType, statement, map[string][]byte{
+		stmtConnectionIDKeyName: []byte(info.ConnectionID),
+		stmtEndTimeKeyName:      []byte(strconv.FormatInt(info.EndTime.UnixNano(), 10)),
+		stmtRowsKeyName:         []byte(strconv.FormatInt(info.Rows, 10)),
+		stmtErrorCodeKeyName:    []byte(info.ErrorCode),
+		stmtErrorMessageKeyName: []byte(info.ErrorMessage),
+	})
+}
+
+// StatementInfo returns the attributes of a statement event
+func (e *EventLog) StatementInfo() (*StatementInfo, error) {
+	if e.EventType != StatementType {
+		return nil, fmt.Errorf("event type %q is not a statement", string(e.EventType))
+	}
+	endTime, err := strconv.ParseInt(string(e.GetMetadata(stmtEndTimeKeyName)), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing statement end time: %v", err)
+	}
+	rows, err := strconv.ParseInt(string(e.GetMetadata(stmtRowsKeyName)), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing statement rows: %v", err)
+	}
+	return &StatementInfo{
+		ConnectionID: string(e.GetMetadata(stmtConnectionIDKeyName)),
+		EndTime:      time.Unix(0, endTime).In(time.UTC),
+		Rows:         rows,
+		ErrorCode:    string(e.GetMetadata(stmtErrorCodeKeyName)),
+		ErrorMessage: string(e.GetMetadata(stmtErrorMessageKeyName)),
+	}, nil
+}
+
 func (e *EventLog) WithMetadata(key string, val []byte) *EventLog {
 	e.metadata[key] = val
 	return e
@@ -99,7 +155,9 @@ func (e *EventLog) logSize() int {
 }
 
 func (e *EventLog) Encode() ([]byte, error) {
-	if e.EventType != InputType && e.EventType != OutputType && e.EventType != ErrorType {
+	switch e.EventType {
+	case InputType, OutputType, ErrorType, StatementType, ResizeType:
+	default:
 		return nil, ErrUnknownEventType
 	}
 	fullLogSize := e.logSize()
@@ -45,6 +45,10 @@ func TestEncodeDecode(t *testing.T) {
 				},
 			),
 		},
+		{
+			msg:  "encode and decode a terminal resize",
+			want: New(date(10, 19), ResizeType, []byte(`40,120,0,0`), nil),
+		},
 		{
 			msg:  "encode and decode it with nil data",
 			want: New(date(10, 19), InputType, nil, nil),
@@ -86,6 +90,11 @@ func TestEncodeDecode(t *testing.T) {
 				},
 			),
 		},
+		{
+			msg: "encode and decode it statement",
+			want: NewStatement(date(10, 19), []byte(`SELECT 1`), StatementInfo{
+				ConnectionID: "1", EndTime: date(10, 20), Rows: 1}),
+		},
 		{
 			msg:     "it should error with unknown event type",
 			want:    New(date(10, 19), 'x', []byte(`ls -l`), nil),
@@ -169,3 +178,92 @@ func TestDecodeWithBrokenData(t *testing.T) {
 		})
 	}
 }
+
+func TestStatementInfo(t *testing.T) {
+	want := StatementInfo{
+		ConnectionID: "2",
+		EndTime:      date(10, 20),
+		Rows:         -1,
+		ErrorCode:    "42P01",
+		ErrorMessage: `relation "foo" does not exist`,
+	}
+	data, err := NewStatement(date(10, 19), []byte(`SELECT * FROM foo`), want).Encode()
+	assert.NoError(t, err)
+	ev, err := Decode(data)
+	assert.NoError(t, err)
+	got, err := ev.StatementInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, &want, got)
+	assert.Equal(t, "SELECT * FROM foo", string(ev.Payload))
+
+	_, err = New(date(10, 19), InputType, nil, nil).StatementInfo()
+	assert.EqualError(t, err, `event type "i" is not a statement`)
+}
+
+func TestChain(t *testing.T) {
+	newChainLogs := func() [][]byte {
+		chain := NewChain([]byte(`org:sid`))
+		var logs [][]byte
+		for i, ev := range []*EventLog{
+			New(date(10, 19), InputType, []byte(`ls -l`), nil),
+			New(date(10, 20), OutputType, []byte(`total 0`), nil),
+			NewCommitError(date(10, 21), "failed persisting session"),
+		} {
+			data, err := chain.Link(ev)
+			if err != nil {
+				t.Fatalf("failed linking event log %v, reason=%v", i, err)
+			}
+			logs = append(logs, data)
+		}
+		return logs
+	}
+	for _, tt := range []struct {
+		msg        string
+		seed       string
+		tamperFn   func(logs [][]byte) [][]byte
+		wantErrIdx int
+	}{
+		{msg: "it must verify the chain", seed: "org:sid", wantErrIdx: -1},
+		{msg: "it must fail when the seed does not match", seed: "org:sid2", wantErrIdx: 0},
+		{
+			msg:        "it must fail when a log is removed",
+			seed:       "org:sid",
+			tamperFn:   func(logs [][]byte) [][]byte { return append(logs[:1], logs[2:]...) },
+			wantErrIdx: 1,
+		},
+		{
+			msg:  "it must fail when logs are reordered",
+			seed: "org:sid",
+			tamperFn: func(logs [][]byte) [][]byte {
+				return [][]byte{logs[0], logs[2], logs[1]}
+			},
+			wantErrIdx: 1,
+		},
+		{
+			msg:  "it must fail in the next log when the payload of a log changes",
+			seed: "org:sid",
+			tamperFn: func(logs [][]byte) [][]byte {
+				logs[1][17] = 'T'
+				return logs
+			},
+			wantErrIdx: 2,
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			logs := newChainLogs()
+			if tt.tamperFn != nil {
+				logs = tt.tamperFn(logs)
+			}
+			chain := NewChain([]byte(tt.seed))
+			errIdx := -1
+			for i, data := range logs {
+				if err := chain.Verify(data); err != nil {
+					assert.Equal(t, ErrChainBroken, err)
+					errIdx = i
+					break
+				}
+			}
+			assert.Equal(t, tt.wantErrIdx, errIdx)
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package integrity
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hoophq/hoop/common/log"
+	"github.com/hoophq/hoop/gateway/appconfig"
+	"github.com/hoophq/hoop/gateway/models"
+)
+
+const maxAnchorLineSize = 1024 * 1024
+
+var (
+	ErrAnchorNotFound    = errors.New("anchor not found")
+	ErrAnchorChainBroken = errors.New("anchor file chain is broken")
+
+	anchorBatchSize = 1000
+)
+
+// Anchor is a line of the anchor file. Each line holds the hash of the previous one,
+// the file is a chain on its own that could be verified apart from the gateway.
+type Anchor struct {
+	OrgID      string    `json:"org_id"`
+	SessionID  string    `json:"session_id"`
+	Events     int       `json:"events"`
+	Digest     string    `json:"digest"`
+	Signature  string    `json:"signature,omitempty"`
+	KeyID      string    `json:"key_id,omitempty"`
+	SealedAt   time.Time `json:"sealed_at"`
+	AnchoredAt time.Time `json:"anchored_at"`
+	PrevHash   string    `json:"prev_hash"`
+}
+
+// anchorFile appends anchors to a file, the hash of the last line is kept
+// to avoid reading the whole file every time anchors are appended
+type anchorFile struct {
+	path     string
+	prevHash string
+	loaded   bool
+	mu       sync.Mutex
+}
+
+// InitAnchorProcess appends the digest of sealed sessions to the anchor file periodically,
+// it's a noop when the anchor file is not configured
+func InitAnchorProcess() {
+	appc := appconfig.Get()
+	if appc.SessionAnchorFile() == "" {
+		return
+	}
+	log.Infof("initializing session integrity anchor process, file=%v, interval=%v",
+		appc.SessionAnchorFile(), appc.SessionAnchorInterval())
+	af := &anchorFile{path: appc.SessionAnchorFile()}
+	go func() {
+		for {
+			if err := af.anchorSessions(time.Now().UTC()); err != nil {
+				log.Warnf("failed anchoring session digests, reason=%v", err)
+			}
+			time.Sleep(appc.SessionAnchorInterval())
+		}
+	}()
+}
+
+func (a *anchorFile) anchorSessions(now time.Time) error {
+	for {
+		sessions, err := models.ListSessionsToAnchor(anchorBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed listing sessions to anchor: %v", err)
+		}
+		if len(sessions) == 0 {
+			return nil
+		}
+		var anchors []Anchor
+		var sessionIDs []string
+		for _, sess := range sessions {
+			anchors = append(anchors, Anchor{
+				OrgID:      sess.OrgID,
+				SessionID:  sess.ID,
+				Events:     sess.Integrity.Events,
+				Digest:     sess.Integrity.Digest,
+				Signature:  sess.Integrity.Signature,
+				KeyID:      sess.Integrity.KeyID,
+				SealedAt:   sess.Integrity.SealedAt,
+				AnchoredAt: now,
+			})
+			sessionIDs = append(sessionIDs, sess.ID)
+		}
+		if err := a.append(anchors); err != nil {
+			return err
+		}
+		if err := models.MarkSessionsAnchored(sessionIDs, now); err != nil {
+			return fmt.Errorf("failed marking sessions as anchored: %v", err)
+		}
+		log.Infof("anchored session digests, total=%v", len(anchors))
+		if len(sessions) < anchorBatchSize {
+			return nil
+		}
+	}
+}
+
+// append writes the anchors at the end of the file and syncs it to disk
+func (a *anchorFile) append(anchors []Anchor) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.loaded {
+		prevHash, err := scanAnchors(a.path, nil)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		a.prevHash, a.loaded = prevHash, true
+	}
+	f, err := os.OpenFile(a.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed opening anchor file: %v", err)
+	}
+	defer f.Close()
+	prevHash := a.prevHash
+	var data []byte
+	for _, anchor := range anchors {
+		anchor.PrevHash = prevHash
+		line, err := json.Marshal(anchor)
+		if err != nil {
+			return fmt.Errorf("failed encoding anchor: %v", err)
+		}
+		prevHash = hashLine(line)
+		data = append(append(data, line...), '\n')
+	}
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("failed writing anchor file: %v", err)
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("failed syncing anchor file: %v", err)
+	}
+	a.prevHash = prevHash
+	return nil
+}
+
+// FindAnchor returns the last anchor of a session, the chain of the file is verified up to the end
+func FindAnchor(path, sid string) (*Anchor, error) {
+	var found *Anchor
+	_, err := scanAnchors(path, func(anchor Anchor) {
+		if anchor.SessionID == sid {
+			found = &anchor
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, ErrAnchorNotFound
+	}
+	return found, nil
+}
+
+// scanAnchors reads all the anchors of the file verifying the chain of lines,
+// it returns the hash of the last line
+func scanAnchors(path string, fn func(Anchor)) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return hashLine(nil), err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxAnchorLineSize)
+	prevHash, lineNo := hashLine(nil), 0
+	for scanner.Scan() {
+		lineNo++
+		var anchor Anchor
+		if err := json.Unmarshal(scanner.Bytes(), &anchor); err != nil {
+			return "", fmt.Errorf("failed decoding anchor at line %v: %v", lineNo, err)
+		}
+		if anchor.PrevHash != prevHash {
+			return "", fmt.Errorf("%w at line %v", ErrAnchorChainBroken, lineNo)
+		}
+		if fn != nil {
+			fn(anchor)
+		}
+		prevHash = hashLine(scanner.Bytes())
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed reading anchor file: %v", err)
+	}
+	return prevHash, nil
+}
+
+func hashLine(line []byte) string {
+	h := sha256.Sum256(line)
+	return hex.EncodeToString(h[:])
+}
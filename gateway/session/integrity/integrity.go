@@ -0,0 +1,283 @@
+package integrity
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hoophq/hoop/gateway/models"
+)
+
+const (
+	Version   = "v1"
+	Algorithm = "sha256-chain+ed25519"
+
+	// StatusValid indicates the event stream matches its seal
+	StatusValid = "valid"
+	// StatusTampered indicates the event stream or its seal were changed after the session was sealed
+	StatusTampered = "tampered"
+	// StatusUnsealed indicates the session was not sealed by the gateway
+	StatusUnsealed = "unsealed"
+	// StatusPurged indicates the event stream was changed by a retention policy after the session was sealed
+	StatusPurged = "purged"
+
+	// PurgeModeOutput is the mode of the retention policies that remove only the output of event streams
+	PurgeModeOutput = "output"
+)
+
+// Result is the outcome of the verification of a session
+type Result struct {
+	Status string
+	Reason string
+	// Events and Digest are computed from the stored event stream
+	Events            int
+	Digest            string
+	Seal              *models.SessionIntegrity
+	SignatureVerified bool
+	Anchor            *Anchor
+}
+
+// Seed is the value that starts the chain of events of a session,
+// it's shared by the write ahead log and the persisted event stream.
+func Seed(orgID, sid string) []byte {
+	return []byte(fmt.Sprintf("hoop:%s:%s:%s", Version, orgID, sid))
+}
+
+// KeyID identifies the public key that signed a seal
+func KeyID(pub ed25519.PublicKey) string {
+	h := sha256.Sum256(pub)
+	return hex.EncodeToString(h[:8])
+}
+
+// Digest chains the events of a stream in the format [[<event-time>, <event-type>, <base64-content>], ...]
+// and returns the last hash of the chain with the number of events. Each event is hashed in a canonical
+// form to keep the digest stable when the stream is normalized by the store (e.g.: postgres jsonb).
+func Digest(orgID, sid string, blobStream json.RawMessage) (string, int, error) {
+	var events []json.RawMessage
+	if len(blobStream) > 0 {
+		if err := json.Unmarshal(blobStream, &events); err != nil {
+			return "", 0, fmt.Errorf("failed decoding event stream: %v", err)
+		}
+	}
+	h := sha256.Sum256(Seed(orgID, sid))
+	for i, raw := range events {
+		canonical, err := canonicalEvent(raw)
+		if err != nil {
+			return "", 0, fmt.Errorf("failed decoding event %v: %v", i, err)
+		}
+		h = sha256.Sum256(append(h[:], canonical...))
+	}
+	return hex.EncodeToString(h[:]), len(events), nil
+}
+
+func canonicalEvent(raw json.RawMessage) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var event []any
+	if err := dec.Decode(&event); err != nil {
+		return nil, err
+	}
+	if len(event) < 3 {
+		return nil, fmt.Errorf("expected 3 attributes, got=%v", len(event))
+	}
+	eventTimeNumber, ok := event[0].(json.Number)
+	if !ok {
+		return nil, fmt.Errorf("event time is not a number, got=%T", event[0])
+	}
+	eventTime, err := strconv.ParseFloat(string(eventTimeNumber), 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing event time: %v", err)
+	}
+	eventType, _ := event[1].(string)
+	eventData, _ := event[2].(string)
+	canonical := strconv.AppendFloat(nil, eventTime, 'g', -1, 64)
+	canonical = append(canonical, 0x00)
+	canonical = append(canonical, eventType...)
+	canonical = append(canonical, 0x00)
+	return append(canonical, eventData...), nil
+}
+
+// Seal computes the digest of the event stream of a session, it's signed when the key is set
+func Seal(orgID, sid string, blobStream json.RawMessage, walVerified bool, key ed25519.PrivateKey, sealedAt time.Time) (*models.SessionIntegrity, error) {
+	digest, events, err := Digest(orgID, sid, blobStream)
+	if err != nil {
+		return nil, err
+	}
+	seal := &models.SessionIntegrity{
+		Version:     Version,
+		Algorithm:   Algorithm,
+		Events:      events,
+		Digest:      digest,
+		WalVerified: walVerified,
+		SealedAt:    sealedAt.UTC(),
+	}
+	sign(orgID, sid, seal, key)
+	return seal, nil
+}
+
+// SealPurge seals the event stream left by a retention policy with a record of the purge. The previous stream
+// is checked against the seal before it's replaced, the record keeps the first seal to verify the anchor of the session.
+// It returns nil when the session was not sealed.
+func SealPurge(orgID, sid string, seal *models.SessionIntegrity, mode string, prevBlobStream, blobStream json.RawMessage,
+	key ed25519.PrivateKey, purgedAt time.Time) (*models.SessionIntegrity, error) {
+	if seal == nil {
+		return nil, nil
+	}
+	digest, events, err := Digest(orgID, sid, prevBlobStream)
+	streamVerified := err == nil && digest == seal.Digest && events == seal.Events
+	if key != nil {
+		streamVerified = streamVerified && verifySignature(orgID, sid, seal, key) == nil
+	}
+	purge := &models.SessionIntegrityPurge{
+		Mode:           mode,
+		PurgedAt:       purgedAt.UTC(),
+		StreamVerified: streamVerified,
+		Events:         seal.Events,
+		Digest:         seal.Digest,
+		Signature:      seal.Signature,
+	}
+	if prev := seal.Purge; prev != nil {
+		purge.StreamVerified = streamVerified && prev.StreamVerified
+		purge.Events, purge.Digest, purge.Signature = prev.Events, prev.Digest, prev.Signature
+	}
+	digest, events, err = Digest(orgID, sid, blobStream)
+	if err != nil {
+		return nil, err
+	}
+	newSeal := &models.SessionIntegrity{
+		Version:     Version,
+		Algorithm:   Algorithm,
+		Events:      events,
+		Digest:      digest,
+		WalVerified: seal.WalVerified,
+		SealedAt:    seal.SealedAt,
+		Purge:       purge,
+	}
+	sign(orgID, sid, newSeal, key)
+	return newSeal, nil
+}
+
+func sign(orgID, sid string, seal *models.SessionIntegrity, key ed25519.PrivateKey) {
+	if key == nil {
+		return
+	}
+	seal.KeyID = KeyID(key.Public().(ed25519.PublicKey))
+	seal.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, signedMessage(orgID, sid, seal)))
+}
+
+func signedMessage(orgID, sid string, seal *models.SessionIntegrity) []byte {
+	msg := fmt.Sprintf("%s\n%s\n%s\n%s\n%v\n%s\n%v\n%v",
+		seal.Version, seal.Algorithm, orgID, sid, seal.Events, seal.Digest, seal.WalVerified, seal.SealedAt.UnixNano())
+	if p := seal.Purge; p != nil {
+		msg += fmt.Sprintf("\n%s\n%v\n%v\n%v\n%s\n%s",
+			p.Mode, p.PurgedAt.UnixNano(), p.StreamVerified, p.Events, p.Digest, p.Signature)
+	}
+	return []byte(msg)
+}
+
+// verifySignature verifies the seal with the public key of the gateway,
+// seals without a signature or signed by other keys are not accepted.
+func verifySignature(orgID, sid string, seal *models.SessionIntegrity, key ed25519.PrivateKey) error {
+	pub := key.Public().(ed25519.PublicKey)
+	if seal.Signature == "" {
+		return fmt.Errorf("the seal is not signed")
+	}
+	if seal.KeyID != KeyID(pub) {
+		return fmt.Errorf("the seal was signed by an unknown key %v", seal.KeyID)
+	}
+	sig, err := base64.StdEncoding.DecodeString(seal.Signature)
+	if err != nil || !ed25519.Verify(pub, signedMessage(orgID, sid, seal), sig) {
+		return fmt.Errorf("the signature of the seal is not valid")
+	}
+	return nil
+}
+
+// Verify checks the event stream of a session against its seal. When the key is set, the seal must be signed by it.
+// When the anchor file is set, the seal is compared with the anchor of the session.
+func Verify(sess *models.Session, key ed25519.PrivateKey, anchorFile string) *Result {
+	res := &Result{Seal: sess.Integrity}
+	seal := sess.Integrity
+	if anchorFile != "" {
+		anchor, err := FindAnchor(anchorFile, sess.ID)
+		switch {
+		case errors.Is(err, ErrAnchorNotFound):
+			// the session could be waiting to be anchored
+			if sess.IntegrityAnchoredAt != nil {
+				res.Status, res.Reason = StatusTampered, "the session was anchored, but its anchor was not found"
+				return res
+			}
+		case err != nil:
+			res.Status, res.Reason = StatusTampered, fmt.Sprintf("unable to find the anchor of the session: %v", err)
+			return res
+		default:
+			res.Anchor = anchor
+		}
+	}
+	if seal == nil {
+		switch {
+		case res.Anchor != nil:
+			res.Status, res.Reason = StatusTampered, "the session was anchored, but its seal was removed"
+		case key != nil:
+			res.Status, res.Reason = StatusTampered, "the session is not sealed, but the gateway signs all sessions"
+		default:
+			res.Status, res.Reason = StatusUnsealed, "the session was not sealed by the gateway"
+		}
+		return res
+	}
+	if anchor := res.Anchor; anchor != nil {
+		events, digest, signature := seal.Events, seal.Digest, seal.Signature
+		if seal.Purge != nil {
+			events, digest, signature = seal.Purge.Events, seal.Purge.Digest, seal.Purge.Signature
+		}
+		if anchor.OrgID != sess.OrgID || anchor.Digest != digest || anchor.Events != events || anchor.Signature != signature {
+			res.Status, res.Reason = StatusTampered, "the seal of the session does not match the anchored digest"
+			return res
+		}
+	}
+	if key != nil {
+		if err := verifySignature(sess.OrgID, sess.ID, seal, key); err != nil {
+			res.Status, res.Reason = StatusTampered, err.Error()
+			return res
+		}
+		res.SignatureVerified = true
+	}
+
+	digest, events, err := Digest(sess.OrgID, sess.ID, sess.BlobStream)
+	if err != nil {
+		res.Status, res.Reason = StatusTampered, fmt.Sprintf("the event stream is not valid: %v", err)
+		return res
+	}
+	res.Digest, res.Events = digest, events
+	switch {
+	case events < seal.Events:
+		res.Status, res.Reason = StatusTampered, fmt.Sprintf("the event stream is truncated, found %v of %v sealed events", events, seal.Events)
+	case events > seal.Events:
+		res.Status, res.Reason = StatusTampered, fmt.Sprintf("the event stream has %v events, but only %v were sealed", events, seal.Events)
+	case digest != seal.Digest:
+		res.Status, res.Reason = StatusTampered, "the event stream was modified after it was sealed"
+	case !seal.WalVerified:
+		res.Status, res.Reason = StatusTampered, "the event stream was modified in the gateway before it was sealed"
+	case seal.Purge != nil && !seal.Purge.StreamVerified:
+		res.Status, res.Reason = StatusTampered, "the event stream was modified before it was purged by a retention policy"
+	case seal.Purge != nil && seal.Purge.Mode == PurgeModeOutput:
+		res.Status, res.Reason = StatusPurged, fmt.Sprintf("the output of the event stream was purged by a retention policy at %v",
+			seal.Purge.PurgedAt.Format(time.RFC3339))
+	case seal.Purge != nil:
+		res.Status, res.Reason = StatusPurged, fmt.Sprintf("the event stream was purged by a retention policy at %v",
+			seal.Purge.PurgedAt.Format(time.RFC3339))
+	case seal.Signature == "":
+		res.Status, res.Reason = StatusValid, "the event stream matches its digest, the digest is not signed"
+	case !res.SignatureVerified:
+		res.Status, res.Reason = StatusValid, fmt.Sprintf("the event stream matches its digest, the signature of key %v was not verified", seal.KeyID)
+	default:
+		res.Status, res.Reason = StatusValid, "the event stream matches its signed digest"
+	}
+	return res
+}
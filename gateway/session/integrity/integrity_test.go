@@ -0,0 +1,330 @@
+package integrity
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hoophq/hoop/gateway/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testOrgID     = "ee2d5e9f-5d71-4cd2-93d5-b6a3fb6e2d6f"
+	testSessionID = "2ae1c1b9-f6f1-44b1-8e2c-8b6fdf0a35a2"
+	// the stream as written by the gateway
+	testBlobStream = `[[1.23e-04, "i", "U0VMRUNUIDE="],[0.5, "o", "MQ=="],[1.25, "r", "MjQsODAsMCww"]]`
+)
+
+func newTestKey(seed byte) ed25519.PrivateKey {
+	return ed25519.NewKeyFromSeed(bytes.Repeat([]byte{seed}, ed25519.SeedSize))
+}
+
+func newTestSession(t *testing.T, key ed25519.PrivateKey, walVerified bool) *models.Session {
+	seal, err := Seal(testOrgID, testSessionID, json.RawMessage(testBlobStream), walVerified, key,
+		time.Date(2024, time.June, 10, 10, 19, 15, 23, time.UTC))
+	if err != nil {
+		t.Fatalf("failed sealing session, reason=%v", err)
+	}
+	return &models.Session{ID: testSessionID, OrgID: testOrgID, BlobStream: json.RawMessage(testBlobStream), Integrity: seal}
+}
+
+func newTestPurgedSession(t *testing.T, key ed25519.PrivateKey, mode, blobStream string, purgedAt time.Time) *models.Session {
+	sess := newTestSession(t, key, true)
+	var newBlobStream json.RawMessage
+	if blobStream != "" {
+		newBlobStream = json.RawMessage(blobStream)
+	}
+	seal, err := SealPurge(testOrgID, testSessionID, sess.Integrity, mode, sess.BlobStream, newBlobStream, key, purgedAt)
+	if err != nil {
+		t.Fatalf("failed sealing purged session, reason=%v", err)
+	}
+	sess.BlobStream, sess.Integrity = newBlobStream, seal
+	return sess
+}
+
+func TestDigest(t *testing.T) {
+	digest, events, err := Digest(testOrgID, testSessionID, json.RawMessage(testBlobStream))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, events)
+
+	// postgres jsonb normalizes the spaces and the format of numbers
+	normalizedDigest, _, err := Digest(testOrgID, testSessionID,
+		json.RawMessage(`[[0.000123,"i","U0VMRUNUIDE="],[0.50,"o","MQ=="],[1.25,"r","MjQsODAsMCww"]]`))
+	assert.NoError(t, err)
+	assert.Equal(t, digest, normalizedDigest)
+
+	otherSessionDigest, _, err := Digest(testOrgID, "other-session", json.RawMessage(testBlobStream))
+	assert.NoError(t, err)
+	assert.NotEqual(t, digest, otherSessionDigest)
+
+	_, _, err = Digest(testOrgID, testSessionID, json.RawMessage(`[["0.1","i","MQ=="]]`))
+	assert.EqualError(t, err, "failed decoding event 0: event time is not a number, got=string")
+
+	emptyDigest, events, err := Digest(testOrgID, testSessionID, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, events)
+	assert.NotEmpty(t, emptyDigest)
+}
+
+func TestVerify(t *testing.T) {
+	key := newTestKey(1)
+	purgedAt := time.Date(2024, time.July, 10, 10, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		msg        string
+		sessFn     func() *models.Session
+		key        ed25519.PrivateKey
+		wantStatus string
+		wantReason string
+	}{
+		{
+			msg:        "it must be valid with a signed digest",
+			sessFn:     func() *models.Session { return newTestSession(t, key, true) },
+			key:        key,
+			wantStatus: StatusValid,
+			wantReason: "the event stream matches its signed digest",
+		},
+		{
+			msg:        "it must be valid with an unsigned digest when the signing key is not set",
+			sessFn:     func() *models.Session { return newTestSession(t, nil, true) },
+			key:        nil,
+			wantStatus: StatusValid,
+			wantReason: "the event stream matches its digest, the digest is not signed",
+		},
+		{
+			msg:        "it must be valid without verifying the signature when the signing key is not set",
+			sessFn:     func() *models.Session { return newTestSession(t, key, true) },
+			key:        nil,
+			wantStatus: StatusValid,
+			wantReason: "the event stream matches its digest, the signature of key " + KeyID(key.Public().(ed25519.PublicKey)) + " was not verified",
+		},
+		{
+			msg:        "it must be tampered with an unsigned digest when the signing key is set",
+			sessFn:     func() *models.Session { return newTestSession(t, nil, true) },
+			key:        key,
+			wantStatus: StatusTampered,
+			wantReason: "the seal is not signed",
+		},
+		{
+			msg:        "it must be tampered when the seal was signed by an unknown key",
+			sessFn:     func() *models.Session { return newTestSession(t, key, true) },
+			key:        newTestKey(2),
+			wantStatus: StatusTampered,
+			wantReason: "the seal was signed by an unknown key " + KeyID(key.Public().(ed25519.PublicKey)),
+		},
+		{
+			msg: "it must be tampered when the digest is recomputed with a bogus key id",
+			sessFn: func() *models.Session {
+				sess := newTestSession(t, nil, true)
+				sess.BlobStream = json.RawMessage(`[[0.000123,"i","U0VMRUNUIDI="]]`)
+				sess.Integrity.Digest, sess.Integrity.Events, _ = Digest(testOrgID, testSessionID, sess.BlobStream)
+				sess.Integrity.KeyID, sess.Integrity.Signature = "bogus", "bogus"
+				return sess
+			},
+			key:        key,
+			wantStatus: StatusTampered,
+			wantReason: "the seal was signed by an unknown key bogus",
+		},
+		{
+			msg:        "it must be unsealed when the session has no seal and the signing key is not set",
+			sessFn:     func() *models.Session { return &models.Session{ID: testSessionID, OrgID: testOrgID} },
+			key:        nil,
+			wantStatus: StatusUnsealed,
+			wantReason: "the session was not sealed by the gateway",
+		},
+		{
+			msg:        "it must be tampered when the session has no seal and the signing key is set",
+			sessFn:     func() *models.Session { return &models.Session{ID: testSessionID, OrgID: testOrgID} },
+			key:        key,
+			wantStatus: StatusTampered,
+			wantReason: "the session is not sealed, but the gateway signs all sessions",
+		},
+		{
+			msg: "it must be tampered when the stream is truncated",
+			sessFn: func() *models.Session {
+				sess := newTestSession(t, key, true)
+				sess.BlobStream = json.RawMessage(`[[0.000123,"i","U0VMRUNUIDE="]]`)
+				return sess
+			},
+			key:        key,
+			wantStatus: StatusTampered,
+			wantReason: "the event stream is truncated, found 1 of 3 sealed events",
+		},
+		{
+			msg: "it must be tampered when the stream has more events",
+			sessFn: func() *models.Session {
+				sess := newTestSession(t, key, true)
+				sess.BlobStream = json.RawMessage(`[[0.000123,"i","U0VMRUNUIDE="],[0.5,"o","MQ=="],[1.25,"r","MjQsODAsMCww"],[2,"i","MQ=="]]`)
+				return sess
+			},
+			key:        key,
+			wantStatus: StatusTampered,
+			wantReason: "the event stream has 4 events, but only 3 were sealed",
+		},
+		{
+			msg: "it must be tampered when an event is modified",
+			sessFn: func() *models.Session {
+				sess := newTestSession(t, key, true)
+				sess.BlobStream = json.RawMessage(`[[0.000123,"i","U0VMRUNUIDI="],[0.5,"o","MQ=="],[1.25,"r","MjQsODAsMCww"]]`)
+				return sess
+			},
+			key:        key,
+			wantStatus: StatusTampered,
+			wantReason: "the event stream was modified after it was sealed",
+		},
+		{
+			msg: "it must be tampered when the digest is recomputed without the signing key",
+			sessFn: func() *models.Session {
+				sess := newTestSession(t, key, true)
+				sess.BlobStream = json.RawMessage(`[[0.000123,"i","U0VMRUNUIDI="]]`)
+				sess.Integrity.Digest, sess.Integrity.Events, _ = Digest(testOrgID, testSessionID, sess.BlobStream)
+				return sess
+			},
+			key:        key,
+			wantStatus: StatusTampered,
+			wantReason: "the signature of the seal is not valid",
+		},
+		{
+			msg:        "it must be tampered when the write ahead log chain was broken",
+			sessFn:     func() *models.Session { return newTestSession(t, key, false) },
+			key:        key,
+			wantStatus: StatusTampered,
+			wantReason: "the event stream was modified in the gateway before it was sealed",
+		},
+		{
+			msg: "it must be purged when the output was purged by a retention policy",
+			sessFn: func() *models.Session {
+				return newTestPurgedSession(t, key, "output", `[[0.000123,"i","U0VMRUNUIDE="],[1.25,"r","MjQsODAsMCww"]]`, purgedAt)
+			},
+			key:        key,
+			wantStatus: StatusPurged,
+			wantReason: "the output of the event stream was purged by a retention policy at 2024-07-10T10:00:00Z",
+		},
+		{
+			msg:        "it must be purged when the stream was purged by a retention policy",
+			sessFn:     func() *models.Session { return newTestPurgedSession(t, key, "event_stream", "", purgedAt) },
+			key:        key,
+			wantStatus: StatusPurged,
+			wantReason: "the event stream was purged by a retention policy at 2024-07-10T10:00:00Z",
+		},
+		{
+			msg: "it must be tampered when the stream was modified before it was purged",
+			sessFn: func() *models.Session {
+				sess := newTestSession(t, key, true)
+				prevBlobStream := json.RawMessage(`[[0.000123,"i","U0VMRUNUIDI="]]`)
+				seal, err := SealPurge(testOrgID, testSessionID, sess.Integrity, "event_stream", prevBlobStream, nil, key, purgedAt)
+				assert.NoError(t, err)
+				sess.BlobStream, sess.Integrity = nil, seal
+				return sess
+			},
+			key:        key,
+			wantStatus: StatusTampered,
+			wantReason: "the event stream was modified before it was purged by a retention policy",
+		},
+		{
+			msg: "it must be tampered when the stream is removed without a purge record",
+			sessFn: func() *models.Session {
+				sess := newTestSession(t, key, true)
+				sess.BlobStream = nil
+				sess.StreamPurgedAt = &purgedAt
+				return sess
+			},
+			key:        key,
+			wantStatus: StatusTampered,
+			wantReason: "the event stream is truncated, found 0 of 3 sealed events",
+		},
+		{
+			msg: "it must be tampered when the purge record is changed",
+			sessFn: func() *models.Session {
+				sess := newTestPurgedSession(t, key, "event_stream", "", purgedAt)
+				sess.Integrity.Purge.PurgedAt = purgedAt.Add(-time.Hour)
+				return sess
+			},
+			key:        key,
+			wantStatus: StatusTampered,
+			wantReason: "the signature of the seal is not valid",
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			res := Verify(tt.sessFn(), tt.key, "")
+			assert.Equal(t, tt.wantStatus, res.Status)
+			assert.Equal(t, tt.wantReason, res.Reason)
+		})
+	}
+}
+
+func TestAnchorFile(t *testing.T) {
+	anchorPath := filepath.Join(t.TempDir(), "anchors.jsonl")
+	key := newTestKey(1)
+	sess := newTestSession(t, key, true)
+	anchoredAt := time.Date(2024, time.June, 10, 11, 0, 0, 0, time.UTC)
+	newAnchor := func(sid, digest string) Anchor {
+		return Anchor{OrgID: testOrgID, SessionID: sid, Events: 3, Digest: digest, AnchoredAt: anchoredAt}
+	}
+
+	af := &anchorFile{path: anchorPath}
+	err := af.append([]Anchor{newAnchor("sid1", "digest1"), newAnchor("sid2", "digest2")})
+	assert.NoError(t, err)
+
+	// a new process must continue the chain of the existing file
+	af = &anchorFile{path: anchorPath}
+	anchor := newAnchor(sess.ID, sess.Integrity.Digest)
+	anchor.Signature = sess.Integrity.Signature
+	err = af.append([]Anchor{anchor})
+	assert.NoError(t, err)
+
+	got, err := FindAnchor(anchorPath, "sid2")
+	assert.NoError(t, err)
+	assert.Equal(t, "digest2", got.Digest)
+
+	_, err = FindAnchor(anchorPath, "sid3")
+	assert.Equal(t, ErrAnchorNotFound, err)
+
+	sess.IntegrityAnchoredAt = &anchoredAt
+	res := Verify(sess, key, anchorPath)
+	assert.Equal(t, StatusValid, res.Status)
+	if assert.NotNil(t, res.Anchor) {
+		assert.Equal(t, sess.Integrity.Digest, res.Anchor.Digest)
+	}
+
+	// the first seal is compared with the anchor after a purge
+	purgedSess := newTestPurgedSession(t, key, "event_stream", "", anchoredAt)
+	purgedSess.IntegrityAnchoredAt = &anchoredAt
+	res = Verify(purgedSess, key, anchorPath)
+	assert.Equal(t, StatusPurged, res.Status)
+
+	// the seal must match the anchored digest, even if the anchored column was removed
+	tamperedSess := newTestSession(t, nil, true)
+	res = Verify(tamperedSess, nil, anchorPath)
+	assert.Equal(t, StatusTampered, res.Status)
+	assert.Equal(t, "the seal of the session does not match the anchored digest", res.Reason)
+
+	tamperedSess.Integrity = nil
+	res = Verify(tamperedSess, nil, anchorPath)
+	assert.Equal(t, StatusTampered, res.Status)
+	assert.Equal(t, "the session was anchored, but its seal was removed", res.Reason)
+
+	// the session is waiting to be anchored
+	res = Verify(&models.Session{ID: "sid3", OrgID: testOrgID}, nil, anchorPath)
+	assert.Equal(t, StatusUnsealed, res.Status)
+	anchoredSess := newTestSession(t, nil, true)
+	anchoredSess.ID = "sid3"
+	anchoredSess.IntegrityAnchoredAt = &anchoredAt
+	res = Verify(anchoredSess, nil, anchorPath)
+	assert.Equal(t, StatusTampered, res.Status)
+	assert.Equal(t, "the session was anchored, but its anchor was not found", res.Reason)
+
+	// changing a line breaks the chain of the next lines
+	data, err := os.ReadFile(anchorPath)
+	assert.NoError(t, err)
+	data = bytes.Replace(data, []byte(`"digest1"`), []byte(`"digest3"`), 1)
+	assert.NoError(t, os.WriteFile(anchorPath, data, 0600))
+	_, err = FindAnchor(anchorPath, "sid1")
+	assert.True(t, errors.Is(err, ErrAnchorChainBroken))
+	assert.EqualError(t, err, "anchor file chain is broken at line 2")
+}
@@ -0,0 +1,182 @@
+package statement
+
+import (
+	"encoding/binary"
+	"strconv"
+	"time"
+)
+
+const (
+	mysqlComQuery           = 0x03
+	mysqlOKPacket           = 0x00
+	mysqlErrPacket          = 0xff
+	mysqlEOFPacket          = 0xfe
+	mysqlLocalInfilePacket  = 0xfb
+	mysqlMoreResultsExists  = 0x0008
+	mysqlMaxEOFPacketLength = 9
+)
+
+type mysqlResponseState int
+
+const (
+	mysqlStateResponse mysqlResponseState = iota
+	mysqlStateColumns
+	mysqlStateColumnsEnd
+	mysqlStateRows
+)
+
+// mysqlConn tracks the text protocol of mysql (COM_QUERY), the client sends
+// a command and waits for the response of the server before sending the next one.
+//
+// https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_com_query.html
+type mysqlConn struct {
+	client *frameReader
+	server *frameReader
+
+	pending *Record
+	state   mysqlResponseState
+	columns uint64
+	rows    int64
+}
+
+func newMySQLConn() *mysqlConn {
+	return &mysqlConn{
+		client: &frameReader{headerSize: 4, bodySize: mysqlBodySize, maxBody: maxStatementSize},
+		server: &frameReader{headerSize: 4, bodySize: mysqlBodySize, maxBody: maxServerMessageSize},
+	}
+}
+
+func mysqlBodySize(header []byte) int {
+	return int(uint32(header[0]) | uint32(header[1])<<8 | uint32(header[2])<<16)
+}
+
+func (c *mysqlConn) clientWrite(data []byte, now time.Time) error {
+	return c.client.feed(data, func(header, body []byte) error {
+		// commands start a new sequence, it distinguishes them from the handshake packets
+		if header[3] == 0 && len(body) > 0 && body[0] == mysqlComQuery {
+			c.pending = newRecord(string(body[1:]), now)
+			c.state, c.rows = mysqlStateResponse, 0
+		}
+		return nil
+	})
+}
+
+func (c *mysqlConn) serverWrite(data []byte, now time.Time) ([]*Record, error) {
+	var records []*Record
+	err := c.server.feed(data, func(_, body []byte) error {
+		if c.pending == nil || len(body) == 0 {
+			return nil
+		}
+		if done := c.processServerPacket(body); done {
+			c.pending.EndTime = now
+			records = append(records, c.pending)
+			c.pending = nil
+		}
+		return nil
+	})
+	return records, err
+}
+
+// processServerPacket handles a packet of the response, it returns true when the response is complete
+func (c *mysqlConn) processServerPacket(body []byte) bool {
+	rec := c.pending
+	if body[0] == mysqlErrPacket {
+		rec.ErrorCode, rec.ErrorMessage = parseMySQLError(body)
+		return true
+	}
+	switch c.state {
+	case mysqlStateResponse:
+		switch body[0] {
+		case mysqlOKPacket:
+			affectedRows, n := readLengthEncodedInt(body[1:])
+			_, m := readLengthEncodedInt(body[1+n:]) // last insert id
+			rec.Rows = max(rec.Rows, 0) + int64(affectedRows)
+			return !hasMoreResults(body[1+n+m:])
+		case mysqlLocalInfilePacket:
+			// the client sends the file and the server answers with an ok packet
+			return false
+		}
+		c.columns, _ = readLengthEncodedInt(body)
+		c.state = mysqlStateColumns
+	case mysqlStateColumns:
+		c.columns--
+		if c.columns == 0 {
+			c.state = mysqlStateColumnsEnd
+		}
+	case mysqlStateColumnsEnd:
+		c.state = mysqlStateRows
+		// the eof packet after the columns is not sent when CLIENT_DEPRECATE_EOF is set
+		if body[0] == mysqlEOFPacket && len(body) < mysqlMaxEOFPacketLength {
+			return false
+		}
+		return c.processServerPacket(body)
+	case mysqlStateRows:
+		if body[0] != mysqlEOFPacket {
+			c.rows++
+			return false
+		}
+		rec.Rows = max(rec.Rows, 0) + c.rows
+		c.rows = 0
+		c.state = mysqlStateResponse
+		var status []byte
+		if len(body) < mysqlMaxEOFPacketLength {
+			// eof packet: header, warnings and status flags
+			if len(body) >= 5 {
+				status = body[3:5]
+			}
+		} else {
+			// ok packet with the eof header
+			_, n := readLengthEncodedInt(body[1:])
+			_, m := readLengthEncodedInt(body[1+n:])
+			status = body[1+n+m:]
+		}
+		return !hasMoreResults(status)
+	}
+	return false
+}
+
+func hasMoreResults(status []byte) bool {
+	if len(status) < 2 {
+		return false
+	}
+	return binary.LittleEndian.Uint16(status)&mysqlMoreResultsExists != 0
+}
+
+// parseMySQLError returns the code and the message of an error packet
+func parseMySQLError(body []byte) (code, message string) {
+	if len(body) < 3 {
+		return "", ""
+	}
+	code = strconv.Itoa(int(binary.LittleEndian.Uint16(body[1:3])))
+	msg := body[3:]
+	// sql state marker and the sql state
+	if len(msg) >= 6 && msg[0] == '#' {
+		msg = msg[6:]
+	}
+	return code, string(msg)
+}
+
+// readLengthEncodedInt returns a length encoded integer and the number of bytes read
+func readLengthEncodedInt(data []byte) (uint64, int) {
+	if len(data) == 0 {
+		return 0, 0
+	}
+	switch data[0] {
+	case 0xfc:
+		if len(data) < 3 {
+			return 0, len(data)
+		}
+		return uint64(binary.LittleEndian.Uint16(data[1:3])), 3
+	case 0xfd:
+		if len(data) < 4 {
+			return 0, len(data)
+		}
+		return uint64(data[1]) | uint64(data[2])<<8 | uint64(data[3])<<16, 4
+	case 0xfe:
+		if len(data) < 9 {
+			return 0, len(data)
+		}
+		return binary.LittleEndian.Uint64(data[1:9]), 9
+	}
+	return uint64(data[0]), 1
+}
@@ -0,0 +1,234 @@
+package statement
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strconv"
+	"time"
+
+	"github.com/hoophq/hoop/common/pgtypes"
+)
+
+const (
+	pgProtocolVersion3    uint32 = 196608
+	pgSSLRequestCode      uint32 = 80877103
+	pgGSSENCRequestCode   uint32 = 80877104
+	pgCancelRequestCode          = pgtypes.ClientCancelRequestMessage
+	pgServerErrorMessage         = 'E'
+	pgServerCommandDone          = 'C'
+	pgServerDataRow              = 'D'
+	pgServerEmptyQuery           = 'I'
+	pgServerSuspended            = 's'
+	pgServerReadyForQuery        = 'Z'
+)
+
+// pgStatement is a statement waiting for the response of the server
+type pgStatement struct {
+	record   *Record
+	extended bool
+	dataRows int64
+}
+
+// postgresConn tracks the simple and extended query protocols of postgres.
+// The statements are queued until the server completes them, the extended
+// protocol allows the client to send many statements before a sync message.
+//
+// https://www.postgresql.org/docs/current/protocol-flow.html
+type postgresConn struct {
+	client *frameReader
+	server *frameReader
+	// the first messages of the client don't have a type
+	startup bool
+
+	prepared map[string]string
+	portals  map[string]string
+	pending  []*pgStatement
+}
+
+func newPostgresConn() *postgresConn {
+	c := &postgresConn{
+		startup:  true,
+		prepared: map[string]string{},
+		portals:  map[string]string{},
+	}
+	c.client = &frameReader{headerSize: 4, bodySize: pgStartupBodySize, maxBody: maxStatementSize}
+	c.server = &frameReader{headerSize: 5, bodySize: pgTypedBodySize, maxBody: maxServerMessageSize}
+	return c
+}
+
+func pgStartupBodySize(header []byte) int { return int(binary.BigEndian.Uint32(header)) - 4 }
+func pgTypedBodySize(header []byte) int   { return int(binary.BigEndian.Uint32(header[1:])) - 4 }
+
+func (c *postgresConn) clientWrite(data []byte, now time.Time) error {
+	return c.client.feed(data, func(header, body []byte) error {
+		if c.startup {
+			return c.processStartupMessage(body)
+		}
+		c.processClientMessage(header[0], body, now)
+		return nil
+	})
+}
+
+func (c *postgresConn) processStartupMessage(body []byte) error {
+	if len(body) < 4 {
+		return errInvalidMessage
+	}
+	switch binary.BigEndian.Uint32(body[:4]) {
+	case pgSSLRequestCode, pgGSSENCRequestCode:
+		// the server answers with a single byte
+		c.server.skip++
+	case pgCancelRequestCode:
+	case pgProtocolVersion3:
+		c.startup = false
+		c.client.headerSize, c.client.bodySize = 5, pgTypedBodySize
+	default:
+		return errInvalidMessage
+	}
+	return nil
+}
+
+func (c *postgresConn) processClientMessage(typ byte, body []byte, now time.Time) {
+	switch pgtypes.PacketType(typ) {
+	case pgtypes.ClientSimpleQuery:
+		c.pending = append(c.pending, &pgStatement{record: newRecord(string(cstring(body)), now)})
+	case pgtypes.ClientParse:
+		name, rest := nextCString(body)
+		c.prepared[string(name)] = string(cstring(rest))
+	case pgtypes.ClientBind:
+		portal, rest := nextCString(body)
+		stmtName, _ := nextCString(rest)
+		c.portals[string(portal)] = c.prepared[string(stmtName)]
+	case pgtypes.ClientExecute:
+		portal, _ := nextCString(body)
+		c.pending = append(c.pending, &pgStatement{
+			record:   newRecord(c.portals[string(portal)], now),
+			extended: true,
+		})
+	case pgtypes.ClientClose:
+		if len(body) == 0 {
+			return
+		}
+		name := string(cstring(body[1:]))
+		if body[0] == 'S' {
+			delete(c.prepared, name)
+		} else {
+			delete(c.portals, name)
+		}
+	}
+}
+
+func (c *postgresConn) serverWrite(data []byte, now time.Time) ([]*Record, error) {
+	var records []*Record
+	err := c.server.feed(data, func(header, body []byte) error {
+		if rec := c.processServerMessage(header[0], body, now); rec != nil {
+			records = append(records, rec)
+		}
+		return nil
+	})
+	return records, err
+}
+
+func (c *postgresConn) processServerMessage(typ byte, body []byte, now time.Time) *Record {
+	if typ == pgServerReadyForQuery {
+		// the statements of the extended protocol without a response are skipped by
+		// the server after an error, a simple query completes after all its commands.
+		var rec *Record
+		if len(c.pending) > 0 && !c.pending[0].extended {
+			rec = c.pending[0].complete(now)
+		}
+		c.pending = nil
+		return rec
+	}
+	if len(c.pending) == 0 {
+		return nil
+	}
+	stmt := c.pending[0]
+	switch typ {
+	case pgServerDataRow:
+		stmt.dataRows++
+	case pgServerCommandDone:
+		if rows, ok := parseCommandTagRows(cstring(body)); ok {
+			stmt.record.Rows = max(stmt.record.Rows, 0) + rows
+		}
+		stmt.dataRows = 0
+		if stmt.extended {
+			return c.dequeue(now)
+		}
+	case pgServerEmptyQuery, pgServerSuspended:
+		if stmt.extended {
+			return c.dequeue(now)
+		}
+	case pgServerErrorMessage:
+		stmt.record.ErrorCode, stmt.record.ErrorMessage = parseErrorFields(body)
+		if stmt.extended {
+			return c.dequeue(now)
+		}
+	}
+	return nil
+}
+
+func (c *postgresConn) dequeue(now time.Time) *Record {
+	stmt := c.pending[0]
+	c.pending = c.pending[1:]
+	return stmt.complete(now)
+}
+
+func (s *pgStatement) complete(now time.Time) *Record {
+	if s.record.Rows == -1 && s.dataRows > 0 {
+		s.record.Rows = s.dataRows
+	}
+	s.record.EndTime = now
+	return s.record
+}
+
+func newRecord(statement string, now time.Time) *Record {
+	return &Record{Statement: statement, StartTime: now, Rows: -1}
+}
+
+// parseCommandTagRows returns the rows of a command tag, e.g.: SELECT 5, INSERT 0 1, UPDATE 3.
+// Commands without rows have only the name of the command, e.g.: CREATE TABLE.
+func parseCommandTagRows(tag []byte) (int64, bool) {
+	fields := bytes.Fields(tag)
+	if len(fields) < 2 {
+		return 0, false
+	}
+	switch string(fields[0]) {
+	case "SELECT", "INSERT", "UPDATE", "DELETE", "MERGE", "MOVE", "FETCH", "COPY":
+		rows, err := strconv.ParseInt(string(fields[len(fields)-1]), 10, 64)
+		return rows, err == nil
+	}
+	return 0, false
+}
+
+// parseErrorFields returns the code and the message of an error response
+func parseErrorFields(body []byte) (code, message string) {
+	for len(body) > 0 && body[0] != 0x00 {
+		fieldType := body[0]
+		var val []byte
+		val, body = nextCString(body[1:])
+		switch fieldType {
+		case 'C':
+			code = string(val)
+		case 'M':
+			message = string(val)
+		}
+	}
+	return
+}
+
+// cstring returns the data up to the null terminator
+func cstring(data []byte) []byte {
+	if idx := bytes.IndexByte(data, 0x00); idx != -1 {
+		return data[:idx]
+	}
+	return data
+}
+
+// nextCString returns the null terminated string and the data after it
+func nextCString(data []byte) ([]byte, []byte) {
+	idx := bytes.IndexByte(data, 0x00)
+	if idx == -1 {
+		return data, nil
+	}
+	return data[:idx], data[idx+1:]
+}
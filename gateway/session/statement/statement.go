@@ -0,0 +1,189 @@
+// Package statement decodes the protocol messages of database connections
+// into per-statement records. A record contains the statement sent by the
+// client, when it started and ended, the rows returned or affected and the
+// error returned by the database server.
+package statement
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	pb "github.com/hoophq/hoop/common/proto"
+)
+
+const (
+	// maxStatementSize is the maximum size of a statement kept in a record,
+	// larger statements are truncated.
+	maxStatementSize = 64 * 1024
+	// maxServerMessageSize is the maximum size of a server message decoded,
+	// the body of data rows is not required to track the statements.
+	maxServerMessageSize = 4 * 1024
+)
+
+var errInvalidMessage = errors.New("invalid protocol message")
+
+// Record is a statement executed by a database connection
+type Record struct {
+	ConnectionID string
+	Statement    string
+	StartTime    time.Time
+	EndTime      time.Time
+	// Rows returned or affected by the statement, -1 when it's unknown
+	Rows         int64
+	ErrorCode    string
+	ErrorMessage string
+}
+
+// connTracker tracks the statements of a single client connection
+type connTracker interface {
+	clientWrite(data []byte, now time.Time) error
+	serverWrite(data []byte, now time.Time) ([]*Record, error)
+}
+
+// Tracker tracks the statements of all connections of a session
+type Tracker struct {
+	mu      sync.Mutex
+	newConn func() connTracker
+	conns   map[string]connTracker
+	// broken connections are not tracked anymore
+	broken  map[string]struct{}
+	nowFunc func() time.Time
+}
+
+// NewTracker returns a tracker for the connection type,
+// it returns nil if the protocol is not supported.
+func NewTracker(connType pb.ConnectionType) *Tracker {
+	var newConn func() connTracker
+	switch connType {
+	case pb.ConnectionTypePostgres:
+		newConn = func() connTracker { return newPostgresConn() }
+	case pb.ConnectionTypeMySQL:
+		newConn = func() connTracker { return newMySQLConn() }
+	default:
+		return nil
+	}
+	return &Tracker{
+		newConn: newConn,
+		conns:   map[string]connTracker{},
+		broken:  map[string]struct{}{},
+		nowFunc: func() time.Time { return time.Now().UTC() },
+	}
+}
+
+func (t *Tracker) conn(connectionID string) connTracker {
+	if _, ok := t.broken[connectionID]; ok {
+		return nil
+	}
+	c, ok := t.conns[connectionID]
+	if !ok {
+		c = t.newConn()
+		t.conns[connectionID] = c
+	}
+	return c
+}
+
+// ClientWrite decodes the data sent by the client of the connection.
+// A connection that sends data which could not be decoded isn't tracked anymore.
+func (t *Tracker) ClientWrite(connectionID string, data []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if c := t.conn(connectionID); c != nil {
+		if err := c.clientWrite(data, t.nowFunc()); err != nil {
+			t.markBroken(connectionID)
+		}
+	}
+}
+
+// ServerWrite decodes the data sent by the database server of the connection,
+// it returns the statements completed by it.
+func (t *Tracker) ServerWrite(connectionID string, data []byte) []*Record {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c := t.conn(connectionID)
+	if c == nil {
+		return nil
+	}
+	records, err := c.serverWrite(data, t.nowFunc())
+	if err != nil {
+		t.markBroken(connectionID)
+	}
+	for _, r := range records {
+		r.ConnectionID = connectionID
+	}
+	return records
+}
+
+// CloseConnection stops tracking the connection, statements
+// that didn't complete are discarded.
+func (t *Tracker) CloseConnection(connectionID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.conns, connectionID)
+	delete(t.broken, connectionID)
+}
+
+func (t *Tracker) markBroken(connectionID string) {
+	delete(t.conns, connectionID)
+	t.broken[connectionID] = struct{}{}
+}
+
+// frameReader reassembles the messages of a stream, the data could
+// have partial or multiple messages. The body of each message is
+// kept up to maxBody bytes, the remaining is discarded.
+type frameReader struct {
+	headerSize int
+	// bodySize returns the size of the body based on the header,
+	// a negative value means the stream could not be decoded.
+	bodySize func(header []byte) int
+	maxBody  int
+	// skip is the number of bytes to discard before the next message
+	skip int
+
+	header    []byte
+	body      []byte
+	remaining int
+	inBody    bool
+}
+
+// feed reads the data calling fn for each complete message. The header size and the
+// body size function could be changed by fn, they apply to the next message.
+func (r *frameReader) feed(data []byte, fn func(header, body []byte) error) error {
+	for len(data) > 0 {
+		if !r.inBody {
+			if r.skip > 0 && len(r.header) == 0 {
+				n := min(r.skip, len(data))
+				r.skip -= n
+				data = data[n:]
+				continue
+			}
+			n := min(r.headerSize-len(r.header), len(data))
+			r.header = append(r.header, data[:n]...)
+			data = data[n:]
+			if len(r.header) < r.headerSize {
+				return nil
+			}
+			r.remaining = r.bodySize(r.header)
+			if r.remaining < 0 {
+				return errInvalidMessage
+			}
+			r.body = r.body[:0]
+			r.inBody = true
+		}
+		n := min(r.remaining, len(data))
+		if keep := min(r.maxBody-len(r.body), n); keep > 0 {
+			r.body = append(r.body, data[:keep]...)
+		}
+		r.remaining -= n
+		data = data[n:]
+		if r.remaining > 0 {
+			return nil
+		}
+		header := r.header
+		r.header, r.inBody = nil, false
+		if err := fn(header, r.body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
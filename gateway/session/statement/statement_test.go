@@ -0,0 +1,281 @@
+package statement
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	pb "github.com/hoophq/hoop/common/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTracker(connType pb.ConnectionType) *Tracker {
+	t := NewTracker(connType)
+	clock := time.Date(2024, time.June, 10, 10, 0, 0, 0, time.UTC)
+	t.nowFunc = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+	return t
+}
+
+func pgMessage(typ byte, body ...string) []byte {
+	var payload []byte
+	for _, b := range body {
+		payload = append(payload, b...)
+	}
+	msg := []byte{typ, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(msg[1:], uint32(len(payload)+4))
+	return append(msg, payload...)
+}
+
+func pgStartupMessage(code uint32) []byte {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint32(msg, 8)
+	binary.BigEndian.PutUint32(msg[4:], code)
+	return msg
+}
+
+func pgError(code, message string) []byte {
+	return pgMessage('E', "SERROR\x00", "C"+code+"\x00", "M"+message+"\x00", "\x00")
+}
+
+func concat(items ...[]byte) []byte {
+	var data []byte
+	for _, item := range items {
+		data = append(data, item...)
+	}
+	return data
+}
+
+type stmtWrite struct {
+	client []byte
+	server []byte
+}
+
+func TestPostgresTracker(t *testing.T) {
+	startup := concat(pgStartupMessage(pgSSLRequestCode), pgStartupMessage(pgProtocolVersion3))
+	readyForQuery := pgMessage('Z', "I")
+	for _, tt := range []struct {
+		msg    string
+		writes []stmtWrite
+		want   []*Record
+	}{
+		{
+			msg: "it must track a simple query",
+			writes: []stmtWrite{
+				{client: startup, server: concat([]byte("N"), pgMessage('R', "\x00\x00\x00\x00"), readyForQuery)},
+				{client: pgMessage('Q', "SELECT * FROM customers\x00")},
+				{server: concat(pgMessage('T', "..."), pgMessage('D', "a"), pgMessage('D', "b"))},
+				{server: concat(pgMessage('C', "SELECT 2\x00"), readyForQuery)},
+			},
+			want: []*Record{{Statement: "SELECT * FROM customers", Rows: 2}},
+		},
+		{
+			msg: "it must track a simple query with an error",
+			writes: []stmtWrite{
+				{client: startup, server: concat([]byte("N"), readyForQuery)},
+				{client: pgMessage('Q', "SELECT * FROM foo\x00")},
+				{server: concat(pgError("42P01", `relation "foo" does not exist`), readyForQuery)},
+			},
+			want: []*Record{{Statement: "SELECT * FROM foo", Rows: -1,
+				ErrorCode: "42P01", ErrorMessage: `relation "foo" does not exist`}},
+		},
+		{
+			msg: "it must track statements without rows",
+			writes: []stmtWrite{
+				{client: startup, server: concat([]byte("N"), readyForQuery)},
+				{client: pgMessage('Q', "CREATE TABLE foo (id INT)\x00")},
+				{server: concat(pgMessage('C', "CREATE TABLE\x00"), readyForQuery)},
+			},
+			want: []*Record{{Statement: "CREATE TABLE foo (id INT)", Rows: -1}},
+		},
+		{
+			msg: "it must track the extended protocol",
+			writes: []stmtWrite{
+				{client: startup, server: concat([]byte("N"), readyForQuery)},
+				{client: concat(
+					pgMessage('P', "s1\x00", "UPDATE t SET a=$1\x00", "\x00\x00"),
+					pgMessage('B', "\x00", "s1\x00", "..."),
+					pgMessage('E', "\x00", "\x00\x00\x00\x00"),
+					pgMessage('P', "\x00", "INSERT INTO t VALUES ($1)\x00", "\x00\x00"),
+					pgMessage('B', "p1\x00", "\x00", "..."),
+					pgMessage('E', "p1\x00", "\x00\x00\x00\x00"),
+					pgMessage('S'),
+				)},
+				{server: concat(
+					pgMessage('1'), pgMessage('2'), pgMessage('C', "UPDATE 3\x00"),
+					pgMessage('1'), pgMessage('2'), pgMessage('C', "INSERT 0 1\x00"),
+					readyForQuery,
+				)},
+			},
+			want: []*Record{
+				{Statement: "UPDATE t SET a=$1", Rows: 3},
+				{Statement: "INSERT INTO t VALUES ($1)", Rows: 1},
+			},
+		},
+		{
+			msg: "it must discard the statements skipped after an error of the extended protocol",
+			writes: []stmtWrite{
+				{client: startup, server: concat([]byte("N"), readyForQuery)},
+				{client: concat(
+					pgMessage('P', "\x00", "SELECT 1/0\x00", "\x00\x00"),
+					pgMessage('B', "\x00", "\x00", "..."),
+					pgMessage('E', "\x00", "\x00\x00\x00\x00"),
+					pgMessage('E', "\x00", "\x00\x00\x00\x00"),
+					pgMessage('S'),
+				)},
+				{server: concat(pgMessage('1'), pgMessage('2'), pgError("22012", "division by zero"), readyForQuery)},
+			},
+			want: []*Record{{Statement: "SELECT 1/0", Rows: -1, ErrorCode: "22012", ErrorMessage: "division by zero"}},
+		},
+		{
+			msg: "it must reassemble messages split in many writes",
+			writes: func() []stmtWrite {
+				query := pgMessage('Q', "SELECT 1\x00")
+				done := concat(pgMessage('C', "SELECT 1\x00"), readyForQuery)
+				return []stmtWrite{
+					{client: startup[:3]},
+					{client: startup[3:], server: []byte("N")},
+					{client: query[:2]},
+					{client: query[2:], server: done[:7]},
+					{server: done[7:]},
+				}
+			}(),
+			want: []*Record{{Statement: "SELECT 1", Rows: 1}},
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			tracker := newTestTracker(pb.ConnectionTypePostgres)
+			var got []*Record
+			for _, w := range tt.writes {
+				if w.client != nil {
+					tracker.ClientWrite("1", w.client)
+				}
+				if w.server != nil {
+					got = append(got, tracker.ServerWrite("1", w.server)...)
+				}
+			}
+			assertRecords(t, tt.want, got)
+		})
+	}
+}
+
+func mysqlPacket(seq byte, body ...string) []byte {
+	var payload []byte
+	for _, b := range body {
+		payload = append(payload, b...)
+	}
+	size := len(payload)
+	return append([]byte{byte(size), byte(size >> 8), byte(size >> 16), seq}, payload...)
+}
+
+func TestMySQLTracker(t *testing.T) {
+	handshake := stmtWrite{
+		server: mysqlPacket(0, "\x0a8.0.36\x00..."),
+		client: mysqlPacket(1, "\x03\xa6\x0f\x00..."),
+	}
+	okPacket := mysqlPacket(2, "\x00\x00\x00\x02\x00\x00\x00")
+	for _, tt := range []struct {
+		msg    string
+		writes []stmtWrite
+		want   []*Record
+	}{
+		{
+			msg: "it must track a query with a result set",
+			writes: []stmtWrite{
+				handshake,
+				{server: okPacket},
+				{client: mysqlPacket(0, "\x03SELECT id FROM customers")},
+				{server: concat(
+					mysqlPacket(1, "\x01"),
+					mysqlPacket(2, "\x03def..."),
+					mysqlPacket(3, "\xfe\x00\x00\x02\x00"),
+					mysqlPacket(4, "\x011"),
+					mysqlPacket(5, "\x012"),
+					mysqlPacket(6, "\x013"),
+					mysqlPacket(7, "\xfe\x00\x00\x02\x00"),
+				)},
+			},
+			want: []*Record{{Statement: "SELECT id FROM customers", Rows: 3}},
+		},
+		{
+			msg: "it must track a query with the affected rows",
+			writes: []stmtWrite{
+				handshake,
+				{server: okPacket},
+				{client: mysqlPacket(0, "\x03DELETE FROM customers")},
+				{server: mysqlPacket(1, "\x00\x05\x00\x02\x00\x00\x00")},
+			},
+			want: []*Record{{Statement: "DELETE FROM customers", Rows: 5}},
+		},
+		{
+			msg: "it must track a query with an error",
+			writes: []stmtWrite{
+				handshake,
+				{server: okPacket},
+				{client: mysqlPacket(0, "\x03SELECT * FROM foo")},
+				{server: mysqlPacket(1, "\xff\x7a\x04#42S02Table 'db.foo' doesn't exist")},
+			},
+			want: []*Record{{Statement: "SELECT * FROM foo", Rows: -1,
+				ErrorCode: "1146", ErrorMessage: "Table 'db.foo' doesn't exist"}},
+		},
+		{
+			msg: "it must track multiple result sets without eof packets",
+			writes: []stmtWrite{
+				handshake,
+				{server: okPacket},
+				{client: mysqlPacket(0, "\x03CALL p()")},
+				{server: concat(
+					mysqlPacket(1, "\x01"),
+					mysqlPacket(2, "\x03def..."),
+					mysqlPacket(3, "\x011"),
+					mysqlPacket(4, "\xfe\x00\x00\x0a\x00\x00\x00\x00\x00"),
+					mysqlPacket(5, "\x00\x00\x00\x02\x00\x00\x00"),
+				)},
+			},
+			want: []*Record{{Statement: "CALL p()", Rows: 1}},
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			tracker := newTestTracker(pb.ConnectionTypeMySQL)
+			var got []*Record
+			for _, w := range tt.writes {
+				if w.client != nil {
+					tracker.ClientWrite("1", w.client)
+				}
+				if w.server != nil {
+					got = append(got, tracker.ServerWrite("1", w.server)...)
+				}
+			}
+			assertRecords(t, tt.want, got)
+		})
+	}
+}
+
+func TestTrackerUnsupportedAndBrokenConnections(t *testing.T) {
+	assert.Nil(t, NewTracker(pb.ConnectionTypeMongoDB))
+
+	tracker := newTestTracker(pb.ConnectionTypePostgres)
+	tracker.ClientWrite("1", pgStartupMessage(1234))
+	tracker.ClientWrite("1", pgMessage('Q', "SELECT 1\x00"))
+	assert.Empty(t, tracker.ServerWrite("1", concat(pgMessage('C', "SELECT 1\x00"), pgMessage('Z', "I"))))
+
+	tracker.CloseConnection("1")
+	tracker.ClientWrite("1", concat(pgStartupMessage(pgProtocolVersion3), pgMessage('Q', "SELECT 1\x00")))
+	got := tracker.ServerWrite("1", concat(pgMessage('C', "SELECT 1\x00"), pgMessage('Z', "I")))
+	assertRecords(t, []*Record{{Statement: "SELECT 1", Rows: 1}}, got)
+}
+
+// assertRecords compares the records ignoring the time, it asserts the statements end after they start
+func assertRecords(t *testing.T, want, got []*Record) {
+	if !assert.Len(t, got, len(want)) {
+		return
+	}
+	for i, rec := range got {
+		assert.Equal(t, "1", rec.ConnectionID)
+		assert.True(t, rec.EndTime.After(rec.StartTime), "end time must be after the start time")
+		want[i].ConnectionID, want[i].StartTime, want[i].EndTime = rec.ConnectionID, rec.StartTime, rec.EndTime
+		assert.Equal(t, want[i], rec)
+	}
+}
@@ -32,10 +32,12 @@ type SlackService struct {
 }
 
 const (
-	reviewIDMetadataKey  = "review_id"
-	sessionIDMetadataKey = "session_id"
-	EventKindOneTime     = "onetime"
-	EventKindJit         = "jit"
+	reviewIDMetadataKey   = "review_id"
+	sessionIDMetadataKey  = "session_id"
+	EventKindOneTime      = "onetime"
+	EventKindJit          = "jit"
+	EventKindJitExtension = "jit-extension"
+	EventKindBreakGlass   = "break-glass"
 	// it's usually 2000, keep a more safe number
 	maxLabelSize  = 1800
 	maxGroupsSize = 50
@@ -87,6 +89,17 @@ type MessageReviewRequest struct {
 	WebappURL      string
 	SessionID      string
 	SlackChannels  []string
+	// Escalated indicates the message is sent to the escalation group of the review
+	Escalated bool
+	// Extension indicates the message is a request of additional time
+	// for a jit review, the session time is the requested time
+	Extension bool
+	// Scheduled indicates the message is a request of access for a future
+	// time window, the script contains the details of the window
+	Scheduled bool
+	// BreakGlass indicates the message announces a session opened without
+	// waiting for the review, the reviewers must acknowledge it
+	BreakGlass bool
 }
 
 type MessageReviewResponse struct {
@@ -115,6 +128,16 @@ func (m *MessageReviewRequest) sessionTime() string {
 
 func (s *SlackService) SendMessageReview(msg *MessageReviewRequest) (result string) {
 	title := "Review"
+	switch {
+	case msg.Escalated:
+		title = "Review (escalated)"
+	case msg.Extension:
+		title = "Review (access extension)"
+	case msg.Scheduled:
+		title = "Review (scheduled access)"
+	case msg.BreakGlass:
+		title = "Break-glass access (acknowledgement required)"
+	}
 
 	header := slack.NewHeaderBlock(&slack.TextBlockObject{
 		Type: slack.PlainTextType,
@@ -152,7 +175,7 @@ func (s *SlackService) SendMessageReview(msg *MessageReviewRequest) (result stri
 		Type: slack.MarkdownType,
 		Text: fmt.Sprintf("_script_\n```%s```", script),
 	}, nil, nil)
-	if msg.SessionTime != nil {
+	if msg.SessionTime != nil && !msg.Scheduled && !msg.BreakGlass {
 		scriptBlock = slack.NewSectionBlock(&slack.TextBlockObject{Type: slack.PlainTextType, Text: "-"}, nil, nil)
 	}
 
@@ -177,6 +200,10 @@ func (s *SlackService) SendMessageReview(msg *MessageReviewRequest) (result stri
 		slack.NewDividerBlock(),
 	}
 
+	approveText := "Approve"
+	if msg.BreakGlass {
+		approveText = "Acknowledge"
+	}
 	// add groups button
 	for i, groupName := range msg.ApprovalGroups {
 		key := fmt.Sprintf("%s:%s", msg.ID, groupName)
@@ -190,7 +217,7 @@ func (s *SlackService) SendMessageReview(msg *MessageReviewRequest) (result stri
 			slack.NewActionBlock(
 				blockID,
 				slack.NewButtonBlockElement("review-approved", key,
-					&slack.TextBlockObject{Type: slack.PlainTextType, Text: "Approve"}).
+					&slack.TextBlockObject{Type: slack.PlainTextType, Text: approveText}).
 					WithStyle(slack.StylePrimary),
 				slack.NewButtonBlockElement("review-rejected", key,
 					&slack.TextBlockObject{Type: slack.PlainTextType, Text: "Reject"}).
@@ -200,7 +227,12 @@ func (s *SlackService) SendMessageReview(msg *MessageReviewRequest) (result stri
 	}
 
 	eventKind := EventKindOneTime
-	if msg.SessionTime != nil {
+	switch {
+	case msg.Extension:
+		eventKind = EventKindJitExtension
+	case msg.BreakGlass:
+		eventKind = EventKindBreakGlass
+	case msg.SessionTime != nil:
 		eventKind = EventKindJit
 	}
 	metadata := slack.MsgOptionMetadata(slack.SlackMetadata{
@@ -247,8 +279,13 @@ func (s *SlackService) UpdateMessage(msg *MessageReviewResponse, isApproved bool
 
 	if isApproved {
 		text := "*Session ready to be executed!*\n"
-		if msg.EventKind == EventKindJit {
+		switch msg.EventKind {
+		case EventKindJit:
 			text = "*Interactive session ready!*\n"
+		case EventKindJitExtension:
+			text = "*Access extended!*\n"
+		case EventKindBreakGlass:
+			text = "*Break-glass access acknowledged!*\n"
 		}
 		blocks = append(blocks,
 			slack.NewDividerBlock(),
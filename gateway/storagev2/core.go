@@ -84,6 +84,17 @@ func (c *Context) WithOrgLicenseData(licenseData *json.RawMessage) *Context {
 	return c
 }
 
+func (c *Context) WithAPIToken(tokenID string, connections []string) *Context {
+	c.TokenID = tokenID
+	c.TokenConnections = connections
+	return c
+}
+
+func (c *Context) WithPermissions(permissions []string) *Context {
+	c.Permissions = permissions
+	return c
+}
+
 func (c *Context) WithApiURL(apiURL string) *Context {
 	c.ApiURL = apiURL
 	return c
@@ -120,3 +131,6 @@ func (c *Context) GroupRoleName() string {
 	}
 	return "regular"
 }
+
+// GetTokenConnections returns the connections an api token is restricted to
+func (c *Context) GetTokenConnections() []string { return c.TokenConnections }
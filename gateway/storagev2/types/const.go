@@ -51,4 +51,5 @@ const (
 	ReviewStatusProcessing ReviewStatus = "PROCESSING"
 	ReviewStatusExecuted   ReviewStatus = "EXECUTED"
 	ReviewStatusUnknown    ReviewStatus = "UNKNOWN"
+	ReviewStatusExpired    ReviewStatus = "EXPIRED"
 )
@@ -31,6 +31,9 @@ func (c *APIContext) IsAuditorOrAdminUser() bool {
 	return false
 }
 
+// HasPermission returns true if a custom role of the user grants the permission
+func (c *APIContext) HasPermission(name string) bool { return pb.IsInList(name, c.Permissions) }
+
 // SetName set the attribute name using from the Connection structure
 func (p *PluginConnection) SetName() {
 	if p != nil {
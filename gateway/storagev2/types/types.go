@@ -26,6 +26,14 @@ type APIContext struct {
 	UserAnonPicture       string
 	UserAnonEmailVerified *bool
 
+	// the api token used to authenticate, the token
+	// could be restricted to a list of connections
+	TokenID          string   `json:"-"`
+	TokenConnections []string `json:"-"`
+
+	// the permissions granted by the custom roles of the user groups
+	Permissions []string `json:"-"`
+
 	ApiURL  string `json:"-"`
 	GrpcURL string `json:"-"`
 }
@@ -122,11 +130,61 @@ type ReviewConnection struct {
 }
 
 type ReviewGroup struct {
-	Id         string       `json:"id"          edn:"xt/id"`
-	Group      string       `json:"group"       edn:"review-group/group"`
-	Status     ReviewStatus `json:"status"      edn:"review-group/status"`
-	ReviewedBy *ReviewOwner `json:"reviewed_by" edn:"review-group/reviewed-by"`
-	ReviewDate *string      `json:"review_date" edn:"review-group/review_date"`
+	Id         string           `json:"id"          edn:"xt/id"`
+	Group      string           `json:"group"       edn:"review-group/group"`
+	Status     ReviewStatus     `json:"status"      edn:"review-group/status"`
+	ReviewedBy *ReviewOwner     `json:"reviewed_by" edn:"review-group/reviewed-by"`
+	ReviewDate *string          `json:"review_date" edn:"review-group/review_date"`
+	Approvals  []ReviewApproval `json:"approvals"   edn:"review-group/approvals"`
+	// Escalation is the fallback group notified when the review isn't reviewed in time
+	Escalation bool `json:"escalation" edn:"review-group/escalation"`
+}
+
+// ReviewApproval is an approval of a member of a review group
+type ReviewApproval struct {
+	ReviewedBy ReviewOwner `json:"reviewed_by"`
+	ReviewDate string      `json:"review_date"`
+}
+
+const (
+	ReviewPolicyModeAll = "all"
+	ReviewPolicyModeAny = "any"
+)
+
+// ReviewPolicy are the rules to consider a review approved
+type ReviewPolicy struct {
+	// the minimum of distinct members that must approve to consider a group approved
+	MinApprovalsPerGroup int `json:"min_approvals_per_group"`
+	// the minimum of distinct approvers considering all groups
+	MinApprovers int `json:"min_approvers"`
+	// all requires every group to approve, any requires at least one group
+	Mode string `json:"mode"`
+	// a group that must always approve the review
+	RequiredGroup string `json:"required_group"`
+	// the time in seconds to expire a pending review, zero means it never expires
+	ExpirationSec int `json:"expiration_sec"`
+	// the time in seconds to notify the escalation group of a pending review
+	EscalationSec int `json:"escalation_sec"`
+	// the fallback group notified when the review isn't reviewed in time
+	EscalationGroup string `json:"escalation_group"`
+	// allows opening sessions without waiting for the review in emergencies,
+	// the reviewers acknowledge the access afterwards
+	BreakGlass bool `json:"break_glass"`
+	// the access time in seconds of a break-glass session
+	BreakGlassDurationSec int `json:"break_glass_duration_sec"`
+	// the maximum of break-glass sessions of a user in the connection in 24 hours
+	BreakGlassMaxPerDay int `json:"break_glass_max_per_day"`
+}
+
+// ReviewExtension is a request of additional access time for an approved jit review,
+// it's reviewed by the same groups of the review.
+type ReviewExtension struct {
+	// the additional time in seconds requested by the owner of the review
+	AccessDurationSec int          `json:"access_duration_sec"`
+	Status            ReviewStatus `json:"status"`
+	CreatedAt         string       `json:"created_at"`
+	// the decision of each review group of the review
+	ReviewGroupsData []ReviewGroup `json:"review_groups_data"`
 }
 
 type Review struct {
@@ -147,6 +205,12 @@ type Review struct {
 	Connection       ReviewConnection  `edn:"review/review-connection"`
 	ReviewGroupsIds  []string          `edn:"review/review-groups"`
 	ReviewGroupsData []ReviewGroup     `edn:"review/review-groups-data"`
+	Policy           *ReviewPolicy     `edn:"review/policy"`
+	Extension        *ReviewExtension  `edn:"review/extension"`
+	// the start of the access window of scheduled reviews
+	AccessStartAt *time.Time `edn:"review/access-start-at"`
+	AccessReason  string     `edn:"review/access-reason"`
+	JiraIssueKey  string     `edn:"review/jira-issue-key"`
 }
 
 type ReviewJSON struct {
@@ -165,6 +229,11 @@ type ReviewJSON struct {
 	ReviewOwner      ReviewOwner      `json:"review_owner"`
 	Connection       ReviewConnection `json:"review_connection"`
 	ReviewGroupsData []ReviewGroup    `json:"review_groups_data"`
+	Policy           ReviewPolicy     `json:"policy"`
+	Extension        *ReviewExtension `json:"extension"`
+	AccessStartAt    *time.Time       `json:"access_start_at"`
+	AccessReason     string           `json:"access_reason"`
+	JiraIssueKey     string           `json:"jira_issue_key"`
 }
 
 type SessionEventStream []any
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/getsentry/sentry-go"
 	"github.com/hoophq/hoop/common/apiutils"
@@ -15,11 +16,13 @@ import (
 	pbgateway "github.com/hoophq/hoop/common/proto/gateway"
 	"github.com/hoophq/hoop/gateway/analytics"
 	"github.com/hoophq/hoop/gateway/appconfig"
+	"github.com/hoophq/hoop/gateway/review"
 	"github.com/hoophq/hoop/gateway/storagev2/types"
 	"github.com/hoophq/hoop/gateway/transport/connectionrequests"
 	transportext "github.com/hoophq/hoop/gateway/transport/extensions"
 	pluginslack "github.com/hoophq/hoop/gateway/transport/plugins/slack"
 	plugintypes "github.com/hoophq/hoop/gateway/transport/plugins/types"
+	"github.com/hoophq/hoop/gateway/transport/plugins/webhooks"
 	"github.com/hoophq/hoop/gateway/transport/streamclient"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -165,6 +168,13 @@ func (s *Server) listenClientMessages(stream *streamclient.ProxyStream) error {
 			if connectResponse.Context != nil {
 				pctx.Context = connectResponse.Context
 			}
+			if connectResponse.ReviewID != "" {
+				pctx.ReviewID = connectResponse.ReviewID
+				stream.SetPluginContext(func(p *plugintypes.Context) { p.ReviewID = connectResponse.ReviewID })
+			}
+			if connectResponse.RevokeAt != nil {
+				stream.SetAccessDeadline(*connectResponse.RevokeAt)
+			}
 			if connectResponse.ClientPacket != nil {
 				_ = stream.Send(connectResponse.ClientPacket)
 				shouldProcessClientPacket = false
@@ -280,6 +290,20 @@ func handleSystemPacketRequests(pktType string) (handled bool) {
 }
 
 func (s *Server) ReviewStatusChange(rev *types.Review) {
+	// a revoked jit review could grant access to multiple sessions
+	if rev.Status == types.ReviewStatusRevoked {
+		total := streamclient.DisconnectProxiesByReview(rev.Id, streamclient.ErrAccessRevoked)
+		log.With("id", rev.Id, "connection", rev.Connection.Name, "sessions", total).
+			Infof("jit review revoked")
+		return
+	}
+	// a break-glass session is opened before the review
+	if rev.Type == review.ReviewTypeBreakGlass && rev.Status == types.ReviewStatusRejected {
+		total := streamclient.DisconnectProxiesByReview(rev.Id, streamclient.ErrBreakGlassRejected)
+		log.With("sid", rev.Session, "id", rev.Id, "connection", rev.Connection.Name, "sessions", total).
+			Infof("break-glass review rejected")
+		return
+	}
 	if rev.Status == types.ReviewStatusApproved {
 		pluginslack.SendApprovedMessage(
 			rev.OrgId,
@@ -293,10 +317,15 @@ func (s *Server) ReviewStatusChange(rev *types.Review) {
 	if proxyStream != nil {
 		payload := []byte(rev.Input)
 		packetType := pbclient.SessionOpenApproveOK
-		if rev.Status == types.ReviewStatusRejected {
+		switch rev.Status {
+		case types.ReviewStatusRejected:
 			packetType = pbclient.SessionClose
 			payload = []byte(`access to connection has been denied`)
 			proxyStream.Close(fmt.Errorf("access to connection has been denied"))
+		case types.ReviewStatusExpired:
+			packetType = pbclient.SessionClose
+			payload = []byte(`the review has expired, it was not approved in time`)
+			proxyStream.Close(fmt.Errorf("the review has expired, it was not approved in time"))
 		}
 		// TODO: return erroo to caller
 		_ = proxyStream.Send(&pb.Packet{
@@ -309,6 +338,117 @@ func (s *Server) ReviewStatusChange(rev *types.Review) {
 		Infof("review status change")
 }
 
+// ReviewEscalated notifies the escalation group of a review through Slack and webhooks
+func (s *Server) ReviewEscalated(rev *types.Review, group string) {
+	pluginslack.SendEscalationMessage(rev, group, s.IDProvider.ApiURL)
+	err := webhooks.SendMessage(rev.OrgId, webhooks.EventReviewEscalatedType, map[string]any{
+		"event_type":       webhooks.EventReviewEscalatedType,
+		"id":               rev.Id,
+		"session_id":       rev.Session,
+		"connection":       rev.Connection.Name,
+		"owner_email":      rev.ReviewOwner.Email,
+		"escalation_group": group,
+		"review_url":       fmt.Sprintf("%s/reviews/%s", s.IDProvider.ApiURL, rev.Id),
+	})
+	if err != nil {
+		log.With("sid", rev.Session).Warn(err)
+	}
+}
+
+// ReviewExtensionRequested notifies the groups of a jit review about
+// a request of additional access time through Slack and webhooks
+func (s *Server) ReviewExtensionRequested(rev *types.Review) {
+	pluginslack.SendExtensionMessage(rev, s.IDProvider.ApiURL)
+	s.sendReviewExtensionWebhook(rev)
+}
+
+// ReviewExtensionChange pushes out the deadline of the live sessions
+// of a jit review when its extension is approved
+func (s *Server) ReviewExtensionChange(rev *types.Review) {
+	if rev.Extension.Status == types.ReviewStatusApproved && rev.RevokeAt != nil {
+		total := streamclient.ExtendProxiesByReview(rev.Id, *rev.RevokeAt)
+		log.With("id", rev.Id, "connection", rev.Connection.Name, "sessions", total).
+			Infof("jit review extended until %v", rev.RevokeAt.Format(time.RFC3339))
+	}
+	s.sendReviewExtensionWebhook(rev)
+}
+
+// ReviewScheduled notifies the groups of a scheduled review through Slack and webhooks
+func (s *Server) ReviewScheduled(rev *types.Review) {
+	pluginslack.SendScheduledMessage(rev, s.IDProvider.ApiURL)
+	err := webhooks.SendMessage(rev.OrgId, webhooks.EventReviewScheduledType, map[string]any{
+		"event_type":          webhooks.EventReviewScheduledType,
+		"id":                  rev.Id,
+		"connection":          rev.Connection.Name,
+		"owner_email":         rev.ReviewOwner.Email,
+		"access_start_at":     rev.AccessStartAt,
+		"access_duration_sec": int(rev.AccessDuration.Seconds()),
+		"access_reason":       rev.AccessReason,
+		"jira_issue_key":      rev.JiraIssueKey,
+		"review_url":          fmt.Sprintf("%s/reviews/%s", s.IDProvider.ApiURL, rev.Id),
+	})
+	if err != nil {
+		log.With("id", rev.Id).Warn(err)
+	}
+}
+
+// ReviewBreakGlass announces a break-glass session to the groups of the review
+// through Slack and webhooks, the groups must acknowledge the access.
+func (s *Server) ReviewBreakGlass(rev *types.Review) {
+	pluginslack.SendBreakGlassMessage(rev, s.IDProvider.ApiURL)
+	err := webhooks.SendMessage(rev.OrgId, webhooks.EventReviewBreakGlassType, map[string]any{
+		"event_type":          webhooks.EventReviewBreakGlassType,
+		"id":                  rev.Id,
+		"session_id":          rev.Session,
+		"connection":          rev.Connection.Name,
+		"owner_email":         rev.ReviewOwner.Email,
+		"access_reason":       rev.AccessReason,
+		"access_duration_sec": int(rev.AccessDuration.Seconds()),
+		"revoke_at":           rev.RevokeAt,
+		"review_groups":       rev.ReviewGroupsIds,
+		"review_url":          fmt.Sprintf("%s/reviews/%s", s.IDProvider.ApiURL, rev.Id),
+	})
+	if err != nil {
+		log.With("sid", rev.Session).Warn(err)
+	}
+}
+
+// ReviewCreated notifies the groups of a review created outside of
+// the stream of a session (e.g.: guard rails) through Slack and webhooks
+func (s *Server) ReviewCreated(rev *types.Review) {
+	pluginslack.SendReviewMessage(rev, s.IDProvider.ApiURL)
+	err := webhooks.SendMessage(rev.OrgId, webhooks.EventReviewCreatedType, map[string]any{
+		"event_type":    webhooks.EventReviewCreatedType,
+		"id":            rev.Id,
+		"session_id":    rev.Session,
+		"connection":    rev.Connection.Name,
+		"owner_email":   rev.ReviewOwner.Email,
+		"input":         rev.Input,
+		"review_groups": rev.ReviewGroupsIds,
+		"review_url":    fmt.Sprintf("%s/reviews/%s", s.IDProvider.ApiURL, rev.Id),
+	})
+	if err != nil {
+		log.With("sid", rev.Session).Warn(err)
+	}
+}
+
+func (s *Server) sendReviewExtensionWebhook(rev *types.Review) {
+	err := webhooks.SendMessage(rev.OrgId, webhooks.EventReviewExtensionType, map[string]any{
+		"event_type":          webhooks.EventReviewExtensionType,
+		"id":                  rev.Id,
+		"session_id":          rev.Session,
+		"connection":          rev.Connection.Name,
+		"owner_email":         rev.ReviewOwner.Email,
+		"status":              rev.Extension.Status,
+		"access_duration_sec": rev.Extension.AccessDurationSec,
+		"revoke_at":           rev.RevokeAt,
+		"review_url":          fmt.Sprintf("%s/reviews/%s", s.IDProvider.ApiURL, rev.Id),
+	})
+	if err != nil {
+		log.With("sid", rev.Session).Warn(err)
+	}
+}
+
 func validateConnectionType(clientVerb string, pctx plugintypes.Context) error {
 	if clientVerb == pb.ClientVerbExec {
 		connType := pb.ToConnectionType(pctx.ConnectionType, pctx.ConnectionSubType)
@@ -8,6 +8,7 @@ import (
 	"github.com/hoophq/hoop/common/proto"
 	pbagent "github.com/hoophq/hoop/common/proto/agent"
 	pbclient "github.com/hoophq/hoop/common/proto/client"
+	"github.com/hoophq/hoop/common/redistypes"
 	"github.com/hoophq/hoop/gateway/guardrails"
 	"github.com/hoophq/hoop/gateway/jira"
 	"github.com/hoophq/hoop/gateway/models"
@@ -15,7 +16,12 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-var mem = memory.New()
+var (
+	mem = memory.New()
+	// guardRailsMatchStore keeps the matches recorded by session
+	// to avoid recording the same match multiple times
+	guardRailsMatchStore = memory.New()
+)
 
 type Context struct {
 	SID                                 string
@@ -31,18 +37,37 @@ func OnReceive(ctx Context, pkt *proto.Packet) error {
 	}
 	switch pkt.Type {
 	case pbagent.SessionOpen:
-		conn, err := models.GetConnectionGuardRailRules(ctx.OrgID, ctx.ConnectionName)
-		if err != nil || conn == nil {
-			return fmt.Errorf("unable to obtain connection (empty: %v, name=%v): %v",
-				conn == nil, ctx.ConnectionName, err)
+		if _, err := loadRules(ctx); err != nil {
+			return err
+		}
+	case pbagent.RedisConnectionWrite:
+		rules, err := getRules(ctx)
+		if rules == nil {
+			return err
+		}
+		commands, err := redistypes.DecodeCommandsText(pkt.Payload)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "failed decoding redis commands: %v", err)
+		}
+		result, err := rules.input.Evaluate("input", commands)
+		switch err.(type) {
+		case *guardrails.ErrRuleMatch:
+			return status.Errorf(codes.FailedPrecondition, err.Error())
+		case nil:
+		default:
+			return fmt.Errorf("internal error, failed validating guard rails input rules: %v", err)
+		}
+		// native protocols can't wait for a review, the command is rejected
+		if result.Review != nil {
+			return status.Errorf(codes.FailedPrecondition, result.Review.Error())
 		}
-		mem.Set(ctx.SID, conn.GuardRailOutputRules)
+		recordMatches(ctx, result.Matches())
 	case pbclient.WriteStdout, pbclient.WriteStderr:
-		outputRules, ok := mem.Get(ctx.SID).([]byte)
-		if !ok {
-			return nil
+		rules, err := getRules(ctx)
+		if rules == nil {
+			return err
 		}
-		err := guardrails.Validate("output", outputRules, pkt.Payload)
+		result, err := rules.output.Evaluate("output", pkt.Payload)
 		switch err.(type) {
 		case *guardrails.ErrRuleMatch:
 			return status.Errorf(codes.FailedPrecondition, err.Error())
@@ -50,6 +75,8 @@ func OnReceive(ctx Context, pkt *proto.Packet) error {
 		default:
 			return fmt.Errorf("internal error, failed validating guard rails output rules: %v", err)
 		}
+		pkt.Payload = result.Data
+		recordMatches(ctx, result.Matches())
 	case pbclient.SessionClose:
 		jiraConf, err := models.GetJiraIntegration(ctx.OrgID)
 		if err != nil {
@@ -77,4 +104,63 @@ func OnReceive(ctx Context, pkt *proto.Packet) error {
 	return nil
 }
 
-func OnDisconnect(sid string) { mem.Del(sid) }
+// sessionRuleSet are the compiled rules of a session. The input rules are
+// evaluated by the gateway only for protocols decoded by it, e.g.: redis
+type sessionRuleSet struct {
+	generation int64
+	input      *guardrails.RuleSet
+	output     *guardrails.RuleSet
+}
+
+// getRules returns the rules loaded when the session was opened,
+// it returns nil when the session was not opened by this gateway
+func getRules(ctx Context) (*sessionRuleSet, error) {
+	rules, ok := mem.Get(ctx.SID).(*sessionRuleSet)
+	if !ok {
+		return nil, nil
+	}
+	// the rules were changed, reload it to apply the new version in the session
+	if rules.generation != guardrails.CacheGeneration() {
+		return loadRules(ctx)
+	}
+	return rules, nil
+}
+
+func loadRules(ctx Context) (*sessionRuleSet, error) {
+	generation := guardrails.CacheGeneration()
+	conn, err := models.GetConnectionGuardRailRules(ctx.OrgID, ctx.ConnectionName)
+	if err != nil || conn == nil {
+		return nil, fmt.Errorf("unable to obtain connection (empty: %v, name=%v): %v",
+			conn == nil, ctx.ConnectionName, err)
+	}
+	inputRules, err := guardrails.Compile(conn.GuardRailInputRules)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compile guard rails input rules: %v", err)
+	}
+	outputRules, err := guardrails.Compile(conn.GuardRailOutputRules)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compile guard rails output rules: %v", err)
+	}
+	rules := &sessionRuleSet{generation: generation, input: inputRules, output: outputRules}
+	mem.Set(ctx.SID, rules)
+	return rules, nil
+}
+
+func recordMatches(ctx Context, matches []*guardrails.ErrRuleMatch) {
+	seen, _ := guardRailsMatchStore.Get(ctx.SID).(map[string]struct{})
+	if seen == nil {
+		seen = map[string]struct{}{}
+		guardRailsMatchStore.Set(ctx.SID, seen)
+	}
+	var newMatches []*guardrails.ErrRuleMatch
+	for _, m := range matches {
+		if _, ok := seen[m.Error()]; ok {
+			continue
+		}
+		seen[m.Error()] = struct{}{}
+		newMatches = append(newMatches, m)
+	}
+	RecordGuardRailsMatches(ctx.OrgID, ctx.SID, ctx.ConnectionName, newMatches)
+}
+
+func OnDisconnect(sid string) { mem.Del(sid); guardRailsMatchStore.Del(sid) }
@@ -0,0 +1,50 @@
+package transportext
+
+import (
+	"time"
+
+	"github.com/hoophq/hoop/common/log"
+	"github.com/hoophq/hoop/gateway/guardrails"
+	"github.com/hoophq/hoop/gateway/models"
+	"github.com/hoophq/hoop/gateway/transport/plugins/webhooks"
+)
+
+// RecordGuardRailsMatches persists the non blocking guard rails matches in the session
+// and emits a webhook event for each match with the warn action
+func RecordGuardRailsMatches(orgID, sid, connectionName string, matches []*guardrails.ErrRuleMatch) {
+	if len(matches) == 0 {
+		return
+	}
+	var info []models.SessionGuardRailsInfo
+	for _, m := range matches {
+		info = append(info, models.SessionGuardRailsInfo{
+			Direction: m.StreamDirection(),
+			RuleType:  m.RuleType(),
+			Action:    m.Action(),
+			Message:   m.Error(),
+			CreatedAt: time.Now().UTC(),
+		})
+	}
+	if err := models.AppendSessionGuardRailsInfo(orgID, sid, info); err != nil {
+		log.With("sid", sid).Warnf("failed recording guard rails matches, reason=%v", err)
+	}
+	for _, i := range info {
+		if i.Action != guardrails.ActionWarn {
+			continue
+		}
+		go func(i models.SessionGuardRailsInfo) {
+			err := webhooks.SendMessage(orgID, webhooks.EventGuardRailsMatchType, map[string]any{
+				"event_type": webhooks.EventGuardRailsMatchType,
+				"session_id": sid,
+				"connection": connectionName,
+				"direction":  i.Direction,
+				"rule_type":  i.RuleType,
+				"action":     i.Action,
+				"message":    i.Message,
+			})
+			if err != nil {
+				log.With("sid", sid).Warn(err)
+			}
+		}(i)
+	}
+}
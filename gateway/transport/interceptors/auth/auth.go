@@ -3,6 +3,7 @@ package authinterceptor
 import (
 	"context"
 	"errors"
+	"net"
 	"os"
 	"strings"
 
@@ -12,6 +13,7 @@ import (
 	commongrpc "github.com/hoophq/hoop/common/grpc"
 	"github.com/hoophq/hoop/common/log"
 	pb "github.com/hoophq/hoop/common/proto"
+	"github.com/hoophq/hoop/gateway/accesspolicy"
 	apiconnections "github.com/hoophq/hoop/gateway/api/connections"
 	"github.com/hoophq/hoop/gateway/appconfig"
 	"github.com/hoophq/hoop/gateway/clientexec"
@@ -19,11 +21,13 @@ import (
 	pgagents "github.com/hoophq/hoop/gateway/pgrest/agents"
 	pgorgs "github.com/hoophq/hoop/gateway/pgrest/orgs"
 	pguserauth "github.com/hoophq/hoop/gateway/pgrest/userauth"
+	"github.com/hoophq/hoop/gateway/security/apitoken"
 	"github.com/hoophq/hoop/gateway/security/idp"
 	"github.com/hoophq/hoop/gateway/storagev2/types"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
@@ -110,6 +114,28 @@ func (i *interceptor) StreamServerInterceptor(srv any, ss grpc.ServerStream, inf
 		return err
 	}
 
+	// the executions of the api are issued by the gateway with the plain execution key
+	issuedByGateway := commongrpc.MetaGet(md, "plain-exec-key") == clientexec.PlainExecSecretKey
+	switch clientOrigin[0] {
+	case pb.ConnectionOriginClientAPI, pb.ConnectionOriginClientAPIRunbooks:
+		// the origin is used by the conditions of access policies, clients must not impersonate the api
+		if !issuedByGateway {
+			log.Debugf("client origin %v without a valid plain-exec-key", clientOrigin[0])
+			return status.Errorf(codes.PermissionDenied, "origin %q is reserved to the gateway", clientOrigin[0])
+		}
+	}
+
+	policyReq := accesspolicy.Request{
+		Verb:            commongrpc.MetaGet(md, "verb"),
+		Origin:          clientOrigin[0],
+		SourceIP:        SourceIP(ss.Context()),
+		IssuedByGateway: issuedByGateway,
+	}
+	// clients that don't send the verb open interactive sessions
+	if policyReq.Verb == "" {
+		policyReq.Verb = pb.ClientVerbConnect
+	}
+
 	var ctxVal any
 	switch clientOrigin[0] {
 	case pb.ConnectionOriginAgent:
@@ -124,18 +150,9 @@ func (i *interceptor) StreamServerInterceptor(srv any, ss grpc.ServerStream, inf
 		}
 	// client proxy manager authentication (access token)
 	case pb.ConnectionOriginClientProxyManager:
-		subject, err := i.validateAccessToken(bearerToken)
+		userCtx, err := i.authenticateUser(bearerToken, pb.ClientVerbConnect)
 		if err != nil {
-			log.Debugf("failed verifying access token, reason=%v", err)
-			return status.Errorf(codes.Unauthenticated, "invalid authentication")
-		}
-		userCtx, err := pguserauth.New().FetchUserContext(subject)
-		if err != nil || userCtx.IsEmpty() {
-			log.Errorf("failed fetching user context, reason=%v", err)
-			return status.Errorf(codes.Unauthenticated, "invalid authentication")
-		}
-		if userCtx.UserStatus != string(types.UserStatusActive) {
-			return status.Errorf(codes.Unauthenticated, "user is not active")
+			return err
 		}
 		ctxVal = &GatewayContext{
 			UserContext: *userCtx.ToAPIContext(),
@@ -177,7 +194,7 @@ func (i *interceptor) StreamServerInterceptor(srv any, ss grpc.ServerStream, inf
 
 			gwctx.UserContext.ApiURL = os.Getenv("API_URL")
 			connectionName := commongrpc.MetaGet(md, "connection-name")
-			conn, err := i.getConnection(connectionName, ctx)
+			conn, err := i.getConnection(connectionName, ctx, policyReq)
 			if err != nil {
 				return err
 			}
@@ -188,20 +205,9 @@ func (i *interceptor) StreamServerInterceptor(srv any, ss grpc.ServerStream, inf
 			ctxVal = gwctx
 			break
 		}
-		// first we check if the auth method is local, if so, we authenticate the user
-		// using the local auth method, otherwise we use the i.idp.VerifyAccessToken
-		subject, err := i.validateAccessToken(bearerToken)
+		userCtx, err := i.authenticateUser(bearerToken, policyReq.Verb)
 		if err != nil {
-			log.Debugf("failed verifying access token, reason=%v", err)
-			return status.Errorf(codes.Unauthenticated, "invalid authentication")
-		}
-		userCtx, err := pguserauth.New().FetchUserContext(subject)
-		if err != nil || userCtx.IsEmpty() {
-			return status.Errorf(codes.Unauthenticated, "invalid authentication")
-		}
-
-		if userCtx.UserStatus != string(types.UserStatusActive) {
-			return status.Errorf(codes.Unauthenticated, "user is not active")
+			return err
 		}
 		gwctx := &GatewayContext{
 			UserContext: *userCtx.ToAPIContext(),
@@ -209,7 +215,7 @@ func (i *interceptor) StreamServerInterceptor(srv any, ss grpc.ServerStream, inf
 		}
 		gwctx.UserContext.ApiURL = i.idp.ApiURL
 		connectionName := commongrpc.MetaGet(md, "connection-name")
-		conn, err := i.getConnection(connectionName, userCtx)
+		conn, err := i.getConnection(connectionName, userCtx, policyReq)
 		if err != nil {
 			return err
 		}
@@ -223,6 +229,41 @@ func (i *interceptor) StreamServerInterceptor(srv any, ss grpc.ServerStream, inf
 	return handler(srv, &serverStreamWrapper{ss, nil, ctxVal})
 }
 
+// authenticateUser validates the access token or the api token of a user,
+// the scopes of an api token must allow the verb of the client
+func (i *interceptor) authenticateUser(bearerToken, clientVerb string) (*pguserauth.Context, error) {
+	if apitoken.IsToken(bearerToken) {
+		userCtx, scopes, err := apitoken.Authenticate(bearerToken)
+		if err != nil {
+			log.Debugf("failed authenticating api token, prefix=%v, reason=%v", apitoken.DisplayPrefix(bearerToken), err)
+			return nil, status.Errorf(codes.Unauthenticated, "invalid authentication")
+		}
+		if !apitoken.IsVerbAllowed(scopes, clientVerb) {
+			return nil, status.Errorf(codes.PermissionDenied, "the scopes of the api token do not allow the verb %q", clientVerb)
+		}
+		return userCtx, nil
+	}
+
+	// first we check if the auth method is local, if so, we authenticate the user
+	// using the local auth method, otherwise we use the i.idp.VerifyAccessToken
+	subject, err := i.validateAccessToken(bearerToken)
+	if err != nil {
+		log.Debugf("failed verifying access token, reason=%v", err)
+		return nil, status.Errorf(codes.Unauthenticated, "invalid authentication")
+	}
+	userCtx, err := pguserauth.New().FetchUserContext(subject)
+	if err != nil || userCtx.IsEmpty() {
+		if err != nil {
+			log.Errorf("failed fetching user context, reason=%v", err)
+		}
+		return nil, status.Errorf(codes.Unauthenticated, "invalid authentication")
+	}
+	if userCtx.UserStatus != string(types.UserStatusActive) {
+		return nil, status.Errorf(codes.Unauthenticated, "user is not active")
+	}
+	return userCtx, nil
+}
+
 func (i *interceptor) validateAccessToken(bearerToken string) (subject string, err error) {
 	if i.idp.HasSecretKey() {
 		return i.idp.VerifyAccessTokenHS256Alg(bearerToken)
@@ -230,8 +271,8 @@ func (i *interceptor) validateAccessToken(bearerToken string) (subject string, e
 	return i.idp.VerifyAccessToken(bearerToken)
 }
 
-func (i *interceptor) getConnection(name string, userCtx *pguserauth.Context) (*types.ConnectionInfo, error) {
-	conn, err := apiconnections.FetchByName(userCtx, name)
+func (i *interceptor) getConnection(name string, userCtx *pguserauth.Context, policyReq accesspolicy.Request) (*types.ConnectionInfo, error) {
+	conn, err := apiconnections.FetchByName(userCtx, name, policyReq)
 	if err != nil {
 		log.Errorf("failed retrieving connection %v, err=%v", name, err)
 		sentry.CaptureException(err)
@@ -289,6 +330,19 @@ func (i *interceptor) authenticateAgent(bearerToken string, md metadata.MD) (*pg
 	return ag, nil
 }
 
+// SourceIP returns the ip address of the peer of a grpc stream
+func SourceIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
 func parseBearerToken(md metadata.MD) (string, error) {
 	t := md.Get("authorization")
 	if len(t) == 0 {
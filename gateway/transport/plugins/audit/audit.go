@@ -16,9 +16,11 @@ import (
 	pbagent "github.com/hoophq/hoop/common/proto/agent"
 	pbclient "github.com/hoophq/hoop/common/proto/client"
 	"github.com/hoophq/hoop/common/proto/spectypes"
+	"github.com/hoophq/hoop/common/redistypes"
 	"github.com/hoophq/hoop/gateway/api/openapi"
 	"github.com/hoophq/hoop/gateway/models"
 	eventlogv1 "github.com/hoophq/hoop/gateway/session/eventlog/v1"
+	"github.com/hoophq/hoop/gateway/session/statement"
 	"github.com/hoophq/hoop/gateway/storagev2/types"
 	plugintypes "github.com/hoophq/hoop/gateway/transport/plugins/types"
 )
@@ -27,11 +29,15 @@ var memorySessionStore = memory.New()
 
 type auditPlugin struct {
 	walSessionStore memory.Store
-	started         bool
-	mu              sync.RWMutex
+	// statement trackers of database sessions
+	trackerStore memory.Store
+	started      bool
+	mu           sync.RWMutex
 }
 
-func New() *auditPlugin             { return &auditPlugin{walSessionStore: memory.New()} }
+func New() *auditPlugin {
+	return &auditPlugin{walSessionStore: memory.New(), trackerStore: memory.New()}
+}
 func (p *auditPlugin) Name() string { return plugintypes.PluginAuditName }
 func (p *auditPlugin) OnStartup(pctx plugintypes.Context) error {
 	if p.started {
@@ -81,6 +87,10 @@ func (p *auditPlugin) OnConnect(pctx plugintypes.Context) error {
 			return fmt.Errorf("failed persisting session to store, reason=%v", err)
 		}
 	}
+	connType := pb.ToConnectionType(pctx.ConnectionType, pctx.ConnectionSubType)
+	if tracker := statement.NewTracker(connType); tracker != nil {
+		p.trackerStore.Set(pctx.SID, tracker)
+	}
 	p.mu = sync.RWMutex{}
 	memorySessionStore.Set(pctx.SID, pctx.AgentID)
 	return nil
@@ -100,12 +110,24 @@ func (p *auditPlugin) OnReceive(pctx plugintypes.Context, pkt *pb.Packet) (*plug
 			memorySessionStore.Del(pctx.SID)
 		}
 	case pbclient.PGConnectionWrite,
-		pbclient.MySQLConnectionWrite,
-		pbclient.MongoDBConnectionWrite:
+		pbclient.MySQLConnectionWrite:
+		if err := p.writeStatements(pctx.SID, pkt); err != nil {
+			return nil, err
+		}
 		if len(eventMetadata) > 0 {
 			return nil, p.writeOnReceive(pctx.SID, eventlogv1.OutputType, nil, eventMetadata)
 		}
+	case pbclient.MongoDBConnectionWrite,
+		pbclient.RedisConnectionWrite:
+		if len(eventMetadata) > 0 {
+			return nil, p.writeOnReceive(pctx.SID, eventlogv1.OutputType, nil, eventMetadata)
+		}
+	case pbagent.TCPConnectionClose, pbclient.TCPConnectionClose:
+		if tracker, ok := p.trackerStore.Get(pctx.SID).(*statement.Tracker); ok {
+			tracker.CloseConnection(string(pkt.Spec[pb.SpecClientConnectionID]))
+		}
 	case pbagent.PGConnectionWrite:
+		p.trackClientStatements(pctx.SID, pkt)
 		isSimpleQuery, queryBytes, err := pgtypes.SimpleQueryContent(pkt.Payload)
 		if !isSimpleQuery {
 			break
@@ -116,6 +138,7 @@ func (p *auditPlugin) OnReceive(pctx plugintypes.Context, pkt *pb.Packet) (*plug
 		}
 		return nil, p.writeOnReceive(pctx.SID, eventlogv1.InputType, queryBytes, eventMetadata)
 	case pbagent.MySQLConnectionWrite:
+		p.trackClientStatements(pctx.SID, pkt)
 		if queryBytes := decodeMySQLCommandQuery(pkt.Payload); queryBytes != nil {
 			return nil, p.writeOnReceive(pctx.SID, eventlogv1.InputType, queryBytes, eventMetadata)
 		}
@@ -142,6 +165,14 @@ func (p *auditPlugin) OnReceive(pctx plugintypes.Context, pkt *pb.Packet) (*plug
 		if decJSONPayload != nil {
 			return nil, p.writeOnReceive(pctx.SID, eventlogv1.InputType, decJSONPayload, eventMetadata)
 		}
+	case pbagent.RedisConnectionWrite:
+		commands, err := redistypes.DecodeCommandsText(pkt.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed decoding redis commands: %v", err)
+		}
+		if len(commands) > 0 {
+			return nil, p.writeOnReceive(pctx.SID, eventlogv1.InputType, commands, eventMetadata)
+		}
 	case pbclient.WriteStdout,
 		pbclient.WriteStderr:
 		err := p.writeOnReceive(pctx.SID, eventlogv1.OutputType, pkt.Payload, eventMetadata)
@@ -149,6 +180,9 @@ func (p *auditPlugin) OnReceive(pctx plugintypes.Context, pkt *pb.Packet) (*plug
 			log.Warnf("failed writing agent packet response, err=%v", err)
 		}
 		return nil, nil
+	case pbagent.TerminalResizeTTY:
+		// the size of the terminal allows replaying the session
+		return nil, p.writeOnReceive(pctx.SID, eventlogv1.ResizeType, pkt.Payload, eventMetadata)
 	case pbagent.ExecWriteStdin,
 		pbagent.TerminalWriteStdin,
 		pbagent.TCPConnectionWrite:
@@ -180,6 +214,7 @@ func (p *auditPlugin) OnDisconnect(pctx plugintypes.Context, err error) error {
 func (p *auditPlugin) closeSession(pctx plugintypes.Context, err error) {
 	log.With("sid", pctx.SID, "origin", pctx.ClientOrigin, "verb", pctx.ClientVerb).
 		Infof("closing session, reason=%v", err)
+	p.trackerStore.Del(pctx.SID)
 	go func() {
 		defer memorySessionStore.Del(pctx.SID)
 		if err := p.writeOnClose(pctx, err); err != nil {
@@ -191,6 +226,33 @@ func (p *auditPlugin) closeSession(pctx plugintypes.Context, err error) {
 
 func (p *auditPlugin) OnShutdown() {}
 
+func (p *auditPlugin) trackClientStatements(sid string, pkt *pb.Packet) {
+	if tracker, ok := p.trackerStore.Get(sid).(*statement.Tracker); ok {
+		tracker.ClientWrite(string(pkt.Spec[pb.SpecClientConnectionID]), pkt.Payload)
+	}
+}
+
+// writeStatements writes the statements completed by the response of the database server
+func (p *auditPlugin) writeStatements(sid string, pkt *pb.Packet) error {
+	tracker, ok := p.trackerStore.Get(sid).(*statement.Tracker)
+	if !ok {
+		return nil
+	}
+	for _, rec := range tracker.ServerWrite(string(pkt.Spec[pb.SpecClientConnectionID]), pkt.Payload) {
+		ev := eventlogv1.NewStatement(rec.StartTime, []byte(rec.Statement), eventlogv1.StatementInfo{
+			ConnectionID: rec.ConnectionID,
+			EndTime:      rec.EndTime,
+			Rows:         rec.Rows,
+			ErrorCode:    rec.ErrorCode,
+			ErrorMessage: rec.ErrorMessage,
+		})
+		if err := p.writeEvent(sid, ev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func parseSpecAsEventMetadata(pkt *pb.Packet) map[string][]byte {
 	if dataMaskingInfo, ok := pkt.Spec[spectypes.DataMaskingInfoKey]; ok {
 		return map[string][]byte{spectypes.DataMaskingInfoKey: dataMaskingInfo}
@@ -14,8 +14,10 @@ import (
 	pb "github.com/hoophq/hoop/common/proto"
 	"github.com/hoophq/hoop/common/proto/spectypes"
 	"github.com/hoophq/hoop/gateway/api/openapi"
+	"github.com/hoophq/hoop/gateway/appconfig"
 	"github.com/hoophq/hoop/gateway/models"
 	eventlogv1 "github.com/hoophq/hoop/gateway/session/eventlog/v1"
+	"github.com/hoophq/hoop/gateway/session/integrity"
 	sessionwal "github.com/hoophq/hoop/gateway/session/wal"
 	plugintypes "github.com/hoophq/hoop/gateway/transport/plugins/types"
 )
@@ -32,6 +34,17 @@ type walLogRWMutex struct {
 	log        *sessionwal.WalLog
 	mu         sync.RWMutex
 	folderName string
+	// chain links the event logs written to the wal
+	chain *eventlogv1.Chain
+}
+
+// write links the event log to the chain before writing it to the wal
+func (w *walLogRWMutex) write(ev *eventlogv1.EventLog) error {
+	data, err := w.chain.Link(ev)
+	if err != nil {
+		return err
+	}
+	return w.log.Write(data)
 }
 
 func (p *auditPlugin) writeOnConnect(pctx plugintypes.Context) error {
@@ -51,7 +64,11 @@ func (p *auditPlugin) writeOnConnect(pctx plugintypes.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed opening wal file, err=%v", err)
 	}
-	p.walSessionStore.Set(pctx.SID, &walLogRWMutex{walog, sync.RWMutex{}, walFolder})
+	p.walSessionStore.Set(pctx.SID, &walLogRWMutex{
+		log:        walog,
+		folderName: walFolder,
+		chain:      eventlogv1.NewChain(integrity.Seed(pctx.OrgID, pctx.SID)),
+	})
 	return nil
 }
 
@@ -63,7 +80,17 @@ func (p *auditPlugin) writeOnReceive(sessionID string, eventType eventlogv1.Even
 	}
 	walogm.mu.Lock()
 	defer walogm.mu.Unlock()
-	return walogm.log.Write(eventlogv1.New(time.Now().UTC(), eventType, event, metadata))
+	return walogm.write(eventlogv1.New(time.Now().UTC(), eventType, event, metadata))
+}
+
+func (p *auditPlugin) writeEvent(sessionID string, ev *eventlogv1.EventLog) error {
+	walogm, ok := p.walSessionStore.Get(sessionID).(*walLogRWMutex)
+	if !ok {
+		return fmt.Errorf("failed obtaining write ahead log for session %v", sessionID)
+	}
+	walogm.mu.Lock()
+	defer walogm.mu.Unlock()
+	return walogm.write(ev)
 }
 
 func (p *auditPlugin) dropWalLog(sid string) {
@@ -91,7 +118,7 @@ func (p *auditPlugin) writeOnClose(pctx plugintypes.Context, errMsg error) error
 	// we could add an attribute to have the last message
 	// propagated as metadata instead inside the stream
 	if errMsg != nil && errMsg != io.EOF {
-		err := walogm.log.Write(eventlogv1.New(time.Now().UTC(), eventlogv1.ErrorType, []byte(errMsg.Error()), nil))
+		err := walogm.write(eventlogv1.New(time.Now().UTC(), eventlogv1.ErrorType, []byte(errMsg.Error()), nil))
 		if err != nil {
 			log.With("sid", pctx.SID).Warnf("failed writing end error message, err=%v", err)
 		}
@@ -105,12 +132,33 @@ func (p *auditPlugin) writeOnClose(pctx plugintypes.Context, errMsg error) error
 			pctx.SID, wh.SessionID)
 	}
 	var rawJSONBlobStream string
+	var statements []models.SessionStatement
+	// the chain of the wal is verified while reading it, a broken chain
+	// means the logs were changed or lost before the session is sealed
+	walChain := eventlogv1.NewChain(integrity.Seed(wh.OrgID, wh.SessionID))
+	walVerified := true
 	metrics := newSessionMetric()
 	metrics.Truncated, err = walogm.log.ReadFull(func(data []byte) error {
+		if walVerified {
+			if err := walChain.Verify(data); err != nil {
+				log.With("sid", pctx.SID).Warnf("failed verifying wal chain, reason=%v", err)
+				walVerified = false
+			}
+		}
 		ev, err := eventlogv1.Decode(data)
 		if err != nil {
 			return err
 		}
+		// statements are stored apart from the event stream
+		if ev.EventType == eventlogv1.StatementType {
+			stmt, err := parseSessionStatement(wh.OrgID, wh.SessionID, ev)
+			if err != nil {
+				log.With("sid", pctx.SID).Warnf("failed decoding statement event, reason=%v", err)
+				return nil
+			}
+			statements = append(statements, *stmt)
+			return nil
+		}
 		if infoEnc := ev.GetMetadata(spectypes.DataMaskingInfoKey); infoEnc != nil {
 			dataMaskingInfo, err := spectypes.Decode(infoEnc)
 			if err != nil {
@@ -175,6 +223,11 @@ func (p *auditPlugin) writeOnClose(pctx plugintypes.Context, errMsg error) error
 	if err != nil {
 		log.With("sid", pctx.SID).Warnf("failed parsing session metrics to map, reason=%v", err)
 	}
+	seal, err := integrity.Seal(wh.OrgID, wh.SessionID, json.RawMessage(rawJSONBlobStream),
+		walVerified, appconfig.Get().SessionSigningKey(), endDate)
+	if err != nil {
+		log.With("sid", pctx.SID).Warnf("failed sealing session event stream, reason=%v", err)
+	}
 	err = models.UpdateSessionEventStream(models.SessionDone{
 		ID:         wh.SessionID,
 		OrgID:      wh.OrgID,
@@ -183,12 +236,14 @@ func (p *auditPlugin) writeOnClose(pctx plugintypes.Context, errMsg error) error
 		Status:     string(openapi.SessionStatusDone),
 		ExitCode:   parseExitCodeFromErr(errMsg),
 		EndSession: &endDate,
+		Statements: statements,
+		Integrity:  seal,
 	})
 	log.With("sid", pctx.SID, "origin", pctx.ClientOrigin, "verb", pctx.ClientVerb).
 		Infof("finished persisting session to store, err=%v", errMsg)
 
 	if err != nil {
-		_ = walogm.log.Write(eventlogv1.NewCommitError(endDate, err.Error()))
+		_ = walogm.write(eventlogv1.NewCommitError(endDate, err.Error()))
 	} else {
 		if err := os.RemoveAll(walogm.folderName); err != nil {
 			log.Errorf("failed removing wal file %q, err=%v", walogm.folderName, err)
@@ -197,6 +252,28 @@ func (p *auditPlugin) writeOnClose(pctx plugintypes.Context, errMsg error) error
 	return err
 }
 
+func parseSessionStatement(orgID, sid string, ev *eventlogv1.EventLog) (*models.SessionStatement, error) {
+	info, err := ev.StatementInfo()
+	if err != nil {
+		return nil, err
+	}
+	stmt := &models.SessionStatement{
+		OrgID:        orgID,
+		SessionID:    sid,
+		ConnectionID: info.ConnectionID,
+		Statement:    string(ev.Payload),
+		StartedAt:    ev.EventTime,
+		EndedAt:      info.EndTime,
+	}
+	if info.Rows >= 0 {
+		stmt.Rows = &info.Rows
+	}
+	if info.ErrorCode != "" || info.ErrorMessage != "" {
+		stmt.ErrorCode, stmt.ErrorMessage = &info.ErrorCode, &info.ErrorMessage
+	}
+	return stmt, nil
+}
+
 func (p *auditPlugin) truncateTCPEventStream(eventStream []byte, connType string) []byte {
 	if len(eventStream) > 5000 && connType == pb.ConnectionTypeTCP.String() {
 		return eventStream[0:5000]
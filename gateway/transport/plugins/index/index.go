@@ -11,6 +11,7 @@ import (
 	pb "github.com/hoophq/hoop/common/proto"
 	pbagent "github.com/hoophq/hoop/common/proto/agent"
 	pbclient "github.com/hoophq/hoop/common/proto/client"
+	"github.com/hoophq/hoop/common/redistypes"
 	"github.com/hoophq/hoop/gateway/indexer"
 	eventlogv0 "github.com/hoophq/hoop/gateway/session/eventlog/v0"
 	"github.com/hoophq/hoop/gateway/storagev2/types"
@@ -70,7 +71,14 @@ func (p *indexPlugin) OnConnect(pctx plugintypes.Context) error {
 
 func (p *indexPlugin) OnReceive(c plugintypes.Context, pkt *pb.Packet) (*plugintypes.ConnectResponse, error) {
 	switch pb.PacketType(pkt.GetType()) {
+	case pbclient.PGConnectionWrite, pbclient.MySQLConnectionWrite:
+		p.trackStatements(c.SID, pkt, true)
+	case pbagent.TCPConnectionClose, pbclient.TCPConnectionClose:
+		p.closeStatementConnection(c.SID, pkt)
+	case pbagent.MySQLConnectionWrite:
+		p.trackStatements(c.SID, pkt, false)
 	case pbagent.PGConnectionWrite:
+		p.trackStatements(c.SID, pkt, false)
 		isSimpleQuery, queryBytes, err := pgtypes.SimpleQueryContent(pkt.Payload)
 		if !isSimpleQuery {
 			break
@@ -94,6 +102,14 @@ func (p *indexPlugin) OnReceive(c plugintypes.Context, pkt *pb.Packet) (*plugint
 				return nil, p.writeOnReceive(c.SID, eventlogv0.InputType, []byte(query))
 			}
 		}
+	case pbagent.RedisConnectionWrite:
+		commands, err := redistypes.DecodeCommandsText(pkt.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("session=%v - failed decoding redis commands, err=%v", c.SID, err)
+		}
+		if len(commands) > 0 {
+			return nil, p.writeOnReceive(c.SID, eventlogv0.InputType, commands)
+		}
 	case pbclient.WriteStdout:
 		return nil, p.writeOnReceive(c.SID, eventlogv0.OutputType, pkt.Payload)
 	case pbclient.WriteStderr:
@@ -7,9 +7,11 @@ import (
 	"time"
 
 	"github.com/hoophq/hoop/common/log"
+	pb "github.com/hoophq/hoop/common/proto"
 	"github.com/hoophq/hoop/gateway/indexer"
 	"github.com/hoophq/hoop/gateway/session/eventlog"
 	eventlogv0 "github.com/hoophq/hoop/gateway/session/eventlog/v0"
+	"github.com/hoophq/hoop/gateway/session/statement"
 	sessionwal "github.com/hoophq/hoop/gateway/session/wal"
 	plugintypes "github.com/hoophq/hoop/gateway/transport/plugins/types"
 )
@@ -24,6 +26,10 @@ type walLogRWMutex struct {
 	stdoutSize      int64
 	stdinTruncated  bool
 	stdoutTruncated bool
+	// statements of database connections
+	tracker          *statement.Tracker
+	statements       int64
+	slowestStatement time.Duration
 }
 
 func (p *indexPlugin) writeOnConnect(c plugintypes.Context) error {
@@ -48,10 +54,41 @@ func (p *indexPlugin) writeOnConnect(c plugintypes.Context) error {
 		wlog:       walog,
 		mu:         sync.RWMutex{},
 		folderName: walFolder,
+		tracker:    statement.NewTracker(pb.ToConnectionType(c.ConnectionType, c.ConnectionSubType)),
 	})
 	return nil
 }
 
+// trackStatements decodes the statements of database connections, the server
+// packets are used to compute the statements completed and their duration.
+func (p *indexPlugin) trackStatements(sid string, pkt *pb.Packet, isServerWrite bool) {
+	walogm, ok := p.walSessionStore.Get(sid).(*walLogRWMutex)
+	if !ok || walogm.tracker == nil {
+		return
+	}
+	connectionID := string(pkt.Spec[pb.SpecClientConnectionID])
+	if !isServerWrite {
+		walogm.tracker.ClientWrite(connectionID, pkt.Payload)
+		return
+	}
+	records := walogm.tracker.ServerWrite(connectionID, pkt.Payload)
+	if len(records) == 0 {
+		return
+	}
+	walogm.mu.Lock()
+	defer walogm.mu.Unlock()
+	for _, rec := range records {
+		walogm.statements++
+		walogm.slowestStatement = max(walogm.slowestStatement, rec.EndTime.Sub(rec.StartTime))
+	}
+}
+
+func (p *indexPlugin) closeStatementConnection(sid string, pkt *pb.Packet) {
+	if walogm, ok := p.walSessionStore.Get(sid).(*walLogRWMutex); ok && walogm.tracker != nil {
+		walogm.tracker.CloseConnection(string(pkt.Spec[pb.SpecClientConnectionID]))
+	}
+}
+
 func (p *indexPlugin) writeOnReceive(sid string, eventType eventlogv0.EventType, event []byte) error {
 	walLogObj := p.walSessionStore.Get(sid)
 	walogm, ok := walLogObj.(*walLogRWMutex)
@@ -145,6 +182,8 @@ func (p *indexPlugin) indexOnClose(c plugintypes.Context, isError bool) {
 		StartDate:         wh.StartDate.Format(time.RFC3339),
 		EndDate:           endDate.Format(time.RFC3339),
 		Duration:          durationInSecs,
+		Statements:        walogm.statements,
+		SlowestStatement:  walogm.slowestStatement.Milliseconds(),
 	}
 	indexCh := p.indexers.Get(c.OrgID).(chan *indexer.Session)
 	if indexCh == nil {
@@ -1,7 +1,6 @@
 package review
 
 import (
-	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -12,6 +11,7 @@ import (
 	pb "github.com/hoophq/hoop/common/proto"
 	pbagent "github.com/hoophq/hoop/common/proto/agent"
 	pbclient "github.com/hoophq/hoop/common/proto/client"
+	"github.com/hoophq/hoop/gateway/models"
 	pgreview "github.com/hoophq/hoop/gateway/pgrest/review"
 	"github.com/hoophq/hoop/gateway/review"
 	"github.com/hoophq/hoop/gateway/storagev2/types"
@@ -39,7 +39,17 @@ func (p *reviewPlugin) OnReceive(pctx plugintypes.Context, pkt *pb.Packet) (*plu
 	if pkt.Type != pbagent.SessionOpen {
 		return nil, nil
 	}
-	if pctx.OrgLicenseType == license.OSSType {
+	isOSS := pctx.OrgLicenseType == license.OSSType
+	if reason, ok := pkt.Spec[pb.SpecBreakGlassReason]; ok {
+		// break-glass skips the review, not the restrictions of the license
+		if isOSS {
+			if err := validateOSSVerb(pctx); err != nil {
+				return nil, err
+			}
+		}
+		return p.grantBreakGlassAccess(pctx, string(reason))
+	}
+	if isOSS {
 		return p.onReceiveOSS(pctx, pkt)
 	}
 
@@ -71,6 +81,10 @@ func (p *reviewPlugin) OnReceive(pctx plugintypes.Context, pkt *pb.Packet) (*plu
 		return nil, nil
 	}
 
+	if resp, err := grantScheduledAccess(pctx); resp != nil || err != nil {
+		return resp, err
+	}
+
 	jitr, err := pgreview.New().FetchJit(pctx, pctx.UserID, pctx.ConnectionID)
 	if err != nil {
 		return nil, plugintypes.InternalErr("failed listing time based reviews", err)
@@ -83,8 +97,7 @@ func (p *reviewPlugin) OnReceive(pctx plugintypes.Context, pkt *pb.Packet) (*plu
 			log.With("sid", pctx.SID, "id", jitr.Id, "user", jitr.CreatedBy, "org", pctx.OrgID,
 				"revoke-at", jitr.RevokeAt.Format(time.RFC3339),
 				"duration", fmt.Sprintf("%vm", jitr.AccessDuration.Minutes())).Infof("jit access granted")
-			newCtx, _ := context.WithTimeout(pctx.Context, jitr.AccessDuration)
-			return &plugintypes.ConnectResponse{Context: newCtx, ClientPacket: nil}, nil
+			return &plugintypes.ConnectResponse{Context: nil, ClientPacket: nil, ReviewID: jitr.Id, RevokeAt: jitr.RevokeAt}, nil
 		default:
 			return nil, err
 		}
@@ -121,6 +134,12 @@ func (p *reviewPlugin) OnReceive(pctx plugintypes.Context, pkt *pb.Packet) (*plu
 		})
 	}
 
+	// the policy is kept in the review, changes in the connection don't affect pending reviews
+	policy, err := models.GetConnectionReviewPolicy(pctx.OrgID, pctx.ConnectionID)
+	if err != nil {
+		return nil, plugintypes.InternalErr("failed obtaining the review policy of the connection", err)
+	}
+
 	var inputClientArgs []string
 	if encInputClientArgs, ok := pkt.Spec[pb.SpecClientExecArgsKey]; ok {
 		if err := pb.GobDecodeInto(encInputClientArgs, &inputClientArgs); err != nil {
@@ -152,6 +171,7 @@ func (p *reviewPlugin) OnReceive(pctx plugintypes.Context, pkt *pb.Packet) (*plu
 		Status:           types.ReviewStatusPending,
 		ReviewGroupsIds:  groups,
 		ReviewGroupsData: reviewGroups,
+		Policy:           policy,
 	}
 
 	if !isJitReview {
@@ -188,6 +208,58 @@ func (p *reviewPlugin) OnShutdown()                                            {
 // it will allow applying special logic for these cases
 func (p *reviewPlugin) setSpecReview(pkt *pb.Packet) { pkt.Spec[pb.SpecHasReviewKey] = []byte("true") }
 
+// grantScheduledAccess admits the session when the user has an approved
+// scheduled review with an access window open to the connection
+func grantScheduledAccess(pctx plugintypes.Context) (*plugintypes.ConnectResponse, error) {
+	schedr, err := pgreview.New().FetchScheduled(pctx, pctx.UserID, pctx.ConnectionID, time.Now().UTC())
+	if err != nil {
+		return nil, plugintypes.InternalErr("failed listing scheduled reviews", err)
+	}
+	if schedr == nil {
+		return nil, nil
+	}
+	log.With("sid", pctx.SID, "id", schedr.Id, "user", schedr.ReviewOwner.Email, "org", pctx.OrgID,
+		"revoke-at", schedr.RevokeAt.Format(time.RFC3339)).Infof("scheduled access granted")
+	return &plugintypes.ConnectResponse{Context: nil, ClientPacket: nil, ReviewID: schedr.Id, RevokeAt: schedr.RevokeAt}, nil
+}
+
+// grantBreakGlassAccess admits the session without waiting for the review,
+// the reviewers are notified and must acknowledge the access afterwards
+func (p *reviewPlugin) grantBreakGlassAccess(pctx plugintypes.Context, reason string) (*plugintypes.ConnectResponse, error) {
+	if len(pctx.PluginConnectionConfig) == 0 {
+		err := fmt.Errorf("missing approval groups for connection")
+		return nil, plugintypes.InternalErr(err.Error(), err)
+	}
+	policy, err := models.GetConnectionReviewPolicy(pctx.OrgID, pctx.ConnectionID)
+	if err != nil {
+		return nil, plugintypes.InternalErr("failed obtaining the review policy of the connection", err)
+	}
+	rev, err := p.reviewSvc.CreateBreakGlass(pctx, review.BreakGlassAccess{
+		SessionID: pctx.SID,
+		Owner: types.ReviewOwner{
+			Id:      pctx.UserID,
+			Name:    pctx.UserName,
+			Email:   pctx.UserEmail,
+			SlackID: pctx.UserSlackID,
+		},
+		Connection: types.ReviewConnection{Id: pctx.ConnectionID, Name: pctx.ConnectionName},
+		Reviewers:  pctx.PluginConnectionConfig,
+		Policy:     policy,
+		Reason:     reason,
+	})
+	switch err {
+	case nil:
+	case review.ErrBreakGlassDisabled, review.ErrBreakGlassRateLimited, review.ErrBreakGlassReason:
+		return nil, plugintypes.InvalidArgument("%v", err)
+	default:
+		return nil, plugintypes.InternalErr("failed creating break-glass review", err)
+	}
+	if err := models.MarkSessionBreakGlass(pctx.OrgID, pctx.SID); err != nil {
+		log.With("sid", pctx.SID, "id", rev.Id).Warnf("failed flagging break-glass session, reason=%v", err)
+	}
+	return &plugintypes.ConnectResponse{Context: nil, ClientPacket: nil, ReviewID: rev.Id, RevokeAt: rev.RevokeAt}, nil
+}
+
 var errJitExpired = errors.New("jit expired")
 
 func validateJit(jit *types.Review, t time.Time) error {
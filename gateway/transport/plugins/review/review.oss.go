@@ -1,7 +1,6 @@
 package review
 
 import (
-	"context"
 	"fmt"
 	"time"
 
@@ -9,16 +8,29 @@ import (
 	"github.com/hoophq/hoop/common/log"
 	pb "github.com/hoophq/hoop/common/proto"
 	pbclient "github.com/hoophq/hoop/common/proto/client"
+	"github.com/hoophq/hoop/gateway/models"
 	pgreview "github.com/hoophq/hoop/gateway/pgrest/review"
 	"github.com/hoophq/hoop/gateway/review"
 	"github.com/hoophq/hoop/gateway/storagev2/types"
 	plugintypes "github.com/hoophq/hoop/gateway/transport/plugins/types"
 )
 
-func (r *reviewPlugin) onReceiveOSS(pctx plugintypes.Context, pkt *pb.Packet) (*plugintypes.ConnectResponse, error) {
+// validateOSSVerb allows only the connect verb for connections with review in the open source version
+func validateOSSVerb(pctx plugintypes.Context) error {
 	if pctx.ClientVerb != pb.ClientVerbConnect {
-		return nil, fmt.Errorf(`Accessing a connection with review from the web requires an Enterprise plan. Contact us for instant access to a 15-day trial license - no strings attached. If you want to continue using the OSS version, you can access your connection from the CLI or the Hoop desktop app. Check our docs for more information: https://hoop.dev/docs/getting-started/cli`)
+		return fmt.Errorf(`Accessing a connection with review from the web requires an Enterprise plan. Contact us for instant access to a 15-day trial license - no strings attached. If you want to continue using the OSS version, you can access your connection from the CLI or the Hoop desktop app. Check our docs for more information: https://hoop.dev/docs/getting-started/cli`)
+	}
+	return nil
+}
+
+func (r *reviewPlugin) onReceiveOSS(pctx plugintypes.Context, pkt *pb.Packet) (*plugintypes.ConnectResponse, error) {
+	if err := validateOSSVerb(pctx); err != nil {
+		return nil, err
 	}
+	if resp, err := grantScheduledAccess(pctx); resp != nil || err != nil {
+		return resp, err
+	}
+
 	jitr, err := pgreview.New().FetchJit(pctx, pctx.UserID, pctx.ConnectionID)
 	if err != nil {
 		return nil, plugintypes.InternalErr("failed listing time based reviews", err)
@@ -31,8 +43,7 @@ func (r *reviewPlugin) onReceiveOSS(pctx plugintypes.Context, pkt *pb.Packet) (*
 			log.With("sid", pctx.SID, "id", jitr.Id, "user", jitr.CreatedBy, "org", pctx.OrgID,
 				"revoke-at", jitr.RevokeAt.Format(time.RFC3339),
 				"duration", fmt.Sprintf("%vm", jitr.AccessDuration.Minutes())).Infof("jit access granted")
-			newCtx, _ := context.WithTimeout(pctx.Context, jitr.AccessDuration)
-			return &plugintypes.ConnectResponse{Context: newCtx, ClientPacket: nil}, nil
+			return &plugintypes.ConnectResponse{Context: nil, ClientPacket: nil, ReviewID: jitr.Id, RevokeAt: jitr.RevokeAt}, nil
 		default:
 			return nil, err
 		}
@@ -66,6 +77,12 @@ func (r *reviewPlugin) onReceiveOSS(pctx plugintypes.Context, pkt *pb.Packet) (*
 		})
 	}
 
+	// the policy is kept in the review, changes in the connection don't affect pending reviews
+	policy, err := models.GetConnectionReviewPolicy(pctx.OrgID, pctx.ConnectionID)
+	if err != nil {
+		return nil, plugintypes.InternalErr("failed obtaining the review policy of the connection", err)
+	}
+
 	var inputClientArgs []string
 	if encInputClientArgs, ok := pkt.Spec[pb.SpecClientExecArgsKey]; ok {
 		if err := pb.GobDecodeInto(encInputClientArgs, &inputClientArgs); err != nil {
@@ -98,6 +115,7 @@ func (r *reviewPlugin) onReceiveOSS(pctx plugintypes.Context, pkt *pb.Packet) (*
 		Status:           types.ReviewStatusPending,
 		ReviewGroupsIds:  groups,
 		ReviewGroupsData: reviewGroups,
+		Policy:           policy,
 	}
 	log.With("session", pctx.SID, "id", newRev.Id, "user", pctx.UserID, "org", pctx.OrgID,
 		"type", review.ReviewTypeJit, "duration", fmt.Sprintf("%vm", accessDuration.Minutes())).
@@ -5,7 +5,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hoophq/hoop/common/license"
+	pb "github.com/hoophq/hoop/common/proto"
+	pbagent "github.com/hoophq/hoop/common/proto/agent"
 	"github.com/hoophq/hoop/gateway/storagev2/types"
+	plugintypes "github.com/hoophq/hoop/gateway/transport/plugins/types"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -63,3 +67,15 @@ func TestValidateJit(t *testing.T) {
 		})
 	}
 }
+
+func TestOnReceiveBreakGlassOSS(t *testing.T) {
+	pctx := plugintypes.Context{OrgLicenseType: license.OSSType, ClientVerb: pb.ClientVerbExec}
+	pkt := &pb.Packet{
+		Type: pbagent.SessionOpen,
+		Spec: map[string][]byte{pb.SpecBreakGlassReason: []byte("production is down")},
+	}
+	resp, err := New(nil, "").OnReceive(pctx, pkt)
+	assert.Nil(t, resp)
+	assert.Equal(t, validateOSSVerb(pctx), err)
+	assert.ErrorContains(t, err, "requires an Enterprise plan")
+}
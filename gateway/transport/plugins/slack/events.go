@@ -59,12 +59,18 @@ func (p *slackPlugin) processEventResponse(ev *event) {
 	log.With("sid", sid).Infof("performing review, kind=%v, id=%v, status=%s, group=%v",
 		ev.msg.EventKind, ev.msg.ID, ev.msg.Status, ev.msg.GroupName)
 	switch ev.msg.EventKind {
-	case slackservice.EventKindOneTime, slackservice.EventKindJit:
+	case slackservice.EventKindOneTime, slackservice.EventKindJit, slackservice.EventKindBreakGlass:
 		status := types.ReviewStatusRejected
 		if ev.msg.Status == "approved" {
 			status = types.ReviewStatusApproved
 		}
 		p.performReview(ev, userContext, status)
+	case slackservice.EventKindJitExtension:
+		status := types.ReviewStatusRejected
+		if ev.msg.Status == "approved" {
+			status = types.ReviewStatusApproved
+		}
+		p.performExtensionReview(ev, userContext, status)
 	default:
 		log.With("sid", sid).Warnf("received unknown event kind %v", ev.msg.EventKind)
 	}
@@ -72,21 +78,48 @@ func (p *slackPlugin) processEventResponse(ev *event) {
 
 func (p *slackPlugin) performReview(ev *event, ctx *storagev2.Context, status types.ReviewStatus) {
 	rev, err := p.reviewSvc.Review(ctx, ev.msg.ID, status)
+	p.updateReviewMessage(ev, rev, err, func(rev *types.Review) (types.ReviewStatus, []types.ReviewGroup) {
+		return rev.Status, rev.ReviewGroupsData
+	})
+}
+
+func (p *slackPlugin) performExtensionReview(ev *event, ctx *storagev2.Context, status types.ReviewStatus) {
+	rev, err := p.reviewSvc.ReviewExtension(ctx, ev.msg.ID, status)
+	p.updateReviewMessage(ev, rev, err, func(rev *types.Review) (types.ReviewStatus, []types.ReviewGroup) {
+		return rev.Extension.Status, rev.Extension.ReviewGroupsData
+	})
+}
+
+// updateReviewMessage updates the slack message with the result of the review,
+// the statusFn returns the status and groups of the reviewed resource.
+func (p *slackPlugin) updateReviewMessage(ev *event, rev *types.Review, err error,
+	statusFn func(rev *types.Review) (types.ReviewStatus, []types.ReviewGroup)) {
 	var msg string
 	switch err {
 	case review.ErrNotFound:
 		msg = err.Error()
 	case review.ErrWrongState:
 		msg = "The review is already approved or rejected"
+		if ev.msg.EventKind == slackservice.EventKindJitExtension {
+			msg = "The extension is already approved or rejected, or the access has been revoked"
+		}
 	case review.ErrSelfApproval:
 		msg = "Unable to self approval review, contact another member of you team to approve it"
 	case review.ErrNotEligible:
 		msg = "You're not eligible to approve/reject this review"
+	case review.ErrAlreadyReviewed:
+		msg = "You have already approved this review, it requires the approval of other members"
 	case nil:
-		isApproved := rev.Status == types.ReviewStatusApproved
+		// the group requires more approvals, keep the actions for the other members
+		status, groups := statusFn(rev)
+		if status == types.ReviewStatusPending && isGroupPending(groups, ev.msg.GroupName) {
+			msg = "Your approval was registered, the review requires the approval of other members of this group"
+			break
+		}
+		isApproved := status == types.ReviewStatusApproved
 		err = ev.ss.UpdateMessage(ev.msg, isApproved)
-		log.With("sid", ev.msg.SessionID).Infof("review id=%s, isapproved=%v, status=%v, update-msg-err=%v",
-			ev.msg.ID, isApproved, rev.Status, err)
+		log.With("sid", ev.msg.SessionID).Infof("review id=%s, kind=%v, isapproved=%v, status=%v, update-msg-err=%v",
+			ev.msg.ID, ev.msg.EventKind, isApproved, status, err)
 		return
 	default:
 		log.With("sid", ev.msg.SessionID).Warnf("failed reviewing, id=%s, internal error=%v",
@@ -97,3 +130,12 @@ func (p *slackPlugin) performReview(ev *event, ctx *storagev2.Context, status ty
 		log.With("sid", ev.msg.SessionID).Warnf("failed updating slack review, reason=%v", err)
 	}
 }
+
+func isGroupPending(groups []types.ReviewGroup, groupName string) bool {
+	for _, g := range groups {
+		if g.Group == groupName {
+			return g.Status == types.ReviewStatusPending
+		}
+	}
+	return false
+}
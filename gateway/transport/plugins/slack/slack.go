@@ -4,10 +4,12 @@ import (
 	"encoding/base64"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/hoophq/hoop/common/log"
 	pb "github.com/hoophq/hoop/common/proto"
 	pbagent "github.com/hoophq/hoop/common/proto/agent"
+	"github.com/hoophq/hoop/gateway/models"
 	"github.com/hoophq/hoop/gateway/pgrest"
 	pgorgs "github.com/hoophq/hoop/gateway/pgrest/orgs"
 	pgplugins "github.com/hoophq/hoop/gateway/pgrest/plugins"
@@ -182,6 +184,151 @@ func SendApprovedMessage(orgID, slackID, sid, apiURL string) {
 	}
 }
 
+// SendReviewMessage sends the message of a pending review to its groups
+// using the channels configured for the connection of the review.
+func SendReviewMessage(rev *types.Review, apiURL string) {
+	slacksvc := getSlackServiceInstance(rev.OrgId)
+	if slacksvc == nil {
+		return
+	}
+	sreq := newReviewMessage(rev, apiURL)
+	if sreq == nil {
+		return
+	}
+	sreq.ApprovalGroups = parseGroups(rev.ReviewGroupsData)
+	if rev.AccessDuration > 0 {
+		sreq.SessionTime = &rev.AccessDuration
+	}
+	if len(sreq.ApprovalGroups) == 0 || len(sreq.ApprovalGroups) >= slackMaxButtons {
+		log.With("sid", rev.Session).Infof("no review message to process, approval-groups=%v/%v",
+			len(sreq.ApprovalGroups), slackMaxButtons)
+		return
+	}
+	log.With("sid", rev.Session).Infof("sending slack review message, conn=%v", rev.Connection.Name)
+	result := slacksvc.SendMessageReview(sreq)
+	log.With("sid", rev.Session).Infof("review slack message sent, %v", result)
+}
+
+// SendEscalationMessage sends the review message to the escalation group
+// using the channels configured for the connection of the review.
+func SendEscalationMessage(rev *types.Review, group, apiURL string) {
+	slacksvc := getSlackServiceInstance(rev.OrgId)
+	if slacksvc == nil {
+		return
+	}
+	sreq := newReviewMessage(rev, apiURL)
+	if sreq == nil {
+		return
+	}
+	sreq.ApprovalGroups = []string{group}
+	sreq.Escalated = true
+	if rev.AccessDuration > 0 {
+		sreq.SessionTime = &rev.AccessDuration
+	}
+	log.With("sid", rev.Session).Infof("sending slack escalation message, conn=%v, group=%v", rev.Connection.Name, group)
+	result := slacksvc.SendMessageReview(sreq)
+	log.With("sid", rev.Session).Infof("escalation slack message sent, %v", result)
+}
+
+// SendExtensionMessage sends the message of a jit extension request to the groups
+// of the review using the channels configured for the connection of the review.
+func SendExtensionMessage(rev *types.Review, apiURL string) {
+	slacksvc := getSlackServiceInstance(rev.OrgId)
+	if slacksvc == nil || rev.Extension == nil {
+		return
+	}
+	sreq := newReviewMessage(rev, apiURL)
+	if sreq == nil {
+		return
+	}
+	for _, g := range rev.Extension.ReviewGroupsData {
+		sreq.ApprovalGroups = append(sreq.ApprovalGroups, g.Group)
+	}
+	extensionTime := time.Duration(rev.Extension.AccessDurationSec) * time.Second
+	sreq.SessionTime = &extensionTime
+	sreq.Extension = true
+	log.With("sid", rev.Session).Infof("sending slack extension message, conn=%v, duration=%v", rev.Connection.Name, extensionTime)
+	result := slacksvc.SendMessageReview(sreq)
+	log.With("sid", rev.Session).Infof("extension slack message sent, %v", result)
+}
+
+// SendScheduledMessage sends the message of a scheduled access request to the groups
+// of the review using the channels configured for the connection of the review.
+func SendScheduledMessage(rev *types.Review, apiURL string) {
+	slacksvc := getSlackServiceInstance(rev.OrgId)
+	if slacksvc == nil || rev.AccessStartAt == nil || rev.RevokeAt == nil {
+		return
+	}
+	sreq := newReviewMessage(rev, apiURL)
+	if sreq == nil {
+		return
+	}
+	sreq.ApprovalGroups = parseGroups(rev.ReviewGroupsData)
+	sreq.SessionTime = &rev.AccessDuration
+	sreq.Scheduled = true
+	sreq.Script = fmt.Sprintf("window: %s - %s\nreason: %s",
+		rev.AccessStartAt.Format(time.RFC1123), rev.RevokeAt.Format(time.RFC1123), rev.AccessReason)
+	if rev.JiraIssueKey != "" {
+		sreq.Script += fmt.Sprintf("\njira issue: %s", rev.JiraIssueKey)
+	}
+	log.With("id", rev.Id).Infof("sending slack scheduled review message, conn=%v", rev.Connection.Name)
+	result := slacksvc.SendMessageReview(sreq)
+	log.With("id", rev.Id).Infof("scheduled review slack message sent, %v", result)
+}
+
+// SendBreakGlassMessage announces a break-glass session to the groups of the review
+// using the channels configured for the connection of the review.
+func SendBreakGlassMessage(rev *types.Review, apiURL string) {
+	slacksvc := getSlackServiceInstance(rev.OrgId)
+	if slacksvc == nil || rev.RevokeAt == nil {
+		return
+	}
+	sreq := newReviewMessage(rev, apiURL)
+	if sreq == nil {
+		return
+	}
+	sreq.ApprovalGroups = parseGroups(rev.ReviewGroupsData)
+	sreq.SessionTime = &rev.AccessDuration
+	sreq.BreakGlass = true
+	sreq.Script = fmt.Sprintf("reason: %s\nrevoke at: %s", rev.AccessReason, rev.RevokeAt.Format(time.RFC1123))
+	log.With("sid", rev.Session, "id", rev.Id).Infof("sending slack break-glass message, conn=%v", rev.Connection.Name)
+	result := slacksvc.SendMessageReview(sreq)
+	log.With("sid", rev.Session, "id", rev.Id).Infof("break-glass slack message sent, %v", result)
+}
+
+// newReviewMessage returns the base message of a review, it returns nil
+// if it fails to obtain the slack plugin.
+func newReviewMessage(rev *types.Review, apiURL string) *slack.MessageReviewRequest {
+	ctx := pgrest.NewOrgContext(rev.OrgId)
+	pl, err := pgplugins.New().FetchOne(ctx, plugintypes.PluginSlackName)
+	if err != nil || pl == nil {
+		log.With("sid", rev.Session).Warnf("failed obtaining slack plugin, err=%v", err)
+		return nil
+	}
+	var slackChannels []string
+	for _, conn := range pl.Connections {
+		if conn.ConnectionID == rev.Connection.Id {
+			slackChannels = conn.Config
+			break
+		}
+	}
+	var connectionType string
+	if conn, _ := models.GetConnectionByNameOrID(rev.OrgId, rev.Connection.Id); conn != nil {
+		connectionType = conn.Type
+	}
+	return &slack.MessageReviewRequest{
+		ID:             rev.Id,
+		Name:           rev.ReviewOwner.Name,
+		Email:          rev.ReviewOwner.Email,
+		Connection:     rev.Connection.Name,
+		ConnectionType: connectionType,
+		Script:         rev.Input,
+		WebappURL:      fmt.Sprintf("%s/reviews/%s", apiURL, rev.Id),
+		SessionID:      rev.Session,
+		SlackChannels:  slackChannels,
+	}
+}
+
 func (p *slackPlugin) OnConnect(pctx plugintypes.Context) error { return nil }
 func (p *slackPlugin) OnReceive(pctx plugintypes.Context, pkt *pb.Packet) (*plugintypes.ConnectResponse, error) {
 	if pkt.Type != pbagent.SessionOpen {
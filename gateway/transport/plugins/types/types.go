@@ -38,6 +38,8 @@ type Context struct {
 	UserEmail      string
 	UserSlackID    string
 	UserGroups     []string
+	// The connections an api token is restricted to
+	TokenConnections []string
 
 	// Connection attributes
 	ConnectionID                        string
@@ -56,6 +58,9 @@ type Context struct {
 	// Plugin attributes
 	PluginConnectionConfig []string
 
+	// The id of the jit review granting access to the session
+	ReviewID string
+
 	// Gateway client attributes
 	ClientVerb   string
 	ClientOrigin string
@@ -82,12 +87,21 @@ type ConnectResponse struct {
 	// This is useful when a plugin needs to intercept the current flow and
 	// send a packet back to client.
 	ClientPacket *pb.Packet
+	// The id of the jit review granting access to the session,
+	// the session is terminated when the review is revoked.
+	ReviewID string
+	// The time when the access to the session ends, the deadline
+	// is pushed out when an extension of the review is approved.
+	RevokeAt *time.Time
 }
 
 func (c Context) GetOrgID() string        { return c.OrgID }
 func (c Context) GetUserID() string       { return c.UserID }
 func (c Context) GetUserGroups() []string { return c.UserGroups }
 func (c Context) IsAdmin() bool           { return slices.Contains(c.UserGroups, types.GroupAdmin) }
+
+// GetTokenConnections returns the connections an api token is restricted to
+func (c Context) GetTokenConnections() []string { return c.TokenConnections }
 func (c *Context) Validate() error {
 	if c.SID == "" ||
 		c.ConnectionID == "" || c.ConnectionName == "" || c.ConnectionType == "" ||
@@ -5,5 +5,11 @@ const (
 	eventSessionCloseType        = "session.close"
 	eventMSTeamsReviewCreateType = "microsoftteams.review.create"
 	EventDBRoleJobFinishedType   = "dbroles.job.finished"
+	EventGuardRailsMatchType     = "guardrails.match"
+	EventReviewEscalatedType     = "review.escalated"
+	EventReviewExtensionType     = "review.extension"
+	EventReviewScheduledType     = "review.scheduled"
+	EventReviewBreakGlassType    = "review.break_glass"
+	EventReviewCreatedType       = "review.created"
 	maxInputSize                 = 10 * 1000 // 10KB
 )
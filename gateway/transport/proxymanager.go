@@ -14,10 +14,12 @@ import (
 	pbagent "github.com/hoophq/hoop/common/proto/agent"
 	pbclient "github.com/hoophq/hoop/common/proto/client"
 	pbgateway "github.com/hoophq/hoop/common/proto/gateway"
+	"github.com/hoophq/hoop/gateway/accesspolicy"
 	"github.com/hoophq/hoop/gateway/analytics"
 	apiconnections "github.com/hoophq/hoop/gateway/api/connections"
 	"github.com/hoophq/hoop/gateway/storagev2/clientstate"
 	"github.com/hoophq/hoop/gateway/storagev2/types"
+	authinterceptor "github.com/hoophq/hoop/gateway/transport/interceptors/auth"
 	plugintypes "github.com/hoophq/hoop/gateway/transport/plugins/types"
 	"github.com/hoophq/hoop/gateway/transport/streamclient"
 	"google.golang.org/grpc/codes"
@@ -150,7 +152,11 @@ func (s *Server) proccessConnectOKAck(stream *streamclient.ProxyStream) error {
 		return stream.ContextCauseError()
 	case req := <-disp.requestCh:
 		log.With("session", pctx.SID).Infof("starting connect phase for %s", req.RequestConnectionName)
-		conn, err := apiconnections.FetchByName(pctx, req.RequestConnectionName)
+		conn, err := apiconnections.FetchByName(pctx, req.RequestConnectionName, accesspolicy.Request{
+			Verb:     pb.ClientVerbConnect,
+			Origin:   pb.ConnectionOriginClientProxyManager,
+			SourceIP: authinterceptor.SourceIP(stream.Context()),
+		})
 		if err != nil {
 			log.Errorf("failed retrieving connection, reason=%v", err)
 			disp.sendResponse(nil, err)
@@ -142,14 +142,15 @@ func (s *Server) Connect(stream pb.Transport_ConnectServer) (err error) {
 		Context: context.Background(),
 		SID:     "",
 
-		OrgID:          gwctx.UserContext.OrgID,
-		OrgName:        gwctx.UserContext.OrgName, // TODO: it's not set when it's a service account
-		OrgLicenseType: licenseType,
-		UserID:         gwctx.UserContext.UserID,
-		UserName:       gwctx.UserContext.UserName,
-		UserEmail:      gwctx.UserContext.UserEmail,
-		UserSlackID:    gwctx.UserContext.SlackID,
-		UserGroups:     gwctx.UserContext.UserGroups,
+		OrgID:            gwctx.UserContext.OrgID,
+		OrgName:          gwctx.UserContext.OrgName, // TODO: it's not set when it's a service account
+		OrgLicenseType:   licenseType,
+		UserID:           gwctx.UserContext.UserID,
+		UserName:         gwctx.UserContext.UserName,
+		UserEmail:        gwctx.UserContext.UserEmail,
+		UserSlackID:      gwctx.UserContext.SlackID,
+		UserGroups:       gwctx.UserContext.UserGroups,
+		TokenConnections: gwctx.UserContext.TokenConnections,
 
 		ConnectionID:                        gwctx.Connection.ID,
 		ConnectionName:                      gwctx.Connection.Name,
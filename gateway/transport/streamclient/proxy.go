@@ -2,7 +2,9 @@ package streamclient
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,6 +12,7 @@ import (
 	"github.com/hoophq/hoop/common/memory"
 	pb "github.com/hoophq/hoop/common/proto"
 	pbagent "github.com/hoophq/hoop/common/proto/agent"
+	pbclient "github.com/hoophq/hoop/common/proto/client"
 	transportext "github.com/hoophq/hoop/gateway/transport/extensions"
 	plugintypes "github.com/hoophq/hoop/gateway/transport/plugins/types"
 	streamtypes "github.com/hoophq/hoop/gateway/transport/streamclient/types"
@@ -46,6 +49,13 @@ func InitProxyMemoryCleanup() {
 var (
 	proxyMaxTimeoutDuration = time.Hour * 48
 	proxyStore              = memory.New()
+
+	ErrAccessExpired   = status.Error(codes.Aborted, "session ended, reached connection duration")
+	ErrAccessRevoked   = errors.New("session terminated, access to the connection has been revoked")
+	ErrUserDeactivated = errors.New("session terminated, the user has been deactivated")
+	ErrUserDeleted     = errors.New("session terminated, the user has been removed")
+	// ErrBreakGlassRejected is returned when the reviewers reject a break-glass session
+	ErrBreakGlassRejected = errors.New("session terminated, the break-glass access has been rejected")
 )
 
 type ProxyStream struct {
@@ -58,6 +68,9 @@ type ProxyStream struct {
 	runtimePlugins []runtimePlugin
 	pluginCtx      *plugintypes.Context
 	stateTime      time.Time
+
+	deadlineMu    sync.Mutex
+	deadlineTimer *time.Timer
 }
 
 func GetProxyStream(sid string) *ProxyStream {
@@ -112,6 +125,25 @@ func (s *ProxyStream) GetMeta(key string) string {
 func (s *ProxyStream) SetPluginContext(fn func(pctx *plugintypes.Context)) { fn(s.pluginCtx) }
 func (s *ProxyStream) PluginContext() plugintypes.Context                  { return *s.pluginCtx }
 
+// SetAccessDeadline terminates the session when the deadline is reached,
+// calling it again replaces the current deadline of the session.
+func (s *ProxyStream) SetAccessDeadline(deadline time.Time) {
+	s.deadlineMu.Lock()
+	defer s.deadlineMu.Unlock()
+	if s.deadlineTimer != nil {
+		s.deadlineTimer.Stop()
+	}
+	s.deadlineTimer = time.AfterFunc(time.Until(deadline), func() { _ = s.Close(ErrAccessExpired) })
+}
+
+func (s *ProxyStream) stopAccessDeadline() {
+	s.deadlineMu.Lock()
+	defer s.deadlineMu.Unlock()
+	if s.deadlineTimer != nil {
+		s.deadlineTimer.Stop()
+	}
+}
+
 func (s *ProxyStream) String() string {
 	return fmt.Sprintf("user=%v,hostname=%v,origin=%v,verb=%v,platform=%v,version=%v,license=%v",
 		s.pluginCtx.UserEmail,
@@ -148,6 +180,7 @@ func (s *ProxyStream) Close(errMsg error) error {
 		},
 	})
 
+	s.stopAccessDeadline()
 	transportext.OnDisconnect(s.pluginCtx.SID)
 	_ = s.PluginExecOnDisconnect(*s.pluginCtx, errMsg)
 	s.cancelFn(errMsg)
@@ -193,6 +226,59 @@ func DisconnectAllProxies(reason error) chan struct{} {
 	return donec
 }
 
+// DisconnectProxiesByReview terminates all the sessions opened with the access granted
+// by a jit review. The reason is sent to the client and recorded in the session.
+// It returns the number of terminated sessions.
+func DisconnectProxiesByReview(reviewID string, reason error) int {
+	return terminateProxies(func(pctx *plugintypes.Context) bool {
+		return reviewID != "" && pctx.ReviewID == reviewID
+	}, reason)
+}
+
+// ExtendProxiesByReview moves the deadline of all the sessions opened with the access
+// granted by a jit review. It returns the number of extended sessions.
+func ExtendProxiesByReview(reviewID string, revokeAt time.Time) (total int) {
+	for sid, obj := range proxyStore.List() {
+		s, _ := obj.(*ProxyStream)
+		if s == nil || reviewID == "" || s.pluginCtx.ReviewID != reviewID {
+			continue
+		}
+		log.With("sid", sid, "user", s.pluginCtx.UserEmail, "connection", s.pluginCtx.ConnectionName).
+			Infof("extending session access until %v", revokeAt.Format(time.RFC3339))
+		s.SetAccessDeadline(revokeAt)
+		total++
+	}
+	return
+}
+
+// DisconnectProxiesByUser terminates all the sessions of a user.
+// The reason is sent to the client and recorded in the session.
+// It returns the number of terminated sessions.
+func DisconnectProxiesByUser(orgID, userID string, reason error) int {
+	return terminateProxies(func(pctx *plugintypes.Context) bool {
+		return pctx.OrgID == orgID && pctx.UserID == userID
+	}, reason)
+}
+
+func terminateProxies(match func(pctx *plugintypes.Context) bool, reason error) (total int) {
+	for sid, obj := range proxyStore.List() {
+		s, _ := obj.(*ProxyStream)
+		if s == nil || !match(s.pluginCtx) {
+			continue
+		}
+		log.With("sid", sid, "user", s.pluginCtx.UserEmail, "connection", s.pluginCtx.ConnectionName).
+			Infof("terminating session, reason=%v", reason)
+		_ = s.Send(&pb.Packet{
+			Type:    pbclient.SessionClose,
+			Spec:    map[string][]byte{pb.SpecGatewaySessionID: []byte(sid)},
+			Payload: []byte(reason.Error()),
+		})
+		_ = s.Close(reason)
+		total++
+	}
+	return
+}
+
 func disconnectProxiesByAgent(pctx plugintypes.Context, errMsg error) {
 	for _, obj := range proxyStore.List() {
 		s, _ := obj.(*ProxyStream)
@@ -0,0 +1,7 @@
+BEGIN;
+
+SET search_path TO private;
+
+ALTER TABLE private.sessions DROP COLUMN guardrails_info;
+
+COMMIT;
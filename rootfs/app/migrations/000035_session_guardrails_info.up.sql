@@ -0,0 +1,7 @@
+BEGIN;
+
+SET search_path TO private;
+
+ALTER TABLE private.sessions ADD COLUMN guardrails_info JSONB NULL;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+SET search_path TO private;
+
+DROP TABLE IF EXISTS session_statements;
+
+COMMIT;
@@ -0,0 +1,22 @@
+BEGIN;
+
+SET search_path TO private;
+
+CREATE TABLE session_statements(
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    org_id UUID NOT NULL REFERENCES orgs (id),
+    session_id UUID NOT NULL,
+    connection_id VARCHAR(255) NOT NULL,
+    statement TEXT NOT NULL,
+    rows BIGINT NULL,
+    error_code VARCHAR(255) NULL,
+    error_message TEXT NULL,
+    started_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP NOT NULL,
+
+    FOREIGN KEY (org_id, session_id) REFERENCES sessions (org_id, id) ON DELETE CASCADE
+);
+
+CREATE INDEX session_statements_session_id_idx ON session_statements (org_id, session_id, started_at);
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+SET search_path TO private;
+
+ALTER TABLE connections DROP COLUMN IF EXISTS review_policy;
+ALTER TABLE reviews DROP COLUMN IF EXISTS policy;
+ALTER TABLE review_groups DROP COLUMN IF EXISTS approvals;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+SET search_path TO private;
+
+ALTER TABLE connections ADD COLUMN review_policy JSONB NULL;
+ALTER TABLE reviews ADD COLUMN policy JSONB NULL;
+ALTER TABLE review_groups ADD COLUMN approvals JSONB NULL;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+SET search_path TO private;
+
+-- the EXPIRED value of enum_reviews_status is kept, postgres doesn't support removing values of an enum
+ALTER TABLE review_groups DROP COLUMN IF EXISTS escalation;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+SET search_path TO private;
+
+ALTER TYPE enum_reviews_status ADD VALUE IF NOT EXISTS 'EXPIRED';
+ALTER TABLE review_groups ADD COLUMN escalation BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMIT;
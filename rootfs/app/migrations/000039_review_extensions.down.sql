@@ -0,0 +1,7 @@
+BEGIN;
+
+SET search_path TO private;
+
+ALTER TABLE reviews DROP COLUMN IF EXISTS extension;
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+SET search_path TO private;
+
+-- the scheduled value of enum_reviews_type is kept, postgres doesn't support removing values of an enum
+ALTER TABLE reviews DROP COLUMN IF EXISTS access_start_at;
+ALTER TABLE reviews DROP COLUMN IF EXISTS access_reason;
+ALTER TABLE reviews DROP COLUMN IF EXISTS jira_issue_key;
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+SET search_path TO private;
+
+ALTER TYPE enum_reviews_type ADD VALUE IF NOT EXISTS 'scheduled';
+ALTER TABLE reviews ADD COLUMN access_start_at TIMESTAMP NULL;
+ALTER TABLE reviews ADD COLUMN access_reason TEXT NULL;
+ALTER TABLE reviews ADD COLUMN jira_issue_key VARCHAR(255) NULL;
+
+COMMIT;
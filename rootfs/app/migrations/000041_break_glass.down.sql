@@ -0,0 +1,8 @@
+BEGIN;
+
+SET search_path TO private;
+
+-- the break_glass value of enum_reviews_type is kept, postgres doesn't support removing values of an enum
+ALTER TABLE sessions DROP COLUMN IF EXISTS break_glass;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+SET search_path TO private;
+
+ALTER TYPE enum_reviews_type ADD VALUE IF NOT EXISTS 'break_glass';
+ALTER TABLE sessions ADD COLUMN break_glass BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMIT;
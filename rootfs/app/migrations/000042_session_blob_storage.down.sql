@@ -0,0 +1,11 @@
+BEGIN;
+
+SET search_path TO private;
+
+-- the payloads stored in object stores are not copied back to postgres
+UPDATE blobs SET blob_stream = '[]'::JSONB WHERE blob_stream IS NULL;
+ALTER TABLE blobs ALTER COLUMN blob_stream SET NOT NULL;
+ALTER TABLE blobs DROP COLUMN IF EXISTS storage_key;
+ALTER TABLE blobs DROP COLUMN IF EXISTS storage_backend;
+
+COMMIT;
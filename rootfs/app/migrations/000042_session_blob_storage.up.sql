@@ -0,0 +1,11 @@
+BEGIN;
+
+SET search_path TO private;
+
+-- the payload of blobs could be stored outside of postgres (e.g.: s3),
+-- in this case blob_stream is empty and the payload is located by the storage key
+ALTER TABLE blobs ADD COLUMN storage_backend TEXT NOT NULL DEFAULT 'postgres';
+ALTER TABLE blobs ADD COLUMN storage_key TEXT NULL;
+ALTER TABLE blobs ALTER COLUMN blob_stream DROP NOT NULL;
+
+COMMIT;
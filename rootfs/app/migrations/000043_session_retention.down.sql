@@ -0,0 +1,11 @@
+BEGIN;
+
+SET search_path TO private;
+
+ALTER TABLE sessions DROP COLUMN IF EXISTS output_purged_at;
+ALTER TABLE sessions DROP COLUMN IF EXISTS stream_purged_at;
+DROP TABLE IF EXISTS retention_purges;
+DROP TABLE IF EXISTS retention_policies;
+DROP TYPE IF EXISTS enum_retention_mode;
+
+COMMIT;
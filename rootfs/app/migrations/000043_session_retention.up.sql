@@ -0,0 +1,38 @@
+BEGIN;
+
+SET search_path TO private;
+
+CREATE TYPE enum_retention_mode AS ENUM ('event_stream', 'output', 'all');
+
+CREATE TABLE retention_policies(
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    org_id UUID NOT NULL REFERENCES orgs (id),
+    -- the policy applies to all connections of the organization when it's empty
+    connection_name VARCHAR(128) NULL,
+    mode enum_retention_mode NOT NULL,
+    retention_days INT NOT NULL,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX retention_policies_org_id_connection_mode_idx ON retention_policies (org_id, COALESCE(connection_name, ''), mode);
+
+CREATE TABLE retention_purges(
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    org_id UUID NOT NULL REFERENCES orgs (id),
+    -- the policy could be removed after the purge
+    policy_id UUID NULL,
+    session_id UUID NOT NULL,
+    connection VARCHAR(128) NOT NULL,
+    mode enum_retention_mode NOT NULL,
+    session_created_at TIMESTAMP NOT NULL,
+    purged_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX retention_purges_org_id_purged_at_idx ON retention_purges (org_id, purged_at DESC);
+
+ALTER TABLE sessions ADD COLUMN stream_purged_at TIMESTAMP NULL;
+ALTER TABLE sessions ADD COLUMN output_purged_at TIMESTAMP NULL;
+
+COMMIT;
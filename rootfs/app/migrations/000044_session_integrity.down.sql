@@ -0,0 +1,9 @@
+BEGIN;
+
+SET search_path TO private;
+
+DROP INDEX IF EXISTS sessions_integrity_unanchored_idx;
+ALTER TABLE sessions DROP COLUMN IF EXISTS integrity_anchored_at;
+ALTER TABLE sessions DROP COLUMN IF EXISTS integrity;
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+SET search_path TO private;
+
+-- the seal of the event stream: the digest of the hash chain of events,
+-- the number of events and the signature of the gateway
+ALTER TABLE sessions ADD COLUMN integrity JSONB NULL;
+-- when the digest was appended to the external anchor file
+ALTER TABLE sessions ADD COLUMN integrity_anchored_at TIMESTAMP NULL;
+
+CREATE INDEX sessions_integrity_unanchored_idx ON sessions (ended_at) WHERE integrity IS NOT NULL AND integrity_anchored_at IS NULL;
+
+COMMIT;
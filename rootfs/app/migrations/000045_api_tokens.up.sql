@@ -0,0 +1,28 @@
+BEGIN;
+
+SET search_path TO private;
+
+CREATE TABLE api_tokens(
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    org_id UUID NOT NULL REFERENCES orgs (id),
+    name VARCHAR(128) NOT NULL,
+    -- the user or service account the token acts on behalf of
+    user_subject TEXT NOT NULL,
+    user_email TEXT NOT NULL,
+    -- only the sha256 of the token is stored, the prefix is kept to identify it
+    token_hash TEXT NOT NULL,
+    token_prefix VARCHAR(32) NOT NULL,
+    scopes TEXT[] NOT NULL,
+    -- the token could access any connection allowed to the user when it's empty
+    connections TEXT[] NOT NULL DEFAULT '{}',
+    expires_at TIMESTAMP NULL,
+    last_used_at TIMESTAMP NULL,
+    revoked_at TIMESTAMP NULL,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX api_tokens_token_hash_idx ON api_tokens (token_hash);
+CREATE UNIQUE INDEX api_tokens_org_id_user_subject_name_idx ON api_tokens (org_id, user_subject, name) WHERE revoked_at IS NULL;
+
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN;
+
+SET search_path TO private;
+
+CREATE TABLE roles(
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    org_id UUID NOT NULL REFERENCES orgs (id),
+    name VARCHAR(128) NOT NULL,
+    description TEXT NULL,
+    permissions TEXT[] NOT NULL,
+    -- the users that belong to any of these groups are granted the permissions of the role
+    groups TEXT[] NOT NULL DEFAULT '{}',
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX roles_org_id_name_idx ON roles (org_id, name);
+CREATE INDEX roles_groups_idx ON roles USING GIN (groups);
+
+COMMIT;
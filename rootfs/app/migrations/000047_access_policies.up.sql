@@ -0,0 +1,28 @@
+BEGIN;
+
+SET search_path TO private;
+
+CREATE TABLE access_policies(
+    id UUID DEFAULT uuid_generate_v4() PRIMARY KEY,
+    org_id UUID NOT NULL REFERENCES orgs (id),
+    name VARCHAR(128) NOT NULL,
+    description TEXT NULL,
+    effect VARCHAR(32) NOT NULL,
+    -- the policy applies to the users of any of these groups, empty applies to all users
+    groups TEXT[] NOT NULL DEFAULT '{}',
+
+    -- conditions, empty values match any request
+    connection_tags JSONB NULL,
+    connection_types TEXT[] NOT NULL DEFAULT '{}',
+    verbs TEXT[] NOT NULL DEFAULT '{}',
+    client_origins TEXT[] NOT NULL DEFAULT '{}',
+    source_ips TEXT[] NOT NULL DEFAULT '{}',
+    time_window JSONB NULL,
+
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX access_policies_org_id_name_idx ON access_policies (org_id, name);
+
+COMMIT;